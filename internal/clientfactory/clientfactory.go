@@ -0,0 +1,202 @@
+// Package clientfactory creates the Kubernetes clients the tools use to reach management and service
+// clusters, through backplane or, when backplane is unavailable, from kubeconfig files.
+package clientfactory
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/go-logr/logr"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	bplogin "github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
+	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// backplaneClusterAdmin is the user backplane elevates to.
+const backplaneClusterAdmin = "backplane-cluster-admin"
+
+// Factory creates the Kubernetes clients used to reach clusters. Tools accept a Factory in their
+// options so that tests can substitute controller-runtime fake clients.
+type Factory interface {
+	// NewClient returns a client for a cluster with the operator's own permissions.
+	NewClient(clusterID string, scheme *runtime.Scheme) (client.Client, error)
+	// NewElevatedClient returns a client for a cluster with backplane cluster-admin permissions.
+	NewElevatedClient(clusterID string, scheme *runtime.Scheme, conn *sdk.Connection, reason string) (client.Client, error)
+	// NewWatchClient returns a client for a cluster that can also watch resources.
+	NewWatchClient(clusterID string, scheme *runtime.Scheme) (client.WithWatch, error)
+}
+
+// ClientsetFactory is implemented by factories that also create typed clientsets, for the APIs the
+// controller-runtime client does not cover, such as pod logs. It is separate from Factory so that
+// factories in tests only implement it when a test needs it.
+type ClientsetFactory interface {
+	// NewClientset returns a clientset for a cluster with the operator's own permissions.
+	NewClientset(clusterID string) (kubernetes.Interface, error)
+}
+
+// NewClientset returns a clientset for a cluster from f, or an error when f cannot create clientsets.
+func NewClientset(f Factory, clusterID string) (kubernetes.Interface, error) {
+	clientsets, ok := OrDefault(f).(ClientsetFactory)
+	if !ok {
+		return nil, fmt.Errorf("the client factory for cluster %s cannot create clientsets", clusterID)
+	}
+	return clientsets.NewClientset(clusterID)
+}
+
+// RateLimit is the client-side rate limit of the clients a factory creates, so that tools scanning many
+// namespaces do not trip API priority and fairness on the clusters. Zero values keep the client-go defaults.
+type RateLimit struct {
+	// QPS is the sustained number of requests per second a client sends.
+	QPS float32
+	// Burst is the number of requests a client may send at once above QPS.
+	Burst int
+}
+
+// apply sets the rate limit on cfg and returns it.
+func (r RateLimit) apply(cfg *rest.Config) *rest.Config {
+	if r.QPS > 0 {
+		cfg.QPS = r.QPS
+	}
+	if r.Burst > 0 {
+		cfg.Burst = r.Burst
+	}
+	return cfg
+}
+
+// Backplane creates clients through backplane, rate limited by RateLimit.
+type Backplane struct {
+	RateLimit RateLimit
+}
+
+func (b Backplane) NewClient(clusterID string, scheme *runtime.Scheme) (client.Client, error) {
+	cfg, err := k8s.NewRestConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	discardRuntimeLogger()
+	return client.New(b.RateLimit.apply(cfg), client.Options{Scheme: scheme})
+}
+
+func (b Backplane) NewElevatedClient(clusterID string, scheme *runtime.Scheme, conn *sdk.Connection, reason string) (client.Client, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("an OCM connection is required for an elevated client")
+	}
+	bp, err := bpconfig.GetBackplaneConfigurationWithConn(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backplane-cli config: %w", err)
+	}
+	cfg, err := bplogin.GetRestConfigAsUserWithConn(bp, conn, clusterID, backplaneClusterAdmin, reason)
+	if err != nil {
+		return nil, err
+	}
+	discardRuntimeLogger()
+	return client.New(b.RateLimit.apply(cfg), client.Options{Scheme: scheme})
+}
+
+func (b Backplane) NewWatchClient(clusterID string, scheme *runtime.Scheme) (client.WithWatch, error) {
+	cfg, err := k8s.NewRestConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewWithWatch(b.RateLimit.apply(cfg), client.Options{Scheme: scheme})
+}
+
+func (b Backplane) NewClientset(clusterID string) (kubernetes.Interface, error) {
+	cfg, err := k8s.NewRestConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(b.RateLimit.apply(cfg))
+}
+
+// discardRuntimeLogger silences the controller-runtime logger, unless a tool set one, so that clients do
+// not print a warning and stack trace about the logger never being set.
+func discardRuntimeLogger() {
+	if !ctrllog.Log.Enabled() {
+		ctrllog.SetLogger(logr.Discard())
+	}
+}
+
+// OrDefault returns f, or the backplane factory when f is nil.
+func OrDefault(f Factory) Factory {
+	if f == nil {
+		return Backplane{}
+	}
+	return f
+}
+
+// Kubeconfig creates clients from kubeconfig files for the clusters it has one for, and through
+// Fallback for the others. Its elevated clients use the kubeconfig's own credentials, so elevation and
+// its audit trail must be handled outside the tool.
+type Kubeconfig struct {
+	// Kubeconfigs maps cluster IDs to the kubeconfig file whose current context reaches them.
+	Kubeconfigs map[string]string
+	Fallback    Factory
+
+	// RateLimit applies to the clients created from kubeconfig files.
+	RateLimit RateLimit
+}
+
+// restConfig loads the current context of a kubeconfig file.
+func restConfig(path string) (*rest.Config, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func (f *Kubeconfig) NewClient(clusterID string, scheme *runtime.Scheme) (client.Client, error) {
+	path, ok := f.Kubeconfigs[clusterID]
+	if !ok {
+		return OrDefault(f.Fallback).NewClient(clusterID, scheme)
+	}
+	cfg, err := restConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(f.RateLimit.apply(cfg), client.Options{Scheme: scheme})
+}
+
+// NewElevatedClient returns a client with the kubeconfig's own credentials. The elevation reason is only
+// logged, since no backplane elevation takes place.
+func (f *Kubeconfig) NewElevatedClient(clusterID string, scheme *runtime.Scheme, conn *sdk.Connection, reason string) (client.Client, error) {
+	path, ok := f.Kubeconfigs[clusterID]
+	if !ok {
+		return OrDefault(f.Fallback).NewElevatedClient(clusterID, scheme, conn, reason)
+	}
+	slog.Warn("Using kubeconfig credentials instead of backplane elevation; record the elevation externally",
+		"clusterID", clusterID, "kubeconfig", path, "reason", reason)
+	return f.NewClient(clusterID, scheme)
+}
+
+func (f *Kubeconfig) NewWatchClient(clusterID string, scheme *runtime.Scheme) (client.WithWatch, error) {
+	path, ok := f.Kubeconfigs[clusterID]
+	if !ok {
+		return OrDefault(f.Fallback).NewWatchClient(clusterID, scheme)
+	}
+	cfg, err := restConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewWithWatch(f.RateLimit.apply(cfg), client.Options{Scheme: scheme})
+}
+
+func (f *Kubeconfig) NewClientset(clusterID string) (kubernetes.Interface, error) {
+	path, ok := f.Kubeconfigs[clusterID]
+	if !ok {
+		return NewClientset(f.Fallback, clusterID)
+	}
+	cfg, err := restConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(f.RateLimit.apply(cfg))
+}
@@ -0,0 +1,141 @@
+package clientfactory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: mgmt
+  cluster:
+    server: https://api.mgmt.example.com:6443
+contexts:
+- name: mgmt
+  context:
+    cluster: mgmt
+    user: operator
+current-context: mgmt
+users:
+- name: operator
+  user:
+    token: test-token
+`
+
+// recordingFactory returns a fake client and records which clusters were reached through it.
+type recordingFactory struct {
+	client   client.WithWatch
+	reached  map[string]bool
+	elevated map[string]string
+}
+
+func newRecordingFactory() *recordingFactory {
+	return &recordingFactory{
+		client:   fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+		reached:  map[string]bool{},
+		elevated: map[string]string{},
+	}
+}
+
+func (f *recordingFactory) NewClient(clusterID string, _ *runtime.Scheme) (client.Client, error) {
+	f.reached[clusterID] = true
+	return f.client, nil
+}
+
+func (f *recordingFactory) NewElevatedClient(clusterID string, _ *runtime.Scheme, _ *sdk.Connection, reason string) (client.Client, error) {
+	f.reached[clusterID] = true
+	f.elevated[clusterID] = reason
+	return f.client, nil
+}
+
+func (f *recordingFactory) NewWatchClient(clusterID string, _ *runtime.Scheme) (client.WithWatch, error) {
+	f.reached[clusterID] = true
+	return f.client, nil
+}
+
+// TestOrDefault verifies a nil factory falls back to backplane.
+func TestOrDefault(t *testing.T) {
+	if _, ok := OrDefault(nil).(Backplane); !ok {
+		t.Errorf("Expected the backplane factory for nil")
+	}
+	f := newRecordingFactory()
+	if OrDefault(f) != f {
+		t.Errorf("Expected the given factory to be returned")
+	}
+}
+
+// TestKubeconfig verifies only the clusters with a kubeconfig bypass the fallback factory.
+func TestKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	fallback := newRecordingFactory()
+	f := &Kubeconfig{Kubeconfigs: map[string]string{"mgmt-id": path}, Fallback: fallback}
+	scheme := runtime.NewScheme()
+
+	if _, err := f.NewClient("mgmt-id", scheme); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := f.NewWatchClient("mgmt-id", scheme); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := f.NewElevatedClient("mgmt-id", scheme, nil, "OHSS-12345 - test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fallback.reached["mgmt-id"] {
+		t.Errorf("Expected the management cluster to be reached through its kubeconfig")
+	}
+
+	if _, err := f.NewElevatedClient("svc-id", scheme, nil, "OHSS-12345 - test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fallback.elevated["svc-id"] != "OHSS-12345 - test" {
+		t.Errorf("Expected the service cluster to be elevated through the fallback factory")
+	}
+
+	if _, err := f.NewClientset("mgmt-id"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := f.NewClientset("svc-id"); err == nil || !strings.Contains(err.Error(), "cannot create clientsets") {
+		t.Errorf("Expected an error from a fallback factory without clientsets, got %v", err)
+	}
+
+	missing := &Kubeconfig{Kubeconfigs: map[string]string{"mgmt-id": filepath.Join(t.TempDir(), "missing")}}
+	if _, err := missing.NewClient("mgmt-id", scheme); err == nil || !strings.Contains(err.Error(), "failed to load kubeconfig") {
+		t.Errorf("Expected kubeconfig load error, got %v", err)
+	}
+}
+
+// TestRateLimit verifies the rate limit overrides the QPS and burst of a client config, and that zero
+// values keep the config's own.
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         RateLimit
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		{"unset", RateLimit{}, 50, 100},
+		{"qps only", RateLimit{QPS: 5}, 5, 100},
+		{"qps and burst", RateLimit{QPS: 2.5, Burst: 5}, 2.5, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.limit.apply(&rest.Config{QPS: 50, Burst: 100})
+			if cfg.QPS != tt.expectedQPS || cfg.Burst != tt.expectedBurst {
+				t.Errorf("apply() = QPS %v, burst %d, want QPS %v, burst %d", cfg.QPS, cfg.Burst, tt.expectedQPS, tt.expectedBurst)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package output renders the reports of the tools: format validation, tables, JSON and YAML documents,
+// and atomically written report files.
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"gopkg.in/yaml.v2"
+)
+
+// TableMinWidth is the minimum column width of tables. Tables whose first column holds long values such
+// as namespaces or error messages use WideTableMinWidth.
+const (
+	TableMinWidth     = 20
+	WideTableMinWidth = 30
+)
+
+// Table is a table printed with aligned columns once flushed.
+type Table interface {
+	AddRow(row []string)
+	Flush() error
+}
+
+// NewTable returns a table written to w whose columns are at least minWidth wide.
+func NewTable(w io.Writer, minWidth int) Table {
+	return printer.NewTablePrinter(w, minWidth, 1, 3, ' ')
+}
+
+// ValidateFormat checks that format is one of the valid output formats.
+func ValidateFormat(format string, valid ...string) error {
+	if !slices.Contains(valid, format) {
+		return fmt.Errorf("invalid output format '%s'. Valid options: %s", format, strings.Join(valid, ", "))
+	}
+	return nil
+}
+
+// JSON writes v as indented JSON. Appended reports form a stream of JSON documents.
+func JSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// YAML writes v as a YAML document, preceded by a document separator when appending.
+func YAML(w io.Writer, v interface{}, appending bool) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if appending {
+		fmt.Fprintln(w, "---")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// WriteFile atomically replaces path with the output of render. With appendOutput the existing content of
+// path is kept and render is told it is appending, so it can skip headers or separate documents. The file
+// is written to a temporary file in the same directory and renamed, so readers never see a partially
+// written report.
+func WriteFile(path string, appendOutput bool, render func(w io.Writer, appending bool) error) error {
+	var existing []byte
+	if appendOutput {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read output file: %v", err)
+		}
+		existing = data
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(existing); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	if err := render(tmp, len(existing) > 0); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	return nil
+}
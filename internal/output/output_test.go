@@ -0,0 +1,115 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateFormat verifies only the listed formats are accepted and the error names the valid options.
+func TestValidateFormat(t *testing.T) {
+	if err := ValidateFormat("json", "text", "json", "yaml"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	err := ValidateFormat("xml", "text", "json", "yaml")
+	if err == nil || err.Error() != "invalid output format 'xml'. Valid options: text, json, yaml" {
+		t.Errorf("Expected invalid output format error, got %v", err)
+	}
+}
+
+// TestWriteFile verifies the file is replaced or appended to, and left untouched when rendering fails.
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.csv")
+
+	render := func(content string) func(io.Writer, bool) error {
+		return func(w io.Writer, appending bool) error {
+			_, err := fmt.Fprintf(w, "%s appending=%v\n", content, appending)
+			return err
+		}
+	}
+
+	if err := WriteFile(path, true, render("first")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := WriteFile(path, true, render("second")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertFileContent(t, path, "first appending=false\nsecond appending=true\n")
+
+	if err := WriteFile(path, false, render("third")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertFileContent(t, path, "third appending=false\n")
+
+	err := WriteFile(path, false, func(w io.Writer, _ bool) error {
+		fmt.Fprintln(w, "partial")
+		return fmt.Errorf("render failed")
+	})
+	if err == nil {
+		t.Fatal("Expected render error")
+	}
+	assertFileContent(t, path, "third appending=false\n")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary files to be removed, found %d entries", len(entries))
+	}
+}
+
+// TestDocuments verifies JSON is indented and appended YAML documents are separated.
+func TestDocuments(t *testing.T) {
+	v := map[string]string{"clusterID": "abc"}
+
+	var buf bytes.Buffer
+	if err := JSON(&buf, v); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "{\n  \"clusterID\": \"abc\"\n}\n" {
+		t.Errorf("JSON = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := YAML(&buf, v, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := YAML(&buf, v, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "clusterID: abc\n\n---\nclusterID: abc\n\n" {
+		t.Errorf("YAML = %q", buf.String())
+	}
+}
+
+// TestNewTable verifies columns are padded to the minimum width.
+func TestNewTable(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewTable(&buf, TableMinWidth)
+	table.AddRow([]string{"NAME", "SIZE"})
+	table.AddRow([]string{"cluster-a", "m54xl"})
+	if err := table.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "cluster-a"+strings.Repeat(" ", TableMinWidth-len("cluster-a"))) {
+		t.Errorf("Unexpected table:\n%s", buf.String())
+	}
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if string(data) != expected {
+		t.Errorf("File content = %q, want %q", data, expected)
+	}
+}
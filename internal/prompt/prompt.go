@@ -0,0 +1,72 @@
+// Package prompt asks the operator for confirmation on the terminal.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Confirm asks "Continue? (y/N)" on out until the answer read from in is yes or no. An empty answer or the
+// end of input counts as no.
+func Confirm(in io.Reader, out io.Writer) bool {
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprint(out, "Continue? (y/N): ")
+		line, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return true
+		case "", "n", "no":
+			return false
+		}
+		if err != nil {
+			return false
+		}
+		fmt.Fprintln(out, "Invalid input. Expecting (y)es or (N)o")
+	}
+}
+
+// typedAttempts is how many wrong answers ConfirmTyped accepts before declining.
+const typedAttempts = 3
+
+// ConfirmTyped asks on out for one of the accepted answers to be typed, e.g. the number of clusters a change
+// applies to, and reports whether the answer read from in matched one exactly. It declines after
+// typedAttempts wrong answers, on an empty answer or at the end of input, so it never waits on an input that
+// can no longer confirm.
+func ConfirmTyped(in io.Reader, out io.Writer, accepted ...string) bool {
+	if len(accepted) == 0 {
+		return false
+	}
+	reader := bufio.NewReader(in)
+	for attempt := 1; ; attempt++ {
+		fmt.Fprintf(out, "Type %s to continue: ", strings.Join(quoted(accepted), " or "))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		for _, a := range accepted {
+			if answer == a {
+				return true
+			}
+		}
+		if answer == "" || err != nil || attempt == typedAttempts {
+			return false
+		}
+		fmt.Fprintf(out, "%q does not match, %d attempts left\n", answer, typedAttempts-attempt)
+	}
+}
+
+// quoted returns the answers in double quotes.
+func quoted(answers []string) []string {
+	q := make([]string, 0, len(answers))
+	for _, a := range answers {
+		q = append(q, fmt.Sprintf("%q", a))
+	}
+	return q
+}
+
+// ConfirmTerminal asks for confirmation on the process's standard input and output.
+func ConfirmTerminal() bool {
+	return Confirm(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,75 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestConfirm verifies yes and no answers, that empty input and EOF decline, and that invalid answers are asked again.
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+		prompts  int
+	}{
+		{name: "yes", input: "y\n", expected: true, prompts: 1},
+		{name: "yes uppercase", input: "YES\n", expected: true, prompts: 1},
+		{name: "no", input: "no\n", expected: false, prompts: 1},
+		{name: "empty", input: "\n", expected: false, prompts: 1},
+		{name: "eof", input: "", expected: false, prompts: 1},
+		{name: "yes without newline", input: "y", expected: true, prompts: 1},
+		{name: "invalid then yes", input: "maybe\ny\n", expected: true, prompts: 2},
+		{name: "invalid then eof", input: "maybe", expected: false, prompts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := Confirm(strings.NewReader(tt.input), &out); got != tt.expected {
+				t.Errorf("Confirm() = %v, want %v", got, tt.expected)
+			}
+			if prompts := strings.Count(out.String(), "Continue? (y/N): "); prompts != tt.prompts {
+				t.Errorf("Expected %d prompts, got %d:\n%s", tt.prompts, prompts, out.String())
+			}
+		})
+	}
+}
+
+// TestConfirmTyped verifies that only an exact accepted answer confirms, and that empty input, EOF and too many
+// wrong answers decline without asking again.
+func TestConfirmTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+		prompts  int
+	}{
+		{name: "count", input: "200\n", expected: true, prompts: 1},
+		{name: "name", input: "  mgmt-cluster \n", expected: true, prompts: 1},
+		{name: "yes", input: "y\n", expected: false, prompts: 2},
+		{name: "empty", input: "\n", expected: false, prompts: 1},
+		{name: "eof", input: "", expected: false, prompts: 1},
+		{name: "count without newline", input: "200", expected: true, prompts: 1},
+		{name: "wrong then count", input: "20\n200\n", expected: true, prompts: 2},
+		{name: "wrong then eof", input: "20\n", expected: false, prompts: 2},
+		{name: "too many wrong answers", input: "1\n2\n3\n200\n", expected: false, prompts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := ConfirmTyped(strings.NewReader(tt.input), &out, "200", "mgmt-cluster"); got != tt.expected {
+				t.Errorf("ConfirmTyped() = %v, want %v", got, tt.expected)
+			}
+			if prompts := strings.Count(out.String(), `Type "200" or "mgmt-cluster" to continue: `); prompts != tt.prompts {
+				t.Errorf("Expected %d prompts, got %d:\n%s", tt.prompts, prompts, out.String())
+			}
+		})
+	}
+
+	if ConfirmTyped(strings.NewReader("\n"), &bytes.Buffer{}) {
+		t.Error("Expected ConfirmTyped() without accepted answers to decline")
+	}
+}
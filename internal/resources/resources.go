@@ -0,0 +1,30 @@
+// Package resources aggregates the resource requests of pods, e.g. to size hosted control planes or report
+// the utilization of serving nodes.
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodRequests returns the total CPU and memory requests of all running or pending pods.
+func PodRequests(pods []corev1.Pod) (resource.Quantity, resource.Quantity) {
+	cpu := resource.Quantity{Format: resource.DecimalSI}
+	memory := resource.Quantity{Format: resource.BinarySI}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+				cpu.Add(q)
+			}
+			if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+				memory.Add(q)
+			}
+		}
+	}
+
+	return cpu, memory
+}
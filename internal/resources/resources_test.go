@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TestPodRequests verifies request aggregation skips completed pods.
+func TestPodRequests(t *testing.T) {
+	container := func(cpu, memory string) corev1.Container {
+		return corev1.Container{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+
+	pods := []corev1.Pod{
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{container("500m", "1Gi"), container("250m", "512Mi")}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{container("1", "2Gi")}},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{container("4", "8Gi")}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+
+	cpu, memory := PodRequests(pods)
+	if cpu.Cmp(resource.MustParse("1750m")) != 0 {
+		t.Errorf("cpu requests = %s, want 1750m", cpu.String())
+	}
+	if memory.Cmp(resource.MustParse("3584Mi")) != 0 {
+		t.Errorf("memory requests = %s, want 3584Mi", memory.String())
+	}
+}
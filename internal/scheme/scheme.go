@@ -0,0 +1,77 @@
+// Package scheme builds the controller-runtime schemes the tools create their clients with, registering
+// only the API groups a command needs.
+package scheme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// Option is an API group registered on a scheme.
+type Option struct {
+	name        string
+	addToScheme func(*runtime.Scheme) error
+}
+
+// The API groups that can be registered on a scheme.
+var (
+	// Core registers core v1: Namespaces, Pods, Nodes and ConfigMaps.
+	Core = Option{"core v1", corev1.AddToScheme}
+	// Authorization registers authorization v1: SelfSubjectAccessReviews.
+	Authorization = Option{"authorization v1", authorizationv1.AddToScheme}
+	// HyperShift registers hypershift v1beta1: HostedClusters and NodePools.
+	HyperShift = Option{"hypershift", hypershiftv1beta1.AddToScheme}
+	// Scheduling registers hypershift scheduling v1alpha1: ClusterSizingConfigurations.
+	Scheduling = Option{"scheduling", schedulingv1alpha1.AddToScheme}
+	// Work registers open-cluster-management work v1: ManifestWorks.
+	Work = Option{"work v1", workv1.Install}
+)
+
+// NewScheme returns a new scheme with the API groups of opts registered.
+func NewScheme(opts ...Option) (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, opt := range opts {
+		if err := opt.addToScheme(scheme); err != nil {
+			return nil, fmt.Errorf("failed to add %s scheme: %v", opt.name, err)
+		}
+	}
+	return scheme, nil
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]*runtime.Scheme{}
+)
+
+// Cached returns a scheme with the API groups of opts registered, built on first use and shared by every
+// caller asking for the same groups, in any order. Schemes are safe for concurrent use once built and must
+// not be modified.
+func Cached(opts ...Option) (*runtime.Scheme, error) {
+	names := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		names = append(names, opt.name)
+	}
+	sort.Strings(names)
+	key := strings.Join(names, ",")
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if scheme, ok := cache[key]; ok {
+		return scheme, nil
+	}
+	scheme, err := NewScheme(opts...)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = scheme
+	return scheme, nil
+}
@@ -0,0 +1,84 @@
+package scheme
+
+import (
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// TestNewScheme verifies only the types of the requested API groups are registered.
+func TestNewScheme(t *testing.T) {
+	scheme, err := NewScheme(HyperShift, Work)
+	if err != nil {
+		t.Fatalf("NewScheme() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		obj        runtime.Object
+		registered bool
+	}{
+		{"HostedCluster", &hypershiftv1beta1.HostedCluster{}, true},
+		{"NodePool", &hypershiftv1beta1.NodePool{}, true},
+		{"ManifestWork", &workv1.ManifestWork{}, true},
+		{"Namespace", &corev1.Namespace{}, false},
+		{"ClusterSizingConfiguration", &schedulingv1alpha1.ClusterSizingConfiguration{}, false},
+		{"SelfSubjectAccessReview", &authorizationv1.SelfSubjectAccessReview{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := scheme.ObjectKinds(tt.obj)
+			if registered := err == nil; registered != tt.registered {
+				t.Errorf("%s registered = %v, want %v", tt.name, registered, tt.registered)
+			}
+		})
+	}
+}
+
+// TestNewSchemeAllGroups verifies every API group can be registered on the same scheme.
+func TestNewSchemeAllGroups(t *testing.T) {
+	scheme, err := NewScheme(Core, Authorization, HyperShift, Scheduling, Work)
+	if err != nil {
+		t.Fatalf("NewScheme() error = %v", err)
+	}
+	for _, obj := range []runtime.Object{
+		&corev1.Pod{}, &authorizationv1.SelfSubjectAccessReview{}, &hypershiftv1beta1.NodePool{},
+		&schedulingv1alpha1.ClusterSizingConfiguration{}, &workv1.ManifestWork{},
+	} {
+		if _, _, err := scheme.ObjectKinds(obj); err != nil {
+			t.Errorf("Expected %T to be registered: %v", obj, err)
+		}
+	}
+}
+
+// TestCached verifies a scheme is built once per set of API groups, whatever their order.
+func TestCached(t *testing.T) {
+	first, err := Cached(HyperShift, Core)
+	if err != nil {
+		t.Fatalf("Cached() error = %v", err)
+	}
+	second, err := Cached(Core, HyperShift)
+	if err != nil {
+		t.Fatalf("Cached() error = %v", err)
+	}
+	if first != second {
+		t.Error("Expected the same scheme for the same API groups")
+	}
+
+	other, err := Cached(HyperShift)
+	if err != nil {
+		t.Fatalf("Cached() error = %v", err)
+	}
+	if other == first {
+		t.Error("Expected a different scheme for different API groups")
+	}
+	if _, _, err := other.ObjectKinds(&corev1.Namespace{}); err == nil {
+		t.Error("Expected core v1 not to be registered on the hypershift only scheme")
+	}
+}
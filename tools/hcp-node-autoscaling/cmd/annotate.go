@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/prompt"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// annotateProfileName is the profile name recorded in the run history of annotate runs.
+	annotateProfileName = "annotate"
+
+	defaultAnnotateElevationReason = "Changing hosted cluster annotations"
+)
+
+// annotateOpts holds the options of the annotate command. The change is made with migrate's options and
+// an ad-hoc migration profile built from --set and --remove.
+type annotateOpts struct {
+	clusterID string
+	set       []string
+	remove    []string
+	migrate   migrateOpts
+}
+
+// NewAnnotateCmd creates the annotate subcommand that sets or removes arbitrary HostedCluster annotations
+// through the cluster's ManifestWork.
+func NewAnnotateCmd() *cobra.Command {
+	opts := &annotateOpts{
+		migrate: migrateOpts{
+			maxInFlight: 1,
+			environment: "all",
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Set or remove annotations on a single hosted cluster through its ManifestWork",
+		Long: `Set or remove arbitrary annotations on the HostedCluster of one hosted cluster.
+
+The annotations are changed in the HostedCluster manifest of the cluster's ManifestWork on the
+service cluster, exactly like migrate changes the autoscaling annotations: the annotation diff is
+shown for confirmation, the ManifestWork is patched with elevated permissions recording the ticket,
+the change is verified on the management cluster and recorded in the run history.`,
+		Example: `
+  # Preview the change
+  hcp-node-autoscaling annotate \
+    --mgmt-cluster-id mgmt-456 \
+    --cluster-id abc123 \
+    --set hypershift.openshift.io/cluster-size-override=m54xl \
+    --dry-run
+
+  # Remove an annotation
+  hcp-node-autoscaling annotate \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-id mgmt-456 \
+    --cluster-id abc123 \
+    --remove hypershift.openshift.io/cluster-size-override`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.clusterID, "cluster-id", "",
+		"ID of the hosted cluster to annotate")
+	cmd.Flags().StringArrayVar(&opts.set, "set", nil,
+		"Annotation to set as key=value (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.remove, "remove", nil,
+		"Annotation key to remove (repeatable)")
+	cmd.Flags().StringVar(&opts.migrate.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	addAllowMismatchFlag(cmd, &opts.migrate.allowMismatch)
+	cmd.Flags().StringVar(&opts.migrate.mgmtClusterID, "mgmt-cluster-id", "",
+		"The management cluster ID of the hosted cluster")
+	addDryRunFlag(cmd, &opts.migrate.dryRunMode, "the ManifestWork")
+	cmd.Flags().BoolVar(&opts.migrate.skipConfirmation, "skip-confirmation", false,
+		"Skip confirmation prompt (use with caution)")
+	cmd.Flags().BoolVar(&opts.migrate.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login, backplane access and ManifestWork permissions before the change")
+	cmd.Flags().BoolVar(&opts.migrate.ignoreFreeze, "ignore-freeze", false,
+		"Change the cluster even if it is upgrading, has a control plane upgrade scheduled or is in limited support")
+	cmd.Flags().DurationVar(&opts.migrate.syncTimeout, "sync-timeout", defaultSyncTimeout,
+		"Maximum time to wait for annotations to sync to the management cluster")
+	cmd.Flags().DurationVar(&opts.migrate.pollInterval, "poll-interval", defaultPollInterval,
+		"Interval between sync verification attempts")
+	cmd.Flags().IntVar(&opts.migrate.conflictRetries, "conflict-retries", defaultConflictRetries,
+		"Number of times to retry a ManifestWork update that fails with a conflict")
+	cmd.Flags().StringVar(&opts.migrate.patchStrategy, "patch-strategy", "json-patch",
+		"How to write the ManifestWork: update, json-patch")
+	cmd.Flags().StringVar(&opts.migrate.ticket, "ticket", "",
+		"JIRA issue approving the change, e.g. OHSS-12345 (required unless --dry-run)")
+	cmd.Flags().StringVar(&opts.migrate.ticket, "reason", "", "Alias of --ticket")
+	cmd.Flags().StringVar(&opts.migrate.elevationReason, "elevation-reason", defaultAnnotateElevationReason,
+		"Description of the change recorded after the ticket on the backplane elevation, the run history and service log entries")
+	cmd.Flags().BoolVar(&opts.migrate.serviceLog, "service-log", false,
+		"Post an internal OCM service log entry once the change is verified")
+	cmd.Flags().StringVar(&opts.migrate.historyDir, "history-dir", "",
+		"Directory to write the run history to (default ~/.config/hcp-node-autoscaling/history)")
+	cmd.Flags().DurationVar(&opts.migrate.timeouts.manifestWork, "manifestwork-timeout", defaultManifestWorkTimeout,
+		"Deadline for each get and update of the cluster's ManifestWork (0 disables it)")
+	opts.migrate.kubeconfigs.addFlags(cmd, true)
+	_ = cmd.MarkFlagRequired("cluster-id")
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	cmd.MarkFlagsOneRequired("set", "remove")
+	cmd.MarkFlagsMutuallyExclusive("ticket", "reason")
+	opts.migrate.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run builds the ad-hoc profile, shows the annotation diff and, once confirmed, patches the ManifestWork
+// and verifies the change on the management cluster.
+func (o *annotateOpts) run(ctx context.Context) error {
+	profile, err := parseAnnotationChanges(o.set, o.remove)
+	if err != nil {
+		return err
+	}
+
+	m := &o.migrate
+	m.profile = profile
+	m.serviceLogSummary = "Changed HostedCluster annotations"
+	if err := m.initialize(ctx); err != nil {
+		return fmt.Errorf("initialization failed: %v", err)
+	}
+	defer m.ocmConn.Close()
+
+	target, err := m.getAnnotateTarget(ctx, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	allowed, frozen := m.filterFrozen(ctx, []hostedClusterAuditInfo{*target})
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking for maintenance and change freezes"))
+	}
+	if len(allowed) == 0 {
+		displayFrozen(os.Stdout, frozen)
+		return fmt.Errorf("cluster %s is in a maintenance or change freeze; use --ignore-freeze to change it anyway", target.ClusterID)
+	}
+	if _, notReady := m.filterOCMState(ctx, allowed); len(notReady) > 0 {
+		return fmt.Errorf("cluster %s is not ready: %s", target.ClusterID, notReady[0].reason)
+	}
+
+	changes, err := m.previewManifestWork(ctx, target.ClusterID)
+	if err != nil {
+		return err
+	}
+	displayAnnotationDiff(os.Stdout, *target, changes)
+	if len(changes) == 0 {
+		fmt.Println("ManifestWork already has the requested annotations")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: ManifestWork already has the requested annotations"))
+	}
+
+	if m.dryRun {
+		if m.serverDryRun {
+			if err := m.submitServerDryRun(ctx, os.Stdout, []hostedClusterAuditInfo{*target}); err != nil {
+				return err
+			}
+		}
+		fmt.Println("[DRY RUN] No changes will be applied")
+		return nil
+	}
+	if !m.skipConfirmation && !prompt.ConfirmTerminal() {
+		return fmt.Errorf("annotation change cancelled by user")
+	}
+
+	m.history = m.newRunHistory(m.historyDir, time.Now())
+	result := m.migrateCandidate(ctx, *target)
+	if err := m.history.finish(); err != nil {
+		slog.Warn("Failed to write run history", "error", err)
+	} else {
+		slog.Info("Wrote run history", "file", m.history.path)
+	}
+
+	if result.Status == "success" {
+		fmt.Printf("✓ Annotations of %s (%s) changed and synced in %s\n",
+			result.ClusterName, result.ClusterID, result.syncDuration().Round(time.Second))
+	}
+	if result.Status == "interrupted" {
+		return withExitCode(exitInterrupted, fmt.Errorf("annotation change interrupted: %s", result.Error))
+	}
+	return migrationExitError([]migrationResult{result})
+}
+
+// parseAnnotationChanges builds the ad-hoc migration profile of an annotate run from the --set key=value
+// and --remove key flags.
+func parseAnnotationChanges(set, remove []string) (*migrationProfile, error) {
+	profile := &migrationProfile{Name: annotateProfileName, Ensure: map[string]string{}}
+	for _, s := range set {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set '%s'. Expected key=value", s)
+		}
+		if err := validateAnnotationKey(key); err != nil {
+			return nil, fmt.Errorf("invalid --set '%s': %v", s, err)
+		}
+		if _, ok := profile.Ensure[key]; ok {
+			return nil, fmt.Errorf("annotation %s is set more than once", key)
+		}
+		profile.Ensure[key] = value
+	}
+
+	removed := map[string]bool{}
+	for _, key := range remove {
+		if err := validateAnnotationKey(key); err != nil {
+			return nil, fmt.Errorf("invalid --remove '%s': %v", key, err)
+		}
+		if _, ok := profile.Ensure[key]; ok {
+			return nil, fmt.Errorf("annotation %s cannot be both set and removed", key)
+		}
+		if removed[key] {
+			continue
+		}
+		removed[key] = true
+		profile.Remove = append(profile.Remove, key)
+	}
+	sort.Strings(profile.Remove)
+
+	if len(profile.Ensure) == 0 && len(profile.Remove) == 0 {
+		return nil, fmt.Errorf("at least one --set or --remove is required")
+	}
+	return profile, nil
+}
+
+// validateAnnotationKey checks that key is a valid Kubernetes annotation key.
+func validateAnnotationKey(key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// getAnnotateTarget finds the HostedCluster of a cluster ID on the management cluster.
+func (m *migrateOpts) getAnnotateTarget(ctx context.Context, clusterID string) (*hostedClusterAuditInfo, error) {
+	hostedClusters := &hypershiftv1beta1.HostedClusterList{}
+	if err := m.mgmtClient.List(ctx, hostedClusters, client.MatchingLabels{"api.openshift.com/id": clusterID}); err != nil {
+		return nil, fmt.Errorf("failed to list HostedClusters: %v", err)
+	}
+	if len(hostedClusters.Items) != 1 {
+		return nil, fmt.Errorf("found %d HostedClusters with cluster ID %s on management cluster %s",
+			len(hostedClusters.Items), clusterID, m.mgmtClusterID)
+	}
+
+	hc := &hostedClusters.Items[0]
+	return &hostedClusterAuditInfo{
+		ClusterID:   clusterID,
+		ClusterName: hc.Name,
+		Namespace:   hc.Namespace,
+		CurrentSize: hc.Labels["hypershift.openshift.io/hosted-cluster-size"],
+		Labels:      hc.Labels,
+		Annotations: hc.Annotations,
+	}, nil
+}
+
+// displayAnnotationDiff prints the annotation changes an annotate run makes to the cluster's ManifestWork.
+func displayAnnotationDiff(w io.Writer, target hostedClusterAuditInfo, changes []annotationChangePreview) {
+	fmt.Fprintf(w, "\n=== ManifestWork Annotation Changes: %s (%s) ===\n\n", target.ClusterName, target.ClusterID)
+	if len(changes) == 0 {
+		return
+	}
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"ANNOTATION", "BEFORE", "AFTER", "ACTION"})
+	for _, change := range changes {
+		p.AddRow([]string{change.Annotation, driftValue(change.Before), driftValue(change.After), change.Action})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestParseAnnotationChanges verifies --set and --remove are parsed into an ad-hoc profile and malformed or
+// conflicting changes are rejected.
+func TestParseAnnotationChanges(t *testing.T) {
+	tests := []struct {
+		name           string
+		set            []string
+		remove         []string
+		expectedEnsure map[string]string
+		expectedRemove []string
+		expectedError  string
+	}{
+		{
+			name:           "set and remove",
+			set:            []string{"example.com/owner=sre", "example.com/empty="},
+			remove:         []string{"example.com/b", "example.com/a", "example.com/b"},
+			expectedEnsure: map[string]string{"example.com/owner": "sre", "example.com/empty": ""},
+			expectedRemove: []string{"example.com/a", "example.com/b"},
+		},
+		{
+			name:           "value containing equals sign",
+			set:            []string{"example.com/selector=a=b"},
+			expectedEnsure: map[string]string{"example.com/selector": "a=b"},
+		},
+		{name: "nothing to change", expectedError: "at least one --set or --remove is required"},
+		{name: "missing value", set: []string{"example.com/owner"}, expectedError: "Expected key=value"},
+		{name: "invalid key", set: []string{"not a key=value"}, expectedError: "invalid --set"},
+		{name: "invalid removed key", remove: []string{"example.com/"}, expectedError: "invalid --remove"},
+		{name: "set twice", set: []string{"example.com/a=1", "example.com/a=2"}, expectedError: "set more than once"},
+		{name: "set and removed", set: []string{"example.com/a=1"}, remove: []string{"example.com/a"}, expectedError: "both set and removed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := parseAnnotationChanges(tt.set, tt.remove)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if profile.Name != annotateProfileName {
+				t.Errorf("Expected profile name %s, got %s", annotateProfileName, profile.Name)
+			}
+			if len(profile.Ensure) != len(tt.expectedEnsure) {
+				t.Errorf("Ensure = %v, want %v", profile.Ensure, tt.expectedEnsure)
+			}
+			for key, value := range tt.expectedEnsure {
+				if actual, ok := profile.Ensure[key]; !ok || actual != value {
+					t.Errorf("Ensure[%s] = %q, want %q", key, actual, value)
+				}
+			}
+			if strings.Join(profile.Remove, ",") != strings.Join(tt.expectedRemove, ",") {
+				t.Errorf("Remove = %v, want %v", profile.Remove, tt.expectedRemove)
+			}
+		})
+	}
+}
+
+// TestAnnotateEndToEnd verifies the target is found by cluster ID, the diff is previewed from its ManifestWork
+// and the annotations are set and removed once the simulated work agent syncs them.
+func TestAnnotateEndToEnd(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", map[string]string{
+		"example.com/stale": "true",
+		"example.com/owner": "team-a",
+	})
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newTestNamespace(hc.Namespace), hc.DeepCopy()).
+		Build()
+	agent := &workAgent{mgmtClient: mgmtClient, delay: 50 * time.Millisecond}
+	t.Cleanup(agent.wait)
+	serviceClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).
+		WithInterceptorFuncs(agent.interceptors()).
+		Build()
+
+	profile, err := parseAnnotationChanges([]string{"example.com/owner=sre"}, []string{"example.com/stale"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := &migrateOpts{
+		mgmtClusterID:   "mgmt-id",
+		mgmtClusterName: "mgmt-cluster",
+		mgmtClient:      mgmtClient,
+		serviceClient:   serviceClient,
+		profile:         profile,
+		patchStrategy:   "json-patch",
+		conflictRetries: 3,
+		syncTimeout:     500 * time.Millisecond,
+		pollInterval:    20 * time.Millisecond,
+	}
+
+	ctx := context.Background()
+	if _, err := m.getAnnotateTarget(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "found 0 HostedClusters") {
+		t.Errorf("Expected missing cluster error, got %v", err)
+	}
+	target, err := m.getAnnotateTarget(ctx, "a1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.ClusterName != hc.Name || target.Namespace != hc.Namespace {
+		t.Errorf("Unexpected target %+v", target)
+	}
+
+	changes, err := m.previewManifestWork(ctx, target.ClusterID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	displayAnnotationDiff(&out, *target, changes)
+	for _, expected := range []string{"example.com/owner", "overwrite", "example.com/stale", "remove"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	result := m.migrateCandidate(ctx, *target)
+	if result.Status != "success" {
+		t.Fatalf("Expected success, got %s: %s", result.Status, result.Error)
+	}
+
+	synced := &hypershiftv1beta1.HostedCluster{}
+	if err := mgmtClient.Get(ctx, client.ObjectKeyFromObject(hc), synced); err != nil {
+		t.Fatalf("Failed to get HostedCluster: %v", err)
+	}
+	if synced.Annotations["example.com/owner"] != "sre" {
+		t.Errorf("Expected owner annotation to be set, got %v", synced.Annotations)
+	}
+	if _, ok := synced.Annotations["example.com/stale"]; ok {
+		t.Errorf("Expected stale annotation to be removed, got %v", synced.Annotations)
+	}
+}
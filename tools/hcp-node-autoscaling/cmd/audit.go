@@ -0,0 +1,1327 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type auditOpts struct {
+	mgmtClusterID string
+	environment   string
+	output        string
+	outputFile    string
+	appendOutput  bool
+	showOnly      string
+	noHeaders     bool
+	sizeAnalysis  bool
+	checkDrift    bool
+	enrichOCM     bool
+	noCache       bool
+	cacheTTL      time.Duration
+	cache         *ocmCache
+	failOnFlag    []string
+	failOn        map[string]bool
+	watch         bool
+	watchFile     string
+	skipPreflight bool
+	includePaused bool
+
+	serviceClusterID      string
+	simulateSizing        bool
+	metricsPushgatewayURL string
+	notifyWebhook         string
+	notifyFormat          string
+	exportDestinations    []string
+	exporters             []reportExporter
+	eventsBrokerURL       string
+	eventsTopic           string
+	broker                *eventsBroker
+	excludeClusterIDs     []string
+	excludeFile           string
+	exclusions            map[string]string
+	labelSelector         string
+	annotationSelector    string
+	selector              *hostedClusterSelector
+	profilePath           string
+	profile               *migrationProfile
+	timeouts              phaseTimeouts
+	kubeconfigs           kubeconfigOverrides
+	listing               namespaceListing
+
+	// columnNames is the --columns value, parsed into columns on initialization.
+	columnNames []string
+	columns     []auditColumn
+
+	ocmConn           *sdk.Connection
+	clients           clientfactory.Factory
+	mgmtClient        client.Client
+	watchClient       client.WithWatch
+	serviceClient     client.Client
+	mgmtClusterName   string
+	metrics           *runMetrics
+	sizeClasses       []schedulingv1alpha1.SizeConfiguration
+	organizationNames map[string]string
+	hypershiftVersion string
+
+	// events is the --output jsonl event stream.
+	events *eventStream
+
+	// session holds the run ID and the settings of the run shared by all subcommands.
+	session *runSession
+
+	// diffFile is the previous audit report that the results are compared with, loaded into previousReport.
+	diffFile       string
+	previousReport *auditResults
+
+	// includeUnmanaged also audits the HostedClusters outside the OCM namespaces, whose namespaces are
+	// recorded in unmanagedNamespaces.
+	includeUnmanaged    bool
+	unmanagedNamespaces map[string]bool
+}
+
+type hostedClusterAuditInfo struct {
+	ClusterID   string            `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName string            `json:"cluster_name" yaml:"cluster_name"`
+	Namespace   string            `json:"namespace" yaml:"namespace"`
+	CurrentSize string            `json:"current_size" yaml:"current_size"`
+	Category    string            `json:"category" yaml:"category"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Available   string            `json:"available,omitempty" yaml:"available,omitempty"`
+
+	// Subcategory refines Category by the state of the topology, autoscaling and size override annotations,
+	// and Reasons explains the category.
+	Subcategory string   `json:"subcategory,omitempty" yaml:"subcategory,omitempty"`
+	Reasons     []string `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+
+	// SizeOverride is the value of the cluster-size-override annotation that the migration removes.
+	SizeOverride string `json:"size_override,omitempty" yaml:"size_override,omitempty"`
+
+	PausedReason string `json:"paused_reason,omitempty" yaml:"paused_reason,omitempty"`
+
+	// DeletionTimestamp is set, in RFC3339, for clusters that are being deleted.
+	DeletionTimestamp string `json:"deletion_timestamp,omitempty" yaml:"deletion_timestamp,omitempty"`
+
+	// MigratedAt and MigrationRunID are read from the provenance annotations set by migrate --stamp-provenance.
+	MigratedAt     string `json:"migrated_at,omitempty" yaml:"migrated_at,omitempty"`
+	MigrationRunID string `json:"migration_run_id,omitempty" yaml:"migration_run_id,omitempty"`
+
+	// Autoscaling behavior differs across releases, so the audit reports the versions of the cluster
+	// and of the HyperShift operator reconciling it.
+	OpenShiftVersion  string `json:"openshift_version,omitempty" yaml:"openshift_version,omitempty"`
+	ChannelGroup      string `json:"channel_group,omitempty" yaml:"channel_group,omitempty"`
+	HyperShiftVersion string `json:"hypershift_operator_version,omitempty" yaml:"hypershift_operator_version,omitempty"`
+
+	NodePoolCount              int    `json:"nodepool_count,omitempty" yaml:"nodepool_count,omitempty"`
+	WorkerReplicas             int32  `json:"worker_replicas,omitempty" yaml:"worker_replicas,omitempty"`
+	ControlPlaneCPURequests    string `json:"control_plane_cpu_requests,omitempty" yaml:"control_plane_cpu_requests,omitempty"`
+	ControlPlaneMemoryRequests string `json:"control_plane_memory_requests,omitempty" yaml:"control_plane_memory_requests,omitempty"`
+	ExpectedSizeClass          string `json:"expected_size_class,omitempty" yaml:"expected_size_class,omitempty"`
+
+	// With --simulate-sizing, the size class the resource-based autoscaler is expected to choose.
+	KubeAPIServerMemoryRequests string `json:"kube_apiserver_memory_requests,omitempty" yaml:"kube_apiserver_memory_requests,omitempty"`
+	SimulatedSizeClass          string `json:"simulated_size_class,omitempty" yaml:"simulated_size_class,omitempty"`
+	SizeChange                  bool   `json:"size_change,omitempty" yaml:"size_change,omitempty"`
+
+	Drift []annotationDrift `json:"drift,omitempty" yaml:"drift,omitempty"`
+
+	Excluded        bool   `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+	ExclusionReason string `json:"exclusion_reason,omitempty" yaml:"exclusion_reason,omitempty"`
+
+	OCMState           string `json:"ocm_state,omitempty" yaml:"ocm_state,omitempty"`
+	SubscriptionStatus string `json:"subscription_status,omitempty" yaml:"subscription_status,omitempty"`
+	OrganizationID     string `json:"organization_id,omitempty" yaml:"organization_id,omitempty"`
+	OrganizationName   string `json:"organization_name,omitempty" yaml:"organization_name,omitempty"`
+	SupportLevel       string `json:"support_level,omitempty" yaml:"support_level,omitempty"`
+
+	// Conditions summarizes the Available, Degraded and Progressing conditions of the HostedCluster.
+	Conditions []hostedClusterCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+type auditResults struct {
+	SchemaVersion     int                      `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	MgmtClusterID     string                   `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	GeneratedAt       string                   `json:"generated_at,omitempty" yaml:"generated_at,omitempty"`
+	RunID             string                   `json:"run_id,omitempty" yaml:"run_id,omitempty"`
+	Environment       string                   `json:"environment,omitempty" yaml:"environment,omitempty"`
+	TotalScanned      int                      `json:"total_scanned" yaml:"total_scanned"`
+	NeedsLabelRemoval []hostedClusterAuditInfo `json:"needs_label_removal" yaml:"needs_label_removal"`
+	ReadyForMigration []hostedClusterAuditInfo `json:"ready_for_migration" yaml:"ready_for_migration"`
+	AlreadyConfigured []hostedClusterAuditInfo `json:"already_configured" yaml:"already_configured"`
+	Drifted           []hostedClusterAuditInfo `json:"drifted,omitempty" yaml:"drifted,omitempty"`
+	Paused            []hostedClusterAuditInfo `json:"paused,omitempty" yaml:"paused,omitempty"`
+	Deleting          []hostedClusterAuditInfo `json:"deleting,omitempty" yaml:"deleting,omitempty"`
+	Unmanaged         []hostedClusterAuditInfo `json:"unmanaged,omitempty" yaml:"unmanaged,omitempty"`
+	Errors            []auditError             `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Partial           bool                     `json:"partial,omitempty" yaml:"partial,omitempty"`
+
+	// Diff holds the changes since the report passed with --diff.
+	Diff *auditDiff `json:"diff,omitempty" yaml:"diff,omitempty"`
+
+	// Summary holds the counts of the whole audit, before --show-only, in schema version 2 reports.
+	Summary *auditSummary `json:"summary,omitempty" yaml:"summary,omitempty"`
+}
+
+type auditError struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Error     string `json:"error" yaml:"error"`
+}
+
+// NewAuditCmd creates the audit subcommand for analyzing hosted clusters.
+func NewAuditCmd() *cobra.Command {
+	opts := &auditOpts{}
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit hosted clusters on a management cluster for autoscaling migration readiness",
+		Long: `Analyze all hosted clusters on a management cluster and categorize them based on their
+autoscaling migration readiness. Clusters are categorized into:
+- Group A: Needs annotation removal (have cluster-size-override annotation)
+- Group B: Ready for migration (missing required autoscaling annotations)
+- Already configured (have autoscaling annotations set)
+- Drifted (with --check-drift: ManifestWork and live HostedCluster annotations differ)
+- Paused (spec.pausedUntil or a manual control plane annotation is set; skipped by migrate)
+- Deleting (the HostedCluster is being deleted; never migrated)
+- Unmanaged (with --include-unmanaged: outside the OCM namespaces, no ManifestWork; never migrated)`,
+		Example: `
+  # Audit all hosted clusters on a management cluster
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123
+
+  # Show only clusters that need annotation removal
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --show-only needs-removal
+
+  # Export to JSON for scripting
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --output json
+
+  # Export to CSV for spreadsheet analysis
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --output csv
+
+  # Mark clusters under incident or customer freeze as excluded
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --exclude-file exclusions.txt
+
+  # Predict which clusters will change size class once autoscaling is enabled
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --simulate-sizing
+
+  # Compare ManifestWork annotations on the service cluster with the live HostedClusters
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --check-drift --service-cluster-id svc-456
+
+  # Keep reporting newly created clusters that need migration, appending them to a JSONL file
+  hcp-node-autoscaling audit --mgmt-cluster-id mgmt-cluster-123 --watch --watch-file new-clusters.jsonl
+`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID to audit")
+	cmd.Flags().StringVar(&opts.environment, "environment", "production",
+		"OCM environment whose hosted cluster namespaces are scanned: production, staging, all")
+	cmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text, wide, summary, json, yaml, csv, markdown, html, jsonl")
+	cmd.Flags().StringVar(&opts.outputFile, "output-file", "",
+		"Atomically write json, yaml, csv, markdown or html results to this file instead of stdout")
+	cmd.Flags().BoolVar(&opts.appendOutput, "append", false,
+		"Append results to --output-file instead of replacing it, e.g. to aggregate runs across management clusters")
+	cmd.Flags().StringVar(&opts.showOnly, "show-only", "",
+		"Filter results by category (needs-removal, ready-for-migration, drifted, paused, deleting), subcategory ("+strings.Join(subcategories, ", ")+
+			") or cluster name glob pattern (name=<pattern>, e.g. name=prod-*)")
+	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Skip headers in output (for text, wide, summary and csv formats)")
+	cmd.Flags().StringSliceVar(&opts.columnNames, "columns", nil,
+		"Columns of the csv output and of the cluster tables of the text, wide, markdown and html output, in order, "+
+			"e.g. cluster_id,current_size,label:ext-managed.openshift.io/sector")
+	cmd.Flags().BoolVar(&opts.sizeAnalysis, "size-analysis", true,
+		"Collect NodePool, worker and control plane request data and compute the expected size class")
+	cmd.Flags().BoolVar(&opts.simulateSizing, "simulate-sizing", false,
+		"Predict the size class the resource-based autoscaler will choose after migration and flag clusters expected to change size")
+	cmd.Flags().BoolVar(&opts.checkDrift, "check-drift", false,
+		"Compare ManifestWork annotations on the service cluster with the live HostedCluster annotations")
+	cmd.Flags().BoolVar(&opts.includeUnmanaged, "include-unmanaged", false,
+		"Also audit HostedClusters in namespaces outside the OCM namespaces, reported as unmanaged and never migrated")
+	cmd.Flags().BoolVar(&opts.enrichOCM, "enrich-ocm", false,
+		"Add OCM cluster state, subscription status, organization and support level to each cluster (slow)")
+	cmd.Flags().BoolVar(&opts.noCache, "no-cache", false, "Always query OCM instead of using cached cluster lookups")
+	cmd.Flags().DurationVar(&opts.cacheTTL, "cache-ttl", defaultCacheTTL, "How long cached OCM cluster lookups are used (0 disables the cache)")
+	cmd.Flags().StringSliceVar(&opts.failOnFlag, "fail-on", []string{"needs-removal", "errors"},
+		"Exit with code 2 when any of these are found: needs-removal, ready-for-migration, drifted, errors, none")
+	cmd.Flags().BoolVar(&opts.watch, "watch", false,
+		"After the audit, keep watching HostedClusters and report clusters that newly need annotation removal or migration until interrupted")
+	cmd.Flags().StringVar(&opts.watchFile, "watch-file", "",
+		"Also append each cluster reported by --watch to this file as a JSON line")
+	cmd.Flags().StringVar(&opts.diffFile, "diff", "",
+		"Compare the results with a previous audit report written with --output json and report new, removed and recategorized clusters and annotation changes")
+	cmd.Flags().BoolVar(&opts.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login and backplane access before the audit")
+	cmd.Flags().StringVar(&opts.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist, used with --check-drift (default: discovered from the management cluster)")
+	cmd.Flags().StringSliceVar(&opts.excludeClusterIDs, "exclude-cluster-ids", nil,
+		"Comma-separated cluster IDs to mark as excluded from migration")
+	cmd.Flags().StringVar(&opts.excludeFile, "exclude-file", "",
+		"File of cluster IDs to mark as excluded from migration, one per line")
+	cmd.Flags().StringVar(&opts.labelSelector, "label-selector", "",
+		"Only audit HostedClusters whose labels match this selector (e.g. hypershift.openshift.io/hosted-cluster-size=large)")
+	cmd.Flags().StringVar(&opts.annotationSelector, "annotation-selector", "",
+		"Only audit HostedClusters whose annotations match this selector, using label selector syntax")
+	cmd.Flags().StringVar(&opts.profilePath, "profile", "",
+		"Migration profile YAML declaring the annotations to ensure and remove and the categorization rules (default built-in profile)")
+	cmd.Flags().StringVar(&opts.metricsPushgatewayURL, "metrics-pushgateway-url", "",
+		"Push run metrics to this Prometheus pushgateway URL when the run completes")
+	cmd.Flags().StringVar(&opts.notifyWebhook, "notify-webhook", "",
+		"Post a summary of the run to this webhook URL when the run completes")
+	cmd.Flags().StringVar(&opts.notifyFormat, "notify-format", "slack",
+		"Payload of --notify-webhook: slack, json")
+	cmd.Flags().StringSliceVar(&opts.exportDestinations, "export", nil,
+		"Upload the report after the run to these destinations: s3://<bucket>/<key>, gsheet:<sheet-id>")
+	addEventsBrokerFlags(cmd, &opts.eventsBrokerURL, &opts.eventsTopic)
+	opts.timeouts.addFlags(cmd, false)
+	opts.kubeconfigs.addFlags(cmd, true)
+	opts.listing.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run executes the audit command to analyze hosted clusters for autoscaling readiness.
+func (a *auditOpts) run(ctx context.Context) error {
+	started := time.Now()
+	if err := utils.IsValidClusterKey(a.mgmtClusterID); err != nil {
+		return err
+	}
+
+	if err := output.ValidateFormat(a.output, "text", "wide", "summary", "json", "yaml", "csv", "markdown", "html", "jsonl"); err != nil {
+		return err
+	}
+	if a.output == "jsonl" && a.showOnly != "" {
+		return fmt.Errorf("--show-only is not supported with --output jsonl")
+	}
+
+	if err := validateOutputFile(a.outputFile, a.appendOutput, a.output); err != nil {
+		return err
+	}
+
+	if len(a.columnNames) > 0 {
+		if a.output == "summary" || a.output == "json" || a.output == "yaml" || a.output == "jsonl" {
+			return fmt.Errorf("--columns is only supported with text, wide, csv, markdown and html output")
+		}
+		columns, err := parseColumns(a.columnNames)
+		if err != nil {
+			return err
+		}
+		a.columns = columns
+	}
+
+	if _, err := ocmNamespacePattern(a.environment); err != nil {
+		return err
+	}
+
+	if a.diffFile != "" {
+		switch a.output {
+		case "text", "wide", "summary", "json", "yaml":
+		default:
+			return fmt.Errorf("--diff is only supported with text, wide, summary, json and yaml output")
+		}
+		report, err := loadAuditReport(a.diffFile)
+		if err != nil {
+			return err
+		}
+		a.previousReport = report
+	}
+
+	selector, err := parseHostedClusterSelector(a.labelSelector, a.annotationSelector)
+	if err != nil {
+		return err
+	}
+	a.selector = selector
+
+	a.profile, err = loadProfile(a.profilePath)
+	if err != nil {
+		return err
+	}
+
+	a.failOn, err = parseFailOn(a.failOnFlag)
+	if err != nil {
+		return err
+	}
+
+	if a.simulateSizing && !a.sizeAnalysis {
+		return fmt.Errorf("--simulate-sizing requires --size-analysis")
+	}
+
+	if a.watchFile != "" && !a.watch {
+		return fmt.Errorf("--watch-file requires --watch")
+	}
+	if a.watch && a.output != "text" && a.output != "wide" && a.output != "summary" {
+		return fmt.Errorf("--watch is only supported with text, wide and summary output; use --watch-file for machine-readable events")
+	}
+
+	if a.showOnly != "" {
+		validFilters := map[string]bool{"needs-removal": true, "ready-for-migration": true, "drifted": true, "paused": true, "deleting": true,
+			unmanagedCategory: true}
+		if isNameFilter(a.showOnly) {
+			if err := validateNameFilter(a.showOnly); err != nil {
+				return err
+			}
+		} else if !validFilters[a.showOnly] && !isSubcategory(a.showOnly) {
+			return fmt.Errorf("invalid show-only filter '%s'. Valid options: needs-removal, ready-for-migration, drifted, paused, deleting, unmanaged, %s, %s<pattern>",
+				a.showOnly, strings.Join(subcategories, ", "), nameFilterPrefix)
+		}
+		if a.showOnly == "drifted" && !a.checkDrift {
+			return fmt.Errorf("--show-only drifted requires --check-drift")
+		}
+		if a.showOnly == unmanagedCategory && !a.includeUnmanaged {
+			return fmt.Errorf("--show-only unmanaged requires --include-unmanaged")
+		}
+	}
+
+	if a.checkDrift && a.serviceClusterID != "" {
+		if err := utils.IsValidClusterKey(a.serviceClusterID); err != nil {
+			return fmt.Errorf("invalid service cluster ID: %v", err)
+		}
+	}
+
+	a.exclusions, err = loadExclusions(a.excludeClusterIDs, a.excludeFile)
+	if err != nil {
+		return err
+	}
+
+	if err := a.kubeconfigs.validate(); err != nil {
+		return err
+	}
+	if err := a.listing.validate(); err != nil {
+		return err
+	}
+
+	if a.metricsPushgatewayURL != "" {
+		if err := validatePushgatewayURL(a.metricsPushgatewayURL); err != nil {
+			return err
+		}
+		a.metrics = newRunMetrics("audit", a.session.runID())
+	}
+	if err := validateNotifyFlags(a.notifyWebhook, a.notifyFormat); err != nil {
+		return err
+	}
+	a.exporters, err = newExporters(ctx, a.exportDestinations, a.output)
+	if err != nil {
+		return err
+	}
+	a.broker, err = newEventsBroker(ctx, a.eventsBrokerURL, a.eventsTopic, a.session.runID())
+	if err != nil {
+		return err
+	}
+	defer a.broker.close()
+
+	a.cache, err = newOCMCache(a.noCache, a.cacheTTL)
+	if err != nil {
+		return err
+	}
+	if a.output == "jsonl" {
+		a.events = newEventStream(os.Stdout, a.session.runID())
+	}
+
+	if !a.skipPreflight {
+		err := runPreflight(ctx, &preflightOpts{
+			mgmtClusterID:    a.mgmtClusterID,
+			serviceClusterID: a.serviceClusterID,
+			serviceCluster:   a.checkDrift,
+			kubeconfigs:      a.kubeconfigs,
+			clients:          a.clients,
+			session:          a.session,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+	a.ocmConn = connection
+
+	var cluster *cmv1.Cluster
+	err = traced(ctx, spanOCMManagementCluster, func(context.Context) error {
+		defer a.session.timings().track(phaseOCMCalls)()
+
+		cluster, err = a.cache.getCluster(connection, a.mgmtClusterID)
+		if err != nil {
+			return fmt.Errorf("failed to get cluster: %v", err)
+		}
+
+		isMC, err := a.cache.isManagementCluster(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("failed to verify if cluster is a management cluster: %v", err)
+		}
+		if !isMC {
+			return fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
+		}
+		return nil
+	}, attribute.String(attrMgmtClusterID, a.mgmtClusterID))
+	if err != nil {
+		return err
+	}
+
+	a.mgmtClusterID = cluster.ID()
+	setRunAttributes(ctx, attribute.String(attrMgmtClusterID, a.mgmtClusterID))
+	a.mgmtClusterName = cluster.Name()
+
+	if a.previousReport != nil && a.previousReport.MgmtClusterID != a.mgmtClusterID {
+		return fmt.Errorf("audit report %s is for management cluster %s, not %s", a.diffFile, a.previousReport.MgmtClusterID, a.mgmtClusterID)
+	}
+
+	defer func() {
+		if err := a.metrics.push(a.metricsPushgatewayURL, a.mgmtClusterID); err != nil {
+			slog.Warn("Failed to push metrics", "error", err)
+		}
+	}()
+
+	slog.Info("Auditing management cluster", "name", cluster.Name(), "id", cluster.ID())
+
+	clientScheme, err := scheme.Cached(scheme.HyperShift, scheme.Core, scheme.Scheduling, scheme.Work)
+	if err != nil {
+		return err
+	}
+
+	clients := a.kubeconfigs.clients(a.clients, a.session.apiRateLimit(), a.mgmtClusterID, "")
+	var mgmtClient client.Client
+	if a.watch {
+		a.watchClient, err = clients.NewWatchClient(a.mgmtClusterID, clientScheme)
+		mgmtClient = a.watchClient
+	} else {
+		mgmtClient, err = clients.NewClient(a.mgmtClusterID, clientScheme)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	a.mgmtClient = mgmtClient
+
+	if a.checkDrift {
+		resolved := a.session.timings().track(phaseOCMCalls)
+		serviceCluster, err := resolveServiceCluster(connection, a.cache, a.serviceClusterID, cluster.Name())
+		resolved()
+		if err != nil {
+			return err
+		}
+
+		serviceClients := a.kubeconfigs.clients(a.clients, a.session.apiRateLimit(), a.mgmtClusterID, serviceCluster.ID())
+		serviceClient, err := serviceClients.NewClient(serviceCluster.ID(), clientScheme)
+		if err != nil {
+			return fmt.Errorf("failed to create service cluster client: %v", err)
+		}
+		a.serviceClient = serviceClient
+
+		slog.Info("Checking ManifestWork drift", "serviceCluster", serviceCluster.Name(),
+			"serviceClusterID", serviceCluster.ID(), "manifestWorkNamespace", a.mgmtClusterName)
+	}
+
+	if a.sizeAnalysis {
+		sizeClasses, err := a.loadSizeClasses(ctx)
+		if err != nil {
+			slog.Warn("Expected size classes will not be computed", "error", err)
+		}
+		a.sizeClasses = sizeClasses
+	}
+
+	hypershiftVersion, err := a.hypershiftOperatorVersion(ctx)
+	if err != nil {
+		slog.Warn("HyperShift operator version will not be reported", "error", err)
+	}
+	a.hypershiftVersion = hypershiftVersion
+
+	listed := a.session.timings().track(phaseNamespaceList)
+	namespaces, err := a.listOcmNamespaces(ctx)
+	listed()
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %v", err)
+	}
+
+	slog.Info("Found OCM namespaces to audit", "environment", a.environment, "count", len(namespaces))
+
+	if a.includeUnmanaged {
+		listed := a.session.timings().track(phaseNamespaceList)
+		unmanaged, err := a.listUnmanagedNamespaces(ctx)
+		listed()
+		if err != nil {
+			return fmt.Errorf("failed to list unmanaged hosted cluster namespaces: %v", err)
+		}
+		slog.Info("Found unmanaged hosted cluster namespaces to audit", "count", len(unmanaged))
+		namespaces = append(namespaces, unmanaged...)
+	}
+
+	results, audited := a.auditNamespaces(ctx, namespaces)
+	results.Summary = newAuditSummary(results, time.Since(started))
+	if a.previousReport != nil {
+		if results.Partial {
+			slog.Warn("Not comparing with the previous audit because the results are partial", "file", a.diffFile)
+		} else {
+			results.Diff = diffAuditResults(a.previousReport, results, a.profile.annotationKeys())
+		}
+	}
+
+	filtered := results
+	if a.showOnly != "" {
+		filtered = a.applyFilter(results)
+	}
+
+	written := a.session.timings().track(phaseOutput)
+	if err := a.outputResults(filtered); err != nil {
+		written()
+		return err
+	}
+	exportErr := a.exportResults(ctx, filtered)
+	written()
+
+	notify(ctx, a.notifyWebhook, a.notifyFormat, a.auditNotification(results))
+	if exportErr != nil {
+		return exportErr
+	}
+
+	if results.Partial {
+		return withExitCode(exitInterrupted, fmt.Errorf("audit interrupted after %d of %d namespaces", audited, len(namespaces)))
+	}
+
+	if a.watch {
+		return a.watchHostedClusters(ctx, os.Stdout, results)
+	}
+
+	return failOnError(a.failOn, results)
+}
+
+// auditNamespaces audits the hosted cluster in each namespace and groups the clusters by category as they
+// are streamed by streamAudit. It stops early when ctx is cancelled, marking the results as partial, and
+// returns the number of namespaces audited.
+func (a *auditOpts) auditNamespaces(ctx context.Context, namespaces []corev1.Namespace) (*auditResults, int) {
+	results := &auditResults{
+		SchemaVersion:     auditSchemaVersion,
+		MgmtClusterID:     a.mgmtClusterID,
+		GeneratedAt:       time.Now().UTC().Format(time.RFC3339),
+		RunID:             a.session.runID(),
+		Environment:       a.environment,
+		NeedsLabelRemoval: []hostedClusterAuditInfo{},
+		ReadyForMigration: []hostedClusterAuditInfo{},
+		AlreadyConfigured: []hostedClusterAuditInfo{},
+		Errors:            []auditError{},
+	}
+
+	progress := newProgressBar(a.session.progressOut(), "Auditing", len(namespaces))
+	defer progress.close()
+
+	if len(namespaces) > 0 {
+		progress.begin(namespaces[0].Name)
+	}
+
+	audited := 0
+	for event := range a.streamAudit(ctx, namespaces) {
+		progress.finish()
+		audited++
+		if audited < len(namespaces) {
+			progress.begin(namespaces[audited].Name)
+		}
+		if event.Err != nil {
+			slog.Warn("Failed to audit namespace", "namespace", event.Namespace, "error", event.Err)
+			a.events.emit(streamEvent{Event: eventError, MgmtClusterID: a.mgmtClusterID, Namespace: event.Namespace,
+				Error: event.Err.Error()})
+			a.metrics.recordNamespaceError()
+			results.Errors = append(results.Errors, auditError{
+				Namespace: event.Namespace,
+				Error:     event.Err.Error(),
+			})
+			continue
+		}
+
+		clusters := len(event.Clusters)
+		a.events.emit(streamEvent{Event: eventNamespaceAudited, MgmtClusterID: a.mgmtClusterID, Namespace: event.Namespace,
+			Clusters: &clusters})
+		for _, info := range event.Clusters {
+			a.metrics.recordAudited(info.Category)
+			a.events.emit(streamEvent{Event: eventClusterCategorized, MgmtClusterID: a.mgmtClusterID, Namespace: info.Namespace,
+				ClusterID: info.ClusterID, ClusterName: info.ClusterName, Category: info.Category, Subcategory: info.Subcategory})
+			a.broker.categorized(a.mgmtClusterID, info)
+
+			switch info.Category {
+			case "needs-removal":
+				results.NeedsLabelRemoval = append(results.NeedsLabelRemoval, info)
+			case "ready-for-migration":
+				results.ReadyForMigration = append(results.ReadyForMigration, info)
+			case "already-configured":
+				results.AlreadyConfigured = append(results.AlreadyConfigured, info)
+			case "drifted":
+				results.Drifted = append(results.Drifted, info)
+			case "paused":
+				results.Paused = append(results.Paused, info)
+			case "deleting":
+				results.Deleting = append(results.Deleting, info)
+			case unmanagedCategory:
+				results.Unmanaged = append(results.Unmanaged, info)
+			}
+		}
+	}
+
+	results.TotalScanned = len(results.NeedsLabelRemoval) +
+		len(results.ReadyForMigration) +
+		len(results.AlreadyConfigured) +
+		len(results.Drifted) +
+		len(results.Paused) +
+		len(results.Deleting) +
+		len(results.Unmanaged)
+
+	if audited < len(namespaces) {
+		slog.Warn("Audit interrupted, reporting partial results", "audited", audited, "total", len(namespaces))
+		results.Partial = true
+	}
+
+	return results, audited
+}
+
+// ocmNamespacePattern returns the pattern matching hosted cluster namespaces for an OCM environment.
+func ocmNamespacePattern(environment string) (*regexp.Regexp, error) {
+	switch environment {
+	case "production", "staging":
+		return regexp.MustCompile(`^ocm-` + environment + `-[a-zA-Z0-9]+$`), nil
+	case "all":
+		return regexp.MustCompile(`^ocm-(production|staging)-[a-zA-Z0-9]+$`), nil
+	default:
+		return nil, fmt.Errorf("invalid environment '%s'. Valid options: production, staging, all", environment)
+	}
+}
+
+// listOcmNamespaces returns the OCM namespaces for the selected environment from the management cluster.
+// Namespaces are listed in pages and only the OCM namespaces of each page are kept, bounding the memory
+// used on management clusters with many namespaces. The list namespaces deadline applies to each page.
+func (a *auditOpts) listOcmNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	pattern, err := a.listing.namespaceMatcher(a.environment)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []corev1.Namespace
+	continueToken := ""
+	for page := 1; ; page++ {
+		nsList := &corev1.NamespaceList{}
+		err = withPhaseTimeout(ctx, a.timeouts.listNamespaces, "listing namespaces", "management cluster "+a.mgmtClusterID,
+			func(ctx context.Context) error {
+				return a.mgmtClient.List(ctx, nsList, a.listing.listOptions(continueToken)...)
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ns := range nsList.Items {
+			if pattern.MatchString(ns.Name) {
+				filtered = append(filtered, ns)
+			}
+		}
+		slog.Debug("Listed namespace page", "page", page, "namespaces", len(nsList.Items), "ocmNamespaces", len(filtered))
+
+		if nsList.Continue == "" {
+			return filtered, nil
+		}
+		continueToken = nsList.Continue
+	}
+}
+
+// auditNamespace analyzes a single namespace and returns audit information for each of its hosted
+// clusters that matches the audit selectors.
+func (a *auditOpts) auditNamespace(ctx context.Context, namespace string) ([]hostedClusterAuditInfo, error) {
+	ctx, span := startSpan(ctx, spanNamespaceAudit, attribute.String(attrNamespace, namespace))
+	var infos []hostedClusterAuditInfo
+	err := withPhaseTimeout(ctx, a.timeouts.namespace, "auditing namespace", namespace, func(ctx context.Context) error {
+		hostedClusters, err := a.getHostedClustersInNamespace(ctx, namespace)
+		if err != nil {
+			return err
+		}
+		for i := range hostedClusters {
+			if info := a.auditHostedCluster(ctx, &hostedClusters[i]); info != nil {
+				infos = append(infos, *info)
+			}
+		}
+		return nil
+	})
+	span.SetAttributes(clusterIDsAttribute(infos))
+	endSpan(span, err)
+	return infos, err
+}
+
+// auditHostedCluster categorizes a HostedCluster and collects the optional sizing, OCM and drift data.
+// It returns nil when the HostedCluster does not match the audit selectors.
+func (a *auditOpts) auditHostedCluster(ctx context.Context, hc *hypershiftv1beta1.HostedCluster) *hostedClusterAuditInfo {
+	namespace := hc.Namespace
+	if !a.selector.matches(hc) {
+		slog.Debug("Skipping HostedCluster not matching selectors", "namespace", namespace, "name", hc.Name)
+		return nil
+	}
+
+	clusterID := hc.Labels["api.openshift.com/id"]
+	currentSize := hc.Labels["hypershift.openshift.io/hosted-cluster-size"]
+
+	category := a.categorizeCluster(hc)
+
+	info := &hostedClusterAuditInfo{
+		ClusterID:   clusterID,
+		ClusterName: hc.Name,
+		Namespace:   namespace,
+		CurrentSize: currentSize,
+		Category:    category,
+		Labels:      hc.Labels,
+		Annotations: hc.Annotations,
+		Available:   hostedClusterAvailable(hc),
+		Conditions:  summarizeConditions(hc),
+		Subcategory: subcategoryOf(a.profile, hc.Annotations),
+		Reasons:     categoryReasons(a.profile, hc.Annotations),
+
+		SizeOverride: hc.Annotations["hypershift.openshift.io/cluster-size-override"],
+		PausedReason: pausedReason(hc, time.Now()),
+
+		MigratedAt:     hc.Annotations[migratedAtAnnotation],
+		MigrationRunID: hc.Annotations[migrationRunIDAnnotation],
+
+		OpenShiftVersion:  hostedClusterVersion(hc),
+		ChannelGroup:      channelGroup(hc.Spec.Channel),
+		HyperShiftVersion: a.hypershiftVersion,
+	}
+
+	if reason, ok := a.exclusions[clusterID]; ok {
+		info.Excluded = true
+		info.ExclusionReason = reason
+	}
+
+	if a.sizeAnalysis {
+		if err := a.analyzeSizing(ctx, hc, info); err != nil {
+			slog.Warn("Size class analysis failed", "namespace", namespace, "error", err)
+		}
+	}
+
+	if a.enrichOCM {
+		err := traced(ctx, spanOCMCluster, func(context.Context) error {
+			return a.enrichFromOCM(info)
+		}, clusterAttributes(*info)...)
+		if err != nil {
+			slog.Warn("OCM enrichment failed", "namespace", namespace, "clusterID", clusterID, "error", err)
+		}
+	}
+
+	if hc.DeletionTimestamp != nil {
+		info.DeletionTimestamp = hc.DeletionTimestamp.UTC().Format(time.RFC3339)
+	}
+
+	if a.checkDrift && category != "deleting" && category != unmanagedCategory {
+		drift, err := a.detectDrift(ctx, hc)
+		if err != nil {
+			slog.Warn("Drift check failed", "namespace", namespace, "error", err)
+		} else if len(drift) > 0 {
+			info.Category = "drifted"
+			info.Drift = drift
+		}
+	}
+
+	return info
+}
+
+// getHostedClustersInNamespace retrieves the HostedCluster resources from a namespace. Namespaces usually
+// hold a single HostedCluster, but some staging management clusters have several.
+func (a *auditOpts) getHostedClustersInNamespace(ctx context.Context, namespace string) ([]hypershiftv1beta1.HostedCluster, error) {
+	hcList := &hypershiftv1beta1.HostedClusterList{}
+	listOpts := []client.ListOption{client.InNamespace(namespace)}
+
+	if err := a.mgmtClient.List(ctx, hcList, listOpts...); err != nil {
+		return nil, err
+	}
+
+	if len(hcList.Items) == 0 {
+		return nil, fmt.Errorf("no HostedCluster found")
+	}
+
+	if len(hcList.Items) > 1 {
+		slog.Debug("Namespace has multiple HostedClusters", "namespace", namespace, "count", len(hcList.Items))
+	}
+
+	return hcList.Items, nil
+}
+
+// categorizeCluster determines the migration category for a hosted cluster using the migration profile rules.
+// Clusters in unmanaged namespaces are always categorized as unmanaged, clusters that are being deleted as
+// deleting, and paused clusters that still need work are categorized as paused unless includePaused is set.
+func (a *auditOpts) categorizeCluster(hc *hypershiftv1beta1.HostedCluster) string {
+	if a.unmanagedNamespaces[hc.Namespace] {
+		return unmanagedCategory
+	}
+	if !hc.DeletionTimestamp.IsZero() {
+		return "deleting"
+	}
+	category := a.profile.categorize(hc.Annotations)
+	if category != "already-configured" && !a.includePaused && pausedReason(hc, time.Now()) != "" {
+		return "paused"
+	}
+	return category
+}
+
+// applyFilter filters audit results based on the showOnly option.
+func (a *auditOpts) applyFilter(results *auditResults) *auditResults {
+	filtered := &auditResults{
+		SchemaVersion: results.SchemaVersion,
+		MgmtClusterID: results.MgmtClusterID,
+		GeneratedAt:   results.GeneratedAt,
+		RunID:         results.RunID,
+		Environment:   results.Environment,
+		Errors:        results.Errors,
+		Partial:       results.Partial,
+		Diff:          results.Diff,
+		Summary:       results.Summary,
+	}
+
+	switch a.showOnly {
+	case "needs-removal":
+		filtered.NeedsLabelRemoval = results.NeedsLabelRemoval
+		filtered.TotalScanned = len(results.NeedsLabelRemoval)
+	case "ready-for-migration":
+		filtered.ReadyForMigration = results.ReadyForMigration
+		filtered.TotalScanned = len(results.ReadyForMigration)
+	case "drifted":
+		filtered.Drifted = results.Drifted
+		filtered.TotalScanned = len(results.Drifted)
+	case "paused":
+		filtered.Paused = results.Paused
+		filtered.TotalScanned = len(results.Paused)
+	case "deleting":
+		filtered.Deleting = results.Deleting
+		filtered.TotalScanned = len(results.Deleting)
+	case unmanagedCategory:
+		filtered.Unmanaged = results.Unmanaged
+		filtered.TotalScanned = len(results.Unmanaged)
+	default:
+		var keep func(hostedClusterAuditInfo) bool
+		switch {
+		case isSubcategory(a.showOnly):
+			keep = func(c hostedClusterAuditInfo) bool { return c.Subcategory == a.showOnly }
+		case isNameFilter(a.showOnly):
+			keep = func(c hostedClusterAuditInfo) bool { return matchesNameFilter(a.showOnly, c) }
+		default:
+			return results
+		}
+		filterCategories(filtered, results, keep)
+	}
+
+	return filtered
+}
+
+// filterCategories sets the clusters of every category of filtered to those of results that keep selects.
+func filterCategories(filtered, results *auditResults, keep func(hostedClusterAuditInfo) bool) {
+	filter := func(clusters []hostedClusterAuditInfo) []hostedClusterAuditInfo {
+		var kept []hostedClusterAuditInfo
+		for _, c := range clusters {
+			if keep(c) {
+				kept = append(kept, c)
+			}
+		}
+		return kept
+	}
+	filtered.NeedsLabelRemoval = filter(results.NeedsLabelRemoval)
+	filtered.ReadyForMigration = filter(results.ReadyForMigration)
+	filtered.AlreadyConfigured = filter(results.AlreadyConfigured)
+	filtered.Drifted = filter(results.Drifted)
+	filtered.Paused = filter(results.Paused)
+	filtered.Deleting = filter(results.Deleting)
+	filtered.Unmanaged = filter(results.Unmanaged)
+	filtered.TotalScanned = len(filtered.NeedsLabelRemoval) + len(filtered.ReadyForMigration) +
+		len(filtered.AlreadyConfigured) + len(filtered.Drifted) + len(filtered.Paused) + len(filtered.Deleting) +
+		len(filtered.Unmanaged)
+}
+
+// outputResults formats and prints audit results in the specified output format.
+func (a *auditOpts) outputResults(results *auditResults) error {
+	if a.outputFile != "" {
+		err := output.WriteFile(a.outputFile, a.appendOutput, func(w io.Writer, appending bool) error {
+			return a.printStructuredOutput(w, results, appending)
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("Wrote audit results", "file", a.outputFile, "format", a.output, "append", a.appendOutput)
+		return nil
+	}
+
+	switch a.output {
+	case "json", "yaml", "csv", "markdown", "html":
+		return a.printStructuredOutput(os.Stdout, results, false)
+	case "jsonl":
+		// The events were written as the namespaces were audited.
+		return nil
+	case "summary":
+		if err := a.printSummaryOutput(results); err != nil {
+			return err
+		}
+	default:
+		if err := a.printTextOutput(results); err != nil {
+			return err
+		}
+	}
+	printAuditDiff(os.Stdout, results.Diff, a.noHeaders)
+	return nil
+}
+
+// hostedClusterAvailable returns the status of the HostedCluster Available condition, or an empty
+// string if the condition has not been reported.
+func hostedClusterAvailable(hc *hypershiftv1beta1.HostedCluster) string {
+	for _, c := range hc.Status.Conditions {
+		if c.Type == "Available" {
+			return string(c.Status)
+		}
+	}
+	return ""
+}
+
+// clusterTableHeader returns the column headers for the per-category cluster tables.
+func (a *auditOpts) clusterTableHeader() []string {
+	if len(a.columns) > 0 {
+		header := make([]string, 0, len(a.columns))
+		for _, column := range a.columns {
+			header = append(header, column.header())
+		}
+		return header
+	}
+	header := []string{"CLUSTER ID", "CLUSTER NAME", "NAMESPACE", "CURRENT SIZE"}
+	if a.output == "wide" {
+		header = append(header, "TOPOLOGY", "AUTOSCALING", "OVERRIDE", "AVAILABLE", "DEGRADED", "PROGRESSING", "VERSION", "CHANNEL GROUP", "SUBCATEGORY")
+	}
+	if a.enrichOCM {
+		header = append(header, "OCM STATE", "SUBSCRIPTION", "ORGANIZATION", "SUPPORT")
+	}
+	return header
+}
+
+// clusterTableRow returns the row for a cluster in the per-category cluster tables.
+func (a *auditOpts) clusterTableRow(c hostedClusterAuditInfo) []string {
+	if len(a.columns) > 0 {
+		row := make([]string, 0, len(a.columns))
+		for _, column := range a.columns {
+			row = append(row, driftValue(column.value(c)))
+		}
+		return row
+	}
+	row := []string{c.ClusterID, c.ClusterName, c.Namespace, c.CurrentSize}
+	if a.output == "wide" {
+		row = append(row,
+			driftValue(c.Annotations["hypershift.openshift.io/topology"]),
+			driftValue(c.Annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"]),
+			driftValue(c.Annotations["hypershift.openshift.io/cluster-size-override"]),
+			driftValue(c.condition(hypershiftv1beta1.HostedClusterAvailable)),
+			driftValue(c.condition(hypershiftv1beta1.HostedClusterDegraded)),
+			driftValue(c.condition(hypershiftv1beta1.HostedClusterProgressing)),
+			driftValue(c.OpenShiftVersion),
+			driftValue(c.ChannelGroup),
+			driftValue(c.Subcategory))
+	}
+	if a.enrichOCM {
+		row = append(row, c.OCMState, c.SubscriptionStatus, c.OrganizationName, c.SupportLevel)
+	}
+	return row
+}
+
+// printTextOutput prints audit results in human-readable text format.
+func (a *auditOpts) printTextOutput(results *auditResults) error {
+	fmt.Printf("\nManagement Cluster: %s\n", results.MgmtClusterID)
+	printRunID(os.Stdout, results.RunID)
+	if a.hypershiftVersion != "" {
+		fmt.Printf("HyperShift Operator: %s\n", a.hypershiftVersion)
+	}
+	fmt.Printf("Total Hosted Clusters Scanned: %d\n\n", results.TotalScanned)
+
+	if results.Partial {
+		fmt.Println("WARNING: audit was interrupted; results are partial")
+		fmt.Println()
+	}
+
+	if len(results.NeedsLabelRemoval) > 0 {
+		fmt.Printf("=== GROUP A: Needs Annotation Removal (%d clusters) ===\n", len(results.NeedsLabelRemoval))
+		fmt.Println("These clusters have the cluster-size-override annotation that must be removed:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow(a.clusterTableHeader())
+		}
+
+		sort.Slice(results.NeedsLabelRemoval, func(i, j int) bool {
+			return results.NeedsLabelRemoval[i].ClusterID < results.NeedsLabelRemoval[j].ClusterID
+		})
+
+		for _, c := range results.NeedsLabelRemoval {
+			p.AddRow(a.clusterTableRow(c))
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.ReadyForMigration) > 0 {
+		fmt.Printf("=== GROUP B: Ready for Migration (%d clusters) ===\n", len(results.ReadyForMigration))
+		fmt.Println("These clusters can be immediately migrated to autoscaling:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow(a.clusterTableHeader())
+		}
+
+		sort.Slice(results.ReadyForMigration, func(i, j int) bool {
+			return results.ReadyForMigration[i].ClusterID < results.ReadyForMigration[j].ClusterID
+		})
+
+		for _, c := range results.ReadyForMigration {
+			p.AddRow(a.clusterTableRow(c))
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if a.showsAlreadyConfigured() && len(results.AlreadyConfigured) > 0 {
+		fmt.Printf("=== Already Configured (%d clusters) ===\n", len(results.AlreadyConfigured))
+		fmt.Println("These clusters already have autoscaling annotations set:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow(a.clusterTableHeader())
+		}
+
+		sort.Slice(results.AlreadyConfigured, func(i, j int) bool {
+			return results.AlreadyConfigured[i].ClusterID < results.AlreadyConfigured[j].ClusterID
+		})
+
+		for _, c := range results.AlreadyConfigured {
+			p.AddRow(a.clusterTableRow(c))
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Drifted) > 0 {
+		fmt.Printf("=== Drifted (%d clusters) ===\n", len(results.Drifted))
+		fmt.Println("These clusters have ManifestWork annotations that differ from the live HostedCluster:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "MANIFESTWORK", "HOSTEDCLUSTER"})
+		}
+
+		sort.Slice(results.Drifted, func(i, j int) bool {
+			return results.Drifted[i].ClusterID < results.Drifted[j].ClusterID
+		})
+
+		for _, c := range results.Drifted {
+			for _, d := range c.Drift {
+				p.AddRow([]string{c.ClusterID, c.ClusterName, d.Annotation, driftValue(d.ManifestWork), driftValue(d.HostedCluster)})
+			}
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Paused) > 0 {
+		fmt.Printf("=== Paused (%d clusters) ===\n", len(results.Paused))
+		fmt.Println("These clusters are paused or have their control plane managed by hand and are skipped by migrate:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "CURRENT SIZE", "REASON"})
+		}
+
+		sort.Slice(results.Paused, func(i, j int) bool {
+			return results.Paused[i].ClusterID < results.Paused[j].ClusterID
+		})
+
+		for _, c := range results.Paused {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.CurrentSize, c.PausedReason})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Deleting) > 0 {
+		fmt.Printf("=== Deleting (%d clusters) ===\n", len(results.Deleting))
+		fmt.Println("These clusters are being deleted and are never migrated:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "CURRENT SIZE", "DELETION TIMESTAMP"})
+		}
+		for _, c := range sortedByClusterID(results.Deleting) {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.CurrentSize, c.DeletionTimestamp})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Unmanaged) > 0 {
+		fmt.Printf("=== Unmanaged (%d clusters) ===\n", len(results.Unmanaged))
+		fmt.Println("These clusters are outside the OCM namespaces, have no ManifestWork and are never migrated:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow(a.clusterTableHeader())
+		}
+		for _, c := range sortedByClusterID(results.Unmanaged) {
+			p.AddRow(a.clusterTableRow(c))
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	allClusters := append(append(append(append(append([]hostedClusterAuditInfo{}, results.NeedsLabelRemoval...), results.ReadyForMigration...), results.AlreadyConfigured...), results.Drifted...), results.Paused...)
+	allClusters = append(append(allClusters, results.Deleting...), results.Unmanaged...)
+
+	var excluded []hostedClusterAuditInfo
+	for _, c := range allClusters {
+		if c.Excluded {
+			excluded = append(excluded, c)
+		}
+	}
+
+	if len(excluded) > 0 {
+		fmt.Printf("=== Excluded (%d clusters) ===\n", len(excluded))
+		fmt.Println("These clusters are on the exclusion list and will never be migrated:")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "CATEGORY", "REASON"})
+		}
+
+		sort.Slice(excluded, func(i, j int) bool {
+			return excluded[i].ClusterID < excluded[j].ClusterID
+		})
+
+		for _, c := range excluded {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.Category, c.ExclusionReason})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Errors) > 0 {
+		fmt.Printf("=== Errors (%d) ===\n", len(results.Errors))
+		p := output.NewTable(os.Stdout, output.WideTableMinWidth)
+		p.AddRow([]string{"NAMESPACE", "ERROR"})
+		for _, e := range results.Errors {
+			p.AddRow([]string{e.Namespace, e.Error})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if a.sizeAnalysis {
+		if transitions := summarizeSizeTransitions(allClusters); len(transitions) > 0 {
+			fmt.Println("=== Expected Size Redistribution ===")
+			fmt.Println("Current size class compared to the size class expected from worker node count:")
+
+			p := output.NewTable(os.Stdout, output.TableMinWidth)
+			if !a.noHeaders {
+				p.AddRow([]string{"CURRENT SIZE", "EXPECTED SIZE", "CLUSTERS"})
+			}
+			for _, t := range transitions {
+				p.AddRow([]string{t.From, t.To, strconv.Itoa(t.Count)})
+			}
+			p.Flush()
+			fmt.Println()
+		}
+	}
+
+	if a.simulateSizing {
+		printSizeChanges(os.Stdout, allClusters, a.noHeaders)
+	}
+
+	a.printCategoryCounts(results, len(excluded))
+
+	return nil
+}
+
+// printCategoryCounts prints the number of clusters in each audit category.
+func (a *auditOpts) printCategoryCounts(results *auditResults, excluded int) {
+	fmt.Println("Summary:")
+	fmt.Printf("  - Group A (Needs annotation removal): %d clusters\n", len(results.NeedsLabelRemoval))
+	fmt.Printf("  - Group B (Ready for migration): %d clusters\n", len(results.ReadyForMigration))
+	fmt.Printf("  - Already configured: %d clusters\n", len(results.AlreadyConfigured))
+	if a.checkDrift {
+		fmt.Printf("  - Drifted: %d clusters\n", len(results.Drifted))
+	}
+	fmt.Printf("  - Paused: %d clusters\n", len(results.Paused))
+	if len(results.Deleting) > 0 {
+		fmt.Printf("  - Deleting: %d clusters\n", len(results.Deleting))
+	}
+	if len(results.Unmanaged) > 0 {
+		fmt.Printf("  - Unmanaged: %d clusters\n", len(results.Unmanaged))
+	}
+	if len(a.exclusions) > 0 {
+		fmt.Printf("  - Excluded: %d clusters\n", excluded)
+	}
+	fmt.Printf("  - Errors: %d namespaces\n", len(results.Errors))
+}
+
+// driftValue formats an annotation value for table output, marking unset values.
+func driftValue(value string) string {
+	if value == "" {
+		return "<unset>"
+	}
+	return value
+}
+
+// printStructuredOutput writes audit results in a format that can be written to an output file. When
+// appending to an existing output file, CSV headers are skipped and YAML and markdown documents are separated.
+func (a *auditOpts) printStructuredOutput(out io.Writer, results *auditResults, appending bool) error {
+	switch a.output {
+	case "markdown":
+		return a.printMarkdownOutput(out, results, appending)
+	case "html":
+		return a.printHTMLOutput(out, results)
+	case "yaml":
+		return output.YAML(out, results, appending)
+	case "csv":
+		return a.printCSVOutput(out, results, !a.noHeaders && !appending)
+	default:
+		return output.JSON(out, results)
+	}
+}
+
+// printCSVOutput writes audit results in CSV format, with the --columns columns or all CSV columns.
+func (a *auditOpts) printCSVOutput(out io.Writer, results *auditResults, headers bool) error {
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	columns := a.columns
+	if len(columns) == 0 {
+		columns = csvColumns
+	}
+
+	if headers {
+		w.Write(columnNamesOf(columns))
+	}
+
+	allClusters := append(append(append(append([]hostedClusterAuditInfo{}, results.NeedsLabelRemoval...), results.ReadyForMigration...), results.AlreadyConfigured...), results.Drifted...)
+	allClusters = append(append(append(allClusters, results.Paused...), results.Deleting...), results.Unmanaged...)
+	for _, c := range allClusters {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.value(c))
+		}
+		w.Write(row)
+	}
+
+	return nil
+}
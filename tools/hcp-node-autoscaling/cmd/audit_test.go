@@ -1,15 +1,15 @@
-package main
+package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // TestCategorizeCluster verifies cluster categorization logic for migration readiness.
@@ -35,12 +35,20 @@ func TestCategorizeCluster(t *testing.T) {
 			expected: "needs-removal",
 		},
 		{
-			name: "already-configured: has required annotation",
+			name: "already-configured: has required annotations",
 			annotations: map[string]string{
 				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
 			},
 			expected: "already-configured",
 		},
+		{
+			name: "ready-for-migration: auto-scaling without dedicated topology",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+			expected: "ready-for-migration",
+		},
 		{
 			name:        "ready-for-migration: missing auto-scaling annotation",
 			annotations: map[string]string{},
@@ -185,10 +193,70 @@ func TestListOcmNamespaces(t *testing.T) {
 	}
 }
 
+// TestOcmNamespacePattern verifies the --environment selector constrains which OCM namespaces match.
+func TestOcmNamespacePattern(t *testing.T) {
+	tests := []struct {
+		environment string
+		matches     []string
+		rejects     []string
+		expectError bool
+	}{
+		{
+			environment: "production",
+			matches:     []string{"ocm-production-abc123"},
+			rejects:     []string{"ocm-staging-abc123", "ocm-production-abc123-extra", "kube-system"},
+		},
+		{
+			environment: "staging",
+			matches:     []string{"ocm-staging-abc123"},
+			rejects:     []string{"ocm-production-abc123", "ocm-staging-"},
+		},
+		{
+			environment: "all",
+			matches:     []string{"ocm-production-abc123", "ocm-staging-xyz789"},
+			rejects:     []string{"ocm-other-abc123"},
+		},
+		{
+			environment: "integration",
+			expectError: true,
+		},
+		{
+			environment: "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			pattern, err := ocmNamespacePattern(tt.environment)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for environment %q", tt.environment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, ns := range tt.matches {
+				if !pattern.MatchString(ns) {
+					t.Errorf("Expected %s to match environment %s", ns, tt.environment)
+				}
+			}
+			for _, ns := range tt.rejects {
+				if pattern.MatchString(ns) {
+					t.Errorf("Expected %s not to match environment %s", ns, tt.environment)
+				}
+			}
+		})
+	}
+}
+
 // TestApplyFilter verifies audit result filtering based on category.
 func TestApplyFilter(t *testing.T) {
 	baseResults := &auditResults{
 		MgmtClusterID: "test-cluster",
+		Environment:   "staging",
 		TotalScanned:  6,
 		NeedsLabelRemoval: []hostedClusterAuditInfo{
 			{ClusterID: "cluster1", Category: "needs-removal"},
@@ -255,266 +323,126 @@ func TestApplyFilter(t *testing.T) {
 			if filtered.TotalScanned != tt.expectedTotalScanned {
 				t.Errorf("TotalScanned = %d, want %d", filtered.TotalScanned, tt.expectedTotalScanned)
 			}
+			if filtered.MgmtClusterID != "test-cluster" || filtered.Environment != "staging" {
+				t.Errorf("Expected the management cluster and environment of the audit, got %q and %q",
+					filtered.MgmtClusterID, filtered.Environment)
+			}
 		})
 	}
 }
 
-// TestHasRequiredAnnotations verifies annotation validation for autoscaling readiness.
-func TestHasRequiredAnnotations(t *testing.T) {
-	tests := []struct {
-		name        string
-		annotations map[string]string
-		expected    bool
-	}{
-		{
-			name: "has required annotation with correct value",
-			annotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
-			},
-			expected: true,
-		},
-		{
-			name: "has required annotation with other annotations",
-			annotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
-				"other.annotation": "value",
-			},
-			expected: true,
-		},
-		{
-			name: "missing auto-scaling annotation",
-			annotations: map[string]string{
-				"other.annotation": "value",
-			},
-			expected: false,
-		},
-		{
-			name: "wrong auto-scaling value",
-			annotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "false",
-			},
-			expected: false,
-		},
-		{
-			name:        "nil annotations",
-			annotations: nil,
-			expected:    false,
-		},
-		{
-			name:        "empty annotations",
-			annotations: map[string]string{},
-			expected:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			hc := &hypershiftv1beta1.HostedCluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: tt.annotations,
-				},
-			}
+// TestAuditNamespaceMultipleHostedClusters verifies every HostedCluster of a namespace is audited.
+func TestAuditNamespaceMultipleHostedClusters(t *testing.T) {
+	first := newTestHostedCluster("a1", nil)
+	second := newTestHostedCluster("b2", map[string]string{"hypershift.openshift.io/cluster-size-override": "large"})
+	second.Namespace = first.Namespace
 
-			opts := &migrateOpts{}
-			result := opts.hasRequiredAnnotations(hc)
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(first, second).Build()
+	a := &auditOpts{mgmtClient: mgmtClient}
 
-			if result != tt.expected {
-				t.Errorf("hasRequiredAnnotations() = %v, want %v", result, tt.expected)
-			}
-		})
+	infos, err := a.auditNamespace(context.Background(), first.Namespace)
+	if err != nil {
+		t.Fatalf("auditNamespace() error = %v", err)
 	}
-}
-
-// TestPatchManifestWorkAnnotations verifies annotation injection into ManifestWork resources.
-func TestPatchManifestWorkAnnotations(t *testing.T) {
-	tests := []struct {
-		name                string
-		initialAnnotations  map[string]string
-		expectError         bool
-		expectedAnnotations map[string]string
-	}{
-		{
-			name: "adds annotation to cluster without existing annotations",
-			initialAnnotations: map[string]string{
-				"other.annotation": "value",
-			},
-			expectError: false,
-			expectedAnnotations: map[string]string{
-				"other.annotation": "value",
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
-			},
-		},
-		{
-			name:               "adds annotation to cluster with no annotations",
-			initialAnnotations: map[string]string{},
-			expectError:        false,
-			expectedAnnotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
-			},
-		},
-		{
-			name: "updates existing annotation",
-			initialAnnotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "false",
-			},
-			expectError: false,
-			expectedAnnotations: map[string]string{
-				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
-			},
-		},
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 audited clusters, got %d", len(infos))
 	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			hc := &hypershiftv1beta1.HostedCluster{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: "hypershift.openshift.io/v1beta1",
-					Kind:       "HostedCluster",
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name:        "test-cluster",
-					Namespace:   "test-namespace",
-					Annotations: tt.initialAnnotations,
-				},
-			}
-
-			hcJSON, err := json.Marshal(hc)
-			if err != nil {
-				t.Fatalf("Failed to marshal HostedCluster: %v", err)
-			}
-
-			mw := &workv1.ManifestWork{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test-cluster-id",
-					Namespace: "test-mgmt-cluster",
-				},
-				Spec: workv1.ManifestWorkSpec{
-					Workload: workv1.ManifestsTemplate{
-						Manifests: []workv1.Manifest{
-							{RawExtension: runtime.RawExtension{Raw: hcJSON}},
-						},
-					},
-				},
-			}
-
-			manifest := mw.Spec.Workload.Manifests[0]
-			var manifestData map[string]interface{}
-			if err := json.Unmarshal(manifest.Raw, &manifestData); err != nil {
-				t.Fatalf("Failed to unmarshal manifest: %v", err)
-			}
-
-			kind, _ := manifestData["kind"].(string)
-			if kind != "HostedCluster" {
-				t.Fatalf("Expected HostedCluster, got %s", kind)
-			}
-
-			metadata, ok := manifestData["metadata"].(map[string]interface{})
-			if !ok {
-				metadata = make(map[string]interface{})
-				manifestData["metadata"] = metadata
-			}
-
-			annotations, ok := metadata["annotations"].(map[string]interface{})
-			if !ok {
-				annotations = make(map[string]interface{})
-				metadata["annotations"] = annotations
-			}
-
-			annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] = "true"
-
-			for key, expectedValue := range tt.expectedAnnotations {
-				actualValue, ok := annotations[key]
-				if !ok {
-					t.Errorf("Expected annotation %s not found", key)
-					continue
-				}
-				if actualValue != expectedValue {
-					t.Errorf("Annotation %s = %v, want %v", key, actualValue, expectedValue)
-				}
-			}
-
-			if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
-				t.Errorf("auto-scaling annotation not set correctly")
-			}
-		})
+	ids := map[string]bool{}
+	for _, info := range infos {
+		ids[info.ClusterID] = true
+	}
+	if !ids["a1"] || !ids["b2"] {
+		t.Errorf("Expected clusters a1 and b2, got %+v", infos)
 	}
 }
 
-// TestPatchManifestWorkFindsHostedCluster verifies HostedCluster detection in multi-manifest ManifestWork.
-func TestPatchManifestWorkFindsHostedCluster(t *testing.T) {
-	secret := map[string]interface{}{
-		"apiVersion": "v1",
-		"kind":       "Secret",
-		"metadata": map[string]interface{}{
-			"name": "test-secret",
-		},
+// TestAuditDeletingCluster verifies a HostedCluster that is being deleted is categorized as deleting
+// whatever its annotations, with its deletion timestamp, and is not reported as ready for migration.
+func TestAuditDeletingCluster(t *testing.T) {
+	deletedAt := metav1.NewTime(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	deleting := newTestHostedCluster("a1", nil)
+	deleting.DeletionTimestamp = &deletedAt
+	deleting.Finalizers = []string{"hypershift.openshift.io/finalizer"}
+	ready := newTestHostedCluster("b2", nil)
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).
+		WithObjects(newTestNamespace(deleting.Namespace), deleting, newTestNamespace(ready.Namespace), ready).Build()
+	a := &auditOpts{environment: "production", mgmtClient: mgmtClient}
+
+	namespaces, err := a.listOcmNamespaces(context.Background())
+	if err != nil {
+		t.Fatal(err)
 	}
-	secretJSON, _ := json.Marshal(secret)
-
-	hc := map[string]interface{}{
-		"apiVersion": "hypershift.openshift.io/v1beta1",
-		"kind":       "HostedCluster",
-		"metadata": map[string]interface{}{
-			"name":        "test-cluster",
-			"annotations": map[string]interface{}{},
-		},
+	results, _ := a.auditNamespaces(context.Background(), namespaces)
+	if len(results.Deleting) != 1 || results.Deleting[0].ClusterID != "a1" || results.Deleting[0].DeletionTimestamp != "2026-03-01T09:30:00Z" {
+		t.Errorf("Expected a1 to be deleting with its deletion timestamp, got %+v", results.Deleting)
+	}
+	if len(results.ReadyForMigration) != 1 || results.ReadyForMigration[0].ClusterID != "b2" {
+		t.Errorf("Expected only b2 to be ready for migration, got %+v", results.ReadyForMigration)
+	}
+	if results.TotalScanned != 2 {
+		t.Errorf("Expected 2 clusters scanned, got %d", results.TotalScanned)
 	}
-	hcJSON, _ := json.Marshal(hc)
 
-	cert := map[string]interface{}{
-		"apiVersion": "cert-manager.io/v1",
-		"kind":       "Certificate",
-		"metadata": map[string]interface{}{
-			"name": "test-cert",
-		},
+	configured := deleting.DeepCopy()
+	configured.Annotations = map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology}
+	if category := a.categorizeCluster(configured); category != "deleting" {
+		t.Errorf("categorizeCluster() = %s, want deleting", category)
 	}
-	certJSON, _ := json.Marshal(cert)
+}
 
-	mw := &workv1.ManifestWork{
-		Spec: workv1.ManifestWorkSpec{
-			Workload: workv1.ManifestsTemplate{
-				Manifests: []workv1.Manifest{
-					{RawExtension: runtime.RawExtension{Raw: secretJSON}},
-					{RawExtension: runtime.RawExtension{Raw: hcJSON}},
-					{RawExtension: runtime.RawExtension{Raw: certJSON}},
-				},
+// TestClusterTableRow verifies the wide output adds annotation and availability columns.
+func TestClusterTableRow(t *testing.T) {
+	hc := &hypershiftv1beta1.HostedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+		},
+		Status: hypershiftv1beta1.HostedClusterStatus{
+			Conditions: []metav1.Condition{
+				{Type: "Degraded", Status: metav1.ConditionFalse},
+				{Type: "Available", Status: metav1.ConditionTrue},
 			},
 		},
 	}
-
-	foundIndex := -1
-	for i, manifest := range mw.Spec.Workload.Manifests {
-		var manifestData map[string]interface{}
-		if err := json.Unmarshal(manifest.Raw, &manifestData); err != nil {
-			continue
-		}
-
-		kind, _ := manifestData["kind"].(string)
-		if kind == "HostedCluster" {
-			foundIndex = i
-			break
-		}
+	info := hostedClusterAuditInfo{
+		ClusterID:   "cluster-1",
+		ClusterName: "one",
+		Namespace:   "ocm-production-cluster-1",
+		CurrentSize: "large",
+		Annotations: hc.Annotations,
+		Available:   hostedClusterAvailable(hc),
+		Conditions:  summarizeConditions(hc),
+
+		Subcategory: subcategoryOf(nil, hc.Annotations),
+
+		OpenShiftVersion: "4.16.10",
+		ChannelGroup:     "stable",
 	}
 
-	if foundIndex != 1 {
-		t.Errorf("Expected to find HostedCluster at index 1, found at %d", foundIndex)
+	text := (&auditOpts{output: "text"}).clusterTableRow(info)
+	if len(text) != 4 {
+		t.Errorf("Expected 4 columns in text output, got %v", text)
 	}
 
-	var hcData map[string]interface{}
-	if err := json.Unmarshal(mw.Spec.Workload.Manifests[foundIndex].Raw, &hcData); err != nil {
-		t.Fatalf("Failed to unmarshal HostedCluster: %v", err)
+	wide := (&auditOpts{output: "wide"}).clusterTableRow(info)
+	expected := []string{"cluster-1", "one", "ocm-production-cluster-1", "large",
+		"dedicated-request-serving-components", "true", "<unset>", "True", "False", "<unset>", "4.16.10", "stable", "configured"}
+	if len(wide) != len(expected) {
+		t.Fatalf("Expected %d columns in wide output, got %v", len(expected), wide)
 	}
-
-	metadata := hcData["metadata"].(map[string]interface{})
-	annotations, ok := metadata["annotations"].(map[string]interface{})
-	if !ok {
-		annotations = make(map[string]interface{})
-		metadata["annotations"] = annotations
+	for i := range expected {
+		if wide[i] != expected[i] {
+			t.Errorf("Column %d = %s, want %s", i, wide[i], expected[i])
+		}
+	}
+	if header := (&auditOpts{output: "wide"}).clusterTableHeader(); len(header) != len(expected) {
+		t.Errorf("Wide header has %d columns, want %d", len(header), len(expected))
 	}
 
-	annotations["test-key"] = "test-value"
-
-	if annotations["test-key"] != "test-value" {
-		t.Errorf("Failed to modify HostedCluster annotations")
+	if available := hostedClusterAvailable(&hypershiftv1beta1.HostedCluster{}); available != "" {
+		t.Errorf("Expected empty availability without conditions, got %s", available)
 	}
 }
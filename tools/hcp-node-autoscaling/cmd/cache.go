@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const defaultCacheTTL = time.Hour
+
+// ocmCache is an on-disk cache of OCM cluster lookups shared between runs. Entries older than ttl are
+// ignored. A nil *ocmCache is valid and always queries OCM.
+type ocmCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cacheEntry is a cached value with the time it was stored.
+type cacheEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// defaultCacheDir returns the directory OCM lookups are cached in.
+func defaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "hcp-node-autoscaling", "cache"), nil
+}
+
+// newOCMCache returns the cache for a run, or nil when caching is disabled by --no-cache or a zero TTL.
+func newOCMCache(noCache bool, ttl time.Duration) (*ocmCache, error) {
+	if ttl < 0 {
+		return nil, fmt.Errorf("invalid cache TTL %v: must not be negative", ttl)
+	}
+	if noCache || ttl == 0 {
+		return nil, nil
+	}
+
+	dir, err := defaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &ocmCache{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// path returns the cache file for a key.
+func (c *ocmCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached value for key if it exists and has not expired.
+func (c *ocmCache) get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if c.now().Sub(entry.StoredAt) > c.ttl {
+		return nil, false
+	}
+
+	slog.Debug("Using cached OCM lookup", "key", key, "storedAt", entry.StoredAt)
+	return entry.Value, true
+}
+
+// put stores a value for key. Failing to write the cache is logged and otherwise ignored.
+func (c *ocmCache) put(key string, value []byte) {
+	data, err := json.Marshal(cacheEntry{StoredAt: c.now().UTC(), Value: value})
+	if err == nil {
+		err = os.MkdirAll(c.dir, 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(c.path(key), data, 0o600)
+	}
+	if err != nil {
+		slog.Warn("Failed to write OCM cache", "key", key, "error", err)
+	}
+}
+
+// getCluster returns the OCM cluster for a name, internal ID or external ID.
+func (c *ocmCache) getCluster(conn *sdk.Connection, key string) (*cmv1.Cluster, error) {
+	if c == nil {
+		return utils.GetCluster(conn, key)
+	}
+
+	if data, ok := c.get("cluster:" + key); ok {
+		if cluster, err := cmv1.UnmarshalCluster(data); err == nil {
+			return cluster, nil
+		}
+	}
+
+	cluster, err := utils.GetCluster(conn, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := cmv1.MarshalCluster(cluster, &buf); err == nil {
+		c.put("cluster:"+key, buf.Bytes())
+		if cluster.ID() != key {
+			c.put("cluster:"+cluster.ID(), buf.Bytes())
+		}
+	}
+	return cluster, nil
+}
+
+// isManagementCluster reports whether the cluster with the given internal ID is a management cluster.
+func (c *ocmCache) isManagementCluster(clusterID string) (bool, error) {
+	if c == nil {
+		return utils.IsManagementCluster(clusterID)
+	}
+
+	key := "management-cluster:" + clusterID
+	if data, ok := c.get(key); ok {
+		var isMC bool
+		if err := json.Unmarshal(data, &isMC); err == nil {
+			return isMC, nil
+		}
+	}
+
+	isMC, err := utils.IsManagementCluster(clusterID)
+	if err != nil {
+		return false, err
+	}
+	data, _ := json.Marshal(isMC)
+	c.put(key, data)
+	return isMC, nil
+}
+
+// parentServiceCluster returns the cached name of a management cluster's parent service cluster, looking
+// it up with lookup on a cache miss.
+func (c *ocmCache) parentServiceCluster(mgmtClusterName string, lookup func() (string, error)) (string, error) {
+	if c == nil {
+		return lookup()
+	}
+
+	key := "parent-service-cluster:" + mgmtClusterName
+	if data, ok := c.get(key); ok {
+		var name string
+		if err := json.Unmarshal(data, &name); err == nil && name != "" {
+			return name, nil
+		}
+	}
+
+	name, err := lookup()
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(name)
+	c.put(key, data)
+	return name, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// TestNewOCMCache verifies the cache is disabled by --no-cache or a zero TTL and rejects a negative TTL.
+func TestNewOCMCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if c, err := newOCMCache(true, time.Hour); err != nil || c != nil {
+		t.Errorf("Expected no cache with --no-cache, got %v, %v", c, err)
+	}
+	if c, err := newOCMCache(false, 0); err != nil || c != nil {
+		t.Errorf("Expected no cache with a zero TTL, got %v, %v", c, err)
+	}
+	if _, err := newOCMCache(false, -time.Minute); err == nil {
+		t.Error("Expected error for a negative TTL")
+	}
+	if c, err := newOCMCache(false, time.Hour); err != nil || c == nil {
+		t.Errorf("Expected cache, got %v, %v", c, err)
+	}
+}
+
+// TestOCMCacheExpiry verifies cached values are returned until the TTL elapses.
+func TestOCMCacheExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC)
+	c := &ocmCache{dir: t.TempDir(), ttl: time.Hour, now: func() time.Time { return now }}
+
+	if _, ok := c.get("missing"); ok {
+		t.Error("Expected miss for a key that was never stored")
+	}
+
+	c.put("key", []byte(`"value"`))
+	if data, ok := c.get("key"); !ok || string(data) != `"value"` {
+		t.Errorf("Expected cached value, got %s, %v", data, ok)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.get("key"); ok {
+		t.Error("Expected miss after the TTL elapsed")
+	}
+}
+
+// TestOCMCacheGetCluster verifies a cached cluster is returned without querying OCM.
+func TestOCMCacheGetCluster(t *testing.T) {
+	c := &ocmCache{dir: t.TempDir(), ttl: time.Hour, now: time.Now}
+
+	cluster, err := cmv1.NewCluster().ID("mc-id").Name("mgmt-cluster").Build()
+	if err != nil {
+		t.Fatalf("Failed to build cluster: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := cmv1.MarshalCluster(cluster, &buf); err != nil {
+		t.Fatalf("Failed to marshal cluster: %v", err)
+	}
+	c.put("cluster:mgmt-cluster", buf.Bytes())
+
+	result, err := c.getCluster(nil, "mgmt-cluster")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.ID() != "mc-id" || result.Name() != "mgmt-cluster" {
+		t.Errorf("Unexpected cached cluster: %s/%s", result.ID(), result.Name())
+	}
+}
+
+// TestOCMCacheParentServiceCluster verifies the parent service cluster is looked up once and errors are not cached.
+func TestOCMCacheParentServiceCluster(t *testing.T) {
+	c := &ocmCache{dir: t.TempDir(), ttl: time.Hour, now: time.Now}
+
+	if _, err := c.parentServiceCluster("mc1", func() (string, error) {
+		return "", errors.New("fleet manager unavailable")
+	}); err == nil {
+		t.Fatal("Expected lookup error")
+	}
+
+	lookups := 0
+	lookup := func() (string, error) {
+		lookups++
+		return "svc1", nil
+	}
+	for i := 0; i < 2; i++ {
+		name, err := c.parentServiceCluster("mc1", lookup)
+		if err != nil || name != "svc1" {
+			t.Fatalf("parentServiceCluster() = %q, %v", name, err)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected 1 lookup, got %d", lookups)
+	}
+
+	var disabled *ocmCache
+	if _, err := disabled.parentServiceCluster("mc1", lookup); err != nil || lookups != 2 {
+		t.Errorf("Expected a nil cache to always look up, got %d lookups, %v", lookups, err)
+	}
+}
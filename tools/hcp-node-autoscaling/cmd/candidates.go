@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// candidateRow is a cluster listed in a --candidates-file. Namespace and ClusterName are optional and,
+// when set, must match the live HostedCluster. Annotations optionally override the values the migration
+// profile sets for the cluster.
+type candidateRow struct {
+	Line        int               `json:"-"`
+	ClusterID   string            `json:"cluster_id"`
+	Namespace   string            `json:"namespace,omitempty"`
+	ClusterName string            `json:"cluster_name,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// rejectedCandidate is a --candidates-file row that failed validation against the management cluster.
+type rejectedCandidate struct {
+	row    candidateRow
+	reason string
+}
+
+// loadCandidatesFile reads the clusters to migrate from a CSV or JSON file produced by external tooling.
+// Files ending in .json hold an array of objects; other files are CSV with a header row naming the
+// cluster_id column and, optionally, namespace and cluster_name columns and a column per overridden
+// annotation.
+func loadCandidatesFile(path string) ([]candidateRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read candidates file: %v", err)
+	}
+
+	var rows []candidateRow
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse candidates file %s: %v", path, err)
+		}
+		for i := range rows {
+			rows[i].Line = i + 1
+		}
+	} else {
+		rows, err = parseCandidatesCSV(strings.NewReader(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse candidates file %s: %v", path, err)
+		}
+	}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		if row.ClusterID == "" {
+			return nil, fmt.Errorf("candidates file %s: entry %d has no cluster_id", path, row.Line)
+		}
+		if line, ok := seen[row.ClusterID]; ok {
+			return nil, fmt.Errorf("candidates file %s: cluster %s is listed in entries %d and %d", path, row.ClusterID, line, row.Line)
+		}
+		if err := validateAnnotationOverrides(row.Annotations); err != nil {
+			return nil, fmt.Errorf("candidates file %s: entry %d: %v", path, row.Line, err)
+		}
+		seen[row.ClusterID] = row.Line
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("candidates file %s lists no clusters", path)
+	}
+	return rows, nil
+}
+
+// parseCandidatesCSV reads candidate rows from CSV with a header row. Columns named after an annotation,
+// e.g. hypershift.openshift.io/topology, hold the row's value of the annotation, and an empty cell keeps the
+// profile's value. Other unknown columns are ignored, so an audit CSV report can be used as is.
+func parseCandidatesCSV(r io.Reader) ([]candidateRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["cluster_id"]; !ok {
+		return nil, fmt.Errorf("header row has no cluster_id column")
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var annotationColumns []string
+	for name := range columns {
+		if isAnnotationColumn(name) {
+			annotationColumns = append(annotationColumns, name)
+		}
+	}
+
+	var rows []candidateRow
+	for i, record := range records[1:] {
+		row := candidateRow{
+			Line:        i + 2,
+			ClusterID:   field(record, "cluster_id"),
+			Namespace:   field(record, "namespace"),
+			ClusterName: field(record, "cluster_name"),
+		}
+		for _, name := range annotationColumns {
+			if value := field(record, name); value != "" {
+				if row.Annotations == nil {
+					row.Annotations = map[string]string{}
+				}
+				row.Annotations[name] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// getCandidatesFromFile re-validates each --candidates-file row against the live management cluster. Rows
+// whose HostedCluster is missing, does not match the expected namespace or name, is outside the selected
+// environment, is not ready for migration with the row's annotation values or overrides an annotation --set
+// leaves alone are returned as rejected. The annotation values of the candidates are kept for their patch.
+func (m *migrateOpts) getCandidatesFromFile(ctx context.Context) ([]hostedClusterAuditInfo, []rejectedCandidate, error) {
+	pattern, err := m.listing.namespaceMatcher(m.environment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.Info("Validating clusters from candidates file", "file", m.candidatesFile, "count", len(m.candidateRows))
+
+	var candidates []hostedClusterAuditInfo
+	var rejected []rejectedCandidate
+	for _, row := range m.candidateRows {
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("interrupted while validating candidates file: %v", ctx.Err())
+		}
+		reject := func(format string, args ...interface{}) {
+			rejected = append(rejected, rejectedCandidate{row: row, reason: fmt.Sprintf(format, args...)})
+		}
+		if reason := m.overridePartError(row.Annotations); reason != "" {
+			reject("%s", reason)
+			continue
+		}
+
+		hostedClusters := &hypershiftv1beta1.HostedClusterList{}
+		if err := m.mgmtClient.List(ctx, hostedClusters, client.MatchingLabels{"api.openshift.com/id": row.ClusterID}); err != nil {
+			reject("failed to list HostedClusters: %v", err)
+			continue
+		}
+		if len(hostedClusters.Items) != 1 {
+			reject("found %d HostedClusters with this cluster ID on management cluster %s", len(hostedClusters.Items), m.mgmtClusterID)
+			continue
+		}
+
+		hc := &hostedClusters.Items[0]
+		switch {
+		case row.Namespace != "" && row.Namespace != hc.Namespace:
+			reject("expected namespace %s, HostedCluster is in %s", row.Namespace, hc.Namespace)
+		case row.ClusterName != "" && row.ClusterName != hc.Name:
+			reject("expected name %s, HostedCluster is named %s", row.ClusterName, hc.Name)
+		case !pattern.MatchString(hc.Namespace):
+			reject("namespace %s is outside %s", hc.Namespace, m.listing.scope(m.environment))
+		default:
+			auditOpts := &auditOpts{mgmtClusterID: m.mgmtClusterID, mgmtClient: m.mgmtClient, profile: m.profile.withOverrides(row.Annotations),
+				includePaused: m.includePaused, timeouts: m.timeouts}
+			if category := auditOpts.categorizeCluster(hc); category != "ready-for-migration" {
+				reject("category is %s, not ready-for-migration", category)
+				continue
+			}
+			if len(row.Annotations) > 0 {
+				if m.overrides == nil {
+					m.overrides = map[string]map[string]string{}
+				}
+				m.overrides[row.ClusterID] = row.Annotations
+			}
+			candidates = append(candidates, hostedClusterAuditInfo{
+				ClusterID:   row.ClusterID,
+				ClusterName: hc.Name,
+				Namespace:   hc.Namespace,
+				CurrentSize: hc.Labels["hypershift.openshift.io/hosted-cluster-size"],
+				Category:    "ready-for-migration",
+				Labels:      hc.Labels,
+				Annotations: hc.Annotations,
+
+				OpenShiftVersion: hostedClusterVersion(hc),
+				ChannelGroup:     channelGroup(hc.Spec.Channel),
+			})
+		}
+	}
+
+	return candidates, rejected, nil
+}
+
+// displayRejectedCandidates prints the --candidates-file rows that failed validation.
+func displayRejectedCandidates(w io.Writer, path string, rejected []rejectedCandidate) {
+	if len(rejected) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Skipped: Failed Validation in %s (%d) ===\n\n", path, len(rejected))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"ENTRY", "CLUSTER ID", "REASON"})
+	for _, r := range rejected {
+		p.AddRow([]string{fmt.Sprint(r.row.Line), r.row.ClusterID, r.reason})
+	}
+	p.Flush()
+}
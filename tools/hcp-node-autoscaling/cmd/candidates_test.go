@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestLoadCandidatesFile verifies CSV and JSON candidate files, with their annotation values, are parsed and
+// malformed ones are rejected.
+func TestLoadCandidatesFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		expected    []candidateRow
+		expectError string
+	}{
+		{
+			name:     "csv with cluster IDs only",
+			file:     "clusters.csv",
+			content:  "cluster_id\na1\nb2\n",
+			expected: []candidateRow{{Line: 2, ClusterID: "a1"}, {Line: 3, ClusterID: "b2"}},
+		},
+		{
+			name:    "csv with expected namespace and name in any order",
+			file:    "clusters.csv",
+			content: "namespace,cluster_id,cluster_name\nocm-production-a1,a1,cluster-a1\n,b2,\n",
+			expected: []candidateRow{
+				{Line: 2, ClusterID: "a1", Namespace: "ocm-production-a1", ClusterName: "cluster-a1"},
+				{Line: 3, ClusterID: "b2"},
+			},
+		},
+		{
+			name:     "audit csv report",
+			file:     "audit.csv",
+			content:  "cluster_id,cluster_name,namespace,current_size,category\na1,cluster-a1,ocm-production-a1,m5xl,ready-for-migration\n",
+			expected: []candidateRow{{Line: 2, ClusterID: "a1", Namespace: "ocm-production-a1", ClusterName: "cluster-a1"}},
+		},
+		{
+			name:     "json",
+			file:     "clusters.json",
+			content:  `[{"cluster_id": "a1", "namespace": "ocm-production-a1"}, {"cluster_id": "b2", "cluster_name": "cluster-b2"}]`,
+			expected: []candidateRow{{Line: 1, ClusterID: "a1", Namespace: "ocm-production-a1"}, {Line: 2, ClusterID: "b2", ClusterName: "cluster-b2"}},
+		},
+		{
+			name:    "csv with a topology column",
+			file:    "clusters.csv",
+			content: "cluster_id,hypershift.openshift.io/topology\na1,shared\nb2,\n",
+			expected: []candidateRow{
+				{Line: 2, ClusterID: "a1", Annotations: map[string]string{topologyAnnotation: "shared"}},
+				{Line: 3, ClusterID: "b2"},
+			},
+		},
+		{
+			name:     "json with annotations",
+			file:     "clusters.json",
+			content:  `[{"cluster_id": "a1", "annotations": {"hypershift.openshift.io/topology": "dedicated"}}]`,
+			expected: []candidateRow{{Line: 1, ClusterID: "a1", Annotations: map[string]string{topologyAnnotation: "dedicated"}}},
+		},
+		{
+			name:        "annotation not on the allowlist",
+			file:        "clusters.csv",
+			content:     "cluster_id,hypershift.openshift.io/cluster-size-override\na1,large\n",
+			expectError: "entry 2: annotation hypershift.openshift.io/cluster-size-override cannot be overridden",
+		},
+		{
+			name:        "value not on the allowlist",
+			file:        "clusters.json",
+			content:     `[{"cluster_id": "a1", "annotations": {"hypershift.openshift.io/topology": "spread"}}]`,
+			expectError: `entry 1: invalid hypershift.openshift.io/topology "spread": must be one of`,
+		},
+		{name: "csv without cluster_id column", file: "clusters.csv", content: "id\na1\n", expectError: "no cluster_id column"},
+		{name: "empty cluster ID", file: "clusters.csv", content: "cluster_id,namespace\n,ocm-production-a1\n", expectError: "entry 2 has no cluster_id"},
+		{name: "duplicate cluster ID", file: "clusters.csv", content: "cluster_id\na1\na1\n", expectError: "listed in entries 2 and 3"},
+		{name: "no clusters", file: "clusters.json", content: "[]", expectError: "lists no clusters"},
+		{name: "invalid json", file: "clusters.json", content: "{", expectError: "failed to parse candidates file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write candidates file: %v", err)
+			}
+
+			rows, err := loadCandidatesFile(path)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rows) != len(tt.expected) {
+				t.Fatalf("Expected %d rows, got %+v", len(tt.expected), rows)
+			}
+			for i := range rows {
+				if !reflect.DeepEqual(rows[i], tt.expected[i]) {
+					t.Errorf("Row %d = %+v, want %+v", i, rows[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGetCandidatesFromFile verifies each row is re-validated against the live HostedCluster and rows that
+// fail validation are reported with the reason.
+func TestGetCandidatesFromFile(t *testing.T) {
+	ready := newTestHostedCluster("a1", nil)
+	needsRemoval := newTestHostedCluster("b2", map[string]string{"hypershift.openshift.io/cluster-size-override": "large"})
+	renamed := newTestHostedCluster("c3", nil)
+	staging := newTestHostedCluster("d4", nil)
+	staging.Namespace = "ocm-staging-d4"
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(ready, needsRemoval, renamed, staging).Build()
+	m := &migrateOpts{
+		mgmtClusterID:  "mgmt-1",
+		mgmtClient:     mgmtClient,
+		environment:    "production",
+		profile:        defaultProfile,
+		candidatesFile: "clusters.csv",
+		candidateRows: []candidateRow{
+			{Line: 2, ClusterID: "a1", Namespace: ready.Namespace, ClusterName: ready.Name},
+			{Line: 3, ClusterID: "b2"},
+			{Line: 4, ClusterID: "c3", ClusterName: "old-name"},
+			{Line: 5, ClusterID: "d4"},
+			{Line: 6, ClusterID: "e5"},
+		},
+	}
+
+	candidates, rejected, err := m.getCandidatesFromFile(context.Background())
+	if err != nil {
+		t.Fatalf("getCandidatesFromFile() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ClusterID != "a1" || candidates[0].Namespace != ready.Namespace {
+		t.Errorf("Expected only a1 to be a candidate, got %+v", candidates)
+	}
+
+	expected := map[string]string{
+		"b2": "category is needs-removal",
+		"c3": "expected name old-name",
+		"d4": "outside the production environment",
+		"e5": "found 0 HostedClusters",
+	}
+	if len(rejected) != len(expected) {
+		t.Fatalf("Expected %d rejected rows, got %+v", len(expected), rejected)
+	}
+	for _, r := range rejected {
+		if !strings.Contains(r.reason, expected[r.row.ClusterID]) {
+			t.Errorf("Rejection of %s = %q, want it to contain %q", r.row.ClusterID, r.reason, expected[r.row.ClusterID])
+		}
+	}
+
+	var buf bytes.Buffer
+	displayRejectedCandidates(&buf, m.candidatesFile, rejected)
+	if !strings.Contains(buf.String(), "Failed Validation in clusters.csv (4)") {
+		t.Errorf("Expected rejected rows to be reported, got %q", buf.String())
+	}
+}
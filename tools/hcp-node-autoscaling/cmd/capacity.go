@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultRequestServingNodesPerCluster is the number of dedicated request serving nodes a cluster is assumed
+// to take when no cluster on the management cluster is scheduled on dedicated nodes yet: one per zone of a
+// pair.
+const defaultRequestServingNodesPerCluster = 2
+
+// clusterCapacityEstimate is how a candidate is scheduled today and the size class it is expected to move to.
+type clusterCapacityEstimate struct {
+	// currentSize is the cluster's hosted-cluster-size label.
+	currentSize string
+	// targetSize is the size class expected once the migration applies, or "" when it cannot be predicted.
+	targetSize string
+	// nodeSize and nodes are the size class and number of the request serving nodes the cluster is
+	// scheduled on today; nodes is 0 when it is not on dedicated nodes.
+	nodeSize string
+	nodes    int
+	// dedicated reports whether the migration puts the cluster on dedicated request serving nodes.
+	dedicated bool
+}
+
+// sizeCapacityDelta is the estimated change of one size class on the management cluster.
+type sizeCapacityDelta struct {
+	SizeClass string
+	// Clusters is the number of candidates of this current size class.
+	Clusters int
+	// MovingOut and MovingIn are the candidates expected to leave and join the size class.
+	MovingOut int
+	MovingIn  int
+	// NodeDelta is the estimated change in request serving nodes of the size class.
+	NodeDelta int
+	// Unknown is the number of candidates of this current size class whose target size class is unknown.
+	Unknown int
+}
+
+// summarizeCapacityDelta aggregates the candidates' estimates per size class. A cluster moving size class
+// frees the nodes it is scheduled on today and takes as many of its new size class; a cluster newly on
+// dedicated nodes takes perCluster nodes. Clusters whose target size class is unknown only count towards
+// their current size class, as Unknown. Size classes are sorted by name, with the unknown size last.
+func summarizeCapacityDelta(estimates []clusterCapacityEstimate, perCluster int) []sizeCapacityDelta {
+	bySize := map[string]*sizeCapacityDelta{}
+	get := func(size string) *sizeCapacityDelta {
+		if bySize[size] == nil {
+			bySize[size] = &sizeCapacityDelta{SizeClass: size}
+		}
+		return bySize[size]
+	}
+
+	for _, e := range estimates {
+		get(e.currentSize).Clusters++
+		if e.targetSize == "" {
+			get(e.currentSize).Unknown++
+			continue
+		}
+		from := e.currentSize
+		if e.nodes > 0 && e.nodeSize != "" {
+			from = e.nodeSize
+		}
+		if from != e.targetSize {
+			get(from).MovingOut++
+			get(e.targetSize).MovingIn++
+			if e.nodes > 0 {
+				get(from).NodeDelta -= e.nodes
+				get(e.targetSize).NodeDelta += e.nodes
+			}
+		}
+		if e.nodes == 0 && e.dedicated {
+			get(e.targetSize).NodeDelta += perCluster
+		}
+	}
+
+	deltas := make([]sizeCapacityDelta, 0, len(bySize))
+	for _, d := range bySize {
+		deltas = append(deltas, *d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if (deltas[i].SizeClass == "") != (deltas[j].SizeClass == "") {
+			return deltas[j].SizeClass == ""
+		}
+		return deltas[i].SizeClass < deltas[j].SizeClass
+	})
+	return deltas
+}
+
+// requestServingNodes returns the size class and number of the dedicated request serving nodes each hosted
+// control plane namespace is scheduled on, and the most common number of nodes per cluster.
+func requestServingNodes(nodes []corev1.Node) (map[string]int, map[string]string, int) {
+	counts := map[string]int{}
+	sizes := map[string]string{}
+	for _, node := range nodes {
+		namespace := node.Labels[hypershiftv1beta1.HostedClusterLabel]
+		if namespace == "" {
+			continue
+		}
+		counts[namespace]++
+		sizes[namespace] = node.Labels[hypershiftv1beta1.NodeSizeLabel]
+	}
+
+	frequency := map[int]int{}
+	perCluster := defaultRequestServingNodesPerCluster
+	for _, n := range counts {
+		frequency[n]++
+		if frequency[n] > frequency[perCluster] || frequency[n] == frequency[perCluster] && n < perCluster {
+			perCluster = n
+		}
+	}
+	return counts, sizes, perCluster
+}
+
+// estimateCapacity estimates the change of each size class of request serving nodes the candidates' migration
+// causes on the management cluster. The target size class of each candidate is predicted as with
+// audit --simulate-sizing, and where it is scheduled today is read from the labels of the request serving nodes.
+func (m *migrateOpts) estimateCapacity(ctx context.Context, candidates []hostedClusterAuditInfo) ([]sizeCapacityDelta, int, error) {
+	sizing := &auditOpts{mgmtClient: m.mgmtClient, simulateSizing: true}
+	sizeClasses, err := sizing.loadSizeClasses(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	sizing.sizeClasses = sizeClasses
+
+	nodeList := &corev1.NodeList{}
+	if err := m.mgmtClient.List(ctx, nodeList, client.MatchingLabels{hypershiftv1beta1.RequestServingComponentLabel: "true"}); err != nil {
+		return nil, 0, fmt.Errorf("failed to list request serving nodes: %v", err)
+	}
+	nodeCounts, nodeSizes, perCluster := requestServingNodes(nodeList.Items)
+
+	estimates := make([]clusterCapacityEstimate, 0, len(candidates))
+	for _, c := range candidates {
+		controlPlaneNamespace := fmt.Sprintf("%s-%s", c.Namespace, c.ClusterName)
+		profile := m.clusterProfile(c.ClusterID).orDefault()
+		e := clusterCapacityEstimate{
+			currentSize: c.CurrentSize,
+			nodeSize:    nodeSizes[controlPlaneNamespace],
+			nodes:       nodeCounts[controlPlaneNamespace],
+			dedicated:   profile.Ensure[topologyAnnotation] == dedicatedTopology,
+		}
+		if profile.Ensure[autoscalingAnnotation] != "true" {
+			e.targetSize = e.currentSize
+		} else if hc, err := m.getHostedClusterFromMgmt(ctx, c.Namespace, c.ClusterName); err != nil {
+			slog.Warn("Capacity estimate will not include cluster", "clusterID", c.ClusterID, "error", err)
+		} else if err := sizing.analyzeSizing(ctx, hc, &c); err != nil {
+			slog.Warn("Capacity estimate will not include cluster", "clusterID", c.ClusterID, "error", err)
+		} else {
+			e.targetSize = c.SimulatedSizeClass
+		}
+		estimates = append(estimates, e)
+	}
+	return summarizeCapacityDelta(estimates, perCluster), perCluster, nil
+}
+
+// displayCapacityDelta prints, for each size class, how many candidates move to autoscaling and the estimated
+// change in request serving nodes on the management cluster, so capacity can be planned before migrating.
+func (m *migrateOpts) displayCapacityDelta(ctx context.Context, w io.Writer, candidates []hostedClusterAuditInfo) {
+	fmt.Fprintf(w, "=== [DRY RUN] Estimated Capacity Change by Size Class ===\n\n")
+	deltas, perCluster, err := m.estimateCapacity(ctx, candidates)
+	if err != nil {
+		fmt.Fprintf(w, "Capacity estimate unavailable: %v\n\n", err)
+		return
+	}
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"SIZE CLASS", "CLUSTERS", "MOVING OUT", "MOVING IN", "NODE DELTA"})
+	total, unknown := 0, 0
+	for _, d := range deltas {
+		p.AddRow([]string{driftValue(d.SizeClass), strconv.Itoa(d.Clusters), strconv.Itoa(d.MovingOut),
+			strconv.Itoa(d.MovingIn), fmt.Sprintf("%+d", d.NodeDelta)})
+		total += d.NodeDelta
+		unknown += d.Unknown
+	}
+	p.Flush()
+	fmt.Fprintf(w, "\nEstimated request serving node change: %+d (%d nodes per cluster newly on dedicated nodes).\n", total, perCluster)
+	if unknown > 0 {
+		fmt.Fprintf(w, "%d clusters are not included: their target size class could not be predicted.\n", unknown)
+	}
+	fmt.Fprintln(w, "The target size classes are predicted from current worker counts and kube-apiserver memory; the autoscaler reacts to live usage.")
+	fmt.Fprintln(w)
+}
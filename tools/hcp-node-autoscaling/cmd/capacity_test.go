@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestSummarizeCapacityDelta verifies clusters moving size class move their nodes, clusters newly on
+// dedicated nodes add nodes to their target size class, and unknown targets are only counted.
+func TestSummarizeCapacityDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimates []clusterCapacityEstimate
+		expected  []sizeCapacityDelta
+	}{
+		{
+			name:      "moving size class on dedicated nodes",
+			estimates: []clusterCapacityEstimate{{currentSize: "small", targetSize: "large", nodeSize: "small", nodes: 2, dedicated: true}},
+			expected: []sizeCapacityDelta{
+				{SizeClass: "large", MovingIn: 1, NodeDelta: 2},
+				{SizeClass: "small", Clusters: 1, MovingOut: 1, NodeDelta: -2},
+			},
+		},
+		{
+			name:      "staying on dedicated nodes",
+			estimates: []clusterCapacityEstimate{{currentSize: "small", targetSize: "small", nodeSize: "small", nodes: 2, dedicated: true}},
+			expected:  []sizeCapacityDelta{{SizeClass: "small", Clusters: 1}},
+		},
+		{
+			name:      "newly on dedicated nodes",
+			estimates: []clusterCapacityEstimate{{currentSize: "small", targetSize: "medium", dedicated: true}},
+			expected: []sizeCapacityDelta{
+				{SizeClass: "medium", MovingIn: 1, NodeDelta: 3},
+				{SizeClass: "small", Clusters: 1, MovingOut: 1},
+			},
+		},
+		{
+			name:      "not on dedicated nodes",
+			estimates: []clusterCapacityEstimate{{currentSize: "small", targetSize: "small"}},
+			expected:  []sizeCapacityDelta{{SizeClass: "small", Clusters: 1}},
+		},
+		{
+			name: "unknown target and size",
+			estimates: []clusterCapacityEstimate{
+				{currentSize: "", targetSize: "small", dedicated: true},
+				{currentSize: "small", nodes: 2},
+			},
+			expected: []sizeCapacityDelta{
+				{SizeClass: "small", Clusters: 1, MovingIn: 1, NodeDelta: 3, Unknown: 1},
+				{SizeClass: "", Clusters: 1, MovingOut: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeCapacityDelta(tt.estimates, 3); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("summarizeCapacityDelta() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRequestServingNodes verifies nodes are counted per hosted control plane namespace and the most common
+// count is used for clusters not on dedicated nodes yet.
+func TestRequestServingNodes(t *testing.T) {
+	node := func(namespace, size string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			hypershiftv1beta1.HostedClusterLabel: namespace, hypershiftv1beta1.NodeSizeLabel: size}}}
+	}
+
+	counts, sizes, perCluster := requestServingNodes([]corev1.Node{
+		node("a", "small"), node("a", "small"), node("a", "small"),
+		node("b", "large"), node("b", "large"), node("b", "large"),
+		node("c", "small"), node("c", "small"),
+		node("", "small"),
+	})
+	if !reflect.DeepEqual(counts, map[string]int{"a": 3, "b": 3, "c": 2}) ||
+		!reflect.DeepEqual(sizes, map[string]string{"a": "small", "b": "large", "c": "small"}) || perCluster != 3 {
+		t.Errorf("requestServingNodes() = %v, %v, %d", counts, sizes, perCluster)
+	}
+
+	if _, _, perCluster := requestServingNodes(nil); perCluster != defaultRequestServingNodesPerCluster {
+		t.Errorf("Expected %d nodes per cluster without dedicated nodes, got %d", defaultRequestServingNodesPerCluster, perCluster)
+	}
+}
+
+// TestDisplayCapacityDelta verifies the dry run estimate reads the size classes, the candidates' worker
+// counts and the request serving nodes of the management cluster.
+func TestDisplayCapacityDelta(t *testing.T) {
+	s, err := scheme.NewScheme(scheme.HyperShift, scheme.Core, scheme.Scheduling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, small := newTestHostedCluster("a1", nil), newTestHostedCluster("b2", nil)
+	nodePool := func(hc *hypershiftv1beta1.HostedCluster, replicas int32) *hypershiftv1beta1.NodePool {
+		return &hypershiftv1beta1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: hc.Name + "-workers", Namespace: hc.Namespace},
+			Spec:       hypershiftv1beta1.NodePoolSpec{ClusterName: hc.Name},
+			Status:     hypershiftv1beta1.NodePoolStatus{Replicas: replicas},
+		}
+	}
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+			hypershiftv1beta1.RequestServingComponentLabel: "true",
+			hypershiftv1beta1.HostedClusterLabel:           "ocm-production-a1-cluster-a1",
+			hypershiftv1beta1.NodeSizeLabel:                "small",
+		}}}
+	}
+	sizing := &schedulingv1alpha1.ClusterSizingConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: schedulingv1alpha1.ClusterSizingConfigurationSpec{Sizes: []schedulingv1alpha1.SizeConfiguration{
+			{Name: "small", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 0, To: uint32Ptr(10)}},
+			{Name: "large", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 11}},
+		}},
+	}
+
+	m := &migrateOpts{
+		profile: topologyProfile,
+		mgmtClient: fake.NewClientBuilder().WithScheme(s).WithObjects(sizing, large, small,
+			nodePool(large, 20), nodePool(small, 3), node("rs-1"), node("rs-2")).Build(),
+	}
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "a1", ClusterName: large.Name, Namespace: large.Namespace, CurrentSize: "small"},
+		{ClusterID: "b2", ClusterName: small.Name, Namespace: small.Namespace, CurrentSize: "small"},
+	}
+
+	var buf bytes.Buffer
+	m.displayCapacityDelta(context.Background(), &buf, candidates)
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		"=== [DRY RUN] Estimated Capacity Change by Size Class ===",
+		"large 0 0 1 +2 small 2 1 0 +0",
+		"Estimated request serving node change: +2 (2 nodes per cluster newly on dedicated nodes).",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	(&migrateOpts{mgmtClient: fake.NewClientBuilder().WithScheme(s).Build()}).displayCapacityDelta(context.Background(), &buf, candidates)
+	if !strings.Contains(buf.String(), "Capacity estimate unavailable: failed to get ClusterSizingConfiguration") {
+		t.Errorf("Expected the estimate to be reported unavailable, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// writeChangeRecord writes the --change-record of a migrate run to path. Failing to write it does not
+// fail the run, since the clusters were already changed and the run history holds the same record.
+func writeChangeRecord(path string, runs []*mgmtClusterRun) {
+	if path == "" {
+		return
+	}
+	err := output.WriteFile(path, false, func(w io.Writer, _ bool) error {
+		return renderChangeRecord(w, runs)
+	})
+	if err != nil {
+		slog.Warn("Failed to write change record", "file", path, "error", err)
+		return
+	}
+	slog.Info("Wrote change record", "file", path)
+}
+
+// renderChangeRecord formats a markdown change record of a migrate run, ready to attach to the change
+// ticket: who ran it and when, the annotations changed on each cluster, the sync verification of the
+// migrated clusters and the clusters that failed or were not started.
+func renderChangeRecord(w io.Writer, runs []*mgmtClusterRun) error {
+	var first *runHistory
+	for _, r := range runs {
+		if r.opts.history != nil {
+			first = r.opts.history
+			break
+		}
+	}
+	if first == nil {
+		return fmt.Errorf("no clusters were migrated")
+	}
+
+	title := "Autoscaling Migration"
+	if first.Ticket != "" {
+		title = first.Ticket
+	}
+	fmt.Fprintf(w, "# Change Record: %s\n\n", markdownCell(title))
+	if first.RunID != "" {
+		fmt.Fprintf(w, "- Run ID: %s\n", markdownCell(first.RunID))
+	}
+	fmt.Fprintf(w, "- Operator: %s\n", markdownCell(first.Operator))
+	fmt.Fprintf(w, "- Reason: %s\n", markdownCell(first.ElevationReason))
+	fmt.Fprintf(w, "- Migration profile: %s\n", markdownCell(first.Profile))
+	fmt.Fprintf(w, "- Patch strategy: %s\n", first.PatchStrategy)
+	fmt.Fprintf(w, "- Started: %s\n", first.StartedAt)
+	if first.FinishedAt != "" {
+		fmt.Fprintf(w, "- Finished: %s\n", first.FinishedAt)
+	}
+
+	fmt.Fprint(w, "\n## Summary\n\n")
+	fmt.Fprintln(w, markdownRow([]string{"Management Cluster", "Service Cluster", "Candidates", "Migrated",
+		"Failed", "Aborted", "Skipped", "State Changed", "Interrupted", "Not Started"}))
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, r := range runs {
+		fmt.Fprintln(w, markdownRow(fleetSummaryRow(r)))
+	}
+
+	for _, r := range runs {
+		if r.opts.history == nil {
+			continue
+		}
+		fmt.Fprintf(w, "\n## Management Cluster %s (%s)\n", markdownCell(r.opts.mgmtClusterName), r.opts.mgmtClusterID)
+		writeChangeRecordChanges(w, r.opts.history.Changes)
+		writeChangeRecordVerification(w, r.results)
+		writeChangeRecordFailures(w, r.results, r.notStarted(), r.opts.skipped)
+	}
+	return nil
+}
+
+// writeChangeRecordChanges lists every annotation change recorded in the run history.
+func writeChangeRecordChanges(w io.Writer, changes []annotationChange) {
+	fmt.Fprint(w, "\n### Annotation Changes\n\n")
+	fmt.Fprintln(w, markdownRow([]string{"Cluster ID", "Cluster Name", "Annotation", "Before", "After", "Status", "Changed At"}))
+	fmt.Fprintln(w, "|"+strings.Repeat(" --- |", 7))
+	for _, c := range changes {
+		fmt.Fprintln(w, markdownRow([]string{c.ClusterID, c.ClusterName, c.Annotation,
+			driftValue(c.Before), driftValue(c.After), c.Status, c.ChangedAt}))
+	}
+}
+
+// writeChangeRecordVerification lists when each migrated cluster's annotations were verified on the
+// management cluster and how long the sync took.
+func writeChangeRecordVerification(w io.Writer, results []migrationResult) {
+	latency := summarizeSyncLatency(results)
+	if latency == nil {
+		return
+	}
+
+	fmt.Fprint(w, "\n### Verification\n\n")
+	fmt.Fprintf(w, "Annotations verified on the management cluster for %d clusters: sync p50 %s, p95 %s, max %s.\n\n",
+		latency.Count, latency.P50.Round(time.Second), latency.P95.Round(time.Second), latency.Max.Round(time.Second))
+	fmt.Fprintln(w, markdownRow([]string{"Cluster ID", "Cluster Name", "Verified At", "Sync Time", "Conflict Retries"}))
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: |")
+	for _, r := range results {
+		if r.Status != "success" {
+			continue
+		}
+		fmt.Fprintln(w, markdownRow([]string{r.ClusterID, r.ClusterName, r.VerifiedAt,
+			r.syncDuration().Round(time.Second).String(), fmt.Sprint(r.ConflictRetries)}))
+	}
+}
+
+// writeChangeRecordFailures lists the clusters that were not migrated, with the reason.
+func writeChangeRecordFailures(w io.Writer, results []migrationResult, notStarted []hostedClusterAuditInfo, skipped []skippedCluster) {
+	var rows [][]string
+	for _, s := range skipped {
+		rows = append(rows, []string{s.info.ClusterID, s.info.ClusterName, skippedStatus, s.reason})
+	}
+	for _, r := range results {
+		if r.Status != "success" {
+			rows = append(rows, []string{r.ClusterID, r.ClusterName, r.Status, r.Error})
+		}
+	}
+	for _, c := range notStarted {
+		rows = append(rows, []string{c.ClusterID, c.ClusterName, "not-started", "run was interrupted before the cluster was started"})
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprint(w, "\n### Not Migrated\n\n")
+	fmt.Fprintln(w, markdownRow([]string{"Cluster ID", "Cluster Name", "Status", "Reason"}))
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+	for _, row := range rows {
+		fmt.Fprintln(w, markdownRow(row))
+	}
+}
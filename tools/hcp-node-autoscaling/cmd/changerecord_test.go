@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteChangeRecord verifies the change record lists the changes, verification evidence and failures.
+func TestWriteChangeRecord(t *testing.T) {
+	dir := t.TempDir()
+	m := &migrateOpts{
+		serviceClusterID: "svc-123",
+		mgmtClusterID:    "mgmt-456",
+		mgmtClusterName:  "hs-mc-1",
+		patchStrategy:    "json-patch",
+		operator:         "jdoe",
+		ticket:           "OHSS-12345",
+		skipped:          []skippedCluster{{info: hostedClusterAuditInfo{ClusterID: "d4", ClusterName: "four"}, reason: "paused: pausedUntil is set"}},
+	}
+	m.history = m.newRunHistory(dir, time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC))
+
+	migrated := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: "one"}
+	failed := hostedClusterAuditInfo{ClusterID: "b2", ClusterName: "two"}
+	notStarted := hostedClusterAuditInfo{ClusterID: "c3", ClusterName: "three"}
+	results := []migrationResult{
+		{ClusterID: "a1", ClusterName: "one", Status: "success", VerifiedAt: "2026-01-27T10:01:00Z", SyncSeconds: 42},
+		{ClusterID: "b2", ClusterName: "two", Status: "failed", Error: "sync verification failed: timeout"},
+	}
+	for i, info := range []hostedClusterAuditInfo{migrated, failed} {
+		if err := m.history.record(info, results[i], false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := m.history.finish(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join(dir, "change.md")
+	writeChangeRecord(path, []*mgmtClusterRun{{opts: m, candidates: []hostedClusterAuditInfo{migrated, failed, notStarted}, results: results}})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Change record not written: %v", err)
+	}
+
+	record := string(data)
+	for _, expected := range []string{
+		"# Change Record: OHSS-12345",
+		"- Operator: jdoe",
+		"- Started: 2026-01-27T10:00:00Z",
+		"| hs-mc-1 | svc-123 | 4 | 1 | 1 | 0 | 1 | 0 | 0 | 1 |",
+		"## Management Cluster hs-mc-1 (mgmt-456)",
+		"| a1 | one | hypershift.openshift.io/resource-based-cp-auto-scaling | <unset> | true | success |",
+		"sync p50 42s, p95 42s, max 42s",
+		"| a1 | one | 2026-01-27T10:01:00Z | 42s | 0 |",
+		"| b2 | two | failed | sync verification failed: timeout |",
+		"| c3 | three | not-started |",
+		"| d4 | four | skipped | paused: pausedUntil is set |",
+	} {
+		if !strings.Contains(record, expected) {
+			t.Errorf("Expected change record to contain %q, got:\n%s", expected, record)
+		}
+	}
+
+	if err := renderChangeRecord(&strings.Builder{}, []*mgmtClusterRun{{opts: &migrateOpts{}}}); err == nil {
+		t.Error("Expected an error for a run that migrated no clusters")
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+)
+
+// nameFilterPrefix starts a --show-only filter listing the clusters whose name matches a glob pattern, e.g.
+// name=prod-*.
+const nameFilterPrefix = "name="
+
+// addClusterNamesFlag registers --cluster-names on a command that migrates clusters.
+func addClusterNamesFlag(cmd *cobra.Command, m *migrateOpts) {
+	cmd.Flags().StringSliceVar(&m.clusterNames, "cluster-names", nil,
+		"Comma-separated names of the hosted clusters to migrate, resolved to cluster IDs through OCM; other candidates are left alone")
+}
+
+// clusterNameLookup returns the hosted clusters in OCM whose name or display name is name.
+type clusterNameLookup func(name string) ([]*cmv1.Cluster, error)
+
+// ocmClusterNameLookup looks hosted clusters up by name or display name in OCM.
+func ocmClusterNameLookup(conn *sdk.Connection) clusterNameLookup {
+	return func(name string) ([]*cmv1.Cluster, error) {
+		quoted := strings.ReplaceAll(name, "'", "''")
+		resp, err := conn.ClustersMgmt().V1().Clusters().List().
+			Search(fmt.Sprintf("hypershift.enabled = 'true' and (name = '%s' or display_name = '%s')", quoted, quoted)).
+			Size(completionPageSize).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up hosted cluster %s in OCM: %v", name, err)
+		}
+		return resp.Items().Slice(), nil
+	}
+}
+
+// resolveClusterNames resolves --cluster-names to the IDs of the hosted clusters to migrate, mapped to the
+// name they were targeted by. A name matching no hosted cluster, or several, is an error, since migrating the
+// wrong cluster is worse than stopping.
+func resolveClusterNames(names []string, lookup clusterNameLookup) (map[string]string, error) {
+	targets := map[string]string{}
+	var problems []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		clusters, err := lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		switch len(clusters) {
+		case 0:
+			problems = append(problems, fmt.Sprintf("no hosted cluster is named %s", name))
+		case 1:
+			targets[clusters[0].ID()] = name
+			slog.Debug("Resolved cluster name", "name", name, "clusterID", clusters[0].ID())
+		default:
+			var matches []string
+			for _, c := range clusters {
+				matches = append(matches, fmt.Sprintf("%s (%s)", c.ID(), c.Name()))
+			}
+			slices.Sort(matches)
+			problems = append(problems, fmt.Sprintf("%s is ambiguous, it names %d hosted clusters: %s",
+				name, len(clusters), strings.Join(matches, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("failed to resolve --cluster-names: %s", strings.Join(problems, "; "))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("invalid cluster names %v: must name at least one hosted cluster", names)
+	}
+	return targets, nil
+}
+
+// filterTargeted keeps the candidates resolved from --cluster-names, and warns about the targeted clusters
+// that are not migration candidates, e.g. because they are already configured or on another management
+// cluster. Without --cluster-names all candidates are kept.
+func (m *migrateOpts) filterTargeted(w io.Writer, candidates []hostedClusterAuditInfo) []hostedClusterAuditInfo {
+	if m.targets == nil {
+		return candidates
+	}
+
+	found := map[string]bool{}
+	var targeted []hostedClusterAuditInfo
+	for _, c := range candidates {
+		if _, ok := m.targets[c.ClusterID]; ok {
+			found[c.ClusterID] = true
+			targeted = append(targeted, c)
+		}
+	}
+
+	var missing []string
+	for id, name := range m.targets {
+		if !found[id] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, id))
+		}
+	}
+	slices.Sort(missing)
+	for _, c := range missing {
+		fmt.Fprintf(w, "WARNING: %s is not a migration candidate on management cluster %s\n", c, m.mgmtClusterName)
+	}
+	slog.Info("Targeting clusters by name", "targeted", len(m.targets), "candidates", len(targeted))
+	return targeted
+}
+
+// showsAlreadyConfigured reports whether the already configured clusters are listed: without --show-only,
+// or with a filter that selects clusters across categories.
+func (a *auditOpts) showsAlreadyConfigured() bool {
+	return a.showOnly == "" || isSubcategory(a.showOnly) || isNameFilter(a.showOnly)
+}
+
+// isNameFilter reports whether a --show-only filter selects clusters by name.
+func isNameFilter(filter string) bool {
+	return strings.HasPrefix(filter, nameFilterPrefix)
+}
+
+// validateNameFilter checks the glob pattern of a name=<pattern> --show-only filter.
+func validateNameFilter(filter string) error {
+	pattern := strings.TrimPrefix(filter, nameFilterPrefix)
+	if pattern == "" {
+		return fmt.Errorf("invalid show-only filter '%s': must give a cluster name or glob pattern after %s", filter, nameFilterPrefix)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid show-only filter '%s': %v", filter, err)
+	}
+	return nil
+}
+
+// matchesNameFilter reports whether the name of a cluster matches the pattern of a name=<pattern> --show-only
+// filter.
+func matchesNameFilter(filter string, c hostedClusterAuditInfo) bool {
+	matched, _ := path.Match(strings.TrimPrefix(filter, nameFilterPrefix), c.ClusterName)
+	return matched
+}
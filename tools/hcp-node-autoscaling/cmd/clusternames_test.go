@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// TestResolveClusterNames verifies names resolve to the ID of the one hosted cluster they name, and that
+// unknown and ambiguous names are reported together.
+func TestResolveClusterNames(t *testing.T) {
+	build := func(id, name string) *cmv1.Cluster {
+		c, err := cmv1.NewCluster().ID(id).Name(name).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	clusters := map[string][]*cmv1.Cluster{
+		"prod-api":  {build("id-1", "prod-api")},
+		"Billing":   {build("id-2", "billing-prod")},
+		"staging":   {build("id-4", "staging"), build("id-3", "staging")},
+		"lookup-ko": nil,
+	}
+	lookup := func(name string) ([]*cmv1.Cluster, error) {
+		if name == "lookup-ko" {
+			return nil, errors.New("OCM unavailable")
+		}
+		return clusters[name], nil
+	}
+
+	tests := []struct {
+		name      string
+		names     []string
+		expected  map[string]string
+		expectErr string
+	}{
+		{
+			name:     "by name and display name",
+			names:    []string{"prod-api", " Billing"},
+			expected: map[string]string{"id-1": "prod-api", "id-2": "Billing"},
+		},
+		{
+			name:      "unknown and ambiguous",
+			names:     []string{"prod-api", "missing", "staging"},
+			expectErr: "no hosted cluster is named missing; staging is ambiguous, it names 2 hosted clusters: id-3 (staging), id-4 (staging)",
+		},
+		{
+			name:      "lookup error",
+			names:     []string{"lookup-ko"},
+			expectErr: "OCM unavailable",
+		},
+		{
+			name:      "only blanks",
+			names:     []string{" "},
+			expectErr: "must name at least one hosted cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := resolveClusterNames(tt.names, lookup)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("resolveClusterNames() error = %v, want %q", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveClusterNames() error = %v", err)
+			}
+			if !reflect.DeepEqual(targets, tt.expected) {
+				t.Errorf("resolveClusterNames() = %v, want %v", targets, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFilterTargeted verifies only the targeted candidates are kept, targeted clusters that are not
+// candidates are warned about, and all candidates are kept without --cluster-names.
+func TestFilterTargeted(t *testing.T) {
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "id-1", ClusterName: "prod-api"},
+		{ClusterID: "id-2", ClusterName: "billing-prod"},
+	}
+
+	var buf bytes.Buffer
+	m := &migrateOpts{mgmtClusterName: "mgmt-cluster", targets: map[string]string{"id-2": "Billing", "id-9": "done"}}
+	targeted := m.filterTargeted(&buf, candidates)
+	if len(targeted) != 1 || targeted[0].ClusterID != "id-2" {
+		t.Errorf("filterTargeted() = %+v, want only id-2", targeted)
+	}
+	if expected := "WARNING: done (id-9) is not a migration candidate on management cluster mgmt-cluster\n"; buf.String() != expected {
+		t.Errorf("Unexpected output %q, want %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if all := (&migrateOpts{}).filterTargeted(&buf, candidates); len(all) != 2 || buf.Len() != 0 {
+		t.Errorf("Expected all candidates without --cluster-names, got %+v and output %q", all, buf.String())
+	}
+}
+
+// TestApplyFilterName verifies --show-only name=<pattern> keeps the clusters of every category whose name
+// matches the glob pattern, and that bad patterns are rejected.
+func TestApplyFilterName(t *testing.T) {
+	results := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{{ClusterID: "a1", ClusterName: "prod-api"}},
+		ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "b2", ClusterName: "staging-api"}},
+		AlreadyConfigured: []hostedClusterAuditInfo{{ClusterID: "c3", ClusterName: "prod-billing"}},
+	}
+
+	a := &auditOpts{showOnly: "name=prod-*"}
+	filtered := a.applyFilter(results)
+	if filtered.TotalScanned != 2 || len(filtered.NeedsLabelRemoval) != 1 || len(filtered.ReadyForMigration) != 0 ||
+		len(filtered.AlreadyConfigured) != 1 {
+		t.Errorf("Unexpected filtered results: %+v", filtered)
+	}
+	if !a.showsAlreadyConfigured() {
+		t.Error("Expected already configured clusters to be listed with a name filter")
+	}
+
+	for filter, expectErr := range map[string]bool{"name=prod-*": false, "name=prod-api": false, "name=": true, "name=[": true} {
+		if err := validateNameFilter(filter); (err != nil) != expectErr {
+			t.Errorf("validateNameFilter(%q) error = %v, expectErr %v", filter, err, expectErr)
+		}
+	}
+}
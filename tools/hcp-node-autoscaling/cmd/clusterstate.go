@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// filterOCMState splits candidates into those whose OCM cluster state is ready and those that are not, e.g.
+// because they are installing, uninstalling or in error. Patching the ManifestWork of a cluster that is
+// still being provisioned or torn down races with the provisioner. A cluster whose state cannot be
+// fetched is treated as not ready.
+func (m *migrateOpts) filterOCMState(ctx context.Context, candidates []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, []skippedCluster) {
+	var ready []hostedClusterAuditInfo
+	var notReady []skippedCluster
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		spanCtx, span := startSpan(ctx, spanOCMClusterState, clusterAttributes(c)...)
+		response, err := m.ocmConn.ClustersMgmt().V1().Clusters().Cluster(c.ClusterID).Get().SendContext(spanCtx)
+		endSpan(span, err)
+		reason := ""
+		if err != nil {
+			reason = fmt.Sprintf("failed to get OCM cluster state: %v", err)
+		} else {
+			reason = ocmStateReason(response.Body().State())
+		}
+		if reason != "" {
+			slog.Info("Skipping cluster that is not ready in OCM", "clusterID", c.ClusterID, "reason", reason)
+			notReady = append(notReady, skippedCluster{info: c, reason: reason})
+			continue
+		}
+		ready = append(ready, c)
+	}
+	return ready, notReady
+}
+
+// ocmStateReason returns why a cluster in the given OCM state must not be patched, or "" when it is ready.
+func ocmStateReason(state cmv1.ClusterState) string {
+	switch state {
+	case cmv1.ClusterStateReady:
+		return ""
+	case "":
+		return "OCM cluster state is not reported"
+	default:
+		return fmt.Sprintf("OCM cluster state is %s, not ready", state)
+	}
+}
+
+// displayNotReady prints the candidates skipped because their OCM cluster state is not ready.
+func displayNotReady(w io.Writer, notReady []skippedCluster) {
+	if len(notReady) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Skipped: OCM Cluster Not Ready (%d) ===\n\n", len(notReady))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+	for _, s := range notReady {
+		p.AddRow([]string{s.info.ClusterID, s.info.ClusterName, s.reason})
+	}
+	p.Flush()
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// TestOCMStateReason verifies only clusters that are ready in OCM may be patched.
+func TestOCMStateReason(t *testing.T) {
+	tests := []struct {
+		state    cmv1.ClusterState
+		expected string
+	}{
+		{state: cmv1.ClusterStateReady},
+		{state: cmv1.ClusterStateInstalling, expected: "OCM cluster state is installing, not ready"},
+		{state: cmv1.ClusterStateUninstalling, expected: "OCM cluster state is uninstalling, not ready"},
+		{state: cmv1.ClusterStateError, expected: "OCM cluster state is error, not ready"},
+		{state: cmv1.ClusterStateHibernating, expected: "OCM cluster state is hibernating, not ready"},
+		{state: "", expected: "OCM cluster state is not reported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if result := ocmStateReason(tt.state); result != tt.expected {
+				t.Errorf("ocmStateReason(%q) = %q, want %q", tt.state, result, tt.expected)
+			}
+		})
+	}
+}
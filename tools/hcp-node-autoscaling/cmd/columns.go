@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of --columns entries that select a label or annotation of the HostedCluster.
+const (
+	labelColumnPrefix      = "label:"
+	annotationColumnPrefix = "annotation:"
+)
+
+// auditColumn is a column of the CSV and table output of an audit.
+type auditColumn struct {
+	name  string
+	value func(c hostedClusterAuditInfo) string
+}
+
+// header returns the table header of the column: the upper-cased name, or the key of a label or annotation.
+func (col auditColumn) header() string {
+	for _, prefix := range []string{labelColumnPrefix, annotationColumnPrefix} {
+		if key, ok := strings.CutPrefix(col.name, prefix); ok {
+			return strings.ToUpper(key)
+		}
+	}
+	return strings.ToUpper(strings.ReplaceAll(col.name, "_", " "))
+}
+
+// csvColumns are the columns of the CSV output, in the default order.
+var csvColumns = []auditColumn{
+	{"cluster_id", func(c hostedClusterAuditInfo) string { return c.ClusterID }},
+	{"cluster_name", func(c hostedClusterAuditInfo) string { return c.ClusterName }},
+	{"namespace", func(c hostedClusterAuditInfo) string { return c.Namespace }},
+	{"current_size", func(c hostedClusterAuditInfo) string { return c.CurrentSize }},
+	{"category", func(c hostedClusterAuditInfo) string { return c.Category }},
+	{"nodepool_count", func(c hostedClusterAuditInfo) string { return strconv.Itoa(c.NodePoolCount) }},
+	{"worker_replicas", func(c hostedClusterAuditInfo) string { return strconv.Itoa(int(c.WorkerReplicas)) }},
+	{"control_plane_cpu_requests", func(c hostedClusterAuditInfo) string { return c.ControlPlaneCPURequests }},
+	{"control_plane_memory_requests", func(c hostedClusterAuditInfo) string { return c.ControlPlaneMemoryRequests }},
+	{"expected_size_class", func(c hostedClusterAuditInfo) string { return c.ExpectedSizeClass }},
+	{"drifted_annotations", func(c hostedClusterAuditInfo) string {
+		var drifted []string
+		for _, d := range c.Drift {
+			drifted = append(drifted, d.Annotation)
+		}
+		return strings.Join(drifted, ";")
+	}},
+	{"excluded", func(c hostedClusterAuditInfo) string { return strconv.FormatBool(c.Excluded) }},
+	{"exclusion_reason", func(c hostedClusterAuditInfo) string { return c.ExclusionReason }},
+	{"ocm_state", func(c hostedClusterAuditInfo) string { return c.OCMState }},
+	{"subscription_status", func(c hostedClusterAuditInfo) string { return c.SubscriptionStatus }},
+	{"organization_id", func(c hostedClusterAuditInfo) string { return c.OrganizationID }},
+	{"organization_name", func(c hostedClusterAuditInfo) string { return c.OrganizationName }},
+	{"support_level", func(c hostedClusterAuditInfo) string { return c.SupportLevel }},
+	{"paused_reason", func(c hostedClusterAuditInfo) string { return c.PausedReason }},
+	{"size_override", func(c hostedClusterAuditInfo) string { return c.SizeOverride }},
+	{"kube_apiserver_memory_requests", func(c hostedClusterAuditInfo) string { return c.KubeAPIServerMemoryRequests }},
+	{"simulated_size_class", func(c hostedClusterAuditInfo) string { return c.SimulatedSizeClass }},
+	{"size_change", sizeChangeValue},
+	{"openshift_version", func(c hostedClusterAuditInfo) string { return c.OpenShiftVersion }},
+	{"channel_group", func(c hostedClusterAuditInfo) string { return c.ChannelGroup }},
+	{"hypershift_operator_version", func(c hostedClusterAuditInfo) string { return c.HyperShiftVersion }},
+	{"subcategory", func(c hostedClusterAuditInfo) string { return c.Subcategory }},
+	{"reasons", func(c hostedClusterAuditInfo) string { return strings.Join(c.Reasons, ";") }},
+	{"deletion_timestamp", func(c hostedClusterAuditInfo) string { return c.DeletionTimestamp }},
+	{"migrated_at", func(c hostedClusterAuditInfo) string { return c.MigratedAt }},
+	{"migration_run_id", func(c hostedClusterAuditInfo) string { return c.MigrationRunID }},
+	{"conditions", conditionsValue},
+}
+
+// parseColumns parses a --columns list of column names and label:<key> or annotation:<key> entries, in
+// the order they are given.
+func parseColumns(names []string) ([]auditColumn, error) {
+	columns := make([]auditColumn, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if key, ok := strings.CutPrefix(name, labelColumnPrefix); ok && key != "" {
+			columns = append(columns, auditColumn{name, func(c hostedClusterAuditInfo) string { return c.Labels[key] }})
+			continue
+		}
+		if key, ok := strings.CutPrefix(name, annotationColumnPrefix); ok && key != "" {
+			columns = append(columns, auditColumn{name, func(c hostedClusterAuditInfo) string { return c.Annotations[key] }})
+			continue
+		}
+		column, ok := findColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid column '%s'. Valid options: %s, %s<key>, %s<key>",
+				name, strings.Join(columnNamesOf(csvColumns), ", "), labelColumnPrefix, annotationColumnPrefix)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// findColumn returns the CSV column with the given name.
+func findColumn(name string) (auditColumn, bool) {
+	for _, column := range csvColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return auditColumn{}, false
+}
+
+// columnNamesOf returns the names of columns.
+func columnNamesOf(columns []auditColumn) []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.name)
+	}
+	return names
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestParseColumns verifies column names and label and annotation columns are accepted in order, and that
+// unknown columns are rejected.
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		columns     []string
+		expected    []string
+		expectError bool
+	}{
+		{name: "known columns", columns: []string{"current_size", "cluster_id"}, expected: []string{"current_size", "cluster_id"}},
+		{
+			name:     "label and annotation",
+			columns:  []string{"cluster_id", " label:ext-managed.openshift.io/sector", "annotation:" + topologyAnnotation},
+			expected: []string{"cluster_id", "label:ext-managed.openshift.io/sector", "annotation:" + topologyAnnotation},
+		},
+		{name: "unknown column", columns: []string{"cluster_id", "region"}, expectError: true},
+		{name: "label without key", columns: []string{"label:"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := parseColumns(tt.columns)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseColumns() error = %v, expectError %v", err, tt.expectError)
+			}
+			if err == nil && strings.Join(columnNamesOf(columns), ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("parseColumns() = %v, want %v", columnNamesOf(columns), tt.expected)
+			}
+		})
+	}
+}
+
+// TestAuditColumnsOutput verifies --columns selects and orders the CSV columns and the cluster table columns,
+// and that the CSV output keeps all columns by default.
+func TestAuditColumnsOutput(t *testing.T) {
+	results := &auditResults{
+		ReadyForMigration: []hostedClusterAuditInfo{{
+			ClusterID:   "a1",
+			ClusterName: "one",
+			CurrentSize: "large",
+			Labels:      map[string]string{"ext-managed.openshift.io/sector": "canary"},
+		}},
+		AlreadyConfigured: []hostedClusterAuditInfo{{ClusterID: "b2", ClusterName: "two"}},
+	}
+	columns, err := parseColumns([]string{"current_size", "cluster_id", "label:ext-managed.openshift.io/sector"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &auditOpts{columns: columns}
+
+	var out bytes.Buffer
+	if err := a.printCSVOutput(&out, results, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "current_size,cluster_id,label:ext-managed.openshift.io/sector\nlarge,a1,canary\n,b2,\n"
+	if out.String() != expected {
+		t.Errorf("printCSVOutput() = %q, want %q", out.String(), expected)
+	}
+
+	if header := strings.Join(a.clusterTableHeader(), ","); header != "CURRENT SIZE,CLUSTER ID,EXT-MANAGED.OPENSHIFT.IO/SECTOR" {
+		t.Errorf("clusterTableHeader() = %s", header)
+	}
+	if row := strings.Join(a.clusterTableRow(results.AlreadyConfigured[0]), ","); row != "<unset>,b2,<unset>" {
+		t.Errorf("clusterTableRow() = %s", row)
+	}
+
+	out.Reset()
+	if err := (&auditOpts{}).printCSVOutput(&out, results, true); err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(out.String(), "\n")
+	if !strings.HasPrefix(header, "cluster_id,cluster_name,namespace,current_size,category,") || !strings.HasSuffix(header, ",reasons,deletion_timestamp,migrated_at,migration_run_id,conditions") {
+		t.Errorf("Unexpected default CSV header %s", header)
+	}
+}
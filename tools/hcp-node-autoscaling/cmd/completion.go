@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const completionPageSize = 100
+
+// fleetCluster is a management or service cluster offered for cluster ID flag completion.
+type fleetCluster struct {
+	ID     string
+	Name   string
+	Region string
+}
+
+// registerClusterCompletions completes the cluster ID flags of cmd with the management and service
+// clusters known to OSD Fleet Manager.
+func registerClusterCompletions(cmd *cobra.Command) {
+	completions := map[string]cobra.CompletionFunc{
+		"mgmt-cluster-id":    completeFleetClusters(listManagementClusters, false),
+		"mgmt-cluster-ids":   completeFleetClusters(listManagementClusters, true),
+		"service-cluster-id": completeFleetClusters(listServiceClusters, false),
+	}
+	for flag, complete := range completions {
+		if cmd.Flags().Lookup(flag) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(flag, complete)
+		}
+	}
+}
+
+// completeFleetClusters returns a flag completion function offering the clusters returned by list whose
+// ID or name starts with the typed prefix. For a comma-separated list flag only the last element is completed.
+func completeFleetClusters(list func(*sdk.Connection) ([]fleetCluster, error), commaSeparated bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			cobra.CompErrorln(fmt.Sprintf("failed to create OCM connection: %v", err))
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		defer conn.Close()
+
+		clusters, err := list(conn)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		previous := ""
+		if i := strings.LastIndex(toComplete, ","); commaSeparated && i >= 0 {
+			previous, toComplete = toComplete[:i+1], toComplete[i+1:]
+		}
+		return clusterCompletions(clusters, previous, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// clusterCompletions returns the clusters whose ID or name starts with prefix, described by their other
+// identifier and region. Clusters are offered by ID unless only their name matches.
+func clusterCompletions(clusters []fleetCluster, previous, prefix string) []cobra.Completion {
+	var completions []cobra.Completion
+	for _, c := range clusters {
+		switch {
+		case c.ID != "" && strings.HasPrefix(c.ID, prefix):
+			completions = append(completions, cobra.CompletionWithDesc(previous+c.ID, fmt.Sprintf("%s (%s)", c.Name, c.Region)))
+		case c.Name != "" && strings.HasPrefix(c.Name, prefix):
+			completions = append(completions, cobra.CompletionWithDesc(previous+c.Name, fmt.Sprintf("%s (%s)", c.ID, c.Region)))
+		}
+	}
+	return completions
+}
+
+// listManagementClusters returns all management clusters in OSD Fleet Manager.
+func listManagementClusters(conn *sdk.Connection) ([]fleetCluster, error) {
+	var clusters []fleetCluster
+	for page := 1; ; page++ {
+		resp, err := conn.OSDFleetMgmt().V1().ManagementClusters().List().Page(page).Size(completionPageSize).Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list management clusters: %v", err)
+		}
+		for _, mc := range resp.Items().Slice() {
+			clusters = append(clusters, fleetCluster{ID: mc.ClusterManagementReference().ClusterId(), Name: mc.Name(), Region: mc.Region()})
+		}
+		if resp.Size() < completionPageSize || len(clusters) >= resp.Total() {
+			return clusters, nil
+		}
+	}
+}
+
+// listServiceClusters returns all service clusters in OSD Fleet Manager.
+func listServiceClusters(conn *sdk.Connection) ([]fleetCluster, error) {
+	var clusters []fleetCluster
+	for page := 1; ; page++ {
+		resp, err := conn.OSDFleetMgmt().V1().ServiceClusters().List().Page(page).Size(completionPageSize).Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service clusters: %v", err)
+		}
+		for _, sc := range resp.Items().Slice() {
+			clusters = append(clusters, fleetCluster{ID: sc.ClusterManagementReference().ClusterId(), Name: sc.Name(), Region: sc.Region()})
+		}
+		if resp.Size() < completionPageSize || len(clusters) >= resp.Total() {
+			return clusters, nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestClusterCompletions verifies clusters are completed by ID or name prefix, and that the earlier
+// elements of a comma-separated list are kept.
+func TestClusterCompletions(t *testing.T) {
+	clusters := []fleetCluster{
+		{ID: "2abc", Name: "hs-mc-one", Region: "us-east-1"},
+		{ID: "2abd", Name: "hs-mc-two", Region: "eu-west-1"},
+		{ID: "3xyz", Name: "hs-mc-three", Region: "us-east-1"},
+	}
+
+	tests := []struct {
+		name     string
+		previous string
+		prefix   string
+		expected []cobra.Completion
+	}{
+		{
+			name:   "by ID",
+			prefix: "2ab",
+			expected: []cobra.Completion{
+				cobra.CompletionWithDesc("2abc", "hs-mc-one (us-east-1)"),
+				cobra.CompletionWithDesc("2abd", "hs-mc-two (eu-west-1)"),
+			},
+		},
+		{
+			name:     "by name",
+			prefix:   "hs-mc-t",
+			expected: []cobra.Completion{cobra.CompletionWithDesc("hs-mc-two", "2abd (eu-west-1)"), cobra.CompletionWithDesc("hs-mc-three", "3xyz (us-east-1)")},
+		},
+		{
+			name:     "comma-separated",
+			previous: "2abc,",
+			prefix:   "3",
+			expected: []cobra.Completion{cobra.CompletionWithDesc("2abc,3xyz", "hs-mc-three (us-east-1)")},
+		},
+		{name: "no match", prefix: "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := clusterCompletions(clusters, tt.previous, tt.prefix)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("clusterCompletions() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRegisterClusterCompletions verifies completion is registered only for the cluster ID flags a command has.
+func TestRegisterClusterCompletions(t *testing.T) {
+	cmd := NewAuditNodePoolsCmd()
+	registerClusterCompletions(cmd)
+
+	if _, ok := cmd.GetFlagCompletionFunc("mgmt-cluster-id"); !ok {
+		t.Error("Expected --mgmt-cluster-id to be completed")
+	}
+	if _, ok := cmd.GetFlagCompletionFunc("service-cluster-id"); ok {
+		t.Error("Expected no completion for a flag the command does not have")
+	}
+}
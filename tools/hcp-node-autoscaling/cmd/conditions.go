@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+)
+
+// summarizedConditions are the HostedCluster conditions reported by the audit, in order.
+var summarizedConditions = []hypershiftv1beta1.ConditionType{
+	hypershiftv1beta1.HostedClusterAvailable,
+	hypershiftv1beta1.HostedClusterDegraded,
+	hypershiftv1beta1.HostedClusterProgressing,
+}
+
+// hostedClusterCondition is the status of a HostedCluster condition, with its reason.
+type hostedClusterCondition struct {
+	Type   string `json:"type" yaml:"type"`
+	Status string `json:"status" yaml:"status"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// healthy reports whether the condition is in its expected state: Available, and not Degraded or Progressing.
+func (c hostedClusterCondition) healthy() bool {
+	if c.Type == string(hypershiftv1beta1.HostedClusterAvailable) {
+		return c.Status == "True"
+	}
+	return c.Status == "False"
+}
+
+// String formats the condition status, followed by its reason when the condition is unhealthy, e.g.
+// "False (WaitingForAvailable)".
+func (c hostedClusterCondition) String() string {
+	if c.healthy() || c.Reason == "" {
+		return c.Status
+	}
+	return fmt.Sprintf("%s (%s)", c.Status, c.Reason)
+}
+
+// summarizeConditions returns the Available, Degraded and Progressing conditions that the HostedCluster
+// reports, in that order.
+func summarizeConditions(hc *hypershiftv1beta1.HostedCluster) []hostedClusterCondition {
+	var summary []hostedClusterCondition
+	for _, conditionType := range summarizedConditions {
+		for _, c := range hc.Status.Conditions {
+			if c.Type == string(conditionType) {
+				summary = append(summary, hostedClusterCondition{Type: c.Type, Status: string(c.Status), Reason: c.Reason})
+				break
+			}
+		}
+	}
+	return summary
+}
+
+// condition returns the formatted status of a summarized condition, or an empty string if the HostedCluster
+// has not reported it.
+func (c hostedClusterAuditInfo) condition(conditionType hypershiftv1beta1.ConditionType) string {
+	for _, condition := range c.Conditions {
+		if condition.Type == string(conditionType) {
+			return condition.String()
+		}
+	}
+	return ""
+}
+
+// conditionsValue formats the summarized conditions for the CSV output, e.g.
+// "Available=True;Degraded=True (UnavailableReplicas);Progressing=False".
+func conditionsValue(c hostedClusterAuditInfo) string {
+	values := make([]string, 0, len(c.Conditions))
+	for _, condition := range c.Conditions {
+		values = append(values, condition.Type+"="+condition.String())
+	}
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestSummarizeConditions verifies the Available, Degraded and Progressing conditions are summarized in
+// that order, and that reasons are only shown for unhealthy conditions.
+func TestSummarizeConditions(t *testing.T) {
+	hc := &hypershiftv1beta1.HostedCluster{
+		Status: hypershiftv1beta1.HostedClusterStatus{
+			Conditions: []metav1.Condition{
+				{Type: "Progressing", Status: metav1.ConditionFalse, Reason: "AsExpected"},
+				{Type: "ValidConfiguration", Status: metav1.ConditionTrue, Reason: "AsExpected"},
+				{Type: "Degraded", Status: metav1.ConditionTrue, Reason: "UnavailableReplicas"},
+				{Type: "Available", Status: metav1.ConditionTrue, Reason: "AsExpected"},
+			},
+		},
+	}
+
+	info := hostedClusterAuditInfo{Conditions: summarizeConditions(hc)}
+	expected := []hostedClusterCondition{
+		{Type: "Available", Status: "True", Reason: "AsExpected"},
+		{Type: "Degraded", Status: "True", Reason: "UnavailableReplicas"},
+		{Type: "Progressing", Status: "False", Reason: "AsExpected"},
+	}
+	if !reflect.DeepEqual(info.Conditions, expected) {
+		t.Fatalf("summarizeConditions() = %+v, want %+v", info.Conditions, expected)
+	}
+	if value := conditionsValue(info); value != "Available=True;Degraded=True (UnavailableReplicas);Progressing=False" {
+		t.Errorf("conditionsValue() = %s", value)
+	}
+
+	if conditions := summarizeConditions(&hypershiftv1beta1.HostedCluster{}); conditions != nil {
+		t.Errorf("Expected no conditions for a HostedCluster without status, got %+v", conditions)
+	}
+}
+
+// TestHostedClusterConditionString verifies the formatting of each condition state.
+func TestHostedClusterConditionString(t *testing.T) {
+	tests := []struct {
+		condition hostedClusterCondition
+		expected  string
+	}{
+		{hostedClusterCondition{Type: "Available", Status: "True", Reason: "AsExpected"}, "True"},
+		{hostedClusterCondition{Type: "Available", Status: "False", Reason: "WaitingForAvailable"}, "False (WaitingForAvailable)"},
+		{hostedClusterCondition{Type: "Available", Status: "Unknown"}, "Unknown"},
+		{hostedClusterCondition{Type: "Degraded", Status: "False", Reason: "AsExpected"}, "False"},
+		{hostedClusterCondition{Type: "Progressing", Status: "True", Reason: "ReconciliationActive"}, "True (ReconciliationActive)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := tt.condition.String(); result != tt.expected {
+				t.Errorf("String() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
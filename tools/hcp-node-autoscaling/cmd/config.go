@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// configEnvPrefix is the prefix of the environment variables that set flag defaults, e.g.
+// HCP_NAS_SYNC_TIMEOUT for --sync-timeout.
+const configEnvPrefix = "HCP_NAS"
+
+// configurableFlags lists the flags that can be set from the config file and environment: common defaults
+// of every run. Flags that skip a safety check or confirmation, such as --ignore-freeze, --force-overwrite,
+// --direct or --skip-confirmation, or that widen a run, such as --max-clusters, must be passed on the command
+// line of the run they apply to.
+var configurableFlags = map[string]bool{
+	"output":               true,
+	"environment":          true,
+	"max-in-flight-per-mc": true,
+	"migrate-concurrency":  true,
+	"sync-timeout":         true,
+	"elevation-reason":     true,
+	"exclude-cluster-ids":  true,
+	"log-level":            true,
+	"log-format":           true,
+	"no-progress":          true,
+	"qps":                  true,
+	"burst":                true,
+}
+
+// defaultConfigPath returns the path of the config file read when --config is not set.
+func defaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "hcp-node-autoscaling", "config.yaml"), nil
+}
+
+// applyConfig sets the flags of cmd that were not given on the command line from HCP_NAS_* environment
+// variables and the config file at path, in that order of precedence. Config keys are flag names and apply
+// to every subcommand with that flag. Only configurableFlags can be set this way; setting any other flag of cmd
+// is an error. A missing config file is only an error when its path was given explicitly.
+func applyConfig(cmd *cobra.Command, path string) error {
+	explicit := path != ""
+	if !explicit {
+		defaultPath, err := defaultConfigPath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+
+	v := viper.New()
+	v.SetEnvPrefix(configEnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+		return fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	var errs []error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || f.Name == "help" || f.Name == "config" || !v.IsSet(f.Name) {
+			return
+		}
+		if !configurableFlags[f.Name] {
+			errs = append(errs, fmt.Errorf("--%s cannot be set in the config file or environment; pass it on the command line", f.Name))
+			return
+		}
+		if err := f.Value.Set(configValue(v.Get(f.Name))); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s in config: %v", f.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// configValue converts a config file or environment value to flag syntax. YAML lists become the
+// comma-separated form accepted by list flags.
+func configValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = fmt.Sprint(item)
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TestApplyConfig verifies flags are set from the config file and environment, with command line flags
+// taking precedence over environment variables and environment variables over the config file.
+func TestApplyConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := `output: json
+environment: staging
+sync-timeout: 10m
+max-in-flight-per-mc: 3
+exclude-cluster-ids:
+  - a1
+  - b2
+`
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("HCP_NAS_ENVIRONMENT", "all")
+	t.Setenv("HCP_NAS_MAX_IN_FLIGHT_PER_MC", "5")
+
+	var output, environment string
+	var syncTimeout time.Duration
+	var maxInFlight int
+	var excludeIDs []string
+	cmd := &cobra.Command{Use: "migrate"}
+	cmd.Flags().StringVar(&output, "output", "text", "")
+	cmd.Flags().StringVar(&environment, "environment", "production", "")
+	cmd.Flags().DurationVar(&syncTimeout, "sync-timeout", defaultSyncTimeout, "")
+	cmd.Flags().IntVar(&maxInFlight, "max-in-flight-per-mc", 1, "")
+	cmd.Flags().StringSliceVar(&excludeIDs, "exclude-cluster-ids", nil, "")
+	if err := cmd.ParseFlags([]string{"--output", "yaml"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if err := applyConfig(cmd, configPath); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+
+	if output != "yaml" {
+		t.Errorf("Expected command line output yaml, got %s", output)
+	}
+	if environment != "all" || maxInFlight != 5 {
+		t.Errorf("Expected environment variables to win over the config file, got environment %s, max in flight %d", environment, maxInFlight)
+	}
+	if syncTimeout != 10*time.Minute {
+		t.Errorf("Expected sync timeout 10m from the config file, got %v", syncTimeout)
+	}
+	if strings.Join(excludeIDs, ",") != "a1,b2" {
+		t.Errorf("Expected excluded cluster IDs from the config file, got %v", excludeIDs)
+	}
+	if cmd.Flags().Changed("sync-timeout") {
+		t.Error("Expected flags set from config to not be marked as changed")
+	}
+}
+
+// TestApplyConfigErrors verifies a missing default config file is ignored, while a missing explicit config
+// file and invalid values are reported.
+func TestApplyConfigErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "audit"}
+		cmd.Flags().Duration("sync-timeout", defaultSyncTimeout, "")
+		return cmd
+	}
+
+	if err := applyConfig(newCmd(), ""); err != nil {
+		t.Errorf("Expected a missing default config file to be ignored, got %v", err)
+	}
+	if err := applyConfig(newCmd(), filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing explicit config file")
+	}
+
+	t.Setenv("HCP_NAS_SYNC_TIMEOUT", "soon")
+	if err := applyConfig(newCmd(), ""); err == nil || !strings.Contains(err.Error(), "sync-timeout") {
+		t.Errorf("Expected an invalid sync-timeout error, got %v", err)
+	}
+}
+
+// TestApplyConfigSafetyFlags verifies flags that skip safety checks or confirmation cannot be set from the
+// config file or environment, and are left at their defaults.
+func TestApplyConfigSafetyFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    map[string]string
+		flag   string
+	}{
+		{name: "ignore-freeze in config file", config: "ignore-freeze: true\n", flag: "ignore-freeze"},
+		{name: "force-overwrite in config file", config: "force-overwrite: true\n", flag: "force-overwrite"},
+		{name: "skip-confirmation in environment", env: map[string]string{"HCP_NAS_SKIP_CONFIRMATION": "true"}, flag: "skip-confirmation"},
+		{name: "direct in environment", env: map[string]string{"HCP_NAS_DIRECT": "true"}, flag: "direct"},
+		{name: "allow-mismatch in environment", env: map[string]string{"HCP_NAS_ALLOW_MISMATCH": "true"}, flag: "allow-mismatch"},
+		{name: "max-clusters in config file", config: "max-clusters: 500\n", flag: "max-clusters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cmd := &cobra.Command{Use: "migrate"}
+			cmd.Flags().Bool("ignore-freeze", false, "")
+			cmd.Flags().Bool("force-overwrite", false, "")
+			cmd.Flags().Bool("skip-confirmation", false, "")
+			cmd.Flags().Bool("direct", false, "")
+			cmd.Flags().Bool("allow-mismatch", false, "")
+			cmd.Flags().Int("max-clusters", 0, "")
+
+			err := applyConfig(cmd, configPath)
+			if err == nil || !strings.Contains(err.Error(), "--"+tt.flag+" cannot be set") {
+				t.Errorf("Expected --%s to be rejected, got %v", tt.flag, err)
+			}
+			if value := cmd.Flags().Lookup(tt.flag).Value.String(); value != cmd.Flags().Lookup(tt.flag).DefValue {
+				t.Errorf("Expected --%s to keep its default, got %s", tt.flag, value)
+			}
+		})
+	}
+}
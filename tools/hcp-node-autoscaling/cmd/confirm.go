@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// defaultConfirmThreshold is the number of clusters from which a change must be confirmed by typing the
+// number of clusters instead of answering y/N.
+const defaultConfirmThreshold = 20
+
+// addConfirmThresholdFlag registers --confirm-threshold on a command that asks for confirmation.
+func addConfirmThresholdFlag(cmd *cobra.Command, threshold *int) {
+	cmd.Flags().IntVar(threshold, "confirm-threshold", defaultConfirmThreshold,
+		"Number of clusters from which the confirmation requires typing the number of clusters or the management cluster name (0 always asks y/N)")
+}
+
+// validateConfirmThreshold rejects a negative --confirm-threshold.
+func validateConfirmThreshold(threshold int) error {
+	if threshold < 0 {
+		return fmt.Errorf("invalid confirmation threshold %d: must not be negative", threshold)
+	}
+	return nil
+}
+
+// confirm asks the operator to confirm a change of the given number of clusters. Below --confirm-threshold
+// a y/N answer is enough; from it on the number of clusters, or the name of one of the management clusters
+// given, must be typed. Input that ends or keeps not matching declines instead of asking again.
+func (m *migrateOpts) confirm(in io.Reader, out io.Writer, clusters int, mgmtClusterNames ...string) bool {
+	if m.confirmThreshold == 0 || clusters < m.confirmThreshold {
+		return prompt.Confirm(in, out)
+	}
+	fmt.Fprintf(out, "This change affects %d clusters.\n", clusters)
+	accepted := []string{strconv.Itoa(clusters)}
+	for _, name := range mgmtClusterNames {
+		if name != "" {
+			accepted = append(accepted, name)
+		}
+	}
+	return prompt.ConfirmTyped(in, out, accepted...)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestMigrateConfirm verifies that changes below --confirm-threshold are confirmed with y/N, and that larger
+// changes need the number of clusters or the management cluster name typed.
+func TestMigrateConfirm(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		clusters  int
+		input     string
+		expected  bool
+		typed     bool
+	}{
+		{name: "below threshold yes", threshold: 20, clusters: 19, input: "y\n", expected: true},
+		{name: "threshold disabled", threshold: 0, clusters: 200, input: "y\n", expected: true},
+		{name: "at threshold yes", threshold: 20, clusters: 20, input: "y\n", expected: false, typed: true},
+		{name: "typed count", threshold: 20, clusters: 200, input: "200\n", expected: true, typed: true},
+		{name: "typed management cluster", threshold: 20, clusters: 200, input: "mgmt-cluster\n", expected: true, typed: true},
+		{name: "wrong count", threshold: 20, clusters: 200, input: "20\n", expected: false, typed: true},
+		{name: "eof", threshold: 20, clusters: 200, input: "", expected: false, typed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &migrateOpts{confirmThreshold: tt.threshold}
+			var out bytes.Buffer
+			if got := m.confirm(strings.NewReader(tt.input), &out, tt.clusters, "mgmt-cluster"); got != tt.expected {
+				t.Errorf("confirm() = %v, want %v", got, tt.expected)
+			}
+			if typed := strings.Contains(out.String(), "to continue: "); typed != tt.typed {
+				t.Errorf("Expected typed confirmation %v, got:\n%s", tt.typed, out.String())
+			}
+		})
+	}
+}
+
+// TestValidateConfirmThreshold verifies that a negative --confirm-threshold is rejected.
+func TestValidateConfirmThreshold(t *testing.T) {
+	for _, threshold := range []int{0, 1, defaultConfirmThreshold} {
+		if err := validateConfirmThreshold(threshold); err != nil {
+			t.Errorf("validateConfirmThreshold(%d) error = %v", threshold, err)
+		}
+	}
+	if err := validateConfirmThreshold(-1); err == nil {
+		t.Error("Expected an error for a negative threshold")
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// conflictingAnnotation is the status of a migration result for a cluster that was skipped because it
+// already has a topology annotation other than the one the migration sets.
+const conflictingAnnotation = "conflicting-annotation"
+
+// annotationConflict is an existing annotation the migration would replace with a different value.
+type annotationConflict struct {
+	Annotation string `json:"annotation"`
+	Existing   string `json:"existing"`
+	Desired    string `json:"desired"`
+}
+
+// topologyConflicts returns the topology annotation of annotations if it is set to a value other than the
+// one the profile ensures.
+func topologyConflicts(profile *migrationProfile, annotations map[string]string) []annotationConflict {
+	desired, ensured := profile.orDefault().Ensure[topologyAnnotation]
+	existing := annotations[topologyAnnotation]
+	if !ensured || existing == "" || existing == desired {
+		return nil
+	}
+	return []annotationConflict{{Annotation: topologyAnnotation, Existing: existing, Desired: desired}}
+}
+
+// describeConflicts formats conflicts as annotation="existing" -> "desired".
+func describeConflicts(conflicts []annotationConflict) string {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		descriptions = append(descriptions, fmt.Sprintf("%s=%q -> %q", c.Annotation, c.Existing, c.Desired))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// checkAnnotationConflicts returns the annotations of a candidate that the patch would overwrite with a
+// different value, read from the HostedCluster manifest of its ManifestWork or, with --direct, from the
+// HostedCluster. Like revalidateBeforePatch, targets that were not categorized are not checked.
+func (m *migrateOpts) checkAnnotationConflicts(ctx context.Context, info hostedClusterAuditInfo) ([]annotationConflict, error) {
+	if info.Category != "ready-for-migration" {
+		return nil, nil
+	}
+
+	if m.direct {
+		hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HostedCluster: %w", err)
+		}
+		return topologyConflicts(m.clusterProfile(info.ClusterID), hc.Annotations), nil
+	}
+
+	var annotations map[string]string
+	err := withPhaseTimeout(ctx, m.timeouts.manifestWork, "reading ManifestWork", "cluster "+info.ClusterID, func(ctx context.Context) error {
+		manifestWork, err := m.getManifestWork(ctx, info.ClusterID)
+		if err != nil {
+			return err
+		}
+		_, manifestData, err := findHostedClusterManifest(manifestWork)
+		if err != nil {
+			return err
+		}
+		metadata, _ := manifestData["metadata"].(map[string]interface{})
+		existing, _ := metadata["annotations"].(map[string]interface{})
+		annotations = make(map[string]string, len(existing))
+		for key, value := range existing {
+			annotations[key] = fmt.Sprint(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return topologyConflicts(m.clusterProfile(info.ClusterID), annotations), nil
+}
+
+// displayOverwritten lists the annotation values --force-overwrite replaced.
+func displayOverwritten(w io.Writer, results []migrationResult) {
+	var rows [][]string
+	for _, r := range results {
+		if r.Status != "success" {
+			continue
+		}
+		for _, c := range r.Overwritten {
+			rows = append(rows, []string{r.ClusterID, r.ClusterName, c.Annotation, c.Existing, c.Desired})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "! Overwritten Annotations (--force-overwrite):")
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "REPLACED", "WITH"})
+	for _, row := range rows {
+		p.AddRow(row)
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
+
+// logOverwritten warns about each conflicting annotation value a forced migration is about to replace.
+func logOverwritten(info hostedClusterAuditInfo, conflicts []annotationConflict) {
+	for _, c := range conflicts {
+		slog.Warn("Overwriting conflicting annotation (--force-overwrite)", "clusterID", info.ClusterID,
+			"annotation", c.Annotation, "replaced", c.Existing, "with", c.Desired)
+	}
+}
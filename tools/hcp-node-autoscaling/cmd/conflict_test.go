@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// topologyProfile is a migration profile that also sets the dedicated topology.
+var topologyProfile = &migrationProfile{
+	Name:   "topology",
+	Ensure: map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology},
+	Remove: []string{sizeOverrideAnnotation},
+}
+
+// TestTopologyConflicts verifies only a topology annotation set to another value conflicts with the migration.
+func TestTopologyConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     *migrationProfile
+		annotations map[string]string
+		expected    []annotationConflict
+	}{
+		{name: "unset", profile: topologyProfile, annotations: nil},
+		{name: "already dedicated", profile: topologyProfile, annotations: map[string]string{topologyAnnotation: dedicatedTopology}},
+		{
+			name:        "different topology",
+			profile:     topologyProfile,
+			annotations: map[string]string{topologyAnnotation: "shared"},
+			expected:    []annotationConflict{{Annotation: topologyAnnotation, Existing: "shared", Desired: dedicatedTopology}},
+		},
+		{
+			name:        "default profile against a different topology",
+			annotations: map[string]string{topologyAnnotation: "shared"},
+			expected:    []annotationConflict{{Annotation: topologyAnnotation, Existing: "shared", Desired: dedicatedTopology}},
+		},
+		{
+			name:        "profile not setting the topology",
+			profile:     &migrationProfile{Name: "autoscaling", Ensure: map[string]string{autoscalingAnnotation: "true"}},
+			annotations: map[string]string{topologyAnnotation: "shared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := topologyConflicts(tt.profile, tt.annotations); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("topologyConflicts() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMigrateClusterConflictingAnnotation verifies a cluster whose ManifestWork has a different topology is
+// skipped without being patched unless --force-overwrite is set.
+func TestMigrateClusterConflictingAnnotation(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", map[string]string{topologyAnnotation: "shared"})
+	manifestWork := newTestManifestWork(t, "mgmt-cluster", hc)
+
+	// The default profile ensures the dedicated topology.
+	m := &migrateOpts{
+		mgmtClusterName: "mgmt-cluster",
+		mgmtClient:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc).Build(),
+		serviceClient:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(manifestWork).Build(),
+	}
+	info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace, Category: "ready-for-migration"}
+
+	result := m.migrateCluster(context.Background(), info)
+	if result.Status != conflictingAnnotation || !strings.Contains(result.Error, `"shared" -> "dedicated-request-serving-components"`) {
+		t.Fatalf("migrateCluster() = %+v, want a %s result naming the existing value", result, conflictingAnnotation)
+	}
+	if err := migrationExitError([]migrationResult{result}); err != nil {
+		t.Errorf("Expected a conflicting cluster not to fail the run, got %v", err)
+	}
+	current, err := m.getManifestWork(context.Background(), "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current.Spec.Workload.Manifests[0].Raw) != string(manifestWork.Spec.Workload.Manifests[0].Raw) {
+		t.Error("Expected the ManifestWork not to be patched")
+	}
+
+	for _, direct := range []bool{false, true} {
+		m.direct = direct
+		conflicts, err := m.checkAnnotationConflicts(context.Background(), info)
+		if err != nil {
+			t.Fatalf("checkAnnotationConflicts(direct=%v) error = %v", direct, err)
+		}
+		if len(conflicts) != 1 || conflicts[0].Existing != "shared" {
+			t.Errorf("checkAnnotationConflicts(direct=%v) = %+v, want the shared topology", direct, conflicts)
+		}
+	}
+
+	// Uncategorized targets, such as the cluster of an annotate run, are not checked.
+	if conflicts, err := m.checkAnnotationConflicts(context.Background(), hostedClusterAuditInfo{ClusterID: "a1"}); err != nil || conflicts != nil {
+		t.Errorf("Expected no check for uncategorized targets, got %+v, %v", conflicts, err)
+	}
+}
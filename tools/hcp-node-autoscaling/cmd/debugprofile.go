@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Phases of a run whose time is reported with --debug-profile. Phases may overlap: OCM calls made while
+// auditing a namespace count towards both.
+const (
+	phaseNamespaceList  = "namespace list"
+	phaseNamespaceAudit = "per-namespace audit"
+	phaseOCMCalls       = "OCM calls"
+	phaseOutput         = "output"
+)
+
+// debugProfile captures CPU and heap profiles and a runtime trace of the run into a directory, for the
+// hidden --debug-profile flag.
+type debugProfile struct {
+	dir string
+
+	// timings accumulates the time spent in each phase of the run while --debug-profile is set, and is nil
+	// otherwise.
+	timings *phaseTimings
+
+	mu      sync.Mutex
+	runDir  string
+	cpu     *os.File
+	trace   *os.File
+	stopped bool
+}
+
+// addDebugProfileFlag registers the hidden --debug-profile flag.
+func addDebugProfileFlag(cmd *cobra.Command, p *debugProfile) {
+	cmd.Flags().StringVar(&p.dir, "debug-profile", "",
+		"Write CPU and heap profiles and a runtime trace of the run to <dir>/<run ID>/, and print the time spent in each phase")
+	_ = cmd.Flags().MarkHidden("debug-profile")
+}
+
+// start begins the CPU profile, the runtime trace and the phase timings of the run with the given ID. It
+// does nothing without --debug-profile.
+func (p *debugProfile) start(runID string) error {
+	if p == nil || p.dir == "" {
+		return nil
+	}
+	p.runDir = filepath.Join(p.dir, runID)
+	if err := os.MkdirAll(p.runDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create debug profile directory: %v", err)
+	}
+
+	cpu, err := os.Create(filepath.Join(p.runDir, "cpu.pprof"))
+	if err != nil {
+		return fmt.Errorf("failed to create CPU profile: %v", err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return fmt.Errorf("failed to start CPU profile: %v", err)
+	}
+	p.cpu = cpu
+
+	traceFile, err := os.Create(filepath.Join(p.runDir, "trace.out"))
+	if err != nil {
+		p.stop(io.Discard)
+		return fmt.Errorf("failed to create runtime trace: %v", err)
+	}
+	if err := trace.Start(traceFile); err != nil {
+		traceFile.Close()
+		p.stop(io.Discard)
+		return fmt.Errorf("failed to start runtime trace: %v", err)
+	}
+	p.trace = traceFile
+
+	p.timings = newPhaseTimings()
+	return nil
+}
+
+// stop ends the CPU profile and the runtime trace, writes the heap profile and prints the phase timings to
+// w. It only does so once, so it can be called both after a successful run and when the run failed.
+func (p *debugProfile) stop(w io.Writer) error {
+	if p == nil || p.runDir == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return nil
+	}
+	p.stopped = true
+
+	var errs []error
+	if p.trace != nil {
+		trace.Stop()
+		errs = append(errs, p.trace.Close())
+	}
+	if p.cpu != nil {
+		pprof.StopCPUProfile()
+		errs = append(errs, p.cpu.Close())
+	}
+	errs = append(errs, writeHeapProfile(filepath.Join(p.runDir, "heap.pprof")))
+
+	p.timings.print(w)
+	p.timings = nil
+	fmt.Fprintf(w, "Debug profiles written to %s\n", p.runDir)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to write debug profiles: %v", err)
+	}
+	return nil
+}
+
+// writeHeapProfile writes the heap profile after a garbage collection, so it shows the live heap.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// phaseTiming is the number of times a phase ran and the time it took.
+type phaseTiming struct {
+	calls int
+	total time.Duration
+	max   time.Duration
+}
+
+// phaseTimings accumulates the time spent in each phase, in the order the phases first ran. A nil value
+// records nothing, so the phases can be tracked unconditionally.
+type phaseTimings struct {
+	mu     sync.Mutex
+	order  []string
+	phases map[string]*phaseTiming
+	now    func() time.Time
+}
+
+func newPhaseTimings() *phaseTimings {
+	return &phaseTimings{phases: map[string]*phaseTiming{}, now: time.Now}
+}
+
+// track starts timing a run of phase and returns the function that ends it, e.g.
+// defer a.session.timings().track(phaseOutput)().
+func (p *phaseTimings) track(phase string) func() {
+	if p == nil {
+		return func() {}
+	}
+	started := p.now()
+	return func() {
+		p.record(phase, p.now().Sub(started))
+	}
+}
+
+// record adds a run of phase that took d.
+func (p *phaseTimings) record(phase string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t, ok := p.phases[phase]
+	if !ok {
+		t = &phaseTiming{}
+		p.phases[phase] = t
+		p.order = append(p.order, phase)
+	}
+	t.calls++
+	t.total += d
+	t.max = max(t.max, d)
+}
+
+// print writes the time spent in each phase.
+func (p *phaseTimings) print(w io.Writer) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(w, "\n=== Phase Timings ===\n\n")
+	if len(p.order) == 0 {
+		fmt.Fprintf(w, "No phases were timed\n\n")
+		return
+	}
+	table := output.NewTable(w, output.TableMinWidth)
+	table.AddRow([]string{"PHASE", "CALLS", "TOTAL", "AVERAGE", "MAX"})
+	for _, phase := range p.order {
+		t := p.phases[phase]
+		table.AddRow([]string{phase, strconv.Itoa(t.calls), roundDuration(t.total),
+			roundDuration(t.total / time.Duration(t.calls)), roundDuration(t.max)})
+	}
+	table.Flush()
+	fmt.Fprintln(w)
+}
+
+// roundDuration formats d to the millisecond.
+func roundDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPhaseTimings verifies the calls, total, average and maximum time of each phase are printed in the order
+// the phases first ran.
+func TestPhaseTimings(t *testing.T) {
+	timings := newPhaseTimings()
+	clock := time.Unix(0, 0)
+	timings.now = func() time.Time { return clock }
+
+	for _, run := range []struct {
+		phase string
+		took  time.Duration
+	}{
+		{phaseNamespaceList, 2 * time.Second},
+		{phaseNamespaceAudit, 100 * time.Millisecond},
+		{phaseNamespaceAudit, 300 * time.Millisecond},
+		{phaseOutput, 5 * time.Millisecond},
+	} {
+		done := timings.track(run.phase)
+		clock = clock.Add(run.took)
+		done()
+	}
+
+	var buf bytes.Buffer
+	timings.print(&buf)
+	var rows []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows = append(rows, strings.Join(fields, " "))
+		}
+	}
+	expected := []string{
+		"=== Phase Timings ===",
+		"PHASE CALLS TOTAL AVERAGE MAX",
+		"namespace list 1 2s 2s 2s",
+		"per-namespace audit 2 400ms 200ms 300ms",
+		"output 1 5ms 5ms 5ms",
+	}
+	if strings.Join(rows, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected timings:\n%s", buf.String())
+	}
+}
+
+// TestPhaseTimingsNil verifies nil timings, as without --debug-profile, record and print nothing.
+func TestPhaseTimingsNil(t *testing.T) {
+	var timings *phaseTimings
+	timings.track(phaseOCMCalls)()
+
+	var buf bytes.Buffer
+	timings.print(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got:\n%s", buf.String())
+	}
+}
+
+// TestDebugProfile verifies the CPU and heap profiles and the runtime trace are written to the run's directory,
+// the phase timings are printed and stopping twice does nothing.
+func TestDebugProfile(t *testing.T) {
+	p := &debugProfile{dir: t.TempDir()}
+	if err := p.start("run-123"); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	p.timings.track(phaseOutput)()
+
+	var buf bytes.Buffer
+	if err := p.stop(&buf); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+	runDir := filepath.Join(p.dir, "run-123")
+	if !strings.Contains(buf.String(), "=== Phase Timings ===") ||
+		!strings.Contains(buf.String(), "Debug profiles written to "+runDir) {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+	for _, name := range []string{"cpu.pprof", "heap.pprof", "trace.out"} {
+		info, err := os.Stat(filepath.Join(runDir, name))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		} else if info.Size() == 0 {
+			t.Errorf("Expected %s not to be empty", name)
+		}
+	}
+	if p.timings != nil {
+		t.Error("Expected the phase timings to be reset")
+	}
+
+	buf.Reset()
+	if err := p.stop(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("Second stop() = %v with output %q, want nothing", err, buf.String())
+	}
+}
+
+// TestDebugProfileDisabled verifies nothing is captured without --debug-profile.
+func TestDebugProfileDisabled(t *testing.T) {
+	p := &debugProfile{}
+	if err := p.start("run-123"); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if p.timings != nil {
+		t.Error("Expected no phase timings without --debug-profile")
+	}
+	var buf bytes.Buffer
+	if err := p.stop(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("stop() = %v with output %q, want nothing", err, buf.String())
+	}
+}
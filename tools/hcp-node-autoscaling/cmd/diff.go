@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// auditDiff describes what changed between a previous audit report, passed with --diff, and the current
+// audit of the same management cluster.
+type auditDiff struct {
+	PreviousGeneratedAt string `json:"previous_generated_at,omitempty" yaml:"previous_generated_at,omitempty"`
+	PreviousRunID       string `json:"previous_run_id,omitempty" yaml:"previous_run_id,omitempty"`
+
+	Added             []diffCluster          `json:"added" yaml:"added"`
+	Removed           []diffCluster          `json:"removed" yaml:"removed"`
+	CategoryChanges   []diffCategoryChange   `json:"category_changes" yaml:"category_changes"`
+	AnnotationChanges []diffAnnotationChange `json:"annotation_changes" yaml:"annotation_changes"`
+}
+
+// diffCluster is a cluster found in only one of the two audits, with its category in that audit.
+type diffCluster struct {
+	ClusterID   string `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	Category    string `json:"category" yaml:"category"`
+}
+
+// diffCategoryChange is a cluster whose category differs between the two audits.
+type diffCategoryChange struct {
+	ClusterID   string `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Previous    string `json:"previous" yaml:"previous"`
+	Current     string `json:"current" yaml:"current"`
+}
+
+// diffAnnotationChange is a migration profile annotation whose value on a cluster differs between the two
+// audits. An empty value means the annotation is not set in that audit.
+type diffAnnotationChange struct {
+	ClusterID   string `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Annotation  string `json:"annotation" yaml:"annotation"`
+	Previous    string `json:"previous" yaml:"previous"`
+	Current     string `json:"current" yaml:"current"`
+}
+
+// empty reports whether the two audits found the same clusters in the same categories with the same annotations.
+func (d *auditDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.CategoryChanges) == 0 && len(d.AnnotationChanges) == 0
+}
+
+// auditedClusters returns the clusters of every category of an audit by cluster ID.
+func auditedClusters(results *auditResults) map[string]hostedClusterAuditInfo {
+	clusters := map[string]hostedClusterAuditInfo{}
+	for _, category := range [][]hostedClusterAuditInfo{results.NeedsLabelRemoval, results.ReadyForMigration,
+		results.AlreadyConfigured, results.Drifted, results.Paused, results.Deleting, results.Unmanaged} {
+		for _, c := range category {
+			clusters[c.ClusterID] = c
+		}
+	}
+	return clusters
+}
+
+// diffAuditResults compares the current audit with a previous report. Annotation changes are limited to
+// keys, the annotations of the migration profile, as other HostedCluster annotations change with routine
+// reconciliation.
+func diffAuditResults(previous, current *auditResults, keys []string) *auditDiff {
+	diff := &auditDiff{
+		PreviousGeneratedAt: previous.GeneratedAt,
+		PreviousRunID:       previous.RunID,
+		Added:               []diffCluster{},
+		Removed:             []diffCluster{},
+		CategoryChanges:     []diffCategoryChange{},
+		AnnotationChanges:   []diffAnnotationChange{},
+	}
+
+	before := auditedClusters(previous)
+	after := auditedClusters(current)
+
+	for id, c := range after {
+		prev, ok := before[id]
+		if !ok {
+			diff.Added = append(diff.Added, diffCluster{ClusterID: id, ClusterName: c.ClusterName, Namespace: c.Namespace, Category: c.Category})
+			continue
+		}
+		if prev.Category != c.Category {
+			diff.CategoryChanges = append(diff.CategoryChanges, diffCategoryChange{ClusterID: id, ClusterName: c.ClusterName,
+				Previous: prev.Category, Current: c.Category})
+		}
+		for _, key := range keys {
+			if prev.Annotations[key] != c.Annotations[key] {
+				diff.AnnotationChanges = append(diff.AnnotationChanges, diffAnnotationChange{ClusterID: id, ClusterName: c.ClusterName,
+					Annotation: key, Previous: prev.Annotations[key], Current: c.Annotations[key]})
+			}
+		}
+	}
+	for id, c := range before {
+		if _, ok := after[id]; !ok {
+			diff.Removed = append(diff.Removed, diffCluster{ClusterID: id, ClusterName: c.ClusterName, Namespace: c.Namespace, Category: c.Category})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].ClusterID < diff.Added[j].ClusterID })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].ClusterID < diff.Removed[j].ClusterID })
+	sort.Slice(diff.CategoryChanges, func(i, j int) bool { return diff.CategoryChanges[i].ClusterID < diff.CategoryChanges[j].ClusterID })
+	sort.Slice(diff.AnnotationChanges, func(i, j int) bool {
+		if diff.AnnotationChanges[i].ClusterID != diff.AnnotationChanges[j].ClusterID {
+			return diff.AnnotationChanges[i].ClusterID < diff.AnnotationChanges[j].ClusterID
+		}
+		return diff.AnnotationChanges[i].Annotation < diff.AnnotationChanges[j].Annotation
+	})
+
+	return diff
+}
+
+// printAuditDiff prints the changes since the previous audit for text, wide and summary output.
+func printAuditDiff(w io.Writer, diff *auditDiff, noHeaders bool) {
+	if diff == nil {
+		return
+	}
+
+	since := diff.PreviousGeneratedAt
+	if since == "" {
+		since = "previous audit"
+	}
+	fmt.Fprintf(w, "\n=== Changes Since %s ===\n", since)
+	if diff.empty() {
+		fmt.Fprintln(w, "No clusters were added, removed, recategorized or had their annotations changed.")
+		return
+	}
+
+	for _, section := range []struct {
+		title    string
+		clusters []diffCluster
+	}{{"New clusters", diff.Added}, {"Removed clusters", diff.Removed}} {
+		if len(section.clusters) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.clusters))
+		p := output.NewTable(w, output.TableMinWidth)
+		if !noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "NAMESPACE", "CATEGORY"})
+		}
+		for _, c := range section.clusters {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.Namespace, c.Category})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	if len(diff.CategoryChanges) > 0 {
+		fmt.Fprintf(w, "Category changes (%d):\n", len(diff.CategoryChanges))
+		p := output.NewTable(w, output.TableMinWidth)
+		if !noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "PREVIOUS", "CURRENT"})
+		}
+		for _, c := range diff.CategoryChanges {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.Previous, c.Current})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	if len(diff.AnnotationChanges) > 0 {
+		fmt.Fprintf(w, "Annotation changes (%d):\n", len(diff.AnnotationChanges))
+		p := output.NewTable(w, output.TableMinWidth)
+		if !noHeaders {
+			p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "PREVIOUS", "CURRENT"})
+		}
+		for _, c := range diff.AnnotationChanges {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, c.Annotation, driftValue(c.Previous), driftValue(c.Current)})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestDiffAuditResults verifies new, removed and recategorized clusters and changes to the profile
+// annotations are reported, and other annotation changes are ignored.
+func TestDiffAuditResults(t *testing.T) {
+	const override = "hypershift.openshift.io/cluster-size-override"
+	previous := &auditResults{
+		GeneratedAt: "2026-10-01T00:00:00Z",
+		RunID:       "run-1",
+		NeedsLabelRemoval: []hostedClusterAuditInfo{
+			{ClusterID: "a", ClusterName: "cluster-a", Category: "needs-removal", Annotations: map[string]string{override: "large"}},
+			{ClusterID: "b", ClusterName: "cluster-b", Category: "needs-removal", Annotations: map[string]string{override: "small"}},
+		},
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "c", ClusterName: "cluster-c", Namespace: "ocm-production-c", Category: "ready-for-migration"},
+			{ClusterID: "d", ClusterName: "cluster-d", Category: "ready-for-migration", Annotations: map[string]string{"other": "1"}},
+		},
+	}
+	current := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{
+			{ClusterID: "b", ClusterName: "cluster-b", Category: "needs-removal", Annotations: map[string]string{override: "medium"}},
+		},
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "a", ClusterName: "cluster-a", Category: "ready-for-migration"},
+			{ClusterID: "d", ClusterName: "cluster-d", Category: "ready-for-migration", Annotations: map[string]string{"other": "2"}},
+		},
+		Paused: []hostedClusterAuditInfo{
+			{ClusterID: "e", ClusterName: "cluster-e", Namespace: "ocm-production-e", Category: "paused"},
+		},
+	}
+
+	diff := diffAuditResults(previous, current, []string{override})
+
+	if diff.PreviousGeneratedAt != "2026-10-01T00:00:00Z" || diff.PreviousRunID != "run-1" {
+		t.Errorf("Unexpected previous report: %q %q", diff.PreviousGeneratedAt, diff.PreviousRunID)
+	}
+	if len(diff.Added) != 1 || diff.Added[0] != (diffCluster{ClusterID: "e", ClusterName: "cluster-e", Namespace: "ocm-production-e", Category: "paused"}) {
+		t.Errorf("Added = %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ClusterID != "c" || diff.Removed[0].Category != "ready-for-migration" {
+		t.Errorf("Removed = %+v", diff.Removed)
+	}
+	if len(diff.CategoryChanges) != 1 || diff.CategoryChanges[0] != (diffCategoryChange{ClusterID: "a", ClusterName: "cluster-a",
+		Previous: "needs-removal", Current: "ready-for-migration"}) {
+		t.Errorf("CategoryChanges = %+v", diff.CategoryChanges)
+	}
+	want := []diffAnnotationChange{
+		{ClusterID: "a", ClusterName: "cluster-a", Annotation: override, Previous: "large"},
+		{ClusterID: "b", ClusterName: "cluster-b", Annotation: override, Previous: "small", Current: "medium"},
+	}
+	if len(diff.AnnotationChanges) != len(want) {
+		t.Fatalf("AnnotationChanges = %+v", diff.AnnotationChanges)
+	}
+	for i := range want {
+		if diff.AnnotationChanges[i] != want[i] {
+			t.Errorf("AnnotationChanges[%d] = %+v, want %+v", i, diff.AnnotationChanges[i], want[i])
+		}
+	}
+}
+
+// TestPrintAuditDiff verifies the changes are printed by section and an unchanged audit is reported as such.
+func TestPrintAuditDiff(t *testing.T) {
+	var buf bytes.Buffer
+	printAuditDiff(&buf, &auditDiff{
+		PreviousGeneratedAt: "2026-10-01T00:00:00Z",
+		Added:               []diffCluster{{ClusterID: "e", ClusterName: "cluster-e", Category: "paused"}},
+		AnnotationChanges:   []diffAnnotationChange{{ClusterID: "a", ClusterName: "cluster-a", Annotation: "size", Previous: "large"}},
+	}, false)
+	out := buf.String()
+	for _, want := range []string{"=== Changes Since 2026-10-01T00:00:00Z ===", "New clusters (1):", "Annotation changes (1):", "<unset>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Removed clusters") || strings.Contains(out, "Category changes") {
+		t.Errorf("Expected empty sections to be omitted, got:\n%s", out)
+	}
+
+	buf.Reset()
+	printAuditDiff(&buf, diffAuditResults(&auditResults{}, &auditResults{}, nil), false)
+	if !strings.Contains(buf.String(), "No clusters were added, removed, recategorized") {
+		t.Errorf("Expected an unchanged audit to be reported, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printAuditDiff(&buf, nil, false)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output without --diff, got %q", buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+)
+
+// directPatchStrategy is the patch strategy recorded in the run history of a --direct migration.
+const directPatchStrategy = "direct"
+
+// patchHostedClusterDirect applies the migration profile annotations straight to the live HostedCluster
+// on the management cluster, bypassing its ManifestWork. It is the break-glass path for when the work
+// agent is broken. Conflicting updates are retried like ManifestWork updates, and the number of conflict
+// retries is returned.
+func (m *migrateOpts) patchHostedClusterDirect(ctx context.Context, info hostedClusterAuditInfo) (int, error) {
+	attempts := 0
+	err := retry.RetryOnConflict(conflictBackoff(m.conflictRetries), func() error {
+		attempts++
+		if attempts > 1 {
+			slog.Info("Retrying HostedCluster update after conflict", "clusterID", info.ClusterID, "retry", attempts-1)
+		}
+
+		return withPhaseTimeout(ctx, m.timeouts.manifestWork, "updating HostedCluster", "cluster "+info.ClusterID, func(ctx context.Context) error {
+			hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+			if err != nil {
+				return fmt.Errorf("failed to get HostedCluster: %w", err)
+			}
+
+			profile := m.patchProfile(info.ClusterID).orDefault()
+			if hc.Annotations == nil {
+				hc.Annotations = map[string]string{}
+			}
+			for key, value := range profile.Ensure {
+				hc.Annotations[key] = value
+			}
+			for _, key := range profile.Remove {
+				delete(hc.Annotations, key)
+			}
+
+			if err := m.mgmtClient.Update(ctx, hc); err != nil {
+				if apierrors.IsConflict(err) {
+					return err
+				}
+				return fmt.Errorf("failed to update HostedCluster: %w", err)
+			}
+			return nil
+		})
+	})
+	retries := attempts - 1
+
+	if apierrors.IsConflict(err) {
+		return retries, fmt.Errorf("failed to update HostedCluster after %d conflict retries: %w", retries, err)
+	}
+	return retries, err
+}
+
+// printDirectWarning warns before confirmation that --direct bypasses the ManifestWorks.
+func printDirectWarning(w io.Writer) {
+	fmt.Fprintln(w, "WARNING: --direct patches the HostedClusters on the management cluster with elevated permissions,")
+	fmt.Fprintln(w, "bypassing their ManifestWorks. The ManifestWorks will be out of sync and must be patched as a")
+	fmt.Fprintln(w, "follow-up, or the work agent will revert the annotations once it reconciles again.")
+	fmt.Fprintln(w)
+}
+
+// printDirectFollowUp lists the clusters patched with --direct whose ManifestWork still needs the same change.
+func printDirectFollowUp(w io.Writer, results []migrationResult, mgmtClusterID string) {
+	var patched []migrationResult
+	for _, r := range results {
+		if r.Status == "success" {
+			patched = append(patched, r)
+		}
+	}
+	if len(patched) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "⚠ Follow-up required: the ManifestWorks of %d clusters are out of sync:\n", len(patched))
+	for _, r := range patched {
+		fmt.Fprintf(w, "  - %s (%s)\n", r.ClusterName, r.ClusterID)
+	}
+	fmt.Fprintln(w, "Once the work agent is healthy, find them with:")
+	fmt.Fprintf(w, "  hcp-node-autoscaling audit --mgmt-cluster-id %s --check-drift --show-only drifted\n", mgmtClusterID)
+	fmt.Fprintln(w, "and apply the same annotations to their ManifestWorks.")
+	fmt.Fprintln(w)
+}
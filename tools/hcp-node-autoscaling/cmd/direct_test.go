@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestPatchHostedClusterDirect verifies the profile annotations are applied to the HostedCluster on the
+// management cluster and that conflicting updates are retried within the budget.
+func TestPatchHostedClusterDirect(t *testing.T) {
+	tests := []struct {
+		name            string
+		conflicts       int
+		expectError     bool
+		expectedRetries int
+	}{
+		{name: "no conflicts"},
+		{name: "succeeds after conflicts", conflicts: 2, expectedRetries: 2},
+		{name: "fails when conflicts exhaust the budget", conflicts: 5, expectError: true, expectedRetries: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := newTestHostedCluster("a1", map[string]string{
+				"hypershift.openshift.io/cluster-size-override": "m5xl",
+				"example.com/unrelated":                         "keep",
+			})
+
+			conflictsLeft := tt.conflicts
+			mgmtClient := fake.NewClientBuilder().
+				WithScheme(testScheme(t)).
+				WithObjects(hc).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if conflictsLeft > 0 {
+							conflictsLeft--
+							return apierrors.NewConflict(schema.GroupResource{Group: "hypershift.openshift.io", Resource: "hostedclusters"},
+								obj.GetName(), fmt.Errorf("the object has been modified"))
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			m := &migrateOpts{mgmtClient: mgmtClient, conflictRetries: 3, direct: true}
+			info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace}
+
+			retries, err := m.patchHostedClusterDirect(context.Background(), info)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("patchHostedClusterDirect() error = %v, expectError %v", err, tt.expectError)
+			}
+			if retries != tt.expectedRetries {
+				t.Errorf("Expected %d retries, got %d", tt.expectedRetries, retries)
+			}
+			if tt.expectError {
+				return
+			}
+
+			updated, err := m.getHostedClusterFromMgmt(context.Background(), hc.Namespace, hc.Name)
+			if err != nil {
+				t.Fatalf("Failed to get HostedCluster: %v", err)
+			}
+			if _, ok := updated.Annotations["hypershift.openshift.io/cluster-size-override"]; ok {
+				t.Error("Expected cluster-size-override to be removed")
+			}
+			if updated.Annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+				t.Errorf("Expected autoscaling annotation to be set, got %v", updated.Annotations)
+			}
+			if updated.Annotations["example.com/unrelated"] != "keep" {
+				t.Errorf("Expected unrelated annotations to be kept, got %v", updated.Annotations)
+			}
+		})
+	}
+}
+
+// TestDirectRunHistory verifies a --direct run is recorded with the direct strategy and that migrated
+// clusters are marked as having an out of sync ManifestWork.
+func TestDirectRunHistory(t *testing.T) {
+	m := &migrateOpts{mgmtClusterID: "mgmt-456", patchStrategy: "update", direct: true}
+	h := m.newRunHistory(filepath.Join(t.TempDir(), "history"), time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC))
+	if h.PatchStrategy != directPatchStrategy {
+		t.Errorf("PatchStrategy = %s, want %s", h.PatchStrategy, directPatchStrategy)
+	}
+
+	if err := h.record(hostedClusterAuditInfo{ClusterID: "a1"}, migrationResult{Status: "success"}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := h.record(hostedClusterAuditInfo{ClusterID: "b2"}, migrationResult{Status: "failed"}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range h.Changes {
+		if c.ManifestWorkOutOfSync != (c.ClusterID == "a1") {
+			t.Errorf("Cluster %s: ManifestWorkOutOfSync = %v", c.ClusterID, c.ManifestWorkOutOfSync)
+		}
+	}
+}
+
+// TestPrintDirectFollowUp verifies only successfully patched clusters are listed as needing a ManifestWork patch.
+func TestPrintDirectFollowUp(t *testing.T) {
+	var out bytes.Buffer
+	printDirectFollowUp(&out, []migrationResult{
+		{ClusterID: "a1", ClusterName: "alpha", Status: "success"},
+		{ClusterID: "b2", ClusterName: "beta", Status: "failed"},
+	}, "mgmt-456")
+
+	for _, e := range []string{"ManifestWorks of 1 clusters are out of sync", "alpha (a1)", "--mgmt-cluster-id mgmt-456 --check-drift"} {
+		if !strings.Contains(out.String(), e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "beta") {
+		t.Errorf("Expected failed clusters to be left out, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	printDirectFollowUp(&out, []migrationResult{{ClusterID: "b2", Status: "failed"}}, "mgmt-456")
+	if out.Len() != 0 {
+		t.Errorf("Expected no output without patched clusters, got:\n%s", out.String())
+	}
+}
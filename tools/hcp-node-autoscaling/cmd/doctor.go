@@ -0,0 +1,578 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// workAgentNamespace is the namespace of the klusterlet and its work agent on a management cluster.
+	workAgentNamespace = "open-cluster-management-agent"
+
+	// hypershiftOperatorNamespace, hypershiftOperatorSelector and hypershiftOperatorContainer select the
+	// HyperShift operator pods whose logs doctor searches.
+	hypershiftOperatorNamespace = "hypershift"
+	hypershiftOperatorSelector  = "app=operator"
+	hypershiftOperatorContainer = "operator"
+
+	// workAgentRestartThreshold is the number of container restarts from which a running work agent pod
+	// is reported as unstable.
+	workAgentRestartThreshold = 5
+
+	// maxLogSnippetLines is the number of HyperShift operator log lines about the cluster that are shown.
+	maxLogSnippetLines = 10
+
+	defaultDoctorEventsSince = time.Hour
+	defaultDoctorLogLines    = 2000
+)
+
+// Likelihoods of a cause found by doctor, from the most to the least likely. Causes are listed in this order.
+const (
+	likelihoodCertain  = "certain"
+	likelihoodLikely   = "likely"
+	likelihoodPossible = "possible"
+)
+
+var likelihoodRanks = map[string]int{likelihoodCertain: 0, likelihoodLikely: 1, likelihoodPossible: 2}
+
+// doctorCause is a likely cause of an annotation sync failure, with what points at it and what to do.
+type doctorCause struct {
+	Likelihood string
+	Cause      string
+	Evidence   string
+	Fix        string
+}
+
+// doctorOpts holds the options of the doctor command. Clusters are read with migrate's options and
+// clients, without elevation.
+type doctorOpts struct {
+	clusterID   string
+	eventsSince time.Duration
+	logLines    int64
+	migrate     migrateOpts
+}
+
+// doctorFindings is what doctor collected about a cluster. Fields are left empty when they could not be
+// read, with the error in the matching Err field.
+type doctorFindings struct {
+	target      *hostedClusterAuditInfo
+	targetErr   error
+	work        *workv1.ManifestWork
+	workErr     error
+	agentPods   []corev1.Pod
+	agentErr    error
+	warnings    []corev1.Event
+	eventsErr   error
+	logLines    []string
+	logsErr     error
+	profileKeys []string
+}
+
+// NewDoctorCmd creates the doctor subcommand diagnosing why annotations do not sync to a HostedCluster.
+func NewDoctorCmd() *cobra.Command {
+	opts := &doctorOpts{}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose why the annotations of a cluster do not sync to its HostedCluster",
+		Long: `Diagnose why the annotations patched into a cluster's ManifestWork do not reach its HostedCluster,
+e.g. after a migration timed out waiting for sync.
+
+doctor collects, without elevation:
+- The ManifestWork status conditions on the service cluster
+- The annotations of the ManifestWork and of the live HostedCluster on the management cluster
+- The status of the work agent pods on the management cluster
+- Recent warning events in the cluster's OCM namespace
+- HyperShift operator log lines about the cluster
+
+and prints them followed by a ranked list of likely causes, with how to fix each.`,
+		Example: `
+  # Diagnose a cluster whose migration timed out
+  hcp-node-autoscaling doctor --cluster-id 2abc123def456 --mgmt-cluster-id mgmt-456
+
+  # Look further back for events and in more operator logs
+  hcp-node-autoscaling doctor --cluster-id 2abc123def456 --mgmt-cluster-id mgmt-456 --events-since 6h --log-lines 10000`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.clusterID, "cluster-id", "", "The ID of the hosted cluster to diagnose")
+	cmd.Flags().StringVar(&opts.migrate.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID hosting the cluster")
+	cmd.Flags().StringVar(&opts.migrate.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	cmd.Flags().DurationVar(&opts.eventsSince, "events-since", defaultDoctorEventsSince,
+		"How far back to look for warning events in the cluster's OCM namespace")
+	cmd.Flags().Int64Var(&opts.logLines, "log-lines", defaultDoctorLogLines,
+		"Number of recent HyperShift operator log lines to search for the cluster")
+	cmd.Flags().StringVar(&opts.migrate.profilePath, "profile", "",
+		"Migration profile YAML declaring the annotations to compare (default built-in profile)")
+	opts.migrate.kubeconfigs.addFlags(cmd, true)
+	_ = cmd.MarkFlagRequired("cluster-id")
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	opts.migrate.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// validate checks the cluster IDs and the lookback settings.
+func (d *doctorOpts) validate() error {
+	if err := utils.IsValidClusterKey(d.clusterID); err != nil {
+		return fmt.Errorf("invalid cluster ID: %v", err)
+	}
+	if err := utils.IsValidClusterKey(d.migrate.mgmtClusterID); err != nil {
+		return err
+	}
+	if d.migrate.serviceClusterID != "" {
+		if err := utils.IsValidClusterKey(d.migrate.serviceClusterID); err != nil {
+			return fmt.Errorf("invalid service cluster ID: %v", err)
+		}
+	}
+	if d.eventsSince <= 0 {
+		return fmt.Errorf("invalid events since %v: must be positive", d.eventsSince)
+	}
+	if d.logLines < 0 {
+		return fmt.Errorf("invalid log lines %d: must not be negative", d.logLines)
+	}
+	return d.migrate.kubeconfigs.validate()
+}
+
+// run collects the findings about the cluster, and prints them with the likely causes.
+func (d *doctorOpts) run(ctx context.Context) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	profile, err := loadProfile(d.migrate.profilePath)
+	if err != nil {
+		return err
+	}
+	d.migrate.profile = profile
+	if err := d.connect(); err != nil {
+		return err
+	}
+	defer d.migrate.ocmConn.Close()
+
+	findings := d.collect(ctx)
+	printDoctorFindings(os.Stdout, d.migrate.session.runID(), d.clusterID, findings)
+	printDoctorCauses(os.Stdout, diagnose(findings))
+	return nil
+}
+
+// connect resolves the management and service cluster and creates clients with the operator's own
+// permissions, since doctor only reads.
+func (d *doctorOpts) connect() error {
+	m := &d.migrate
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	m.ocmConn = conn
+
+	mgmtCluster, err := utils.GetCluster(conn, m.mgmtClusterID)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to get management cluster: %v", err)
+	}
+	m.mgmtClusterID = mgmtCluster.ID()
+	m.mgmtClusterName = mgmtCluster.Name()
+
+	serviceCluster, err := resolveServiceCluster(conn, nil, m.serviceClusterID, mgmtCluster.Name())
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	m.serviceClusterID = serviceCluster.ID()
+
+	clientScheme, err := scheme.Cached(scheme.HyperShift, scheme.Core, scheme.Work)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	clients := m.kubeconfigs.clients(m.clients, m.session.apiRateLimit(), m.mgmtClusterID, m.serviceClusterID)
+	if m.mgmtClient, err = clients.NewClient(m.mgmtClusterID, clientScheme); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	if m.serviceClient, err = clients.NewClient(m.serviceClusterID, clientScheme); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create service cluster client: %v", err)
+	}
+	m.clients = clients
+	return nil
+}
+
+// collect reads everything doctor diagnoses from. A read that fails is recorded in the findings and the
+// others still run, since a failing read is itself a clue.
+func (d *doctorOpts) collect(ctx context.Context) *doctorFindings {
+	m := &d.migrate
+	f := &doctorFindings{profileKeys: m.profile.annotationKeys()}
+
+	f.target, f.targetErr = m.getAnnotateTarget(ctx, d.clusterID)
+	f.work, f.workErr = m.getManifestWork(ctx, d.clusterID)
+
+	pods := &corev1.PodList{}
+	if err := m.mgmtClient.List(ctx, pods, client.InNamespace(workAgentNamespace)); err != nil {
+		f.agentErr = fmt.Errorf("failed to list work agent pods: %v", err)
+	} else {
+		f.agentPods = pods.Items
+	}
+
+	if f.target != nil {
+		events := &corev1.EventList{}
+		if err := m.mgmtClient.List(ctx, events, client.InNamespace(f.target.Namespace)); err != nil {
+			f.eventsErr = fmt.Errorf("failed to list events in namespace %s: %v", f.target.Namespace, err)
+		} else {
+			f.warnings = recentWarnings(events.Items, time.Now(), d.eventsSince)
+		}
+		f.logLines, f.logsErr = d.operatorLogLines(ctx, *f.target)
+	}
+	return f
+}
+
+// operatorLogLines returns the recent HyperShift operator log lines that mention the cluster's namespace
+// or name.
+func (d *doctorOpts) operatorLogLines(ctx context.Context, target hostedClusterAuditInfo) ([]string, error) {
+	if d.logLines == 0 {
+		return nil, nil
+	}
+	clientset, err := clientfactory.NewClientset(d.migrate.clients, d.migrate.mgmtClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management cluster clientset: %v", err)
+	}
+	pods, err := clientset.CoreV1().Pods(hypershiftOperatorNamespace).List(ctx, metav1.ListOptions{LabelSelector: hypershiftOperatorSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HyperShift operator pods: %v", err)
+	}
+
+	var lines []string
+	for _, pod := range pods.Items {
+		tail := d.logLines
+		logs, err := clientset.CoreV1().Pods(pod.Namespace).
+			GetLogs(pod.Name, &corev1.PodLogOptions{Container: hypershiftOperatorContainer, TailLines: &tail}).
+			Stream(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read logs of HyperShift operator pod %s: %v", pod.Name, err)
+		}
+		lines = append(lines, clusterLogLines(logs, target)...)
+		logs.Close()
+	}
+	slog.Debug("Searched HyperShift operator logs", "pods", len(pods.Items), "matches", len(lines))
+	return lines, nil
+}
+
+// clusterLogLines returns the log lines that mention the namespace or name of the cluster.
+func clusterLogLines(logs io.Reader, target hostedClusterAuditInfo) []string {
+	var lines []string
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, target.Namespace) || (target.ClusterName != "" && strings.Contains(line, `"`+target.ClusterName+`"`)) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// diagnose returns the likely causes pointed at by the findings, most likely first.
+func diagnose(f *doctorFindings) []doctorCause {
+	var causes []doctorCause
+	causes = append(causes, diagnoseManifestWork(f)...)
+	causes = append(causes, diagnoseWorkAgent(f.agentPods, f.agentErr)...)
+	causes = append(causes, diagnoseEvents(f.warnings)...)
+	causes = append(causes, diagnoseOperatorLogs(f.logLines)...)
+	sort.SliceStable(causes, func(i, j int) bool {
+		return likelihoodRanks[causes[i].Likelihood] < likelihoodRanks[causes[j].Likelihood]
+	})
+	return causes
+}
+
+// diagnoseManifestWork finds causes in the ManifestWork status and in how its annotations compare with the
+// live HostedCluster.
+func diagnoseManifestWork(f *doctorFindings) []doctorCause {
+	var causes []doctorCause
+	if f.targetErr != nil {
+		causes = append(causes, doctorCause{likelihoodCertain, "The HostedCluster is not on the management cluster",
+			f.targetErr.Error(), "Check the cluster and management cluster IDs; the cluster may have been deleted or moved"})
+	}
+	if f.workErr != nil {
+		causes = append(causes, doctorCause{likelihoodCertain, "The ManifestWork cannot be read on the service cluster",
+			f.workErr.Error(), fixServiceCluster})
+		return causes
+	}
+
+	mw := f.work
+	if reason := workAgentRejection(mw); reason != "" {
+		causes = append(causes, doctorCause{likelihoodCertain, "The work agent failed to apply the ManifestWork", reason,
+			"Fix the error reported by the work agent, e.g. a HostedCluster admission webhook rejecting the update"})
+	}
+	applied := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkApplied)
+	switch {
+	case applied == nil:
+		causes = append(causes, doctorCause{likelihoodLikely, "The work agent never reported the ManifestWork status",
+			"the ManifestWork has no Applied condition",
+			"Check that the work agent on the management cluster is running and connected to the service cluster"})
+	case applied.ObservedGeneration < mw.Generation:
+		causes = append(causes, doctorCause{likelihoodLikely, "The work agent has not picked up the latest ManifestWork",
+			fmt.Sprintf("ManifestWork generation %d, Applied condition observed generation %d", mw.Generation, applied.ObservedGeneration),
+			"Check the work agent pods and their connection to the service cluster; restarting the work agent makes it resync"})
+	}
+	if available := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkAvailable); available != nil && available.Status == metav1.ConditionFalse {
+		causes = append(causes, doctorCause{likelihoodPossible, "The ManifestWork resources are not available",
+			fmt.Sprintf("%s=False (%s): %s", workv1.WorkAvailable, available.Reason, available.Message),
+			"Check the HostedCluster status on the management cluster"})
+	}
+
+	if f.target == nil || !workAppliedForGeneration(mw) {
+		return causes
+	}
+	_, manifestData, err := findHostedClusterManifest(mw)
+	if err != nil {
+		return append(causes, doctorCause{likelihoodCertain, "The ManifestWork has no usable HostedCluster manifest", err.Error(),
+			"Check the ManifestWork; the cluster may not be a ROSA HCP cluster managed by OCM"})
+	}
+	if drift := compareAnnotations(f.profileKeys, manifestAnnotations(manifestData), f.target.Annotations); len(drift) > 0 {
+		var diffs []string
+		for _, d := range drift {
+			diffs = append(diffs, fmt.Sprintf("%s: %s in the ManifestWork, %s on the HostedCluster",
+				d.Annotation, driftValue(d.ManifestWork), driftValue(d.HostedCluster)))
+		}
+		causes = append(causes, doctorCause{likelihoodLikely,
+			"The ManifestWork is applied but the HostedCluster annotations differ, so something reverts or rejects them",
+			strings.Join(diffs, "; "),
+			"Check the HyperShift operator logs and the HostedCluster admission webhooks for changes to these annotations"})
+	}
+	return causes
+}
+
+// workAppliedForGeneration reports whether the work agent applied the current generation of a ManifestWork.
+func workAppliedForGeneration(mw *workv1.ManifestWork) bool {
+	c := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkApplied)
+	return c != nil && c.Status == metav1.ConditionTrue && c.ObservedGeneration >= mw.Generation
+}
+
+// diagnoseWorkAgent finds causes in the status of the work agent pods.
+func diagnoseWorkAgent(pods []corev1.Pod, err error) []doctorCause {
+	const fix = "Check the klusterlet and work agent on the management cluster, e.g. their logs and recent restarts"
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return []doctorCause{{likelihoodPossible, "The work agent pods cannot be read with your permissions", err.Error(), fix}}
+		}
+		return []doctorCause{{likelihoodPossible, "The work agent pods could not be read", err.Error(), fix}}
+	}
+	if len(pods) == 0 {
+		return []doctorCause{{likelihoodCertain, "No work agent is running on the management cluster",
+			fmt.Sprintf("no pods in namespace %s", workAgentNamespace), fix}}
+	}
+
+	var causes []doctorCause
+	for _, pod := range pods {
+		if problem := podProblem(pod); problem != "" {
+			causes = append(causes, doctorCause{likelihoodCertain, "A work agent pod is not healthy",
+				fmt.Sprintf("pod %s: %s", pod.Name, problem), fix})
+		} else if restarts := podRestarts(pod); restarts >= workAgentRestartThreshold {
+			causes = append(causes, doctorCause{likelihoodPossible, "A work agent pod restarts repeatedly",
+				fmt.Sprintf("pod %s restarted %d times", pod.Name, restarts), fix})
+		}
+	}
+	return causes
+}
+
+// podProblem returns why a pod is not running and ready, or "" when it is.
+func podProblem(pod corev1.Pod) string {
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.State.Waiting != nil && s.State.Waiting.Reason != "" {
+			return fmt.Sprintf("container %s is waiting: %s", s.Name, s.State.Waiting.Reason)
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Sprintf("phase %s", pod.Status.Phase)
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status != corev1.ConditionTrue {
+			return "not ready"
+		}
+	}
+	return ""
+}
+
+// podRestarts returns the total restart count of the containers of a pod.
+func podRestarts(pod corev1.Pod) int32 {
+	var restarts int32
+	for _, s := range pod.Status.ContainerStatuses {
+		restarts += s.RestartCount
+	}
+	return restarts
+}
+
+// recentWarnings returns the warning events last seen within since of now, most recent first.
+func recentWarnings(events []corev1.Event, now time.Time, since time.Duration) []corev1.Event {
+	var recent []corev1.Event
+	for _, e := range events {
+		if e.Type == corev1.EventTypeWarning && now.Sub(eventTime(e)) <= since {
+			recent = append(recent, e)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool { return eventTime(recent[i]).After(eventTime(recent[j])) })
+	return recent
+}
+
+// eventTime returns when an event was last seen.
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+// diagnoseEvents finds causes in the recent warning events of the cluster's namespace. Events about
+// admission webhooks are the likely cause of annotations being rejected.
+func diagnoseEvents(warnings []corev1.Event) []doctorCause {
+	if len(warnings) == 0 {
+		return nil
+	}
+	var webhook []string
+	for _, e := range warnings {
+		if message := strings.ToLower(e.Message); strings.Contains(message, "webhook") || strings.Contains(message, "admission") {
+			webhook = append(webhook, fmt.Sprintf("%s: %s", e.Reason, e.Message))
+		}
+	}
+	if len(webhook) > 0 {
+		return []doctorCause{{likelihoodLikely, "An admission webhook rejects changes to the cluster's resources",
+			webhook[0], "Check the validating and mutating webhook configurations on the management cluster"}}
+	}
+	return []doctorCause{{likelihoodPossible, "There are recent warning events in the cluster's namespace",
+		fmt.Sprintf("%d warning events, most recently %s: %s", len(warnings), warnings[0].Reason, warnings[0].Message),
+		"Review the events listed above"}}
+}
+
+// diagnoseOperatorLogs finds causes in the HyperShift operator log lines about the cluster.
+func diagnoseOperatorLogs(lines []string) []doctorCause {
+	var errorLines []string
+	for _, line := range lines {
+		if lower := strings.ToLower(line); strings.Contains(lower, `"error"`) || strings.Contains(lower, "level=error") {
+			errorLines = append(errorLines, line)
+		}
+	}
+	if len(errorLines) == 0 {
+		return nil
+	}
+	return []doctorCause{{likelihoodPossible, "The HyperShift operator reports errors reconciling the cluster",
+		fmt.Sprintf("%d error log lines, most recently: %s", len(errorLines), errorLines[len(errorLines)-1]),
+		"Check the HyperShift operator logs on the management cluster for the HostedCluster"}}
+}
+
+// printDoctorFindings prints what the doctor run with the given ID collected about the cluster.
+func printDoctorFindings(w io.Writer, runID, clusterID string, f *doctorFindings) {
+	name := clusterID
+	if f.target != nil {
+		name = fmt.Sprintf("%s (%s), namespace %s", f.target.ClusterName, clusterID, f.target.Namespace)
+	}
+	fmt.Fprintf(w, "\n=== Doctor: %s ===\n\n", name)
+	printRunID(w, runID)
+
+	switch {
+	case f.workErr != nil:
+		fmt.Fprintf(w, "ManifestWork: %v\n", f.workErr)
+	default:
+		fmt.Fprintf(w, "ManifestWork %s/%s (generation %d): %s\n", f.work.Namespace, f.work.Name, f.work.Generation, workAgentStatus(f.work))
+	}
+	if f.targetErr != nil {
+		fmt.Fprintf(w, "HostedCluster: %v\n", f.targetErr)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Work agent pods (%s):\n", workAgentNamespace)
+	if f.agentErr != nil {
+		fmt.Fprintf(w, "  %v\n", f.agentErr)
+	} else if len(f.agentPods) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"POD", "PHASE", "RESTARTS", "PROBLEM"})
+		for _, pod := range f.agentPods {
+			p.AddRow([]string{pod.Name, string(pod.Status.Phase), strconv.Itoa(int(podRestarts(pod))), driftValue(podProblem(pod))})
+		}
+		p.Flush()
+	}
+	fmt.Fprintln(w)
+
+	if f.target == nil {
+		return
+	}
+	fmt.Fprintf(w, "Recent warning events (%s):\n", f.target.Namespace)
+	if f.eventsErr != nil {
+		fmt.Fprintf(w, "  %v\n", f.eventsErr)
+	} else if len(f.warnings) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"LAST SEEN", "REASON", "OBJECT", "MESSAGE"})
+		for _, e := range f.warnings {
+			p.AddRow([]string{eventTime(e).UTC().Format(time.RFC3339), e.Reason,
+				e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name, e.Message})
+		}
+		p.Flush()
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "HyperShift operator log lines about the cluster:")
+	switch {
+	case f.logsErr != nil:
+		fmt.Fprintf(w, "  %v\n", f.logsErr)
+	case len(f.logLines) == 0:
+		fmt.Fprintln(w, "  none")
+	default:
+		lines := f.logLines
+		if len(lines) > maxLogSnippetLines {
+			fmt.Fprintf(w, "  (last %d of %d)\n", maxLogSnippetLines, len(lines))
+			lines = lines[len(lines)-maxLogSnippetLines:]
+		}
+		for _, line := range lines {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// printDoctorCauses prints the likely causes, most likely first.
+func printDoctorCauses(w io.Writer, causes []doctorCause) {
+	fmt.Fprintf(w, "=== Likely Causes ===\n\n")
+	if len(causes) == 0 {
+		fmt.Fprintln(w, "No likely cause found: the ManifestWork is applied and matches the HostedCluster, and the work agent is healthy.")
+		fmt.Fprintln(w, "If a migration timed out, the annotations may have synced since; check with the verify or audit command.")
+		fmt.Fprintln(w)
+		return
+	}
+	for i, c := range causes {
+		fmt.Fprintf(w, "%d. [%s] %s\n", i+1, c.Likelihood, c.Cause)
+		fmt.Fprintf(w, "   Evidence: %s\n", c.Evidence)
+		fmt.Fprintf(w, "   Fix: %s\n", c.Fix)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestAgentPod returns a work agent pod in the given phase, with a container in waiting state when
+// waiting is set.
+func newTestAgentPod(name string, phase corev1.PodPhase, waiting string, restarts int32) corev1.Pod {
+	status := corev1.ContainerStatus{Name: "work-agent", RestartCount: restarts}
+	if waiting != "" {
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waiting}
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: workAgentNamespace},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: []corev1.ContainerStatus{status},
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// withWorkConditions sets the generation and status conditions of a ManifestWork.
+func withWorkConditions(mw *workv1.ManifestWork, generation int64, conditions ...metav1.Condition) *workv1.ManifestWork {
+	mw.Generation = generation
+	mw.Status.Conditions = conditions
+	return mw
+}
+
+// TestDiagnose verifies the likely causes found for each kind of sync failure, most likely first.
+func TestDiagnose(t *testing.T) {
+	healthy := []corev1.Pod{newTestAgentPod("klusterlet-work-agent", corev1.PodRunning, "", 0)}
+	synced := newTestHostedCluster("a1", topologyProfile.Ensure)
+	reverted := newTestHostedCluster("a1", nil)
+	applied := func(generation int64) metav1.Condition {
+		return metav1.Condition{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, ObservedGeneration: generation}
+	}
+	target := func(hcAnnotations map[string]string) *hostedClusterAuditInfo {
+		return &hostedClusterAuditInfo{ClusterID: "a1", ClusterName: "cluster-a1", Namespace: "ocm-production-a1", Annotations: hcAnnotations}
+	}
+
+	tests := []struct {
+		name     string
+		findings func(t *testing.T) *doctorFindings
+		expected []string
+	}{
+		{
+			name: "healthy and synced",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(synced.Annotations), agentPods: healthy,
+					work: withWorkConditions(newTestManifestWork(t, "mgmt-cluster", synced), 2, applied(2))}
+			},
+		},
+		{
+			name: "manifestwork missing",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(nil), agentPods: healthy, workErr: errors.New("ManifestWork mgmt-cluster/a1 not found")}
+			},
+			expected: []string{"certain: The ManifestWork cannot be read on the service cluster"},
+		},
+		{
+			name: "work agent behind and crashing",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(nil),
+					agentPods: []corev1.Pod{newTestAgentPod("klusterlet-work-agent", corev1.PodRunning, "CrashLoopBackOff", 12)},
+					work:      withWorkConditions(newTestManifestWork(t, "mgmt-cluster", synced), 3, applied(2))}
+			},
+			expected: []string{
+				"certain: A work agent pod is not healthy",
+				"likely: The work agent has not picked up the latest ManifestWork",
+			},
+		},
+		{
+			name: "applied but reverted",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(reverted.Annotations), agentPods: healthy,
+					profileKeys: topologyProfile.annotationKeys(),
+					work:        withWorkConditions(newTestManifestWork(t, "mgmt-cluster", synced), 2, applied(2)),
+					logLines:    []string{`{"level":"info","msg":"reconciling","namespace":"ocm-production-a1"}`, `{"level":"error","error":"boom"}`},
+				}
+			},
+			expected: []string{
+				"likely: The ManifestWork is applied but the HostedCluster annotations differ, so something reverts or rejects them",
+				"possible: The HyperShift operator reports errors reconciling the cluster",
+			},
+		},
+		{
+			name: "rejected by a webhook",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(nil), agentPods: healthy,
+					work: withWorkConditions(newTestManifestWork(t, "mgmt-cluster", synced), 2,
+						metav1.Condition{Type: workv1.WorkApplied, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "AppliedManifestWorkFailed", Message: "denied"}),
+					warnings: []corev1.Event{{Type: corev1.EventTypeWarning, Reason: "FailedUpdate", Message: `admission webhook "hostedclusters.hypershift.openshift.io" denied the request`}},
+				}
+			},
+			expected: []string{
+				"certain: The work agent failed to apply the ManifestWork",
+				"likely: An admission webhook rejects changes to the cluster's resources",
+			},
+		},
+		{
+			name: "no work agent",
+			findings: func(t *testing.T) *doctorFindings {
+				return &doctorFindings{target: target(synced.Annotations),
+					work: withWorkConditions(newTestManifestWork(t, "mgmt-cluster", synced), 1)}
+			},
+			expected: []string{
+				"certain: No work agent is running on the management cluster",
+				"likely: The work agent never reported the ManifestWork status",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range diagnose(tt.findings(t)) {
+				got = append(got, c.Likelihood+": "+c.Cause)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("diagnose() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+// TestRecentWarnings verifies only warning events within the lookback are kept, most recent first.
+func TestRecentWarnings(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := func(reason, eventType string, ago time.Duration) corev1.Event {
+		return corev1.Event{Type: eventType, Reason: reason, LastTimestamp: metav1.NewTime(now.Add(-ago))}
+	}
+	events := []corev1.Event{
+		event("Old", corev1.EventTypeWarning, 2*time.Hour),
+		event("Older", corev1.EventTypeWarning, 30*time.Minute),
+		event("Normal", corev1.EventTypeNormal, time.Minute),
+		event("Newest", corev1.EventTypeWarning, time.Minute),
+	}
+
+	var reasons []string
+	for _, e := range recentWarnings(events, now, time.Hour) {
+		reasons = append(reasons, e.Reason)
+	}
+	if got := strings.Join(reasons, ","); got != "Newest,Older" {
+		t.Errorf("recentWarnings() = %s, want Newest,Older", got)
+	}
+}
+
+// TestClusterLogLines verifies only the log lines mentioning the cluster's namespace or quoted name are kept.
+func TestClusterLogLines(t *testing.T) {
+	logs := strings.Join([]string{
+		`{"msg":"reconciling","namespace":"ocm-production-a1"}`,
+		`{"msg":"reconciling","namespace":"ocm-production-b2"}`,
+		`{"msg":"updated","name":"cluster-a1"}`,
+		`{"msg":"updated","name":"cluster-a10"}`,
+	}, "\n")
+
+	lines := clusterLogLines(strings.NewReader(logs), hostedClusterAuditInfo{ClusterName: "cluster-a1", Namespace: "ocm-production-a1"})
+	if len(lines) != 2 || !strings.Contains(lines[0], "ocm-production-a1") || !strings.Contains(lines[1], `"cluster-a1"`) {
+		t.Errorf("clusterLogLines() = %q", lines)
+	}
+}
+
+// TestDoctorCollect verifies doctor reads the ManifestWork, HostedCluster, work agent pods and events and
+// prints them with the likely causes, reporting that operator logs cannot be read without a clientset.
+func TestDoctorCollect(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", nil)
+	desired := newTestHostedCluster("a1", topologyProfile.Ensure)
+	mw := withWorkConditions(newTestManifestWork(t, "mgmt-cluster", desired), 2,
+		metav1.Condition{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, ObservedGeneration: 1})
+	pod := newTestAgentPod("klusterlet-work-agent", corev1.PodRunning, "", 1)
+	warning := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "e1", Namespace: hc.Namespace},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "ReconcileError",
+		Message:        "failed to reconcile",
+		InvolvedObject: corev1.ObjectReference{Kind: "HostedCluster", Name: hc.Name},
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+	}
+
+	d := &doctorOpts{
+		clusterID:   "a1",
+		eventsSince: time.Hour,
+		logLines:    100,
+		migrate: migrateOpts{
+			mgmtClusterID:   "mc-id",
+			mgmtClusterName: "mgmt-cluster",
+			profile:         topologyProfile,
+			mgmtClient:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc, &pod, warning).Build(),
+			serviceClient:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mw).Build(),
+			clients:         &fakeClientFactory{},
+		},
+	}
+
+	findings := d.collect(context.Background())
+	if findings.targetErr != nil || findings.workErr != nil || findings.agentErr != nil || findings.eventsErr != nil {
+		t.Fatalf("Unexpected errors: %v, %v, %v, %v", findings.targetErr, findings.workErr, findings.agentErr, findings.eventsErr)
+	}
+	if len(findings.agentPods) != 1 || len(findings.warnings) != 1 {
+		t.Errorf("Expected 1 work agent pod and 1 warning event, got %d and %d", len(findings.agentPods), len(findings.warnings))
+	}
+	if findings.logsErr == nil || !strings.Contains(findings.logsErr.Error(), "cannot create clientsets") {
+		t.Errorf("Expected operator logs to fail without a clientset, got %v", findings.logsErr)
+	}
+
+	var buf bytes.Buffer
+	printDoctorFindings(&buf, "", d.clusterID, findings)
+	printDoctorCauses(&buf, diagnose(findings))
+	for _, expected := range []string{
+		"=== Doctor: cluster-a1 (a1), namespace ocm-production-a1 ===",
+		"ManifestWork mgmt-cluster/a1 (generation 2): ManifestWork Applied=True, Available=Unknown",
+		"klusterlet-work-agent",
+		"ReconcileError",
+		"1. [likely] The work agent has not picked up the latest ManifestWork",
+		"2. [possible] There are recent warning events in the cluster's namespace",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,622 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultDrainWaveSize        = 5
+	defaultDrainWaveInterval    = 10 * time.Minute
+	defaultMaxPressureWait      = 30 * time.Minute
+	defaultPressurePollInterval = 30 * time.Second
+	defaultDrainElevationReason = "Removing hosted cluster size overrides"
+
+	// hostedControlPlaneLabel is set on the pods of every hosted control plane.
+	hostedControlPlaneLabel = "hypershift.openshift.io/hosted-control-plane"
+
+	// drainPending is the status of a cluster whose wave has not run yet.
+	drainPending = "pending"
+)
+
+// drainOverrideProfile removes the size override without changing any other annotation.
+var drainOverrideProfile = &migrationProfile{
+	Name:   "drain-override",
+	Ensure: map[string]string{},
+	Remove: []string{sizeOverrideAnnotation},
+}
+
+// drainOverrideOpts holds the options of the drain-override command. Overrides are removed with migrate's
+// options and the drainOverrideProfile, one wave of clusters at a time.
+type drainOverrideOpts struct {
+	waveSize         int
+	waveInterval     time.Duration
+	stateFile        string
+	pause            bool
+	maxUnschedulable int
+	maxPressureWait  time.Duration
+	pressurePoll     time.Duration
+	migrate          migrateOpts
+	state            *drainState
+
+	// unschedulablePods counts the unschedulable hosted control plane pods, defaulting to
+	// migrateOpts.unschedulableControlPlanePods.
+	unschedulablePods func(ctx context.Context) (int, error)
+}
+
+// drainState is the progress of a drain, saved to --state-file after every wave so a paused or interrupted
+// drain can be resumed by running the command again with the same file.
+type drainState struct {
+	MgmtClusterID string              `json:"mgmt_cluster_id"`
+	Paused        bool                `json:"paused"`
+	UpdatedAt     string              `json:"updated_at"`
+	Clusters      []drainClusterState `json:"clusters"`
+}
+
+// drainClusterState is the progress of a single cluster of a drain. Status is pending until the cluster's
+// wave ran, then the status of its migration result.
+type drainClusterState struct {
+	ClusterID    string `json:"cluster_id"`
+	ClusterName  string `json:"cluster_name"`
+	Namespace    string `json:"namespace"`
+	CurrentSize  string `json:"current_size,omitempty"`
+	SizeOverride string `json:"size_override,omitempty"`
+	Status       string `json:"status"`
+	Wave         int    `json:"wave,omitempty"`
+	Error        string `json:"error,omitempty"`
+	RunID        string `json:"run_id,omitempty"`
+}
+
+// remaining reports whether the cluster still needs its override removed: it is pending, or its last
+// attempt failed or was interrupted.
+func (c drainClusterState) remaining() bool {
+	return c.Status == drainPending || c.Status == "failed" || c.Status == "interrupted"
+}
+
+// auditInfo returns the migration target of the cluster.
+func (c drainClusterState) auditInfo() hostedClusterAuditInfo {
+	return hostedClusterAuditInfo{
+		ClusterID:    c.ClusterID,
+		ClusterName:  c.ClusterName,
+		Namespace:    c.Namespace,
+		CurrentSize:  c.CurrentSize,
+		Category:     "needs-removal",
+		SizeOverride: c.SizeOverride,
+	}
+}
+
+// remaining returns the clusters of the drain that still need their override removed.
+func (s *drainState) remaining() []hostedClusterAuditInfo {
+	var remaining []hostedClusterAuditInfo
+	for _, c := range s.Clusters {
+		if c.remaining() {
+			remaining = append(remaining, c.auditInfo())
+		}
+	}
+	return remaining
+}
+
+// record stores the results of a wave drained by the run with the given ID.
+func (s *drainState) record(runID string, wave int, results []migrationResult) {
+	byID := make(map[string]migrationResult, len(results))
+	for _, r := range results {
+		byID[r.ClusterID] = r
+	}
+	for i := range s.Clusters {
+		r, ok := byID[s.Clusters[i].ClusterID]
+		if !ok {
+			continue
+		}
+		s.Clusters[i].Status = r.Status
+		s.Clusters[i].Wave = wave
+		s.Clusters[i].Error = r.Error
+		s.Clusters[i].RunID = runID
+	}
+}
+
+// lastWave returns the highest wave number recorded in the state.
+func (s *drainState) lastWave() int {
+	last := 0
+	for _, c := range s.Clusters {
+		last = max(last, c.Wave)
+	}
+	return last
+}
+
+// newDrainState returns the state of a new drain of candidates, sorted by cluster ID.
+func newDrainState(mgmtClusterID string, candidates []hostedClusterAuditInfo) *drainState {
+	state := &drainState{MgmtClusterID: mgmtClusterID}
+	for _, c := range candidates {
+		state.Clusters = append(state.Clusters, drainClusterState{
+			ClusterID:    c.ClusterID,
+			ClusterName:  c.ClusterName,
+			Namespace:    c.Namespace,
+			CurrentSize:  c.CurrentSize,
+			SizeOverride: c.SizeOverride,
+			Status:       drainPending,
+		})
+	}
+	sort.Slice(state.Clusters, func(i, j int) bool {
+		return state.Clusters[i].ClusterID < state.Clusters[j].ClusterID
+	})
+	return state
+}
+
+// loadDrainState reads a drain state file. It returns nil without an error when the file does not exist.
+func loadDrainState(path string) (*drainState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	state := &drainState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// saveDrainState atomically writes the drain state file.
+func saveDrainState(path string, state *drainState) error {
+	state.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	err := output.WriteFile(path, false, func(w io.Writer, _ bool) error {
+		return output.JSON(w, state)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	return nil
+}
+
+// drainWaves splits clusters into waves of at most size clusters.
+func drainWaves(clusters []hostedClusterAuditInfo, size int) [][]hostedClusterAuditInfo {
+	var waves [][]hostedClusterAuditInfo
+	for start := 0; start < len(clusters); start += size {
+		waves = append(waves, clusters[start:min(start+size, len(clusters))])
+	}
+	return waves
+}
+
+// NewDrainOverrideCmd creates the drain-override subcommand that removes size overrides in rate-limited waves.
+func NewDrainOverrideCmd() *cobra.Command {
+	opts := &drainOverrideOpts{
+		pressurePoll: defaultPressurePollInterval,
+		migrate: migrateOpts{
+			environment:   "production",
+			patchStrategy: "json-patch",
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "drain-override",
+		Short: "Remove cluster size overrides from Group A clusters in rate-limited waves",
+		Long: `Remove the cluster-size-override annotation from the clusters that need annotation removal
+(Group A) in waves of --wave-size clusters, --wave-interval apart, so the resulting resizes do not all
+hit the management cluster at once.
+
+Before each wave the hosted control plane pods of the management cluster are checked for pods the
+scheduler cannot place, and the next wave waits until at most --max-unschedulable-pods remain.
+
+Progress is saved to --state-file after every wave. Run the command with --pause and the same state
+file to stop a running drain after its current wave, and run it again without --pause to resume with
+the clusters that are left.`,
+		Example: `
+  # Preview the waves
+  hcp-node-autoscaling drain-override --mgmt-cluster-id mgmt-456 --state-file drain-mgmt-456.json --dry-run
+
+  # Remove overrides from 3 clusters every 15 minutes
+  hcp-node-autoscaling drain-override \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-id mgmt-456 \
+    --state-file drain-mgmt-456.json \
+    --wave-size 3 \
+    --wave-interval 15m
+
+  # Pause the drain after its current wave; run the command above again to resume
+  hcp-node-autoscaling drain-override --mgmt-cluster-id mgmt-456 --state-file drain-mgmt-456.json --pause`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.migrate.mgmtClusterID, "mgmt-cluster-id", "",
+		"The management cluster ID whose size overrides are removed")
+	cmd.Flags().StringVar(&opts.migrate.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	addAllowMismatchFlag(cmd, &opts.migrate.allowMismatch)
+	cmd.Flags().StringVar(&opts.stateFile, "state-file", "",
+		"File the progress of the drain is saved to and resumed from")
+	cmd.Flags().BoolVar(&opts.pause, "pause", false,
+		"Pause the drain of --state-file after its current wave and exit")
+	cmd.Flags().IntVar(&opts.waveSize, "wave-size", defaultDrainWaveSize,
+		"Number of clusters whose override is removed in each wave")
+	cmd.Flags().DurationVar(&opts.waveInterval, "wave-interval", defaultDrainWaveInterval,
+		"Time to wait between the end of a wave and the start of the next")
+	cmd.Flags().IntVar(&opts.maxUnschedulable, "max-unschedulable-pods", 0,
+		"Start the next wave only when at most this many hosted control plane pods are unschedulable")
+	cmd.Flags().DurationVar(&opts.maxPressureWait, "max-pressure-wait", defaultMaxPressureWait,
+		"Maximum time to wait for scheduling pressure to ease before a wave; the drain stops when it is exceeded")
+	cmd.Flags().StringVar(&opts.migrate.environment, "environment", "production",
+		"OCM environment whose hosted cluster namespaces are scanned: production, staging, all")
+	cmd.Flags().BoolVar(&opts.migrate.dryRun, "dry-run", false,
+		"Show the waves without removing any override")
+	cmd.Flags().BoolVar(&opts.migrate.skipConfirmation, "skip-confirmation", false,
+		"Skip confirmation prompt (use with caution)")
+	addConfirmThresholdFlag(cmd, &opts.migrate.confirmThreshold)
+	cmd.Flags().BoolVar(&opts.migrate.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login, backplane access and ManifestWork permissions before the drain")
+	cmd.Flags().BoolVar(&opts.migrate.ignoreFreeze, "ignore-freeze", false,
+		"Drain clusters that are upgrading, have a control plane upgrade scheduled or are in limited support")
+	cmd.Flags().DurationVar(&opts.migrate.syncTimeout, "sync-timeout", defaultSyncTimeout,
+		"Maximum time to wait for the override removal to sync to the management cluster")
+	cmd.Flags().DurationVar(&opts.migrate.pollInterval, "poll-interval", defaultPollInterval,
+		"Interval between sync verification attempts")
+	cmd.Flags().IntVar(&opts.migrate.conflictRetries, "conflict-retries", defaultConflictRetries,
+		"Number of times to retry a ManifestWork update that fails with a conflict")
+	cmd.Flags().IntVar(&opts.migrate.maxRetries, "max-retries", defaultMaxRetries,
+		"Number of times to remove a cluster's override again after a transient failure (API timeout or exhausted conflict retries)")
+	cmd.Flags().StringVar(&opts.migrate.ticket, "ticket", "",
+		"JIRA issue approving the change, e.g. OHSS-12345 (required unless --dry-run or --pause)")
+	cmd.Flags().StringVar(&opts.migrate.ticket, "reason", "", "Alias of --ticket")
+	cmd.Flags().StringVar(&opts.migrate.elevationReason, "elevation-reason", defaultDrainElevationReason,
+		"Description of the change recorded after the ticket on the backplane elevation, the run history and service log entries")
+	cmd.Flags().BoolVar(&opts.migrate.serviceLog, "service-log", false,
+		"Post an internal OCM service log entry for each cluster once the override removal is verified")
+	cmd.Flags().StringVar(&opts.migrate.historyDir, "history-dir", "",
+		"Directory to write the run history to (default ~/.config/hcp-node-autoscaling/history)")
+	cmd.Flags().StringSliceVar(&opts.migrate.excludeIDs, "exclude-cluster-ids", nil,
+		"Comma-separated cluster IDs whose override must not be removed")
+	cmd.Flags().StringVar(&opts.migrate.excludeFile, "exclude-file", "",
+		"File of cluster IDs whose override must not be removed, one per line")
+	opts.migrate.timeouts.addFlags(cmd, true)
+	opts.migrate.kubeconfigs.addFlags(cmd, true)
+	opts.migrate.listing.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	_ = cmd.MarkFlagRequired("state-file")
+	cmd.MarkFlagsMutuallyExclusive("ticket", "reason")
+	opts.migrate.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// validate checks the wave and scheduling pressure settings.
+func (d *drainOverrideOpts) validate() error {
+	if d.waveSize < 1 || d.waveSize > maxMaxInFlight {
+		return fmt.Errorf("invalid wave size %d: must be between 1 and %d", d.waveSize, maxMaxInFlight)
+	}
+	if d.waveInterval < 0 {
+		return fmt.Errorf("invalid wave interval %v: must not be negative", d.waveInterval)
+	}
+	if d.maxUnschedulable < 0 {
+		return fmt.Errorf("invalid max unschedulable pods %d: must not be negative", d.maxUnschedulable)
+	}
+	if d.maxPressureWait < 0 {
+		return fmt.Errorf("invalid max pressure wait %v: must not be negative", d.maxPressureWait)
+	}
+	if err := validateConfirmThreshold(d.migrate.confirmThreshold); err != nil {
+		return err
+	}
+	return nil
+}
+
+// run pauses the drain of the state file, or removes the overrides of the remaining clusters wave by wave.
+func (d *drainOverrideOpts) run(ctx context.Context) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	if d.pause {
+		return d.pauseDrain(os.Stdout)
+	}
+
+	state, err := loadDrainState(d.stateFile)
+	if err != nil {
+		return err
+	}
+	if state != nil && state.MgmtClusterID != d.migrate.mgmtClusterID {
+		return fmt.Errorf("state file %s belongs to management cluster %s, not %s", d.stateFile, state.MgmtClusterID, d.migrate.mgmtClusterID)
+	}
+
+	m := &d.migrate
+	m.profile = drainOverrideProfile
+	m.maxInFlight = d.waveSize
+	m.serviceLogSummary = "Removed the hosted cluster size override"
+	if err := m.initialize(ctx); err != nil {
+		return fmt.Errorf("initialization failed: %v", err)
+	}
+	defer m.ocmConn.Close()
+	if d.unschedulablePods == nil {
+		d.unschedulablePods = m.unschedulableControlPlanePods
+	}
+
+	if state == nil {
+		candidates, err := m.getOverrideClusters(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return withExitCode(exitInterrupted, fmt.Errorf("failed to get clusters with a size override: %v", err))
+			}
+			return fmt.Errorf("failed to get clusters with a size override: %v", err)
+		}
+		state = newDrainState(m.mgmtClusterID, filterExcluded(candidates, m.exclusions))
+	} else {
+		slog.Info("Resuming drain", "stateFile", d.stateFile, "remaining", len(state.remaining()))
+	}
+	d.state = state
+
+	remaining := filterExcluded(state.remaining(), m.exclusions)
+	remaining, frozen := m.filterFrozen(ctx, remaining)
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking for maintenance and change freezes"))
+	}
+	displayFrozen(m.session.infoOut(), frozen)
+
+	remaining, notReady := m.filterOCMState(ctx, remaining)
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking OCM cluster states"))
+	}
+	displayNotReady(m.session.infoOut(), notReady)
+	m.skipped = append(m.skipped, notReady...)
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(m.session.infoOut(), "No clusters with a size override left to drain")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no clusters with a size override left to drain"))
+	}
+
+	waves := drainWaves(remaining, d.waveSize)
+	if m.dryRun {
+		displayDrainWaves(os.Stdout, m.session.runID(), waves, state.lastWave()+1, d.waveInterval)
+		fmt.Println("[DRY RUN] No changes will be applied")
+		return nil
+	}
+	displayDrainWaves(m.session.infoOut(), m.session.runID(), waves, state.lastWave()+1, d.waveInterval)
+	if !m.skipConfirmation && !m.confirm(os.Stdin, m.session.infoOut(), len(remaining), m.mgmtClusterName) {
+		return fmt.Errorf("drain cancelled by user")
+	}
+
+	state.Paused = false
+	if err := saveDrainState(d.stateFile, state); err != nil {
+		return err
+	}
+
+	m.history = m.newRunHistory(m.historyDir, time.Now())
+	results, notStarted, drainErr := d.drain(ctx, waves, state.lastWave()+1)
+	if err := m.history.finish(); err != nil {
+		slog.Warn("Failed to write run history", "error", err)
+	} else {
+		slog.Info("Wrote run history", "file", m.history.path)
+	}
+
+	m.displayResults(m.session.infoOut(), results, nil)
+	fmt.Fprintf(m.session.infoOut(), "Clusters left to drain: %d (state file %s)\n", len(notStarted), d.stateFile)
+
+	if drainErr != nil {
+		return drainErr
+	}
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted,
+			fmt.Errorf("drain interrupted: %d clusters left; run again with --state-file %s to resume", len(notStarted), d.stateFile))
+	}
+	return migrationExitError(results)
+}
+
+// drain runs the waves, numbered from firstWave, saving the state after each. Before each wave after the
+// first it waits for the wave interval, stops if the drain was paused and waits for scheduling pressure to
+// ease. It returns the results of the waves that ran and the clusters of the waves that did not.
+func (d *drainOverrideOpts) drain(ctx context.Context, waves [][]hostedClusterAuditInfo, firstWave int) ([]migrationResult, []hostedClusterAuditInfo, error) {
+	m := &d.migrate
+	var results []migrationResult
+	notStarted := func(i int) []hostedClusterAuditInfo {
+		var clusters []hostedClusterAuditInfo
+		for _, wave := range waves[i:] {
+			clusters = append(clusters, wave...)
+		}
+		return clusters
+	}
+
+	for i, wave := range waves {
+		number := firstWave + i
+		if i > 0 {
+			slog.Info("Waiting before the next wave", "wave", number, "interval", d.waveInterval)
+			if !sleepContext(ctx, d.waveInterval) {
+				return results, notStarted(i), nil
+			}
+		}
+
+		if paused, err := d.pausedOnDisk(); err != nil {
+			slog.Warn("Failed to check whether the drain was paused", "error", err)
+		} else if paused {
+			fmt.Fprintf(m.session.infoOut(), "Drain paused before wave %d; run again without --pause to resume\n", number)
+			return results, notStarted(i), nil
+		}
+
+		if err := d.waitForSchedulingPressure(ctx); err != nil {
+			if ctx.Err() != nil {
+				return results, notStarted(i), nil
+			}
+			return results, notStarted(i), err
+		}
+
+		slog.Info("Starting wave", "wave", number, "clusters", len(wave))
+		waveResults := m.migrateClusters(ctx, wave)
+		results = append(results, waveResults...)
+		d.state.record(m.session.runID(), number, waveResults)
+		if err := d.saveState(); err != nil {
+			slog.Warn("Failed to save drain state", "error", err)
+		}
+		if ctx.Err() != nil {
+			return results, append(wave[len(waveResults):], notStarted(i+1)...), nil
+		}
+	}
+	return results, nil, nil
+}
+
+// pausedOnDisk reports whether the state file was paused by another run since the drain started, and
+// records the pause in the drain's state so it is kept when the state is saved.
+func (d *drainOverrideOpts) pausedOnDisk() (bool, error) {
+	onDisk, err := loadDrainState(d.stateFile)
+	if err != nil || onDisk == nil {
+		return false, err
+	}
+	d.state.Paused = onDisk.Paused
+	return onDisk.Paused, nil
+}
+
+// saveState saves the drain's state, keeping a pause made by another run since the drain started.
+func (d *drainOverrideOpts) saveState() error {
+	if _, err := d.pausedOnDisk(); err != nil {
+		return err
+	}
+	return saveDrainState(d.stateFile, d.state)
+}
+
+// pauseDrain marks the drain of the state file as paused.
+func (d *drainOverrideOpts) pauseDrain(w io.Writer) error {
+	state, err := loadDrainState(d.stateFile)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("state file %s does not exist", d.stateFile)
+	}
+	if state.MgmtClusterID != d.migrate.mgmtClusterID {
+		return fmt.Errorf("state file %s belongs to management cluster %s, not %s", d.stateFile, state.MgmtClusterID, d.migrate.mgmtClusterID)
+	}
+	state.Paused = true
+	if err := saveDrainState(d.stateFile, state); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Drain of %s paused with %d clusters left; a running drain stops before its next wave\n",
+		state.MgmtClusterID, len(state.remaining()))
+	return nil
+}
+
+// waitForSchedulingPressure waits until at most --max-unschedulable-pods hosted control plane pods are
+// unschedulable, for up to --max-pressure-wait.
+func (d *drainOverrideOpts) waitForSchedulingPressure(ctx context.Context) error {
+	deadline := time.Now().Add(d.maxPressureWait)
+	for {
+		count, err := d.unschedulablePods(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check scheduling pressure: %v", err)
+		}
+		if count <= d.maxUnschedulable {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%d hosted control plane pods still unschedulable after %v; "+
+				"run again with --state-file %s once the management cluster has capacity", count, d.maxPressureWait, d.stateFile)
+		}
+		slog.Warn("Management cluster under scheduling pressure, waiting before the next wave",
+			"unschedulablePods", count, "max", d.maxUnschedulable)
+		if !sleepContext(ctx, d.pressurePoll) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// unschedulableControlPlanePods returns the number of hosted control plane pods on the management cluster
+// that the scheduler could not place.
+func (m *migrateOpts) unschedulableControlPlanePods(ctx context.Context) (int, error) {
+	pods := &corev1.PodList{}
+	if err := m.mgmtClient.List(ctx, pods, client.HasLabels{hostedControlPlaneLabel}); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// getOverrideClusters audits the management cluster for the clusters that need their size override removed.
+func (m *migrateOpts) getOverrideClusters(ctx context.Context) ([]hostedClusterAuditInfo, error) {
+	auditOpts := &auditOpts{
+		mgmtClusterID: m.mgmtClusterID,
+		environment:   m.environment,
+		mgmtClient:    m.mgmtClient,
+		timeouts:      m.timeouts,
+		listing:       m.listing,
+	}
+
+	namespaces, err := auditOpts.listOcmNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []hostedClusterAuditInfo
+	for event := range auditOpts.streamAudit(ctx, namespaces) {
+		if event.Err != nil {
+			slog.Warn("Failed to audit namespace", "namespace", event.Namespace, "error", event.Err)
+			continue
+		}
+		for _, info := range event.Clusters {
+			switch info.Category {
+			case "needs-removal":
+				clusters = append(clusters, info)
+			case "paused":
+				if info.SizeOverride != "" {
+					slog.Info("Skipping paused cluster", "clusterID", info.ClusterID, "reason", info.PausedReason)
+				}
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return clusters, nil
+}
+
+// displayDrainWaves prints the clusters of each wave of the run with the given ID, numbered from firstWave.
+func displayDrainWaves(w io.Writer, runID string, waves [][]hostedClusterAuditInfo, firstWave int, interval time.Duration) {
+	total := 0
+	for _, wave := range waves {
+		total += len(wave)
+	}
+	fmt.Fprintf(w, "\n=== Size Override Drain: %d clusters in %d waves, %s apart ===\n\n", total, len(waves), interval)
+	printRunID(w, runID)
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"WAVE", "CLUSTER ID", "CLUSTER NAME", "CURRENT SIZE", "SIZE OVERRIDE"})
+	for i, wave := range waves {
+		for _, c := range wave {
+			p.AddRow([]string{strconv.Itoa(firstWave + i), c.ClusterID, c.ClusterName, c.CurrentSize, c.SizeOverride})
+		}
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
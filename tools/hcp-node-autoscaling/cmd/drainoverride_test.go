@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestDrainWaves verifies clusters are split into waves of at most the wave size.
+func TestDrainWaves(t *testing.T) {
+	clusters := []hostedClusterAuditInfo{{ClusterID: "a1"}, {ClusterID: "b2"}, {ClusterID: "c3"}, {ClusterID: "d4"}, {ClusterID: "e5"}}
+	waves := drainWaves(clusters, 2)
+	if len(waves) != 3 || len(waves[0]) != 2 || len(waves[2]) != 1 || waves[2][0].ClusterID != "e5" {
+		t.Errorf("drainWaves() = %+v, want waves of 2, 2 and 1 clusters", waves)
+	}
+	if waves := drainWaves(nil, 2); len(waves) != 0 {
+		t.Errorf("Expected no waves without clusters, got %+v", waves)
+	}
+}
+
+// TestDrainStateRemaining verifies successful and skipped clusters are not drained again, while pending,
+// failed and interrupted clusters are.
+func TestDrainStateRemaining(t *testing.T) {
+	state := newDrainState("mgmt-id", []hostedClusterAuditInfo{{ClusterID: "d4"}, {ClusterID: "a1"}, {ClusterID: "c3"}, {ClusterID: "b2"}})
+	state.record("run-123", 1, []migrationResult{{ClusterID: "a1", Status: "success"}, {ClusterID: "b2", Status: "failed", Error: "timeout"}})
+	state.record("run-123", 2, []migrationResult{{ClusterID: "c3", Status: stateChanged}})
+
+	var remaining []string
+	for _, c := range state.remaining() {
+		remaining = append(remaining, c.ClusterID)
+	}
+	if fmt.Sprint(remaining) != "[b2 d4]" {
+		t.Errorf("remaining() = %v, want [b2 d4]", remaining)
+	}
+	if state.lastWave() != 2 {
+		t.Errorf("lastWave() = %d, want 2", state.lastWave())
+	}
+	if c := state.Clusters[1]; c.ClusterID != "b2" || c.Wave != 1 || c.Error != "timeout" || c.RunID != "run-123" {
+		t.Errorf("Unexpected state for b2: %+v", c)
+	}
+}
+
+// TestUnschedulableControlPlanePods verifies only pending hosted control plane pods the scheduler could not
+// place are counted.
+func TestUnschedulableControlPlanePods(t *testing.T) {
+	pod := func(name string, labels map[string]string, phase corev1.PodPhase, reason string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ocm-production-a1-hcp", Labels: labels}}
+		p.Status.Phase = phase
+		if reason != "" {
+			p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: reason}}
+		}
+		return p
+	}
+	controlPlane := map[string]string{hostedControlPlaneLabel: "ocm-production-a1-hcp"}
+	m := &migrateOpts{mgmtClient: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		pod("kube-apiserver-0", controlPlane, corev1.PodPending, corev1.PodReasonUnschedulable),
+		pod("etcd-0", controlPlane, corev1.PodPending, ""),
+		pod("etcd-1", controlPlane, corev1.PodRunning, ""),
+		pod("workload", nil, corev1.PodPending, corev1.PodReasonUnschedulable),
+	).Build()}
+
+	count, err := m.unschedulableControlPlanePods(context.Background())
+	if err != nil || count != 1 {
+		t.Errorf("unschedulableControlPlanePods() = %d, %v, want 1", count, err)
+	}
+}
+
+// TestDrainOverride verifies overrides are removed wave by wave and saved to the state file, that a pause
+// from another run stops the drain before its next wave, and that a resumed drain only handles the clusters
+// that are left.
+func TestDrainOverride(t *testing.T) {
+	scheme := testScheme(t)
+	var hostedClusters []*hypershiftv1beta1.HostedCluster
+	var mgmtObjects, serviceObjects []client.Object
+	for _, id := range []string{"a1", "b2", "c3"} {
+		hc := newTestHostedCluster(id, map[string]string{sizeOverrideAnnotation: "m5xl", "example.com/owner": "team-a"})
+		hostedClusters = append(hostedClusters, hc)
+		mgmtObjects = append(mgmtObjects, newTestNamespace(hc.Namespace), hc.DeepCopy())
+		serviceObjects = append(serviceObjects, newTestManifestWork(t, "mgmt-cluster", hc))
+	}
+	mgmtObjects = append(mgmtObjects, newTestNamespace("ocm-production-d4"), newTestHostedCluster("d4", nil))
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mgmtObjects...).Build()
+	agent := &workAgent{mgmtClient: mgmtClient, delay: 20 * time.Millisecond}
+	t.Cleanup(agent.wait)
+	serviceClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceObjects...).
+		WithInterceptorFuncs(agent.interceptors()).Build()
+
+	stateFile := filepath.Join(t.TempDir(), "drain.json")
+	pressure := []int{0, 2, 0}
+	d := &drainOverrideOpts{
+		waveSize:         2,
+		stateFile:        stateFile,
+		maxUnschedulable: 1,
+		maxPressureWait:  time.Second,
+		pressurePoll:     10 * time.Millisecond,
+		migrate: migrateOpts{
+			mgmtClusterID:   "mgmt-id",
+			mgmtClusterName: "mgmt-cluster",
+			environment:     "production",
+			mgmtClient:      mgmtClient,
+			serviceClient:   serviceClient,
+			profile:         drainOverrideProfile,
+			maxInFlight:     2,
+			patchStrategy:   "json-patch",
+			syncTimeout:     time.Second,
+			pollInterval:    10 * time.Millisecond,
+		},
+	}
+	calls := 0
+	d.unschedulablePods = func(context.Context) (int, error) {
+		count := pressure[min(calls, len(pressure)-1)]
+		calls++
+		return count, nil
+	}
+
+	ctx := context.Background()
+	candidates, err := d.migrate.getOverrideClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("Expected the 3 clusters with an override, got %+v", candidates)
+	}
+	d.state = newDrainState("mgmt-id", candidates)
+
+	// Pause the drain from another run before its first wave.
+	pauser := &drainOverrideOpts{stateFile: stateFile, migrate: migrateOpts{mgmtClusterID: "mgmt-id"}}
+	if err := saveDrainState(stateFile, d.state); err != nil {
+		t.Fatal(err)
+	}
+	if err := pauser.pauseDrain(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	d.state.Paused = false
+
+	results, notStarted, err := d.drain(ctx, drainWaves(d.state.remaining(), 2), 1)
+	if err != nil {
+		t.Fatalf("drain() error = %v", err)
+	}
+	if len(results) != 0 || len(notStarted) != 3 {
+		t.Fatalf("Expected the paused drain to stop before its first wave, got %d results and %d not started", len(results), len(notStarted))
+	}
+
+	// Resume: the first wave runs, the second waits for scheduling pressure to ease.
+	d.state.Paused = false
+	if err := saveDrainState(stateFile, d.state); err != nil {
+		t.Fatal(err)
+	}
+	results, notStarted, err = d.drain(ctx, drainWaves(d.state.remaining(), 2), 1)
+	if err != nil {
+		t.Fatalf("drain() error = %v", err)
+	}
+	if len(results) != 3 || len(notStarted) != 0 || calls != 3 {
+		t.Fatalf("Expected all 3 clusters drained after 3 pressure checks, got %d results, %d not started, %d checks", len(results), len(notStarted), calls)
+	}
+	for _, r := range results {
+		if r.Status != "success" {
+			t.Errorf("Expected %s to succeed, got %s: %s", r.ClusterID, r.Status, r.Error)
+		}
+	}
+
+	saved, err := loadDrainState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.remaining()) != 0 || saved.Clusters[2].Wave != 2 {
+		t.Errorf("Expected every cluster to be drained in 2 waves, got %+v", saved.Clusters)
+	}
+
+	for _, hc := range hostedClusters {
+		synced := &hypershiftv1beta1.HostedCluster{}
+		if err := mgmtClient.Get(ctx, client.ObjectKeyFromObject(hc), synced); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := synced.Annotations[sizeOverrideAnnotation]; ok || synced.Annotations["example.com/owner"] != "team-a" {
+			t.Errorf("Expected only the size override of %s to be removed, got %v", hc.Name, synced.Annotations)
+		}
+	}
+}
+
+// TestWaitForSchedulingPressure verifies the drain stops when pressure does not ease within the maximum wait.
+func TestWaitForSchedulingPressure(t *testing.T) {
+	d := &drainOverrideOpts{
+		stateFile:         "drain.json",
+		maxPressureWait:   30 * time.Millisecond,
+		pressurePoll:      10 * time.Millisecond,
+		unschedulablePods: func(context.Context) (int, error) { return 4, nil },
+	}
+	err := d.waitForSchedulingPressure(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "4 hosted control plane pods still unschedulable") {
+		t.Errorf("Expected a scheduling pressure error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// annotationDrift describes an annotation whose value in the ManifestWork differs from the live HostedCluster.
+// An empty value means the annotation is not set on that side.
+type annotationDrift struct {
+	Annotation    string `json:"annotation" yaml:"annotation"`
+	ManifestWork  string `json:"manifestwork" yaml:"manifestwork"`
+	HostedCluster string `json:"hosted_cluster" yaml:"hosted_cluster"`
+}
+
+// detectDrift compares the HostedCluster manifest in the cluster's ManifestWork on the service
+// cluster with the live HostedCluster and returns the annotations that differ.
+func (a *auditOpts) detectDrift(ctx context.Context, hc *hypershiftv1beta1.HostedCluster) ([]annotationDrift, error) {
+	clusterID := hc.Labels["api.openshift.com/id"]
+
+	manifestWork := &workv1.ManifestWork{}
+	err := a.serviceClient.Get(ctx,
+		types.NamespacedName{
+			Name:      clusterID,
+			Namespace: a.mgmtClusterName,
+		},
+		manifestWork)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ManifestWork %s/%s: %v", a.mgmtClusterName, clusterID, err)
+	}
+
+	_, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareAnnotations(a.profile.annotationKeys(), manifestAnnotations(manifestData), hc.Annotations), nil
+}
+
+// manifestAnnotations returns the string annotations of a decoded manifest.
+func manifestAnnotations(manifestData map[string]interface{}) map[string]string {
+	annotations := map[string]string{}
+
+	metadata, ok := manifestData["metadata"].(map[string]interface{})
+	if !ok {
+		return annotations
+	}
+
+	raw, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return annotations
+	}
+
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			annotations[k] = s
+		}
+	}
+
+	return annotations
+}
+
+// compareAnnotations returns the given annotations whose values differ between the ManifestWork
+// and the live HostedCluster.
+func compareAnnotations(keys []string, manifestWork, hostedCluster map[string]string) []annotationDrift {
+	var drift []annotationDrift
+
+	for _, annotation := range keys {
+		desired, hasDesired := manifestWork[annotation]
+		live, hasLive := hostedCluster[annotation]
+		if hasDesired == hasLive && desired == live {
+			continue
+		}
+		drift = append(drift, annotationDrift{
+			Annotation:    annotation,
+			ManifestWork:  desired,
+			HostedCluster: live,
+		})
+	}
+
+	return drift
+}
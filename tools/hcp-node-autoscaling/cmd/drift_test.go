@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestCompareAnnotations verifies drift detection between ManifestWork and live HostedCluster annotations.
+func TestCompareAnnotations(t *testing.T) {
+	tests := []struct {
+		name          string
+		manifestWork  map[string]string
+		hostedCluster map[string]string
+		expected      []annotationDrift
+	}{
+		{
+			name: "in sync",
+			manifestWork: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+			hostedCluster: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"unrelated": "value",
+			},
+			expected: nil,
+		},
+		{
+			name: "annotation in ManifestWork not synced to HostedCluster",
+			manifestWork: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+			hostedCluster: map[string]string{},
+			expected: []annotationDrift{{
+				Annotation:   "hypershift.openshift.io/resource-based-cp-auto-scaling",
+				ManifestWork: "true",
+			}},
+		},
+		{
+			name:         "annotation on HostedCluster missing from ManifestWork",
+			manifestWork: map[string]string{},
+			hostedCluster: map[string]string{
+				"hypershift.openshift.io/cluster-size-override": "large",
+			},
+			expected: []annotationDrift{{
+				Annotation:    "hypershift.openshift.io/cluster-size-override",
+				HostedCluster: "large",
+			}},
+		},
+		{
+			name: "different values",
+			manifestWork: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+			hostedCluster: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "false",
+			},
+			expected: []annotationDrift{{
+				Annotation:    "hypershift.openshift.io/resource-based-cp-auto-scaling",
+				ManifestWork:  "true",
+				HostedCluster: "false",
+			}},
+		},
+		{
+			name:          "unrelated annotations are ignored",
+			manifestWork:  map[string]string{"unrelated": "a"},
+			hostedCluster: map[string]string{"unrelated": "b"},
+			expected:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compareAnnotations(defaultProfile.annotationKeys(), tt.manifestWork, tt.hostedCluster)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d drifted annotations, got %d: %+v", len(tt.expected), len(result), result)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Drift %d = %+v, want %+v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestManifestAnnotations verifies annotations are extracted from decoded manifests.
+func TestManifestAnnotations(t *testing.T) {
+	tests := []struct {
+		name         string
+		manifestData map[string]interface{}
+		expected     map[string]string
+	}{
+		{
+			name:         "no metadata",
+			manifestData: map[string]interface{}{"kind": "HostedCluster"},
+			expected:     map[string]string{},
+		},
+		{
+			name: "no annotations",
+			manifestData: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "test-cluster"},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "string annotations",
+			manifestData: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{"a": "1", "b": "2"},
+				},
+			},
+			expected: map[string]string{"a": "1", "b": "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := manifestAnnotations(tt.manifestData)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d annotations, got %d", len(tt.expected), len(result))
+			}
+			for k, v := range tt.expected {
+				if result[k] != v {
+					t.Errorf("Annotation %s = %s, want %s", k, result[k], v)
+				}
+			}
+		})
+	}
+}
+
+// TestDetectDrift verifies drift is detected by reading the ManifestWork from the service cluster.
+func TestDetectDrift(t *testing.T) {
+	hcJSON, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata": map[string]interface{}{
+			"name": "test-cluster",
+			"annotations": map[string]interface{}{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+		},
+	})
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-123", Namespace: "mgmt-cluster"},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+
+	a := &auditOpts{
+		serviceClient:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mw).Build(),
+		mgmtClusterName: "mgmt-cluster",
+	}
+
+	hc := &hypershiftv1beta1.HostedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "ocm-production-cluster-123",
+			Labels:    map[string]string{"api.openshift.com/id": "cluster-123"},
+		},
+	}
+
+	drift, err := a.detectDrift(context.Background(), hc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(drift) != 1 || drift[0].Annotation != "hypershift.openshift.io/resource-based-cp-auto-scaling" {
+		t.Fatalf("Expected auto-scaling annotation drift, got %+v", drift)
+	}
+
+	hc.Labels["api.openshift.com/id"] = "missing-cluster"
+	if _, err := a.detectDrift(context.Background(), hc); err == nil {
+		t.Errorf("Expected error for missing ManifestWork")
+	}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	dryRunClient = "client"
+	dryRunServer = "server"
+)
+
+// annotationChangePreview describes how the migration would change one annotation of a HostedCluster manifest.
+// An empty Before or After value means the annotation is not set on that side.
+type annotationChangePreview struct {
+	Annotation string `json:"annotation"`
+	Before     string `json:"before,omitempty"`
+	After      string `json:"after,omitempty"`
+	Action     string `json:"action"`
+}
+
+// previewAnnotationChanges returns the changes the migration profile would make to the given manifest
+// annotations. Action is "add" for a new annotation, "overwrite" when an existing value is replaced and
+// "remove" when an annotation is deleted. Annotations that would not change are omitted.
+func previewAnnotationChanges(annotations map[string]string, profile *migrationProfile) []annotationChangePreview {
+	profile = profile.orDefault()
+
+	after := map[string]interface{}{}
+	for k, v := range annotations {
+		after[k] = v
+	}
+	profile.applyTo(after)
+
+	var changes []annotationChangePreview
+	for _, key := range profile.annotationKeys() {
+		before, wasSet := annotations[key]
+		value, isSet := after[key].(string)
+
+		switch {
+		case !wasSet && isSet:
+			changes = append(changes, annotationChangePreview{Annotation: key, After: value, Action: "add"})
+		case wasSet && !isSet:
+			changes = append(changes, annotationChangePreview{Annotation: key, Before: before, Action: "remove"})
+		case wasSet && before != value:
+			changes = append(changes, annotationChangePreview{Annotation: key, Before: before, After: value, Action: "overwrite"})
+		}
+	}
+
+	return changes
+}
+
+// displayDryRunDiff reads the ManifestWork of every candidate and prints the annotation changes the migration
+// would make to its HostedCluster manifest, warning about existing annotations that would be overwritten.
+func (m *migrateOpts) displayDryRunDiff(ctx context.Context, w io.Writer, candidates []hostedClusterAuditInfo) {
+	target := "ManifestWork"
+	if m.direct {
+		target = "HostedCluster"
+	}
+	fmt.Fprintf(w, "\n=== [DRY RUN] %s Annotation Changes ===\n\n", target)
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "BEFORE", "AFTER", "ACTION"})
+
+	var overwrites []string
+	for _, c := range candidates {
+		changes, err := m.previewChanges(ctx, c)
+		if err != nil {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, "-", "-", "-", fmt.Sprintf("error: %v", err)})
+			continue
+		}
+		if len(changes) == 0 {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, "-", "-", "-", fmt.Sprintf("none (%s already up to date)", target)})
+			continue
+		}
+		for _, change := range changes {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, change.Annotation,
+				driftValue(change.Before), driftValue(change.After), change.Action})
+			if change.Action == "overwrite" {
+				overwrites = append(overwrites, fmt.Sprintf("%s (%s): %s %q -> %q",
+					c.ClusterName, c.ClusterID, change.Annotation, change.Before, change.After))
+			}
+		}
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+
+	if len(overwrites) > 0 {
+		sort.Strings(overwrites)
+		fmt.Fprintf(w, "WARNING: %d existing annotations would be overwritten:\n", len(overwrites))
+		for _, o := range overwrites {
+			fmt.Fprintf(w, "  - %s\n", o)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// previewChanges returns the annotation changes the migration would make to a candidate: to its live
+// HostedCluster with --direct, otherwise to its ManifestWork.
+func (m *migrateOpts) previewChanges(ctx context.Context, c hostedClusterAuditInfo) ([]annotationChangePreview, error) {
+	if !m.direct {
+		return m.previewManifestWork(ctx, c.ClusterID)
+	}
+	hc, err := m.getHostedClusterFromMgmt(ctx, c.Namespace, c.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	return previewAnnotationChanges(hc.Annotations, m.clusterProfile(c.ClusterID)), nil
+}
+
+// previewManifestWork returns the annotation changes the migration would make to a cluster's ManifestWork.
+func (m *migrateOpts) previewManifestWork(ctx context.Context, clusterID string) ([]annotationChangePreview, error) {
+	manifestWork, err := m.getManifestWork(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		return nil, err
+	}
+
+	return previewAnnotationChanges(manifestAnnotations(manifestData), m.clusterProfile(clusterID)), nil
+}
+
+// addDryRunFlag registers --dry-run. Without a value it previews the changes; --dry-run=server also
+// submits them to the API server as a dry run.
+func addDryRunFlag(cmd *cobra.Command, mode *string, target string) {
+	cmd.Flags().StringVar(mode, "dry-run", "",
+		"Preview the annotation changes to "+target+" without applying them; --dry-run=server also submits "+
+			"them as a server-side dry run to check permissions and validation")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = dryRunClient
+}
+
+// parseDryRunMode parses the --dry-run value. "true" is accepted for client and "false" for no dry run.
+func parseDryRunMode(mode string) (dryRun, server bool, err error) {
+	switch mode {
+	case "", "false":
+		return false, false, nil
+	case dryRunClient, "true":
+		return true, false, nil
+	case dryRunServer:
+		return true, true, nil
+	default:
+		return false, false, fmt.Errorf("invalid dry run mode '%s'. Valid options: client, server", mode)
+	}
+}
+
+// submitServerDryRun patches every candidate like the real run, but with every write sent as a server-side
+// dry run: the API server checks that the elevated client may make the change and that the patched object
+// validates, without persisting it. It prints the outcome per cluster and returns an error if any failed.
+func (m *migrateOpts) submitServerDryRun(ctx context.Context, w io.Writer, candidates []hostedClusterAuditInfo) error {
+	dryRun := *m
+	target := "ManifestWork"
+	if m.direct {
+		target = "HostedCluster"
+		dryRun.mgmtClient = client.NewDryRunClient(m.mgmtClient)
+	} else {
+		dryRun.serviceClient = client.NewDryRunClient(m.serviceClient)
+	}
+
+	fmt.Fprintf(w, "=== [DRY RUN] Server-Side %s Patches ===\n\n", target)
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "RESULT"})
+	failed := 0
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		var err error
+		if m.direct {
+			_, err = dryRun.patchHostedClusterDirect(ctx, c)
+		} else {
+			_, err = dryRun.patchManifestWork(ctx, c.ClusterID)
+		}
+		result := "accepted"
+		if err != nil {
+			failed++
+			result = fmt.Sprintf("rejected: %v", err)
+		}
+		p.AddRow([]string{c.ClusterID, c.ClusterName, result})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("server-side dry run interrupted"))
+	}
+	if failed > 0 {
+		return fmt.Errorf("server-side dry run rejected %d of %d %s patches", failed, len(candidates), target)
+	}
+	return nil
+}
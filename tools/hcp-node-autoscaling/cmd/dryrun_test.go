@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestPreviewAnnotationChanges verifies added, overwritten and removed annotations are reported and unchanged ones omitted.
+func TestPreviewAnnotationChanges(t *testing.T) {
+	profile := &migrationProfile{
+		Name: "topology",
+		Ensure: map[string]string{
+			"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+		},
+		Remove: []string{"hypershift.openshift.io/cluster-size-override"},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    []annotationChangePreview
+	}{
+		{
+			name:        "adds missing annotations",
+			annotations: map[string]string{},
+			expected: []annotationChangePreview{
+				{Annotation: "hypershift.openshift.io/resource-based-cp-auto-scaling", After: "true", Action: "add"},
+				{Annotation: "hypershift.openshift.io/topology", After: "dedicated-request-serving-components", Action: "add"},
+			},
+		},
+		{
+			name: "overwrites topology and removes override",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "shared",
+				"hypershift.openshift.io/cluster-size-override":          "m5xl",
+			},
+			expected: []annotationChangePreview{
+				{Annotation: "hypershift.openshift.io/topology", Before: "shared", After: "dedicated-request-serving-components", Action: "overwrite"},
+				{Annotation: "hypershift.openshift.io/cluster-size-override", Before: "m5xl", Action: "remove"},
+			},
+		},
+		{
+			name: "already up to date",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := previewAnnotationChanges(tt.annotations, profile)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("previewAnnotationChanges() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDisplayDryRunDiff verifies the dry-run diff reads each ManifestWork and warns about overwritten annotations.
+func TestDisplayDryRunDiff(t *testing.T) {
+	hcJSON, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata": map[string]interface{}{
+			"name":        "cluster-1",
+			"annotations": map[string]interface{}{"hypershift.openshift.io/resource-based-cp-auto-scaling": "false"},
+		},
+	})
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-id-1", Namespace: "test-mgmt-cluster"},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+
+	m := &migrateOpts{
+		serviceClient:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(mw).Build(),
+		mgmtClusterName: "test-mgmt-cluster",
+	}
+
+	var out bytes.Buffer
+	m.displayDryRunDiff(context.Background(), &out, []hostedClusterAuditInfo{
+		{ClusterID: "cluster-id-1", ClusterName: "cluster-1"},
+		{ClusterID: "cluster-id-2", ClusterName: "cluster-2"},
+	})
+
+	output := out.String()
+	for _, expected := range []string{
+		"overwrite",
+		"WARNING: 1 existing annotations would be overwritten",
+		`hypershift.openshift.io/resource-based-cp-auto-scaling "false" -> "true"`,
+		"not found on service cluster",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+// TestParseDryRunMode verifies the --dry-run values, including the boolean values of the old flag.
+func TestParseDryRunMode(t *testing.T) {
+	tests := []struct {
+		mode         string
+		dryRun       bool
+		serverDryRun bool
+		expectError  bool
+	}{
+		{mode: ""},
+		{mode: "false"},
+		{mode: "true", dryRun: true},
+		{mode: "client", dryRun: true},
+		{mode: "server", dryRun: true, serverDryRun: true},
+		{mode: "all", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			dryRun, serverDryRun, err := parseDryRunMode(tt.mode)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseDryRunMode(%q) error = %v, expectError %v", tt.mode, err, tt.expectError)
+			}
+			if dryRun != tt.dryRun || serverDryRun != tt.serverDryRun {
+				t.Errorf("parseDryRunMode(%q) = %v, %v, want %v, %v", tt.mode, dryRun, serverDryRun, tt.dryRun, tt.serverDryRun)
+			}
+		})
+	}
+}
+
+// TestSubmitServerDryRun verifies the patches are submitted as server-side dry runs, leaving the
+// ManifestWorks unchanged, and that rejected patches fail the dry run.
+func TestSubmitServerDryRun(t *testing.T) {
+	scheme := testScheme(t)
+	allowed := newTestHostedCluster("a1", nil)
+	forbidden := newTestHostedCluster("b2", nil)
+	allowedWork := newTestManifestWork(t, "mgmt-cluster", allowed)
+
+	var dryRuns int
+	serviceClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(allowedWork, newTestManifestWork(t, "mgmt-cluster", forbidden)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patchOpts := &client.PatchOptions{}
+				patchOpts.ApplyOptions(opts)
+				if len(patchOpts.DryRun) == 1 && patchOpts.DryRun[0] == metav1.DryRunAll {
+					dryRuns++
+				}
+				if obj.GetName() == "b2" {
+					return apierrors.NewForbidden(schema.GroupResource{Group: "work.open-cluster-management.io", Resource: "manifestworks"}, "b2", nil)
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	m := &migrateOpts{
+		profile:         defaultProfile,
+		patchStrategy:   "json-patch",
+		mgmtClusterName: "mgmt-cluster",
+		serviceClient:   serviceClient,
+	}
+
+	var out bytes.Buffer
+	err := m.submitServerDryRun(context.Background(), &out, []hostedClusterAuditInfo{
+		{ClusterID: "a1", ClusterName: allowed.Name},
+		{ClusterID: "b2", ClusterName: forbidden.Name},
+	})
+	if err == nil || !strings.Contains(err.Error(), "rejected 1 of 2 ManifestWork patches") {
+		t.Errorf("submitServerDryRun() error = %v, want 1 of 2 rejected", err)
+	}
+	if dryRuns != 2 {
+		t.Errorf("Expected 2 dry run patches, got %d", dryRuns)
+	}
+	for _, expected := range []string{"a1", "accepted", "b2", "rejected:", "forbidden"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	current, err := m.getManifestWork(context.Background(), "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current.Spec.Workload.Manifests[0].Raw) != string(allowedWork.Spec.Workload.Manifests[0].Raw) {
+		t.Error("Expected the server-side dry run not to change the ManifestWork")
+	}
+}
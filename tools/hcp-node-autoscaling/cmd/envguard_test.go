@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	osdfmv1 "github.com/openshift-online/ocm-sdk-go/osdfleetmgmt/v1"
+)
+
+// newTestFleetClusters returns the fleet manager records of management cluster mgmt-cluster, whose parent is
+// the given service cluster, and of service cluster svc-cluster, registered at the given OCM API URLs.
+func newTestFleetClusters(t *testing.T, parent, mgmtHref, serviceHref string) (*osdfmv1.ManagementCluster, *osdfmv1.ServiceCluster) {
+	t.Helper()
+	mgmtCluster, err := osdfmv1.NewManagementCluster().
+		Name("mgmt-cluster").
+		Parent(osdfmv1.NewManagementClusterParent().Name(parent).Kind("ServiceCluster")).
+		ClusterManagementReference(osdfmv1.NewClusterManagementReference().Href(mgmtHref)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceCluster, err := osdfmv1.NewServiceCluster().
+		Name("svc-cluster").
+		ClusterManagementReference(osdfmv1.NewClusterManagementReference().Href(serviceHref)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mgmtCluster, serviceCluster
+}
+
+// TestOCMEnvironment verifies OCM API URLs are mapped to their environment.
+func TestOCMEnvironment(t *testing.T) {
+	tests := []struct {
+		href     string
+		expected string
+	}{
+		{"https://api.openshift.com/api/clusters_mgmt/v1/clusters/abc", "production"},
+		{"https://api.stage.openshift.com/api/clusters_mgmt/v1/clusters/abc", "staging"},
+		{"https://api.integration.openshift.com/api/clusters_mgmt/v1/clusters/abc", "integration"},
+		{"https://api.example.com/api/clusters_mgmt/v1/clusters/abc", "api.example.com"},
+		{"/api/clusters_mgmt/v1/clusters/abc", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ocmEnvironment(tt.href); got != tt.expected {
+			t.Errorf("ocmEnvironment(%q) = %q, want %q", tt.href, got, tt.expected)
+		}
+	}
+}
+
+// TestCheckClusterPairing verifies a service cluster is accepted only when it is the management cluster's
+// parent and both belong to the same OCM environment.
+func TestCheckClusterPairing(t *testing.T) {
+	const (
+		production = "https://api.openshift.com/api/clusters_mgmt/v1/clusters/abc"
+		staging    = "https://api.stage.openshift.com/api/clusters_mgmt/v1/clusters/def"
+	)
+	tests := []struct {
+		name        string
+		parent      string
+		mgmtHref    string
+		serviceHref string
+		errContains string
+	}{
+		{name: "paired", parent: "svc-cluster", mgmtHref: production, serviceHref: production},
+		{name: "other parent", parent: "svc-other", mgmtHref: production, serviceHref: production,
+			errContains: "service cluster svc-cluster is not the parent of management cluster mgmt-cluster (parent: svc-other)"},
+		{name: "other environment", parent: "svc-cluster", mgmtHref: production, serviceHref: staging,
+			errContains: "management cluster mgmt-cluster belongs to the production OCM environment but service cluster svc-cluster to staging"},
+		{name: "unknown environment", parent: "svc-cluster", mgmtHref: production,
+			errContains: "could not determine the OCM environment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgmtCluster, serviceCluster := newTestFleetClusters(t, tt.parent, tt.mgmtHref, tt.serviceHref)
+			err := checkClusterPairing(mgmtCluster, serviceCluster)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("checkClusterPairing() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("checkClusterPairing() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
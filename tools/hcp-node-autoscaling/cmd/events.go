@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// Events written to the --output jsonl event stream.
+const (
+	eventNamespaceAudited   = "namespace-audited"
+	eventClusterCategorized = "cluster-categorized"
+	eventPatchApplied       = "patch-applied"
+	eventSyncVerified       = "sync-verified"
+	eventError              = "error"
+)
+
+// streamEvent is one line of the --output jsonl event stream.
+type streamEvent struct {
+	Time          string `json:"time"`
+	RunID         string `json:"run_id"`
+	Event         string `json:"event"`
+	MgmtClusterID string `json:"mgmt_cluster_id,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	ClusterID     string `json:"cluster_id,omitempty"`
+	ClusterName   string `json:"cluster_name,omitempty"`
+
+	// Clusters is the number of hosted clusters found in an audited namespace.
+	Clusters *int `json:"clusters,omitempty"`
+
+	// Category and Subcategory are the audit category of a categorized cluster.
+	Category    string `json:"category,omitempty"`
+	Subcategory string `json:"subcategory,omitempty"`
+
+	// Target is what a patch was applied to: ManifestWork, or HostedCluster with --direct.
+	Target string `json:"target,omitempty"`
+
+	// SyncSeconds is how long it took to verify a patch on the management cluster.
+	SyncSeconds float64 `json:"sync_seconds,omitempty"`
+
+	Error        string `json:"error,omitempty"`
+	FailureClass string `json:"failure_class,omitempty"`
+}
+
+// eventStream writes the --output jsonl event stream, one JSON object per line as each event happens, so
+// long runs can be followed by a data pipeline or dashboard. It is safe for concurrent use. A nil
+// *eventStream writes nothing.
+type eventStream struct {
+	mu    sync.Mutex
+	w     io.Writer
+	runID string
+	now   func() time.Time
+}
+
+// newEventStream returns an event stream of the run with the given ID writing to w.
+func newEventStream(w io.Writer, runID string) *eventStream {
+	return &eventStream{w: w, runID: runID, now: time.Now}
+}
+
+// emit stamps the event with the current time and run ID and writes it. Failing to write an event is
+// logged and does not fail the run.
+func (s *eventStream) emit(event streamEvent) {
+	if s == nil {
+		return
+	}
+	event.Time = s.now().UTC().Format(time.RFC3339Nano)
+	event.RunID = s.runID
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Warn("Failed to marshal event", "event", event.Event, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := fmt.Fprintf(s.w, "%s\n", data); err != nil {
+		slog.Warn("Failed to write event", "event", event.Event, "error", err)
+	}
+}
+
+// setupEvents validates the migrate --output flag. With jsonl the events of the run are written to stdout
+// and the output meant for an operator watching the run moves to stderr.
+func (m *migrateOpts) setupEvents() error {
+	if m.output == "" || m.output == "text" {
+		return nil
+	}
+	if err := output.ValidateFormat(m.output, "text", "jsonl"); err != nil {
+		return err
+	}
+	if m.interactive || (m.dryRunMode != "" && m.dryRunMode != "false") || m.planFile != "" {
+		return fmt.Errorf("--output jsonl cannot be combined with --interactive, --dry-run or --plan-file")
+	}
+	m.events = newEventStream(os.Stdout, m.session.runID())
+	m.session.eventsOnStdout = true
+	return nil
+}
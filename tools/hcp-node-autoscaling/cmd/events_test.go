@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestEventStream verifies events are written as one JSON object per line with the time and run ID, and that
+// a nil stream writes nothing.
+func TestEventStream(t *testing.T) {
+	var buf bytes.Buffer
+	s := newEventStream(&buf, "run-123")
+	s.now = func() time.Time { return time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC) }
+
+	s.emit(streamEvent{Event: eventPatchApplied, ClusterID: "a1", Target: "ManifestWork"})
+	s.emit(streamEvent{Event: eventSyncVerified, ClusterID: "a1", SyncSeconds: 12.5})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	expected := `{"time":"2026-01-27T10:00:00Z","run_id":"run-123","event":"patch-applied","cluster_id":"a1","target":"ManifestWork"}`
+	if lines[0] != expected {
+		t.Errorf("event = %s, want %s", lines[0], expected)
+	}
+
+	var nilStream *eventStream
+	nilStream.emit(streamEvent{Event: eventError})
+}
+
+// TestAuditNamespacesEvents verifies audit --output jsonl writes an event per audited namespace, categorized
+// cluster and namespace error.
+func TestAuditNamespacesEvents(t *testing.T) {
+	ready := newTestHostedCluster("a1", nil)
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).
+		WithObjects(newTestNamespace(ready.Namespace), ready, newTestNamespace("ocm-production-b2")).
+		Build()
+
+	var buf bytes.Buffer
+	a := &auditOpts{mgmtClusterID: "mgmt-123", environment: "production", mgmtClient: mgmtClient, events: newEventStream(&buf, "")}
+	namespaces, err := a.listOcmNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list namespaces: %v", err)
+	}
+	a.auditNamespaces(context.Background(), namespaces)
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		event := streamEvent{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Failed to parse event %q: %v", line, err)
+		}
+		if event.MgmtClusterID != "mgmt-123" || event.Time == "" {
+			t.Errorf("Expected the management cluster and time on every event, got %+v", event)
+		}
+		got = append(got, event.Event+" "+event.Namespace+" "+event.Category)
+	}
+	expected := []string{
+		"namespace-audited ocm-production-a1 ",
+		"cluster-categorized ocm-production-a1 ready-for-migration",
+		"error ocm-production-b2 ",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("events = %q, want %q", got, expected)
+	}
+}
+
+// TestSetupEvents verifies migrate --output jsonl is rejected with the modes that write their own output to
+// stdout, and moves the output meant for an operator to stderr otherwise.
+func TestSetupEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        migrateOpts
+		expectedErr string
+	}{
+		{name: "text", opts: migrateOpts{output: "text"}},
+		{name: "invalid", opts: migrateOpts{output: "json"}, expectedErr: "invalid output format 'json'"},
+		{name: "dry run", opts: migrateOpts{output: "jsonl", dryRunMode: dryRunClient}, expectedErr: "cannot be combined"},
+		{name: "interactive", opts: migrateOpts{output: "jsonl", interactive: true}, expectedErr: "cannot be combined"},
+		{name: "jsonl", opts: migrateOpts{output: "jsonl", session: newRunSession()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.setupEvents()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if (tt.opts.events != nil) != (tt.opts.output == "jsonl") {
+					t.Errorf("Expected an event stream only for jsonl output, got %v", tt.opts.events)
+				}
+				if tt.opts.output == "jsonl" && tt.opts.session.infoOut() != tt.opts.session.stderr {
+					t.Error("Expected operator output on stderr with jsonl output")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// loadExclusions builds the set of excluded cluster IDs from --exclude-cluster-ids and an optional
+// exclusion file, mapping each ID to the reason it is excluded.
+func loadExclusions(ids []string, file string) (map[string]string, error) {
+	exclusions := map[string]string{}
+
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			exclusions[id] = "excluded by --exclude-cluster-ids"
+		}
+	}
+
+	if file == "" {
+		return exclusions, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open exclusion file: %v", err)
+	}
+	defer f.Close()
+
+	fromFile, err := parseExclusionFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exclusion file %s: %v", file, err)
+	}
+	for id, reason := range fromFile {
+		if _, ok := exclusions[id]; !ok {
+			exclusions[id] = reason
+		}
+	}
+
+	return exclusions, nil
+}
+
+// parseExclusionFile reads one cluster ID per line. Blank lines and lines starting with '#' are
+// ignored, and a trailing '# comment' is used as the exclusion reason.
+func parseExclusionFile(r io.Reader) (map[string]string, error) {
+	exclusions := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		reason := "excluded by exclusion file"
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			if comment := strings.TrimSpace(line[idx+1:]); comment != "" {
+				reason = comment
+			}
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		if strings.ContainsAny(line, " \t,") {
+			return nil, fmt.Errorf("line %d: expected a single cluster ID, got '%s'", lineNum, line)
+		}
+
+		exclusions[line] = reason
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return exclusions, nil
+}
+
+// filterExcluded removes clusters on the exclusion list from the migration candidates.
+func filterExcluded(candidates []hostedClusterAuditInfo, exclusions map[string]string) []hostedClusterAuditInfo {
+	if len(exclusions) == 0 {
+		return candidates
+	}
+
+	filtered := make([]hostedClusterAuditInfo, 0, len(candidates))
+	for _, c := range candidates {
+		if reason, ok := exclusions[c.ClusterID]; ok {
+			slog.Info("Skipping excluded cluster", "clusterID", c.ClusterID, "cluster", c.ClusterName, "reason", reason)
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	return filtered
+}
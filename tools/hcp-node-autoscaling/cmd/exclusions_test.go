@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseExclusionFile verifies exclusion file parsing, including comments and reasons.
+func TestParseExclusionFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:    "IDs with comments and blank lines",
+			content: "# incident exclusions\n\ncluster-1\ncluster-2  # customer freeze INC-1234\n",
+			expected: map[string]string{
+				"cluster-1": "excluded by exclusion file",
+				"cluster-2": "customer freeze INC-1234",
+			},
+		},
+		{
+			name:     "empty file",
+			content:  "",
+			expected: map[string]string{},
+		},
+		{
+			name:        "multiple IDs on one line",
+			content:     "cluster-1, cluster-2\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseExclusionFile(strings.NewReader(tt.content))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d exclusions, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for id, reason := range tt.expected {
+				if result[id] != reason {
+					t.Errorf("Exclusion %s reason = %q, want %q", id, result[id], reason)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadExclusions verifies flag and file exclusions are merged.
+func TestLoadExclusions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "exclusions.txt")
+	if err := os.WriteFile(file, []byte("cluster-2 # freeze\ncluster-3\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write exclusion file: %v", err)
+	}
+
+	exclusions, err := loadExclusions([]string{"cluster-1", " cluster-2 ", ""}, file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(exclusions) != 3 {
+		t.Fatalf("Expected 3 exclusions, got %d: %v", len(exclusions), exclusions)
+	}
+	if exclusions["cluster-2"] != "excluded by --exclude-cluster-ids" {
+		t.Errorf("Expected flag reason to take precedence, got %q", exclusions["cluster-2"])
+	}
+	if _, ok := exclusions["cluster-3"]; !ok {
+		t.Errorf("Expected cluster-3 from exclusion file")
+	}
+
+	if _, err := loadExclusions(nil, filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("Expected error for missing exclusion file")
+	}
+}
+
+// TestFilterExcluded verifies excluded clusters are removed from migration candidates.
+func TestFilterExcluded(t *testing.T) {
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "cluster-1"},
+		{ClusterID: "cluster-2"},
+		{ClusterID: "cluster-3"},
+	}
+
+	filtered := filterExcluded(candidates, map[string]string{"cluster-2": "freeze"})
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d", len(filtered))
+	}
+	for _, c := range filtered {
+		if c.ClusterID == "cluster-2" {
+			t.Errorf("Excluded cluster was not filtered")
+		}
+	}
+
+	if unfiltered := filterExcluded(candidates, nil); len(unfiltered) != 3 {
+		t.Errorf("Expected all candidates without exclusions, got %d", len(unfiltered))
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Exit codes returned by the tool so automation can tell outcomes apart.
+const (
+	exitOK             = 0
+	exitFailure        = 1
+	exitAuditFailOn    = 2
+	exitPartialFailure = 3
+	exitAllFailed      = 4
+	exitNothingToDo    = 5
+	exitInterrupted    = 130
+)
+
+// exitCodeDescriptions documents each exit code for --help-exit-codes.
+var exitCodeDescriptions = []struct {
+	code        int
+	commands    string
+	description string
+}{
+	{exitOK, "all", "Success"},
+	{exitFailure, "all", "Error: invalid flags, OCM or cluster access failure"},
+	{exitAuditFailOn, "audit", "A --fail-on condition matched (default: clusters need annotation removal or namespaces failed to audit)"},
+	{exitPartialFailure, "migrate, apply, drain-override, verify", "Some clusters were migrated or verified and some failed or were aborted by --max-failures or --max-failure-rate, or a --rollout-order rollout was halted"},
+	{exitAllFailed, "migrate, apply, drain-override, verify", "Every attempted cluster migration or verification failed, and any clusters left were aborted"},
+	{exitNothingToDo, "migrate, plan, apply, drain-override, verify", "No clusters were ready for migration, selected or pending verification, or no size overrides were left to drain"},
+	{exitInterrupted, "all", "Interrupted by SIGINT or SIGTERM; partial results were reported"},
+}
+
+// codedError is an error that makes the process exit with a specific exit code.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps err so the process exits with code.
+func withExitCode(code int, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// ExitCode returns the exit code for an error returned by a subcommand.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return exitFailure
+}
+
+// printExitCodes writes the exit code documentation.
+func printExitCodes(w io.Writer) {
+	fmt.Fprintln(w, "Exit codes:")
+	for _, e := range exitCodeDescriptions {
+		fmt.Fprintf(w, "  %-4d %-8s %s\n", e.code, e.commands, e.description)
+	}
+}
+
+// parseFailOn parses the audit --fail-on conditions. "none" disables them.
+func parseFailOn(value []string) (map[string]bool, error) {
+	conditions := map[string]bool{}
+	validConditions := map[string]bool{"needs-removal": true, "ready-for-migration": true, "drifted": true, "errors": true}
+
+	for _, c := range value {
+		c = strings.TrimSpace(c)
+		if c == "" || c == "none" {
+			continue
+		}
+		if !validConditions[c] {
+			return nil, fmt.Errorf("invalid --fail-on condition '%s'. Valid options: needs-removal, ready-for-migration, drifted, errors, none", c)
+		}
+		conditions[c] = true
+	}
+
+	return conditions, nil
+}
+
+// failOnError returns an error with exitAuditFailOn if any of the --fail-on conditions match the results.
+func failOnError(conditions map[string]bool, results *auditResults) error {
+	counts := []struct {
+		condition string
+		count     int
+		message   string
+	}{
+		{"needs-removal", len(results.NeedsLabelRemoval), "clusters need annotation removal"},
+		{"ready-for-migration", len(results.ReadyForMigration), "clusters are ready for migration"},
+		{"drifted", len(results.Drifted), "clusters have drifted"},
+		{"errors", len(results.Errors), "namespaces failed to audit"},
+	}
+
+	var matched []string
+	for _, c := range counts {
+		if conditions[c.condition] && c.count > 0 {
+			matched = append(matched, fmt.Sprintf("%d %s", c.count, c.message))
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil
+	}
+	return withExitCode(exitAuditFailOn, fmt.Errorf("audit failed: %s", strings.Join(matched, ", ")))
+}
+
+// migrationExitError returns the error for a completed migration based on its results. Clusters skipped
+// because their state changed since they were audited or they have a conflicting annotation, and clusters
+// patched with --no-verify, do not count as failed. Clusters aborted by the failure budget do.
+func migrationExitError(results []migrationResult) error {
+	succeeded, aborted := 0, 0
+	for _, r := range results {
+		switch r.Status {
+		case "success", stateChanged, conflictingAnnotation, pendingVerificationStatus:
+			succeeded++
+		case abortedStatus:
+			aborted++
+		}
+	}
+
+	code := exitPartialFailure
+	if succeeded == 0 {
+		code = exitAllFailed
+	}
+	switch {
+	case succeeded == len(results):
+		return nil
+	case aborted > 0:
+		return withExitCode(code, fmt.Errorf("%d of %d cluster migrations failed and the remaining %d were aborted",
+			len(results)-succeeded-aborted, len(results), aborted))
+	case succeeded == 0:
+		return withExitCode(exitAllFailed, fmt.Errorf("all %d cluster migrations failed", len(results)))
+	default:
+		return withExitCode(exitPartialFailure, fmt.Errorf("%d of %d cluster migrations failed", len(results)-succeeded, len(results)))
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestExitCode verifies exit codes are taken from wrapped errors and default to exitFailure.
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "nil", err: nil, expected: exitOK},
+		{name: "plain error", err: errors.New("boom"), expected: exitFailure},
+		{name: "coded error", err: withExitCode(exitNothingToDo, errors.New("nothing")), expected: exitNothingToDo},
+		{
+			name:     "wrapped coded error",
+			err:      fmt.Errorf("outer: %w", withExitCode(exitInterrupted, errors.New("interrupted"))),
+			expected: exitInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ExitCode(tt.err); result != tt.expected {
+				t.Errorf("ExitCode() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseFailOn verifies --fail-on conditions are validated and "none" disables them.
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       []string
+		expected    map[string]bool
+		expectError bool
+	}{
+		{
+			name:     "defaults",
+			value:    []string{"needs-removal", "errors"},
+			expected: map[string]bool{"needs-removal": true, "errors": true},
+		},
+		{name: "none", value: []string{"none"}, expected: map[string]bool{}},
+		{name: "empty", value: nil, expected: map[string]bool{}},
+		{
+			name:     "whitespace",
+			value:    []string{" drifted "},
+			expected: map[string]bool{"drifted": true},
+		},
+		{name: "invalid", value: []string{"already-configured"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseFailOn(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("parseFailOn() = %v, want %v", result, tt.expected)
+			}
+			for k := range tt.expected {
+				if !result[k] {
+					t.Errorf("Expected condition %s to be set", k)
+				}
+			}
+		})
+	}
+}
+
+// TestFailOnError verifies the audit fails only when a selected condition has results.
+func TestFailOnError(t *testing.T) {
+	results := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{{ClusterID: "c1"}},
+		ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "c2"}, {ClusterID: "c3"}},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]bool
+		results    *auditResults
+		expected   int
+	}{
+		{name: "no conditions", conditions: map[string]bool{}, results: results, expected: exitOK},
+		{name: "needs-removal matched", conditions: map[string]bool{"needs-removal": true}, results: results, expected: exitAuditFailOn},
+		{name: "drifted not matched", conditions: map[string]bool{"drifted": true}, results: results, expected: exitOK},
+		{
+			name:       "errors matched",
+			conditions: map[string]bool{"errors": true},
+			results:    &auditResults{Errors: []auditError{{Namespace: "ocm-production-abc", Error: "boom"}}},
+			expected:   exitAuditFailOn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ExitCode(failOnError(tt.conditions, tt.results)); result != tt.expected {
+				t.Errorf("exit code = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMigrationExitError verifies migrations report distinct exit codes for partial and total failure.
+func TestMigrationExitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		expected int
+	}{
+		{name: "all succeeded", statuses: []string{"success", "success"}, expected: exitOK},
+		{name: "partial failure", statuses: []string{"success", "failed"}, expected: exitPartialFailure},
+		{name: "all failed", statuses: []string{"failed", "failed"}, expected: exitAllFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []migrationResult
+			for i, s := range tt.statuses {
+				results = append(results, migrationResult{ClusterID: fmt.Sprintf("c%d", i), Status: s})
+			}
+			if result := ExitCode(migrationExitError(results)); result != tt.expected {
+				t.Errorf("exit code = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
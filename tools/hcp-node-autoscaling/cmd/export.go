@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	exportTimeout = 2 * time.Minute
+
+	// googleAccessTokenEnv holds the OAuth access token used for Google Sheets exports, e.g. the output
+	// of gcloud auth print-access-token.
+	googleAccessTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+
+	sheetsAPIURL = "https://sheets.googleapis.com"
+)
+
+// reportExporter uploads the audit report to an --export destination after the run.
+type reportExporter interface {
+	export(ctx context.Context, a *auditOpts, results *auditResults) error
+	String() string
+}
+
+// s3PutObjectAPI is the part of the S3 client used to upload reports.
+type s3PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3Exporter uploads the report in the --output format to an S3 object.
+type s3Exporter struct {
+	bucket string
+	key    string
+	client s3PutObjectAPI
+}
+
+// gsheetExporter replaces the first sheet of a Google Sheets spreadsheet with the CSV rows of the report.
+type gsheetExporter struct {
+	sheetID string
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// newExporters parses the --export destinations and resolves their credentials from the environment, so
+// that missing credentials are reported before the audit runs.
+func newExporters(ctx context.Context, destinations []string, output string) ([]reportExporter, error) {
+	var exporters []reportExporter
+	for _, destination := range destinations {
+		switch {
+		case strings.HasPrefix(destination, "s3://"):
+			bucket, key, err := parseS3URL(destination)
+			if err != nil {
+				return nil, err
+			}
+			if output == "text" || output == "wide" || output == "summary" || output == "jsonl" {
+				return nil, fmt.Errorf("--export %s is not supported with --output %s", destination, output)
+			}
+			client, err := newS3Client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			exporters = append(exporters, &s3Exporter{bucket: bucket, key: key, client: client})
+		case strings.HasPrefix(destination, "gsheet:"):
+			sheetID := strings.TrimPrefix(destination, "gsheet:")
+			if sheetID == "" || strings.Contains(sheetID, "/") {
+				return nil, fmt.Errorf("invalid export destination '%s'. Valid options: s3://<bucket>/<key>, gsheet:<sheet-id>", destination)
+			}
+			token := os.Getenv(googleAccessTokenEnv)
+			if token == "" {
+				return nil, fmt.Errorf("--export %s requires an OAuth access token in %s, e.g. from 'gcloud auth print-access-token'",
+					destination, googleAccessTokenEnv)
+			}
+			exporters = append(exporters, &gsheetExporter{
+				sheetID: sheetID,
+				token:   token,
+				baseURL: sheetsAPIURL,
+				client:  &http.Client{Timeout: exportTimeout},
+			})
+		default:
+			return nil, fmt.Errorf("invalid export destination '%s'. Valid options: s3://<bucket>/<key>, gsheet:<sheet-id>", destination)
+		}
+	}
+	return exporters, nil
+}
+
+// parseS3URL returns the bucket and key of an s3://bucket/key URL.
+func parseS3URL(destination string) (string, string, error) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(destination, "s3://"), "/")
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("invalid export destination '%s'. Valid options: s3://<bucket>/<key>, gsheet:<sheet-id>", destination)
+	}
+	return bucket, key, nil
+}
+
+// newS3Client creates an S3 client from the standard AWS environment: environment variables, shared
+// config and credentials files, or an instance role.
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %v", err)
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find AWS credentials for --export: %v", err)
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+// exportResults uploads the audit report to every --export destination. Reports are uploaded even when
+// ctx has been cancelled, so that interrupted runs still publish their partial results.
+func (a *auditOpts) exportResults(ctx context.Context, results *auditResults) error {
+	for _, e := range a.exporters {
+		exportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportTimeout)
+		err := e.export(exportCtx, a, results)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to export audit results to %s: %v", e, err)
+		}
+		slog.Info("Exported audit results", "destination", e.String())
+	}
+	return nil
+}
+
+// export uploads the report rendered in the --output format.
+func (e *s3Exporter) export(ctx context.Context, a *auditOpts, results *auditResults) error {
+	var buf bytes.Buffer
+	if err := a.printStructuredOutput(&buf, results, false); err != nil {
+		return err
+	}
+
+	_, err := e.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(e.bucket),
+		Key:         aws.String(e.key),
+		Body:        bytes.NewReader(buf.Bytes()),
+		ContentType: aws.String(outputContentType(a.output)),
+	})
+	return err
+}
+
+func (e *s3Exporter) String() string {
+	return fmt.Sprintf("s3://%s/%s", e.bucket, e.key)
+}
+
+// outputContentType returns the MIME type of a structured output format.
+func outputContentType(output string) string {
+	switch output {
+	case "yaml":
+		return "application/yaml"
+	case "csv":
+		return "text/csv"
+	case "markdown":
+		return "text/markdown"
+	case "html":
+		return "text/html"
+	default:
+		return "application/json"
+	}
+}
+
+// export clears the first sheet of the spreadsheet and writes the CSV rows of the report to it, so the
+// sheet always holds exactly the latest run.
+func (e *gsheetExporter) export(ctx context.Context, a *auditOpts, results *auditResults) error {
+	var buf bytes.Buffer
+	if err := a.printCSVOutput(&buf, results, !a.noHeaders); err != nil {
+		return err
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV report: %v", err)
+	}
+
+	values := e.baseURL + "/v4/spreadsheets/" + url.PathEscape(e.sheetID) + "/values/"
+	if err := e.do(ctx, http.MethodPost, values+url.PathEscape("A:ZZ")+":clear", struct{}{}); err != nil {
+		return fmt.Errorf("failed to clear sheet: %v", err)
+	}
+
+	body := map[string]interface{}{"range": "A1", "majorDimension": "ROWS", "values": rows}
+	if err := e.do(ctx, http.MethodPut, values+"A1?valueInputOption=RAW", body); err != nil {
+		return fmt.Errorf("failed to write sheet: %v", err)
+	}
+	return nil
+}
+
+// do sends a Sheets API request with the access token. The token is left out of errors.
+func (e *gsheetExporter) do(ctx context.Context, method, requestURL string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("request returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *gsheetExporter) String() string {
+	return "gsheet:" + e.sheetID
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeS3 records the objects uploaded with PutObject.
+type fakeS3 struct {
+	input *s3.PutObjectInput
+	body  string
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.input, f.body = params, string(data)
+	return &s3.PutObjectOutput{}, nil
+}
+
+// TestNewExporters verifies --export destinations are validated before any credentials are resolved.
+func TestNewExporters(t *testing.T) {
+	t.Setenv(googleAccessTokenEnv, "")
+
+	tests := []struct {
+		name        string
+		destination string
+		output      string
+		token       string
+		expectError string
+	}{
+		{name: "unknown scheme", destination: "gs://bucket/report.json", output: "json", expectError: "invalid export destination"},
+		{name: "s3 without key", destination: "s3://bucket", output: "json", expectError: "invalid export destination"},
+		{name: "s3 with text output", destination: "s3://bucket/report.txt", output: "text", expectError: "not supported with --output text"},
+		{name: "gsheet without ID", destination: "gsheet:", output: "text", expectError: "invalid export destination"},
+		{name: "gsheet without token", destination: "gsheet:abc123", output: "text", expectError: googleAccessTokenEnv},
+		{name: "gsheet", destination: "gsheet:abc123", output: "text", token: "token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(googleAccessTokenEnv, tt.token)
+			exporters, err := newExporters(context.Background(), []string{tt.destination}, tt.output)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(exporters) != 1 || exporters[0].String() != tt.destination {
+				t.Errorf("Expected exporter for %s, got %v", tt.destination, exporters)
+			}
+		})
+	}
+}
+
+// TestS3Export verifies the report is uploaded in the --output format.
+func TestS3Export(t *testing.T) {
+	client := &fakeS3{}
+	a := &auditOpts{output: "csv"}
+	a.exporters = []reportExporter{&s3Exporter{bucket: "fleet-reports", key: "weekly/mgmt-1.csv", client: client}}
+	results := &auditResults{ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "a1", ClusterName: "alpha"}}}
+
+	if err := a.exportResults(context.Background(), results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.input == nil || *client.input.Bucket != "fleet-reports" || *client.input.Key != "weekly/mgmt-1.csv" {
+		t.Fatalf("Expected upload to s3://fleet-reports/weekly/mgmt-1.csv, got %+v", client.input)
+	}
+	if *client.input.ContentType != "text/csv" {
+		t.Errorf("Expected text/csv content type, got %s", *client.input.ContentType)
+	}
+	if !strings.HasPrefix(client.body, "cluster_id,") || !strings.Contains(client.body, "a1,alpha") {
+		t.Errorf("Expected CSV report, got %q", client.body)
+	}
+}
+
+// TestGsheetExport verifies the sheet is cleared and then replaced with the CSV rows of the report.
+func TestGsheetExport(t *testing.T) {
+	var requests []string
+	var written struct {
+		Values [][]string `json:"values"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPut {
+			_ = json.NewDecoder(r.Body).Decode(&written)
+		}
+	}))
+	defer server.Close()
+
+	a := &auditOpts{output: "text"}
+	a.exporters = []reportExporter{&gsheetExporter{sheetID: "abc123", token: "token", baseURL: server.URL, client: server.Client()}}
+	results := &auditResults{NeedsLabelRemoval: []hostedClusterAuditInfo{{ClusterID: "a1", ClusterName: "alpha"}}}
+
+	if err := a.exportResults(context.Background(), results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"POST /v4/spreadsheets/abc123/values/A:ZZ:clear", "PUT /v4/spreadsheets/abc123/values/A1"}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Requests = %v, want %v", requests, expected)
+	}
+	if len(written.Values) != 2 || written.Values[0][0] != "cluster_id" || written.Values[1][0] != "a1" {
+		t.Errorf("Expected header and one cluster row, got %v", written.Values)
+	}
+}
+
+// TestGsheetExportError verifies a rejected request fails the export without leaking the access token.
+func TestGsheetExportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	a := &auditOpts{}
+	a.exporters = []reportExporter{&gsheetExporter{sheetID: "abc123", token: "secret-token", baseURL: server.URL, client: server.Client()}}
+
+	err := a.exportResults(context.Background(), &auditResults{})
+	if err == nil || !strings.Contains(err.Error(), "gsheet:abc123") || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Expected 403 error for gsheet:abc123, got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected access token to be left out of the error, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// abortedStatus is the status of a migration result for a candidate that was not started because the
+	// failure budget of the run was exceeded.
+	abortedStatus = "aborted"
+
+	// minFailureRateMigrations is the number of finished migrations from which --max-failure-rate applies,
+	// so that a single early failure does not abort the run.
+	minFailureRateMigrations = 5
+
+	// abortedPrefix starts the error of an aborted result, followed by why the failure budget was exceeded.
+	abortedPrefix = "not started: "
+)
+
+// addFailureBudgetFlags registers --max-failures and --max-failure-rate on a command that migrates clusters.
+func addFailureBudgetFlags(cmd *cobra.Command, m *migrateOpts) {
+	cmd.Flags().IntVar(&m.maxFailures, "max-failures", 0,
+		"Abort the remaining migrations once this many clusters failed (0 disables the limit)")
+	cmd.Flags().Float64Var(&m.maxFailureRate, "max-failure-rate", 0,
+		fmt.Sprintf("Abort the remaining migrations once more than this percentage of the finished migrations failed, from %d finished migrations on (0 disables the limit)", minFailureRateMigrations))
+}
+
+// validateFailureBudget checks that --max-failures and --max-failure-rate are within bounds.
+func validateFailureBudget(maxFailures int, maxFailureRate float64) error {
+	if maxFailures < 0 {
+		return fmt.Errorf("invalid max failures %d: must not be negative", maxFailures)
+	}
+	if maxFailureRate < 0 || maxFailureRate > 100 {
+		return fmt.Errorf("invalid max failure rate %v: must be between 0 and 100", maxFailureRate)
+	}
+	return nil
+}
+
+// failureBudget counts the finished migrations of a run and the failed ones, to stop starting migrations
+// once too many failed. A nil budget is never exceeded.
+type failureBudget struct {
+	maxFailures    int
+	maxFailureRate float64
+
+	mu       sync.Mutex
+	finished int
+	failed   int
+	reason   string
+}
+
+// newFailureBudget returns the budget for --max-failures and --max-failure-rate, or nil when both are 0.
+func newFailureBudget(maxFailures int, maxFailureRate float64) *failureBudget {
+	if maxFailures == 0 && maxFailureRate == 0 {
+		return nil
+	}
+	return &failureBudget{maxFailures: maxFailures, maxFailureRate: maxFailureRate}
+}
+
+// record counts a finished migration. Once the budget is exceeded it keeps the reason of the first time.
+func (b *failureBudget) record(result migrationResult) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finished++
+	if result.Status == "failed" {
+		b.failed++
+	}
+	if b.reason != "" {
+		return
+	}
+	switch rate := 100 * float64(b.failed) / float64(b.finished); {
+	case b.maxFailures > 0 && b.failed >= b.maxFailures:
+		b.reason = fmt.Sprintf("%d migrations failed, reaching --max-failures %d", b.failed, b.maxFailures)
+	case b.maxFailureRate > 0 && b.finished >= minFailureRateMigrations && rate > b.maxFailureRate:
+		b.reason = fmt.Sprintf("%d of %d finished migrations failed (%.0f%%), exceeding --max-failure-rate %v%%",
+			b.failed, b.finished, rate, b.maxFailureRate)
+	}
+}
+
+// exceeded returns why the budget is exceeded, or an empty string while it is not.
+func (b *failureBudget) exceeded() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reason
+}
+
+// abortCandidates returns the aborted results of the candidates that were not started because the failure
+// budget was exceeded, and records them in the run history.
+func (m *migrateOpts) abortCandidates(candidates []hostedClusterAuditInfo, reason string) []migrationResult {
+	results := make([]migrationResult, 0, len(candidates))
+	for _, c := range candidates {
+		result := migrationResult{
+			ClusterID:   c.ClusterID,
+			ClusterName: c.ClusterName,
+			Status:      abortedStatus,
+			Error:       abortedPrefix + reason,
+		}
+		if err := m.history.record(c, result, false); err != nil {
+			slog.Warn("Failed to write run history", "error", err)
+		}
+		m.broker.migrated(m.mgmtClusterID, c, result)
+		results = append(results, result)
+	}
+	return results
+}
+
+// systemicFailureHint returns what most of the failed migrations have in common and what to check about it,
+// or an empty string when no failure class accounts for most of them.
+func systemicFailureHint(failed []migrationResult) string {
+	for _, c := range failureClasses {
+		if n := countFailureClass(failed, c.class); c.class != failureOther && 2*n > len(failed) {
+			return fmt.Sprintf("%d of %d failures are %s failures, which points at a common cause: %s",
+				n, len(failed), c.class, c.action)
+		}
+	}
+	return ""
+}
+
+// displayAborted prints the candidates aborted by the failure budget, with a hint at the likely cause of the
+// failures.
+func displayAborted(w io.Writer, aborted, failed []migrationResult) {
+	if len(aborted) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "✗ Aborted, Not Started (%d): %s\n", len(aborted), strings.TrimPrefix(aborted[0].Error, abortedPrefix))
+	for _, r := range aborted {
+		fmt.Fprintf(w, "  - %s (%s)\n", r.ClusterName, r.ClusterID)
+	}
+	if hint := systemicFailureHint(failed); hint != "" {
+		fmt.Fprintf(w, "\nHint: %s\n", hint)
+	}
+	fmt.Fprintln(w)
+}
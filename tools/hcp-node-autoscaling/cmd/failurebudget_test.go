@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestValidateFailureBudget verifies negative limits and rates above 100% are rejected.
+func TestValidateFailureBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxFailures    int
+		maxFailureRate float64
+		expectErr      bool
+	}{
+		{"disabled", 0, 0, false},
+		{"both set", 3, 25, false},
+		{"full rate", 0, 100, false},
+		{"negative failures", -1, 0, true},
+		{"negative rate", 0, -5, true},
+		{"rate above 100", 0, 150, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFailureBudget(tt.maxFailures, tt.maxFailureRate)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateFailureBudget(%d, %v) error = %v, expectErr %v", tt.maxFailures, tt.maxFailureRate, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+// TestFailureBudget verifies the budget is exceeded at --max-failures failures, or once the failure rate is
+// above --max-failure-rate after enough migrations finished.
+func TestFailureBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxFailures    int
+		maxFailureRate float64
+		statuses       string
+		exceededAfter  int
+	}{
+		{"disabled", 0, 0, "fffff", -1},
+		{"max failures", 2, 0, "sfsf", 4},
+		{"max failures not reached", 3, 0, "sfsfs", -1},
+		{"rate waits for enough migrations", 0, 50, "ffs", -1},
+		{"rate exceeded", 0, 50, "ffsfs", 5},
+		{"rate at the limit", 0, 50, "ffss", -1},
+		{"skipped clusters are not failures", 1, 0, "cc", -1},
+	}
+	statuses := map[rune]string{'s': "success", 'f': "failed", 'c': stateChanged}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newFailureBudget(tt.maxFailures, tt.maxFailureRate)
+			exceededAfter := -1
+			for i, s := range tt.statuses {
+				budget.record(migrationResult{Status: statuses[s]})
+				if exceededAfter == -1 && budget.exceeded() != "" {
+					exceededAfter = i + 1
+				}
+			}
+			if exceededAfter != tt.exceededAfter {
+				t.Errorf("Budget exceeded after %d migrations, want %d (%s)", exceededAfter, tt.exceededAfter, budget.exceeded())
+			}
+		})
+	}
+}
+
+// TestMigrateClustersFailureBudget verifies that once --max-failures migrations failed the candidates not
+// started yet are returned as aborted, and the run fails with a hint at the common cause.
+func TestMigrateClustersFailureBudget(t *testing.T) {
+	scheme := testScheme(t)
+	m := &migrateOpts{
+		mgmtClusterName: "mgmt-cluster",
+		patchStrategy:   "update",
+		syncTimeout:     time.Minute,
+		pollInterval:    time.Hour,
+		maxInFlight:     1,
+		maxFailures:     2,
+		serviceClient:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	var candidates []hostedClusterAuditInfo
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("a%d", i)
+		candidates = append(candidates, hostedClusterAuditInfo{ClusterID: id, ClusterName: "cluster-" + id, Namespace: "ocm-production-" + id})
+	}
+
+	results := m.migrateClusters(context.Background(), candidates)
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.ClusterID+"="+r.Status)
+	}
+	expected := "a1=failed a2=failed a3=aborted a4=aborted a5=aborted"
+	if got := strings.Join(statuses, " "); got != expected {
+		t.Fatalf("migrateClusters() statuses = %s, want %s", got, expected)
+	}
+	if !strings.Contains(results[2].Error, "2 migrations failed, reaching --max-failures 2") {
+		t.Errorf("Expected the aborted result to give the reason, got %q", results[2].Error)
+	}
+
+	err := migrationExitError(results)
+	if code := ExitCode(err); code != exitAllFailed {
+		t.Errorf("migrationExitError() exit code = %d, want %d", code, exitAllFailed)
+	}
+	if err == nil || err.Error() != "2 of 5 cluster migrations failed and the remaining 3 were aborted" {
+		t.Errorf("Unexpected migrationExitError() error %v", err)
+	}
+
+	var buf bytes.Buffer
+	m.displayResults(&buf, results, nil)
+	for _, want := range []string{"Aborted: 3", "✗ Aborted, Not Started", "Hint: 2 of 2 failures are manifest-not-found failures"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected the summary to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestSystemicFailureHint verifies a hint is given only when one failure class accounts for most failures.
+func TestSystemicFailureHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []string
+		expect  string
+	}{
+		{"majority", []string{failureSyncTimeout, failureSyncTimeout, failureRBAC}, "2 of 3 failures are sync-timeout failures"},
+		{"no majority", []string{failureSyncTimeout, failureRBAC}, ""},
+		{"other", []string{failureOther, failureOther}, ""},
+		{"none", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failed []migrationResult
+			for _, class := range tt.classes {
+				failed = append(failed, migrationResult{Status: "failed", FailureClass: class})
+			}
+			hint := systemicFailureHint(failed)
+			if (tt.expect == "") != (hint == "") || !strings.Contains(hint, tt.expect) {
+				t.Errorf("systemicFailureHint() = %q, want %q", hint, tt.expect)
+			}
+		})
+	}
+}
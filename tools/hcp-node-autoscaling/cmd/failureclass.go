@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	failureAPITimeout       = "api-timeout"
+	failureConflict         = "conflict"
+	failureRBAC             = "rbac"
+	failureManifestNotFound = "manifest-not-found"
+	failureSyncTimeout      = "sync-timeout"
+	failureOther            = "other"
+
+	// defaultRetryDelay is the wait before migrating a cluster again after a transient failure.
+	defaultRetryDelay = 10 * time.Second
+)
+
+// failureClasses lists the failure classes in the order the migration summary shows them, with whether a
+// failure of the class is retried and what the operator should do about it.
+var failureClasses = []struct {
+	class     string
+	transient bool
+	action    string
+}{
+	{failureRBAC, false, "Check the backplane elevation and the ManifestWork permissions, e.g. with the preflight command"},
+	{failureManifestNotFound, false, "Check that the service cluster is the parent of the management cluster and the cluster still exists"},
+	{failureSyncTimeout, false, "Check the work agent on the management cluster, e.g. with the doctor command; the ManifestWork is patched but not yet applied"},
+	{failureAPITimeout, true, "The API server did not answer in time; run the migration again"},
+	{failureConflict, true, "The ManifestWork kept changing during the update; run the migration again"},
+	{failureOther, false, "Check the error of each cluster"},
+}
+
+// classifiedError carries the failure class of an error whose cause is not a Kubernetes API error.
+type classifiedError struct {
+	class string
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// withFailureClass wraps err so that classifyFailure returns class for it.
+func withFailureClass(class string, err error) error {
+	return &classifiedError{class: class, err: err}
+}
+
+// classifyFailure returns the failure class of the error a cluster migration failed with.
+func classifyFailure(err error) string {
+	var classified *classifiedError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &classified):
+		return classified.class
+	case apierrors.IsConflict(err):
+		return failureConflict
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return failureRBAC
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err),
+		errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return failureAPITimeout
+	default:
+		return failureOther
+	}
+}
+
+// isTransientFailure reports whether a failure of class may succeed when the cluster is migrated again.
+func isTransientFailure(class string) bool {
+	for _, c := range failureClasses {
+		if c.class == class {
+			return c.transient
+		}
+	}
+	return false
+}
+
+// migrateWithRetries migrates a cluster, migrating it again up to --max-retries times while it fails with a
+// transient failure class.
+func (m *migrateOpts) migrateWithRetries(ctx context.Context, candidate hostedClusterAuditInfo) migrationResult {
+	delay := m.retryDelay
+	if delay == 0 {
+		delay = defaultRetryDelay
+	}
+
+	result := m.migrateClusterTraced(ctx, candidate, 0)
+	for retry := 1; retry <= m.maxRetries && result.Status == "failed" && isTransientFailure(result.FailureClass); retry++ {
+		slog.Warn("Retrying cluster migration after transient failure", "clusterID", candidate.ClusterID,
+			"class", result.FailureClass, "retry", retry, "error", result.Error)
+		if !sleepContext(ctx, delay) {
+			break
+		}
+		result = m.migrateClusterTraced(ctx, candidate, retry)
+		result.Retries = retry
+	}
+	return result
+}
+
+// countFailureClass returns the number of failed results of class; results without a class count as other.
+func countFailureClass(failed []migrationResult, class string) int {
+	n := 0
+	for _, r := range failed {
+		if r.FailureClass == class || (r.FailureClass == "" && class == failureOther) {
+			n++
+		}
+	}
+	return n
+}
+
+// displayFailuresByClass prints the failed migrations grouped by failure class, each with what to do about it.
+func displayFailuresByClass(w io.Writer, failed []migrationResult) {
+	byClass := map[string][]migrationResult{}
+	for _, r := range failed {
+		class := r.FailureClass
+		if class == "" {
+			class = failureOther
+		}
+		byClass[class] = append(byClass[class], r)
+	}
+
+	for _, c := range failureClasses {
+		results := byClass[c.class]
+		if len(results) == 0 {
+			continue
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].ClusterID < results[j].ClusterID })
+
+		fmt.Fprintf(w, "✗ Failed, %s (%d): %s\n", c.class, len(results), c.action)
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "RETRIES", "ERROR"})
+		for _, r := range results {
+			p.AddRow([]string{r.ClusterID, r.ClusterName, fmt.Sprint(r.Retries), r.Error})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+}
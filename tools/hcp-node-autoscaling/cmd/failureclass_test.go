@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestClassifyFailure verifies API errors are classified through the wrapping of the migration steps.
+func TestClassifyFailure(t *testing.T) {
+	resource := schema.GroupResource{Group: "work.open-cluster-management.io", Resource: "manifestworks"}
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", err: nil, expected: ""},
+		{name: "conflict", err: fmt.Errorf("failed to update ManifestWork after 3 conflict retries: %w", apierrors.NewConflict(resource, "a1", errors.New("modified"))), expected: failureConflict},
+		{name: "forbidden", err: fmt.Errorf("failed to get ManifestWork mgmt/a1: %w", apierrors.NewForbidden(resource, "a1", errors.New("no elevation"))), expected: failureRBAC},
+		{name: "unauthorized", err: apierrors.NewUnauthorized("token expired"), expected: failureRBAC},
+		{name: "server timeout", err: fmt.Errorf("failed to patch ManifestWork: %w", apierrors.NewServerTimeout(resource, "patch", 5)), expected: failureAPITimeout},
+		{name: "phase timeout", err: fmt.Errorf("updating ManifestWork for cluster a1 timed out after 1m0s: %w", context.DeadlineExceeded), expected: failureAPITimeout},
+		{name: "manifest not found", err: withFailureClass(failureManifestNotFound, errors.New("ManifestWork mgmt/a1 not found")), expected: failureManifestNotFound},
+		{name: "sync timeout", err: withFailureClass(failureSyncTimeout, errors.New("timeout waiting for sync after 5m0s")), expected: failureSyncTimeout},
+		{name: "other", err: errors.New("HostedCluster not found in ManifestWork manifests"), expected: failureOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifyFailure(tt.err); result != tt.expected {
+				t.Errorf("classifyFailure() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMigrateRetriesTransientFailures verifies a cluster whose ManifestWork update keeps conflicting is migrated
+// again up to --max-retries times, and that a ManifestWork that does not exist is not retried.
+func TestMigrateRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxRetries      int
+		expectedStatus  string
+		expectedClass   string
+		expectedRetries int
+	}{
+		{name: "retried until it succeeds", maxRetries: 2, expectedStatus: "success", expectedRetries: 2},
+		{name: "retries exhausted", maxRetries: 1, expectedStatus: "failed", expectedClass: failureConflict, expectedRetries: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testScheme(t)
+			hc := newTestHostedCluster("a1", nil)
+			mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestNamespace(hc.Namespace), hc.DeepCopy()).Build()
+			agent := &workAgent{mgmtClient: mgmtClient, delay: 20 * time.Millisecond, conflicts: 2}
+			t.Cleanup(agent.wait)
+			serviceClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).
+				WithInterceptorFuncs(agent.interceptors()).Build()
+
+			m := &migrateOpts{
+				mgmtClusterName: "mgmt-cluster",
+				mgmtClient:      mgmtClient,
+				serviceClient:   serviceClient,
+				patchStrategy:   "update",
+				syncTimeout:     500 * time.Millisecond,
+				pollInterval:    10 * time.Millisecond,
+				maxRetries:      tt.maxRetries,
+				retryDelay:      time.Millisecond,
+			}
+			info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace}
+			result := m.migrateWithRetries(context.Background(), info)
+			if result.Status != tt.expectedStatus || result.FailureClass != tt.expectedClass || result.Retries != tt.expectedRetries {
+				t.Errorf("migrateWithRetries() = %s (%s) after %d retries: %s, want %s (%s) after %d retries",
+					result.Status, result.FailureClass, result.Retries, result.Error, tt.expectedStatus, tt.expectedClass, tt.expectedRetries)
+			}
+		})
+	}
+
+	t.Run("manifest not found", func(t *testing.T) {
+		scheme := testScheme(t)
+		hc := newTestHostedCluster("a1", nil)
+		calls := 0
+		serviceClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptorFuncsCountingGets(&calls)).Build()
+		m := &migrateOpts{
+			mgmtClusterName: "mgmt-cluster",
+			mgmtClient:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc.DeepCopy()).Build(),
+			serviceClient:   serviceClient,
+			patchStrategy:   "update",
+			maxRetries:      2,
+			retryDelay:      time.Millisecond,
+		}
+		result := m.migrateWithRetries(context.Background(), hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace})
+		if result.FailureClass != failureManifestNotFound || result.Retries != 0 || calls != 1 {
+			t.Errorf("Expected a single manifest-not-found failure, got %s after %d retries and %d gets: %s",
+				result.FailureClass, result.Retries, calls, result.Error)
+		}
+	})
+}
+
+// TestDisplayFailuresByClass verifies failed migrations are grouped by class in the summary order, with what to
+// do about each class.
+func TestDisplayFailuresByClass(t *testing.T) {
+	var b bytes.Buffer
+	displayFailuresByClass(&b, []migrationResult{
+		{ClusterID: "c3", Status: "failed", FailureClass: failureConflict, Retries: 2, Error: "failed to patch ManifestWork"},
+		{ClusterID: "a1", Status: "failed", FailureClass: failureRBAC, Error: "forbidden"},
+		{ClusterID: "b2", Status: "failed", Error: "unexpected"},
+	})
+	result := b.String()
+	rbac := strings.Index(result, "✗ Failed, rbac (1): Check the backplane elevation")
+	conflict := strings.Index(result, "✗ Failed, conflict (1)")
+	other := strings.Index(result, "✗ Failed, other (1)")
+	if rbac < 0 || conflict < rbac || other < conflict {
+		t.Errorf("Expected rbac, conflict and other groups in order, got:\n%s", result)
+	}
+	if countFailureClass([]migrationResult{{}, {FailureClass: failureRBAC}}, failureOther) != 1 {
+		t.Error("Expected a failure without a class to count as other")
+	}
+}
+
+// interceptorFuncsCountingGets counts the Get calls of a fake client.
+func interceptorFuncsCountingGets(calls *int) interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*calls++
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+)
+
+// upgradeFreezeWindow is how far ahead a scheduled control plane upgrade keeps a cluster from being migrated.
+const upgradeFreezeWindow = 2 * time.Hour
+
+// frozenCluster is a candidate skipped because it is in maintenance or under a change freeze.
+type frozenCluster struct {
+	info    hostedClusterAuditInfo
+	reasons []string
+}
+
+// filterFrozen splits candidates into those that can be migrated and those that are upgrading, have
+// a control plane upgrade scheduled soon or are in limited support. A cluster whose checks fail is
+// treated as frozen.
+func (m *migrateOpts) filterFrozen(ctx context.Context, candidates []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, []frozenCluster) {
+	if m.ignoreFreeze {
+		if len(candidates) > 0 {
+			slog.Warn("Skipping maintenance and change freeze checks (--ignore-freeze)", "mgmtCluster", m.mgmtClusterName)
+		}
+		return candidates, nil
+	}
+
+	var allowed []hostedClusterAuditInfo
+	var frozen []frozenCluster
+	now := time.Now()
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		reasons, err := m.freezeReasons(ctx, c, now)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("freeze check failed: %v", err))
+		}
+		if len(reasons) > 0 {
+			slog.Info("Skipping cluster in maintenance or change freeze", "clusterID", c.ClusterID,
+				"reasons", strings.Join(reasons, "; "))
+			frozen = append(frozen, frozenCluster{info: c, reasons: reasons})
+			continue
+		}
+		allowed = append(allowed, c)
+	}
+
+	return allowed, frozen
+}
+
+// freezeReasons returns why a cluster must not be migrated now, checking the live HostedCluster for a
+// version rollout and OCM for control plane upgrade policies and limited support reasons.
+func (m *migrateOpts) freezeReasons(ctx context.Context, info hostedClusterAuditInfo, now time.Time) ([]string, error) {
+	var reasons []string
+
+	hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HostedCluster: %v", err)
+	}
+	if reason := hostedClusterRolloutReason(hc); reason != "" {
+		reasons = append(reasons, reason)
+	}
+
+	clusterClient := m.ocmConn.ClustersMgmt().V1().Clusters().Cluster(info.ClusterID)
+
+	policies, err := clusterClient.ControlPlane().UpgradePolicies().List().SendContext(ctx)
+	if err != nil {
+		return reasons, fmt.Errorf("failed to list control plane upgrade policies: %v", err)
+	}
+	for _, policy := range policies.Items().Slice() {
+		if reason := upgradePolicyReason(policy, now); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	limitedSupport, err := clusterClient.LimitedSupportReasons().List().SendContext(ctx)
+	if err != nil {
+		return reasons, fmt.Errorf("failed to list limited support reasons: %v", err)
+	}
+	for _, lsr := range limitedSupport.Items().Slice() {
+		reasons = append(reasons, fmt.Sprintf("in limited support: %s", lsr.Summary()))
+	}
+
+	return reasons, nil
+}
+
+// hostedClusterRolloutReason returns a reason if the HostedCluster is progressing or rolling out a version.
+func hostedClusterRolloutReason(hc *hypershiftv1beta1.HostedCluster) string {
+	for _, c := range hc.Status.Conditions {
+		if c.Type == string(hypershiftv1beta1.HostedClusterProgressing) && c.Status == "True" {
+			return fmt.Sprintf("HostedCluster is progressing: %s", c.Message)
+		}
+	}
+
+	if hc.Status.Version != nil && len(hc.Status.Version.History) > 0 {
+		latest := hc.Status.Version.History[0]
+		if string(latest.State) == "Partial" {
+			return fmt.Sprintf("version rollout to %s in progress", latest.Version)
+		}
+	}
+
+	return ""
+}
+
+// upgradePolicyReason returns a reason if a control plane upgrade policy is running or scheduled within
+// upgradeFreezeWindow of now.
+func upgradePolicyReason(policy *cmv1.ControlPlaneUpgradePolicy, now time.Time) string {
+	switch policy.State().Value() {
+	case cmv1.UpgradePolicyStateValueStarted, cmv1.UpgradePolicyStateValueDelayed:
+		return fmt.Sprintf("control plane upgrade to %s is %s", policy.Version(), policy.State().Value())
+	case cmv1.UpgradePolicyStateValuePending, cmv1.UpgradePolicyStateValueScheduled:
+		if policy.NextRun().Before(now.Add(upgradeFreezeWindow)) {
+			return fmt.Sprintf("control plane upgrade to %s scheduled at %s", policy.Version(),
+				policy.NextRun().UTC().Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// displayFrozen prints the candidates skipped because of maintenance or a change freeze.
+func displayFrozen(w io.Writer, frozen []frozenCluster) {
+	if len(frozen) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Skipped: Maintenance or Change Freeze (%d) ===\n\n", len(frozen))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+	for _, f := range frozen {
+		p.AddRow([]string{f.info.ClusterID, f.info.ClusterName, strings.Join(f.reasons, "; ")})
+	}
+	p.Flush()
+	fmt.Fprintln(w, "\nUse --ignore-freeze to migrate these clusters anyway.")
+}
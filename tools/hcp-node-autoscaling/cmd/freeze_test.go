@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestHostedClusterRolloutReason verifies progressing and partially rolled out HostedClusters are frozen.
+func TestHostedClusterRolloutReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   hypershiftv1beta1.HostedClusterStatus
+		expected string
+	}{
+		{
+			name:     "idle cluster",
+			status:   hypershiftv1beta1.HostedClusterStatus{},
+			expected: "",
+		},
+		{
+			name: "progressing condition",
+			status: hypershiftv1beta1.HostedClusterStatus{
+				Conditions: []metav1.Condition{{Type: "Progressing", Status: metav1.ConditionTrue, Message: "upgrading"}},
+			},
+			expected: "HostedCluster is progressing: upgrading",
+		},
+		{
+			name: "progressing condition false",
+			status: hypershiftv1beta1.HostedClusterStatus{
+				Conditions: []metav1.Condition{{Type: "Progressing", Status: metav1.ConditionFalse}},
+			},
+			expected: "",
+		},
+		{
+			name: "partial version rollout",
+			status: hypershiftv1beta1.HostedClusterStatus{
+				Version: &hypershiftv1beta1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{{State: configv1.PartialUpdate, Version: "4.16.2"}},
+				},
+			},
+			expected: "version rollout to 4.16.2 in progress",
+		},
+		{
+			name: "completed version rollout",
+			status: hypershiftv1beta1.HostedClusterStatus{
+				Version: &hypershiftv1beta1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: "4.16.2"}},
+				},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{Status: tt.status}
+			if result := hostedClusterRolloutReason(hc); result != tt.expected {
+				t.Errorf("hostedClusterRolloutReason() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestUpgradePolicyReason verifies running upgrades and upgrades scheduled within the freeze window are frozen.
+func TestUpgradePolicyReason(t *testing.T) {
+	now := time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		state    cmv1.UpgradePolicyStateValue
+		nextRun  time.Time
+		expected string
+	}{
+		{
+			name:     "started",
+			state:    cmv1.UpgradePolicyStateValueStarted,
+			nextRun:  now.Add(-time.Hour),
+			expected: "control plane upgrade to 4.16.2 is started",
+		},
+		{
+			name:     "scheduled within window",
+			state:    cmv1.UpgradePolicyStateValueScheduled,
+			nextRun:  now.Add(time.Hour),
+			expected: "control plane upgrade to 4.16.2 scheduled at 2026-01-27T11:00:00Z",
+		},
+		{
+			name:     "scheduled after window",
+			state:    cmv1.UpgradePolicyStateValueScheduled,
+			nextRun:  now.Add(24 * time.Hour),
+			expected: "",
+		},
+		{
+			name:     "completed",
+			state:    cmv1.UpgradePolicyStateValueCompleted,
+			nextRun:  now.Add(-time.Hour),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := cmv1.NewControlPlaneUpgradePolicy().
+				Version("4.16.2").
+				NextRun(tt.nextRun).
+				State(cmv1.NewUpgradePolicyState().Value(tt.state)).
+				Build()
+			if err != nil {
+				t.Fatalf("Failed to build upgrade policy: %v", err)
+			}
+			if result := upgradePolicyReason(policy, now); result != tt.expected {
+				t.Errorf("upgradePolicyReason() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFilterFrozen verifies clusters whose freeze checks fail are skipped unless --ignore-freeze is set.
+func TestFilterFrozen(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := hypershiftv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add hypershift scheme: %v", err)
+	}
+
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "cluster-id-1", ClusterName: "cluster-1", Namespace: "ocm-production-cluster-id-1"},
+	}
+
+	m := &migrateOpts{mgmtClient: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	allowed, frozen := m.filterFrozen(context.Background(), candidates)
+	if len(allowed) != 0 || len(frozen) != 1 {
+		t.Fatalf("Expected the cluster to be frozen, got %d allowed and %d frozen", len(allowed), len(frozen))
+	}
+	if !strings.Contains(frozen[0].reasons[0], "freeze check failed") {
+		t.Errorf("Expected a failed freeze check reason, got %v", frozen[0].reasons)
+	}
+
+	m.ignoreFreeze = true
+	allowed, frozen = m.filterFrozen(context.Background(), candidates)
+	if len(allowed) != 1 || len(frozen) != 0 {
+		t.Errorf("Expected --ignore-freeze to allow the cluster, got %d allowed and %d frozen", len(allowed), len(frozen))
+	}
+}
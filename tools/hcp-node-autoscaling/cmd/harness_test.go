@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fakeClientFactory returns preconfigured clients by cluster ID and records which were requested elevated.
+type fakeClientFactory struct {
+	clients  map[string]client.Client
+	elevated map[string]bool
+}
+
+func (f *fakeClientFactory) NewClient(clusterID string, _ *runtime.Scheme) (client.Client, error) {
+	c, ok := f.clients[clusterID]
+	if !ok {
+		return nil, fmt.Errorf("no fake client for cluster %s", clusterID)
+	}
+	return c, nil
+}
+
+func (f *fakeClientFactory) NewElevatedClient(clusterID string, scheme *runtime.Scheme, _ *sdk.Connection, _ string) (client.Client, error) {
+	if f.elevated == nil {
+		f.elevated = map[string]bool{}
+	}
+	f.elevated[clusterID] = true
+	return f.NewClient(clusterID, scheme)
+}
+
+func (f *fakeClientFactory) NewWatchClient(clusterID string, scheme *runtime.Scheme) (client.WithWatch, error) {
+	c, err := f.NewClient(clusterID, scheme)
+	if err != nil {
+		return nil, err
+	}
+	watchClient, ok := c.(client.WithWatch)
+	if !ok {
+		return nil, fmt.Errorf("fake client for cluster %s does not support watches", clusterID)
+	}
+	return watchClient, nil
+}
+
+// testScheme returns a scheme with every API group the tool reads or writes.
+func testScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme, err := scheme.NewScheme(scheme.HyperShift, scheme.Core, scheme.Work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+// newTestHostedCluster returns a HostedCluster for cluster ID id in its OCM production namespace.
+func newTestHostedCluster(id string, annotations map[string]string) *hypershiftv1beta1.HostedCluster {
+	return &hypershiftv1beta1.HostedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-" + id,
+			Namespace:   "ocm-production-" + id,
+			Labels:      map[string]string{"api.openshift.com/id": id},
+			Annotations: annotations,
+		},
+	}
+}
+
+// newTestNamespace returns the namespace of a HostedCluster.
+func newTestNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+// newTestManifestWork returns the ManifestWork that delivers hc to the management cluster.
+func newTestManifestWork(t *testing.T, mgmtClusterName string, hc *hypershiftv1beta1.HostedCluster) *workv1.ManifestWork {
+	t.Helper()
+	annotations := map[string]interface{}{}
+	for k, v := range hc.Annotations {
+		annotations[k] = v
+	}
+	hcJSON, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata": map[string]interface{}{
+			"name":        hc.Name,
+			"namespace":   hc.Namespace,
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal HostedCluster manifest: %v", err)
+	}
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: hc.Labels["api.openshift.com/id"], Namespace: mgmtClusterName},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+			},
+		},
+	}
+}
+
+// workAgent simulates the work agent on a management cluster. After delay it copies the HostedCluster
+// annotations of every ManifestWork written on the service cluster to the live HostedCluster. The first
+// conflicts writes fail with a conflict, and ManifestWorks listed in stalled are never synced. ManifestWorks
+// listed in rejected are not synced either; their Applied condition is set to False with the given message.
+type workAgent struct {
+	mgmtClient client.Client
+	delay      time.Duration
+	conflicts  int
+	stalled    map[string]bool
+	rejected   map[string]string
+
+	mu sync.Mutex
+	wg sync.WaitGroup
+}
+
+// interceptors returns the service cluster client interceptors that drive the simulated work agent.
+func (w *workAgent) interceptors() interceptor.Funcs {
+	return interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := w.injectConflict(obj); err != nil {
+				return err
+			}
+			if err := c.Update(ctx, obj, opts...); err != nil {
+				return err
+			}
+			w.scheduleSync(c, client.ObjectKeyFromObject(obj))
+			return nil
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := w.injectConflict(obj); err != nil {
+				return err
+			}
+			if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+				return err
+			}
+			w.scheduleSync(c, client.ObjectKeyFromObject(obj))
+			return nil
+		},
+	}
+}
+
+// injectConflict returns a conflict error while injected conflicts remain.
+func (w *workAgent) injectConflict(obj client.Object) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conflicts == 0 {
+		return nil
+	}
+	w.conflicts--
+	return apierrors.NewConflict(schema.GroupResource{Group: "work.open-cluster-management.io", Resource: "manifestworks"},
+		obj.GetName(), fmt.Errorf("the object has been modified"))
+}
+
+// scheduleSync copies the ManifestWork's HostedCluster annotations to the management cluster after delay.
+func (w *workAgent) scheduleSync(serviceClient client.Client, key client.ObjectKey) {
+	if w.stalled[key.Name] {
+		return
+	}
+	w.wg.Add(1)
+	time.AfterFunc(w.delay, func() {
+		defer w.wg.Done()
+		ctx := context.Background()
+
+		manifestWork := &workv1.ManifestWork{}
+		if err := serviceClient.Get(ctx, key, manifestWork); err != nil {
+			return
+		}
+		if message, ok := w.rejected[key.Name]; ok {
+			meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+				Type:               workv1.WorkApplied,
+				Status:             metav1.ConditionFalse,
+				Reason:             "AppliedManifestWorkFailed",
+				Message:            message,
+				ObservedGeneration: manifestWork.Generation,
+			})
+			_ = serviceClient.Update(ctx, manifestWork)
+			return
+		}
+		_, manifestData, err := findHostedClusterManifest(manifestWork)
+		if err != nil {
+			return
+		}
+		metadata, _ := manifestData["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		namespace, _ := metadata["namespace"].(string)
+
+		hc := &hypershiftv1beta1.HostedCluster{}
+		if err := w.mgmtClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, hc); err != nil {
+			return
+		}
+		hc.Annotations = manifestAnnotations(manifestData)
+		_ = w.mgmtClient.Update(ctx, hc)
+	})
+}
+
+// wait blocks until every scheduled sync has run.
+func (w *workAgent) wait() {
+	w.wg.Wait()
+}
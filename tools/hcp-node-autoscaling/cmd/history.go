@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+// defaultElevationReason describes the change in the backplane elevation reason and the run history
+// unless --elevation-reason is set.
+const defaultElevationReason = "Migrating hosted clusters to node autoscaling"
+
+// ticketPattern matches a JIRA issue key such as OHSS-12345.
+var ticketPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// runHistory is the audit trail of a single migrate run. It is rewritten after every cluster so
+// that the record survives an interrupted run. It is safe for concurrent use. A nil *runHistory
+// records nothing.
+type runHistory struct {
+	RunID            string             `json:"run_id,omitempty"`
+	StartedAt        string             `json:"started_at"`
+	FinishedAt       string             `json:"finished_at,omitempty"`
+	Operator         string             `json:"operator"`
+	Ticket           string             `json:"ticket,omitempty"`
+	ElevationReason  string             `json:"elevation_reason"`
+	ServiceClusterID string             `json:"service_cluster_id"`
+	MgmtClusterID    string             `json:"mgmt_cluster_id"`
+	PatchStrategy    string             `json:"patch_strategy"`
+	Profile          string             `json:"profile"`
+	Changes          []annotationChange `json:"changes"`
+
+	path      string
+	profile   *migrationProfile
+	overrides map[string]map[string]string
+	mu        sync.Mutex
+}
+
+// annotationChange records an annotation change attempted on a single hosted cluster.
+// An empty Before or After value means the annotation was not set.
+type annotationChange struct {
+	ClusterID        string `json:"cluster_id"`
+	ClusterName      string `json:"cluster_name"`
+	Annotation       string `json:"annotation"`
+	Before           string `json:"before"`
+	After            string `json:"after"`
+	Status           string `json:"status"`
+	Error            string `json:"error,omitempty"`
+	ChangedAt        string `json:"changed_at"`
+	ServiceLogPosted bool   `json:"service_log_posted,omitempty"`
+
+	// ManifestWorkOutOfSync is set when the change was applied directly to the HostedCluster with --direct
+	// and the ManifestWork still needs the same change.
+	ManifestWorkOutOfSync bool `json:"manifestwork_out_of_sync,omitempty"`
+
+	// FailureClass is the failure class of a failed change, e.g. rbac or sync-timeout.
+	FailureClass string `json:"failure_class,omitempty"`
+}
+
+// defaultHistoryDir returns the directory migrate run histories are written to.
+func defaultHistoryDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "hcp-node-autoscaling", "history"), nil
+}
+
+// elevation returns the reason recorded on the backplane elevation, in the run history and in service
+// log entries: the ticket approving the change followed by its description.
+func (m *migrateOpts) elevation() string {
+	reason := m.elevationReason
+	if reason == "" {
+		reason = defaultElevationReason
+	}
+	if m.ticket == "" {
+		return reason
+	}
+	return fmt.Sprintf("%s - %s", m.ticket, reason)
+}
+
+// validateTicket checks the --ticket flag. A ticket is required for every run that changes clusters, so
+// each change is traceable to an approved ticket.
+func validateTicket(ticket string, required bool) error {
+	if ticket == "" {
+		if required {
+			return fmt.Errorf("--ticket is required: pass the JIRA issue approving the change, e.g. --ticket OHSS-12345")
+		}
+		return nil
+	}
+	if !ticketPattern.MatchString(ticket) {
+		return fmt.Errorf("invalid ticket '%s'. Expected a JIRA issue key such as OHSS-12345", ticket)
+	}
+	return nil
+}
+
+// newRunHistory creates the history for a migrate run, written to a timestamped file in dir.
+func (m *migrateOpts) newRunHistory(dir string, startedAt time.Time) *runHistory {
+	fileName := fmt.Sprintf("%s-%s.json", startedAt.UTC().Format("20060102T150405Z"), m.mgmtClusterID)
+	patchStrategy := m.patchStrategy
+	if m.direct {
+		patchStrategy = directPatchStrategy
+	}
+	return &runHistory{
+		RunID:            m.session.runID(),
+		StartedAt:        startedAt.UTC().Format(time.RFC3339),
+		Operator:         m.operator,
+		Ticket:           m.ticket,
+		ElevationReason:  m.elevation(),
+		ServiceClusterID: m.serviceClusterID,
+		MgmtClusterID:    m.mgmtClusterID,
+		PatchStrategy:    patchStrategy,
+		Profile:          m.profile.orDefault().Name,
+		Changes:          []annotationChange{},
+		path:             filepath.Join(dir, fileName),
+		profile:          m.profile,
+		overrides:        m.overrides,
+	}
+}
+
+// record adds the outcome of a cluster migration to the history and saves it. One change is recorded
+// for each ensured annotation and for each removed annotation that was set on the cluster.
+func (h *runHistory) record(info hostedClusterAuditInfo, result migrationResult, serviceLogPosted bool) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	profile := h.profile.withOverrides(h.overrides[info.ClusterID])
+	changedAt := time.Now().UTC().Format(time.RFC3339)
+
+	for _, annotation := range profile.annotationKeys() {
+		before, wasSet := info.Annotations[annotation]
+		target, ensured := profile.Ensure[annotation]
+		if !ensured && !wasSet {
+			continue
+		}
+
+		change := annotationChange{
+			ClusterID:        info.ClusterID,
+			ClusterName:      info.ClusterName,
+			Annotation:       annotation,
+			Before:           before,
+			After:            before,
+			Status:           result.Status,
+			Error:            result.Error,
+			FailureClass:     result.FailureClass,
+			ChangedAt:        changedAt,
+			ServiceLogPosted: serviceLogPosted,
+		}
+		if result.Status == "success" || result.Status == pendingVerificationStatus {
+			change.After = target
+			change.ManifestWorkOutOfSync = h.PatchStrategy == directPatchStrategy
+		}
+
+		h.Changes = append(h.Changes, change)
+	}
+
+	return h.save()
+}
+
+// finish marks the run as finished and saves the history.
+func (h *runHistory) finish() error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	return h.save()
+}
+
+// save writes the history file, creating its directory if needed.
+func (h *runHistory) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %v", err)
+	}
+
+	if err := os.WriteFile(h.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write history file %s: %v", h.path, err)
+	}
+
+	return nil
+}
+
+// currentOperator returns the OCM username of the person running the tool.
+func currentOperator(conn *sdk.Connection) string {
+	account, err := conn.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		slog.Warn("Failed to determine OCM account for history", "error", err)
+		return "unknown"
+	}
+	username, ok := account.Body().GetUsername()
+	if !ok {
+		return "unknown"
+	}
+	return username
+}
+
+// describeAnnotationChanges describes the annotations set and removed on a cluster for its service log.
+func describeAnnotationChanges(set, removed []string) string {
+	switch {
+	case len(removed) == 0:
+		return "Set " + strings.Join(set, ", ")
+	case len(set) == 0:
+		return "Removed " + strings.Join(removed, ", ")
+	default:
+		return fmt.Sprintf("Set %s and removed %s", strings.Join(set, ", "), strings.Join(removed, ", "))
+	}
+}
+
+// postServiceLog posts an internal OCM service log entry recording the annotation changes made to a cluster.
+func (m *migrateOpts) postServiceLog(info hostedClusterAuditInfo) error {
+	cluster, err := utils.GetCluster(m.ocmConn, info.ClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %v", err)
+	}
+
+	profile := m.clusterProfile(info.ClusterID).orDefault()
+	var set, removed []string
+	for _, key := range profile.ensureKeys() {
+		set = append(set, fmt.Sprintf("%s=%q", key, profile.Ensure[key]))
+	}
+	for _, key := range profile.Remove {
+		if _, ok := info.Annotations[key]; ok {
+			removed = append(removed, key)
+		}
+	}
+
+	summary := m.serviceLogSummary
+	if summary == "" {
+		summary = "Enabled resource-based control plane autoscaling"
+	}
+
+	logEntry, err := slv1.NewLogEntry().
+		ClusterUUID(cluster.ExternalID()).
+		ClusterID(cluster.ID()).
+		InternalOnly(true).
+		Severity(slv1.SeverityInfo).
+		ServiceName("SREManualAction").
+		Summary(summary).
+		Description(fmt.Sprintf("%s on the HostedCluster via its ManifestWork on service cluster %s "+
+			"(migration profile %s). Operator: %s. Reason: %s. Run ID: %s.",
+			describeAnnotationChanges(set, removed), m.serviceClusterID, profile.Name, m.operator, m.elevation(), m.session.runID())).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build service log entry: %v", err)
+	}
+
+	if _, err := m.ocmConn.ServiceLogs().V1().ClusterLogs().Add().Body(logEntry).Send(); err != nil {
+		return fmt.Errorf("failed to post service log: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRunHistoryRecord verifies migration outcomes are written to the history file after every cluster.
+func TestRunHistoryRecord(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	m := &migrateOpts{
+		serviceClusterID: "svc-123",
+		mgmtClusterID:    "mgmt-456",
+		patchStrategy:    "update",
+		operator:         "jdoe",
+		ticket:           "OHSS-12345",
+		session:          &runSession{id: "run-789"},
+	}
+
+	h := m.newRunHistory(dir, time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC))
+	if h.path != filepath.Join(dir, "20260127T100000Z-mgmt-456.json") {
+		t.Fatalf("Unexpected history path: %s", h.path)
+	}
+
+	migrated := hostedClusterAuditInfo{ClusterID: "cluster-1", ClusterName: "one"}
+	failed := hostedClusterAuditInfo{
+		ClusterID:   "cluster-2",
+		ClusterName: "two",
+		Annotations: map[string]string{"hypershift.openshift.io/resource-based-cp-auto-scaling": "false"},
+	}
+
+	if err := h.record(migrated, migrationResult{Status: "success"}, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := h.record(failed, migrationResult{Status: "failed", Error: "timeout"}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := h.finish(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(h.path)
+	if err != nil {
+		t.Fatalf("History file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected history file mode 0600, got %o", perm)
+	}
+
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+
+	var saved runHistory
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse history file: %v", err)
+	}
+
+	if saved.Operator != "jdoe" || saved.Ticket != "OHSS-12345" || saved.ElevationReason != "OHSS-12345 - "+defaultElevationReason {
+		t.Errorf("Unexpected run metadata: operator=%s ticket=%s reason=%s", saved.Operator, saved.Ticket, saved.ElevationReason)
+	}
+	if saved.RunID != "run-789" {
+		t.Errorf("Expected run_id run-789, got %q", saved.RunID)
+	}
+	if saved.FinishedAt == "" {
+		t.Errorf("Expected finished_at to be set")
+	}
+	// One change per ensured annotation: autoscaling, then topology.
+	if len(saved.Changes) != 4 {
+		t.Fatalf("Expected 4 changes, got %d", len(saved.Changes))
+	}
+
+	if c := saved.Changes[0]; c.Before != "" || c.After != "true" || !c.ServiceLogPosted {
+		t.Errorf("Unexpected change for migrated cluster: %+v", c)
+	}
+	if c := saved.Changes[1]; c.Annotation != topologyAnnotation || c.After != dedicatedTopology {
+		t.Errorf("Unexpected topology change for migrated cluster: %+v", c)
+	}
+	if c := saved.Changes[2]; c.Before != "false" || c.After != "false" || c.Error != "timeout" {
+		t.Errorf("Unexpected change for failed cluster: %+v", c)
+	}
+}
+
+// TestRunHistoryNil verifies a nil history records nothing.
+func TestRunHistoryNil(t *testing.T) {
+	var h *runHistory
+	if err := h.record(hostedClusterAuditInfo{}, migrationResult{}, false); err != nil {
+		t.Errorf("Expected nil history record to be a no-op, got %v", err)
+	}
+	if err := h.finish(); err != nil {
+		t.Errorf("Expected nil history finish to be a no-op, got %v", err)
+	}
+}
+
+// TestValidateTicket verifies tickets must be JIRA issue keys and are required only when clusters are changed.
+func TestValidateTicket(t *testing.T) {
+	tests := []struct {
+		name        string
+		ticket      string
+		required    bool
+		expectError bool
+	}{
+		{name: "issue key", ticket: "OHSS-12345", required: true},
+		{name: "project key with digits", ticket: "SREP2-7", required: true},
+		{name: "missing", required: true, expectError: true},
+		{name: "missing for a dry run"},
+		{name: "lower case", ticket: "ohss-12345", expectError: true},
+		{name: "free text", ticket: "migrating clusters", expectError: true},
+		{name: "URL", ticket: "https://issues.redhat.com/browse/OHSS-12345", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTicket(tt.ticket, tt.required)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateTicket(%q) error = %v, expectError %v", tt.ticket, err, tt.expectError)
+			}
+		})
+	}
+}
+
+// TestElevation verifies the ticket is recorded before the description of the change.
+func TestElevation(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     migrateOpts
+		expected string
+	}{
+		{name: "default", expected: defaultElevationReason},
+		{name: "ticket", opts: migrateOpts{ticket: "OHSS-1"}, expected: "OHSS-1 - " + defaultElevationReason},
+		{name: "custom description", opts: migrateOpts{ticket: "OHSS-1", elevationReason: "Staging rollout"}, expected: "OHSS-1 - Staging rollout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.elevation(); got != tt.expected {
+				t.Errorf("elevation() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDescribeAnnotationChanges verifies the service log description of set and removed annotations.
+func TestDescribeAnnotationChanges(t *testing.T) {
+	tests := []struct {
+		set      []string
+		removed  []string
+		expected string
+	}{
+		{set: []string{`a="true"`}, expected: `Set a="true"`},
+		{removed: []string{"b", "c"}, expected: "Removed b, c"},
+		{set: []string{`a="true"`}, removed: []string{"b"}, expected: `Set a="true" and removed b`},
+	}
+
+	for _, tt := range tests {
+		if got := describeAnnotationChanges(tt.set, tt.removed); got != tt.expected {
+			t.Errorf("describeAnnotationChanges(%v, %v) = %q, want %q", tt.set, tt.removed, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestAuditEndToEnd verifies namespaces are listed and every hosted cluster is audited, categorized and
+// checked for drift against fake management and service clusters.
+func TestAuditEndToEnd(t *testing.T) {
+	scheme := testScheme(t)
+
+	needsRemoval := newTestHostedCluster("a1", map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"})
+	ready := newTestHostedCluster("b2", nil)
+	configured := newTestHostedCluster("c3", map[string]string{"hypershift.openshift.io/resource-based-cp-auto-scaling": "true"})
+	staging := newTestHostedCluster("d4", nil)
+	staging.Namespace = "ocm-staging-d4"
+
+	mgmtClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newTestNamespace(needsRemoval.Namespace), needsRemoval,
+			newTestNamespace(ready.Namespace), ready,
+			newTestNamespace(configured.Namespace), configured,
+			newTestNamespace(staging.Namespace), staging,
+			newTestNamespace("ocm-production-e5"),
+			newTestNamespace("kube-system"),
+		).
+		Build()
+
+	// The configured cluster's ManifestWork does not carry the autoscaling annotation, so it has drifted.
+	configuredManifest := newTestHostedCluster("c3", nil)
+	serviceClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newTestManifestWork(t, "mgmt-cluster", needsRemoval),
+			newTestManifestWork(t, "mgmt-cluster", ready),
+			newTestManifestWork(t, "mgmt-cluster", configuredManifest),
+		).
+		Build()
+
+	a := &auditOpts{
+		environment:     "production",
+		checkDrift:      true,
+		mgmtClient:      mgmtClient,
+		serviceClient:   serviceClient,
+		mgmtClusterName: "mgmt-cluster",
+	}
+
+	ctx := context.Background()
+	namespaces, err := a.listOcmNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list namespaces: %v", err)
+	}
+	if len(namespaces) != 4 {
+		t.Fatalf("Expected 4 production namespaces, got %d", len(namespaces))
+	}
+
+	results, audited := a.auditNamespaces(ctx, namespaces)
+	if audited != 4 || results.Partial {
+		t.Errorf("Expected all 4 namespaces audited, got %d (partial %v)", audited, results.Partial)
+	}
+
+	categories := map[string][]hostedClusterAuditInfo{
+		"needs-removal":       results.NeedsLabelRemoval,
+		"ready-for-migration": results.ReadyForMigration,
+		"already-configured":  results.AlreadyConfigured,
+		"drifted":             results.Drifted,
+	}
+	expected := map[string]string{"needs-removal": "a1", "ready-for-migration": "b2", "drifted": "c3"}
+	for category, clusters := range categories {
+		want, ok := expected[category]
+		if !ok {
+			if len(clusters) != 0 {
+				t.Errorf("Expected no %s clusters, got %+v", category, clusters)
+			}
+			continue
+		}
+		if len(clusters) != 1 || clusters[0].ClusterID != want {
+			t.Errorf("Expected %s to contain %s, got %+v", category, want, clusters)
+		}
+	}
+
+	if results.TotalScanned != 3 {
+		t.Errorf("Expected 3 clusters scanned, got %d", results.TotalScanned)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Namespace != "ocm-production-e5" {
+		t.Errorf("Expected an error for the namespace without a HostedCluster, got %+v", results.Errors)
+	}
+}
+
+// TestMigrateEndToEnd verifies clients are created through the factory and that candidates are patched,
+// retried on conflict and verified once the simulated work agent syncs the annotations, with an event
+// for each patch, verification and failure.
+func TestMigrateEndToEnd(t *testing.T) {
+	tests := []struct {
+		name            string
+		patchStrategy   string
+		conflicts       int
+		stalled         map[string]bool
+		rejected        map[string]string
+		expectedStatus  map[string]string
+		expectedRetries int
+		expectedError   string
+		expectedClass   string
+	}{
+		{
+			name:           "update with delayed sync",
+			patchStrategy:  "update",
+			expectedStatus: map[string]string{"a1": "success", "b2": "success"},
+		},
+		{
+			name:            "update with conflicts",
+			patchStrategy:   "update",
+			conflicts:       2,
+			expectedStatus:  map[string]string{"a1": "success", "b2": "success"},
+			expectedRetries: 2,
+		},
+		{
+			name:           "json-patch with delayed sync",
+			patchStrategy:  "json-patch",
+			expectedStatus: map[string]string{"a1": "success", "b2": "success"},
+		},
+		{
+			name:           "work agent never syncs",
+			patchStrategy:  "update",
+			stalled:        map[string]bool{"b2": true},
+			expectedStatus: map[string]string{"a1": "success", "b2": "failed"},
+			expectedError:  "ManifestWork Applied=Unknown, Available=Unknown",
+			expectedClass:  failureSyncTimeout,
+		},
+		{
+			name:           "work agent rejects manifest",
+			patchStrategy:  "update",
+			rejected:       map[string]string{"a1": "admission webhook denied the request"},
+			expectedStatus: map[string]string{"a1": "failed", "b2": "success"},
+			expectedError:  "work agent failed to apply ManifestWork mgmt-cluster/a1: Applied=False (AppliedManifestWorkFailed): admission webhook denied the request",
+			expectedClass:  failureOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testScheme(t)
+
+			hostedClusters := []*hypershiftv1beta1.HostedCluster{
+				newTestHostedCluster("a1", nil),
+				newTestHostedCluster("b2", map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"}),
+			}
+
+			var mgmtObjects, serviceObjects []client.Object
+			var candidates []hostedClusterAuditInfo
+			for _, hc := range hostedClusters {
+				mgmtObjects = append(mgmtObjects, newTestNamespace(hc.Namespace), hc.DeepCopy())
+				serviceObjects = append(serviceObjects, newTestManifestWork(t, "mgmt-cluster", hc))
+				candidates = append(candidates, hostedClusterAuditInfo{
+					ClusterID:   hc.Labels["api.openshift.com/id"],
+					ClusterName: hc.Name,
+					Namespace:   hc.Namespace,
+					Annotations: hc.Annotations,
+				})
+			}
+
+			mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mgmtObjects...).Build()
+			agent := &workAgent{
+				mgmtClient: mgmtClient,
+				delay:      50 * time.Millisecond,
+				conflicts:  tt.conflicts,
+				stalled:    tt.stalled,
+				rejected:   tt.rejected,
+			}
+			t.Cleanup(agent.wait)
+			serviceClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(serviceObjects...).
+				WithInterceptorFuncs(agent.interceptors()).
+				Build()
+
+			factory := &fakeClientFactory{clients: map[string]client.Client{"mgmt-id": mgmtClient, "svc-id": serviceClient}}
+			events := &bytes.Buffer{}
+			m := &migrateOpts{
+				events:           newEventStream(events, ""),
+				serviceClusterID: "svc-id",
+				mgmtClusterID:    "mgmt-id",
+				mgmtClusterName:  "mgmt-cluster",
+				clients:          factory,
+				patchStrategy:    tt.patchStrategy,
+				conflictRetries:  3,
+				syncTimeout:      500 * time.Millisecond,
+				pollInterval:     20 * time.Millisecond,
+			}
+
+			ctx := context.Background()
+			if err := m.createClients(ctx); err != nil {
+				t.Fatalf("Failed to create clients: %v", err)
+			}
+			if !factory.elevated["svc-id"] || factory.elevated["mgmt-id"] {
+				t.Errorf("Expected only the service cluster client to be elevated, got %v", factory.elevated)
+			}
+
+			results := m.migrateClusters(ctx, candidates)
+			if len(results) != len(candidates) {
+				t.Fatalf("Expected %d results, got %d", len(candidates), len(results))
+			}
+
+			retries := 0
+			statuses := map[string]int{}
+			for _, r := range results {
+				retries += r.ConflictRetries
+				statuses[r.Status]++
+				if r.Status != tt.expectedStatus[r.ClusterID] {
+					t.Errorf("Cluster %s status = %q (%s), want %q", r.ClusterID, r.Status, r.Error, tt.expectedStatus[r.ClusterID])
+				}
+				if r.Status == "failed" && !strings.Contains(r.Error, "sync verification failed") {
+					t.Errorf("Expected sync verification failure for %s, got %q", r.ClusterID, r.Error)
+				}
+				if r.Status == "failed" && !strings.Contains(r.Error, tt.expectedError) {
+					t.Errorf("Expected error for %s to contain %q, got %q", r.ClusterID, tt.expectedError, r.Error)
+				}
+				if r.Status == "failed" && r.FailureClass != tt.expectedClass {
+					t.Errorf("Expected failure class %q for %s, got %q", tt.expectedClass, r.ClusterID, r.FailureClass)
+				}
+				if r.Status != "success" {
+					continue
+				}
+
+				hc := &hypershiftv1beta1.HostedCluster{}
+				key := client.ObjectKey{Namespace: "ocm-production-" + r.ClusterID, Name: r.ClusterName}
+				if err := mgmtClient.Get(ctx, key, hc); err != nil {
+					t.Fatalf("Failed to get HostedCluster: %v", err)
+				}
+				if hc.Annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+					t.Errorf("Expected autoscaling annotation on %s, got %v", r.ClusterID, hc.Annotations)
+				}
+				if _, ok := hc.Annotations["hypershift.openshift.io/cluster-size-override"]; ok {
+					t.Errorf("Expected size override to be removed from %s, got %v", r.ClusterID, hc.Annotations)
+				}
+			}
+			if retries != tt.expectedRetries {
+				t.Errorf("Expected %d conflict retries, got %d", tt.expectedRetries, retries)
+			}
+
+			counts := map[string]int{}
+			for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+				event := streamEvent{}
+				if err := json.Unmarshal([]byte(line), &event); err != nil {
+					t.Fatalf("Failed to parse event %q: %v", line, err)
+				}
+				counts[event.Event]++
+			}
+			if counts[eventSyncVerified] != statuses["success"] || counts[eventError] != statuses["failed"] ||
+				counts[eventPatchApplied] != statuses["success"]+statuses["failed"] {
+				t.Errorf("Unexpected event counts %v for results %v", counts, statuses)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"golang.org/x/term"
+)
+
+const selectionHelp = `Commands:
+  <numbers>   Toggle clusters by number, e.g. "1", "1,3", "2-5"
+  a           Select all clusters
+  n           Deselect all clusters
+  d <number>  Show labels and annotations for a cluster
+  c           Confirm selection and continue
+  q           Quit without migrating`
+
+// candidateSelector lets the operator pick a subset of migration candidates from the terminal.
+type candidateSelector struct {
+	candidates []hostedClusterAuditInfo
+	selected   []bool
+	in         *bufio.Reader
+	out        io.Writer
+
+	// cursor is the candidate highlighted in the terminal UI, whose labels and annotations are shown in
+	// its detail pane, and offset the first candidate of the list that fits on the screen.
+	cursor int
+	offset int
+}
+
+// selectCandidates interactively lets the operator choose which candidates to migrate: in a terminal UI
+// when in is a terminal, and at a line prompt otherwise, e.g. when the selection is piped in.
+// All candidates start selected; the returned slice contains only the confirmed selection.
+func (m *migrateOpts) selectCandidates(candidates []hostedClusterAuditInfo, in io.Reader, out io.Writer) ([]hostedClusterAuditInfo, error) {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ClusterID < candidates[j].ClusterID
+	})
+
+	s := &candidateSelector{
+		candidates: candidates,
+		selected:   make([]bool, len(candidates)),
+		in:         bufio.NewReader(in),
+		out:        out,
+	}
+	s.setAll(true)
+
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return s.selectInTerminal(f)
+	}
+	return s.selectAtPrompt()
+}
+
+// selectAtPrompt lets the operator toggle candidates by number at a line prompt.
+func (s *candidateSelector) selectAtPrompt() ([]hostedClusterAuditInfo, error) {
+	out := s.out
+	fmt.Fprintf(out, "\n=== Clusters Ready for Migration (%d) ===\n", len(s.candidates))
+	fmt.Fprintf(out, "\n%s\n", selectionHelp)
+
+	for {
+		s.render()
+
+		fmt.Fprint(out, "Selection> ")
+		line, err := s.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, fmt.Errorf("selection cancelled: %v", err)
+		}
+		input := strings.TrimSpace(line)
+
+		switch {
+		case input == "":
+			continue
+		case input == "c":
+			return s.selection(), nil
+		case input == "q":
+			return nil, fmt.Errorf("migration cancelled by user")
+		case input == "a":
+			s.setAll(true)
+		case input == "n":
+			s.setAll(false)
+		case input == "?" || input == "h":
+			fmt.Fprintf(out, "\n%s\n", selectionHelp)
+		case strings.HasPrefix(input, "d"):
+			idx, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(input, "d")))
+			if err != nil || idx < 1 || idx > len(s.candidates) {
+				fmt.Fprintf(out, "Invalid cluster number for details: %q\n", input)
+				continue
+			}
+			fmt.Fprintln(out)
+			s.showDetails(out, s.candidates[idx-1])
+		default:
+			indices, err := parseIndexSelection(input, len(s.candidates))
+			if err != nil {
+				fmt.Fprintf(out, "Invalid selection: %v\n", err)
+				continue
+			}
+			for _, idx := range indices {
+				s.selected[idx] = !s.selected[idx]
+			}
+		}
+	}
+}
+
+// setAll marks every candidate as selected or deselected.
+func (s *candidateSelector) setAll(selected bool) {
+	for i := range s.selected {
+		s.selected[i] = selected
+	}
+}
+
+// selection returns the currently selected candidates.
+func (s *candidateSelector) selection() []hostedClusterAuditInfo {
+	var result []hostedClusterAuditInfo
+	for i, c := range s.candidates {
+		if s.selected[i] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// render prints the candidate table with selection markers.
+func (s *candidateSelector) render() {
+	fmt.Fprintln(s.out)
+
+	p := output.NewTable(s.out, output.TableMinWidth)
+	p.AddRow([]string{"#", "SELECTED", "CLUSTER ID", "CLUSTER NAME", "NAMESPACE", "CURRENT SIZE"})
+	for i, c := range s.candidates {
+		marker := "[ ]"
+		if s.selected[i] {
+			marker = "[x]"
+		}
+		p.AddRow([]string{strconv.Itoa(i + 1), marker, c.ClusterID, c.ClusterName, c.Namespace, c.CurrentSize})
+	}
+	p.Flush()
+
+	fmt.Fprintf(s.out, "\n%d of %d clusters selected\n", len(s.selection()), len(s.candidates))
+}
+
+// showDetails prints the labels and annotations of a single candidate.
+func (s *candidateSelector) showDetails(w io.Writer, c hostedClusterAuditInfo) {
+	fmt.Fprintf(w, "--- %s (%s) ---\n", c.ClusterName, c.ClusterID)
+	fmt.Fprintf(w, "Namespace: %s\n", c.Namespace)
+	fmt.Fprintf(w, "Current size: %s\n", c.CurrentSize)
+
+	fmt.Fprintln(w, "Labels:")
+	printSortedMap(w, c.Labels)
+
+	fmt.Fprintln(w, "Annotations:")
+	printSortedMap(w, c.Annotations)
+}
+
+// printSortedMap prints key/value pairs in key order, one per line.
+func printSortedMap(out io.Writer, m map[string]string) {
+	if len(m) == 0 {
+		fmt.Fprintln(out, "  (none)")
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(out, "  %s: %s\n", k, m[k])
+	}
+}
+
+// parseIndexSelection parses a comma separated list of 1-based numbers and ranges
+// (e.g. "1,3-5") into 0-based indices, validating them against max.
+func parseIndexSelection(input string, max int) ([]int, error) {
+	var indices []int
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end := part, part
+		if before, after, found := strings.Cut(part, "-"); found {
+			start, end = strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+
+		from, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", start)
+		}
+		to, err := strconv.Atoi(end)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", end)
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid range '%s'", part)
+		}
+		if from < 1 || to > max {
+			return nil, fmt.Errorf("'%s' is out of range (1-%d)", part, max)
+		}
+
+		for i := from; i <= to; i++ {
+			indices = append(indices, i-1)
+		}
+	}
+
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("no clusters specified")
+	}
+
+	return indices, nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseIndexSelection verifies parsing of numeric selections and ranges.
+func TestParseIndexSelection(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		max         int
+		expected    []int
+		expectError bool
+	}{
+		{
+			name:     "single number",
+			input:    "2",
+			max:      3,
+			expected: []int{1},
+		},
+		{
+			name:     "comma separated numbers",
+			input:    "1, 3",
+			max:      3,
+			expected: []int{0, 2},
+		},
+		{
+			name:     "range",
+			input:    "2-4",
+			max:      5,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "mixed numbers and ranges",
+			input:    "1,3-4",
+			max:      4,
+			expected: []int{0, 2, 3},
+		},
+		{
+			name:        "out of range",
+			input:       "5",
+			max:         3,
+			expectError: true,
+		},
+		{
+			name:        "zero is out of range",
+			input:       "0",
+			max:         3,
+			expectError: true,
+		},
+		{
+			name:        "reversed range",
+			input:       "3-1",
+			max:         3,
+			expectError: true,
+		},
+		{
+			name:        "not a number",
+			input:       "abc",
+			max:         3,
+			expectError: true,
+		},
+		{
+			name:        "empty selection",
+			input:       ",",
+			max:         3,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseIndexSelection(tt.input, tt.max)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseIndexSelection() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseIndexSelection() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSelectCandidates verifies interactive selection driven by scripted input.
+func TestSelectCandidates(t *testing.T) {
+	candidates := func() []hostedClusterAuditInfo {
+		return []hostedClusterAuditInfo{
+			{ClusterID: "cluster3", ClusterName: "three"},
+			{ClusterID: "cluster1", ClusterName: "one", Labels: map[string]string{"size": "large"}},
+			{ClusterID: "cluster2", ClusterName: "two"},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedIDs []string
+		expectError bool
+	}{
+		{
+			name:        "confirm keeps all clusters selected",
+			input:       "c\n",
+			expectedIDs: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:        "toggle off a cluster",
+			input:       "2\nc\n",
+			expectedIDs: []string{"cluster1", "cluster3"},
+		},
+		{
+			name:        "deselect all then select one",
+			input:       "n\n3\nc\n",
+			expectedIDs: []string{"cluster3"},
+		},
+		{
+			name:        "invalid input is ignored",
+			input:       "9\nfoo\nd 7\nd 1\nc\n",
+			expectedIDs: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:        "quit cancels migration",
+			input:       "q\n",
+			expectError: true,
+		},
+		{
+			name:        "end of input cancels migration",
+			input:       "1\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &migrateOpts{}
+			out := &bytes.Buffer{}
+
+			selected, err := opts.selectCandidates(candidates(), strings.NewReader(tt.input), out)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("selectCandidates() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+
+			var ids []string
+			for _, c := range selected {
+				ids = append(ids, c.ClusterID)
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("selectCandidates() = %v, want %v", ids, tt.expectedIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/spf13/cobra"
+)
+
+// kubeconfigOverrides holds the --mgmt-kubeconfig and --service-kubeconfig flags, which reach the clusters
+// with the credentials of a kubeconfig file instead of backplane, e.g. in disconnected environments.
+type kubeconfigOverrides struct {
+	mgmt    string
+	service string
+}
+
+// addFlags registers the kubeconfig override flags. With service the service cluster flag is registered too.
+func (k *kubeconfigOverrides) addFlags(cmd *cobra.Command, service bool) {
+	cmd.Flags().StringVar(&k.mgmt, "mgmt-kubeconfig", "",
+		"Reach the management cluster with this kubeconfig instead of backplane (no backplane elevation or audit trail)")
+	if service {
+		cmd.Flags().StringVar(&k.service, "service-kubeconfig", "",
+			"Reach the service cluster with this kubeconfig instead of backplane (no backplane elevation or audit trail)")
+	}
+}
+
+// validate checks that the kubeconfig files exist and warns that backplane is bypassed.
+func (k kubeconfigOverrides) validate() error {
+	for _, override := range []struct{ flag, path string }{{"--mgmt-kubeconfig", k.mgmt}, {"--service-kubeconfig", k.service}} {
+		flag, path := override.flag, override.path
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid %s: %v", flag, err)
+		}
+		slog.Warn("Bypassing backplane: "+flag+" clients are not elevated through backplane and leave no backplane audit trail; "+
+			"record the elevation and the change externally", "kubeconfig", path)
+	}
+	return nil
+}
+
+// clients returns f, or the backplane client factory rate limited by limit when f is nil, with the clients
+// of the management and service clusters built from their kubeconfig overrides.
+func (k kubeconfigOverrides) clients(f clientfactory.Factory, limit clientfactory.RateLimit, mgmtClusterID, serviceClusterID string) clientfactory.Factory {
+	if f == nil {
+		f = clientfactory.Backplane{RateLimit: limit}
+	}
+	kubeconfigs := map[string]string{}
+	if k.mgmt != "" {
+		kubeconfigs[mgmtClusterID] = k.mgmt
+	}
+	if k.service != "" && serviceClusterID != "" {
+		kubeconfigs[serviceClusterID] = k.service
+	}
+	if len(kubeconfigs) == 0 {
+		return f
+	}
+	return &clientfactory.Kubeconfig{Kubeconfigs: kubeconfigs, Fallback: f, RateLimit: limit}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: mgmt
+  cluster:
+    server: https://api.mgmt.example.com:6443
+contexts:
+- name: mgmt
+  context:
+    cluster: mgmt
+    user: operator
+current-context: mgmt
+users:
+- name: operator
+  user:
+    token: test-token
+`
+
+// writeTestKubeconfig writes a kubeconfig for a cluster that is never contacted.
+func writeTestKubeconfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+// TestKubeconfigOverridesClients verifies only the clusters with a kubeconfig override bypass the client factory.
+func TestKubeconfigOverridesClients(t *testing.T) {
+	scheme := testScheme(t)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	factory := &fakeClientFactory{clients: map[string]client.Client{"mgmt-id": fakeClient, "svc-id": fakeClient}}
+
+	if clients := (kubeconfigOverrides{}).clients(factory, clientfactory.RateLimit{}, "mgmt-id", "svc-id"); clients != factory {
+		t.Errorf("Expected the client factory to be used without overrides, got %T", clients)
+	}
+
+	clients := kubeconfigOverrides{mgmt: writeTestKubeconfig(t)}.clients(factory, clientfactory.RateLimit{}, "mgmt-id", "svc-id")
+
+	mgmtClient, err := clients.NewClient("mgmt-id", scheme)
+	if err != nil {
+		t.Fatalf("Unexpected error creating management cluster client: %v", err)
+	}
+	if mgmtClient == fakeClient {
+		t.Errorf("Expected the management cluster client to be built from the kubeconfig")
+	}
+
+	serviceClient, err := clients.NewElevatedClient("svc-id", scheme, nil, "OHSS-12345 - test")
+	if err != nil {
+		t.Fatalf("Unexpected error creating service cluster client: %v", err)
+	}
+	if serviceClient != fakeClient || !factory.elevated["svc-id"] {
+		t.Errorf("Expected the elevated service cluster client to come from the client factory")
+	}
+	if factory.elevated["mgmt-id"] {
+		t.Errorf("Expected no elevation through the client factory for the management cluster")
+	}
+}
+
+// TestKubeconfigOverridesValidate verifies missing kubeconfig files are rejected before any cluster is contacted.
+func TestKubeconfigOverridesValidate(t *testing.T) {
+	if err := (kubeconfigOverrides{mgmt: writeTestKubeconfig(t)}).validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err := kubeconfigOverrides{service: filepath.Join(t.TempDir(), "missing")}.validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid --service-kubeconfig") {
+		t.Errorf("Expected invalid --service-kubeconfig error, got %v", err)
+	}
+
+	clients := kubeconfigOverrides{mgmt: filepath.Join(t.TempDir(), "missing")}.clients(nil, clientfactory.RateLimit{}, "mgmt-id", "")
+	if _, err := clients.NewClient("mgmt-id", testScheme(t)); err == nil || !strings.Contains(err.Error(), "failed to load kubeconfig") {
+		t.Errorf("Expected kubeconfig load error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// syncLatency summarizes how long verified syncs took in a migrate run. Slow syncs are the main sign
+// that a management cluster's work agent is unhealthy.
+type syncLatency struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+// summarizeSyncLatency returns the p50, p95 and max sync duration of the successfully migrated clusters,
+// or nil when no sync was verified.
+func summarizeSyncLatency(results []migrationResult) *syncLatency {
+	var durations []time.Duration
+	for _, r := range results {
+		if r.Status == "success" {
+			durations = append(durations, r.syncDuration())
+		}
+	}
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return &syncLatency{
+		Count: len(durations),
+		P50:   percentile(durations, 50),
+		P95:   percentile(durations, 95),
+		Max:   durations[len(durations)-1],
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// syncDuration returns the time the cluster's sync verification took.
+func (r migrationResult) syncDuration() time.Duration {
+	return time.Duration(r.SyncSeconds * float64(time.Second))
+}
+
+// printSyncLatency prints the sync latency summary of a migrate run.
+func printSyncLatency(w io.Writer, l *syncLatency) {
+	if l == nil {
+		return
+	}
+	fmt.Fprintf(w, "Sync latency (%d clusters): p50 %s, p95 %s, max %s\n",
+		l.Count, l.P50.Round(time.Second), l.P95.Round(time.Second), l.Max.Round(time.Second))
+}
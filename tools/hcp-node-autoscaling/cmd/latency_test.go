@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestSummarizeSyncLatency verifies the nearest-rank percentiles are computed over successful migrations only.
+func TestSummarizeSyncLatency(t *testing.T) {
+	var results []migrationResult
+	for i := 1; i <= 20; i++ {
+		results = append(results, migrationResult{Status: "success", SyncSeconds: float64(i)})
+	}
+	results = append(results, migrationResult{Status: "failed", SyncSeconds: 300})
+
+	l := summarizeSyncLatency(results)
+	if l == nil {
+		t.Fatal("Expected a sync latency summary")
+	}
+	expected := syncLatency{Count: 20, P50: 10 * time.Second, P95: 19 * time.Second, Max: 20 * time.Second}
+	if *l != expected {
+		t.Errorf("summarizeSyncLatency() = %+v, want %+v", *l, expected)
+	}
+
+	single := summarizeSyncLatency([]migrationResult{{Status: "success", SyncSeconds: 42}})
+	if single.P50 != 42*time.Second || single.P95 != 42*time.Second || single.Max != 42*time.Second {
+		t.Errorf("Unexpected summary of a single sync: %+v", *single)
+	}
+
+	if l := summarizeSyncLatency([]migrationResult{{Status: "failed"}}); l != nil {
+		t.Errorf("Expected no summary without successful syncs, got %+v", *l)
+	}
+}
+
+// TestPrintSyncLatency verifies the summary line and that nothing is printed without a summary.
+func TestPrintSyncLatency(t *testing.T) {
+	var out bytes.Buffer
+	printSyncLatency(&out, &syncLatency{Count: 3, P50: 30400 * time.Millisecond, P95: 45 * time.Second, Max: 61 * time.Second})
+	if expected := "Sync latency (3 clusters): p50 30s, p95 45s, max 1m1s\n"; out.String() != expected {
+		t.Errorf("printSyncLatency() = %q, want %q", out.String(), expected)
+	}
+
+	out.Reset()
+	printSyncLatency(&out, nil)
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+}
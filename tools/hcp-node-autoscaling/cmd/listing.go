@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultNamespacePageSize is the number of namespaces requested per page when listing OCM namespaces.
+const defaultNamespacePageSize = 500
+
+// namespaceListing controls how the OCM namespaces of a management cluster are listed. Namespaces are
+// listed in pages so that huge management clusters do not return every namespace in a single response.
+type namespaceListing struct {
+	pageSize     int64
+	selectorFlag string
+	selector     labels.Selector
+
+	// patternFlag replaces the namespace pattern of the OCM environment and excludeFlag drops the matching
+	// namespaces. Both are compiled by validate.
+	patternFlag string
+	excludeFlag string
+	pattern     *regexp.Regexp
+	exclude     *regexp.Regexp
+}
+
+// namespaceMatcher matches the hosted cluster namespaces selected by the environment and the namespace
+// pattern flags.
+type namespaceMatcher struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// MatchString reports whether name matches the namespace pattern and not the exclude pattern.
+func (n namespaceMatcher) MatchString(name string) bool {
+	return n.include.MatchString(name) && (n.exclude == nil || !n.exclude.MatchString(name))
+}
+
+// addFlags registers the namespace listing flags.
+func (l *namespaceListing) addFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&l.pageSize, "page-size", defaultNamespacePageSize,
+		"Number of namespaces to request per page when listing the hosted cluster namespaces of the management cluster")
+	cmd.Flags().StringVar(&l.selectorFlag, "namespace-selector", "",
+		"Only list namespaces whose labels match this selector, filtered by the API server (e.g. api.openshift.com/environment=production)")
+	cmd.Flags().StringVar(&l.patternFlag, "namespace-pattern", "",
+		"Regular expression matching the hosted cluster namespaces, replacing the pattern of --environment (e.g. '^ocm-integration-[a-zA-Z0-9]+$')")
+	cmd.Flags().StringVar(&l.excludeFlag, "namespace-exclude-pattern", "",
+		"Regular expression of hosted cluster namespaces to skip, even when they match the namespace pattern")
+}
+
+// validate checks the page size, parses --namespace-selector and compiles the namespace patterns.
+func (l *namespaceListing) validate() error {
+	if l.pageSize < 1 {
+		return fmt.Errorf("invalid page size %d: must be at least 1", l.pageSize)
+	}
+	if l.selectorFlag != "" {
+		selector, err := labels.Parse(l.selectorFlag)
+		if err != nil {
+			return fmt.Errorf("invalid namespace selector '%s': %v", l.selectorFlag, err)
+		}
+		l.selector = selector
+	}
+	if l.patternFlag != "" {
+		pattern, err := regexp.Compile(l.patternFlag)
+		if err != nil {
+			return fmt.Errorf("invalid namespace pattern '%s': %v", l.patternFlag, err)
+		}
+		l.pattern = pattern
+	}
+	if l.excludeFlag != "" {
+		exclude, err := regexp.Compile(l.excludeFlag)
+		if err != nil {
+			return fmt.Errorf("invalid namespace exclude pattern '%s': %v", l.excludeFlag, err)
+		}
+		l.exclude = exclude
+	}
+	return nil
+}
+
+// namespaceMatcher returns the matcher of the hosted cluster namespaces to audit or migrate: --namespace-pattern
+// when set, otherwise the pattern of the OCM environment, without the namespaces matching
+// --namespace-exclude-pattern.
+func (l namespaceListing) namespaceMatcher(environment string) (namespaceMatcher, error) {
+	include := l.pattern
+	if include == nil {
+		pattern, err := ocmNamespacePattern(environment)
+		if err != nil {
+			return namespaceMatcher{}, err
+		}
+		include = pattern
+	}
+	return namespaceMatcher{include: include, exclude: l.exclude}, nil
+}
+
+// scope describes the namespaces matched by namespaceMatcher for messages about clusters outside of them.
+func (l namespaceListing) scope(environment string) string {
+	if l.pattern == nil && l.exclude == nil {
+		return "the " + environment + " environment"
+	}
+	return "the selected namespaces"
+}
+
+// listOptions returns the options listing a page of namespaces, continuing from the continue token of the
+// previous page. A zero page size, as in options that were not set from flags, uses the default.
+func (l namespaceListing) listOptions(continueToken string) []client.ListOption {
+	pageSize := l.pageSize
+	if pageSize <= 0 {
+		pageSize = defaultNamespacePageSize
+	}
+	opts := []client.ListOption{client.Limit(pageSize), client.Continue(continueToken)}
+	if l.selector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: l.selector})
+	}
+	return opts
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestNamespaceListingValidate verifies the page size must be positive and the namespace selector is parsed.
+func TestNamespaceListingValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		listing     namespaceListing
+		expectError bool
+	}{
+		{name: "defaults", listing: namespaceListing{pageSize: defaultNamespacePageSize}},
+		{name: "selector", listing: namespaceListing{pageSize: 100, selectorFlag: "api.openshift.com/environment=production"}},
+		{name: "zero page size", listing: namespaceListing{}, expectError: true},
+		{name: "invalid selector", listing: namespaceListing{pageSize: 100, selectorFlag: "a in (b"}, expectError: true},
+		{name: "patterns", listing: namespaceListing{pageSize: 100, patternFlag: "^ocm-integration-", excludeFlag: "-canary$"}},
+		{name: "invalid pattern", listing: namespaceListing{pageSize: 100, patternFlag: "ocm-("}, expectError: true},
+		{name: "invalid exclude pattern", listing: namespaceListing{pageSize: 100, excludeFlag: "[a-"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.listing.validate()
+			if (err != nil) != tt.expectError {
+				t.Fatalf("validate() error = %v, expectError %v", err, tt.expectError)
+			}
+			if err == nil && (tt.listing.selectorFlag != "") != (tt.listing.selector != nil) {
+				t.Errorf("Expected the selector to be parsed from %q", tt.listing.selectorFlag)
+			}
+		})
+	}
+}
+
+// TestListOcmNamespacesPaginated verifies namespaces are listed page by page with the page size and continue
+// token, keeping the OCM namespaces of every page, and that the namespace selector is sent to the API server.
+func TestListOcmNamespacesPaginated(t *testing.T) {
+	names := []string{"default", "ocm-production-a1", "kube-system", "ocm-production-b2", "ocm-staging-c3", "ocm-production-d4", "openshift"}
+
+	var requests []client.ListOptions
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts := client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				requests = append(requests, listOpts)
+
+				start := 0
+				if listOpts.Continue != "" {
+					start, _ = strconv.Atoi(listOpts.Continue)
+				}
+				end := min(start+int(listOpts.Limit), len(names))
+				nsList := list.(*corev1.NamespaceList)
+				for _, name := range names[start:end] {
+					nsList.Items = append(nsList.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				}
+				if end < len(names) {
+					nsList.Continue = fmt.Sprint(end)
+				}
+				return nil
+			},
+		}).Build()
+
+	listing := namespaceListing{pageSize: 3, selectorFlag: "api.openshift.com/managed=true"}
+	if err := listing.validate(); err != nil {
+		t.Fatal(err)
+	}
+	a := &auditOpts{environment: "production", mgmtClient: mgmtClient, listing: listing}
+
+	namespaces, err := a.listOcmNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("listOcmNamespaces() error = %v", err)
+	}
+
+	var found []string
+	for _, ns := range namespaces {
+		found = append(found, ns.Name)
+	}
+	if fmt.Sprint(found) != "[ocm-production-a1 ocm-production-b2 ocm-production-d4]" {
+		t.Errorf("listOcmNamespaces() = %v", found)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 pages to be requested, got %d", len(requests))
+	}
+	for i, r := range requests {
+		if r.Limit != 3 || r.Continue != []string{"", "3", "6"}[i] || r.LabelSelector == nil || r.LabelSelector.String() != "api.openshift.com/managed=true" {
+			t.Errorf("Unexpected options for page %d: limit=%d continue=%q selector=%v", i+1, r.Limit, r.Continue, r.LabelSelector)
+		}
+	}
+}
+
+// TestNamespaceMatcher verifies --namespace-pattern replaces the environment pattern and
+// --namespace-exclude-pattern drops matching namespaces.
+func TestNamespaceMatcher(t *testing.T) {
+	tests := []struct {
+		name        string
+		listing     namespaceListing
+		environment string
+		matches     map[string]bool
+		scope       string
+	}{
+		{
+			name:        "environment pattern",
+			environment: "production",
+			matches:     map[string]bool{"ocm-production-a1": true, "ocm-staging-a1": false, "ocm-integration-a1": false},
+			scope:       "the production environment",
+		},
+		{
+			name:        "namespace pattern",
+			listing:     namespaceListing{patternFlag: "^ocm-integration-[a-z0-9]+$"},
+			environment: "production",
+			matches:     map[string]bool{"ocm-production-a1": false, "ocm-integration-a1": true},
+			scope:       "the selected namespaces",
+		},
+		{
+			name:        "exclude pattern",
+			listing:     namespaceListing{excludeFlag: "^ocm-production-(b2|c3)$"},
+			environment: "production",
+			matches:     map[string]bool{"ocm-production-a1": true, "ocm-production-b2": false, "ocm-production-c3": false},
+			scope:       "the selected namespaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.listing.pageSize = defaultNamespacePageSize
+			if err := tt.listing.validate(); err != nil {
+				t.Fatal(err)
+			}
+			matcher, err := tt.listing.namespaceMatcher(tt.environment)
+			if err != nil {
+				t.Fatalf("namespaceMatcher() error = %v", err)
+			}
+			for name, want := range tt.matches {
+				if got := matcher.MatchString(name); got != want {
+					t.Errorf("MatchString(%q) = %v, want %v", name, got, want)
+				}
+			}
+			if got := tt.listing.scope(tt.environment); got != tt.scope {
+				t.Errorf("scope() = %q, want %q", got, tt.scope)
+			}
+		})
+	}
+
+	if _, err := (namespaceListing{}).namespaceMatcher("integration"); err == nil {
+		t.Error("Expected an invalid environment to be rejected without --namespace-pattern")
+	}
+}
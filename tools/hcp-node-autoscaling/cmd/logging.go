@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// logOpts holds the logging flags shared by all subcommands.
+type logOpts struct {
+	level  string
+	format string
+}
+
+// setup configures the default slog logger to write progress messages to w.
+// Command results are written to stdout separately so they remain machine-readable.
+func (l *logOpts) setup(w io.Writer) error {
+	level, err := parseLogLevel(l.level)
+	if err != nil {
+		return err
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch l.format {
+	case "text":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case "json":
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return fmt.Errorf("invalid log format '%s'. Valid options: text, json", l.format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// parseLogLevel converts a log level name into a slog.Level.
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level '%s'. Valid options: debug, info, warn, error", level)
+	}
+}
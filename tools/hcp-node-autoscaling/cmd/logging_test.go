@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestParseLogLevel verifies log level name parsing.
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    slog.Level
+		expectError bool
+	}{
+		{input: "debug", expected: slog.LevelDebug},
+		{input: "info", expected: slog.LevelInfo},
+		{input: "WARN", expected: slog.LevelWarn},
+		{input: "warning", expected: slog.LevelWarn},
+		{input: "error", expected: slog.LevelError},
+		{input: "verbose", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := parseLogLevel(tt.input)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseLogLevel() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && level != tt.expected {
+				t.Errorf("parseLogLevel() = %v, want %v", level, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLogSetup verifies the configured logger honors level and format.
+func TestLogSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	buf := &bytes.Buffer{}
+	opts := &logOpts{level: "warn", format: "json"}
+	if err := opts.setup(buf); err != nil {
+		t.Fatalf("setup() error = %v", err)
+	}
+
+	slog.Info("suppressed")
+	slog.Warn("emitted", "namespace", "ocm-production-abc123")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %q", len(lines), buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not valid JSON: %v", err)
+	}
+	if entry["msg"] != "emitted" || entry["namespace"] != "ocm-production-abc123" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+
+	invalid := &logOpts{level: "info", format: "xml"}
+	if err := invalid.setup(buf); err == nil {
+		t.Errorf("expected error for invalid log format")
+	}
+}
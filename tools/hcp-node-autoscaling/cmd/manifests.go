@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// saveManifests writes the HostedCluster manifest of a cluster as it is before the patch, and as the
+// migration profile will leave it, to <dir>/<run ID>/<cluster ID>-original.yaml and -patched.yaml. The
+// manifest is extracted from the ManifestWork, or read from the management cluster with --direct. Each run
+// writes to its own directory so a rerun never replaces the original of an earlier one.
+func (m *migrateOpts) saveManifests(ctx context.Context, info hostedClusterAuditInfo) (string, error) {
+	if m.saveManifestsDir == "" {
+		return "", nil
+	}
+
+	var original map[string]interface{}
+	err := withPhaseTimeout(ctx, m.timeouts.manifestWork, "reading the HostedCluster manifest", "cluster "+info.ClusterID, func(ctx context.Context) error {
+		var err error
+		original, err = m.hostedClusterManifest(ctx, info)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	patched, err := copyManifest(original)
+	if err != nil {
+		return "", err
+	}
+	setProfileAnnotations(patched, m.patchProfile(info.ClusterID))
+
+	dir := filepath.Join(m.saveManifestsDir, m.session.runID())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create manifests directory: %v", err)
+	}
+	for suffix, manifest := range map[string]map[string]interface{}{"original": original, "patched": patched} {
+		if err := writeManifest(filepath.Join(dir, info.ClusterID+"-"+suffix+".yaml"), manifest); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// hostedClusterManifest returns the HostedCluster manifest that the migration patches: the manifest in the
+// cluster's ManifestWork, or the live HostedCluster with --direct.
+func (m *migrateOpts) hostedClusterManifest(ctx context.Context, info hostedClusterAuditInfo) (map[string]interface{}, error) {
+	if m.direct {
+		hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HostedCluster: %w", err)
+		}
+		hc.SetGroupVersionKind(hypershiftv1beta1.GroupVersion.WithKind("HostedCluster"))
+		return runtime.DefaultUnstructuredConverter.ToUnstructured(hc)
+	}
+
+	manifestWork, err := m.getManifestWork(ctx, info.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	_, manifestData, err := findHostedClusterManifest(manifestWork)
+	return manifestData, err
+}
+
+// copyManifest returns a deep copy of a decoded manifest.
+func copyManifest(manifest map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	var manifestCopy map[string]interface{}
+	if err := json.Unmarshal(data, &manifestCopy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %v", err)
+	}
+	return manifestCopy, nil
+}
+
+// writeManifest writes a decoded manifest to path as YAML.
+func writeManifest(path string, manifest map[string]interface{}) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// readTestManifest reads the annotations of a saved manifest.
+func readTestManifest(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read saved manifest: %v", err)
+	}
+	var manifest struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Failed to parse saved manifest %s: %v", path, err)
+	}
+	if manifest.Kind != "HostedCluster" {
+		t.Errorf("Expected a HostedCluster manifest in %s, got kind %q", path, manifest.Kind)
+	}
+	return manifest.Metadata.Annotations
+}
+
+// TestSaveManifests verifies the original and patched HostedCluster manifests are saved under the run ID, from
+// the ManifestWork or, with --direct, from the management cluster.
+func TestSaveManifests(t *testing.T) {
+	for _, direct := range []bool{false, true} {
+		t.Run(map[bool]string{false: "manifestwork", true: "direct"}[direct], func(t *testing.T) {
+			scheme := testScheme(t)
+			hc := newTestHostedCluster("a1", map[string]string{"example.com/keep": "true"})
+			dir := t.TempDir()
+			m := &migrateOpts{
+				profile:          topologyProfile,
+				mgmtClusterName:  "mgmt-cluster",
+				direct:           direct,
+				saveManifestsDir: dir,
+				session:          &runSession{id: "run-789"},
+				mgmtClient:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc).Build(),
+				serviceClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).Build(),
+			}
+
+			saved, err := m.saveManifests(context.Background(), hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace})
+			if err != nil {
+				t.Fatalf("saveManifests() error = %v", err)
+			}
+			if saved != filepath.Join(dir, "run-789") {
+				t.Errorf("saveManifests() dir = %s, want the run ID directory", saved)
+			}
+
+			original := readTestManifest(t, filepath.Join(saved, "a1-original.yaml"))
+			if len(original) != 1 || original["example.com/keep"] != "true" {
+				t.Errorf("Expected the original annotations, got %v", original)
+			}
+			patched := readTestManifest(t, filepath.Join(saved, "a1-patched.yaml"))
+			for key, value := range topologyProfile.Ensure {
+				if patched[key] != value {
+					t.Errorf("Expected patched annotation %s=%s, got %v", key, value, patched)
+				}
+			}
+			if patched["example.com/keep"] != "true" {
+				t.Errorf("Expected the patched manifest to keep other annotations, got %v", patched)
+			}
+		})
+	}
+}
+
+// TestSaveManifestsDisabled verifies nothing is read or written without --save-manifests.
+func TestSaveManifestsDisabled(t *testing.T) {
+	m := &migrateOpts{}
+	if dir, err := m.saveManifests(context.Background(), hostedClusterAuditInfo{ClusterID: "a1"}); dir != "" || err != nil {
+		t.Errorf("saveManifests() = %q, %v, want nothing saved", dir, err)
+	}
+}
+
+// TestMigrateClusterSaveManifestsFailure verifies a cluster whose manifests cannot be saved is not patched.
+func TestMigrateClusterSaveManifestsFailure(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", nil)
+	notADir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notADir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &migrateOpts{
+		profile:          topologyProfile,
+		mgmtClusterID:    "mgmt-123",
+		mgmtClusterName:  "mgmt-cluster",
+		syncTimeout:      time.Hour,
+		pollInterval:     time.Hour,
+		saveManifestsDir: notADir,
+		mgmtClient:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc).Build(),
+		serviceClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).Build(),
+	}
+	info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace, Category: "ready-for-migration"}
+
+	result := m.migrateCluster(context.Background(), info)
+	if result.Status != "failed" || !strings.Contains(result.Error, "nothing was patched") {
+		t.Fatalf("migrateCluster() = %+v, want a failure before patching", result)
+	}
+
+	manifestWork, err := m.getManifestWork(context.Background(), "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations, _ := manifestData["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+	if len(annotations) != 0 {
+		t.Errorf("Expected the ManifestWork not to be patched, got annotations %v", annotations)
+	}
+}
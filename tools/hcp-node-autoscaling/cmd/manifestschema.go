@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+	kjson "sigs.k8s.io/json"
+)
+
+// verifyManifestRoundTrip checks the HostedCluster manifest about to be written back to a ManifestWork. The
+// modified manifest must decode into the HyperShift HostedCluster type without unknown or duplicate fields,
+// and must be identical to the original manifest apart from the profile's annotations, so that decoding
+// and re-encoding the manifest as generic JSON cannot silently drop fields or change numbers.
+func verifyManifestRoundTrip(original, modified []byte, profile *migrationProfile) error {
+	hc := &hypershiftv1beta1.HostedCluster{}
+	strictErrs, err := kjson.UnmarshalStrict(modified, hc)
+	if err != nil {
+		return fmt.Errorf("modified HostedCluster manifest does not match the HyperShift API: %v", err)
+	}
+	if len(strictErrs) > 0 {
+		return fmt.Errorf("modified HostedCluster manifest does not match the HyperShift API, refusing to update the ManifestWork: %v",
+			errors.Join(strictErrs...))
+	}
+
+	var expected, actual map[string]interface{}
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(original, &expected); err != nil {
+		return fmt.Errorf("failed to decode original HostedCluster manifest: %v", err)
+	}
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(modified, &actual); err != nil {
+		return fmt.Errorf("failed to decode modified HostedCluster manifest: %v", err)
+	}
+	setProfileAnnotations(expected, profile)
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("HostedCluster manifest did not survive the JSON round trip unchanged, refusing to update the ManifestWork")
+	}
+	return nil
+}
+
+// verifyProfilePatch runs verifyManifestRoundTrip on the HostedCluster manifest at index as the profile's
+// annotations would leave it, for the JSON patch strategies, which send only the annotation operations and
+// would otherwise write to manifests the update strategy refuses.
+func verifyProfilePatch(manifestWork *workv1.ManifestWork, index int, manifestData map[string]interface{}, profile *migrationProfile) error {
+	modified := runtime.DeepCopyJSON(manifestData)
+	setProfileAnnotations(modified, profile)
+	jsonData, err := json.Marshal(modified)
+	if err != nil {
+		return fmt.Errorf("failed to marshal modified manifest: %v", err)
+	}
+	return verifyManifestRoundTrip(manifestWork.Spec.Workload.Manifests[index].Raw, jsonData, profile)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// TestApplyProfileAnnotationsRoundTrip verifies the HostedCluster manifest is only rewritten, or patched with
+// the JSON patch strategies, when it decodes strictly into a HostedCluster and survives the JSON round trip
+// unchanged apart from its annotations.
+func TestApplyProfileAnnotationsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		expectedErr string
+	}{
+		{
+			name:     "valid manifest",
+			manifest: `{"apiVersion":"hypershift.openshift.io/v1beta1","kind":"HostedCluster","metadata":{"name":"cluster-a1","annotations":{"hypershift.openshift.io/cluster-size-override":"m54xl"}},"spec":{"release":{"image":"quay.io/openshift-release-dev/ocp-release:4.19.0"},"etcd":{"managementType":"Managed"}}}`,
+		},
+		{
+			name:        "unknown field",
+			manifest:    `{"apiVersion":"hypershift.openshift.io/v1beta1","kind":"HostedCluster","metadata":{"name":"cluster-a1"},"spec":{"relase":{"image":"quay.io/openshift-release-dev/ocp-release:4.19.0"}}}`,
+			expectedErr: `unknown field "spec.relase"`,
+		},
+		{
+			name:        "annotation that is not a string",
+			manifest:    `{"apiVersion":"hypershift.openshift.io/v1beta1","kind":"HostedCluster","metadata":{"name":"cluster-a1","annotations":{"example.com/replicas":3}}}`,
+			expectedErr: "does not match the HyperShift API",
+		},
+		{
+			name:        "wrong type",
+			manifest:    `{"apiVersion":"hypershift.openshift.io/v1beta1","kind":"HostedCluster","metadata":{"name":"cluster-a1"},"spec":{"fips":"yes"}}`,
+			expectedErr: "does not match the HyperShift API",
+		},
+		{
+			name:        "integer losing precision",
+			manifest:    `{"apiVersion":"hypershift.openshift.io/v1beta1","kind":"HostedCluster","metadata":{"name":"cluster-a1","generation":9007199254740993}}`,
+			expectedErr: "did not survive the JSON round trip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestWork := &workv1.ManifestWork{}
+			manifestWork.Name = "a1"
+			manifestWork.Spec.Workload.Manifests = []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(tt.manifest)}}}
+
+			index, manifestData, err := findHostedClusterManifest(manifestWork)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patchErr := verifyProfilePatch(manifestWork, index, manifestData, defaultProfile)
+			if (tt.expectedErr == "" && patchErr != nil) || (tt.expectedErr != "" && (patchErr == nil || !strings.Contains(patchErr.Error(), tt.expectedErr))) {
+				t.Errorf("verifyProfilePatch() error = %v, want %q", patchErr, tt.expectedErr)
+			}
+
+			err = applyProfileAnnotations(manifestWork, defaultProfile)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("applyProfileAnnotations() error = %v", err)
+				}
+				raw := string(manifestWork.Spec.Workload.Manifests[0].Raw)
+				if !strings.Contains(raw, autoscalingAnnotation) || strings.Contains(raw, sizeOverrideAnnotation) {
+					t.Errorf("Expected the profile annotations in the manifest, got %s", raw)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+			if raw := string(manifestWork.Spec.Workload.Manifests[0].Raw); raw != tt.manifest {
+				t.Errorf("Expected the manifest to be left unchanged, got %s", raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"go.opentelemetry.io/otel/attribute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkManifestWorks fetches the ManifestWorks of all candidates from the service cluster before anything is
+// changed and splits the candidates into those with a usable ManifestWork and those without, so a missing
+// ManifestWork is reported up front instead of failing the cluster mid-run. The ManifestWorks of the
+// management cluster are listed in one request; the candidates missing from it are looked up in the other
+// namespaces to tell a ManifestWork in the wrong namespace from a deleted one. With --direct the service
+// cluster is not used and nothing is checked.
+func (m *migrateOpts) checkManifestWorks(ctx context.Context, candidates []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, []skippedCluster, error) {
+	if m.direct || len(candidates) == 0 {
+		return candidates, nil, nil
+	}
+	ctx, span := startSpan(ctx, spanManifestWorkPreflight, attribute.String(attrServiceClusterID, m.serviceClusterID),
+		attribute.String(attrNamespace, m.mgmtClusterName))
+	valid, missing, err := m.splitByManifestWork(ctx, candidates)
+	endSpan(span, err)
+	return valid, missing, err
+}
+
+// splitByManifestWork lists the ManifestWorks of the management cluster and splits the candidates by
+// whether theirs is found and holds the HostedCluster manifest.
+func (m *migrateOpts) splitByManifestWork(ctx context.Context, candidates []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, []skippedCluster, error) {
+	manifestWorks := &workv1.ManifestWorkList{}
+	if err := m.serviceClient.List(ctx, manifestWorks, client.InNamespace(m.mgmtClusterName)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list ManifestWorks in namespace %s on service cluster %s: %v",
+			m.mgmtClusterName, m.serviceClusterID, err)
+	}
+	byName := make(map[string]*workv1.ManifestWork, len(manifestWorks.Items))
+	for i := range manifestWorks.Items {
+		byName[manifestWorks.Items[i].Name] = &manifestWorks.Items[i]
+	}
+
+	var elsewhere map[string][]string
+	if slices.ContainsFunc(candidates, func(c hostedClusterAuditInfo) bool { return byName[c.ClusterID] == nil }) {
+		elsewhere = m.manifestWorkNamespaces(ctx)
+	}
+
+	var valid []hostedClusterAuditInfo
+	var missing []skippedCluster
+	for _, c := range candidates {
+		manifestWork := byName[c.ClusterID]
+		reason := ""
+		switch {
+		case manifestWork == nil && len(elsewhere[c.ClusterID]) > 0:
+			reason = fmt.Sprintf("ManifestWork %s found in namespace %s, not %s; check that the service cluster is the parent of the management cluster",
+				c.ClusterID, strings.Join(elsewhere[c.ClusterID], ", "), m.mgmtClusterName)
+		case manifestWork == nil:
+			reason = fmt.Sprintf("ManifestWork %s/%s not found on service cluster %s; the cluster may have been deleted",
+				m.mgmtClusterName, c.ClusterID, m.serviceClusterID)
+		default:
+			if _, _, err := findHostedClusterManifest(manifestWork); err != nil {
+				reason = fmt.Sprintf("ManifestWork %s/%s is unusable: %v", m.mgmtClusterName, c.ClusterID, err)
+			}
+		}
+		if reason != "" {
+			slog.Info("Skipping cluster without a usable ManifestWork", "clusterID", c.ClusterID, "reason", reason)
+			missing = append(missing, skippedCluster{info: c, reason: reason})
+			continue
+		}
+		valid = append(valid, c)
+	}
+	return valid, missing, nil
+}
+
+// manifestWorkNamespaces returns the namespaces of the service cluster each ManifestWork name is found in.
+// Only metadata is listed. When the ManifestWorks cannot be listed across namespaces, e.g. for lack of
+// permission, nothing is returned and the missing ManifestWorks are reported as not found.
+func (m *migrateOpts) manifestWorkNamespaces(ctx context.Context) map[string][]string {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(workv1.GroupVersion.WithKind("ManifestWorkList"))
+	if err := m.serviceClient.List(ctx, list); err != nil {
+		slog.Debug("Failed to list ManifestWorks across namespaces", "serviceClusterID", m.serviceClusterID, "error", err)
+		return nil
+	}
+	namespaces := make(map[string][]string)
+	for _, item := range list.Items {
+		if item.Namespace != m.mgmtClusterName {
+			namespaces[item.Name] = append(namespaces[item.Name], item.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// displayMissingManifestWorks prints the candidates skipped because their ManifestWork is missing, and that
+// the run proceeds with the remaining ones, which the operator confirms.
+func displayMissingManifestWorks(w io.Writer, missing []skippedCluster, remaining int) {
+	if len(missing) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Skipped: ManifestWork Missing (%d) ===\n\n", len(missing))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+	for _, s := range missing {
+		p.AddRow([]string{s.info.ClusterID, s.info.ClusterName, s.reason})
+	}
+	p.Flush()
+	if remaining > 0 {
+		fmt.Fprintf(w, "\nOnly the %d candidates whose ManifestWork was found will be migrated.\n", remaining)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestCheckManifestWorks verifies candidates whose ManifestWork is deleted, in another namespace or without
+// the HostedCluster manifest are reported with the reason, and the others are kept in order.
+func TestCheckManifestWorks(t *testing.T) {
+	scheme := testScheme(t)
+	valid, otherValid := newTestHostedCluster("a1", nil), newTestHostedCluster("d4", nil)
+	wrongNamespace := newTestHostedCluster("b2", nil)
+	empty := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "e5", Namespace: "mgmt-cluster"}}
+
+	m := &migrateOpts{
+		mgmtClusterName:  "mgmt-cluster",
+		serviceClusterID: "svc-1",
+		serviceClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newTestManifestWork(t, "mgmt-cluster", valid),
+			newTestManifestWork(t, "mgmt-cluster", otherValid),
+			newTestManifestWork(t, "other-mgmt-cluster", wrongNamespace),
+			empty,
+		).Build(),
+	}
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "a1", ClusterName: "cluster-a1"},
+		{ClusterID: "b2", ClusterName: "cluster-b2"},
+		{ClusterID: "c3", ClusterName: "cluster-c3"},
+		{ClusterID: "d4", ClusterName: "cluster-d4"},
+		{ClusterID: "e5", ClusterName: "cluster-e5"},
+	}
+
+	remaining, missing, err := m.checkManifestWorks(context.Background(), candidates)
+	if err != nil {
+		t.Fatalf("checkManifestWorks() error = %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].ClusterID != "a1" || remaining[1].ClusterID != "d4" {
+		t.Errorf("Expected a1 and d4 to remain, got %+v", remaining)
+	}
+
+	expected := map[string]string{
+		"b2": "ManifestWork b2 found in namespace other-mgmt-cluster, not mgmt-cluster",
+		"c3": "ManifestWork mgmt-cluster/c3 not found on service cluster svc-1",
+		"e5": "ManifestWork mgmt-cluster/e5 is unusable",
+	}
+	if len(missing) != len(expected) {
+		t.Fatalf("Expected %d clusters with a missing ManifestWork, got %+v", len(expected), missing)
+	}
+	for _, s := range missing {
+		if !strings.Contains(s.reason, expected[s.info.ClusterID]) {
+			t.Errorf("Cluster %s reason = %q, want %q", s.info.ClusterID, s.reason, expected[s.info.ClusterID])
+		}
+	}
+
+	var buf bytes.Buffer
+	displayMissingManifestWorks(&buf, missing, len(remaining))
+	for _, want := range []string{"=== Skipped: ManifestWork Missing (3) ===", "cluster-c3",
+		"Only the 2 candidates whose ManifestWork was found will be migrated."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestCheckManifestWorksDirect verifies nothing is checked with --direct, which does not use the service
+// cluster.
+func TestCheckManifestWorksDirect(t *testing.T) {
+	candidates := []hostedClusterAuditInfo{{ClusterID: "a1"}}
+	remaining, missing, err := (&migrateOpts{direct: true}).checkManifestWorks(context.Background(), candidates)
+	if err != nil || len(remaining) != 1 || len(missing) != 0 {
+		t.Errorf("checkManifestWorks() = %+v, %+v, %v, want the candidates unchanged", remaining, missing, err)
+	}
+}
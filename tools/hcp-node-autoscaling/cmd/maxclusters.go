@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultMaxClusters is the number of clusters a run may change before --max-clusters must be raised.
+const defaultMaxClusters = 50
+
+// addMaxClustersFlag registers --max-clusters on a command that migrates clusters.
+func addMaxClustersFlag(cmd *cobra.Command, maxClusters *int) {
+	cmd.Flags().IntVar(maxClusters, "max-clusters", defaultMaxClusters,
+		"Refuse to migrate more than this many clusters; raise it explicitly to run a larger change")
+}
+
+// validateMaxClusters rejects a --max-clusters that would let no cluster be migrated.
+func validateMaxClusters(maxClusters int) error {
+	if maxClusters < 1 {
+		return fmt.Errorf("invalid max clusters %d: must be at least 1", maxClusters)
+	}
+	return nil
+}
+
+// checkMaxClusters refuses to migrate more clusters than --max-clusters, so a run expected to change a few
+// clusters cannot turn into a fleet-wide change unnoticed. A dry run only warns, so the candidates can be
+// reviewed before raising the limit. Commands that do not register --max-clusters, such as plan, leave it 0
+// and are not limited.
+func (m *migrateOpts) checkMaxClusters(w io.Writer, clusters int) error {
+	if m.maxClusters == 0 || clusters <= m.maxClusters {
+		return nil
+	}
+	if m.dryRun {
+		fmt.Fprintf(w, "WARNING: %d clusters exceed --max-clusters %d; the migration will be refused unless --max-clusters is raised to %d.\n\n",
+			clusters, m.maxClusters, clusters)
+		return nil
+	}
+	return fmt.Errorf("refusing to migrate %d clusters, more than --max-clusters %d: check the candidates and pass --max-clusters %d to proceed",
+		clusters, m.maxClusters, clusters)
+}
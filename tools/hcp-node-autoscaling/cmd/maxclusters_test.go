@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestCheckMaxClusters verifies runs over --max-clusters are refused, dry runs over it only warn, and commands
+// without the flag are not limited.
+func TestCheckMaxClusters(t *testing.T) {
+	tests := []struct {
+		name          string
+		m             *migrateOpts
+		clusters      int
+		expectErr     string
+		expectWarning string
+	}{
+		{name: "within the limit", m: &migrateOpts{maxClusters: 50}, clusters: 50},
+		{
+			name:      "over the limit",
+			m:         &migrateOpts{maxClusters: 50},
+			clusters:  51,
+			expectErr: "refusing to migrate 51 clusters, more than --max-clusters 50: check the candidates and pass --max-clusters 51 to proceed",
+		},
+		{
+			name:          "dry run over the limit",
+			m:             &migrateOpts{maxClusters: 5, dryRun: true},
+			clusters:      8,
+			expectWarning: "WARNING: 8 clusters exceed --max-clusters 5",
+		},
+		{name: "no limit", m: &migrateOpts{}, clusters: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.m.checkMaxClusters(&buf, tt.clusters)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("checkMaxClusters() error = %v, want %q", err, tt.expectErr)
+				}
+			} else if err != nil {
+				t.Errorf("checkMaxClusters() error = %v", err)
+			}
+			if tt.expectWarning == "" && buf.Len() > 0 || !strings.Contains(buf.String(), tt.expectWarning) {
+				t.Errorf("Unexpected output %q, want %q", buf.String(), tt.expectWarning)
+			}
+		})
+	}
+
+	for maxClusters, expectErr := range map[int]bool{1: false, 50: false, 0: true, -1: true} {
+		if err := validateMaxClusters(maxClusters); (err != nil) != expectErr {
+			t.Errorf("validateMaxClusters(%d) error = %v, expectErr %v", maxClusters, err, expectErr)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const metricsJobName = "hcp_node_autoscaling"
+
+// runMetrics records counters and durations for a single audit or migrate run so they can be
+// pushed to a Prometheus pushgateway. A nil *runMetrics is valid and records nothing.
+type runMetrics struct {
+	registry  *prometheus.Registry
+	command   string
+	startTime time.Time
+
+	clustersAudited  *prometheus.CounterVec
+	namespaceErrors  prometheus.Counter
+	clustersMigrated prometheus.Counter
+	clustersFailed   prometheus.Counter
+	clustersChanged  prometheus.Counter
+	clustersConflict prometheus.Counter
+	clustersPending  prometheus.Counter
+	syncWaitSeconds  *prometheus.GaugeVec
+	runDuration      prometheus.Gauge
+	lastCompletion   prometheus.Gauge
+	runInfo          prometheus.Gauge
+}
+
+// newRunMetrics creates the metrics for the run with the given ID of the given subcommand.
+func newRunMetrics(command, runID string) *runMetrics {
+	r := &runMetrics{
+		registry:  prometheus.NewRegistry(),
+		command:   command,
+		startTime: time.Now(),
+		clustersAudited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_audited_total",
+			Help: "Number of hosted clusters audited, by migration category.",
+		}, []string{"category"}),
+		namespaceErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_namespace_errors_total",
+			Help: "Number of OCM namespaces that failed to audit.",
+		}),
+		clustersMigrated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_migrated_total",
+			Help: "Number of hosted clusters successfully migrated.",
+		}),
+		clustersFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_failed_total",
+			Help: "Number of hosted clusters that failed to migrate.",
+		}),
+		clustersChanged: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_state_changed_total",
+			Help: "Number of hosted clusters skipped because they were no longer ready for migration when patched.",
+		}),
+		clustersConflict: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_conflicting_annotation_total",
+			Help: "Number of hosted clusters skipped because they have a conflicting topology annotation.",
+		}),
+		clustersPending: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hcp_node_autoscaling_clusters_pending_verification_total",
+			Help: "Number of hosted clusters patched with --no-verify whose sync was not verified.",
+		}),
+		syncWaitSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hcp_node_autoscaling_sync_wait_seconds",
+			Help: "Time spent waiting for annotations to sync to the management cluster, per hosted cluster.",
+		}, []string{"cluster_id", "result"}),
+		runDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "hcp_node_autoscaling_run_duration_seconds",
+			Help: "Duration of the run in seconds.",
+		}),
+		lastCompletion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "hcp_node_autoscaling_last_completion_timestamp_seconds",
+			Help: "Unix timestamp of the last completed run.",
+		}),
+		runInfo: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "hcp_node_autoscaling_run_info",
+			Help:        "Always 1, labelled with the ID of the run.",
+			ConstLabels: prometheus.Labels{"run_id": runID},
+		}),
+	}
+	r.runInfo.Set(1)
+
+	r.registry.MustRegister(
+		r.clustersAudited,
+		r.namespaceErrors,
+		r.clustersMigrated,
+		r.clustersFailed,
+		r.clustersChanged,
+		r.clustersConflict,
+		r.clustersPending,
+		r.syncWaitSeconds,
+		r.runDuration,
+		r.lastCompletion,
+		r.runInfo,
+	)
+
+	return r
+}
+
+// recordAudited counts an audited hosted cluster in the given category.
+func (r *runMetrics) recordAudited(category string) {
+	if r == nil {
+		return
+	}
+	r.clustersAudited.WithLabelValues(category).Inc()
+}
+
+// recordNamespaceError counts a namespace that failed to audit.
+func (r *runMetrics) recordNamespaceError() {
+	if r == nil {
+		return
+	}
+	r.namespaceErrors.Inc()
+}
+
+// recordMigration counts a migration result.
+func (r *runMetrics) recordMigration(result migrationResult) {
+	if r == nil {
+		return
+	}
+	switch result.Status {
+	case "success":
+		r.clustersMigrated.Inc()
+	case stateChanged:
+		r.clustersChanged.Inc()
+	case conflictingAnnotation:
+		r.clustersConflict.Inc()
+	case pendingVerificationStatus:
+		r.clustersPending.Inc()
+	default:
+		r.clustersFailed.Inc()
+	}
+}
+
+// recordSyncWait records how long a cluster's sync verification took.
+func (r *runMetrics) recordSyncWait(clusterID string, wait time.Duration, synced bool) {
+	if r == nil {
+		return
+	}
+	result := "synced"
+	if !synced {
+		result = "failed"
+	}
+	r.syncWaitSeconds.WithLabelValues(clusterID, result).Set(wait.Seconds())
+}
+
+// push sends the collected metrics to the pushgateway, grouped by command and management cluster.
+func (r *runMetrics) push(pushgatewayURL, mgmtClusterID string) error {
+	if r == nil {
+		return nil
+	}
+
+	r.runDuration.Set(time.Since(r.startTime).Seconds())
+	r.lastCompletion.SetToCurrentTime()
+
+	pusher := push.New(pushgatewayURL, metricsJobName).
+		Gatherer(r.registry).
+		Grouping("command", r.command)
+	if mgmtClusterID != "" {
+		pusher = pusher.Grouping("mgmt_cluster_id", mgmtClusterID)
+	}
+
+	if err := pusher.Push(); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %v", pushgatewayURL, err)
+	}
+	return nil
+}
+
+// validatePushgatewayURL checks that the pushgateway URL is an absolute http(s) URL.
+func validatePushgatewayURL(pushgatewayURL string) error {
+	u, err := url.Parse(pushgatewayURL)
+	if err != nil {
+		return fmt.Errorf("invalid pushgateway URL '%s': %v", pushgatewayURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid pushgateway URL '%s': must be an http or https URL", pushgatewayURL)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidatePushgatewayURL verifies pushgateway URL validation.
+func TestValidatePushgatewayURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		expectError bool
+	}{
+		{url: "http://pushgateway:9091", expectError: false},
+		{url: "https://pushgateway.example.com", expectError: false},
+		{url: "pushgateway:9091", expectError: true},
+		{url: "ftp://pushgateway", expectError: true},
+		{url: "http://", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validatePushgatewayURL(tt.url)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validatePushgatewayURL() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+// TestRunMetricsPush verifies recorded metrics are pushed with the expected grouping.
+func TestRunMetricsPush(t *testing.T) {
+	var gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := newRunMetrics("migrate", "run-123")
+	m.recordAudited("ready-for-migration")
+	m.recordAudited("already-configured")
+	m.recordNamespaceError()
+	m.recordMigration(migrationResult{ClusterID: "cluster1", Status: "success"})
+	m.recordMigration(migrationResult{ClusterID: "cluster2", Status: "failed"})
+	m.recordSyncWait("cluster1", 30*time.Second, true)
+
+	if err := m.push(server.URL, "mgmt-123"); err != nil {
+		t.Fatalf("push() error = %v", err)
+	}
+
+	expectedPath := "/metrics/job/hcp_node_autoscaling/command/migrate/mgmt_cluster_id/mgmt-123"
+	if gotPath != expectedPath {
+		t.Errorf("push path = %s, want %s", gotPath, expectedPath)
+	}
+
+	for _, name := range []string{
+		"hcp_node_autoscaling_clusters_audited_total",
+		"hcp_node_autoscaling_namespace_errors_total",
+		"hcp_node_autoscaling_clusters_migrated_total",
+		"hcp_node_autoscaling_clusters_failed_total",
+		"hcp_node_autoscaling_sync_wait_seconds",
+		"hcp_node_autoscaling_run_duration_seconds",
+		"hcp_node_autoscaling_run_info",
+	} {
+		if !strings.Contains(gotBody, name) {
+			t.Errorf("pushed metrics missing %s", name)
+		}
+	}
+}
+
+// TestRunMetricsNil verifies a nil *runMetrics is a no-op.
+func TestRunMetricsNil(t *testing.T) {
+	var m *runMetrics
+	m.recordAudited("needs-removal")
+	m.recordNamespaceError()
+	m.recordMigration(migrationResult{Status: "success"})
+	m.recordSyncWait("cluster1", time.Second, true)
+	if err := m.push("http://unused", "mgmt-123"); err != nil {
+		t.Errorf("push() on nil metrics returned error: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const fixMgmtAutoscaling = "Ask the management cluster owners to configure the cluster autoscaler, and to enable autoscaling " +
+	"on the request serving machine pools with a max replicas covering the clusters to migrate"
+
+// servingCapacity is what the management cluster's autoscaling configuration allows for request serving
+// nodes, as read from OCM and the management cluster.
+type servingCapacity struct {
+	// autoscaler is the management cluster's ClusterAutoscaler, or nil when none is configured.
+	autoscaler *cmv1.ClusterAutoscaler
+	// pools are the machine pools labeled for request serving components.
+	pools []*cmv1.MachinePool
+	// nodes are all nodes of the management cluster.
+	nodes []corev1.Node
+}
+
+// servingSize is the request serving capacity of one size class.
+type servingSize struct {
+	maxNodes int
+	inUse    int
+}
+
+// readServingCapacity reads the ClusterAutoscaler and machine pools of the management cluster from OCM, and
+// its nodes from the cluster.
+func readServingCapacity(ctx context.Context, conn *sdk.Connection, mgmtClient client.Client, clusterID string) (*servingCapacity, error) {
+	cluster := conn.ClustersMgmt().V1().Clusters().Cluster(clusterID)
+	capacity := &servingCapacity{}
+
+	autoscaler, err := cluster.Autoscaler().Get().SendContext(ctx)
+	switch {
+	case autoscaler != nil && autoscaler.Status() == http.StatusNotFound:
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the cluster autoscaler: %v", err)
+	default:
+		capacity.autoscaler = autoscaler.Body()
+	}
+
+	pools, err := cluster.MachinePools().List().SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list machine pools: %v", err)
+	}
+	for _, pool := range pools.Items().Slice() {
+		if pool.Labels()[hypershiftv1beta1.RequestServingComponentLabel] == "true" {
+			capacity.pools = append(capacity.pools, pool)
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := mgmtClient.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	capacity.nodes = nodes.Items
+	return capacity, nil
+}
+
+// warnings returns what keeps the management cluster from scaling out request serving nodes for the clusters
+// migrated to resource-based autoscaling: a missing cluster autoscaler, request serving machine pools that do
+// not autoscale, and size classes, or a whole cluster, without room for the nodes of expectedClusters more
+// clusters, or of one more cluster in each size class.
+func (s *servingCapacity) warnings(expectedClusters int) []string {
+	var warnings []string
+	if s.autoscaler == nil {
+		warnings = append(warnings, "no cluster autoscaler is configured, so machine pools cannot scale out")
+	}
+	if len(s.pools) == 0 {
+		return append(warnings, fmt.Sprintf("no machine pool is labeled %s=true", hypershiftv1beta1.RequestServingComponentLabel))
+	}
+
+	_, _, perCluster := requestServingNodes(s.nodes)
+	demand := max(expectedClusters, 1) * perCluster
+
+	sizes := map[string]*servingSize{}
+	for _, pool := range s.pools {
+		size := pool.Labels()[hypershiftv1beta1.NodeSizeLabel]
+		if sizes[size] == nil {
+			sizes[size] = &servingSize{}
+		}
+		if pool.Autoscaling() == nil {
+			warnings = append(warnings, fmt.Sprintf("machine pool %s (size %s) does not autoscale, fixed at %d replicas",
+				pool.ID(), driftValue(size), pool.Replicas()))
+			sizes[size].maxNodes += pool.Replicas()
+			continue
+		}
+		sizes[size].maxNodes += pool.Autoscaling().MaxReplicas()
+	}
+	for _, node := range s.nodes {
+		if node.Labels[hypershiftv1beta1.RequestServingComponentLabel] != "true" {
+			continue
+		}
+		if size := sizes[node.Labels[hypershiftv1beta1.NodeSizeLabel]]; size != nil {
+			size.inUse++
+		}
+	}
+
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headroom := 0
+	for _, name := range names {
+		size := sizes[name]
+		free := max(size.maxNodes-size.inUse, 0)
+		headroom += free
+		if free < perCluster {
+			warnings = append(warnings, fmt.Sprintf("size %s has %d of at most %d request serving nodes in use, no room for another cluster's %d",
+				driftValue(name), size.inUse, size.maxNodes, perCluster))
+		}
+	}
+	if expectedClusters > 0 && headroom < demand {
+		warnings = append(warnings, fmt.Sprintf("request serving machine pools can add %d nodes, less than the %d that %d clusters need",
+			headroom, demand, expectedClusters))
+	}
+	if s.autoscaler != nil && s.autoscaler.ResourceLimits() != nil {
+		if maxNodes := s.autoscaler.ResourceLimits().MaxNodesTotal(); maxNodes > 0 && len(s.nodes)+demand > maxNodes {
+			warnings = append(warnings, fmt.Sprintf("the cluster autoscaler's max nodes total %d leaves room for %d more nodes, less than the %d needed",
+				maxNodes, max(maxNodes-len(s.nodes), 0), demand))
+		}
+	}
+	return warnings
+}
+
+// summary describes the request serving capacity when there is nothing to warn about.
+func (s *servingCapacity) summary() string {
+	maxNodes, inUse := 0, 0
+	for _, pool := range s.pools {
+		if pool.Autoscaling() != nil {
+			maxNodes += pool.Autoscaling().MaxReplicas()
+		} else {
+			maxNodes += pool.Replicas()
+		}
+	}
+	for _, node := range s.nodes {
+		if node.Labels[hypershiftv1beta1.RequestServingComponentLabel] == "true" {
+			inUse++
+		}
+	}
+	return fmt.Sprintf("cluster autoscaler configured; %d request serving machine pools with %d of at most %d nodes in use",
+		len(s.pools), inUse, maxNodes)
+}
+
+// checkMgmtClusterAutoscaling records the management cluster autoscaling check. It only warns: the
+// migration can run without room to scale out, but migrated clusters may then wait for request serving nodes.
+func checkMgmtClusterAutoscaling(ctx context.Context, r *preflightReport, conn *sdk.Connection, mgmtClient client.Client,
+	clusterID string, expectedClusters int) {
+	capacity, err := readServingCapacity(ctx, conn, mgmtClient, clusterID)
+	if err != nil {
+		r.warn(fmt.Sprintf("could not be checked: %v", err), fixMgmtAutoscaling)
+		return
+	}
+	if warnings := capacity.warnings(expectedClusters); len(warnings) > 0 {
+		r.warn(strings.Join(warnings, "; "), fixMgmtAutoscaling)
+		return
+	}
+	r.pass(capacity.summary())
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestServingCapacityWarnings verifies the management cluster autoscaling check warns about a missing
+// cluster autoscaler, machine pools that do not autoscale and size classes without room for more clusters.
+func TestServingCapacityWarnings(t *testing.T) {
+	pool := func(id, size string, autoscaling *cmv1.MachinePoolAutoscalingBuilder) *cmv1.MachinePool {
+		builder := cmv1.NewMachinePool().ID(id).Replicas(2).Labels(map[string]string{
+			hypershiftv1beta1.RequestServingComponentLabel: "true", hypershiftv1beta1.NodeSizeLabel: size})
+		if autoscaling != nil {
+			builder = builder.Autoscaling(autoscaling)
+		}
+		p, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	maxReplicas := func(n int) *cmv1.MachinePoolAutoscalingBuilder {
+		return cmv1.NewMachinePoolAutoscaling().MinReplicas(0).MaxReplicas(n)
+	}
+	nodes := func(size string, n int) []corev1.Node {
+		var nodes []corev1.Node
+		for range n {
+			nodes = append(nodes, corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				hypershiftv1beta1.RequestServingComponentLabel: "true", hypershiftv1beta1.NodeSizeLabel: size}}})
+		}
+		return nodes
+	}
+	autoscaler := func(maxNodesTotal int) *cmv1.ClusterAutoscaler {
+		a, err := cmv1.NewClusterAutoscaler().ResourceLimits(cmv1.NewAutoscalerResourceLimits().MaxNodesTotal(maxNodesTotal)).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	tests := []struct {
+		name             string
+		capacity         servingCapacity
+		expectedClusters int
+		expected         []string
+	}{
+		{
+			name: "room to scale out",
+			capacity: servingCapacity{
+				autoscaler: autoscaler(0),
+				pools:      []*cmv1.MachinePool{pool("serving-small", "small", maxReplicas(10)), pool("serving-large", "large", maxReplicas(4))},
+				nodes:      nodes("small", 4),
+			},
+		},
+		{
+			name:     "no cluster autoscaler or serving pools",
+			expected: []string{"no cluster autoscaler is configured", "no machine pool is labeled hypershift.openshift.io/request-serving-component=true"},
+		},
+		{
+			name: "pool without autoscaling",
+			capacity: servingCapacity{
+				autoscaler: autoscaler(0),
+				pools:      []*cmv1.MachinePool{pool("serving-small", "small", nil)},
+				nodes:      nodes("small", 2),
+			},
+			expected: []string{
+				"machine pool serving-small (size small) does not autoscale, fixed at 2 replicas",
+				"size small has 2 of at most 2 request serving nodes in use, no room for another cluster's 2",
+			},
+		},
+		{
+			name: "not enough room for the expected clusters",
+			capacity: servingCapacity{
+				autoscaler: autoscaler(12),
+				pools:      []*cmv1.MachinePool{pool("serving-small", "small", maxReplicas(10))},
+				nodes:      nodes("small", 4),
+			},
+			expectedClusters: 5,
+			expected: []string{
+				"request serving machine pools can add 6 nodes, less than the 10 that 5 clusters need",
+				"the cluster autoscaler's max nodes total 12 leaves room for 8 more nodes, less than the 10 needed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := tt.capacity.warnings(tt.expectedClusters)
+			if len(warnings) != len(tt.expected) {
+				t.Fatalf("warnings() = %q, want %d warnings", warnings, len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				if !strings.HasPrefix(warnings[i], expected) {
+					t.Errorf("Warning %d = %q, want %q", i, warnings[i], expected)
+				}
+			}
+		})
+	}
+}
+
+// TestPreflightReportWarn verifies a warning does not skip the checks after it, fail the preflight or go
+// unreported.
+func TestPreflightReportWarn(t *testing.T) {
+	r := &preflightReport{names: []string{checkMgmtClusterAccess, checkMgmtAutoscaling, checkServiceCluster}}
+	r.pass("can list HostedClusters")
+	r.warn("no cluster autoscaler is configured", fixMgmtAutoscaling)
+	r.pass("hs-sc-def456")
+
+	statuses := []string{}
+	for _, c := range r.checks {
+		statuses = append(statuses, c.Status)
+	}
+	if !reflect.DeepEqual(statuses, []string{preflightPassed, preflightWarning, preflightPassed}) {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+	if err := preflightError(r.checks); err != nil {
+		t.Errorf("Expected warnings not to fail the preflight, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	printPreflightWarnings(&buf, r.checks)
+	expected := "Preflight warnings:\n  - Management cluster autoscaling: no cluster autoscaler is configured\n\n" +
+		"To fix:\n  - Management cluster autoscaling: " + fixMgmtAutoscaling + "\n"
+	if buf.String() != expected {
+		t.Errorf("printPreflightWarnings() = %q, want %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	printPreflightWarnings(&buf, r.checks[:1])
+	if buf.Len() > 0 {
+		t.Errorf("Expected nothing printed without warnings, got %q", buf.String())
+	}
+}
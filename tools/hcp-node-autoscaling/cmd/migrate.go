@@ -0,0 +1,1538 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/types"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultSyncTimeout  = 5 * time.Minute
+	defaultPollInterval = 15 * time.Second
+	minSyncTimeout      = 30 * time.Second
+	maxSyncTimeout      = 60 * time.Minute
+	minPollInterval     = 1 * time.Second
+	maxPollInterval     = 5 * time.Minute
+	defaultMaxAuditAge  = 24 * time.Hour
+
+	defaultConflictRetries = 5
+	maxConflictRetries     = 20
+
+	defaultMaxRetries = 2
+	maxMaxRetries     = 10
+
+	defaultMaxInFlight = 1
+	maxMaxInFlight     = 20
+)
+
+type migrateOpts struct {
+	serviceClusterID string
+	mgmtClusterID    string
+	mgmtClusterIDs   []string
+	maxInFlight      int
+	environment      string
+	dryRun           bool
+	skipConfirmation bool
+	interactive      bool
+	fromAudit        string
+	candidatesFile   string
+	ignoreFreeze     bool
+	includePaused    bool
+	forceOverwrite   bool
+	skipPreflight    bool
+	maxAuditAge      time.Duration
+	syncTimeout      time.Duration
+	pollInterval     time.Duration
+	conflictRetries  int
+	patchStrategy    string
+	ticket           string
+	elevationReason  string
+	direct           bool
+	serviceLog       bool
+	historyDir       string
+	changeRecord     string
+	excludeIDs       []string
+	excludeFile      string
+	exclusions       map[string]string
+	profilePath      string
+	profile          *migrationProfile
+	timeouts         phaseTimeouts
+	kubeconfigs      kubeconfigOverrides
+	listing          namespaceListing
+	clients          clientfactory.Factory
+	serviceClient    client.Client
+	mgmtClient       client.Client
+	ocmConn          *sdk.Connection
+	mgmtClusterName  string
+	auditReport      *auditResults
+	candidateRows    []candidateRow
+	operator         string
+	history          *runHistory
+
+	metricsPushgatewayURL string
+	metrics               *runMetrics
+	notifyWebhook         string
+	notifyFormat          string
+
+	planFile       string
+	signingKeyFile string
+	signingKey     []byte
+	plan           *migrationPlan
+
+	// serviceLogSummary replaces the summary of service log entries for runs that make annotation changes
+	// other than the autoscaling migration.
+	serviceLogSummary string
+
+	// dryRunMode is the --dry-run value, parsed into dryRun and serverDryRun on initialization.
+	dryRunMode   string
+	serverDryRun bool
+
+	// minVersionFlag is the --min-version value, parsed into minVersion on initialization.
+	minVersionFlag string
+	minVersion     *utilversion.Version
+
+	// progress is shared by the management clusters of a --mgmt-cluster-ids run. Otherwise each
+	// migrateClusters call shows its own progress bar.
+	progress *progressBar
+
+	// pagerDuty suppresses the alerts of the migrated clusters with a maintenance window.
+	pagerDuty pdMaintenance
+
+	// maxRetries is how many times a cluster that failed with a transient failure class is migrated again,
+	// retryDelay apart.
+	maxRetries int
+	retryDelay time.Duration
+
+	// stampProvenance also sets the migrated-at and run ID annotations on each patched HostedCluster.
+	stampProvenance bool
+
+	// rollout orders the management clusters of a --mgmt-cluster-ids run into stages by sector.
+	rollout rolloutPlan
+
+	// skipped are the audited candidates left out of the run before anything was patched.
+	skipped []skippedCluster
+
+	// output is text, or jsonl to write the events of the run to stdout as they happen.
+	output string
+	events *eventStream
+
+	// session holds the run ID and the settings of the run shared by all subcommands.
+	session *runSession
+
+	// broker publishes the migration outcome of each cluster to --events-broker.
+	eventsBrokerURL string
+	eventsTopic     string
+	broker          *eventsBroker
+
+	// noVerify skips waiting for sync and records each patched cluster to pendingFile for the verify command.
+	noVerify    bool
+	pendingFile string
+	pending     *pendingRecorder
+
+	// confirmThreshold is the number of clusters from which the confirmation must be typed rather than y/N.
+	confirmThreshold int
+
+	// saveManifestsDir is where the original and patched HostedCluster manifest of each cluster is saved
+	// before it is patched.
+	saveManifestsDir string
+
+	// viaOCM makes the change through the Clusters Mgmt API where it supports it, and falls back to patching
+	// the ManifestWorks where it does not.
+	viaOCM bool
+
+	// allowMismatch continues when the service cluster is not the management cluster's parent or belongs to
+	// another OCM environment.
+	allowMismatch bool
+
+	// maxFailures and maxFailureRate (a percentage) abort the migrations not started yet once exceeded.
+	maxFailures    int
+	maxFailureRate float64
+
+	// emitScript writes the ManifestWork patches to this file as a script of oc patch commands instead of
+	// applying them.
+	emitScript string
+
+	// clusterNames are the --cluster-names to migrate, resolved on initialization into targets, the cluster
+	// IDs mapped to the name they were targeted by. A nil targets keeps all candidates.
+	clusterNames []string
+	targets      map[string]string
+
+	// set is the part of the migration --set selects: topology, autoscaling or both. The profile is
+	// restricted to the part on initialization.
+	set string
+
+	// overrides maps the ID of each candidate whose --candidates-file row gives annotations their own values
+	// to those values, applied on top of the profile for its patch, conflict check and verification.
+	overrides map[string]map[string]string
+
+	// maxClusters is the --max-clusters cap on the number of clusters a run migrates, 0 for commands that
+	// do not register it.
+	maxClusters int
+}
+
+type migrationResult struct {
+	ClusterID       string `json:"cluster_id"`
+	ClusterName     string `json:"cluster_name"`
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+	VerifiedAt      string `json:"verified_at,omitempty"`
+	ConflictRetries int    `json:"conflict_retries,omitempty"`
+
+	// FailureClass groups failed migrations by cause, e.g. rbac or sync-timeout.
+	FailureClass string `json:"failure_class,omitempty"`
+
+	// Retries is how many times the cluster was migrated again after a transient failure.
+	Retries int `json:"retries,omitempty"`
+
+	// SyncSeconds is how long it took to verify the annotations on the management cluster.
+	SyncSeconds float64 `json:"sync_seconds,omitempty"`
+
+	// Overwritten lists the conflicting annotation values replaced with --force-overwrite.
+	Overwritten []annotationConflict `json:"overwritten,omitempty"`
+}
+
+// NewMigrateCmd creates the migrate subcommand for migrating clusters to autoscaling.
+func NewMigrateCmd() *cobra.Command {
+	opts := &migrateOpts{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Enable autoscaling for hosted clusters on a service cluster",
+		Long: `Perform autoscaling migration for hosted clusters that are ready.
+
+This command will:
+1. Audit the management cluster to find clusters ready for migration
+2. Display the list and ask for confirmation
+3. Patch ManifestWork resources on the service cluster
+4. Verify the annotations are synced to the management cluster
+5. Report results`,
+		Example: `
+  # Migrate clusters with confirmation
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456
+
+  # Dry run to see what would be migrated
+  hcp-node-autoscaling migrate \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --dry-run
+
+  # Skip confirmation prompt (use with caution)
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --skip-confirmation
+
+  # Allow more time for slow work-agent reconciliation
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --sync-timeout 15m \
+    --poll-interval 30s
+
+  # Interactively choose which clusters to migrate
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --interactive
+
+  # Patch only the HostedCluster annotations instead of replacing the ManifestWork
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --patch-strategy json-patch
+
+  # Never touch clusters on the centrally maintained exclusion list
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --exclude-file exclusions.txt
+
+  # Migrate only the clusters from a previously reviewed audit report
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --service-cluster-id svc-123 \
+    --mgmt-cluster-id mgmt-456 \
+    --from-audit audit.json
+
+  # Migrate the clusters listed by another team's tooling, re-validated against the management cluster
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-id mgmt-456 \
+    --candidates-file clusters.csv
+
+  # Migrate several management clusters at once, discovering each one's service cluster,
+  # with at most 3 clusters in flight per management cluster
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-ids mgmt-456,mgmt-789 \
+    --max-in-flight-per-mc 3
+
+  # Patch and verify up to 5 clusters of one management cluster in parallel
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-id mgmt-456 \
+    --migrate-concurrency 5
+
+  # Break-glass: patch the HostedClusters directly on the management cluster when the work agent is broken
+  hcp-node-autoscaling migrate \
+    --ticket OHSS-12345 \
+    --mgmt-cluster-id mgmt-456 \
+    --direct`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateMaxClusters(opts.maxClusters); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	addAllowMismatchFlag(cmd, &opts.allowMismatch)
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "",
+		"The management cluster ID to migrate")
+	cmd.Flags().StringSliceVar(&opts.mgmtClusterIDs, "mgmt-cluster-ids", nil,
+		"Comma-separated management cluster IDs to migrate concurrently")
+	cmd.Flags().IntVar(&opts.maxInFlight, "max-in-flight-per-mc", defaultMaxInFlight,
+		"Maximum number of clusters migrated at the same time on each management cluster")
+	cmd.Flags().IntVar(&opts.maxInFlight, "migrate-concurrency", defaultMaxInFlight,
+		"Number of clusters patched and verified in parallel (alias of --max-in-flight-per-mc)")
+	cmd.Flags().StringVar(&opts.environment, "environment", "production",
+		"OCM environment whose hosted cluster namespaces are migrated: production, staging, all")
+	addDryRunFlag(cmd, &opts.dryRunMode, "each ManifestWork")
+	cmd.Flags().BoolVar(&opts.skipConfirmation, "skip-confirmation", false,
+		"Skip confirmation prompt (use with caution)")
+	addConfirmThresholdFlag(cmd, &opts.confirmThreshold)
+	addMaxClustersFlag(cmd, &opts.maxClusters)
+	cmd.Flags().BoolVar(&opts.interactive, "interactive", false,
+		"Interactively select which candidate clusters to migrate")
+	cmd.Flags().BoolVar(&opts.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login, backplane access and ManifestWork permissions before the migration")
+	cmd.Flags().BoolVar(&opts.ignoreFreeze, "ignore-freeze", false,
+		"Migrate clusters that are upgrading, have a control plane upgrade scheduled or are in limited support")
+	cmd.Flags().BoolVar(&opts.includePaused, "include-paused", false,
+		"Migrate clusters with spec.pausedUntil or a manual control plane annotation set")
+	cmd.Flags().BoolVar(&opts.forceOverwrite, "force-overwrite", false,
+		"Migrate clusters whose existing topology annotation differs from the migration, replacing it")
+	cmd.Flags().StringVar(&opts.minVersionFlag, "min-version", "",
+		"Only migrate clusters running at least this OpenShift version, e.g. 4.16.10")
+	cmd.Flags().StringVar(&opts.fromAudit, "from-audit", "",
+		"Read migration candidates from a saved audit JSON report instead of re-auditing")
+	cmd.Flags().DurationVar(&opts.maxAuditAge, "max-audit-age", defaultMaxAuditAge,
+		"Maximum age of the audit report passed to --from-audit")
+	cmd.Flags().StringVar(&opts.candidatesFile, "candidates-file", "",
+		"CSV or JSON file of cluster IDs, with optional namespace and cluster_name, to migrate after re-validating them")
+	cmd.Flags().DurationVar(&opts.syncTimeout, "sync-timeout", defaultSyncTimeout,
+		"Maximum time to wait for annotations to sync to the management cluster")
+	cmd.Flags().DurationVar(&opts.pollInterval, "poll-interval", defaultPollInterval,
+		"Interval between sync verification attempts")
+	cmd.Flags().IntVar(&opts.conflictRetries, "conflict-retries", defaultConflictRetries,
+		"Number of times to retry a ManifestWork update that fails with a conflict")
+	cmd.Flags().BoolVar(&opts.noVerify, "no-verify", false,
+		"Do not wait for the annotations to sync; record each patched cluster to --pending-file for the verify command")
+	cmd.Flags().StringVar(&opts.pendingFile, "pending-file", defaultPendingFile,
+		"File the clusters patched with --no-verify are appended to")
+	cmd.Flags().StringVar(&opts.output, "output", "text",
+		"Output format: text, or jsonl to write an event per patch, verification and error to stdout as it happens")
+	cmd.Flags().BoolVar(&opts.stampProvenance, "stamp-provenance", false,
+		"Also annotate each patched HostedCluster with the time and run ID of its migration, reported by audit")
+	cmd.Flags().IntVar(&opts.maxRetries, "max-retries", defaultMaxRetries,
+		"Number of times to migrate a cluster again after a transient failure (API timeout or exhausted conflict retries)")
+	addFailureBudgetFlags(cmd, opts)
+	addClusterNamesFlag(cmd, opts)
+	addSetFlag(cmd, opts)
+	cmd.Flags().StringVar(&opts.emitScript, "emit-script", "",
+		"Write the ManifestWork patches to this file as an executable script of oc patch commands instead of applying them")
+	cmd.Flags().StringVar(&opts.patchStrategy, "patch-strategy", "update",
+		"How to write the ManifestWork: update, json-patch")
+	cmd.Flags().StringVar(&opts.ticket, "ticket", "",
+		"JIRA issue approving the migration, e.g. OHSS-12345 (required unless --dry-run)")
+	cmd.Flags().StringVar(&opts.ticket, "reason", "", "Alias of --ticket")
+	cmd.Flags().StringVar(&opts.elevationReason, "elevation-reason", defaultElevationReason,
+		"Description of the change recorded after the ticket on the backplane elevation, the run history and service log entries")
+	cmd.Flags().BoolVar(&opts.viaOCM, "via-ocm", false,
+		"Make the change through the Clusters Mgmt API, falling back to patching the ManifestWorks with a warning where the API does not support it")
+	cmd.Flags().BoolVar(&opts.direct, "direct", false,
+		"Break-glass: patch the HostedClusters on the management cluster with elevated permissions instead of their ManifestWorks")
+	cmd.Flags().BoolVar(&opts.serviceLog, "service-log", false,
+		"Post an internal OCM service log entry for each migrated cluster")
+	cmd.Flags().StringVar(&opts.saveManifestsDir, "save-manifests", "",
+		"Directory to save the original and patched HostedCluster manifest of each cluster to before patching it")
+	cmd.Flags().StringVar(&opts.historyDir, "history-dir", "",
+		"Directory to write the run history to (default ~/.config/hcp-node-autoscaling/history)")
+	cmd.Flags().StringVar(&opts.changeRecord, "change-record", "",
+		"Write a markdown change record of the run to this file, to attach to the change ticket")
+	cmd.Flags().StringSliceVar(&opts.excludeIDs, "exclude-cluster-ids", nil,
+		"Comma-separated cluster IDs that must never be migrated")
+	cmd.Flags().StringVar(&opts.excludeFile, "exclude-file", "",
+		"File of cluster IDs that must never be migrated, one per line")
+	cmd.Flags().StringVar(&opts.profilePath, "profile", "",
+		"Migration profile YAML declaring the annotations to ensure and remove and the categorization rules (default built-in profile)")
+	cmd.Flags().StringVar(&opts.metricsPushgatewayURL, "metrics-pushgateway-url", "",
+		"Push run metrics to this Prometheus pushgateway URL when the run completes")
+	cmd.Flags().StringVar(&opts.notifyWebhook, "notify-webhook", "",
+		"Post a summary of the run to this webhook URL when the run completes")
+	cmd.Flags().StringVar(&opts.notifyFormat, "notify-format", "slack",
+		"Payload of --notify-webhook: slack, json")
+	addEventsBrokerFlags(cmd, &opts.eventsBrokerURL, &opts.eventsTopic)
+	opts.timeouts.addFlags(cmd, true)
+	opts.kubeconfigs.addFlags(cmd, true)
+	opts.listing.addFlags(cmd)
+	opts.pagerDuty.addFlags(cmd)
+
+	cmd.MarkFlagsOneRequired("mgmt-cluster-id", "mgmt-cluster-ids")
+	opts.rollout.addFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-id", "mgmt-cluster-ids")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "interactive")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "from-audit")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "candidates-file")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "mgmt-kubeconfig")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "service-kubeconfig")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-ids", "cluster-names")
+	cmd.MarkFlagsMutuallyExclusive("from-audit", "candidates-file")
+	cmd.MarkFlagsMutuallyExclusive("max-in-flight-per-mc", "migrate-concurrency")
+	cmd.MarkFlagsMutuallyExclusive("ticket", "reason")
+	cmd.MarkFlagsMutuallyExclusive("direct", "mgmt-cluster-ids")
+	cmd.MarkFlagsMutuallyExclusive("direct", "service-cluster-id")
+	cmd.MarkFlagsMutuallyExclusive("direct", "patch-strategy")
+	cmd.MarkFlagsMutuallyExclusive("via-ocm", "direct")
+	for _, flag := range []string{"direct", "mgmt-cluster-ids", "dry-run", "interactive", "no-verify"} {
+		cmd.MarkFlagsMutuallyExclusive("emit-script", flag)
+	}
+	cmd.MarkFlagsMutuallyExclusive("no-verify", "rollout-order")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run executes the migrate command to patch clusters with autoscaling annotations.
+func (m *migrateOpts) run(ctx context.Context) error {
+	if err := m.rollout.validate(m.mgmtClusterIDs); err != nil {
+		return err
+	}
+	if err := m.setupEvents(); err != nil {
+		return err
+	}
+	broker, err := newEventsBroker(ctx, m.eventsBrokerURL, m.eventsTopic, m.session.runID())
+	if err != nil {
+		return err
+	}
+	m.broker = broker
+	defer m.broker.close()
+	if m.noVerify {
+		if strings.TrimSpace(m.pendingFile) == "" {
+			return fmt.Errorf("--no-verify requires a --pending-file")
+		}
+		m.pending = &pendingRecorder{path: m.pendingFile}
+	}
+	m.checkViaOCM(m.session.infoOut())
+	if len(m.mgmtClusterIDs) > 0 {
+		return m.runMulti(ctx)
+	}
+	if err := m.initialize(ctx); err != nil {
+		return fmt.Errorf("initialization failed: %v", err)
+	}
+	defer m.ocmConn.Close()
+	defer func() {
+		if err := m.metrics.push(m.metricsPushgatewayURL, m.mgmtClusterID); err != nil {
+			slog.Warn("Failed to push metrics", "error", err)
+		}
+	}()
+
+	var candidates []hostedClusterAuditInfo
+	switch {
+	case m.plan != nil:
+		candidates, err = m.getCandidatesFromPlan(ctx)
+	case m.auditReport != nil:
+		candidates, err = m.getCandidatesFromAudit(ctx)
+	case m.candidateRows != nil:
+		var rejected []rejectedCandidate
+		candidates, rejected, err = m.getCandidatesFromFile(ctx)
+		displayRejectedCandidates(m.session.infoOut(), m.candidatesFile, rejected)
+		m.skipRejected(rejected)
+	default:
+		candidates, err = m.getCandidatesForMigration(ctx)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("failed to get migration candidates: %v", err))
+		}
+		return fmt.Errorf("failed to get migration candidates: %v", err)
+	}
+
+	candidates = m.filterTargeted(m.session.infoOut(), candidates)
+	candidates, belowMinVersion := m.skipFiltered(candidates)
+	displayBelowMinVersion(m.session.infoOut(), belowMinVersion)
+
+	var frozen []frozenCluster
+	candidates, frozen = m.filterFrozen(ctx, candidates)
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking for maintenance and change freezes"))
+	}
+	displayFrozen(m.session.infoOut(), frozen)
+	m.skipFrozen(frozen)
+
+	candidates, notReady := m.filterOCMState(ctx, candidates)
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking OCM cluster states"))
+	}
+	displayNotReady(m.session.infoOut(), notReady)
+	m.skipped = append(m.skipped, notReady...)
+
+	candidates, missingManifestWork, err := m.checkManifestWorks(ctx, candidates)
+	if err != nil {
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("interrupted while checking ManifestWorks"))
+		}
+		return fmt.Errorf("ManifestWork pre-flight failed: %v", err)
+	}
+	displayMissingManifestWorks(m.session.infoOut(), missingManifestWork, len(candidates))
+	m.skipped = append(m.skipped, missingManifestWork...)
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(m.session.infoOut())
+		displaySkipped(m.session.infoOut(), m.skipped)
+		fmt.Fprintln(m.session.infoOut(), "No clusters found ready for migration")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no clusters found ready for migration"))
+	}
+
+	if !m.interactive {
+		if err := m.checkMaxClusters(m.session.infoOut(), len(candidates)); err != nil {
+			return err
+		}
+	}
+	if m.planFile != "" {
+		return m.writePlan(ctx, os.Stdout, candidates)
+	}
+	if m.emitScript != "" {
+		return m.writeScript(ctx, m.session.infoOut(), candidates)
+	}
+
+	if m.interactive {
+		candidates, err = m.selectCandidates(candidates, os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			fmt.Println("No clusters selected for migration")
+			return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no clusters selected for migration"))
+		}
+		fmt.Printf("\n%d clusters selected for migration\n", len(candidates))
+		if err := m.checkMaxClusters(m.session.infoOut(), len(candidates)); err != nil {
+			return err
+		}
+	} else {
+		m.displayCandidates(m.session.infoOut(), candidates)
+		if m.direct {
+			printDirectWarning(m.session.infoOut())
+		}
+
+		if !m.skipConfirmation && !m.dryRun {
+			if !m.confirm(os.Stdin, m.session.infoOut(), len(candidates), m.mgmtClusterName) {
+				return fmt.Errorf("migration cancelled by user")
+			}
+		}
+	}
+
+	if m.dryRun {
+		m.displayDryRunDiff(ctx, os.Stdout, candidates)
+		m.displayCapacityDelta(ctx, os.Stdout, candidates)
+		if m.serverDryRun {
+			if err := m.submitServerDryRun(ctx, os.Stdout, candidates); err != nil {
+				return err
+			}
+		}
+		fmt.Println("[DRY RUN] No changes will be applied")
+		return nil
+	}
+
+	window, err := m.pagerDuty.start(ctx, ocmServiceLabel(ctx, m.ocmConn, m.pagerDuty.serviceLabel), candidates,
+		pdMaintenanceDescription(m.ticket, []string{m.mgmtClusterName}))
+	if err != nil {
+		return err
+	}
+
+	m.history = m.newRunHistory(m.historyDir, time.Now())
+	m.recordSkipped()
+
+	results := m.migrateClusters(ctx, candidates)
+	notStarted := candidates[len(results):]
+	window.end(ctx)
+
+	if err := m.history.finish(); err != nil {
+		slog.Warn("Failed to write run history", "error", err)
+	} else {
+		slog.Info("Wrote run history", "file", m.history.path)
+	}
+
+	m.displayResults(m.session.infoOut(), results, notStarted)
+	writeChangeRecord(m.changeRecord, []*mgmtClusterRun{{opts: m, candidates: candidates, results: results}})
+	if m.direct {
+		printDirectFollowUp(m.session.infoOut(), results, m.mgmtClusterID)
+	}
+	notify(ctx, m.notifyWebhook, m.notifyFormat, m.migrationNotification(results, notStarted))
+
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted,
+			fmt.Errorf("migration interrupted: %d of %d clusters not started", len(notStarted), len(candidates)))
+	}
+
+	return migrationExitError(results)
+}
+
+// initialize validates inputs and creates OCM connections and Kubernetes clients. When no service
+// cluster ID is set, the management cluster's parent service cluster is discovered from OCM. With
+// --direct the service cluster is not used.
+func (m *migrateOpts) initialize(ctx context.Context) error {
+	if m.dryRunMode != "" {
+		dryRun, serverDryRun, err := parseDryRunMode(m.dryRunMode)
+		if err != nil {
+			return err
+		}
+		m.dryRun, m.serverDryRun = dryRun, serverDryRun
+	}
+	if m.serviceClusterID != "" {
+		if err := utils.IsValidClusterKey(m.serviceClusterID); err != nil {
+			return fmt.Errorf("invalid service cluster ID: %v", err)
+		}
+	}
+	if err := utils.IsValidClusterKey(m.mgmtClusterID); err != nil {
+		return fmt.Errorf("invalid management cluster ID: %v", err)
+	}
+	if err := validateSyncSettings(m.syncTimeout, m.pollInterval); err != nil {
+		return err
+	}
+	if err := validateTicket(m.ticket, !m.dryRun && m.planFile == ""); err != nil {
+		return err
+	}
+	if _, err := ocmNamespacePattern(m.environment); err != nil {
+		return err
+	}
+	minVersion, err := parseMinVersion(m.minVersionFlag)
+	if err != nil {
+		return err
+	}
+	m.minVersion = minVersion
+	if m.conflictRetries < 0 || m.conflictRetries > maxConflictRetries {
+		return fmt.Errorf("invalid conflict retries %d: must be between 0 and %d", m.conflictRetries, maxConflictRetries)
+	}
+	if m.maxRetries < 0 || m.maxRetries > maxMaxRetries {
+		return fmt.Errorf("invalid max retries %d: must be between 0 and %d", m.maxRetries, maxMaxRetries)
+	}
+	if m.maxInFlight < 1 || m.maxInFlight > maxMaxInFlight {
+		return fmt.Errorf("invalid max in-flight %d: must be between 1 and %d", m.maxInFlight, maxMaxInFlight)
+	}
+	validStrategies := map[string]bool{"update": true, "json-patch": true}
+	if !validStrategies[m.patchStrategy] {
+		return fmt.Errorf("invalid patch strategy '%s'. Valid options: update, json-patch", m.patchStrategy)
+	}
+	if err := validateFailureBudget(m.maxFailures, m.maxFailureRate); err != nil {
+		return err
+	}
+	if err := validateConfirmThreshold(m.confirmThreshold); err != nil {
+		return err
+	}
+	if m.interactive && m.skipConfirmation {
+		return fmt.Errorf("--interactive cannot be combined with --skip-confirmation")
+	}
+	if m.session != nil && m.session.quiet && !m.skipConfirmation && !m.dryRun && m.planFile == "" {
+		return fmt.Errorf("--quiet requires --skip-confirmation, since the candidates are not listed for confirmation")
+	}
+	if m.changeRecord != "" && m.dryRun {
+		return fmt.Errorf("--change-record cannot be combined with --dry-run")
+	}
+	if m.saveManifestsDir != "" && m.dryRun {
+		return fmt.Errorf("--save-manifests cannot be combined with --dry-run")
+	}
+	if m.metricsPushgatewayURL != "" {
+		if err := validatePushgatewayURL(m.metricsPushgatewayURL); err != nil {
+			return err
+		}
+		m.metrics = newRunMetrics("migrate", m.session.runID())
+	}
+	if err := validateNotifyFlags(m.notifyWebhook, m.notifyFormat); err != nil {
+		return err
+	}
+	if err := m.pagerDuty.validate(); err != nil {
+		return err
+	}
+	if err := m.kubeconfigs.validate(); err != nil {
+		return err
+	}
+	if err := m.listing.validate(); err != nil {
+		return err
+	}
+	if err := validateSet(m.set); err != nil {
+		return err
+	}
+	if m.fromAudit != "" {
+		report, err := loadAuditReport(m.fromAudit)
+		if err != nil {
+			return err
+		}
+		m.auditReport = report
+	}
+	if m.candidatesFile != "" {
+		rows, err := loadCandidatesFile(m.candidatesFile)
+		if err != nil {
+			return err
+		}
+		m.candidateRows = rows
+	}
+	exclusions, err := loadExclusions(m.excludeIDs, m.excludeFile)
+	if err != nil {
+		return err
+	}
+	m.exclusions = exclusions
+	if m.profile == nil {
+		profile, err := loadProfile(m.profilePath)
+		if err != nil {
+			return err
+		}
+		m.profile, err = profile.partial(m.set)
+		if err != nil {
+			return err
+		}
+	}
+	if m.planFile != "" {
+		m.signingKey, err = loadSigningKey(m.signingKeyFile)
+		if err != nil {
+			return err
+		}
+	}
+	if m.historyDir == "" {
+		dir, err := defaultHistoryDir()
+		if err != nil {
+			return err
+		}
+		m.historyDir = dir
+	}
+
+	if !m.skipPreflight {
+		err := runPreflight(ctx, &preflightOpts{
+			mgmtClusterID:       m.mgmtClusterID,
+			serviceClusterID:    m.serviceClusterID,
+			mgmtAutoscaling:     true,
+			serviceCluster:      !m.direct,
+			manifestWorkUpdates: !m.direct && m.planFile == "",
+			elevationReason:     m.elevation(),
+			kubeconfigs:         m.kubeconfigs,
+			clients:             m.clients,
+			session:             m.session,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	m.ocmConn = conn
+	m.operator = currentOperator(conn)
+	if len(m.clusterNames) > 0 {
+		err = traced(ctx, spanOCMClusterNames, func(context.Context) error {
+			m.targets, err = resolveClusterNames(m.clusterNames, ocmClusterNameLookup(conn))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var mgmtCluster *cmv1.Cluster
+	err = traced(ctx, spanOCMManagementCluster, func(context.Context) error {
+		mgmtCluster, err = utils.GetCluster(conn, m.mgmtClusterID)
+		if err != nil {
+			return fmt.Errorf("failed to get management cluster: %v", err)
+		}
+
+		isMC, err := utils.IsManagementCluster(mgmtCluster.ID())
+		if err != nil {
+			return fmt.Errorf("failed to verify management cluster: %v", err)
+		}
+		if !isMC {
+			return fmt.Errorf("cluster %s is not a management cluster", mgmtCluster.ID())
+		}
+		return nil
+	}, attribute.String(attrMgmtClusterID, m.mgmtClusterID))
+	if err != nil {
+		return err
+	}
+
+	m.mgmtClusterID = mgmtCluster.ID()
+	setRunAttributes(ctx, attribute.String(attrMgmtClusterID, m.mgmtClusterID))
+	m.mgmtClusterName = mgmtCluster.Name()
+
+	if m.direct {
+		slog.Warn("Patching HostedClusters directly on the management cluster, bypassing ManifestWorks",
+			"mgmtCluster", mgmtCluster.Name(), "mgmtClusterID", mgmtCluster.ID())
+		return m.createClients(ctx)
+	}
+
+	var serviceCluster *cmv1.Cluster
+	err = traced(ctx, spanOCMServiceCluster, func(context.Context) error {
+		serviceCluster, err = resolveServiceCluster(conn, nil, m.serviceClusterID, mgmtCluster.Name())
+		return err
+	}, attribute.String(attrMgmtClusterID, m.mgmtClusterID))
+	if err != nil {
+		return err
+	}
+	m.serviceClusterID = serviceCluster.ID()
+	setRunAttributes(ctx, attribute.String(attrServiceClusterID, m.serviceClusterID))
+	if err := m.checkEnvironment(conn, mgmtCluster.Name(), serviceCluster.Name()); err != nil {
+		return err
+	}
+
+	slog.Info("Resolved clusters",
+		"serviceCluster", serviceCluster.Name(), "serviceClusterID", serviceCluster.ID(),
+		"mgmtCluster", mgmtCluster.Name(), "mgmtClusterID", mgmtCluster.ID(),
+		"manifestWorkNamespace", m.mgmtClusterName)
+
+	if err := m.createClients(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSyncSettings checks that the sync timeout and poll interval are within sane bounds.
+func validateSyncSettings(timeout, pollInterval time.Duration) error {
+	if timeout < minSyncTimeout || timeout > maxSyncTimeout {
+		return fmt.Errorf("invalid sync timeout %v: must be between %v and %v", timeout, minSyncTimeout, maxSyncTimeout)
+	}
+	if pollInterval < minPollInterval || pollInterval > maxPollInterval {
+		return fmt.Errorf("invalid poll interval %v: must be between %v and %v", pollInterval, minPollInterval, maxPollInterval)
+	}
+	if pollInterval > timeout {
+		return fmt.Errorf("invalid poll interval %v: must not exceed sync timeout %v", pollInterval, timeout)
+	}
+	return nil
+}
+
+// createClients initializes Kubernetes clients for service and management clusters.
+// The service cluster client uses elevated permissions to patch ManifestWork resources. With --direct
+// only an elevated management cluster client is created, to update the HostedClusters. Scheduling is
+// registered for the dry run's capacity estimate, which reads the ClusterSizingConfiguration.
+func (m *migrateOpts) createClients(ctx context.Context) error {
+	clientScheme, err := scheme.Cached(scheme.HyperShift, scheme.Core, scheme.Scheduling, scheme.Work)
+	if err != nil {
+		return err
+	}
+
+	clients := m.kubeconfigs.clients(m.clients, m.session.apiRateLimit(), m.mgmtClusterID, m.serviceClusterID)
+	if m.direct {
+		mgmtClient, err := clients.NewElevatedClient(m.mgmtClusterID, clientScheme, m.ocmConn, m.elevation())
+		if err != nil {
+			return fmt.Errorf("failed to create management cluster client with elevated permissions: %v", err)
+		}
+		m.mgmtClient = mgmtClient
+		return nil
+	}
+
+	serviceClient, err := clients.NewElevatedClient(m.serviceClusterID, clientScheme, m.ocmConn, m.elevation())
+	if err != nil {
+		return fmt.Errorf("failed to create service cluster client with elevated permissions: %v", err)
+	}
+	m.serviceClient = serviceClient
+
+	mgmtClient, err := clients.NewClient(m.mgmtClusterID, clientScheme)
+	if err != nil {
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	m.mgmtClient = mgmtClient
+
+	return nil
+}
+
+// getCandidatesForMigration audits the management cluster to find clusters ready for migration.
+func (m *migrateOpts) getCandidatesForMigration(ctx context.Context) ([]hostedClusterAuditInfo, error) {
+	auditOpts := &auditOpts{
+		mgmtClusterID: m.mgmtClusterID,
+		environment:   m.environment,
+		mgmtClient:    m.mgmtClient,
+		profile:       m.profile,
+		includePaused: m.includePaused,
+		timeouts:      m.timeouts,
+		listing:       m.listing,
+		session:       m.session,
+	}
+
+	namespaces, err := auditOpts.listOcmNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Scanning namespaces for migration candidates", "environment", m.environment, "count", len(namespaces))
+
+	var candidates []hostedClusterAuditInfo
+	progress := newProgressBar(m.session.progressOut(), "Scanning", len(namespaces))
+	defer progress.close()
+
+	for _, ns := range namespaces {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("interrupted while scanning namespaces: %v", ctx.Err())
+		}
+		progress.begin(ns.Name)
+		infos, err := auditOpts.auditNamespace(ctx, ns.Name)
+		progress.finish()
+		if err != nil {
+			slog.Warn("Failed to audit namespace", "namespace", ns.Name, "error", err)
+			m.metrics.recordNamespaceError()
+			continue
+		}
+
+		for _, info := range infos {
+			m.metrics.recordAudited(info.Category)
+
+			switch info.Category {
+			case "ready-for-migration":
+				candidates = append(candidates, info)
+			case "paused":
+				slog.Info("Skipping paused cluster, pass --include-paused to migrate it",
+					"clusterID", info.ClusterID, "reason", info.PausedReason)
+				m.skip(info, "paused: "+info.PausedReason)
+			case "deleting":
+				slog.Info("Skipping cluster that is being deleted",
+					"clusterID", info.ClusterID, "deletionTimestamp", info.DeletionTimestamp)
+				m.skip(info, "being deleted")
+			case unmanagedCategory:
+				m.skip(info, "unmanaged: no ManifestWork exists")
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// getCandidatesFromAudit returns the ready-for-migration clusters from a saved audit report after
+// checking the report is fresh and re-validating each cluster's category against the live HostedCluster.
+func (m *migrateOpts) getCandidatesFromAudit(ctx context.Context) ([]hostedClusterAuditInfo, error) {
+	if err := validateAuditReport(m.auditReport, m.mgmtClusterID, m.maxAuditAge, time.Now()); err != nil {
+		return nil, err
+	}
+
+	reviewedClusters := m.auditReport.ReadyForMigration
+	if m.includePaused {
+		reviewedClusters = append(append([]hostedClusterAuditInfo{}, reviewedClusters...), m.auditReport.Paused...)
+	}
+
+	slog.Info("Re-validating clusters from audit report",
+		"file", m.fromAudit, "generatedAt", m.auditReport.GeneratedAt, "count", len(reviewedClusters))
+
+	return m.revalidateCandidates(ctx, "audit report", reviewedClusters)
+}
+
+// revalidateCandidates returns the reviewed clusters that are still in the selected environment and ready
+// for migration according to their live HostedCluster. source names where the clusters were reviewed.
+func (m *migrateOpts) revalidateCandidates(ctx context.Context, source string, reviewedClusters []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, error) {
+	auditOpts := &auditOpts{mgmtClusterID: m.mgmtClusterID, mgmtClient: m.mgmtClient, profile: m.profile, includePaused: m.includePaused, timeouts: m.timeouts}
+	pattern, err := m.listing.namespaceMatcher(m.environment)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []hostedClusterAuditInfo
+	for _, reviewed := range reviewedClusters {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("interrupted while re-validating %s: %v", source, ctx.Err())
+		}
+		if !pattern.MatchString(reviewed.Namespace) {
+			slog.Warn("Skipping cluster from "+source+": namespace is outside the selected environment",
+				"clusterID", reviewed.ClusterID, "namespace", reviewed.Namespace, "environment", m.environment)
+			m.skip(reviewed, "namespace is outside "+m.listing.scope(m.environment))
+			continue
+		}
+		hc, err := m.getHostedClusterFromMgmt(ctx, reviewed.Namespace, reviewed.ClusterName)
+		if err != nil {
+			slog.Warn("Skipping cluster from "+source+": failed to get HostedCluster",
+				"clusterID", reviewed.ClusterID, "namespace", reviewed.Namespace, "error", err)
+			m.skip(reviewed, fmt.Sprintf("failed to get HostedCluster: %v", err))
+			continue
+		}
+
+		if id := hc.Labels["api.openshift.com/id"]; id != reviewed.ClusterID {
+			slog.Warn("Skipping cluster from "+source+": cluster ID does not match live HostedCluster",
+				"clusterID", reviewed.ClusterID, "liveClusterID", id)
+			m.skip(reviewed, fmt.Sprintf("cluster ID does not match live HostedCluster %s", id))
+			continue
+		}
+
+		if category := auditOpts.categorizeCluster(hc); category != "ready-for-migration" {
+			slog.Warn("Skipping cluster from "+source+": category changed since review",
+				"clusterID", reviewed.ClusterID, "category", category)
+			m.skip(reviewed, fmt.Sprintf("category changed to %s since review", category))
+			continue
+		}
+
+		candidates = append(candidates, hostedClusterAuditInfo{
+			ClusterID:   reviewed.ClusterID,
+			ClusterName: hc.Name,
+			Namespace:   hc.Namespace,
+			CurrentSize: hc.Labels["hypershift.openshift.io/hosted-cluster-size"],
+			Category:    "ready-for-migration",
+			Labels:      hc.Labels,
+			Annotations: hc.Annotations,
+
+			OpenShiftVersion: hostedClusterVersion(hc),
+			ChannelGroup:     channelGroup(hc.Spec.Channel),
+		})
+	}
+
+	return candidates, nil
+}
+
+// loadAuditReport reads an audit report previously written with --output json.
+func loadAuditReport(path string) (*auditResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit report: %v", err)
+	}
+
+	report := &auditResults{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse audit report %s: %v", path, err)
+	}
+	if err := checkSchemaVersion(report); err != nil {
+		return nil, fmt.Errorf("audit report %s: %v", path, err)
+	}
+
+	return report, nil
+}
+
+// validateAuditReport checks that an audit report belongs to the management cluster being
+// migrated and is not older than maxAge.
+func validateAuditReport(report *auditResults, mgmtClusterID string, maxAge time.Duration, now time.Time) error {
+	if report.MgmtClusterID != mgmtClusterID {
+		return fmt.Errorf("audit report is for management cluster %s, not %s", report.MgmtClusterID, mgmtClusterID)
+	}
+
+	if report.GeneratedAt == "" {
+		return fmt.Errorf("audit report has no generated_at timestamp; re-run audit to produce a fresh report")
+	}
+
+	generatedAt, err := time.Parse(time.RFC3339, report.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("audit report has invalid generated_at timestamp '%s': %v", report.GeneratedAt, err)
+	}
+
+	if generatedAt.After(now) {
+		return fmt.Errorf("audit report generated_at %s is in the future", report.GeneratedAt)
+	}
+
+	if age := now.Sub(generatedAt); age > maxAge {
+		return fmt.Errorf("audit report is %v old, which exceeds the maximum age of %v", age.Round(time.Second), maxAge)
+	}
+
+	return nil
+}
+
+// migrateClusters migrates a list of candidate clusters by patching their ManifestWork resources, with
+// at most maxInFlight clusters in progress at a time. Candidates are started in order, so the returned
+// results cover a prefix of candidates when the run is interrupted. When the failure budget is exceeded
+// the candidates not started yet are returned as aborted.
+func (m *migrateOpts) migrateClusters(ctx context.Context, candidates []hostedClusterAuditInfo) []migrationResult {
+	results := make([]migrationResult, len(candidates))
+	slots := make(chan struct{}, max(m.maxInFlight, 1))
+	budget := newFailureBudget(m.maxFailures, m.maxFailureRate)
+	var wg sync.WaitGroup
+
+	progress := m.progress
+	if progress == nil {
+		progress = newProgressBar(m.session.progressOut(), "Migrating", len(candidates))
+		defer progress.close()
+	}
+
+	started := 0
+	for i, candidate := range candidates {
+		if ctx.Err() == nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			slog.Warn("Interrupted, not starting remaining cluster migrations",
+				"mgmtCluster", m.mgmtClusterName, "remaining", len(candidates)-i)
+			break
+		}
+		if reason := budget.exceeded(); reason != "" {
+			<-slots
+			slog.Error("Failure budget exceeded, aborting remaining cluster migrations",
+				"mgmtCluster", m.mgmtClusterName, "reason", reason, "remaining", len(candidates)-i)
+			break
+		}
+
+		slog.Info("Migrating cluster", "mgmtCluster", m.mgmtClusterName,
+			"progress", fmt.Sprintf("%d/%d", i+1, len(candidates)),
+			"cluster", candidate.ClusterName, "clusterID", candidate.ClusterID)
+
+		started++
+		wg.Add(1)
+		progress.begin(candidate.ClusterName)
+		go func(i int, candidate hostedClusterAuditInfo) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer progress.finish()
+			results[i] = m.migrateCandidate(ctx, candidate)
+			budget.record(results[i])
+		}(i, candidate)
+	}
+
+	wg.Wait()
+	if reason := budget.exceeded(); reason != "" && started < len(candidates) && ctx.Err() == nil {
+		return append(results[:started], m.abortCandidates(candidates[started:], reason)...)
+	}
+	return results[:started]
+}
+
+// migrateCandidate migrates one candidate and records the outcome in the metrics, service log and history.
+func (m *migrateOpts) migrateCandidate(ctx context.Context, candidate hostedClusterAuditInfo) migrationResult {
+	result := m.migrateWithRetries(ctx, candidate)
+	m.metrics.recordMigration(result)
+
+	serviceLogPosted := false
+	if m.serviceLog && result.Status == "success" {
+		if err := m.postServiceLog(candidate); err != nil {
+			slog.Warn("Failed to post service log", "clusterID", candidate.ClusterID, "error", err)
+		} else {
+			serviceLogPosted = true
+		}
+	}
+	if err := m.history.record(candidate, result, serviceLogPosted); err != nil {
+		slog.Warn("Failed to write run history", "error", err)
+	}
+	m.broker.migrated(m.mgmtClusterID, candidate, result)
+
+	switch result.Status {
+	case "success":
+		slog.Info("Successfully migrated cluster", "mgmtCluster", m.mgmtClusterName, "clusterID", candidate.ClusterID)
+	case pendingVerificationStatus:
+		slog.Info("Patched cluster, sync verification deferred", "mgmtCluster", m.mgmtClusterName,
+			"clusterID", candidate.ClusterID, "pendingFile", m.pendingFile)
+	case stateChanged:
+		slog.Warn("Cluster not migrated, its state changed since it was audited", "mgmtCluster", m.mgmtClusterName,
+			"clusterID", candidate.ClusterID, "reason", result.Error)
+	case conflictingAnnotation:
+		slog.Warn("Cluster not migrated, it has a conflicting annotation", "mgmtCluster", m.mgmtClusterName,
+			"clusterID", candidate.ClusterID, "reason", result.Error)
+	case "interrupted":
+		slog.Warn("Cluster migration interrupted", "mgmtCluster", m.mgmtClusterName,
+			"clusterID", candidate.ClusterID, "error", result.Error)
+	default:
+		slog.Error("Failed to migrate cluster", "mgmtCluster", m.mgmtClusterName,
+			"clusterID", candidate.ClusterID, "error", result.Error)
+		m.events.emit(streamEvent{Event: eventError, MgmtClusterID: m.mgmtClusterID, Namespace: candidate.Namespace,
+			ClusterID: candidate.ClusterID, ClusterName: candidate.ClusterName, Error: result.Error, FailureClass: result.FailureClass})
+	}
+
+	return result
+}
+
+// migrateCluster migrates a single cluster by patching its ManifestWork, or its HostedCluster with --direct,
+// and verifying sync.
+func (m *migrateOpts) migrateCluster(ctx context.Context, info hostedClusterAuditInfo) migrationResult {
+	result := migrationResult{
+		ClusterID:   info.ClusterID,
+		ClusterName: info.ClusterName,
+	}
+
+	if info.Category == unmanagedCategory {
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("refusing to migrate unmanaged HostedCluster in namespace %s: no ManifestWork exists", info.Namespace)
+		return result
+	}
+
+	reason, err := m.revalidateBeforePatch(ctx, info)
+	if err != nil && ctx.Err() != nil {
+		result.Status = "interrupted"
+		result.Error = "interrupted while re-validating the HostedCluster; nothing was patched"
+		return result
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("failed to re-validate cluster: %v", err)
+		return result
+	}
+	if reason != "" {
+		result.Status = stateChanged
+		result.Error = reason
+		return result
+	}
+
+	conflicts, err := m.checkAnnotationConflicts(ctx, info)
+	if err != nil && ctx.Err() != nil {
+		result.Status = "interrupted"
+		result.Error = "interrupted while checking for conflicting annotations; nothing was patched"
+		return result
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("failed to check for conflicting annotations: %v", err)
+		return result
+	}
+	if len(conflicts) > 0 && !m.forceOverwrite {
+		result.Status = conflictingAnnotation
+		result.Error = fmt.Sprintf("existing %s; use --force-overwrite to replace it", describeConflicts(conflicts))
+		return result
+	}
+	if len(conflicts) > 0 {
+		logOverwritten(info, conflicts)
+		result.Overwritten = conflicts
+	}
+
+	dir, err := m.saveManifests(ctx, info)
+	if err != nil && ctx.Err() != nil {
+		result.Status = "interrupted"
+		result.Error = "interrupted while saving the HostedCluster manifests; nothing was patched"
+		return result
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("failed to save HostedCluster manifests; nothing was patched: %v", err)
+		return result
+	}
+	if dir != "" {
+		slog.Info("Saved HostedCluster manifests", "clusterID", info.ClusterID, "dir", dir)
+	}
+
+	target := "ManifestWork"
+	if m.direct {
+		target = "HostedCluster"
+	}
+	var retries int
+	err = traced(ctx, spanPatch, func(ctx context.Context) error {
+		if m.direct {
+			retries, err = m.patchHostedClusterDirect(ctx, info)
+		} else {
+			retries, err = m.patchManifestWork(ctx, info.ClusterID)
+		}
+		return err
+	}, append(clusterAttributes(info), attribute.String(attrPatchTarget, target))...)
+	result.ConflictRetries = retries
+	if err != nil && ctx.Err() != nil {
+		result.Status = "interrupted"
+		result.Error = fmt.Sprintf("interrupted while patching %s; check whether the annotation was applied", target)
+		return result
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("failed to patch %s: %v", target, err)
+		return result
+	}
+
+	if m.direct {
+		slog.Warn("Patched HostedCluster directly; its ManifestWork is now out of sync", "clusterID", info.ClusterID)
+	} else {
+		slog.Info("Patched ManifestWork on service cluster", "clusterID", info.ClusterID, "strategy", m.patchStrategy)
+	}
+	m.events.emit(streamEvent{Event: eventPatchApplied, MgmtClusterID: m.mgmtClusterID, Namespace: info.Namespace,
+		ClusterID: info.ClusterID, ClusterName: info.ClusterName, Target: target})
+
+	if m.noVerify {
+		result.Status = pendingVerificationStatus
+		if err := m.pending.record(m.pendingVerification(info, target)); err != nil {
+			slog.Error("Failed to record cluster pending verification", "clusterID", info.ClusterID, "error", err)
+			result.Error = fmt.Sprintf("%s patched but not recorded for verification, verify manually: %v", target, err)
+		}
+		return result
+	}
+
+	syncStart := time.Now()
+	err = traced(ctx, spanSyncWait, func(ctx context.Context) error {
+		return m.waitForSync(ctx, info)
+	}, clusterAttributes(info)...)
+	syncWait := time.Since(syncStart)
+	result.SyncSeconds = syncWait.Seconds()
+	m.metrics.recordSyncWait(info.ClusterID, syncWait, err == nil)
+	if err != nil && ctx.Err() != nil {
+		result.Status = "interrupted"
+		result.Error = fmt.Sprintf("%s patched but sync to the management cluster was not verified", target)
+		return result
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("sync verification failed: %v", err)
+		return result
+	}
+
+	result.Status = "success"
+	result.VerifiedAt = time.Now().Format(time.RFC3339)
+	m.events.emit(streamEvent{Event: eventSyncVerified, MgmtClusterID: m.mgmtClusterID, Namespace: info.Namespace,
+		ClusterID: info.ClusterID, ClusterName: info.ClusterName, SyncSeconds: result.SyncSeconds})
+	return result
+}
+
+// waitForSync polls the management cluster until annotations sync or timeout occurs. While the annotations
+// are missing it also checks the ManifestWork status, failing fast when the work agent rejected the patch.
+func (m *migrateOpts) waitForSync(ctx context.Context, info hostedClusterAuditInfo) error {
+	timeout := m.syncTimeout
+	pollInterval := m.pollInterval
+
+	slog.Info("Waiting for sync", "clusterID", info.ClusterID, "timeout", timeout)
+
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	attempt := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled")
+		case <-ticker.C:
+			attempt++
+
+			hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+			if err != nil {
+				slog.Warn("Failed to get HostedCluster", "clusterID", info.ClusterID, "attempt", attempt, "error", err)
+
+				if time.Now().After(deadline) {
+					return withFailureClass(failureSyncTimeout, fmt.Errorf("timeout waiting for sync after %v", timeout))
+				}
+				continue
+			}
+
+			if m.hasRequiredAnnotations(hc) {
+				slog.Info("Verified annotations synced to management cluster", "clusterID", info.ClusterID)
+				return nil
+			}
+
+			slog.Info("Annotations not yet synced", "clusterID", info.ClusterID, "attempt", attempt)
+
+			workStatus, err := m.checkWorkAgent(ctx, info)
+			if err != nil {
+				return err
+			}
+
+			if time.Now().After(deadline) {
+				if workStatus != "" {
+					return withFailureClass(failureSyncTimeout, fmt.Errorf("timeout: annotations did not sync after %v; %s", timeout, workStatus))
+				}
+				return withFailureClass(failureSyncTimeout, fmt.Errorf("timeout: annotations did not sync after %v", timeout))
+			}
+		}
+	}
+}
+
+// getHostedClusterFromMgmt retrieves a HostedCluster from the management cluster.
+func (m *migrateOpts) getHostedClusterFromMgmt(ctx context.Context, namespace, name string) (*hypershiftv1beta1.HostedCluster, error) {
+	hc := &hypershiftv1beta1.HostedCluster{}
+	err := m.mgmtClient.Get(ctx,
+		types.NamespacedName{
+			Namespace: namespace,
+			Name:      name,
+		},
+		hc)
+	return hc, err
+}
+
+// hasRequiredAnnotations checks if a HostedCluster has the annotations required by its migration profile.
+func (m *migrateOpts) hasRequiredAnnotations(hc *hypershiftv1beta1.HostedCluster) bool {
+	return m.clusterProfile(hc.Labels["api.openshift.com/id"]).satisfiedBy(hc.Annotations)
+}
+
+// displayCandidates prints the list of clusters ready for migration.
+func (m *migrateOpts) displayCandidates(w io.Writer, candidates []hostedClusterAuditInfo) {
+	fmt.Fprintf(w, "\n=== Clusters Ready for Migration (%d) ===\n\n", len(candidates))
+	printRunID(w, m.session.runID())
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "NAMESPACE", "CURRENT SIZE"})
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ClusterID < candidates[j].ClusterID
+	})
+
+	for _, c := range candidates {
+		p.AddRow([]string{c.ClusterID, c.ClusterName, c.Namespace, c.CurrentSize})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+
+	profile := m.profile.orDefault()
+	fmt.Fprintf(w, "These clusters will receive the following annotations (profile %s):\n", profile.Name)
+	for _, key := range profile.ensureKeys() {
+		fmt.Fprintf(w, "  - %s: %q\n", key, profile.Ensure[key])
+	}
+	if len(profile.Remove) > 0 {
+		fmt.Fprintln(w, "and have these annotations removed:")
+		for _, key := range profile.Remove {
+			fmt.Fprintf(w, "  - %s\n", key)
+		}
+	}
+	fmt.Fprintln(w)
+	m.printOverrides(w, candidates)
+	m.printRemainingPart(w)
+}
+
+// displayResults prints a summary of the migration results, including the candidates that were skipped
+// before the run and any that were not started because the run was interrupted.
+func (m *migrateOpts) displayResults(w io.Writer, results []migrationResult, notStarted []hostedClusterAuditInfo) {
+	var migrated, failed, interrupted, changed, conflicting, pending, aborted []migrationResult
+	conflictRetries := 0
+
+	for _, r := range results {
+		conflictRetries += r.ConflictRetries
+		switch r.Status {
+		case "success":
+			migrated = append(migrated, r)
+		case "failed":
+			failed = append(failed, r)
+		case "interrupted":
+			interrupted = append(interrupted, r)
+		case stateChanged:
+			changed = append(changed, r)
+		case conflictingAnnotation:
+			conflicting = append(conflicting, r)
+		case pendingVerificationStatus:
+			pending = append(pending, r)
+		case abortedStatus:
+			aborted = append(aborted, r)
+		}
+	}
+
+	fmt.Fprintf(w, "\n\n=== Migration Summary ===\n\n")
+	printRunID(w, m.session.runID())
+	if len(interrupted) > 0 || len(notStarted) > 0 {
+		fmt.Fprintf(w, "WARNING: migration was interrupted; results are partial\n\n")
+	}
+	fmt.Fprintf(w, "Total candidates: %d\n", len(results)+len(notStarted)+len(m.skipped))
+	fmt.Fprintf(w, "Successfully migrated: %d\n", len(migrated))
+	if len(pending) > 0 {
+		fmt.Fprintf(w, "Patched, pending verification: %d\n", len(pending))
+	}
+	fmt.Fprintf(w, "Failed: %d\n", len(failed))
+	for _, c := range failureClasses {
+		if n := countFailureClass(failed, c.class); n > 0 {
+			fmt.Fprintf(w, "  %s: %d\n", c.class, n)
+		}
+	}
+	if len(m.skipped) > 0 {
+		fmt.Fprintf(w, "Skipped: %d\n", len(m.skipped))
+	}
+	if len(changed) > 0 {
+		fmt.Fprintf(w, "Skipped (state changed): %d\n", len(changed))
+	}
+	if len(conflicting) > 0 {
+		fmt.Fprintf(w, "Skipped (conflicting annotation): %d\n", len(conflicting))
+	}
+	if len(aborted) > 0 {
+		fmt.Fprintf(w, "Aborted: %d\n", len(aborted))
+	}
+	if len(interrupted) > 0 || len(notStarted) > 0 {
+		fmt.Fprintf(w, "Interrupted: %d\n", len(interrupted))
+		fmt.Fprintf(w, "Not started: %d\n", len(notStarted))
+	}
+	fmt.Fprintf(w, "ManifestWork conflict retries: %d\n", conflictRetries)
+	printSyncLatency(w, summarizeSyncLatency(results))
+	fmt.Fprintln(w)
+
+	if len(migrated) > 0 {
+		fmt.Fprintln(w, "✓ Successfully Migrated:")
+		for _, r := range migrated {
+			synced := fmt.Sprintf("synced in %s", r.syncDuration().Round(time.Second))
+			if r.ConflictRetries > 0 {
+				fmt.Fprintf(w, "  - %s (%s) %s after %d conflict retries\n", r.ClusterName, r.ClusterID, synced, r.ConflictRetries)
+				continue
+			}
+			fmt.Fprintf(w, "  - %s (%s) %s\n", r.ClusterName, r.ClusterID, synced)
+		}
+		fmt.Fprintln(w)
+		m.printRemainingPart(w)
+	}
+
+	if len(pending) > 0 {
+		fmt.Fprintf(w, "- Patched, Pending Verification (run verify --pending-file %s):\n", m.pendingFile)
+		for _, r := range pending {
+			if r.Error != "" {
+				fmt.Fprintf(w, "  - %s (%s) %s\n", r.ClusterName, r.ClusterID, r.Error)
+				continue
+			}
+			fmt.Fprintf(w, "  - %s (%s)\n", r.ClusterName, r.ClusterID)
+		}
+		fmt.Fprintln(w)
+	}
+
+	displayFailuresByClass(w, failed)
+	displayAborted(w, aborted, failed)
+	displaySkipped(w, m.skipped)
+
+	if len(changed) > 0 {
+		fmt.Fprintln(w, "- Skipped, State Changed Since Audit:")
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+		for _, r := range changed {
+			p.AddRow([]string{r.ClusterID, r.ClusterName, r.Error})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	if len(conflicting) > 0 {
+		fmt.Fprintln(w, "- Skipped, Conflicting Annotation:")
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+		for _, r := range conflicting {
+			p.AddRow([]string{r.ClusterID, r.ClusterName, r.Error})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	displayOverwritten(w, results)
+
+	if len(interrupted) > 0 {
+		fmt.Fprintln(w, "⚠ Interrupted (verify manually):")
+		p := output.NewTable(w, output.TableMinWidth)
+		p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "STATUS"})
+		for _, r := range interrupted {
+			p.AddRow([]string{r.ClusterID, r.ClusterName, r.Error})
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	if len(notStarted) > 0 {
+		fmt.Fprintln(w, "- Not Started:")
+		for _, c := range notStarted {
+			fmt.Fprintf(w, "  - %s (%s)\n", c.ClusterName, c.ClusterID)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestHasRequiredAnnotations verifies annotation validation for autoscaling readiness.
+func TestHasRequiredAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name: "has required annotations with correct values",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+			},
+			expected: true,
+		},
+		{
+			name: "has required annotations with other annotations",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+				"other.annotation":                                       "value",
+			},
+			expected: true,
+		},
+		{
+			name: "missing topology annotation",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+			expected: false,
+		},
+		{
+			name: "missing auto-scaling annotation",
+			annotations: map[string]string{
+				"other.annotation": "value",
+			},
+			expected: false,
+		},
+		{
+			name: "wrong auto-scaling value",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "false",
+			},
+			expected: false,
+		},
+		{
+			name:        "nil annotations",
+			annotations: nil,
+			expected:    false,
+		},
+		{
+			name:        "empty annotations",
+			annotations: map[string]string{},
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tt.annotations,
+				},
+			}
+
+			opts := &migrateOpts{}
+			result := opts.hasRequiredAnnotations(hc)
+
+			if result != tt.expected {
+				t.Errorf("hasRequiredAnnotations() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestValidateSyncSettings verifies bounds validation for sync timeout and poll interval.
+func TestValidateSyncSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		pollInterval time.Duration
+		expectError  bool
+	}{
+		{
+			name:         "defaults are valid",
+			timeout:      defaultSyncTimeout,
+			pollInterval: defaultPollInterval,
+			expectError:  false,
+		},
+		{
+			name:         "extended timeout is valid",
+			timeout:      20 * time.Minute,
+			pollInterval: 30 * time.Second,
+			expectError:  false,
+		},
+		{
+			name:         "timeout below minimum",
+			timeout:      10 * time.Second,
+			pollInterval: 1 * time.Second,
+			expectError:  true,
+		},
+		{
+			name:         "timeout above maximum",
+			timeout:      2 * time.Hour,
+			pollInterval: defaultPollInterval,
+			expectError:  true,
+		},
+		{
+			name:         "poll interval below minimum",
+			timeout:      defaultSyncTimeout,
+			pollInterval: 500 * time.Millisecond,
+			expectError:  true,
+		},
+		{
+			name:         "poll interval above maximum",
+			timeout:      maxSyncTimeout,
+			pollInterval: 10 * time.Minute,
+			expectError:  true,
+		},
+		{
+			name:         "poll interval exceeds timeout",
+			timeout:      1 * time.Minute,
+			pollInterval: 2 * time.Minute,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSyncSettings(tt.timeout, tt.pollInterval)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateSyncSettings() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+// TestValidateAuditReport verifies ownership and freshness checks for saved audit reports.
+func TestValidateAuditReport(t *testing.T) {
+	now := time.Date(2026, 1, 27, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		report      *auditResults
+		expectError bool
+	}{
+		{
+			name:        "fresh report for the same management cluster",
+			report:      &auditResults{MgmtClusterID: "mgmt-123", GeneratedAt: "2026-01-27T10:00:00Z"},
+			expectError: false,
+		},
+		{
+			name:        "report for a different management cluster",
+			report:      &auditResults{MgmtClusterID: "mgmt-999", GeneratedAt: "2026-01-27T10:00:00Z"},
+			expectError: true,
+		},
+		{
+			name:        "report without timestamp",
+			report:      &auditResults{MgmtClusterID: "mgmt-123"},
+			expectError: true,
+		},
+		{
+			name:        "report with invalid timestamp",
+			report:      &auditResults{MgmtClusterID: "mgmt-123", GeneratedAt: "yesterday"},
+			expectError: true,
+		},
+		{
+			name:        "stale report",
+			report:      &auditResults{MgmtClusterID: "mgmt-123", GeneratedAt: "2026-01-25T10:00:00Z"},
+			expectError: true,
+		},
+		{
+			name:        "report from the future",
+			report:      &auditResults{MgmtClusterID: "mgmt-123", GeneratedAt: "2026-01-28T10:00:00Z"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAuditReport(tt.report, "mgmt-123", defaultMaxAuditAge, now)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateAuditReport() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+// TestLoadAuditReport verifies audit reports written as JSON can be read back.
+func TestLoadAuditReport(t *testing.T) {
+	results := &auditResults{
+		MgmtClusterID: "mgmt-123",
+		GeneratedAt:   "2026-01-27T10:00:00Z",
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "cluster1", ClusterName: "one", Namespace: "ocm-production-cluster1", Category: "ready-for-migration"},
+		},
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("Failed to marshal audit results: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write audit report: %v", err)
+	}
+
+	report, err := loadAuditReport(path)
+	if err != nil {
+		t.Fatalf("loadAuditReport() error = %v", err)
+	}
+	if report.MgmtClusterID != "mgmt-123" || len(report.ReadyForMigration) != 1 ||
+		report.ReadyForMigration[0].ClusterID != "cluster1" {
+		t.Errorf("loadAuditReport() = %+v, unexpected content", report)
+	}
+
+	if _, err := loadAuditReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected error for missing audit report")
+	}
+}
+
+// TestMigrateClustersInterrupted verifies an interrupt stops new migrations and reports the in-flight cluster as unverified.
+func TestMigrateClustersInterrupted(t *testing.T) {
+	hcJSON, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata":   map[string]interface{}{"name": "cluster-1"},
+	})
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-id-1", Namespace: "test-mgmt-cluster"},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+
+	// Simulate Ctrl-C arriving right after the first ManifestWork is patched.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serviceClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(mw).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				err := c.Update(ctx, obj, opts...)
+				cancel()
+				return err
+			},
+		}).
+		Build()
+
+	m := &migrateOpts{
+		serviceClient:   serviceClient,
+		mgmtClusterName: "test-mgmt-cluster",
+		patchStrategy:   "update",
+		syncTimeout:     time.Minute,
+		pollInterval:    time.Hour,
+	}
+
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "cluster-id-1", ClusterName: "cluster-1", Namespace: "ocm-production-cluster-id-1"},
+		{ClusterID: "cluster-id-2", ClusterName: "cluster-2", Namespace: "ocm-production-cluster-id-2"},
+	}
+
+	results := m.migrateClusters(ctx, candidates)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result after interrupt, got %d", len(results))
+	}
+	if results[0].Status != "interrupted" {
+		t.Errorf("Expected in-flight cluster to be interrupted, got status %q", results[0].Status)
+	}
+	if !strings.Contains(results[0].Error, "not verified") {
+		t.Errorf("Expected error to report unverified sync, got %q", results[0].Error)
+	}
+
+	if results := m.migrateClusters(ctx, candidates); len(results) != 0 {
+		t.Errorf("Expected no migrations to start on a cancelled context, got %d", len(results))
+	}
+}
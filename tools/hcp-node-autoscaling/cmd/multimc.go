@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// mgmtClusterRun is the migration of one management cluster within a --mgmt-cluster-ids run.
+type mgmtClusterRun struct {
+	opts       *migrateOpts
+	candidates []hostedClusterAuditInfo
+	frozen     []frozenCluster
+	notReady   []skippedCluster
+	results    []migrationResult
+	err        error
+
+	// belowMinVersion are the candidates skipped by --min-version.
+	belowMinVersion []belowMinVersionCluster
+
+	// missingManifestWork are the candidates skipped because their ManifestWork is missing on the service cluster.
+	missingManifestWork []skippedCluster
+
+	// sector and region are the management cluster's placement in OSD Fleet Manager, used by --rollout-order.
+	sector string
+	region string
+}
+
+// notStarted returns the candidates that were not started because the run was interrupted.
+func (r *mgmtClusterRun) notStarted() []hostedClusterAuditInfo {
+	return r.candidates[len(r.results):]
+}
+
+// forMgmtCluster returns a copy of the migrate options for a single management cluster.
+func (m *migrateOpts) forMgmtCluster(mgmtClusterID string) *migrateOpts {
+	opts := *m
+	opts.mgmtClusterID = mgmtClusterID
+	opts.mgmtClusterIDs = nil
+	return &opts
+}
+
+// runMulti migrates several management clusters concurrently. Every management cluster is initialized and
+// audited before anything is changed, the candidates of all of them are confirmed together, and each one
+// is then migrated independently with at most maxInFlight clusters in progress. With --rollout-order the
+// management clusters are migrated in stages by sector, soaking and verifying each stage before the next.
+func (m *migrateOpts) runMulti(ctx context.Context) error {
+	mgmtClusterIDs := uniqueClusterIDs(m.mgmtClusterIDs)
+
+	runs := make([]*mgmtClusterRun, 0, len(mgmtClusterIDs))
+	defer func() {
+		for _, r := range runs {
+			if err := r.opts.metrics.push(r.opts.metricsPushgatewayURL, r.opts.mgmtClusterID); err != nil {
+				slog.Warn("Failed to push metrics", "mgmtClusterID", r.opts.mgmtClusterID, "error", err)
+			}
+			r.opts.ocmConn.Close()
+		}
+	}()
+
+	for _, id := range mgmtClusterIDs {
+		opts := m.forMgmtCluster(id)
+		if err := opts.initialize(ctx); err != nil {
+			return fmt.Errorf("initialization failed for management cluster %s: %v", id, err)
+		}
+		run := &mgmtClusterRun{opts: opts}
+		runs = append(runs, run)
+		if len(m.rollout.order) > 0 {
+			mgmtCluster, err := getFleetManagementCluster(opts.ocmConn, opts.mgmtClusterName)
+			if err != nil {
+				return fmt.Errorf("failed to get the sector of management cluster %s: %v", id, err)
+			}
+			run.sector, run.region = mgmtCluster.Sector(), mgmtCluster.Region()
+		}
+	}
+	stages, err := rolloutStages(runs, m.rollout.order)
+	if err != nil {
+		return err
+	}
+
+	forEachRun(runs, func(r *mgmtClusterRun) {
+		candidates, err := r.opts.getCandidatesForMigration(ctx)
+		if err != nil {
+			r.err = err
+			return
+		}
+		candidates, r.belowMinVersion = r.opts.skipFiltered(candidates)
+		candidates, r.frozen = r.opts.filterFrozen(ctx, candidates)
+		r.opts.skipFrozen(r.frozen)
+		if ctx.Err() != nil {
+			r.err = fmt.Errorf("interrupted while checking for maintenance and change freezes")
+			return
+		}
+		candidates, r.notReady = r.opts.filterOCMState(ctx, candidates)
+		r.opts.skipped = append(r.opts.skipped, r.notReady...)
+		if ctx.Err() != nil {
+			r.err = fmt.Errorf("interrupted while checking OCM cluster states")
+			return
+		}
+		r.candidates, r.missingManifestWork, err = r.opts.checkManifestWorks(ctx, candidates)
+		if err != nil {
+			r.err = fmt.Errorf("ManifestWork pre-flight failed: %v", err)
+			return
+		}
+		r.opts.skipped = append(r.opts.skipped, r.missingManifestWork...)
+	})
+
+	total := 0
+	for _, r := range runs {
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return withExitCode(exitInterrupted, fmt.Errorf("failed to get migration candidates: %v", r.err))
+			}
+			return fmt.Errorf("failed to get migration candidates on management cluster %s: %v", r.opts.mgmtClusterName, r.err)
+		}
+		total += len(r.candidates)
+	}
+
+	for _, r := range runs {
+		out := m.session.infoOut()
+		printMgmtClusterHeader(out, r.opts)
+		displayBelowMinVersion(out, r.belowMinVersion)
+		displayFrozen(out, r.frozen)
+		displayNotReady(out, r.notReady)
+		displayMissingManifestWorks(out, r.missingManifestWork, len(r.candidates))
+		if len(r.candidates) == 0 {
+			fmt.Fprintln(out)
+			displaySkipped(out, r.opts.skipped)
+			fmt.Fprintf(out, "No clusters found ready for migration\n\n")
+			continue
+		}
+		r.opts.displayCandidates(out, r.candidates)
+	}
+
+	if total == 0 {
+		fmt.Fprintln(m.session.infoOut(), "No clusters found ready for migration")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no clusters found ready for migration"))
+	}
+	if len(m.rollout.order) > 0 {
+		displayRolloutPlan(m.session.infoOut(), stages, m.rollout.soakPeriod)
+	}
+	fmt.Fprintf(m.session.infoOut(), "%d clusters across %d management clusters will be migrated, at most %d at a time per management cluster\n\n",
+		total, len(runs), max(m.maxInFlight, 1))
+	if err := m.checkMaxClusters(m.session.infoOut(), total); err != nil {
+		return err
+	}
+
+	if !m.skipConfirmation && !m.dryRun {
+		if !m.confirm(os.Stdin, m.session.infoOut(), total) {
+			return fmt.Errorf("migration cancelled by user")
+		}
+	}
+
+	if m.dryRun {
+		var dryRunErr error
+		for _, r := range runs {
+			if len(r.candidates) == 0 {
+				continue
+			}
+			printMgmtClusterHeader(os.Stdout, r.opts)
+			r.opts.displayDryRunDiff(ctx, os.Stdout, r.candidates)
+			r.opts.displayCapacityDelta(ctx, os.Stdout, r.candidates)
+			if r.opts.serverDryRun {
+				if err := r.opts.submitServerDryRun(ctx, os.Stdout, r.candidates); err != nil {
+					dryRunErr = errors.Join(dryRunErr, fmt.Errorf("%s: %v", r.opts.mgmtClusterName, err))
+				}
+			}
+		}
+		fmt.Println("[DRY RUN] No changes will be applied")
+		return dryRunErr
+	}
+
+	var candidates []hostedClusterAuditInfo
+	var mgmtClusterNames []string
+	for _, r := range runs {
+		candidates = append(candidates, r.candidates...)
+		mgmtClusterNames = append(mgmtClusterNames, r.opts.mgmtClusterName)
+	}
+	// All management clusters are in the same OCM environment, so any connection resolves the services.
+	window, err := m.pagerDuty.start(ctx, ocmServiceLabel(ctx, runs[0].opts.ocmConn, m.pagerDuty.serviceLabel), candidates,
+		pdMaintenanceDescription(m.ticket, mgmtClusterNames))
+	if err != nil {
+		return err
+	}
+
+	progress := newProgressBar(m.session.progressOut(), "Migrating", total)
+	defer progress.close()
+	for _, r := range runs {
+		r.opts.progress = progress
+	}
+
+	startedAt := time.Now()
+	halted := m.migrateStages(ctx, stages, func(r *mgmtClusterRun) {
+		if len(r.candidates) == 0 {
+			return
+		}
+		r.opts.history = r.opts.newRunHistory(r.opts.historyDir, startedAt)
+		r.opts.recordSkipped()
+		r.results = r.opts.migrateClusters(ctx, r.candidates)
+		if err := r.opts.history.finish(); err != nil {
+			slog.Warn("Failed to write run history", "mgmtCluster", r.opts.mgmtClusterName, "error", err)
+		} else {
+			slog.Info("Wrote run history", "mgmtCluster", r.opts.mgmtClusterName, "file", r.opts.history.path)
+		}
+	})
+	window.end(ctx)
+
+	var results []migrationResult
+	notStarted := 0
+	for _, r := range runs {
+		if len(r.candidates) == 0 && len(r.opts.skipped) == 0 {
+			continue
+		}
+		printMgmtClusterHeader(m.session.infoOut(), r.opts)
+		r.opts.displayResults(m.session.infoOut(), r.results, r.notStarted())
+		notify(ctx, r.opts.notifyWebhook, r.opts.notifyFormat, r.opts.migrationNotification(r.results, r.notStarted()))
+		results = append(results, r.results...)
+		notStarted += len(r.notStarted())
+	}
+	printFleetSummary(m.session.infoOut(), runs)
+	writeChangeRecord(m.changeRecord, runs)
+
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted,
+			fmt.Errorf("migration interrupted: %d of %d clusters not started", notStarted, total))
+	}
+	if halted != "" {
+		fmt.Fprintf(m.session.infoOut(), "Rollout halted: %s\n", halted)
+		return withExitCode(exitPartialFailure, fmt.Errorf("rollout halted, %d of %d clusters not started: %s", notStarted, total, halted))
+	}
+
+	return migrationExitError(results)
+}
+
+// forEachRun calls fn for every management cluster run concurrently and waits for all of them.
+func forEachRun(runs []*mgmtClusterRun, fn func(r *mgmtClusterRun)) {
+	var wg sync.WaitGroup
+	for _, r := range runs {
+		wg.Add(1)
+		go func(r *mgmtClusterRun) {
+			defer wg.Done()
+			fn(r)
+		}(r)
+	}
+	wg.Wait()
+}
+
+// uniqueClusterIDs returns the trimmed, non-empty cluster IDs in their original order without duplicates.
+func uniqueClusterIDs(ids []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// printMgmtClusterHeader prints the heading that groups output by management cluster.
+func printMgmtClusterHeader(w io.Writer, m *migrateOpts) {
+	fmt.Fprintf(w, "\n##### Management cluster %s (%s), service cluster %s #####\n",
+		m.mgmtClusterName, m.mgmtClusterID, m.serviceClusterID)
+}
+
+// printFleetSummary prints one row of migration counts per management cluster.
+func printFleetSummary(w io.Writer, runs []*mgmtClusterRun) {
+	fmt.Fprintf(w, "\n=== Fleet Summary ===\n\n")
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"MGMT CLUSTER", "SERVICE CLUSTER", "CANDIDATES", "MIGRATED", "FAILED", "ABORTED", "SKIPPED", "STATE CHANGED", "INTERRUPTED", "NOT STARTED"})
+	for _, r := range runs {
+		p.AddRow(fleetSummaryRow(r))
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
+
+// fleetSummaryRow returns the fleet summary row of a management cluster run.
+func fleetSummaryRow(r *mgmtClusterRun) []string {
+	counts := map[string]int{}
+	for _, result := range r.results {
+		counts[result.Status]++
+	}
+	return []string{
+		r.opts.mgmtClusterName,
+		r.opts.serviceClusterID,
+		strconv.Itoa(len(r.candidates) + len(r.opts.skipped)),
+		strconv.Itoa(counts["success"]),
+		strconv.Itoa(counts["failed"]),
+		strconv.Itoa(counts[abortedStatus]),
+		strconv.Itoa(len(r.opts.skipped)),
+		strconv.Itoa(counts[stateChanged]),
+		strconv.Itoa(counts["interrupted"]),
+		strconv.Itoa(len(r.notStarted())),
+	}
+}
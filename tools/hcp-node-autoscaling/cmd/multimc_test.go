@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestUniqueClusterIDs verifies management cluster IDs are trimmed and deduplicated in order.
+func TestUniqueClusterIDs(t *testing.T) {
+	result := uniqueClusterIDs([]string{"mc1", " mc2 ", "", "mc1", "mc3"})
+	expected := []string{"mc1", "mc2", "mc3"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("uniqueClusterIDs() = %v, want %v", result, expected)
+	}
+}
+
+// TestFleetSummaryRow verifies per-management-cluster counts, including candidates that were skipped, aborted or
+// not started.
+func TestFleetSummaryRow(t *testing.T) {
+	r := &mgmtClusterRun{
+		opts: &migrateOpts{mgmtClusterName: "mc1", serviceClusterID: "svc1",
+			skipped: []skippedCluster{{info: hostedClusterAuditInfo{ClusterID: "c7"}, reason: "paused"}}},
+		candidates: []hostedClusterAuditInfo{
+			{ClusterID: "c1"}, {ClusterID: "c2"}, {ClusterID: "c3"}, {ClusterID: "c4"}, {ClusterID: "c5"}, {ClusterID: "c6"},
+			{ClusterID: "c8"},
+		},
+		results: []migrationResult{
+			{ClusterID: "c1", Status: "success"},
+			{ClusterID: "c2", Status: "success"},
+			{ClusterID: "c3", Status: "failed"},
+			{ClusterID: "c4", Status: "interrupted"},
+			{ClusterID: "c5", Status: stateChanged},
+			{ClusterID: "c6", Status: abortedStatus},
+		},
+	}
+
+	expected := []string{"mc1", "svc1", "8", "2", "1", "1", "1", "1", "1", "1"}
+	if result := fleetSummaryRow(r); !reflect.DeepEqual(result, expected) {
+		t.Errorf("fleetSummaryRow() = %v, want %v", result, expected)
+	}
+}
+
+// TestMigrateClustersMaxInFlight verifies clusters are migrated concurrently without exceeding the in-flight limit.
+func TestMigrateClustersMaxInFlight(t *testing.T) {
+	const clusters = 6
+	const maxInFlight = 2
+
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+	if err := hypershiftv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add hypershift scheme: %v", err)
+	}
+
+	var serviceObjects, mgmtObjects []client.Object
+	var candidates []hostedClusterAuditInfo
+	for i := 0; i < clusters; i++ {
+		id := fmt.Sprintf("cluster-id-%d", i)
+		name := fmt.Sprintf("cluster-%d", i)
+		namespace := "ocm-production-" + id
+
+		hcJSON, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "hypershift.openshift.io/v1beta1",
+			"kind":       "HostedCluster",
+			"metadata":   map[string]interface{}{"name": name},
+		})
+		serviceObjects = append(serviceObjects, &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: id, Namespace: "test-mgmt-cluster"},
+			Spec: workv1.ManifestWorkSpec{
+				Workload: workv1.ManifestsTemplate{
+					Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+				},
+			},
+		})
+		mgmtObjects = append(mgmtObjects, &hypershiftv1beta1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: defaultProfile.Ensure},
+		})
+		candidates = append(candidates, hostedClusterAuditInfo{ClusterID: id, ClusterName: name, Namespace: namespace})
+	}
+
+	var mu sync.Mutex
+	inFlight, maxObserved := 0, 0
+	serviceClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(serviceObjects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				mu.Lock()
+				inFlight++
+				maxObserved = max(maxObserved, inFlight)
+				mu.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	m := &migrateOpts{
+		serviceClient:   serviceClient,
+		mgmtClient:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(mgmtObjects...).Build(),
+		mgmtClusterName: "test-mgmt-cluster",
+		patchStrategy:   "update",
+		maxInFlight:     maxInFlight,
+		syncTimeout:     time.Minute,
+		pollInterval:    10 * time.Millisecond,
+	}
+
+	results := m.migrateClusters(context.Background(), candidates)
+	if len(results) != clusters {
+		t.Fatalf("Expected %d results, got %d", clusters, len(results))
+	}
+	for i, r := range results {
+		if r.ClusterID != candidates[i].ClusterID {
+			t.Errorf("Result %d is for %s, want %s", i, r.ClusterID, candidates[i].ClusterID)
+		}
+		if r.Status != "success" {
+			t.Errorf("Expected %s to succeed, got %q: %s", r.ClusterID, r.Status, r.Error)
+		}
+	}
+
+	if maxObserved > maxInFlight {
+		t.Errorf("Observed %d concurrent migrations, limit is %d", maxObserved, maxInFlight)
+	}
+	if maxObserved < 2 {
+		t.Errorf("Expected clusters to be migrated concurrently, observed %d at a time", maxObserved)
+	}
+}
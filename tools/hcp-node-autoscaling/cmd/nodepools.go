@@ -0,0 +1,517 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type nodePoolAuditOpts struct {
+	mgmtClusterID string
+	environment   string
+	output        string
+	outputFile    string
+	appendOutput  bool
+	noHeaders     bool
+	compareOCM    bool
+	noCache       bool
+	cacheTTL      time.Duration
+	timeouts      phaseTimeouts
+	kubeconfigs   kubeconfigOverrides
+	listing       namespaceListing
+
+	ocmConn    *sdk.Connection
+	clients    clientfactory.Factory
+	mgmtClient client.Client
+	session    *runSession
+}
+
+type nodePoolAuditInfo struct {
+	ClusterID       string   `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName     string   `json:"cluster_name" yaml:"cluster_name"`
+	Namespace       string   `json:"namespace" yaml:"namespace"`
+	NodePool        string   `json:"nodepool" yaml:"nodepool"`
+	Autoscaling     bool     `json:"autoscaling" yaml:"autoscaling"`
+	Min             int32    `json:"min,omitempty" yaml:"min,omitempty"`
+	Max             int32    `json:"max,omitempty" yaml:"max,omitempty"`
+	Replicas        int32    `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	CurrentReplicas int32    `json:"current_replicas" yaml:"current_replicas"`
+	Mismatches      []string `json:"mismatches,omitempty" yaml:"mismatches,omitempty"`
+}
+
+type nodePoolAuditResults struct {
+	MgmtClusterID  string              `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	GeneratedAt    string              `json:"generated_at" yaml:"generated_at"`
+	RunID          string              `json:"run_id,omitempty" yaml:"run_id,omitempty"`
+	Environment    string              `json:"environment" yaml:"environment"`
+	TotalNodePools int                 `json:"total_nodepools" yaml:"total_nodepools"`
+	Autoscaling    int                 `json:"autoscaling" yaml:"autoscaling"`
+	Fixed          int                 `json:"fixed" yaml:"fixed"`
+	Mismatched     int                 `json:"mismatched" yaml:"mismatched"`
+	NodePools      []nodePoolAuditInfo `json:"nodepools" yaml:"nodepools"`
+	Errors         []auditError        `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Partial        bool                `json:"partial,omitempty" yaml:"partial,omitempty"`
+}
+
+// ocmNodePool is the customer's machine pool configuration in OCM for a NodePool.
+type ocmNodePool struct {
+	Autoscaling bool
+	Min         int
+	Max         int
+	Replicas    int
+}
+
+// NewAuditNodePoolsCmd creates the audit-nodepools subcommand for reporting NodePool autoscaling configuration.
+func NewAuditNodePoolsCmd() *cobra.Command {
+	opts := &nodePoolAuditOpts{}
+	cmd := &cobra.Command{
+		Use:   "audit-nodepools",
+		Short: "Audit NodePool autoscaling configuration of hosted clusters on a management cluster",
+		Long: `Inspect the NodePools of every hosted cluster on a management cluster and report which have
+spec.autoScaling configured and which use fixed replicas, with their min/max ranges.
+
+Each NodePool is compared with the customer's machine pool in OCM, and differences in autoscaling
+mode, min/max range or replica count are reported as mismatches.`,
+		Example: `
+  # Report NodePool autoscaling configuration
+  hcp-node-autoscaling audit-nodepools --mgmt-cluster-id mgmt-cluster-123
+
+  # Export to JSON without comparing against OCM
+  hcp-node-autoscaling audit-nodepools --mgmt-cluster-id mgmt-cluster-123 --output json --compare-ocm=false`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID to audit")
+	cmd.Flags().StringVar(&opts.environment, "environment", "production",
+		"OCM environment whose hosted cluster namespaces are scanned: production, staging, all")
+	cmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text, json, yaml, csv")
+	cmd.Flags().StringVar(&opts.outputFile, "output-file", "",
+		"Atomically write json, yaml or csv results to this file instead of stdout")
+	cmd.Flags().BoolVar(&opts.appendOutput, "append", false,
+		"Append results to --output-file instead of replacing it, e.g. to aggregate runs across management clusters")
+	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Skip headers in output (for text and csv formats)")
+	cmd.Flags().BoolVar(&opts.compareOCM, "compare-ocm", true,
+		"Compare each NodePool with the customer's machine pool configuration in OCM")
+	cmd.Flags().BoolVar(&opts.noCache, "no-cache", false, "Always query OCM instead of using cached cluster lookups")
+	cmd.Flags().DurationVar(&opts.cacheTTL, "cache-ttl", defaultCacheTTL, "How long cached OCM cluster lookups are used (0 disables the cache)")
+	opts.timeouts.addFlags(cmd, false)
+	opts.kubeconfigs.addFlags(cmd, false)
+	opts.listing.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run executes the audit-nodepools command.
+func (n *nodePoolAuditOpts) run(ctx context.Context) error {
+	if err := utils.IsValidClusterKey(n.mgmtClusterID); err != nil {
+		return err
+	}
+
+	if err := output.ValidateFormat(n.output, "text", "json", "yaml", "csv"); err != nil {
+		return err
+	}
+
+	if err := validateOutputFile(n.outputFile, n.appendOutput, n.output); err != nil {
+		return err
+	}
+
+	if _, err := ocmNamespacePattern(n.environment); err != nil {
+		return err
+	}
+
+	if err := n.kubeconfigs.validate(); err != nil {
+		return err
+	}
+	if err := n.listing.validate(); err != nil {
+		return err
+	}
+
+	cache, err := newOCMCache(n.noCache, n.cacheTTL)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+	n.ocmConn = connection
+
+	cluster, err := cache.getCluster(connection, n.mgmtClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %v", err)
+	}
+
+	isMC, err := cache.isManagementCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("failed to verify if cluster is a management cluster: %v", err)
+	}
+	if !isMC {
+		return fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
+	}
+	n.mgmtClusterID = cluster.ID()
+
+	slog.Info("Auditing NodePools on management cluster", "name", cluster.Name(), "id", cluster.ID())
+
+	clientScheme, err := scheme.Cached(scheme.HyperShift, scheme.Core)
+	if err != nil {
+		return err
+	}
+
+	mgmtClient, err := n.kubeconfigs.clients(n.clients, n.session.apiRateLimit(), n.mgmtClusterID, "").NewClient(n.mgmtClusterID, clientScheme)
+	if err != nil {
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	n.mgmtClient = mgmtClient
+
+	auditOpts := &auditOpts{mgmtClusterID: n.mgmtClusterID, environment: n.environment, mgmtClient: mgmtClient, timeouts: n.timeouts, listing: n.listing}
+	namespaces, err := auditOpts.listOcmNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %v", err)
+	}
+
+	slog.Info("Found OCM namespaces to audit", "environment", n.environment, "count", len(namespaces))
+
+	results := &nodePoolAuditResults{
+		MgmtClusterID: n.mgmtClusterID,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		RunID:         n.session.runID(),
+		Environment:   n.environment,
+		NodePools:     []nodePoolAuditInfo{},
+		Errors:        []auditError{},
+	}
+
+	audited := 0
+	for _, ns := range namespaces {
+		if ctx.Err() != nil {
+			break
+		}
+		var nodePools []nodePoolAuditInfo
+		err := withPhaseTimeout(ctx, n.timeouts.namespace, "auditing namespace", ns.Name, func(ctx context.Context) error {
+			var err error
+			nodePools, err = n.auditNamespace(ctx, auditOpts, ns.Name)
+			return err
+		})
+		if err != nil && ctx.Err() != nil {
+			break
+		}
+		audited++
+		if err != nil {
+			slog.Warn("Failed to audit NodePools", "namespace", ns.Name, "error", err)
+			results.Errors = append(results.Errors, auditError{Namespace: ns.Name, Error: err.Error()})
+			continue
+		}
+		results.NodePools = append(results.NodePools, nodePools...)
+	}
+
+	summarizeNodePoolResults(results)
+
+	if audited < len(namespaces) {
+		slog.Warn("Audit interrupted, reporting partial results", "audited", audited, "total", len(namespaces))
+		results.Partial = true
+	}
+
+	if err := n.outputResults(results); err != nil {
+		return err
+	}
+
+	if results.Partial {
+		return withExitCode(exitInterrupted, fmt.Errorf("audit interrupted after %d of %d namespaces", audited, len(namespaces)))
+	}
+
+	return nil
+}
+
+// auditNamespace reports the NodePools of the hosted clusters in a namespace.
+func (n *nodePoolAuditOpts) auditNamespace(ctx context.Context, a *auditOpts, namespace string) ([]nodePoolAuditInfo, error) {
+	hostedClusters, err := a.getHostedClustersInNamespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePools := &hypershiftv1beta1.NodePoolList{}
+	if err := n.mgmtClient.List(ctx, nodePools, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list NodePools: %v", err)
+	}
+
+	var infos []nodePoolAuditInfo
+	for i := range hostedClusters {
+		hcInfos, err := n.auditHostedClusterNodePools(&hostedClusters[i], nodePools.Items)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, hcInfos...)
+	}
+
+	return infos, nil
+}
+
+// auditHostedClusterNodePools reports the NodePools of a hosted cluster, compared with OCM when enabled.
+func (n *nodePoolAuditOpts) auditHostedClusterNodePools(hc *hypershiftv1beta1.HostedCluster, nodePools []hypershiftv1beta1.NodePool) ([]nodePoolAuditInfo, error) {
+	clusterID := hc.Labels["api.openshift.com/id"]
+
+	var ocmNodePools map[string]ocmNodePool
+	if n.compareOCM {
+		var err error
+		ocmNodePools, err = n.getOCMNodePools(clusterID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var infos []nodePoolAuditInfo
+	for _, np := range nodePools {
+		if np.Spec.ClusterName != hc.Name {
+			continue
+		}
+
+		info := nodePoolInfo(np, clusterID, hc.Name)
+		if n.compareOCM {
+			id := strings.TrimPrefix(np.Name, hc.Name+"-")
+			ocm, ok := ocmNodePools[id]
+			if !ok {
+				info.Mismatches = []string{"machine pool not found in OCM"}
+			} else {
+				info.Mismatches = compareNodePool(info, ocm)
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// getOCMNodePools returns the OCM machine pool configuration of a hosted cluster keyed by node pool ID.
+func (n *nodePoolAuditOpts) getOCMNodePools(clusterID string) (map[string]ocmNodePool, error) {
+	response, err := n.ocmConn.ClustersMgmt().V1().Clusters().Cluster(clusterID).NodePools().List().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OCM node pools: %v", err)
+	}
+
+	nodePools := map[string]ocmNodePool{}
+	for _, np := range response.Items().Slice() {
+		ocm := ocmNodePool{Replicas: np.Replicas()}
+		if autoscaling, ok := np.GetAutoscaling(); ok {
+			ocm.Autoscaling = true
+			ocm.Min = autoscaling.MinReplica()
+			ocm.Max = autoscaling.MaxReplica()
+		}
+		nodePools[np.ID()] = ocm
+	}
+
+	return nodePools, nil
+}
+
+// nodePoolInfo returns the autoscaling configuration of a NodePool.
+func nodePoolInfo(np hypershiftv1beta1.NodePool, clusterID, clusterName string) nodePoolAuditInfo {
+	info := nodePoolAuditInfo{
+		ClusterID:       clusterID,
+		ClusterName:     clusterName,
+		Namespace:       np.Namespace,
+		NodePool:        np.Name,
+		CurrentReplicas: np.Status.Replicas,
+	}
+
+	if np.Spec.AutoScaling != nil {
+		info.Autoscaling = true
+		info.Min = np.Spec.AutoScaling.Min
+		info.Max = np.Spec.AutoScaling.Max
+	} else if np.Spec.Replicas != nil {
+		info.Replicas = *np.Spec.Replicas
+	}
+
+	return info
+}
+
+// compareNodePool returns the differences between a NodePool and its OCM machine pool configuration.
+func compareNodePool(info nodePoolAuditInfo, ocm ocmNodePool) []string {
+	var mismatches []string
+
+	if info.Autoscaling != ocm.Autoscaling {
+		return []string{fmt.Sprintf("autoscaling: NodePool=%s, OCM=%s", nodePoolMode(info.Autoscaling), nodePoolMode(ocm.Autoscaling))}
+	}
+
+	if info.Autoscaling {
+		if int(info.Min) != ocm.Min {
+			mismatches = append(mismatches, fmt.Sprintf("min: NodePool=%d, OCM=%d", info.Min, ocm.Min))
+		}
+		if int(info.Max) != ocm.Max {
+			mismatches = append(mismatches, fmt.Sprintf("max: NodePool=%d, OCM=%d", info.Max, ocm.Max))
+		}
+		return mismatches
+	}
+
+	if int(info.Replicas) != ocm.Replicas {
+		mismatches = append(mismatches, fmt.Sprintf("replicas: NodePool=%d, OCM=%d", info.Replicas, ocm.Replicas))
+	}
+	return mismatches
+}
+
+// nodePoolMode describes whether a NodePool autoscales or has fixed replicas.
+func nodePoolMode(autoscaling bool) string {
+	if autoscaling {
+		return "autoscaling"
+	}
+	return "fixed"
+}
+
+// summarizeNodePoolResults sorts the NodePools and computes the result totals.
+func summarizeNodePoolResults(results *nodePoolAuditResults) {
+	sort.Slice(results.NodePools, func(i, j int) bool {
+		if results.NodePools[i].ClusterID != results.NodePools[j].ClusterID {
+			return results.NodePools[i].ClusterID < results.NodePools[j].ClusterID
+		}
+		return results.NodePools[i].NodePool < results.NodePools[j].NodePool
+	})
+
+	results.TotalNodePools = len(results.NodePools)
+	results.Autoscaling, results.Fixed, results.Mismatched = 0, 0, 0
+	for _, np := range results.NodePools {
+		if np.Autoscaling {
+			results.Autoscaling++
+		} else {
+			results.Fixed++
+		}
+		if len(np.Mismatches) > 0 {
+			results.Mismatched++
+		}
+	}
+}
+
+// outputResults formats and prints NodePool audit results in the specified output format.
+func (n *nodePoolAuditOpts) outputResults(results *nodePoolAuditResults) error {
+	if n.outputFile != "" {
+		err := output.WriteFile(n.outputFile, n.appendOutput, func(w io.Writer, appending bool) error {
+			return n.printStructuredOutput(w, results, appending)
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("Wrote NodePool audit results", "file", n.outputFile, "format", n.output, "append", n.appendOutput)
+		return nil
+	}
+
+	switch n.output {
+	case "json", "yaml", "csv":
+		return n.printStructuredOutput(os.Stdout, results, false)
+	default:
+		return n.printTextOutput(results)
+	}
+}
+
+// printStructuredOutput writes NodePool audit results in JSON, YAML or CSV format. When appending to an
+// existing output file, CSV headers are skipped and YAML documents are separated.
+func (n *nodePoolAuditOpts) printStructuredOutput(out io.Writer, results *nodePoolAuditResults, appending bool) error {
+	switch n.output {
+	case "yaml":
+		return output.YAML(out, results, appending)
+	case "csv":
+		return n.printCSVOutput(out, results, !n.noHeaders && !appending)
+	default:
+		return output.JSON(out, results)
+	}
+}
+
+// printTextOutput prints NodePool audit results in human-readable text format.
+func (n *nodePoolAuditOpts) printTextOutput(results *nodePoolAuditResults) error {
+	fmt.Printf("\nManagement Cluster: %s\n", results.MgmtClusterID)
+	printRunID(os.Stdout, results.RunID)
+	fmt.Printf("Total NodePools Scanned: %d\n\n", results.TotalNodePools)
+
+	if results.Partial {
+		fmt.Println("WARNING: audit was interrupted; results are partial")
+		fmt.Println()
+	}
+
+	if len(results.NodePools) > 0 {
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !n.noHeaders {
+			header := []string{"CLUSTER ID", "CLUSTER NAME", "NODEPOOL", "MODE", "MIN", "MAX", "REPLICAS", "CURRENT"}
+			if n.compareOCM {
+				header = append(header, "MISMATCH")
+			}
+			p.AddRow(header)
+		}
+		for _, np := range results.NodePools {
+			row := nodePoolRow(np)
+			if n.compareOCM {
+				row = append(row, strings.Join(np.Mismatches, "; "))
+			}
+			p.AddRow(row)
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Errors) > 0 {
+		fmt.Printf("=== Errors (%d) ===\n", len(results.Errors))
+		p := output.NewTable(os.Stdout, output.WideTableMinWidth)
+		p.AddRow([]string{"NAMESPACE", "ERROR"})
+		for _, e := range results.Errors {
+			p.AddRow([]string{e.Namespace, e.Error})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	fmt.Println("Summary:")
+	fmt.Printf("  - Autoscaling: %d NodePools\n", results.Autoscaling)
+	fmt.Printf("  - Fixed replicas: %d NodePools\n", results.Fixed)
+	if n.compareOCM {
+		fmt.Printf("  - Mismatched with OCM: %d NodePools\n", results.Mismatched)
+	}
+	fmt.Printf("  - Errors: %d namespaces\n", len(results.Errors))
+
+	return nil
+}
+
+// nodePoolRow returns the mode, range and replica columns for a NodePool, leaving columns that do
+// not apply to its mode empty.
+func nodePoolRow(np nodePoolAuditInfo) []string {
+	row := []string{np.ClusterID, np.ClusterName, np.NodePool, nodePoolMode(np.Autoscaling)}
+	if np.Autoscaling {
+		row = append(row, strconv.Itoa(int(np.Min)), strconv.Itoa(int(np.Max)), "")
+	} else {
+		row = append(row, "", "", strconv.Itoa(int(np.Replicas)))
+	}
+	return append(row, strconv.Itoa(int(np.CurrentReplicas)))
+}
+
+// printCSVOutput writes NodePool audit results in CSV format.
+func (n *nodePoolAuditOpts) printCSVOutput(out io.Writer, results *nodePoolAuditResults, headers bool) error {
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	if headers {
+		w.Write([]string{"cluster_id", "cluster_name", "nodepool", "mode", "min", "max", "replicas", "current_replicas", "mismatches"})
+	}
+
+	for _, np := range results.NodePools {
+		w.Write(append(nodePoolRow(np), strings.Join(np.Mismatches, ";")))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestNodePoolInfo verifies autoscaling and fixed replica NodePools are reported correctly.
+func TestNodePoolInfo(t *testing.T) {
+	replicas := int32(3)
+
+	autoscaling := hypershiftv1beta1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-workers", Namespace: "ocm-production-abc"},
+		Spec: hypershiftv1beta1.NodePoolSpec{
+			ClusterName: "test-cluster",
+			AutoScaling: &hypershiftv1beta1.NodePoolAutoScaling{Min: 2, Max: 6},
+		},
+		Status: hypershiftv1beta1.NodePoolStatus{Replicas: 4},
+	}
+	info := nodePoolInfo(autoscaling, "abc", "test-cluster")
+	if !info.Autoscaling || info.Min != 2 || info.Max != 6 || info.CurrentReplicas != 4 {
+		t.Errorf("Unexpected autoscaling NodePool info: %+v", info)
+	}
+
+	fixed := hypershiftv1beta1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-infra", Namespace: "ocm-production-abc"},
+		Spec: hypershiftv1beta1.NodePoolSpec{
+			ClusterName: "test-cluster",
+			Replicas:    &replicas,
+		},
+		Status: hypershiftv1beta1.NodePoolStatus{Replicas: 3},
+	}
+	info = nodePoolInfo(fixed, "abc", "test-cluster")
+	if info.Autoscaling || info.Replicas != 3 || info.Min != 0 || info.Max != 0 {
+		t.Errorf("Unexpected fixed NodePool info: %+v", info)
+	}
+}
+
+// TestCompareNodePool verifies mismatches between NodePools and OCM machine pools are detected.
+func TestCompareNodePool(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     nodePoolAuditInfo
+		ocm      ocmNodePool
+		expected []string
+	}{
+		{
+			name: "autoscaling in sync",
+			info: nodePoolAuditInfo{Autoscaling: true, Min: 2, Max: 6},
+			ocm:  ocmNodePool{Autoscaling: true, Min: 2, Max: 6},
+		},
+		{
+			name: "fixed in sync",
+			info: nodePoolAuditInfo{Replicas: 3},
+			ocm:  ocmNodePool{Replicas: 3},
+		},
+		{
+			name:     "mode differs",
+			info:     nodePoolAuditInfo{Autoscaling: true, Min: 2, Max: 6},
+			ocm:      ocmNodePool{Replicas: 3},
+			expected: []string{"autoscaling: NodePool=autoscaling, OCM=fixed"},
+		},
+		{
+			name:     "range differs",
+			info:     nodePoolAuditInfo{Autoscaling: true, Min: 1, Max: 10},
+			ocm:      ocmNodePool{Autoscaling: true, Min: 2, Max: 6},
+			expected: []string{"min: NodePool=1, OCM=2", "max: NodePool=10, OCM=6"},
+		},
+		{
+			name:     "replicas differ",
+			info:     nodePoolAuditInfo{Replicas: 2},
+			ocm:      ocmNodePool{Replicas: 3},
+			expected: []string{"replicas: NodePool=2, OCM=3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compareNodePool(tt.info, tt.ocm)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d mismatches, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Mismatch %d = %q, want %q", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSummarizeNodePoolResults verifies NodePool totals and ordering.
+func TestSummarizeNodePoolResults(t *testing.T) {
+	results := &nodePoolAuditResults{
+		NodePools: []nodePoolAuditInfo{
+			{ClusterID: "b", NodePool: "b-workers", Autoscaling: true},
+			{ClusterID: "a", NodePool: "a-workers", Mismatches: []string{"replicas: NodePool=2, OCM=3"}},
+			{ClusterID: "a", NodePool: "a-infra", Autoscaling: true},
+		},
+	}
+
+	summarizeNodePoolResults(results)
+
+	if results.TotalNodePools != 3 || results.Autoscaling != 2 || results.Fixed != 1 || results.Mismatched != 1 {
+		t.Errorf("Unexpected totals: %+v", results)
+	}
+	if results.NodePools[0].NodePool != "a-infra" || results.NodePools[2].NodePool != "b-workers" {
+		t.Errorf("Unexpected NodePool order: %+v", results.NodePools)
+	}
+}
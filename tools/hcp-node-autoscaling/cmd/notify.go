@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	notifyTimeout = 10 * time.Second
+
+	// maxSlackFailures is the number of failures listed in a Slack message; the JSON payload lists all of them.
+	maxSlackFailures = 20
+)
+
+// runNotification is the summary of an audit or migrate run posted to --notify-webhook. It is the
+// payload of the json format and is rendered as text for the slack format.
+type runNotification struct {
+	Command         string                `json:"command"`
+	RunID           string                `json:"run_id,omitempty"`
+	MgmtClusterID   string                `json:"mgmt_cluster_id"`
+	MgmtClusterName string                `json:"mgmt_cluster_name,omitempty"`
+	Partial         bool                  `json:"partial,omitempty"`
+	Counts          []categoryCount       `json:"counts"`
+	Failures        []notificationFailure `json:"failures,omitempty"`
+	Report          string                `json:"report,omitempty"`
+}
+
+// notificationFailure is a namespace that failed to audit or a cluster that failed to migrate.
+type notificationFailure struct {
+	ClusterID   string `json:"cluster_id,omitempty"`
+	ClusterName string `json:"cluster_name,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Error       string `json:"error"`
+}
+
+// validateNotifyFlags checks the --notify-webhook URL and --notify-format. The URL is left out of errors
+// because Slack webhook URLs are secrets.
+func validateNotifyFlags(webhookURL, format string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid --notify-webhook URL: must be an http or https URL")
+	}
+	if format != "slack" && format != "json" {
+		return fmt.Errorf("invalid notify format '%s'. Valid options: slack, json", format)
+	}
+	return nil
+}
+
+// auditNotification summarizes audit results, listing the namespaces that failed to audit.
+func (a *auditOpts) auditNotification(results *auditResults) *runNotification {
+	n := &runNotification{
+		Command:         "audit",
+		RunID:           results.RunID,
+		MgmtClusterID:   results.MgmtClusterID,
+		MgmtClusterName: a.mgmtClusterName,
+		Partial:         results.Partial,
+		Counts:          a.reportCategoryCounts(results),
+		Report:          absPath(a.outputFile),
+	}
+	for _, e := range results.Errors {
+		n.Failures = append(n.Failures, notificationFailure{Namespace: e.Namespace, Error: e.Error})
+	}
+	return n
+}
+
+// migrationNotification summarizes migration results, listing the failed and interrupted clusters.
+func (m *migrateOpts) migrationNotification(results []migrationResult, notStarted []hostedClusterAuditInfo) *runNotification {
+	counts := map[string]int{}
+	n := &runNotification{
+		Command:         "migrate",
+		RunID:           m.session.runID(),
+		MgmtClusterID:   m.mgmtClusterID,
+		MgmtClusterName: m.mgmtClusterName,
+		Partial:         len(notStarted) > 0,
+	}
+	for _, r := range results {
+		counts[r.Status]++
+		if r.Status == "success" || r.Status == stateChanged || r.Status == conflictingAnnotation || r.Status == pendingVerificationStatus ||
+			r.Status == abortedStatus {
+			continue
+		}
+		n.Partial = n.Partial || r.Status == "interrupted"
+		n.Failures = append(n.Failures, notificationFailure{ClusterID: r.ClusterID, ClusterName: r.ClusterName, Error: r.Error})
+	}
+
+	n.Counts = []categoryCount{
+		{"Candidates", len(results) + len(notStarted) + len(m.skipped)},
+		{"Migrated", counts["success"]},
+		{"Failed", counts["failed"]},
+	}
+	if len(m.skipped) > 0 {
+		n.Counts = append(n.Counts, categoryCount{"Skipped", len(m.skipped)})
+	}
+	if counts[stateChanged] > 0 {
+		n.Counts = append(n.Counts, categoryCount{"State changed", counts[stateChanged]})
+	}
+	if counts[conflictingAnnotation] > 0 {
+		n.Counts = append(n.Counts, categoryCount{"Conflicting annotation", counts[conflictingAnnotation]})
+	}
+	if counts[pendingVerificationStatus] > 0 {
+		n.Counts = append(n.Counts, categoryCount{"Pending verification", counts[pendingVerificationStatus]})
+	}
+	if counts[abortedStatus] > 0 {
+		n.Counts = append(n.Counts, categoryCount{"Aborted", counts[abortedStatus]})
+	}
+	if n.Partial {
+		n.Counts = append(n.Counts, categoryCount{"Interrupted", counts["interrupted"]}, categoryCount{"Not started", len(notStarted)})
+	}
+	if m.history != nil {
+		n.Report = absPath(m.history.path)
+	}
+	return n
+}
+
+// absPath returns path made absolute, so the report location in a notification is usable from anywhere.
+func absPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackText renders the notification as Slack mrkdwn.
+func (n *runNotification) slackText() string {
+	var b strings.Builder
+
+	mgmtCluster := n.MgmtClusterID
+	if n.MgmtClusterName != "" {
+		mgmtCluster = fmt.Sprintf("%s (%s)", n.MgmtClusterName, n.MgmtClusterID)
+	}
+	fmt.Fprintf(&b, "*hcp-node-autoscaling %s* finished on management cluster %s\n", n.Command, slackEscape(mgmtCluster))
+	if n.Partial {
+		b.WriteString(":warning: The run was interrupted; results are partial\n")
+	}
+
+	for _, c := range n.Counts {
+		fmt.Fprintf(&b, "• %s: %d\n", slackEscape(c.Category), c.Count)
+	}
+
+	if len(n.Failures) > 0 {
+		fmt.Fprintf(&b, "*Failures (%d)*\n", len(n.Failures))
+		for i, f := range n.Failures {
+			if i == maxSlackFailures {
+				fmt.Fprintf(&b, "• … and %d more\n", len(n.Failures)-maxSlackFailures)
+				break
+			}
+			target := f.Namespace
+			if f.ClusterID != "" {
+				target = fmt.Sprintf("%s %s", f.ClusterID, f.ClusterName)
+			}
+			fmt.Fprintf(&b, "• `%s`: %s\n", slackEscape(strings.TrimSpace(target)), slackEscape(f.Error))
+		}
+	}
+
+	if n.Report != "" {
+		fmt.Fprintf(&b, "Report: `%s`\n", slackEscape(n.Report))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// sendNotification posts the notification to webhookURL in the given format. The notification is sent
+// even when ctx has been cancelled, so that interrupted runs are still reported.
+func sendNotification(ctx context.Context, webhookURL, format string, n *runNotification) error {
+	var payload interface{} = n
+	if format == "slack" {
+		payload = map[string]string{"text": n.slackText()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// url.Error includes the webhook URL, which must not be logged.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// notify sends n to webhookURL if it is set. A failed notification is logged and does not change the
+// result of the run.
+func notify(ctx context.Context, webhookURL, format string, n *runNotification) {
+	if webhookURL == "" {
+		return
+	}
+	if err := sendNotification(ctx, webhookURL, format, n); err != nil {
+		slog.Warn("Failed to send notification", "error", err)
+		return
+	}
+	slog.Info("Sent run notification", "command", n.Command, "mgmtClusterID", n.MgmtClusterID)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestValidateNotifyFlags verifies webhook URL and format validation, and that the URL is not echoed in errors.
+func TestValidateNotifyFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		format      string
+		expectError bool
+	}{
+		{name: "unset", format: "bogus"},
+		{name: "slack", url: "https://hooks.slack.com/services/T0/B0/secret", format: "slack"},
+		{name: "json", url: "http://automation.example.com/hooks/autoscaling", format: "json"},
+		{name: "not a URL", url: "hooks.slack.com/services/T0/B0/secret", format: "slack", expectError: true},
+		{name: "invalid format", url: "https://hooks.slack.com/services/T0/B0/secret", format: "teams", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNotifyFlags(tt.url, tt.format)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateNotifyFlags() error = %v, expectError %v", err, tt.expectError)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret") {
+				t.Errorf("Expected webhook URL to be left out of the error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestMigrationNotification verifies migration results are counted and failures and interruptions are listed.
+func TestMigrationNotification(t *testing.T) {
+	m := &migrateOpts{mgmtClusterID: "mc-id", mgmtClusterName: "mc-name", history: &runHistory{path: "/tmp/history/run.json"}}
+	results := []migrationResult{
+		{ClusterID: "a1", ClusterName: "alpha", Status: "success"},
+		{ClusterID: "b2", ClusterName: "beta", Status: "failed", Error: "sync verification failed"},
+		{ClusterID: "c3", ClusterName: "gamma", Status: "interrupted", Error: "patch applied, sync not verified"},
+	}
+
+	n := m.migrationNotification(results, []hostedClusterAuditInfo{{ClusterID: "d4"}})
+	if !n.Partial {
+		t.Error("Expected notification to be partial")
+	}
+	expected := []categoryCount{{"Candidates", 4}, {"Migrated", 1}, {"Failed", 1}, {"Interrupted", 1}, {"Not started", 1}}
+	if fmt.Sprint(n.Counts) != fmt.Sprint(expected) {
+		t.Errorf("Counts = %v, want %v", n.Counts, expected)
+	}
+	if len(n.Failures) != 2 || n.Failures[0].ClusterID != "b2" || n.Failures[1].ClusterID != "c3" {
+		t.Errorf("Unexpected failures: %+v", n.Failures)
+	}
+	if n.Report != "/tmp/history/run.json" {
+		t.Errorf("Report = %s, want the run history path", n.Report)
+	}
+
+	complete := m.migrationNotification(results[:2], nil)
+	if complete.Partial || len(complete.Counts) != 3 {
+		t.Errorf("Expected a complete run without interruption counts, got %+v", complete)
+	}
+
+	m.skip(hostedClusterAuditInfo{ClusterID: "e5"}, "being deleted")
+	skipped := m.migrationNotification(results[:2], nil)
+	expected = []categoryCount{{"Candidates", 3}, {"Migrated", 1}, {"Failed", 1}, {"Skipped", 1}}
+	if fmt.Sprint(skipped.Counts) != fmt.Sprint(expected) {
+		t.Errorf("Counts = %v, want %v", skipped.Counts, expected)
+	}
+}
+
+// TestSlackText verifies the Slack message escapes values and truncates long failure lists.
+func TestSlackText(t *testing.T) {
+	n := &runNotification{
+		Command:         "audit",
+		MgmtClusterID:   "mc-id",
+		MgmtClusterName: "mc-name",
+		Counts:          []categoryCount{{"Group A (Needs annotation removal)", 2}},
+		Report:          "/reports/audit.json",
+	}
+	for i := 0; i < maxSlackFailures+3; i++ {
+		n.Failures = append(n.Failures, notificationFailure{Namespace: fmt.Sprintf("ocm-production-%d", i), Error: "<forbidden>"})
+	}
+
+	text := n.slackText()
+	for _, e := range []string{
+		"*hcp-node-autoscaling audit* finished on management cluster mc-name (mc-id)",
+		"• Group A (Needs annotation removal): 2",
+		"*Failures (23)*",
+		"• `ocm-production-0`: &lt;forbidden&gt;",
+		"• … and 3 more",
+		"Report: `/reports/audit.json`",
+	} {
+		if !strings.Contains(text, e) {
+			t.Errorf("Expected Slack text to contain %q, got:\n%s", e, text)
+		}
+	}
+	if strings.Contains(text, "ocm-production-20`") {
+		t.Errorf("Expected failures beyond %d to be truncated, got:\n%s", maxSlackFailures, text)
+	}
+}
+
+// TestSendNotification verifies the payload of each format and that webhook errors are returned.
+func TestSendNotification(t *testing.T) {
+	n := &runNotification{Command: "migrate", MgmtClusterID: "mc-id", Counts: []categoryCount{{"Migrated", 3}}}
+
+	tests := []struct {
+		format      string
+		status      int
+		expectError bool
+		expected    func(t *testing.T, payload map[string]interface{})
+	}{
+		{
+			format: "slack",
+			status: http.StatusOK,
+			expected: func(t *testing.T, payload map[string]interface{}) {
+				if text, _ := payload["text"].(string); !strings.Contains(text, "• Migrated: 3") {
+					t.Errorf("Unexpected Slack payload: %v", payload)
+				}
+			},
+		},
+		{
+			format: "json",
+			status: http.StatusNoContent,
+			expected: func(t *testing.T, payload map[string]interface{}) {
+				if payload["command"] != "migrate" || payload["mgmt_cluster_id"] != "mc-id" {
+					t.Errorf("Unexpected JSON payload: %v", payload)
+				}
+			},
+		},
+		{format: "json", status: http.StatusBadRequest, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.format, tt.status), func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %s, want application/json", ct)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &payload); err != nil {
+					t.Errorf("Failed to decode payload: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			// A cancelled context must not prevent the notification of an interrupted run.
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := sendNotification(ctx, server.URL, tt.format, n)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.expected(t, payload)
+		})
+	}
+}
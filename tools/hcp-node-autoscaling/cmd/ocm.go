@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// enrichFromOCM adds the cluster state and the subscription status, organization and support level
+// from OCM to info. Organization names are cached for the duration of the audit.
+func (a *auditOpts) enrichFromOCM(info *hostedClusterAuditInfo) error {
+	defer a.session.timings().track(phaseOCMCalls)()
+
+	clusterResponse, err := a.ocmConn.ClustersMgmt().V1().Clusters().Cluster(info.ClusterID).Get().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get OCM cluster: %v", err)
+	}
+	cluster := clusterResponse.Body()
+
+	subscriptionID := cluster.Subscription().ID()
+	if subscriptionID == "" {
+		setOCMFields(info, cluster, nil, "")
+		return nil
+	}
+
+	subscriptionResponse, err := a.ocmConn.AccountsMgmt().V1().Subscriptions().Subscription(subscriptionID).Get().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get OCM subscription: %v", err)
+	}
+	subscription := subscriptionResponse.Body()
+
+	organization, err := a.organizationName(subscription.OrganizationID())
+	if err != nil {
+		return err
+	}
+
+	setOCMFields(info, cluster, subscription, organization)
+	return nil
+}
+
+// organizationName returns the name of an OCM organization, looking it up once per audit.
+func (a *auditOpts) organizationName(organizationID string) (string, error) {
+	if organizationID == "" {
+		return "", nil
+	}
+	if name, ok := a.organizationNames[organizationID]; ok {
+		return name, nil
+	}
+
+	response, err := a.ocmConn.AccountsMgmt().V1().Organizations().Organization(organizationID).Get().Send()
+	if err != nil {
+		return "", fmt.Errorf("failed to get OCM organization: %v", err)
+	}
+
+	if a.organizationNames == nil {
+		a.organizationNames = map[string]string{}
+	}
+	a.organizationNames[organizationID] = response.Body().Name()
+	return a.organizationNames[organizationID], nil
+}
+
+// setOCMFields copies the OCM cluster and subscription details onto info. The subscription may be nil.
+func setOCMFields(info *hostedClusterAuditInfo, cluster *cmv1.Cluster, subscription *amv1.Subscription, organization string) {
+	info.OCMState = string(cluster.State())
+	if subscription == nil {
+		return
+	}
+	info.SubscriptionStatus = subscription.Status()
+	info.OrganizationID = subscription.OrganizationID()
+	info.OrganizationName = organization
+	info.SupportLevel = subscription.SupportLevel()
+}
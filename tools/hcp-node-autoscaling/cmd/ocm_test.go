@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// TestSetOCMFields verifies OCM cluster and subscription details are copied onto the audit info.
+func TestSetOCMFields(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("cluster-1").State(cmv1.ClusterStateReady).Build()
+	if err != nil {
+		t.Fatalf("Failed to build cluster: %v", err)
+	}
+	subscription, err := amv1.NewSubscription().
+		Status("Active").
+		OrganizationID("org-1").
+		SupportLevel("Premium").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build subscription: %v", err)
+	}
+
+	info := &hostedClusterAuditInfo{ClusterID: "cluster-1"}
+	setOCMFields(info, cluster, subscription, "Example Corp")
+
+	if info.OCMState != "ready" {
+		t.Errorf("OCMState = %s, want ready", info.OCMState)
+	}
+	if info.SubscriptionStatus != "Active" || info.OrganizationID != "org-1" ||
+		info.OrganizationName != "Example Corp" || info.SupportLevel != "Premium" {
+		t.Errorf("Unexpected subscription fields: %+v", info)
+	}
+
+	withoutSubscription := &hostedClusterAuditInfo{ClusterID: "cluster-1"}
+	setOCMFields(withoutSubscription, cluster, nil, "")
+	if withoutSubscription.OCMState != "ready" || withoutSubscription.SubscriptionStatus != "" {
+		t.Errorf("Unexpected fields without subscription: %+v", withoutSubscription)
+	}
+}
+
+// TestOrganizationNameCached verifies organization names already looked up are not requested again.
+func TestOrganizationNameCached(t *testing.T) {
+	a := &auditOpts{organizationNames: map[string]string{"org-1": "Example Corp"}}
+
+	name, err := a.organizationName("org-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "Example Corp" {
+		t.Errorf("organizationName() = %s, want Example Corp", name)
+	}
+
+	if name, err := a.organizationName(""); err != nil || name != "" {
+		t.Errorf("Expected empty name for empty organization ID, got %q, %v", name, err)
+	}
+}
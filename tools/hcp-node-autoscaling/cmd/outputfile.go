@@ -0,0 +1,18 @@
+package cmd
+
+import "fmt"
+
+// validateOutputFile checks the --output-file and --append flags against the selected output format.
+// The terminal formats cannot be written to a file, and HTML reports cannot be appended to.
+func validateOutputFile(outputFile string, appendOutput bool, output string) error {
+	if appendOutput && outputFile == "" {
+		return fmt.Errorf("--append requires --output-file")
+	}
+	if outputFile != "" && (output == "text" || output == "wide" || output == "summary" || output == "jsonl") {
+		return fmt.Errorf("--output-file is not supported with --output %s", output)
+	}
+	if appendOutput && output == "html" {
+		return fmt.Errorf("--append is not supported with --output html")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateOutputFile verifies --output-file rejects terminal formats and --append requires --output-file
+// and an appendable format.
+func TestValidateOutputFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputFile   string
+		appendOutput bool
+		output       string
+		expectError  bool
+	}{
+		{name: "stdout", output: "text"},
+		{name: "json file", outputFile: "audit.json", output: "json"},
+		{name: "csv file appended", outputFile: "audit.csv", appendOutput: true, output: "csv"},
+		{name: "text file", outputFile: "audit.txt", output: "text", expectError: true},
+		{name: "summary file", outputFile: "audit.txt", output: "summary", expectError: true},
+		{name: "append without file", appendOutput: true, output: "json", expectError: true},
+		{name: "html file", outputFile: "audit.html", output: "html"},
+		{name: "html file appended", outputFile: "audit.html", appendOutput: true, output: "html", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutputFile(tt.outputFile, tt.appendOutput, tt.output)
+			if tt.expectError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestAuditOutputFileAppend verifies appended audit reports skip the CSV header and separate YAML documents.
+func TestAuditOutputFileAppend(t *testing.T) {
+	results := []*auditResults{
+		{MgmtClusterID: "mc1", ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "a1", Category: "ready-for-migration"}}},
+		{MgmtClusterID: "mc2", NeedsLabelRemoval: []hostedClusterAuditInfo{{ClusterID: "b2", Category: "needs-removal"}}},
+	}
+
+	tests := []struct {
+		output   string
+		expected func(t *testing.T, content string)
+	}{
+		{
+			output: "csv",
+			expected: func(t *testing.T, content string) {
+				lines := strings.Split(strings.TrimSpace(content), "\n")
+				if len(lines) != 3 || !strings.HasPrefix(lines[0], "cluster_id,") ||
+					!strings.HasPrefix(lines[1], "a1,") || !strings.HasPrefix(lines[2], "b2,") {
+					t.Errorf("Expected one header and two rows, got:\n%s", content)
+				}
+			},
+		},
+		{
+			output: "yaml",
+			expected: func(t *testing.T, content string) {
+				if strings.Count(content, "\n---\n") != 1 || strings.Count(content, "mgmt_cluster_id:") != 2 {
+					t.Errorf("Expected two YAML documents, got:\n%s", content)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit."+tt.output)
+			a := &auditOpts{output: tt.output, outputFile: path, appendOutput: true}
+			for _, r := range results {
+				if err := a.outputResults(r); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read output file: %v", err)
+			}
+			tt.expected(t, string(data))
+		})
+	}
+}
+
+// assertFileContent fails the test if the file at path does not contain exactly expected.
+func assertFileContent(t *testing.T, path, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if string(data) != expected {
+		t.Errorf("File content = %q, want %q", data, expected)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// overridableAnnotations lists the annotations a --candidates-file row may give its own value, with the
+// values each may take. Any other annotation, or value, is rejected when the file is loaded.
+var overridableAnnotations = map[string][]string{
+	topologyAnnotation: {dedicatedTopology, "dedicated", "shared"},
+}
+
+// isAnnotationColumn reports whether a --candidates-file CSV column names an annotation, e.g.
+// hypershift.openshift.io/topology, rather than a cluster field.
+func isAnnotationColumn(name string) bool {
+	return strings.Contains(name, "/")
+}
+
+// validateAnnotationOverrides checks the annotation values of a --candidates-file row against the allowlist.
+func validateAnnotationOverrides(overrides map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		allowed, ok := overridableAnnotations[key]
+		if !ok {
+			return fmt.Errorf("annotation %s cannot be overridden; overridable annotations: %s",
+				key, strings.Join(slices.Sorted(maps.Keys(overridableAnnotations)), ", "))
+		}
+		if !slices.Contains(allowed, overrides[key]) {
+			return fmt.Errorf("invalid %s %q: must be one of %s", key, overrides[key], strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// overridePartError returns why a row's annotation values cannot be set by the part of the migration --set
+// selects, e.g. a topology value with --set autoscaling, or "" when they can.
+func (m *migrateOpts) overridePartError(overrides map[string]string) string {
+	if m.set == "" || m.set == setBoth {
+		return ""
+	}
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		part := setAutoscaling
+		if key == topologyAnnotation {
+			part = setTopology
+		}
+		if part != m.set {
+			return fmt.Sprintf("overrides %s, which --set %s does not set", key, m.set)
+		}
+	}
+	return ""
+}
+
+// withOverrides returns a copy of the profile ensuring the overridden annotation values instead of its own.
+// The allMatch values of its rules are overridden too, so a cluster with the row's values is categorized as
+// already configured, e.g. one on the shared topology when its row overrides the dedicated topology with it.
+func (p *migrationProfile) withOverrides(overrides map[string]string) *migrationProfile {
+	p = p.orDefault()
+	if len(overrides) == 0 {
+		return p
+	}
+	profile := *p
+	profile.Ensure = maps.Clone(p.Ensure)
+	maps.Copy(profile.Ensure, overrides)
+	profile.Rules = slices.Clone(p.Rules)
+	for i, rule := range profile.Rules {
+		if len(rule.AllMatch) == 0 {
+			continue
+		}
+		profile.Rules[i].AllMatch = maps.Clone(rule.AllMatch)
+		for key, value := range overrides {
+			if _, ok := rule.AllMatch[key]; ok {
+				profile.Rules[i].AllMatch[key] = value
+			}
+		}
+	}
+	return &profile
+}
+
+// clusterProfile returns the profile a cluster is migrated, checked and verified with: the migration profile
+// with the cluster's --candidates-file annotation values, if any.
+func (m *migrateOpts) clusterProfile(clusterID string) *migrationProfile {
+	if len(m.overrides[clusterID]) == 0 {
+		return m.profile
+	}
+	return m.profile.withOverrides(m.overrides[clusterID])
+}
+
+// printOverrides lists the candidates whose --candidates-file row overrides annotation values.
+func (m *migrateOpts) printOverrides(w io.Writer, candidates []hostedClusterAuditInfo) {
+	var lines []string
+	for _, c := range candidates {
+		overrides := m.overrides[c.ClusterID]
+		if len(overrides) == 0 {
+			continue
+		}
+		var values []string
+		for _, key := range slices.Sorted(maps.Keys(overrides)) {
+			values = append(values, fmt.Sprintf("%s: %q", key, overrides[key]))
+		}
+		lines = append(lines, fmt.Sprintf("  - %s (%s): %s", c.ClusterName, c.ClusterID, strings.Join(values, ", ")))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "These clusters receive their own values from %s instead:\n", m.candidatesFile)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetCandidatesFromFileOverrides verifies rows with their own annotation values are categorized and
+// patched with them, while the other candidates keep the profile's values.
+func TestGetCandidatesFromFileOverrides(t *testing.T) {
+	scheme := testScheme(t)
+	shared := newTestHostedCluster("a1", nil)
+	standard := newTestHostedCluster("b2", nil)
+	alreadyShared := newTestHostedCluster("c3", map[string]string{autoscalingAnnotation: "true", topologyAnnotation: "shared"})
+	overrides := map[string]string{topologyAnnotation: "shared"}
+
+	m := &migrateOpts{
+		mgmtClusterID:   "mgmt-1",
+		mgmtClusterName: "mgmt-cluster",
+		mgmtClient:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(shared, standard, alreadyShared).Build(),
+		serviceClient: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newTestManifestWork(t, "mgmt-cluster", shared), newTestManifestWork(t, "mgmt-cluster", standard)).Build(),
+		environment:    "production",
+		profile:        defaultProfile,
+		candidatesFile: "clusters.csv",
+		candidateRows: []candidateRow{
+			{Line: 2, ClusterID: "a1", Annotations: overrides},
+			{Line: 3, ClusterID: "b2"},
+			{Line: 4, ClusterID: "c3", Annotations: overrides},
+		},
+	}
+
+	candidates, rejected, err := m.getCandidatesFromFile(context.Background())
+	if err != nil {
+		t.Fatalf("getCandidatesFromFile() error = %v", err)
+	}
+	if len(candidates) != 2 || len(rejected) != 1 || rejected[0].row.ClusterID != "c3" ||
+		!strings.Contains(rejected[0].reason, "category is already-configured") {
+		t.Fatalf("Expected a1 and b2 to be candidates and c3 already configured, got %+v and %+v", candidates, rejected)
+	}
+
+	for id, expected := range map[string]string{"a1": "shared", "b2": dedicatedTopology} {
+		if _, err := m.patchManifestWork(context.Background(), id); err != nil {
+			t.Fatalf("patchManifestWork(%s) error = %v", id, err)
+		}
+		mw := &workv1.ManifestWork{}
+		if err := m.serviceClient.Get(context.Background(), client.ObjectKey{Namespace: "mgmt-cluster", Name: id}, mw); err != nil {
+			t.Fatalf("Failed to get ManifestWork %s: %v", id, err)
+		}
+		_, manifestData, err := findHostedClusterManifest(mw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := manifestAnnotations(manifestData)[topologyAnnotation]; got != expected {
+			t.Errorf("Cluster %s topology = %q, want %q", id, got, expected)
+		}
+	}
+	migrated := newTestHostedCluster("a1", map[string]string{autoscalingAnnotation: "true", topologyAnnotation: "shared"})
+	if !m.hasRequiredAnnotations(migrated) {
+		t.Error("Expected a1 with its row's topology to be verified as migrated")
+	}
+
+	var buf bytes.Buffer
+	m.printOverrides(&buf, candidates)
+	expected := "These clusters receive their own values from clusters.csv instead:\n" +
+		"  - cluster-a1 (a1): hypershift.openshift.io/topology: \"shared\"\n\n"
+	if buf.String() != expected {
+		t.Errorf("printOverrides() = %q, want %q", buf.String(), expected)
+	}
+}
+
+// TestWithOverridesCategorize verifies a cluster with its row's topology is categorized as already configured
+// by a profile with categorization rules, and one with the profile's own topology is not.
+func TestWithOverridesCategorize(t *testing.T) {
+	profile := defaultProfile.withOverrides(map[string]string{topologyAnnotation: "shared"})
+	tests := []struct {
+		name                string
+		annotations         map[string]string
+		expectedCategory    string
+		expectedSubcategory string
+		expectedReasons     int
+	}{
+		{
+			name:                "row topology",
+			annotations:         map[string]string{autoscalingAnnotation: "true", topologyAnnotation: "shared"},
+			expectedCategory:    "already-configured",
+			expectedSubcategory: subcategoryConfigured,
+		},
+		{
+			name:                "profile topology",
+			annotations:         map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology},
+			expectedCategory:    "ready-for-migration",
+			expectedSubcategory: subcategoryWrongTopology,
+			expectedReasons:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if category := profile.categorize(tt.annotations); category != tt.expectedCategory {
+				t.Errorf("categorize() = %s, want %s", category, tt.expectedCategory)
+			}
+			if subcategory := subcategoryOf(profile, tt.annotations); subcategory != tt.expectedSubcategory {
+				t.Errorf("subcategoryOf() = %s, want %s", subcategory, tt.expectedSubcategory)
+			}
+			if reasons := categoryReasons(profile, tt.annotations); len(reasons) != tt.expectedReasons {
+				t.Errorf("categoryReasons() = %v, want %d reasons", reasons, tt.expectedReasons)
+			}
+		})
+	}
+
+	if defaultProfile.Rules[1].AllMatch[topologyAnnotation] != dedicatedTopology {
+		t.Error("Expected the rules of the migration profile not to be modified")
+	}
+}
+
+// TestClusterProfile verifies the annotation values of a cluster's row replace the profile's values, also
+// with provenance stamps, and that other clusters use the profile itself.
+func TestClusterProfile(t *testing.T) {
+	m := &migrateOpts{
+		profile:         topologyProfile,
+		stampProvenance: true,
+		overrides:       map[string]map[string]string{"a1": {topologyAnnotation: "dedicated"}},
+		session:         &runSession{id: "run-123"},
+	}
+
+	if p := m.clusterProfile("b2"); p != topologyProfile {
+		t.Errorf("Expected clusters without a row override to use the profile, got %+v", p)
+	}
+	p := m.patchProfile("a1")
+	if p.Ensure[topologyAnnotation] != "dedicated" || p.Ensure[autoscalingAnnotation] != "true" || p.Ensure[migrationRunIDAnnotation] != "run-123" {
+		t.Errorf("Unexpected patch profile %+v", p)
+	}
+	if topologyProfile.Ensure[topologyAnnotation] != dedicatedTopology {
+		t.Error("Expected the migration profile not to be modified")
+	}
+}
+
+// TestOverridePartError verifies rows cannot override annotations the part selected with --set leaves alone.
+func TestOverridePartError(t *testing.T) {
+	overrides := map[string]string{topologyAnnotation: "shared"}
+	for set, expected := range map[string]string{
+		setBoth:        "",
+		setTopology:    "",
+		setAutoscaling: "overrides hypershift.openshift.io/topology, which --set autoscaling does not set",
+	} {
+		if got := (&migrateOpts{set: set}).overridePartError(overrides); got != expected {
+			t.Errorf("--set %s: overridePartError() = %q, want %q", set, got, expected)
+		}
+	}
+}
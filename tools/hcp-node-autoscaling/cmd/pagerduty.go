@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pagerDutyAPIURL  = "https://api.pagerduty.com"
+	pagerDutyTimeout = 30 * time.Second
+
+	// defaultPDServiceLabel is the OCM subscription label holding the ID of a cluster's PagerDuty service.
+	defaultPDServiceLabel = "sre-capabilities.pagerduty.service-id"
+
+	defaultPDMaintenanceDuration = 2 * time.Hour
+	maxPDMaintenanceDuration     = 24 * time.Hour
+)
+
+// pdMaintenance holds the --pd-maintenance flags, which put the PagerDuty services of the migrated
+// clusters in a maintenance window for the duration of the run so that flipping the autoscaling
+// annotations does not page anyone.
+type pdMaintenance struct {
+	enabled      bool
+	tokenFile    string
+	from         string
+	serviceLabel string
+	duration     time.Duration
+
+	token  string
+	apiURL string
+}
+
+// pdWindow is a PagerDuty maintenance window created for a run.
+type pdWindow struct {
+	id   string
+	opts *pdMaintenance
+}
+
+// addFlags registers the PagerDuty maintenance window flags.
+func (p *pdMaintenance) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&p.enabled, "pd-maintenance", false,
+		"Put the PagerDuty services of the migrated clusters in a maintenance window while the run is in progress")
+	cmd.Flags().StringVar(&p.tokenFile, "pd-token-file", "",
+		"File holding the PagerDuty REST API token used by --pd-maintenance")
+	cmd.Flags().StringVar(&p.from, "pd-from", "",
+		"Email of the PagerDuty user the maintenance window is created as (required by account-level tokens)")
+	cmd.Flags().StringVar(&p.serviceLabel, "pd-service-label", defaultPDServiceLabel,
+		"OCM subscription label holding the PagerDuty service ID of a cluster")
+	cmd.Flags().DurationVar(&p.duration, "pd-maintenance-duration", defaultPDMaintenanceDuration,
+		"Length of the maintenance window; it is removed as soon as the run finishes")
+}
+
+// validate reads the PagerDuty token when --pd-maintenance is set.
+func (p *pdMaintenance) validate() error {
+	if !p.enabled {
+		return nil
+	}
+	if p.tokenFile == "" {
+		return fmt.Errorf("--pd-token-file is required with --pd-maintenance")
+	}
+	if p.duration < time.Minute || p.duration > maxPDMaintenanceDuration {
+		return fmt.Errorf("invalid PagerDuty maintenance duration %s: must be between 1m and %s", p.duration, maxPDMaintenanceDuration)
+	}
+	if p.serviceLabel == "" {
+		return fmt.Errorf("--pd-service-label must not be empty")
+	}
+	data, err := os.ReadFile(p.tokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read PagerDuty token: %v", err)
+	}
+	p.token = strings.TrimSpace(string(data))
+	if p.token == "" {
+		return fmt.Errorf("PagerDuty token file %s is empty", p.tokenFile)
+	}
+	return nil
+}
+
+// start creates a maintenance window for the PagerDuty services of the candidates, looked up with
+// serviceID. Clusters without a service are logged and left out. It returns nil when --pd-maintenance
+// is not set or no candidate has a service.
+func (p *pdMaintenance) start(ctx context.Context, serviceID func(clusterID string) (string, error),
+	candidates []hostedClusterAuditInfo, description string) (*pdWindow, error) {
+	if !p.enabled {
+		return nil, nil
+	}
+
+	services := pdServiceIDs(candidates, serviceID)
+	if len(services) == 0 {
+		slog.Warn("No PagerDuty services found for the candidates; not creating a maintenance window",
+			"label", p.serviceLabel)
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	window, err := p.create(ctx, services, now, now.Add(p.duration), description)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Created PagerDuty maintenance window", "id", window.id, "services", len(services),
+		"end", now.Add(p.duration).Format(time.RFC3339))
+	return window, nil
+}
+
+// pdServiceIDs returns the sorted, unique PagerDuty service IDs of the candidates.
+func pdServiceIDs(candidates []hostedClusterAuditInfo, serviceID func(clusterID string) (string, error)) []string {
+	seen := map[string]bool{}
+	var services []string
+	for _, c := range candidates {
+		id, err := serviceID(c.ClusterID)
+		if err != nil {
+			slog.Warn("Failed to look up PagerDuty service", "clusterID", c.ClusterID, "error", err)
+			continue
+		}
+		if id == "" {
+			slog.Warn("Cluster has no PagerDuty service; its alerts are not suppressed", "clusterID", c.ClusterID)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			services = append(services, id)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// ocmServiceLabel returns a lookup of the given OCM subscription label of a cluster. Clusters without
+// a subscription or the label return "".
+func ocmServiceLabel(ctx context.Context, conn *sdk.Connection, key string) func(clusterID string) (string, error) {
+	return func(clusterID string) (string, error) {
+		clusterResponse, err := conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Get().SendContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get OCM cluster: %v", err)
+		}
+		subscriptionID := clusterResponse.Body().Subscription().ID()
+		if subscriptionID == "" {
+			return "", nil
+		}
+
+		labelsResponse, err := conn.AccountsMgmt().V1().Subscriptions().Subscription(subscriptionID).Labels().List().
+			Size(100).SendContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list OCM subscription labels: %v", err)
+		}
+		for _, label := range labelsResponse.Items().Slice() {
+			if label.Key() == key {
+				return strings.TrimSpace(label.Value()), nil
+			}
+		}
+		return "", nil
+	}
+}
+
+// create creates a maintenance window covering services from start to end.
+func (p *pdMaintenance) create(ctx context.Context, services []string, start, end time.Time, description string) (*pdWindow, error) {
+	type serviceReference struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	type maintenanceWindow struct {
+		Type        string             `json:"type"`
+		StartTime   string             `json:"start_time"`
+		EndTime     string             `json:"end_time"`
+		Description string             `json:"description"`
+		Services    []serviceReference `json:"services"`
+	}
+
+	window := maintenanceWindow{
+		Type:        "maintenance_window",
+		StartTime:   start.Format(time.RFC3339),
+		EndTime:     end.Format(time.RFC3339),
+		Description: description,
+	}
+	for _, id := range services {
+		window.Services = append(window.Services, serviceReference{ID: id, Type: "service_reference"})
+	}
+	body, err := json.Marshal(map[string]maintenanceWindow{"maintenance_window": window})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PagerDuty maintenance window: %v", err)
+	}
+
+	var created struct {
+		MaintenanceWindow struct {
+			ID string `json:"id"`
+		} `json:"maintenance_window"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/maintenance_windows", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create PagerDuty maintenance window: %v", err)
+	}
+	if created.MaintenanceWindow.ID == "" {
+		return nil, fmt.Errorf("failed to create PagerDuty maintenance window: no ID in response")
+	}
+	return &pdWindow{id: created.MaintenanceWindow.ID, opts: p}, nil
+}
+
+// end removes the maintenance window, ending it early if it is in progress. It runs even when ctx has
+// been cancelled so that an interrupted run does not leave alerts suppressed. A failure is logged with
+// the window ID to remove by hand.
+func (w *pdWindow) end(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.opts.do(context.WithoutCancel(ctx), http.MethodDelete, "/maintenance_windows/"+w.id, nil, nil); err != nil {
+		slog.Warn("Failed to remove PagerDuty maintenance window; remove it by hand", "id", w.id, "error", err)
+		return
+	}
+	slog.Info("Removed PagerDuty maintenance window", "id", w.id)
+}
+
+// do sends a request to the PagerDuty REST API and decodes the response into out if it is not nil.
+func (p *pdMaintenance) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, pagerDutyTimeout)
+	defer cancel()
+
+	apiURL := p.apiURL
+	if apiURL == "" {
+		apiURL = pagerDutyAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+p.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.from != "" {
+		req.Header.Set("From", p.from)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var pdErr struct {
+			Error struct {
+				Message string   `json:"message"`
+				Errors  []string `json:"errors"`
+			} `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(data, &pdErr) == nil && pdErr.Error.Message != "" {
+			return fmt.Errorf("PagerDuty returned %s: %s", resp.Status,
+				strings.Join(append([]string{pdErr.Error.Message}, pdErr.Error.Errors...), "; "))
+		}
+		return fmt.Errorf("PagerDuty returned %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode PagerDuty response: %v", err)
+	}
+	return nil
+}
+
+// pdMaintenanceDescription describes the maintenance window of a migrate run.
+func pdMaintenanceDescription(ticket string, mgmtClusterNames []string) string {
+	description := "hcp-node-autoscaling control plane autoscaling migration"
+	if ticket != "" {
+		description += " (" + ticket + ")"
+	}
+	if len(mgmtClusterNames) > 0 {
+		description += " on " + strings.Join(mgmtClusterNames, ", ")
+	}
+	return description
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestPDServiceIDs verifies service IDs are deduplicated and clusters without a service are left out.
+func TestPDServiceIDs(t *testing.T) {
+	labels := map[string]string{"a1": "PSVC2", "b2": "PSVC1", "c3": "PSVC2", "d4": ""}
+	lookup := func(clusterID string) (string, error) {
+		if clusterID == "e5" {
+			return "", fmt.Errorf("cluster not found")
+		}
+		return labels[clusterID], nil
+	}
+
+	candidates := []hostedClusterAuditInfo{{ClusterID: "a1"}, {ClusterID: "b2"}, {ClusterID: "c3"}, {ClusterID: "d4"}, {ClusterID: "e5"}}
+	if services := pdServiceIDs(candidates, lookup); !reflect.DeepEqual(services, []string{"PSVC1", "PSVC2"}) {
+		t.Errorf("pdServiceIDs() = %v, want [PSVC1 PSVC2]", services)
+	}
+}
+
+// TestPDMaintenanceValidate verifies the token file is required and read when --pd-maintenance is set.
+func TestPDMaintenanceValidate(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        pdMaintenance
+		expectError bool
+	}{
+		{name: "disabled", opts: pdMaintenance{}},
+		{name: "valid", opts: pdMaintenance{enabled: true, tokenFile: tokenFile, serviceLabel: defaultPDServiceLabel, duration: defaultPDMaintenanceDuration}},
+		{name: "missing token file flag", opts: pdMaintenance{enabled: true, serviceLabel: defaultPDServiceLabel, duration: defaultPDMaintenanceDuration}, expectError: true},
+		{name: "missing token file", opts: pdMaintenance{enabled: true, tokenFile: filepath.Join(dir, "missing"), serviceLabel: defaultPDServiceLabel, duration: defaultPDMaintenanceDuration}, expectError: true},
+		{name: "empty token", opts: pdMaintenance{enabled: true, tokenFile: emptyFile, serviceLabel: defaultPDServiceLabel, duration: defaultPDMaintenanceDuration}, expectError: true},
+		{name: "duration too long", opts: pdMaintenance{enabled: true, tokenFile: tokenFile, serviceLabel: defaultPDServiceLabel, duration: 2 * maxPDMaintenanceDuration}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if (err != nil) != tt.expectError {
+				t.Fatalf("validate() error = %v, expectError %v", err, tt.expectError)
+			}
+			if err == nil && tt.opts.enabled && tt.opts.token != "secret-token" {
+				t.Errorf("Expected the token to be read and trimmed, got %q", tt.opts.token)
+			}
+		})
+	}
+}
+
+// TestPDMaintenanceWindow verifies the maintenance window is created for the resolved services and
+// removed when the run ends, even after the run was interrupted.
+func TestPDMaintenanceWindow(t *testing.T) {
+	var created map[string]map[string]interface{}
+	var deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token token=secret-token" || r.Header.Get("From") != "sre@example.com" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/maintenance_windows":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("Failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"maintenance_window": {"id": "PWIN1"}}`)
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &pdMaintenance{enabled: true, token: "secret-token", from: "sre@example.com", duration: defaultPDMaintenanceDuration, apiURL: server.URL}
+	lookup := func(clusterID string) (string, error) { return "PSVC-" + clusterID, nil }
+	candidates := []hostedClusterAuditInfo{{ClusterID: "a1"}, {ClusterID: "b2"}}
+
+	window, err := p.start(context.Background(), lookup, candidates, pdMaintenanceDescription("OHSS-12345", []string{"hs-mc-1"}))
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if window == nil || window.id != "PWIN1" {
+		t.Fatalf("Expected window PWIN1, got %+v", window)
+	}
+
+	request := created["maintenance_window"]
+	if request["description"] != "hcp-node-autoscaling control plane autoscaling migration (OHSS-12345) on hs-mc-1" {
+		t.Errorf("Unexpected description %q", request["description"])
+	}
+	services, _ := request["services"].([]interface{})
+	if len(services) != 2 {
+		t.Errorf("Expected 2 services, got %v", request["services"])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	window.end(ctx)
+	if deleted != "/maintenance_windows/PWIN1" {
+		t.Errorf("Expected the window to be deleted, got %q", deleted)
+	}
+
+	p.token = "wrong-token"
+	if _, err := p.start(context.Background(), lookup, candidates, ""); err == nil {
+		t.Error("Expected an error when PagerDuty rejects the token")
+	}
+
+	// No services resolved and --pd-maintenance not set both create no window.
+	none := func(string) (string, error) { return "", nil }
+	if window, err := p.start(context.Background(), none, candidates, ""); window != nil || err != nil {
+		t.Errorf("Expected no window without services, got %+v, %v", window, err)
+	}
+	if window, err := (&pdMaintenance{}).start(context.Background(), lookup, candidates, ""); window != nil || err != nil {
+		t.Errorf("Expected no window when disabled, got %+v, %v", window, err)
+	}
+	(*pdWindow)(nil).end(context.Background())
+}
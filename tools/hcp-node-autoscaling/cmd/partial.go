@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/spf13/cobra"
+)
+
+// Parts of the migration selected with migrate --set: the dedicated topology annotation, the autoscaling
+// annotations of the profile, or both.
+const (
+	setTopology    = "topology"
+	setAutoscaling = "autoscaling"
+	setBoth        = "both"
+)
+
+// addSetFlag registers --set on a command that migrates clusters.
+func addSetFlag(cmd *cobra.Command, m *migrateOpts) {
+	cmd.Flags().StringVar(&m.set, "set", setBoth,
+		"Annotations to set: topology, autoscaling or both; a later run with the other part completes a partial migration")
+}
+
+// validateSet checks the --set value.
+func validateSet(set string) error {
+	switch set {
+	case "", setBoth, setTopology, setAutoscaling:
+		return nil
+	default:
+		return fmt.Errorf("invalid --set %q: must be one of topology, autoscaling, both", set)
+	}
+}
+
+// partial returns the profile restricted to the part of the migration selected with --set. The topology
+// part ensures only the topology annotation, with the profile's value or the dedicated topology. The
+// autoscaling part ensures the profile's other annotations and removes its removed annotations. A partial
+// profile categorizes clusters by its own annotations, so clusters where the part is already set count as
+// already configured while clusters missing it, whatever the other part, are ready for migration.
+func (p *migrationProfile) partial(set string) (*migrationProfile, error) {
+	p = p.orDefault()
+	if set == "" || set == setBoth {
+		return p, nil
+	}
+
+	partial := &migrationProfile{Name: fmt.Sprintf("%s (%s only)", p.Name, set)}
+	switch set {
+	case setTopology:
+		topology, ok := p.Ensure[topologyAnnotation]
+		if !ok {
+			topology = dedicatedTopology
+		}
+		partial.Ensure = map[string]string{topologyAnnotation: topology}
+	case setAutoscaling:
+		partial.Ensure = maps.Clone(p.Ensure)
+		delete(partial.Ensure, topologyAnnotation)
+		if len(partial.Ensure) == 0 {
+			return nil, fmt.Errorf("invalid --set %s: profile %s ensures no annotation besides %s", set, p.Name, topologyAnnotation)
+		}
+		partial.Remove = p.Remove
+	default:
+		return nil, validateSet(set)
+	}
+	return partial, nil
+}
+
+// remainingPart returns the --set value completing a migration made with set, or "" when set migrates
+// both parts.
+func remainingPart(set string) string {
+	switch set {
+	case setTopology:
+		return setAutoscaling
+	case setAutoscaling:
+		return setTopology
+	default:
+		return ""
+	}
+}
+
+// printRemainingPart reminds the operator that clusters migrated with a partial --set still need the other
+// part.
+func (m *migrateOpts) printRemainingPart(w io.Writer) {
+	if rest := remainingPart(m.set); rest != "" {
+		fmt.Fprintf(w, "Partial migration: run migrate again with --set %s to complete it.\n\n", rest)
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestProfilePartial verifies the annotations each --set part ensures and removes, and that bad parts are
+// rejected.
+func TestProfilePartial(t *testing.T) {
+	onlyTopology := &migrationProfile{Name: "topology", Ensure: map[string]string{topologyAnnotation: "shared"}}
+
+	tests := []struct {
+		name           string
+		profile        *migrationProfile
+		set            string
+		expectedName   string
+		expectedEnsure map[string]string
+		expectedRemove []string
+		expectErr      string
+	}{
+		{
+			name:           "both",
+			set:            setBoth,
+			expectedName:   "default",
+			expectedEnsure: map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology},
+			expectedRemove: []string{sizeOverrideAnnotation},
+		},
+		{
+			name:           "default part",
+			expectedName:   "default",
+			expectedEnsure: map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology},
+			expectedRemove: []string{sizeOverrideAnnotation},
+		},
+		{
+			name:           "topology of the default profile",
+			set:            setTopology,
+			expectedName:   "default (topology only)",
+			expectedEnsure: map[string]string{topologyAnnotation: dedicatedTopology},
+		},
+		{
+			name:           "topology of a profile ensuring it",
+			profile:        topologyProfile,
+			set:            setTopology,
+			expectedName:   topologyProfile.Name + " (topology only)",
+			expectedEnsure: map[string]string{topologyAnnotation: topologyProfile.Ensure[topologyAnnotation]},
+		},
+		{
+			name:           "autoscaling of the default profile",
+			set:            setAutoscaling,
+			expectedName:   "default (autoscaling only)",
+			expectedEnsure: map[string]string{autoscalingAnnotation: "true"},
+			expectedRemove: []string{sizeOverrideAnnotation},
+		},
+		{
+			name:           "autoscaling",
+			profile:        topologyProfile,
+			set:            setAutoscaling,
+			expectedName:   topologyProfile.Name + " (autoscaling only)",
+			expectedEnsure: map[string]string{autoscalingAnnotation: "true"},
+			expectedRemove: topologyProfile.Remove,
+		},
+		{
+			name:      "autoscaling of a topology profile",
+			profile:   onlyTopology,
+			set:       setAutoscaling,
+			expectErr: "profile topology ensures no annotation besides",
+		},
+		{
+			name:      "unknown part",
+			set:       "size",
+			expectErr: `invalid --set "size": must be one of topology, autoscaling, both`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partial, err := tt.profile.partial(tt.set)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("partial() error = %v, want %q", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("partial() error = %v", err)
+			}
+			if partial.Name != tt.expectedName || !reflect.DeepEqual(partial.Ensure, tt.expectedEnsure) ||
+				!reflect.DeepEqual(partial.Remove, tt.expectedRemove) {
+				t.Errorf("partial() = %+v, want name %q, ensure %v and remove %v", partial, tt.expectedName, tt.expectedEnsure, tt.expectedRemove)
+			}
+		})
+	}
+}
+
+// TestProfilePartialCategorize verifies clusters are categorized by the part of the migration --set
+// selects, so a cluster migrated with one part is a candidate for the other.
+func TestProfilePartialCategorize(t *testing.T) {
+	topologyOnly := map[string]string{topologyAnnotation: dedicatedTopology}
+	autoscalingOnly := map[string]string{autoscalingAnnotation: "true"}
+	withOverride := map[string]string{sizeOverrideAnnotation: "large"}
+	configured := map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology}
+
+	tests := []struct {
+		set         string
+		annotations map[string]string
+		expected    string
+	}{
+		{set: setTopology, annotations: topologyOnly, expected: "already-configured"},
+		{set: setTopology, annotations: autoscalingOnly, expected: "ready-for-migration"},
+		{set: setTopology, annotations: withOverride, expected: "ready-for-migration"},
+		{set: setAutoscaling, annotations: topologyOnly, expected: "ready-for-migration"},
+		{set: setAutoscaling, annotations: autoscalingOnly, expected: "already-configured"},
+		{set: setAutoscaling, annotations: withOverride, expected: "needs-removal"},
+		{set: setBoth, annotations: autoscalingOnly, expected: "ready-for-migration"},
+		{set: setBoth, annotations: topologyOnly, expected: "ready-for-migration"},
+		{set: setBoth, annotations: configured, expected: "already-configured"},
+	}
+
+	for _, tt := range tests {
+		partial, err := defaultProfile.partial(tt.set)
+		if err != nil {
+			t.Fatalf("partial(%s) error = %v", tt.set, err)
+		}
+		if got := partial.categorize(tt.annotations); got != tt.expected {
+			t.Errorf("--set %s: categorize(%v) = %s, want %s", tt.set, tt.annotations, got, tt.expected)
+		}
+	}
+}
+
+// TestPrintRemainingPart verifies a partial migration says which --set completes it.
+func TestPrintRemainingPart(t *testing.T) {
+	for set, expected := range map[string]string{
+		setTopology:    "Partial migration: run migrate again with --set autoscaling to complete it.\n\n",
+		setAutoscaling: "Partial migration: run migrate again with --set topology to complete it.\n\n",
+		setBoth:        "",
+		"":             "",
+	} {
+		var buf bytes.Buffer
+		(&migrateOpts{set: set}).printRemainingPart(&buf)
+		if buf.String() != expected {
+			t.Errorf("--set %q: printRemainingPart() = %q, want %q", set, buf.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// jsonPatchOp is a single RFC 6902 JSON patch operation.
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON leaves out the value of remove operations, the only ones without one. Every other operation
+// keeps its value even when it is empty, so ensuring an annotation with an empty value adds "".
+func (o jsonPatchOp) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type op jsonPatchOp
+	return json.Marshal(op(o))
+}
+
+// jsonPatchManifestWork applies the migration profile annotations with a JSON patch that only touches
+// the annotations of the HostedCluster manifest entry, leaving the rest of the workload untouched.
+func (m *migrateOpts) jsonPatchManifestWork(ctx context.Context, clusterID string) error {
+	manifestWork, err := m.getManifestWork(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	index, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		return err
+	}
+
+	profile := m.patchProfile(clusterID)
+	if err := verifyProfilePatch(manifestWork, index, manifestData, profile); err != nil {
+		return err
+	}
+
+	patch, err := profileAnnotationPatch(index, manifestData, profile)
+	if err != nil {
+		return err
+	}
+
+	if err := m.serviceClient.Patch(ctx, manifestWork, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		if apierrors.IsConflict(err) {
+			return err
+		}
+		return fmt.Errorf("failed to patch ManifestWork: %w", err)
+	}
+
+	return nil
+}
+
+// profileAnnotationPatch builds a JSON patch that removes the profile's removed annotations and sets
+// its ensured annotations on the manifest at the given index.
+func profileAnnotationPatch(index int, manifestData map[string]interface{}, profile *migrationProfile) ([]byte, error) {
+	return json.Marshal(profileAnnotationOps(index, manifestData, profile))
+}
+
+// profileAnnotationOps returns the operations of profileAnnotationPatch. They first test that the entry is
+// still the same HostedCluster so the patch fails instead of modifying the wrong manifest if the workload
+// changed since it was read.
+func profileAnnotationOps(index int, manifestData map[string]interface{}, profile *migrationProfile) []jsonPatchOp {
+	profile = profile.orDefault()
+	base := fmt.Sprintf("/spec/workload/manifests/%d", index)
+
+	ops := []jsonPatchOp{{Op: "test", Path: base + "/kind", Value: "HostedCluster"}}
+
+	metadata, ok := manifestData["metadata"].(map[string]interface{})
+	if !ok {
+		ops = append(ops, jsonPatchOp{
+			Op:    "add",
+			Path:  base + "/metadata",
+			Value: map[string]interface{}{"annotations": profile.Ensure},
+		})
+		return ops
+	}
+
+	if name, ok := metadata["name"].(string); ok {
+		ops = append(ops, jsonPatchOp{Op: "test", Path: base + "/metadata/name", Value: name})
+	}
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		ops = append(ops, jsonPatchOp{
+			Op:    "add",
+			Path:  base + "/metadata/annotations",
+			Value: profile.Ensure,
+		})
+		return ops
+	}
+
+	for _, key := range profile.Remove {
+		if _, ok := annotations[key]; ok {
+			ops = append(ops, jsonPatchOp{Op: "remove", Path: base + "/metadata/annotations/" + escapeJSONPointer(key)})
+		}
+	}
+
+	for _, key := range profile.ensureKeys() {
+		ops = append(ops, jsonPatchOp{
+			Op:    "add",
+			Path:  base + "/metadata/annotations/" + escapeJSONPointer(key),
+			Value: profile.Ensure[key],
+		})
+	}
+	return ops
+}
+
+// escapeJSONPointer escapes a key for use as a JSON pointer reference token.
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// patchManifestWork applies the migration profile annotations to the HostedCluster manifest in ManifestWork using
+// the configured patch strategy, retrying with exponential backoff when the write conflicts with a
+// concurrent change. It returns the number of conflict retries that were needed.
+func (m *migrateOpts) patchManifestWork(ctx context.Context, clusterID string) (int, error) {
+	attempts := 0
+	err := retry.RetryOnConflict(conflictBackoff(m.conflictRetries), func() error {
+		attempts++
+		if attempts > 1 {
+			slog.Info("Retrying ManifestWork update after conflict", "clusterID", clusterID, "retry", attempts-1)
+		}
+		return withPhaseTimeout(ctx, m.timeouts.manifestWork, "updating ManifestWork", "cluster "+clusterID, func(ctx context.Context) error {
+			if m.plan != nil {
+				return m.applyPlannedPatch(ctx, clusterID)
+			}
+			switch m.patchStrategy {
+			case "json-patch":
+				return m.jsonPatchManifestWork(ctx, clusterID)
+			default:
+				return m.updateManifestWork(ctx, clusterID)
+			}
+		})
+	})
+	retries := attempts - 1
+
+	if apierrors.IsConflict(err) {
+		return retries, fmt.Errorf("failed to update ManifestWork after %d conflict retries: %w", retries, err)
+	}
+	return retries, err
+}
+
+// conflictBackoff returns the backoff used between ManifestWork update retries.
+func conflictBackoff(retries int) wait.Backoff {
+	return wait.Backoff{
+		Steps:    retries + 1,
+		Duration: 200 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.1,
+		Cap:      10 * time.Second,
+	}
+}
+
+// updateManifestWork reads the ManifestWork, applies the migration profile annotations to its
+// HostedCluster manifest and updates it. Conflict errors are returned unwrapped so they can be retried.
+func (m *migrateOpts) updateManifestWork(ctx context.Context, clusterID string) error {
+	manifestWork, err := m.getManifestWork(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := applyProfileAnnotations(manifestWork, m.patchProfile(clusterID)); err != nil {
+		return err
+	}
+
+	if err := m.serviceClient.Update(ctx, manifestWork); err != nil {
+		if apierrors.IsConflict(err) {
+			return err
+		}
+		return fmt.Errorf("failed to update ManifestWork: %w", err)
+	}
+
+	return nil
+}
+
+// getManifestWork retrieves the ManifestWork for a hosted cluster from the service cluster.
+func (m *migrateOpts) getManifestWork(ctx context.Context, clusterID string) (*workv1.ManifestWork, error) {
+	manifestWork := &workv1.ManifestWork{}
+	err := m.serviceClient.Get(ctx,
+		types.NamespacedName{
+			Name:      clusterID,
+			Namespace: m.mgmtClusterName,
+		},
+		manifestWork)
+
+	if apierrors.IsNotFound(err) {
+		return nil, withFailureClass(failureManifestNotFound, fmt.Errorf("ManifestWork %s/%s not found on service cluster %s; check that it is the parent of the management cluster",
+			m.mgmtClusterName, clusterID, m.serviceClusterID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ManifestWork %s/%s: %w",
+			m.mgmtClusterName, clusterID, err)
+	}
+
+	return manifestWork, nil
+}
+
+// findHostedClusterManifest returns the index and decoded content of the HostedCluster manifest in a ManifestWork.
+// ManifestWorks are named after the cluster ID, so the HostedCluster manifest whose api.openshift.com/id label
+// matches the ManifestWork name is used. A single HostedCluster manifest without the label is accepted as well.
+func findHostedClusterManifest(manifestWork *workv1.ManifestWork) (int, map[string]interface{}, error) {
+	index, unlabeled, found := -1, 0, 0
+	var unlabeledData map[string]interface{}
+	for i, manifest := range manifestWork.Spec.Workload.Manifests {
+		if manifest.Raw == nil {
+			continue
+		}
+
+		var manifestData map[string]interface{}
+		if err := json.Unmarshal(manifest.Raw, &manifestData); err != nil {
+			continue
+		}
+
+		kind, _ := manifestData["kind"].(string)
+		if kind != "HostedCluster" {
+			continue
+		}
+		found++
+
+		metadata, _ := manifestData["metadata"].(map[string]interface{})
+		labels, _ := metadata["labels"].(map[string]interface{})
+		id, ok := labels["api.openshift.com/id"].(string)
+		switch {
+		case ok && id == manifestWork.Name:
+			return i, manifestData, nil
+		case !ok:
+			index, unlabeledData = i, manifestData
+			unlabeled++
+		}
+	}
+
+	if found == 1 && unlabeled == 1 {
+		return index, unlabeledData, nil
+	}
+	if found > 0 {
+		return -1, nil, fmt.Errorf("no HostedCluster manifest with cluster ID %s in ManifestWork manifests", manifestWork.Name)
+	}
+	return -1, nil, fmt.Errorf("HostedCluster not found in ManifestWork manifests")
+}
+
+// applyProfileAnnotations sets the ensured annotations and deletes the removed annotations of the
+// migration profile on the HostedCluster manifest of a ManifestWork.
+func applyProfileAnnotations(manifestWork *workv1.ManifestWork, profile *migrationProfile) error {
+	i, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		return err
+	}
+
+	setProfileAnnotations(manifestData, profile)
+
+	jsonData, err := json.Marshal(manifestData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal modified manifest: %v", err)
+	}
+
+	if err := verifyManifestRoundTrip(manifestWork.Spec.Workload.Manifests[i].Raw, jsonData, profile); err != nil {
+		return err
+	}
+
+	manifestWork.Spec.Workload.Manifests[i].Raw = jsonData
+	return nil
+}
+
+// setProfileAnnotations applies the migration profile to the annotations of a decoded manifest, creating
+// its metadata and annotations when they are missing.
+func setProfileAnnotations(manifestData map[string]interface{}, profile *migrationProfile) {
+	metadata, ok := manifestData["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = make(map[string]interface{})
+		manifestData["metadata"] = metadata
+	}
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = make(map[string]interface{})
+		metadata["annotations"] = annotations
+	}
+
+	profile.applyTo(annotations)
+}
@@ -0,0 +1,622 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestProfileAnnotationPatch verifies the JSON patch operations generated for different
+// HostedCluster manifest shapes.
+func TestProfileAnnotationPatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		index         int
+		manifestData  map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name:  "existing annotations",
+			index: 1,
+			manifestData: map[string]interface{}{
+				"kind": "HostedCluster",
+				"metadata": map[string]interface{}{
+					"name":        "test-cluster",
+					"annotations": map[string]interface{}{"other": "value"},
+				},
+			},
+			expectedPaths: []string{
+				"/spec/workload/manifests/1/kind",
+				"/spec/workload/manifests/1/metadata/name",
+				"/spec/workload/manifests/1/metadata/annotations/hypershift.openshift.io~1resource-based-cp-auto-scaling",
+				"/spec/workload/manifests/1/metadata/annotations/hypershift.openshift.io~1topology",
+			},
+		},
+		{
+			name:  "removes size override",
+			index: 0,
+			manifestData: map[string]interface{}{
+				"kind": "HostedCluster",
+				"metadata": map[string]interface{}{
+					"name":        "test-cluster",
+					"annotations": map[string]interface{}{"hypershift.openshift.io/cluster-size-override": "m5xl"},
+				},
+			},
+			expectedPaths: []string{
+				"/spec/workload/manifests/0/kind",
+				"/spec/workload/manifests/0/metadata/name",
+				"/spec/workload/manifests/0/metadata/annotations/hypershift.openshift.io~1cluster-size-override",
+				"/spec/workload/manifests/0/metadata/annotations/hypershift.openshift.io~1resource-based-cp-auto-scaling",
+				"/spec/workload/manifests/0/metadata/annotations/hypershift.openshift.io~1topology",
+			},
+		},
+		{
+			name:  "no annotations",
+			index: 0,
+			manifestData: map[string]interface{}{
+				"kind":     "HostedCluster",
+				"metadata": map[string]interface{}{"name": "test-cluster"},
+			},
+			expectedPaths: []string{
+				"/spec/workload/manifests/0/kind",
+				"/spec/workload/manifests/0/metadata/name",
+				"/spec/workload/manifests/0/metadata/annotations",
+			},
+		},
+		{
+			name:         "no metadata",
+			index:        2,
+			manifestData: map[string]interface{}{"kind": "HostedCluster"},
+			expectedPaths: []string{
+				"/spec/workload/manifests/2/kind",
+				"/spec/workload/manifests/2/metadata",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := profileAnnotationPatch(tt.index, tt.manifestData, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var ops []jsonPatchOp
+			if err := json.Unmarshal(patch, &ops); err != nil {
+				t.Fatalf("Failed to unmarshal patch: %v", err)
+			}
+
+			if len(ops) != len(tt.expectedPaths) {
+				t.Fatalf("Expected %d operations, got %d: %s", len(tt.expectedPaths), len(ops), patch)
+			}
+			for i, op := range ops {
+				if op.Path != tt.expectedPaths[i] {
+					t.Errorf("Operation %d path = %s, want %s", i, op.Path, tt.expectedPaths[i])
+				}
+			}
+
+			if ops[0].Op != "test" {
+				t.Errorf("Expected first operation to be a test, got %s", ops[0].Op)
+			}
+			if last := ops[len(ops)-1]; last.Op != "add" {
+				t.Errorf("Expected last operation to be an add, got %s", last.Op)
+			}
+		})
+	}
+}
+
+// TestJSONPatchOpMarshal verifies an empty value is kept in the operations that take one, and remove
+// operations carry no value.
+func TestJSONPatchOpMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       jsonPatchOp
+		expected string
+	}{
+		{
+			name:     "add empty value",
+			op:       jsonPatchOp{Op: "add", Path: "/metadata/annotations/a", Value: ""},
+			expected: `{"op":"add","path":"/metadata/annotations/a","value":""}`,
+		},
+		{
+			name:     "test value",
+			op:       jsonPatchOp{Op: "test", Path: "/kind", Value: "HostedCluster"},
+			expected: `{"op":"test","path":"/kind","value":"HostedCluster"}`,
+		},
+		{
+			name:     "remove",
+			op:       jsonPatchOp{Op: "remove", Path: "/metadata/annotations/a"},
+			expected: `{"op":"remove","path":"/metadata/annotations/a"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.op)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.expected)
+			}
+		})
+	}
+}
+
+// TestJSONPatchManifestWork verifies the JSON patch strategy only changes the HostedCluster annotations.
+func TestJSONPatchManifestWork(t *testing.T) {
+	secretJSON, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "test-secret"},
+	})
+	hcJSON, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata": map[string]interface{}{
+			"name":        "test-cluster",
+			"annotations": map[string]interface{}{"other.annotation": "value"},
+		},
+	})
+
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-id", Namespace: "test-mgmt-cluster"},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: secretJSON}},
+					{RawExtension: runtime.RawExtension{Raw: hcJSON}},
+				},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+	serviceClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mw).Build()
+
+	m := &migrateOpts{
+		serviceClient:   serviceClient,
+		mgmtClusterName: "test-mgmt-cluster",
+		patchStrategy:   "json-patch",
+	}
+
+	if _, err := m.patchManifestWork(context.Background(), "test-cluster-id"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updated := &workv1.ManifestWork{}
+	if err := serviceClient.Get(context.Background(), client.ObjectKeyFromObject(mw), updated); err != nil {
+		t.Fatalf("Failed to get ManifestWork: %v", err)
+	}
+
+	var secretData map[string]interface{}
+	if err := json.Unmarshal(updated.Spec.Workload.Manifests[0].Raw, &secretData); err != nil {
+		t.Fatalf("Failed to unmarshal Secret manifest: %v", err)
+	}
+	if _, ok := secretData["metadata"].(map[string]interface{})["annotations"]; ok {
+		t.Errorf("Secret manifest should not have been modified")
+	}
+
+	var hcData map[string]interface{}
+	if err := json.Unmarshal(updated.Spec.Workload.Manifests[1].Raw, &hcData); err != nil {
+		t.Fatalf("Failed to unmarshal HostedCluster manifest: %v", err)
+	}
+	annotations := hcData["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+	if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+		t.Errorf("auto-scaling annotation not set")
+	}
+	if annotations["other.annotation"] != "value" {
+		t.Errorf("existing annotation was not preserved")
+	}
+}
+
+// TestEscapeJSONPointer verifies JSON pointer escaping of annotation keys.
+func TestEscapeJSONPointer(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{key: "simple", expected: "simple"},
+		{key: "hypershift.openshift.io/topology", expected: "hypershift.openshift.io~1topology"},
+		{key: "a~b/c", expected: "a~0b~1c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if result := escapeJSONPointer(tt.key); result != tt.expected {
+				t.Errorf("escapeJSONPointer(%q) = %q, want %q", tt.key, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPatchManifestWorkAnnotations verifies annotation injection into ManifestWork resources.
+func TestPatchManifestWorkAnnotations(t *testing.T) {
+	tests := []struct {
+		name                string
+		initialAnnotations  map[string]string
+		expectError         bool
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "adds annotation to cluster without existing annotations",
+			initialAnnotations: map[string]string{
+				"other.annotation": "value",
+			},
+			expectError: false,
+			expectedAnnotations: map[string]string{
+				"other.annotation": "value",
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+		},
+		{
+			name:               "adds annotation to cluster with no annotations",
+			initialAnnotations: map[string]string{},
+			expectError:        false,
+			expectedAnnotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+		},
+		{
+			name: "updates existing annotation",
+			initialAnnotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "false",
+			},
+			expectError: false,
+			expectedAnnotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "hypershift.openshift.io/v1beta1",
+					Kind:       "HostedCluster",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Namespace:   "test-namespace",
+					Annotations: tt.initialAnnotations,
+				},
+			}
+
+			hcJSON, err := json.Marshal(hc)
+			if err != nil {
+				t.Fatalf("Failed to marshal HostedCluster: %v", err)
+			}
+
+			mw := &workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster-id",
+					Namespace: "test-mgmt-cluster",
+				},
+				Spec: workv1.ManifestWorkSpec{
+					Workload: workv1.ManifestsTemplate{
+						Manifests: []workv1.Manifest{
+							{RawExtension: runtime.RawExtension{Raw: hcJSON}},
+						},
+					},
+				},
+			}
+
+			manifest := mw.Spec.Workload.Manifests[0]
+			var manifestData map[string]interface{}
+			if err := json.Unmarshal(manifest.Raw, &manifestData); err != nil {
+				t.Fatalf("Failed to unmarshal manifest: %v", err)
+			}
+
+			kind, _ := manifestData["kind"].(string)
+			if kind != "HostedCluster" {
+				t.Fatalf("Expected HostedCluster, got %s", kind)
+			}
+
+			metadata, ok := manifestData["metadata"].(map[string]interface{})
+			if !ok {
+				metadata = make(map[string]interface{})
+				manifestData["metadata"] = metadata
+			}
+
+			annotations, ok := metadata["annotations"].(map[string]interface{})
+			if !ok {
+				annotations = make(map[string]interface{})
+				metadata["annotations"] = annotations
+			}
+
+			annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] = "true"
+
+			for key, expectedValue := range tt.expectedAnnotations {
+				actualValue, ok := annotations[key]
+				if !ok {
+					t.Errorf("Expected annotation %s not found", key)
+					continue
+				}
+				if actualValue != expectedValue {
+					t.Errorf("Annotation %s = %v, want %v", key, actualValue, expectedValue)
+				}
+			}
+
+			if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+				t.Errorf("auto-scaling annotation not set correctly")
+			}
+		})
+	}
+}
+
+// TestPatchManifestWorkFindsHostedCluster verifies HostedCluster detection in multi-manifest ManifestWork.
+func TestPatchManifestWorkFindsHostedCluster(t *testing.T) {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name": "test-secret",
+		},
+	}
+	secretJSON, _ := json.Marshal(secret)
+
+	hc := map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       "HostedCluster",
+		"metadata": map[string]interface{}{
+			"name":        "test-cluster",
+			"annotations": map[string]interface{}{},
+		},
+	}
+	hcJSON, _ := json.Marshal(hc)
+
+	cert := map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name": "test-cert",
+		},
+	}
+	certJSON, _ := json.Marshal(cert)
+
+	mw := &workv1.ManifestWork{
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: secretJSON}},
+					{RawExtension: runtime.RawExtension{Raw: hcJSON}},
+					{RawExtension: runtime.RawExtension{Raw: certJSON}},
+				},
+			},
+		},
+	}
+
+	foundIndex := -1
+	for i, manifest := range mw.Spec.Workload.Manifests {
+		var manifestData map[string]interface{}
+		if err := json.Unmarshal(manifest.Raw, &manifestData); err != nil {
+			continue
+		}
+
+		kind, _ := manifestData["kind"].(string)
+		if kind == "HostedCluster" {
+			foundIndex = i
+			break
+		}
+	}
+
+	if foundIndex != 1 {
+		t.Errorf("Expected to find HostedCluster at index 1, found at %d", foundIndex)
+	}
+
+	var hcData map[string]interface{}
+	if err := json.Unmarshal(mw.Spec.Workload.Manifests[foundIndex].Raw, &hcData); err != nil {
+		t.Fatalf("Failed to unmarshal HostedCluster: %v", err)
+	}
+
+	metadata := hcData["metadata"].(map[string]interface{})
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = make(map[string]interface{})
+		metadata["annotations"] = annotations
+	}
+
+	annotations["test-key"] = "test-value"
+
+	if annotations["test-key"] != "test-value" {
+		t.Errorf("Failed to modify HostedCluster annotations")
+	}
+}
+
+// TestFindHostedClusterManifestByClusterID verifies the HostedCluster manifest is matched by its cluster ID
+// label when a ManifestWork holds several HostedClusters.
+func TestFindHostedClusterManifestByClusterID(t *testing.T) {
+	manifest := func(name, id string) workv1.Manifest {
+		metadata := map[string]interface{}{"name": name}
+		if id != "" {
+			metadata["labels"] = map[string]interface{}{"api.openshift.com/id": id}
+		}
+		raw, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "hypershift.openshift.io/v1beta1",
+			"kind":       "HostedCluster",
+			"metadata":   metadata,
+		})
+		return workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}}
+	}
+
+	tests := []struct {
+		name          string
+		manifests     []workv1.Manifest
+		expectedIndex int
+		expectError   bool
+	}{
+		{name: "matching label", manifests: []workv1.Manifest{manifest("other", "b2"), manifest("cluster-a1", "a1")}, expectedIndex: 1},
+		{name: "single unlabeled manifest", manifests: []workv1.Manifest{manifest("cluster-a1", "")}, expectedIndex: 0},
+		{name: "several unlabeled manifests", manifests: []workv1.Manifest{manifest("cluster-a1", ""), manifest("other", "")}, expectError: true},
+		{name: "label of another cluster", manifests: []workv1.Manifest{manifest("other", "b2")}, expectError: true},
+		{name: "no HostedCluster", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := &workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: "a1"},
+				Spec:       workv1.ManifestWorkSpec{Workload: workv1.ManifestsTemplate{Manifests: tt.manifests}},
+			}
+			index, _, err := findHostedClusterManifest(mw)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("findHostedClusterManifest() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && index != tt.expectedIndex {
+				t.Errorf("Expected index %d, got %d", tt.expectedIndex, index)
+			}
+		})
+	}
+}
+
+// TestPatchManifestWorkConflictRetries verifies ManifestWork updates are retried on conflict
+// and that the number of retries is reported.
+func TestPatchManifestWorkConflictRetries(t *testing.T) {
+	tests := []struct {
+		name            string
+		conflicts       int
+		conflictRetries int
+		expectError     bool
+		expectedRetries int
+	}{
+		{
+			name:            "no conflicts",
+			conflicts:       0,
+			conflictRetries: 3,
+			expectError:     false,
+			expectedRetries: 0,
+		},
+		{
+			name:            "succeeds after conflicts within budget",
+			conflicts:       2,
+			conflictRetries: 3,
+			expectError:     false,
+			expectedRetries: 2,
+		},
+		{
+			name:            "fails when conflicts exhaust the budget",
+			conflicts:       5,
+			conflictRetries: 2,
+			expectError:     true,
+			expectedRetries: 2,
+		},
+		{
+			name:            "no retries allowed",
+			conflicts:       1,
+			conflictRetries: 0,
+			expectError:     true,
+			expectedRetries: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hcJSON, _ := json.Marshal(map[string]interface{}{
+				"apiVersion": "hypershift.openshift.io/v1beta1",
+				"kind":       "HostedCluster",
+				"metadata":   map[string]interface{}{"name": "test-cluster"},
+			})
+			mw := &workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-id", Namespace: "test-mgmt-cluster"},
+				Spec: workv1.ManifestWorkSpec{
+					Workload: workv1.ManifestsTemplate{
+						Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: hcJSON}}},
+					},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			if err := workv1.Install(scheme); err != nil {
+				t.Fatalf("Failed to add work v1 scheme: %v", err)
+			}
+
+			conflictsLeft := tt.conflicts
+			serviceClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(mw).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if conflictsLeft > 0 {
+							conflictsLeft--
+							return apierrors.NewConflict(schema.GroupResource{Group: "work.open-cluster-management.io", Resource: "manifestworks"},
+								obj.GetName(), fmt.Errorf("the object has been modified"))
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			m := &migrateOpts{
+				serviceClient:   serviceClient,
+				mgmtClusterName: "test-mgmt-cluster",
+				conflictRetries: tt.conflictRetries,
+			}
+
+			retries, err := m.patchManifestWork(context.Background(), "test-cluster-id")
+			if tt.expectError && err == nil {
+				t.Fatalf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if retries != tt.expectedRetries {
+				t.Errorf("Expected %d retries, got %d", tt.expectedRetries, retries)
+			}
+
+			if tt.expectError {
+				return
+			}
+
+			updated := &workv1.ManifestWork{}
+			if err := serviceClient.Get(context.Background(), client.ObjectKeyFromObject(mw), updated); err != nil {
+				t.Fatalf("Failed to get ManifestWork: %v", err)
+			}
+			var manifestData map[string]interface{}
+			if err := json.Unmarshal(updated.Spec.Workload.Manifests[0].Raw, &manifestData); err != nil {
+				t.Fatalf("Failed to unmarshal manifest: %v", err)
+			}
+			annotations := manifestData["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+			if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+				t.Errorf("auto-scaling annotation not set after retries")
+			}
+		})
+	}
+}
+
+// TestGetManifestWorkNotFound verifies a missing ManifestWork names the service cluster it was looked up on.
+func TestGetManifestWorkNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := workv1.Install(scheme); err != nil {
+		t.Fatalf("Failed to add work v1 scheme: %v", err)
+	}
+
+	m := &migrateOpts{
+		serviceClient:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+		serviceClusterID: "svc-123",
+		mgmtClusterName:  "test-mgmt-cluster",
+	}
+
+	_, err := m.getManifestWork(context.Background(), "missing-cluster-id")
+	if err == nil {
+		t.Fatal("Expected error for missing ManifestWork")
+	}
+	if !strings.Contains(err.Error(), "not found on service cluster svc-123") {
+		t.Errorf("Expected error to name the service cluster, got %q", err)
+	}
+}
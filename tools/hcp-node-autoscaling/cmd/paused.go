@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+)
+
+// pauseAnnotations are HostedCluster annotations showing the control plane is being managed by hand,
+// typically during an incident or a support exception. Clusters carrying them are treated as paused.
+var pauseAnnotations = []string{
+	"hypershift.openshift.io/control-plane-operator-image",
+	"hypershift.openshift.io/disable-pki-reconciliation",
+}
+
+// pausedReason returns why a HostedCluster is paused, or an empty string if it is not. A cluster is paused
+// while spec.pausedUntil is "true" or a time in the future, or while it carries one of the pause annotations.
+func pausedReason(hc *hypershiftv1beta1.HostedCluster, now time.Time) string {
+	if until := hc.Spec.PausedUntil; until != nil {
+		if strings.EqualFold(*until, "true") {
+			return "pausedUntil: true"
+		}
+		if t, err := time.Parse(time.RFC3339, *until); err == nil && now.Before(t) {
+			return "pausedUntil: " + t.UTC().Format(time.RFC3339)
+		}
+	}
+
+	for _, key := range pauseAnnotations {
+		if _, ok := hc.Annotations[key]; ok {
+			return "annotation: " + key
+		}
+	}
+
+	return ""
+}
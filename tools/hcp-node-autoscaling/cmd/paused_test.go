@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPausedReason verifies pausedUntil values and pause annotations are recognized as paused.
+func TestPausedReason(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		pausedUntil string
+		annotations map[string]string
+		expected    string
+	}{
+		{name: "not paused"},
+		{name: "paused indefinitely", pausedUntil: "true", expected: "pausedUntil: true"},
+		{name: "paused indefinitely mixed case", pausedUntil: "True", expected: "pausedUntil: true"},
+		{name: "paused until future time", pausedUntil: "2025-06-02T00:00:00Z", expected: "pausedUntil: 2025-06-02T00:00:00Z"},
+		{name: "pause expired", pausedUntil: "2025-05-31T00:00:00Z"},
+		{name: "unpaused", pausedUntil: "false"},
+		{name: "invalid value", pausedUntil: "tomorrow"},
+		{
+			name:        "control plane operator image override",
+			annotations: map[string]string{"hypershift.openshift.io/control-plane-operator-image": "quay.io/example/cpo:debug"},
+			expected:    "annotation: hypershift.openshift.io/control-plane-operator-image",
+		},
+		{
+			name:        "unrelated annotation",
+			annotations: map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if tt.pausedUntil != "" {
+				hc.Spec.PausedUntil = &tt.pausedUntil
+			}
+
+			if result := pausedReason(hc, now); result != tt.expected {
+				t.Errorf("pausedReason() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCategorizePausedCluster verifies paused clusters are categorized as paused unless includePaused is set,
+// and that already configured clusters keep their category.
+func TestCategorizePausedCluster(t *testing.T) {
+	paused := "true"
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		includePaused bool
+		expected      string
+	}{
+		{name: "ready cluster paused", expected: "paused"},
+		{
+			name:        "needs-removal cluster paused",
+			annotations: map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"},
+			expected:    "paused",
+		},
+		{
+			name: "already configured cluster paused",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+			},
+			expected: "already-configured",
+		},
+		{name: "include paused", includePaused: true, expected: "ready-for-migration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       hypershiftv1beta1.HostedClusterSpec{PausedUntil: &paused},
+			}
+
+			opts := &auditOpts{includePaused: tt.includePaused}
+			if result := opts.categorizeCluster(hc); result != tt.expected {
+				t.Errorf("categorizeCluster() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
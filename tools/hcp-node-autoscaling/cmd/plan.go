@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// planVersion is the version of the plan file format written by plan and accepted by apply.
+	planVersion = 1
+
+	planSignatureType   = "hmac-sha256"
+	defaultMaxPlanAge   = 24 * time.Hour
+	minSigningKeyLength = 32
+)
+
+// migrationPlan is the reviewable record of the exact ManifestWork patches a migration will make. It is
+// signed with an HMAC of its content so that apply only executes plans that were not edited after review.
+type migrationPlan struct {
+	Version          int               `json:"version"`
+	CreatedAt        string            `json:"created_at"`
+	CreatedBy        string            `json:"created_by"`
+	ServiceClusterID string            `json:"service_cluster_id"`
+	MgmtClusterID    string            `json:"mgmt_cluster_id"`
+	MgmtClusterName  string            `json:"mgmt_cluster_name"`
+	Environment      string            `json:"environment"`
+	IncludePaused    bool              `json:"include_paused,omitempty"`
+	Profile          *migrationProfile `json:"profile"`
+	Clusters         []plannedChange   `json:"clusters"`
+	SignatureType    string            `json:"signature_type"`
+	Signature        string            `json:"signature"`
+}
+
+// plannedChange is the JSON patch planned for the HostedCluster manifest in one cluster's ManifestWork,
+// with the annotation changes it makes for review.
+type plannedChange struct {
+	ClusterID    string                    `json:"cluster_id"`
+	ClusterName  string                    `json:"cluster_name"`
+	Namespace    string                    `json:"namespace"`
+	ManifestWork string                    `json:"manifestwork"`
+	Changes      []annotationChangePreview `json:"changes"`
+	Patch        []jsonPatchOp             `json:"patch"`
+}
+
+// applyOpts holds the options of the apply command. The migration itself is run with migrate's options,
+// filled in from the plan.
+type applyOpts struct {
+	planFile       string
+	signingKeyFile string
+	maxPlanAge     time.Duration
+	migrate        migrateOpts
+}
+
+// NewPlanCmd creates the plan subcommand that writes a signed migration plan without changing anything.
+func NewPlanCmd() *cobra.Command {
+	opts := &migrateOpts{
+		maxInFlight:   1,
+		patchStrategy: "json-patch",
+		syncTimeout:   defaultSyncTimeout,
+		pollInterval:  defaultPollInterval,
+	}
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Write a signed plan of the ManifestWork patches a migration would make",
+		Long: `Audit the management cluster like migrate and write the exact JSON patch planned for each
+candidate's ManifestWork to a signed plan file, without changing anything.
+
+The plan is a reviewable artifact for change management approval. Once approved, run it with
+the apply subcommand, which refuses plans that were edited or whose ManifestWorks changed.`,
+		Example: `
+  # Write a plan for review
+  hcp-node-autoscaling plan \
+    --mgmt-cluster-id mgmt-456 \
+    --plan-file mgmt-456.plan.json \
+    --signing-key-file ~/.config/hcp-node-autoscaling/plan.key
+
+  # Plan only the clusters from a previously reviewed audit report
+  hcp-node-autoscaling plan \
+    --mgmt-cluster-id mgmt-456 \
+    --from-audit audit.json \
+    --plan-file mgmt-456.plan.json \
+    --signing-key-file ~/.config/hcp-node-autoscaling/plan.key`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	addAllowMismatchFlag(cmd, &opts.allowMismatch)
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "",
+		"The management cluster ID to plan the migration of")
+	cmd.Flags().StringVar(&opts.environment, "environment", "production",
+		"OCM environment whose hosted cluster namespaces are planned: production, staging, all")
+	cmd.Flags().StringVar(&opts.planFile, "plan-file", "",
+		"File the signed plan is written to")
+	cmd.Flags().StringVar(&opts.signingKeyFile, "signing-key-file", "",
+		"File holding the secret key the plan is signed with, at least 32 bytes")
+	cmd.Flags().StringVar(&opts.fromAudit, "from-audit", "",
+		"Read migration candidates from a saved audit JSON report instead of re-auditing")
+	cmd.Flags().DurationVar(&opts.maxAuditAge, "max-audit-age", defaultMaxAuditAge,
+		"Maximum age of the audit report passed to --from-audit")
+	cmd.Flags().BoolVar(&opts.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login and backplane access before planning")
+	cmd.Flags().BoolVar(&opts.ignoreFreeze, "ignore-freeze", false,
+		"Plan clusters that are upgrading, have a control plane upgrade scheduled or are in limited support")
+	cmd.Flags().BoolVar(&opts.includePaused, "include-paused", false,
+		"Plan clusters with spec.pausedUntil or a manual control plane annotation set")
+	cmd.Flags().StringVar(&opts.minVersionFlag, "min-version", "",
+		"Only plan clusters running at least this OpenShift version, e.g. 4.16.10")
+	cmd.Flags().StringSliceVar(&opts.excludeIDs, "exclude-cluster-ids", nil,
+		"Comma-separated cluster IDs that must never be migrated")
+	cmd.Flags().StringVar(&opts.excludeFile, "exclude-file", "",
+		"File of cluster IDs that must never be migrated, one per line")
+	cmd.Flags().StringVar(&opts.profilePath, "profile", "",
+		"Migration profile YAML declaring the annotations to ensure and remove and the categorization rules (default built-in profile)")
+	opts.timeouts.addFlags(cmd, false)
+	opts.kubeconfigs.addFlags(cmd, true)
+	opts.listing.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	_ = cmd.MarkFlagRequired("plan-file")
+	_ = cmd.MarkFlagRequired("signing-key-file")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// NewApplyCmd creates the apply subcommand that executes a signed migration plan.
+func NewApplyCmd() *cobra.Command {
+	opts := &applyOpts{
+		migrate: migrateOpts{
+			maxInFlight:   1,
+			patchStrategy: "json-patch",
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Execute a signed migration plan created by the plan subcommand",
+		Long: `Execute a migration plan created by the plan subcommand.
+
+Before anything is changed, the plan signature is verified and the plan must be younger than
+--max-plan-age. Each cluster is then re-validated: its live HostedCluster must still be ready
+for migration and not in a change freeze, and its ManifestWork must still produce exactly the
+planned patch. Clusters failing these checks are skipped or reported as failed; everything
+else is patched and verified like migrate --patch-strategy json-patch.`,
+		Example: `
+  # Apply an approved plan
+  hcp-node-autoscaling apply \
+    --ticket OHSS-12345 \
+    --plan-file mgmt-456.plan.json \
+    --signing-key-file ~/.config/hcp-node-autoscaling/plan.key`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateMaxClusters(opts.migrate.maxClusters); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.planFile, "plan-file", "",
+		"Signed plan file written by the plan subcommand")
+	cmd.Flags().StringVar(&opts.signingKeyFile, "signing-key-file", "",
+		"File holding the secret key the plan was signed with")
+	cmd.Flags().DurationVar(&opts.maxPlanAge, "max-plan-age", defaultMaxPlanAge,
+		"Maximum age of the plan")
+	cmd.Flags().BoolVar(&opts.migrate.skipConfirmation, "skip-confirmation", false,
+		"Skip confirmation prompt (use with caution)")
+	addConfirmThresholdFlag(cmd, &opts.migrate.confirmThreshold)
+	addMaxClustersFlag(cmd, &opts.migrate.maxClusters)
+	addAllowMismatchFlag(cmd, &opts.migrate.allowMismatch)
+	addFailureBudgetFlags(cmd, &opts.migrate)
+	cmd.Flags().BoolVar(&opts.migrate.skipPreflight, "skip-preflight", false,
+		"Skip checking OCM login, backplane access and ManifestWork permissions before applying")
+	cmd.Flags().BoolVar(&opts.migrate.ignoreFreeze, "ignore-freeze", false,
+		"Apply to clusters that are upgrading, have a control plane upgrade scheduled or are in limited support")
+	cmd.Flags().BoolVar(&opts.migrate.forceOverwrite, "force-overwrite", false,
+		"Apply to clusters whose existing topology annotation differs from the migration, replacing it")
+	cmd.Flags().DurationVar(&opts.migrate.syncTimeout, "sync-timeout", defaultSyncTimeout,
+		"Maximum time to wait for annotations to sync to the management cluster")
+	cmd.Flags().DurationVar(&opts.migrate.pollInterval, "poll-interval", defaultPollInterval,
+		"Interval between sync verification attempts")
+	cmd.Flags().BoolVar(&opts.migrate.serviceLog, "service-log", false,
+		"Post an internal OCM service log to each successfully migrated cluster")
+	cmd.Flags().StringVar(&opts.migrate.historyDir, "history-dir", "",
+		"Directory run history files are written to (default ~/.config/hcp-node-autoscaling/history)")
+	cmd.Flags().StringVar(&opts.migrate.changeRecord, "change-record", "",
+		"Write a markdown change record of the run to this file, to attach to the change ticket")
+	cmd.Flags().StringVar(&opts.migrate.notifyWebhook, "notify-webhook", "",
+		"Post a summary of the run to this webhook URL when the run completes")
+	cmd.Flags().StringVar(&opts.migrate.notifyFormat, "notify-format", "slack",
+		"Payload of --notify-webhook: slack, json")
+	addEventsBrokerFlags(cmd, &opts.migrate.eventsBrokerURL, &opts.migrate.eventsTopic)
+	cmd.Flags().StringVar(&opts.migrate.ticket, "ticket", "",
+		"JIRA issue approving the migration, e.g. OHSS-12345 (required)")
+	cmd.Flags().StringVar(&opts.migrate.ticket, "reason", "", "Alias of --ticket")
+	cmd.Flags().StringVar(&opts.migrate.elevationReason, "elevation-reason", defaultElevationReason,
+		"Description of the change recorded after the ticket on the backplane elevation, the run history and service log entries")
+	opts.migrate.timeouts.addFlags(cmd, true)
+	opts.migrate.kubeconfigs.addFlags(cmd, true)
+	opts.migrate.pagerDuty.addFlags(cmd)
+	_ = cmd.MarkFlagRequired("plan-file")
+	_ = cmd.MarkFlagRequired("signing-key-file")
+	cmd.MarkFlagsMutuallyExclusive("ticket", "reason")
+	opts.migrate.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run verifies the plan and migrates its clusters with the planned patches.
+func (o *applyOpts) run(ctx context.Context) error {
+	key, err := loadSigningKey(o.signingKeyFile)
+	if err != nil {
+		return err
+	}
+	plan, err := loadPlan(o.planFile, key)
+	if err != nil {
+		return err
+	}
+	if err := validatePlanAge(plan, o.maxPlanAge, time.Now()); err != nil {
+		return err
+	}
+
+	slog.Info("Verified plan signature", "file", o.planFile, "createdAt", plan.CreatedAt,
+		"createdBy", plan.CreatedBy, "clusters", len(plan.Clusters))
+
+	m := &o.migrate
+	m.serviceClusterID = plan.ServiceClusterID
+	m.mgmtClusterID = plan.MgmtClusterID
+	m.environment = plan.Environment
+	m.includePaused = plan.IncludePaused
+	m.profile = plan.Profile.orDefault()
+	m.plan = plan
+
+	return m.run(ctx)
+}
+
+// loadSigningKey reads the plan signing key from path.
+func loadSigningKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("--signing-key-file is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %v", err)
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) < minSigningKeyLength {
+		return nil, fmt.Errorf("signing key in %s must be at least %d bytes", path, minSigningKeyLength)
+	}
+	return key, nil
+}
+
+// sign returns the HMAC-SHA256 of the plan content, excluding the signature itself.
+func (p *migrationPlan) sign(key []byte) (string, error) {
+	unsigned := *p
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan: %v", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// loadPlan reads a plan file and verifies its version and signature.
+func loadPlan(path string, key []byte) (*migrationPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %v", err)
+	}
+
+	plan := &migrationPlan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %v", path, err)
+	}
+	if plan.Version != planVersion {
+		return nil, fmt.Errorf("unsupported plan version %d in %s, expected %d", plan.Version, path, planVersion)
+	}
+	if plan.SignatureType != planSignatureType {
+		return nil, fmt.Errorf("unsupported plan signature type '%s' in %s", plan.SignatureType, path)
+	}
+
+	expected, err := plan.sign(key)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(expected), []byte(plan.Signature)) {
+		return nil, fmt.Errorf("plan signature in %s is invalid: the plan was modified or signed with a different key", path)
+	}
+
+	return plan, nil
+}
+
+// validatePlanAge checks the plan was created no longer than maxAge before now.
+func validatePlanAge(plan *migrationPlan, maxAge time.Duration, now time.Time) error {
+	createdAt, err := time.Parse(time.RFC3339, plan.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("invalid plan created_at '%s': %v", plan.CreatedAt, err)
+	}
+	if age := now.Sub(createdAt); age > maxAge {
+		return fmt.Errorf("plan was created %s ago, older than --max-plan-age %s; create a new plan",
+			age.Round(time.Minute), maxAge)
+	}
+	return nil
+}
+
+// change returns the planned change for a cluster, or nil if the cluster is not in the plan.
+func (p *migrationPlan) change(clusterID string) *plannedChange {
+	for i := range p.Clusters {
+		if p.Clusters[i].ClusterID == clusterID {
+			return &p.Clusters[i]
+		}
+	}
+	return nil
+}
+
+// writePlan plans the ManifestWork patch of every candidate and writes the signed plan to the plan file.
+// Candidates whose ManifestWork cannot be read or is already up to date are left out of the plan.
+func (m *migrateOpts) writePlan(ctx context.Context, w io.Writer, candidates []hostedClusterAuditInfo) error {
+	plan := &migrationPlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now().UTC().Format(time.RFC3339),
+		CreatedBy:        m.operator,
+		ServiceClusterID: m.serviceClusterID,
+		MgmtClusterID:    m.mgmtClusterID,
+		MgmtClusterName:  m.mgmtClusterName,
+		Environment:      m.environment,
+		IncludePaused:    m.includePaused,
+		Profile:          m.profile.orDefault(),
+		Clusters:         []plannedChange{},
+		SignatureType:    planSignatureType,
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ClusterID < candidates[j].ClusterID
+	})
+
+	var skipped []string
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("interrupted while planning: %v", ctx.Err()))
+		}
+		change, err := m.planChange(ctx, c)
+		if err != nil {
+			slog.Warn("Leaving cluster out of the plan", "clusterID", c.ClusterID, "error", err)
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %v", c.ClusterName, c.ClusterID, err))
+			continue
+		}
+		if len(change.Changes) == 0 {
+			slog.Info("Leaving cluster out of the plan: ManifestWork already up to date", "clusterID", c.ClusterID)
+			continue
+		}
+		plan.Clusters = append(plan.Clusters, *change)
+	}
+
+	if len(plan.Clusters) == 0 {
+		fmt.Fprintln(w, "No ManifestWork changes to plan")
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no ManifestWork changes to plan"))
+	}
+
+	signature, err := plan.sign(m.signingKey)
+	if err != nil {
+		return err
+	}
+	plan.Signature = signature
+
+	err = output.WriteFile(m.planFile, false, func(out io.Writer, _ bool) error {
+		return output.JSON(out, plan)
+	})
+	if err != nil {
+		return err
+	}
+
+	printPlan(w, plan, skipped)
+	fmt.Fprintf(w, "Plan for %d clusters written to %s\n", len(plan.Clusters), m.planFile)
+	fmt.Fprintf(w, "After review, apply it with:\n  hcp-node-autoscaling apply --plan-file %s --signing-key-file %s --ticket <JIRA-ID>\n",
+		m.planFile, m.signingKeyFile)
+	return nil
+}
+
+// planChange reads a candidate's ManifestWork and returns the patch and annotation changes planned for it.
+func (m *migrateOpts) planChange(ctx context.Context, candidate hostedClusterAuditInfo) (*plannedChange, error) {
+	manifestWork, err := m.getManifestWork(ctx, candidate.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	return m.plannedChangeFor(manifestWork, candidate)
+}
+
+// plannedChangeFor returns the patch and annotation changes the migration profile makes to a ManifestWork.
+func (m *migrateOpts) plannedChangeFor(manifestWork *workv1.ManifestWork, candidate hostedClusterAuditInfo) (*plannedChange, error) {
+	index, manifestData, err := findHostedClusterManifest(manifestWork)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyProfilePatch(manifestWork, index, manifestData, m.profile); err != nil {
+		return nil, err
+	}
+
+	return &plannedChange{
+		ClusterID:    candidate.ClusterID,
+		ClusterName:  candidate.ClusterName,
+		Namespace:    candidate.Namespace,
+		ManifestWork: manifestWork.Namespace + "/" + manifestWork.Name,
+		Changes:      previewAnnotationChanges(manifestAnnotations(manifestData), m.profile),
+		Patch:        profileAnnotationOps(index, manifestData, m.profile),
+	}, nil
+}
+
+// printPlan prints the annotation changes of a plan and the candidates that were left out of it.
+func printPlan(w io.Writer, plan *migrationPlan, skipped []string) {
+	fmt.Fprintf(w, "\n=== Planned ManifestWork Annotation Changes (%d clusters) ===\n\n", len(plan.Clusters))
+
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "BEFORE", "AFTER", "ACTION"})
+	for _, c := range plan.Clusters {
+		for _, change := range c.Changes {
+			p.AddRow([]string{c.ClusterID, c.ClusterName, change.Annotation,
+				driftValue(change.Before), driftValue(change.After), change.Action})
+		}
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "WARNING: %d clusters were left out of the plan:\n", len(skipped))
+		for _, s := range skipped {
+			fmt.Fprintf(w, "  - %s\n", s)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// getCandidatesFromPlan returns the clusters of the plan that are still ready for migration according to
+// their live HostedCluster.
+func (m *migrateOpts) getCandidatesFromPlan(ctx context.Context) ([]hostedClusterAuditInfo, error) {
+	if m.plan.MgmtClusterID != m.mgmtClusterID {
+		return nil, fmt.Errorf("plan is for management cluster %s, not %s", m.plan.MgmtClusterID, m.mgmtClusterID)
+	}
+
+	reviewed := make([]hostedClusterAuditInfo, 0, len(m.plan.Clusters))
+	for _, c := range m.plan.Clusters {
+		reviewed = append(reviewed, hostedClusterAuditInfo{ClusterID: c.ClusterID, ClusterName: c.ClusterName, Namespace: c.Namespace})
+	}
+
+	slog.Info("Re-validating clusters from plan", "createdAt", m.plan.CreatedAt, "count", len(reviewed))
+	return m.revalidateCandidates(ctx, "plan", reviewed)
+}
+
+// applyPlannedPatch applies the planned JSON patch to a cluster's ManifestWork. The patch is only applied
+// if planning the ManifestWork again produces exactly the planned patch and annotation changes, so a
+// ManifestWork that changed since the plan was reviewed is never modified.
+func (m *migrateOpts) applyPlannedPatch(ctx context.Context, clusterID string) error {
+	planned := m.plan.change(clusterID)
+	if planned == nil {
+		return fmt.Errorf("cluster %s is not in the plan", clusterID)
+	}
+
+	manifestWork, err := m.getManifestWork(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	current, err := m.plannedChangeFor(manifestWork, hostedClusterAuditInfo{ClusterID: clusterID})
+	if err != nil {
+		return err
+	}
+	if !samePlannedChange(planned, current) {
+		return fmt.Errorf("ManifestWork %s changed since the plan was created; create a new plan", planned.ManifestWork)
+	}
+
+	patch, err := json.Marshal(planned.Patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode planned patch: %v", err)
+	}
+	if err := m.serviceClient.Patch(ctx, manifestWork, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to patch ManifestWork: %w", err)
+	}
+
+	return nil
+}
+
+// samePlannedChange reports whether two planned changes have the same patch and annotation changes.
+func samePlannedChange(a, b *plannedChange) bool {
+	encode := func(c *plannedChange) []byte {
+		data, _ := json.Marshal([]interface{}{c.ManifestWork, c.Changes, c.Patch})
+		return data
+	}
+	return bytes.Equal(encode(a), encode(b))
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testSigningKey = "0123456789abcdef0123456789abcdef"
+
+// TestLoadSigningKey verifies the signing key is trimmed and must be long enough.
+func TestLoadSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.key")
+	short := filepath.Join(dir, "short.key")
+	if err := os.WriteFile(valid, []byte(testSigningKey+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	if err := os.WriteFile(short, []byte("secret"), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	key, err := loadSigningKey(valid)
+	if err != nil || string(key) != testSigningKey {
+		t.Errorf("loadSigningKey() = %q, %v; want the trimmed key", key, err)
+	}
+	if _, err := loadSigningKey(short); err == nil {
+		t.Error("Expected an error for a short key")
+	}
+	if _, err := loadSigningKey(""); err == nil {
+		t.Error("Expected an error without a key file")
+	}
+}
+
+// TestPlanWriteAndApply plans a migration, verifies the signed plan round-trips, and applies it to the
+// ManifestWork. A plan is refused once it is edited, signed with another key, or its ManifestWork changes.
+func TestPlanWriteAndApply(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"})
+	candidate := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace}
+
+	newOpts := func(t *testing.T) (*migrateOpts, client.Client) {
+		serviceClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).Build()
+		return &migrateOpts{
+			mgmtClusterID:   "mc-id",
+			mgmtClusterName: "mgmt-cluster",
+			environment:     "production",
+			serviceClient:   serviceClient,
+			planFile:        filepath.Join(t.TempDir(), "plan.json"),
+			signingKeyFile:  "plan.key",
+			signingKey:      []byte(testSigningKey),
+		}, serviceClient
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		m, serviceClient := newOpts(t)
+		var out bytes.Buffer
+		if err := m.writePlan(context.Background(), &out, []hostedClusterAuditInfo{candidate}); err != nil {
+			t.Fatalf("writePlan() error = %v", err)
+		}
+		for _, e := range []string{"hypershift.openshift.io/cluster-size-override", "remove", "Plan for 1 clusters written to"} {
+			if !strings.Contains(out.String(), e) {
+				t.Errorf("Expected plan output to contain %q, got:\n%s", e, out.String())
+			}
+		}
+
+		plan, err := loadPlan(m.planFile, []byte(testSigningKey))
+		if err != nil {
+			t.Fatalf("loadPlan() error = %v", err)
+		}
+		if len(plan.Clusters) != 1 || len(plan.Clusters[0].Changes) != 3 {
+			t.Fatalf("Expected one cluster with three annotation changes, got %+v", plan.Clusters)
+		}
+
+		m.plan = plan
+		if err := m.applyPlannedPatch(context.Background(), "a1"); err != nil {
+			t.Fatalf("applyPlannedPatch() error = %v", err)
+		}
+
+		manifestWork := &workv1.ManifestWork{}
+		if err := serviceClient.Get(context.Background(), client.ObjectKey{Namespace: "mgmt-cluster", Name: "a1"}, manifestWork); err != nil {
+			t.Fatalf("Failed to get ManifestWork: %v", err)
+		}
+		_, manifestData, err := findHostedClusterManifest(manifestWork)
+		if err != nil {
+			t.Fatalf("findHostedClusterManifest() error = %v", err)
+		}
+		annotations := manifestAnnotations(manifestData)
+		if _, ok := annotations["hypershift.openshift.io/cluster-size-override"]; ok {
+			t.Error("Expected cluster-size-override to be removed")
+		}
+		if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" ||
+			annotations["hypershift.openshift.io/topology"] != "dedicated-request-serving-components" {
+			t.Errorf("Expected autoscaling and topology annotations to be set, got %v", annotations)
+		}
+
+		if err := m.applyPlannedPatch(context.Background(), "a1"); err == nil ||
+			!strings.Contains(err.Error(), "changed since the plan was created") {
+			t.Errorf("Expected an applied plan to be refused the second time, got %v", err)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		m, _ := newOpts(t)
+		if err := m.writePlan(context.Background(), &bytes.Buffer{}, []hostedClusterAuditInfo{candidate}); err != nil {
+			t.Fatalf("writePlan() error = %v", err)
+		}
+
+		if _, err := loadPlan(m.planFile, []byte(strings.Repeat("x", minSigningKeyLength))); err == nil {
+			t.Error("Expected a plan signed with another key to be refused")
+		}
+
+		data, err := os.ReadFile(m.planFile)
+		if err != nil {
+			t.Fatalf("Failed to read plan: %v", err)
+		}
+		edited := strings.Replace(string(data), `"value": "true"`, `"value": "false"`, 1)
+		if edited == string(data) {
+			t.Fatalf("Expected the plan to contain the ensured annotation value, got:\n%s", data)
+		}
+		if err := os.WriteFile(m.planFile, []byte(edited), 0o644); err != nil {
+			t.Fatalf("Failed to write plan: %v", err)
+		}
+		if _, err := loadPlan(m.planFile, []byte(testSigningKey)); err == nil || !strings.Contains(err.Error(), "signature") {
+			t.Errorf("Expected an edited plan to be refused, got %v", err)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		m, _ := newOpts(t)
+		m.profile = &migrationProfile{Name: "noop", Ensure: map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"}}
+		err := m.writePlan(context.Background(), &bytes.Buffer{}, []hostedClusterAuditInfo{candidate})
+		if ExitCode(err) != exitNothingToDo {
+			t.Errorf("Expected nothing to do, got %v", err)
+		}
+		if _, err := os.Stat(m.planFile); !os.IsNotExist(err) {
+			t.Errorf("Expected no plan file to be written, got %v", err)
+		}
+	})
+}
+
+// TestValidatePlanAge verifies plans older than the maximum age are refused.
+func TestValidatePlanAge(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		createdAt   string
+		expectError bool
+	}{
+		{name: "fresh", createdAt: "2025-06-01T10:00:00Z"},
+		{name: "stale", createdAt: "2025-05-30T12:00:00Z", expectError: true},
+		{name: "invalid", createdAt: "yesterday", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePlanAge(&migrationPlan{CreatedAt: tt.createdAt}, defaultMaxPlanAge, now)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validatePlanAge() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	checkOCMAuth             = "OCM authentication"
+	checkMgmtCluster         = "Management cluster"
+	checkMgmtClusterAccess   = "Backplane access to management cluster"
+	checkMgmtAutoscaling     = "Management cluster autoscaling"
+	checkServiceCluster      = "Service cluster"
+	checkServiceAccess       = "Backplane access to service cluster"
+	checkManifestWorkUpdates = "ManifestWork update permission"
+
+	preflightPassed  = "passed"
+	preflightFailed  = "failed"
+	preflightSkipped = "skipped"
+	preflightWarning = "warning"
+)
+
+const (
+	fixOCMAuth = "Log in with `ocm login --use-auth-code` for the OCM environment of the clusters; " +
+		"an expired token is refreshed by logging in again"
+	fixMgmtCluster = "Check the management cluster ID or name, and that OCM is logged in to the environment it belongs to"
+	fixBackplane   = "Check that `ocm backplane login %s` works: the backplane config (~/.config/backplane/config.json) " +
+		"must exist and the backplane API must be reachable (VPN or proxy)"
+	fixServiceCluster      = "Pass --service-cluster-id with the parent service cluster of the management cluster"
+	fixManifestWorkUpdates = "Check that your account can elevate to backplane-cluster-admin on the service cluster"
+)
+
+// preflightCheck is the outcome of one preflight check and, when it failed, how to fix it.
+type preflightCheck struct {
+	Name   string
+	Status string
+	Detail string
+	Fix    string
+}
+
+// preflightOpts selects the access checked before a run. Checks run in order and a failed check skips
+// the checks after it, since they depend on it. A check that only warns does not.
+type preflightOpts struct {
+	mgmtClusterID    string
+	serviceClusterID string
+
+	// mgmtAutoscaling checks that the management cluster can scale out request serving nodes.
+	mgmtAutoscaling bool
+	// expectedClusters is the number of clusters the request serving machine pools must have room for.
+	expectedClusters int
+
+	// serviceCluster checks that the service cluster is resolved and its ManifestWorks can be read.
+	serviceCluster bool
+	// manifestWorkUpdates checks, with an elevated client, that ManifestWorks can be updated.
+	manifestWorkUpdates bool
+	// elevationReason is the reason of the elevation used to check ManifestWork updates.
+	elevationReason string
+
+	kubeconfigs kubeconfigOverrides
+	clients     clientfactory.Factory
+	session     *runSession
+}
+
+// NewPreflightCmd creates the preflight subcommand for checking access before an audit or migration.
+func NewPreflightCmd() *cobra.Command {
+	opts := &preflightOpts{mgmtAutoscaling: true, serviceCluster: true, manifestWorkUpdates: true, elevationReason: defaultElevationReason}
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check OCM login, backplane access and ManifestWork permissions before a migration",
+		Long: `Check everything a migration needs before running it:
+- OCM authentication
+- The management cluster exists and is a management cluster
+- Backplane access to the management cluster and permission to list HostedClusters
+- The management cluster's cluster autoscaler and request serving machine pools can scale out (warning only)
+- The parent service cluster and backplane access to its ManifestWorks
+- Permission to update and patch ManifestWorks with backplane cluster-admin elevation
+
+Failed checks and warnings are reported with how to fix them.`,
+		Example: `
+  # Check access for a migration, discovering the service cluster
+  hcp-node-autoscaling preflight --mgmt-cluster-id mgmt-cluster-123
+
+  # Also check the request serving machine pools have room for 30 more clusters
+  hcp-node-autoscaling preflight --mgmt-cluster-id mgmt-cluster-123 --expected-clusters 30`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID to check")
+	cmd.Flags().StringVar(&opts.serviceClusterID, "service-cluster-id", "",
+		"The service cluster ID where ManifestWork resources exist (default: discovered from the management cluster)")
+	cmd.Flags().StringVar(&opts.elevationReason, "elevation-reason", defaultElevationReason,
+		"Reason recorded on the backplane elevation used to check ManifestWork updates")
+	cmd.Flags().IntVar(&opts.expectedClusters, "expected-clusters", 0,
+		"Warn unless the request serving machine pools have room for this many more clusters (default: one per size class)")
+	opts.kubeconfigs.addFlags(cmd, true)
+	_ = cmd.MarkFlagRequired("mgmt-cluster-id")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run executes the preflight subcommand and prints every check.
+func (p *preflightOpts) run(ctx context.Context) error {
+	if err := utils.IsValidClusterKey(p.mgmtClusterID); err != nil {
+		return err
+	}
+	if p.serviceClusterID != "" {
+		if err := utils.IsValidClusterKey(p.serviceClusterID); err != nil {
+			return fmt.Errorf("invalid service cluster ID: %v", err)
+		}
+	}
+	if err := p.kubeconfigs.validate(); err != nil {
+		return err
+	}
+	if p.expectedClusters < 0 {
+		return fmt.Errorf("invalid expected clusters %d: must not be negative", p.expectedClusters)
+	}
+
+	checks := p.check(ctx)
+	printPreflightChecks(os.Stdout, checks)
+	return preflightError(checks)
+}
+
+// runPreflight runs the preflight checks at the start of another subcommand and prints only the checks
+// that failed or warned.
+func runPreflight(ctx context.Context, p *preflightOpts) error {
+	slog.Info("Running preflight checks", "mgmtCluster", p.mgmtClusterID)
+	checks := p.check(ctx)
+	if err := preflightError(checks); err != nil {
+		printPreflightFailures(os.Stderr, checks)
+		return fmt.Errorf("%v; fix them or pass --skip-preflight", err)
+	}
+	printPreflightWarnings(os.Stderr, checks)
+	return nil
+}
+
+// checkNames returns the checks that run for the options, in order.
+func (p *preflightOpts) checkNames() []string {
+	names := []string{checkOCMAuth, checkMgmtCluster, checkMgmtClusterAccess}
+	if p.mgmtAutoscaling {
+		names = append(names, checkMgmtAutoscaling)
+	}
+	if p.serviceCluster {
+		names = append(names, checkServiceCluster, checkServiceAccess)
+		if p.manifestWorkUpdates {
+			names = append(names, checkManifestWorkUpdates)
+		}
+	}
+	return names
+}
+
+// preflightReport collects check results in the order given by names.
+type preflightReport struct {
+	names  []string
+	checks []preflightCheck
+}
+
+// pass records the next check as passed.
+func (r *preflightReport) pass(detail string) {
+	r.checks = append(r.checks, preflightCheck{Name: r.names[len(r.checks)], Status: preflightPassed, Detail: detail})
+}
+
+// warn records the next check as passed with a warning, which does not skip the checks after it.
+func (r *preflightReport) warn(detail, fix string) {
+	r.checks = append(r.checks, preflightCheck{Name: r.names[len(r.checks)], Status: preflightWarning, Detail: detail, Fix: fix})
+}
+
+// fail records the next check as failed, marks the remaining checks as skipped and returns all checks.
+func (r *preflightReport) fail(err error, fix string) []preflightCheck {
+	failed := r.names[len(r.checks)]
+	r.checks = append(r.checks, preflightCheck{Name: failed, Status: preflightFailed, Detail: err.Error(), Fix: fix})
+	for _, name := range r.names[len(r.checks):] {
+		r.checks = append(r.checks, preflightCheck{Name: name, Status: preflightSkipped, Detail: "requires " + failed})
+	}
+	return r.checks
+}
+
+// check runs the preflight checks and returns their results.
+func (p *preflightOpts) check(ctx context.Context) []preflightCheck {
+	r := &preflightReport{names: p.checkNames()}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return r.fail(err, fixOCMAuth)
+	}
+	defer conn.Close()
+	account, err := conn.AccountsMgmt().V1().CurrentAccount().Get().SendContext(ctx)
+	if err != nil {
+		return r.fail(err, fixOCMAuth)
+	}
+	r.pass(fmt.Sprintf("logged in to %s as %s", conn.URL(), account.Body().Username()))
+
+	mgmtCluster, err := utils.GetCluster(conn, p.mgmtClusterID)
+	if err != nil {
+		return r.fail(err, fixMgmtCluster)
+	}
+	isMC, err := utils.IsManagementCluster(mgmtCluster.ID())
+	if err == nil && !isMC {
+		err = fmt.Errorf("cluster %s is not a management cluster", mgmtCluster.ID())
+	}
+	if err != nil {
+		return r.fail(err, fixMgmtCluster)
+	}
+	r.pass(fmt.Sprintf("%s (%s)", mgmtCluster.Name(), mgmtCluster.ID()))
+
+	clientScheme, err := preflightScheme()
+	if err != nil {
+		return r.fail(err, "")
+	}
+	mgmtClient, err := p.kubeconfigs.clients(p.clients, p.session.apiRateLimit(), mgmtCluster.ID(), "").NewClient(mgmtCluster.ID(), clientScheme)
+	if err == nil {
+		err = checkHostedClusterAccess(ctx, mgmtClient)
+	}
+	if err != nil {
+		return r.fail(err, fmt.Sprintf(fixBackplane, mgmtCluster.ID()))
+	}
+	r.pass("can list HostedClusters")
+
+	if p.mgmtAutoscaling {
+		checkMgmtClusterAutoscaling(ctx, r, conn, mgmtClient, mgmtCluster.ID(), p.expectedClusters)
+	}
+
+	if !p.serviceCluster {
+		return r.checks
+	}
+
+	serviceCluster, err := resolveServiceCluster(conn, nil, p.serviceClusterID, mgmtCluster.Name())
+	if err != nil {
+		return r.fail(err, fixServiceCluster)
+	}
+	r.pass(fmt.Sprintf("%s (%s)", serviceCluster.Name(), serviceCluster.ID()))
+
+	clients := p.kubeconfigs.clients(p.clients, p.session.apiRateLimit(), mgmtCluster.ID(), serviceCluster.ID())
+	var serviceClient client.Client
+	if p.manifestWorkUpdates {
+		serviceClient, err = clients.NewElevatedClient(serviceCluster.ID(), clientScheme, conn, p.elevationReason)
+	} else {
+		serviceClient, err = clients.NewClient(serviceCluster.ID(), clientScheme)
+	}
+	if err == nil {
+		err = checkManifestWorkAccess(ctx, serviceClient, mgmtCluster.Name())
+	}
+	if err != nil {
+		return r.fail(err, fmt.Sprintf(fixBackplane, serviceCluster.ID()))
+	}
+	r.pass(fmt.Sprintf("can list ManifestWorks in namespace %s", mgmtCluster.Name()))
+
+	if !p.manifestWorkUpdates {
+		return r.checks
+	}
+
+	if err := checkManifestWorkUpdateAccess(ctx, serviceClient, mgmtCluster.Name()); err != nil {
+		return r.fail(err, fixManifestWorkUpdates)
+	}
+	r.pass(fmt.Sprintf("can update and patch ManifestWorks in namespace %s", mgmtCluster.Name()))
+
+	return r.checks
+}
+
+// preflightScheme returns the scheme for the clients used by the preflight checks.
+func preflightScheme() (*runtime.Scheme, error) {
+	return scheme.Cached(scheme.HyperShift, scheme.Core, scheme.Work, scheme.Authorization)
+}
+
+// checkHostedClusterAccess verifies the management cluster is reachable and HostedClusters can be listed.
+func checkHostedClusterAccess(ctx context.Context, c client.Client) error {
+	if err := c.List(ctx, &hypershiftv1beta1.HostedClusterList{}, client.Limit(1)); err != nil {
+		return fmt.Errorf("failed to list HostedClusters: %v", err)
+	}
+	return nil
+}
+
+// checkManifestWorkAccess verifies the service cluster is reachable and the ManifestWorks of a management
+// cluster can be listed.
+func checkManifestWorkAccess(ctx context.Context, c client.Client, namespace string) error {
+	if err := c.List(ctx, &workv1.ManifestWorkList{}, client.InNamespace(namespace), client.Limit(1)); err != nil {
+		return fmt.Errorf("failed to list ManifestWorks in namespace %s: %v", namespace, err)
+	}
+	return nil
+}
+
+// checkManifestWorkUpdateAccess verifies with SelfSubjectAccessReviews that ManifestWorks in namespace can
+// be updated and patched.
+func checkManifestWorkUpdateAccess(ctx context.Context, c client.Client, namespace string) error {
+	for _, verb := range []string{"update", "patch"} {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     "work.open-cluster-management.io",
+					Resource:  "manifestworks",
+				},
+			},
+		}
+		if err := c.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review access to %s ManifestWorks: %v", verb, err)
+		}
+		if !review.Status.Allowed {
+			detail := fmt.Sprintf("not allowed to %s ManifestWorks in namespace %s", verb, namespace)
+			if review.Status.Reason != "" {
+				detail += ": " + review.Status.Reason
+			}
+			return fmt.Errorf("%s", detail)
+		}
+	}
+	return nil
+}
+
+// preflightError returns an error counting the failed checks, or nil when none failed.
+func preflightError(checks []preflightCheck) error {
+	failed := 0
+	for _, c := range checks {
+		if c.Status == preflightFailed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d preflight checks failed", failed, len(checks))
+}
+
+// printPreflightChecks prints every check with its status, followed by how to fix the failed checks.
+func printPreflightChecks(w io.Writer, checks []preflightCheck) {
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CHECK", "STATUS", "DETAIL"})
+	for _, c := range checks {
+		p.AddRow([]string{c.Name, c.Status, c.Detail})
+	}
+	p.Flush()
+	printPreflightFixes(w, checks)
+}
+
+// printPreflightFailures prints the failed checks and how to fix them.
+func printPreflightFailures(w io.Writer, checks []preflightCheck) {
+	fmt.Fprintln(w, "Preflight checks failed:")
+	for _, c := range checks {
+		if c.Status == preflightFailed {
+			fmt.Fprintf(w, "  - %s: %s\n", c.Name, c.Detail)
+		}
+	}
+	printPreflightFixes(w, checks)
+}
+
+// printPreflightWarnings prints the checks that warned and how to fix them.
+func printPreflightWarnings(w io.Writer, checks []preflightCheck) {
+	if !slices.ContainsFunc(checks, func(c preflightCheck) bool { return c.Status == preflightWarning }) {
+		return
+	}
+	fmt.Fprintln(w, "Preflight warnings:")
+	for _, c := range checks {
+		if c.Status == preflightWarning {
+			fmt.Fprintf(w, "  - %s: %s\n", c.Name, c.Detail)
+		}
+	}
+	printPreflightFixes(w, checks)
+}
+
+// printPreflightFixes prints how to fix each failed or warning check.
+func printPreflightFixes(w io.Writer, checks []preflightCheck) {
+	needsFix := func(c preflightCheck) bool { return c.Status == preflightFailed || c.Status == preflightWarning }
+	if !slices.ContainsFunc(checks, needsFix) {
+		return
+	}
+	fmt.Fprintln(w, "\nTo fix:")
+	for _, c := range checks {
+		if needsFix(c) && c.Fix != "" {
+			fmt.Fprintf(w, "  - %s: %s\n", c.Name, c.Fix)
+		}
+	}
+}
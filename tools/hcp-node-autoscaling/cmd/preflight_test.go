@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestPreflightCheckNames verifies the management cluster autoscaling, service cluster and ManifestWork update
+// checks only run when requested.
+func TestPreflightCheckNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     preflightOpts
+		expected int
+	}{
+		{name: "audit", opts: preflightOpts{}, expected: 3},
+		{name: "audit with drift", opts: preflightOpts{serviceCluster: true}, expected: 5},
+		{name: "migrate", opts: preflightOpts{serviceCluster: true, manifestWorkUpdates: true}, expected: 6},
+		{name: "preflight", opts: preflightOpts{mgmtAutoscaling: true, serviceCluster: true, manifestWorkUpdates: true}, expected: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if names := tt.opts.checkNames(); len(names) != tt.expected {
+				t.Errorf("Expected %d checks, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+// TestPreflightReportFail verifies a failed check skips the checks after it and is counted by preflightError.
+func TestPreflightReportFail(t *testing.T) {
+	r := &preflightReport{names: []string{checkOCMAuth, checkMgmtCluster, checkMgmtClusterAccess}}
+	r.pass("logged in")
+	checks := r.fail(errors.New("cluster not found"), fixMgmtCluster)
+
+	expected := []string{preflightPassed, preflightFailed, preflightSkipped}
+	if len(checks) != len(expected) {
+		t.Fatalf("Expected %d checks, got %+v", len(expected), checks)
+	}
+	for i, status := range expected {
+		if checks[i].Status != status {
+			t.Errorf("Check %s status = %s, want %s", checks[i].Name, checks[i].Status, status)
+		}
+	}
+	if checks[2].Detail != "requires "+checkMgmtCluster {
+		t.Errorf("Unexpected skipped detail: %s", checks[2].Detail)
+	}
+
+	err := preflightError(checks)
+	if err == nil || err.Error() != "1 of 3 preflight checks failed" {
+		t.Errorf("Unexpected preflight error: %v", err)
+	}
+	if err := preflightError(checks[:1]); err != nil {
+		t.Errorf("Expected no error when all checks passed, got %v", err)
+	}
+}
+
+// TestCheckManifestWorkUpdateAccess verifies update and patch access is reviewed in the management cluster's namespace.
+func TestCheckManifestWorkUpdateAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		denied      string
+		expectError string
+	}{
+		{name: "allowed"},
+		{name: "patch denied", denied: "patch", expectError: "not allowed to patch ManifestWorks in namespace mgmt-cluster: no elevation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := authorizationv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed to add authorization scheme: %v", err)
+			}
+
+			var reviewed []string
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						review := obj.(*authorizationv1.SelfSubjectAccessReview)
+						attrs := review.Spec.ResourceAttributes
+						if attrs.Namespace != "mgmt-cluster" || attrs.Resource != "manifestworks" {
+							t.Errorf("Unexpected access review: %+v", attrs)
+						}
+						reviewed = append(reviewed, attrs.Verb)
+						review.Status.Allowed = attrs.Verb != tt.denied
+						if !review.Status.Allowed {
+							review.Status.Reason = "no elevation"
+						}
+						return nil
+					},
+				}).
+				Build()
+
+			err := checkManifestWorkUpdateAccess(context.Background(), c, "mgmt-cluster")
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if strings.Join(reviewed, ",") != "update,patch" {
+					t.Errorf("Expected update and patch to be reviewed, got %v", reviewed)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectError {
+				t.Errorf("Expected error %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+// TestPrintPreflightChecks verifies every check is printed and fixes are listed only for failed checks.
+func TestPrintPreflightChecks(t *testing.T) {
+	checks := []preflightCheck{
+		{Name: checkOCMAuth, Status: preflightPassed, Detail: "logged in"},
+		{Name: checkMgmtCluster, Status: preflightFailed, Detail: "token expired", Fix: fixOCMAuth},
+		{Name: checkMgmtClusterAccess, Status: preflightSkipped, Detail: "requires " + checkMgmtCluster},
+	}
+
+	var buf bytes.Buffer
+	printPreflightChecks(&buf, checks)
+	out := buf.String()
+	for _, name := range []string{checkOCMAuth, checkMgmtCluster, checkMgmtClusterAccess, "To fix:", fixOCMAuth} {
+		if !strings.Contains(out, name) {
+			t.Errorf("Expected output to contain %q, got:\n%s", name, out)
+		}
+	}
+
+	buf.Reset()
+	printPreflightChecks(&buf, checks[:1])
+	if strings.Contains(buf.String(), "To fix:") {
+		t.Errorf("Expected no fixes when all checks passed, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// migrationProfile declares the HostedCluster annotations a migration ensures and removes, and the
+// rules used to categorize clusters. A nil *migrationProfile behaves as defaultProfile.
+type migrationProfile struct {
+	Name string `json:"name" yaml:"name"`
+	// Ensure maps each annotation the migration sets to its target value.
+	Ensure map[string]string `json:"ensure" yaml:"ensure"`
+	// Remove lists annotations the migration deletes from the HostedCluster manifest.
+	Remove []string `json:"remove,omitempty" yaml:"remove"`
+	// Rules are evaluated in order and the first match determines the category. Clusters matching no
+	// rule are ready for migration. When empty, rules are derived from Ensure and Remove.
+	Rules []categoryRule `json:"rules,omitempty" yaml:"rules"`
+}
+
+// categoryRule assigns a category to clusters whose annotations match. A rule matches when any of
+// the AnyPresent annotations is set, or when every AllMatch annotation has the given value.
+type categoryRule struct {
+	Category   string            `json:"category" yaml:"category"`
+	AnyPresent []string          `json:"anyPresent,omitempty" yaml:"anyPresent"`
+	AllMatch   map[string]string `json:"allMatch,omitempty" yaml:"allMatch"`
+}
+
+// defaultProfile enables resource-based control plane autoscaling on the dedicated request serving topology
+// it requires, and treats clusters with a size override as needing manual annotation removal.
+var defaultProfile = &migrationProfile{
+	Name: "default",
+	Ensure: map[string]string{
+		autoscalingAnnotation: "true",
+		topologyAnnotation:    dedicatedTopology,
+	},
+	Remove: []string{
+		sizeOverrideAnnotation,
+	},
+	Rules: []categoryRule{
+		{
+			Category:   "needs-removal",
+			AnyPresent: []string{sizeOverrideAnnotation},
+		},
+		{
+			Category: "already-configured",
+			AllMatch: map[string]string{autoscalingAnnotation: "true", topologyAnnotation: dedicatedTopology},
+		},
+	},
+}
+
+// loadProfile reads a migration profile from a YAML file, or returns the default profile when path is empty.
+func loadProfile(path string) (*migrationProfile, error) {
+	if path == "" {
+		return defaultProfile, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %v", err)
+	}
+
+	profile := &migrationProfile{}
+	if err := yaml.UnmarshalStrict(data, profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %v", path, err)
+	}
+
+	if err := profile.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %v", path, err)
+	}
+
+	if profile.Name == "" {
+		profile.Name = path
+	}
+
+	return profile, nil
+}
+
+// validate checks the profile declares at least one annotation to ensure and well-formed rules.
+func (p *migrationProfile) validate() error {
+	if len(p.Ensure) == 0 {
+		return fmt.Errorf("ensure must declare at least one annotation")
+	}
+
+	for _, key := range p.Remove {
+		if _, ok := p.Ensure[key]; ok {
+			return fmt.Errorf("annotation %s cannot be both ensured and removed", key)
+		}
+	}
+
+	validCategories := map[string]bool{"needs-removal": true, "ready-for-migration": true, "already-configured": true}
+	for i, rule := range p.Rules {
+		if !validCategories[rule.Category] {
+			return fmt.Errorf("rule %d: invalid category '%s'. Valid options: needs-removal, ready-for-migration, already-configured", i+1, rule.Category)
+		}
+		if len(rule.AnyPresent) == 0 && len(rule.AllMatch) == 0 {
+			return fmt.Errorf("rule %d: must set anyPresent or allMatch", i+1)
+		}
+	}
+
+	return nil
+}
+
+// orDefault returns the profile, or the default profile if it is nil.
+func (p *migrationProfile) orDefault() *migrationProfile {
+	if p == nil {
+		return defaultProfile
+	}
+	return p
+}
+
+// rules returns the categorization rules, deriving them from Ensure and Remove when none are declared.
+func (p *migrationProfile) rules() []categoryRule {
+	p = p.orDefault()
+	if len(p.Rules) > 0 {
+		return p.Rules
+	}
+
+	var rules []categoryRule
+	if len(p.Remove) > 0 {
+		rules = append(rules, categoryRule{Category: "needs-removal", AnyPresent: p.Remove})
+	}
+	return append(rules, categoryRule{Category: "already-configured", AllMatch: p.Ensure})
+}
+
+// categorize returns the migration category for a HostedCluster with the given annotations.
+func (p *migrationProfile) categorize(annotations map[string]string) string {
+	for _, rule := range p.rules() {
+		if rule.matches(annotations) {
+			return rule.Category
+		}
+	}
+	return "ready-for-migration"
+}
+
+// matches reports whether the annotations satisfy the rule.
+func (r categoryRule) matches(annotations map[string]string) bool {
+	for _, key := range r.AnyPresent {
+		if _, ok := annotations[key]; ok {
+			return true
+		}
+	}
+
+	if len(r.AllMatch) == 0 {
+		return false
+	}
+	for key, value := range r.AllMatch {
+		if actual, ok := annotations[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfiedBy reports whether the annotations have every ensured value and none of the removed annotations.
+func (p *migrationProfile) satisfiedBy(annotations map[string]string) bool {
+	p = p.orDefault()
+	for key, value := range p.Ensure {
+		if actual, ok := annotations[key]; !ok || actual != value {
+			return false
+		}
+	}
+	for _, key := range p.Remove {
+		if _, ok := annotations[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureKeys returns the ensured annotation keys in sorted order.
+func (p *migrationProfile) ensureKeys() []string {
+	p = p.orDefault()
+	keys := make([]string, 0, len(p.Ensure))
+	for key := range p.Ensure {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// annotationKeys returns every annotation managed by the profile: the ensured keys followed by the removed keys.
+func (p *migrationProfile) annotationKeys() []string {
+	return append(p.ensureKeys(), p.orDefault().Remove...)
+}
+
+// applyTo sets the ensured annotations and deletes the removed annotations in a decoded annotations map.
+func (p *migrationProfile) applyTo(annotations map[string]interface{}) {
+	p = p.orDefault()
+	for key, value := range p.Ensure {
+		annotations[key] = value
+	}
+	for _, key := range p.Remove {
+		delete(annotations, key)
+	}
+}
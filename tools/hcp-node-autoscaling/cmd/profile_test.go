@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadProfile verifies migration profiles are read from YAML and validated.
+func TestLoadProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectError  bool
+		expectedName string
+	}{
+		{
+			name: "valid profile",
+			content: `name: topology
+ensure:
+  hypershift.openshift.io/resource-based-cp-auto-scaling: "true"
+  hypershift.openshift.io/topology: dedicated-request-serving-components
+remove:
+  - hypershift.openshift.io/cluster-size-override
+rules:
+  - category: needs-removal
+    anyPresent:
+      - hypershift.openshift.io/cluster-size-override
+`,
+			expectedName: "topology",
+		},
+		{
+			name: "unknown field",
+			content: `ensure:
+  a: "true"
+ensures:
+  b: "true"
+`,
+			expectError: true,
+		},
+		{
+			name:        "no ensured annotations",
+			content:     "remove:\n  - a\n",
+			expectError: true,
+		},
+		{
+			name:        "annotation ensured and removed",
+			content:     "ensure:\n  a: \"true\"\nremove:\n  - a\n",
+			expectError: true,
+		},
+		{
+			name:        "invalid rule category",
+			content:     "ensure:\n  a: \"true\"\nrules:\n  - category: drifted\n    anyPresent: [b]\n",
+			expectError: true,
+		},
+		{
+			name:        "rule without matchers",
+			content:     "ensure:\n  a: \"true\"\nrules:\n  - category: needs-removal\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profile.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write profile: %v", err)
+			}
+
+			profile, err := loadProfile(path)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if profile.Name != tt.expectedName {
+				t.Errorf("Profile name = %s, want %s", profile.Name, tt.expectedName)
+			}
+		})
+	}
+
+	profile, err := loadProfile("")
+	if err != nil || profile != defaultProfile {
+		t.Errorf("Expected default profile without a path, got %v, %v", profile, err)
+	}
+}
+
+// TestProfileCategorize verifies declared and derived categorization rules.
+func TestProfileCategorize(t *testing.T) {
+	derived := &migrationProfile{
+		Name: "derived",
+		Ensure: map[string]string{
+			"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+			"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+		},
+		Remove: []string{"hypershift.openshift.io/cluster-size-override"},
+	}
+
+	tests := []struct {
+		name        string
+		profile     *migrationProfile
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "default profile size override",
+			annotations: map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"},
+			expected:    "needs-removal",
+		},
+		{
+			name: "default profile configured",
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+			},
+			expected: "already-configured",
+		},
+		{
+			name:        "default profile autoscaling without dedicated topology",
+			annotations: map[string]string{"hypershift.openshift.io/resource-based-cp-auto-scaling": "true"},
+			expected:    "ready-for-migration",
+		},
+		{
+			name:     "default profile no annotations",
+			expected: "ready-for-migration",
+		},
+		{
+			name:        "derived rules require every ensured annotation",
+			profile:     derived,
+			annotations: map[string]string{"hypershift.openshift.io/resource-based-cp-auto-scaling": "true"},
+			expected:    "ready-for-migration",
+		},
+		{
+			name:    "derived rules all ensured annotations set",
+			profile: derived,
+			annotations: map[string]string{
+				"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+				"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+			},
+			expected: "already-configured",
+		},
+		{
+			name:        "derived rules removed annotation present",
+			profile:     derived,
+			annotations: map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"},
+			expected:    "needs-removal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.profile.categorize(tt.annotations); result != tt.expected {
+				t.Errorf("categorize() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestProfileApplyTo verifies ensured annotations are set, removed annotations deleted and the result satisfies the profile.
+func TestProfileApplyTo(t *testing.T) {
+	annotations := map[string]interface{}{
+		"hypershift.openshift.io/cluster-size-override": "m5xl",
+		"other": "value",
+	}
+
+	defaultProfile.applyTo(annotations)
+
+	if annotations["hypershift.openshift.io/resource-based-cp-auto-scaling"] != "true" {
+		t.Errorf("Expected auto-scaling annotation to be set")
+	}
+	if _, ok := annotations["hypershift.openshift.io/cluster-size-override"]; ok {
+		t.Errorf("Expected size override annotation to be removed")
+	}
+	if annotations["other"] != "value" {
+		t.Errorf("Expected unrelated annotation to be kept")
+	}
+
+	applied := map[string]string{}
+	for k, v := range annotations {
+		applied[k] = v.(string)
+	}
+	if !defaultProfile.satisfiedBy(applied) {
+		t.Errorf("Expected applied annotations to satisfy the profile")
+	}
+	if defaultProfile.satisfiedBy(map[string]string{
+		"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+		"hypershift.openshift.io/cluster-size-override":          "m5xl",
+	}) {
+		t.Errorf("Expected removed annotation to fail the profile")
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressBarWidth = 30
+
+// statusLine writes log messages to w below a status line holding the progress bar. The status line is
+// cleared before each message is written and redrawn afterwards, so the two never interleave.
+type statusLine struct {
+	mu   sync.Mutex
+	w    io.Writer
+	line string
+
+	// progress enables progress bars. It is set when w is a terminal and --no-progress is not passed.
+	progress bool
+}
+
+// Write writes a log message above the status line.
+func (s *statusLine) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.line != "" {
+		fmt.Fprint(s.w, "\r\033[K")
+	}
+	n, err := s.w.Write(p)
+	if s.line != "" {
+		fmt.Fprint(s.w, s.line)
+	}
+	return n, err
+}
+
+// set replaces the status line. An empty line clears it.
+func (s *statusLine) set(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprint(s.w, "\r\033[K"+line)
+	s.line = line
+}
+
+// isTerminal reports whether f is a character device, such as an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressBar shows how many of the namespaces or clusters of a long-running loop are done, the estimated
+// time remaining and the item in progress. All methods are safe on a nil *progressBar, which shows nothing.
+type progressBar struct {
+	out   *statusLine
+	label string
+	total int
+	start time.Time
+	now   func() time.Time
+
+	mu      sync.Mutex
+	done    int
+	current string
+}
+
+// newProgressBar returns a progress bar for total items on out, or nil when out is nil or has progress
+// disabled.
+func newProgressBar(out *statusLine, label string, total int) *progressBar {
+	if out == nil || !out.progress || total == 0 {
+		return nil
+	}
+	p := &progressBar{out: out, label: label, total: total, start: time.Now(), now: time.Now}
+	p.render()
+	return p
+}
+
+// begin shows item as the one in progress.
+func (p *progressBar) begin(item string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.current = item
+	p.mu.Unlock()
+	p.render()
+}
+
+// finish counts one more item as done.
+func (p *progressBar) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done++
+	p.mu.Unlock()
+	p.render()
+}
+
+// close removes the progress bar from the status line.
+func (p *progressBar) close() {
+	if p == nil {
+		return
+	}
+	p.out.set("")
+}
+
+func (p *progressBar) render() {
+	p.out.set(p.String())
+}
+
+// String formats the progress bar, e.g. "Auditing [=====>      ] 37/400 9% ETA 4m12s ocm-production-abc".
+func (p *progressBar) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	filled := p.done * progressBarWidth / p.total
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	eta := "--"
+	if p.done > 0 {
+		elapsed := p.now().Sub(p.start)
+		eta = (elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)).Round(time.Second).String()
+	}
+
+	line := fmt.Sprintf("%s [%s] %d/%d %d%% ETA %s", p.label, bar, p.done, p.total, p.done*100/p.total, eta)
+	if p.current != "" && p.done < p.total {
+		line += " " + p.current
+	}
+	return line
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProgressBar verifies the count, percentage, ETA and current item of the progress bar.
+func TestProgressBar(t *testing.T) {
+	out := &statusLine{w: &bytes.Buffer{}, progress: true}
+	p := newProgressBar(out, "Auditing", 4)
+	start := time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC)
+	p.start = start
+	p.now = func() time.Time { return start.Add(30 * time.Second) }
+
+	p.begin("ocm-production-a1")
+	if line := p.String(); !strings.Contains(line, "0/4 0% ETA -- ocm-production-a1") {
+		t.Errorf("Unexpected progress before any item is done: %q", line)
+	}
+
+	p.finish()
+	if line := p.String(); !strings.Contains(line, "1/4 25% ETA 1m30s") {
+		t.Errorf("Unexpected progress after one item: %q", line)
+	}
+	if out.line != p.String() {
+		t.Errorf("Status line = %q, want %q", out.line, p.String())
+	}
+
+	p.finish()
+	p.finish()
+	p.finish()
+	if line := p.String(); !strings.HasPrefix(line, "Auditing ["+strings.Repeat("=", progressBarWidth)+"] 4/4 100% ETA 0s") {
+		t.Errorf("Unexpected progress when done: %q", line)
+	}
+
+	p.close()
+	if out.line != "" {
+		t.Errorf("Expected the status line to be cleared, got %q", out.line)
+	}
+}
+
+// TestProgressBarDisabled verifies no progress bar is shown with progress disabled and that a nil bar is safe.
+func TestProgressBarDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressBar(&statusLine{w: &buf}, "Migrating", 3)
+	if p != nil {
+		t.Fatal("Expected no progress bar with progress disabled")
+	}
+	p.begin("cluster-1")
+	p.finish()
+	p.close()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
+
+// TestStatusLineWrite verifies log messages clear the status line and redraw it after the message.
+func TestStatusLineWrite(t *testing.T) {
+	var buf bytes.Buffer
+	s := &statusLine{w: &buf, progress: true}
+	if _, err := s.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	s.set("Auditing 1/2")
+	buf.Reset()
+
+	if _, err := s.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\r\033[Ksecond\nAuditing 1/2"; buf.String() != expected {
+		t.Errorf("Write() output = %q, want %q", buf.String(), expected)
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"time"
+)
+
+const (
+	// migratedAtAnnotation and migrationRunIDAnnotation record, with --stamp-provenance, when and by which run
+	// the HostedCluster manifest was last patched by this tool.
+	migratedAtAnnotation     = "rosa-hcp-platform-tools/autoscaling-migrated-at"
+	migrationRunIDAnnotation = "rosa-hcp-platform-tools/autoscaling-migration-run-id"
+)
+
+// patchProfile returns the profile a cluster's patch applies. With --stamp-provenance it is a copy of the
+// migration profile that also sets the provenance annotations; verification and categorization keep using
+// the migration profile, so the stamp never makes a cluster look unmigrated.
+func (m *migrateOpts) patchProfile(clusterID string) *migrationProfile {
+	base := m.clusterProfile(clusterID)
+	if !m.stampProvenance {
+		return base
+	}
+
+	profile := *base.orDefault()
+	profile.Ensure = make(map[string]string, len(profile.Ensure)+2)
+	for key, value := range base.orDefault().Ensure {
+		profile.Ensure[key] = value
+	}
+	profile.Ensure[migratedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	profile.Ensure[migrationRunIDAnnotation] = m.session.runID()
+	return &profile
+}
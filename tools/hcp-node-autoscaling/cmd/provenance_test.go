@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestStampProvenance verifies --stamp-provenance adds the migrated-at and run ID annotations with both patch
+// strategies the fake client supports, without changing the migration profile, and that audit reads them back.
+func TestStampProvenance(t *testing.T) {
+	for _, strategy := range []string{"update", "json-patch"} {
+		t.Run(strategy, func(t *testing.T) {
+			scheme := testScheme(t)
+			hc := newTestHostedCluster("a1", nil)
+			mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestNamespace(hc.Namespace), hc.DeepCopy()).Build()
+			agent := &workAgent{mgmtClient: mgmtClient, delay: 10 * time.Millisecond}
+			t.Cleanup(agent.wait)
+			serviceClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).
+				WithInterceptorFuncs(agent.interceptors()).Build()
+
+			m := &migrateOpts{
+				mgmtClusterName: "mgmt-cluster",
+				mgmtClient:      mgmtClient,
+				serviceClient:   serviceClient,
+				patchStrategy:   strategy,
+				syncTimeout:     500 * time.Millisecond,
+				pollInterval:    10 * time.Millisecond,
+				stampProvenance: true,
+				session:         &runSession{id: "run-123"},
+			}
+			ctx := context.Background()
+			result := m.migrateCluster(ctx, hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace})
+			if result.Status != "success" {
+				t.Fatalf("migrateCluster() = %s: %s", result.Status, result.Error)
+			}
+			if _, ok := defaultProfile.Ensure[migratedAtAnnotation]; ok {
+				t.Fatal("Expected the migration profile to be left unchanged")
+			}
+
+			a := &auditOpts{environment: "production", mgmtClient: mgmtClient}
+			clusters, err := a.auditNamespace(ctx, hc.Namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(clusters) != 1 || clusters[0].Category != "already-configured" || clusters[0].MigrationRunID != "run-123" {
+				t.Fatalf("Expected an already configured cluster migrated by run run-123, got %+v", clusters)
+			}
+			if _, err := time.Parse(time.RFC3339, clusters[0].MigratedAt); err != nil {
+				t.Errorf("Expected an RFC3339 migrated-at time, got %q", clusters[0].MigratedAt)
+			}
+		})
+	}
+
+	m := &migrateOpts{}
+	if profile := m.patchProfile("a1"); profile != nil {
+		t.Errorf("Expected the migration profile without --stamp-provenance, got %+v", profile)
+	}
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"io"
+	"os"
+)
+
+// infoOut returns where output meant for an operator watching the run is written: stdout, stderr when stdout
+// carries the --output jsonl event stream, or nowhere with --quiet.
+func (s *runSession) infoOut() io.Writer {
+	switch {
+	case s == nil:
+		return os.Stdout
+	case s.quiet:
+		return io.Discard
+	case s.eventsOnStdout:
+		return s.stderr
+	default:
+		return os.Stdout
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestQuiet verifies --quiet logs only errors, disables progress bars and discards operator output, and that
+// migrate requires --skip-confirmation with it.
+func TestQuiet(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	logger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(logger) })
+
+	cmd := &cobra.Command{Use: "migrate", RunE: func(*cobra.Command, []string) error { return nil }}
+	s := addRunSession(cmd)
+	cmd.SetArgs([]string{"--quiet", "--log-level", "debug"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if slog.Default().Enabled(context.Background(), slog.LevelWarn) || !slog.Default().Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected only errors to be logged with --quiet")
+	}
+	if s.stderr.progress {
+		t.Error("Expected progress bars to be disabled with --quiet")
+	}
+	if s.infoOut() != io.Discard {
+		t.Error("Expected operator output to be discarded with --quiet")
+	}
+
+	m := &migrateOpts{
+		mgmtClusterID: "mgmt-123",
+		ticket:        "OHSS-12345",
+		environment:   "production",
+		syncTimeout:   defaultSyncTimeout,
+		pollInterval:  defaultPollInterval,
+		maxInFlight:   1,
+		patchStrategy: "update",
+		session:       s,
+	}
+	if err := m.initialize(context.Background()); err == nil || !strings.Contains(err.Error(), "--quiet requires --skip-confirmation") {
+		t.Errorf("Expected --quiet to require --skip-confirmation, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/spf13/cobra"
+)
+
+// Default client-side rate limit of the Kubernetes clients. They match the client-go defaults, which keep a
+// fleet audit well within the API priority and fairness share of a management cluster.
+const (
+	defaultQPS   = 5
+	defaultBurst = 10
+
+	maxQPS   = 200
+	maxBurst = 400
+)
+
+// addRateLimitFlags registers the --qps and --burst flags.
+func addRateLimitFlags(cmd *cobra.Command, limit *clientfactory.RateLimit) {
+	cmd.Flags().Float32Var(&limit.QPS, "qps", defaultQPS,
+		"Maximum sustained requests per second sent to each management and service cluster API server")
+	cmd.Flags().IntVar(&limit.Burst, "burst", defaultBurst,
+		"Maximum requests sent at once to each management and service cluster API server, above --qps")
+}
+
+// validateRateLimit checks that the rate limit is within sane bounds.
+func validateRateLimit(limit clientfactory.RateLimit) error {
+	if limit.QPS <= 0 || limit.QPS > maxQPS {
+		return fmt.Errorf("invalid QPS %v: must be greater than 0 and at most %d", limit.QPS, maxQPS)
+	}
+	if limit.Burst < 1 || limit.Burst > maxBurst {
+		return fmt.Errorf("invalid burst %d: must be between 1 and %d", limit.Burst, maxBurst)
+	}
+	return nil
+}
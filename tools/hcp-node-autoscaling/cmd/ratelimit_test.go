@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+)
+
+// TestValidateRateLimit verifies the bounds of --qps and --burst.
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   clientfactory.RateLimit
+		wantErr bool
+	}{
+		{"defaults", clientfactory.RateLimit{QPS: defaultQPS, Burst: defaultBurst}, false},
+		{"fractional qps", clientfactory.RateLimit{QPS: 0.5, Burst: 1}, false},
+		{"maximum", clientfactory.RateLimit{QPS: maxQPS, Burst: maxBurst}, false},
+		{"zero qps", clientfactory.RateLimit{QPS: 0, Burst: defaultBurst}, true},
+		{"negative qps", clientfactory.RateLimit{QPS: -1, Burst: defaultBurst}, true},
+		{"qps too high", clientfactory.RateLimit{QPS: maxQPS + 1, Burst: defaultBurst}, true},
+		{"zero burst", clientfactory.RateLimit{QPS: defaultQPS, Burst: 0}, true},
+		{"burst too high", clientfactory.RateLimit{QPS: defaultQPS, Burst: maxBurst + 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRateLimit(tt.limit); (err != nil) != tt.wantErr {
+				t.Errorf("validateRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestClientsRateLimit verifies the default backplane factory and kubeconfig clients are rate limited by
+// --qps and --burst.
+func TestClientsRateLimit(t *testing.T) {
+	limit := clientfactory.RateLimit{QPS: 2, Burst: 4}
+
+	backplane, ok := kubeconfigOverrides{}.clients(nil, limit, "mgmt-123", "svc-123").(clientfactory.Backplane)
+	if !ok || backplane.RateLimit != limit {
+		t.Errorf("Expected the backplane factory with the rate limit, got %#v", backplane)
+	}
+
+	kubeconfig, ok := kubeconfigOverrides{mgmt: "mgmt.kubeconfig"}.clients(nil, limit, "mgmt-123", "svc-123").(*clientfactory.Kubeconfig)
+	if !ok || kubeconfig.RateLimit != limit {
+		t.Errorf("Expected kubeconfig clients with the rate limit, got %#v", kubeconfig)
+	}
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// reportSection is one table of the markdown and HTML audit reports.
+type reportSection struct {
+	Title       string
+	Description string
+	Header      []string
+	Rows        [][]string
+}
+
+// categoryCount is the number of clusters in an audit category, shown in the report summary.
+type categoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// reportCategoryCounts returns the summary counts of an audit report in the order of the text summary.
+func (a *auditOpts) reportCategoryCounts(results *auditResults) []categoryCount {
+	counts := []categoryCount{
+		{"Group A (Needs annotation removal)", len(results.NeedsLabelRemoval)},
+		{"Group B (Ready for migration)", len(results.ReadyForMigration)},
+		{"Already configured", len(results.AlreadyConfigured)},
+	}
+	if a.checkDrift {
+		counts = append(counts, categoryCount{"Drifted", len(results.Drifted)})
+	}
+	counts = append(counts, categoryCount{"Paused", len(results.Paused)})
+	if len(results.Deleting) > 0 {
+		counts = append(counts, categoryCount{"Deleting", len(results.Deleting)})
+	}
+	if len(results.Unmanaged) > 0 {
+		counts = append(counts, categoryCount{"Unmanaged", len(results.Unmanaged)})
+	}
+	if len(a.exclusions) > 0 {
+		counts = append(counts, categoryCount{"Excluded", len(excludedClusters(results))})
+	}
+	return append(counts, categoryCount{"Errors (namespaces)", len(results.Errors)})
+}
+
+// excludedClusters returns the clusters of every category that are on the exclusion list.
+func excludedClusters(results *auditResults) []hostedClusterAuditInfo {
+	var excluded []hostedClusterAuditInfo
+	for _, clusters := range [][]hostedClusterAuditInfo{results.NeedsLabelRemoval, results.ReadyForMigration, results.AlreadyConfigured, results.Drifted, results.Paused, results.Deleting, results.Unmanaged} {
+		for _, c := range clusters {
+			if c.Excluded {
+				excluded = append(excluded, c)
+			}
+		}
+	}
+	return excluded
+}
+
+// sortedByClusterID returns a copy of clusters sorted by cluster ID.
+func sortedByClusterID(clusters []hostedClusterAuditInfo) []hostedClusterAuditInfo {
+	sorted := append([]hostedClusterAuditInfo{}, clusters...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ClusterID < sorted[j].ClusterID
+	})
+	return sorted
+}
+
+// reportSections returns the non-empty category tables of an audit report, matching the text output.
+func (a *auditOpts) reportSections(results *auditResults) []reportSection {
+	var sections []reportSection
+	addClusters := func(title, description string, clusters []hostedClusterAuditInfo) {
+		if len(clusters) == 0 {
+			return
+		}
+		section := reportSection{
+			Title:       fmt.Sprintf("%s (%d clusters)", title, len(clusters)),
+			Description: description,
+			Header:      a.clusterTableHeader(),
+		}
+		for _, c := range sortedByClusterID(clusters) {
+			section.Rows = append(section.Rows, a.clusterTableRow(c))
+		}
+		sections = append(sections, section)
+	}
+
+	addClusters("Group A: Needs Annotation Removal",
+		"These clusters have the cluster-size-override annotation that must be removed.", results.NeedsLabelRemoval)
+	addClusters("Group B: Ready for Migration",
+		"These clusters can be immediately migrated to autoscaling.", results.ReadyForMigration)
+	if a.showsAlreadyConfigured() {
+		addClusters("Already Configured",
+			"These clusters already have autoscaling annotations set.", results.AlreadyConfigured)
+	}
+
+	if len(results.Drifted) > 0 {
+		section := reportSection{
+			Title:       fmt.Sprintf("Drifted (%d clusters)", len(results.Drifted)),
+			Description: "These clusters have ManifestWork annotations that differ from the live HostedCluster.",
+			Header:      []string{"CLUSTER ID", "CLUSTER NAME", "ANNOTATION", "MANIFESTWORK", "HOSTEDCLUSTER"},
+		}
+		for _, c := range sortedByClusterID(results.Drifted) {
+			for _, d := range c.Drift {
+				section.Rows = append(section.Rows,
+					[]string{c.ClusterID, c.ClusterName, d.Annotation, driftValue(d.ManifestWork), driftValue(d.HostedCluster)})
+			}
+		}
+		sections = append(sections, section)
+	}
+
+	if len(results.Paused) > 0 {
+		section := reportSection{
+			Title:       fmt.Sprintf("Paused (%d clusters)", len(results.Paused)),
+			Description: "These clusters are paused or have their control plane managed by hand and are skipped by migrate.",
+			Header:      []string{"CLUSTER ID", "CLUSTER NAME", "CURRENT SIZE", "REASON"},
+		}
+		for _, c := range sortedByClusterID(results.Paused) {
+			section.Rows = append(section.Rows, []string{c.ClusterID, c.ClusterName, c.CurrentSize, c.PausedReason})
+		}
+		sections = append(sections, section)
+	}
+
+	if len(results.Deleting) > 0 {
+		section := reportSection{
+			Title:       fmt.Sprintf("Deleting (%d clusters)", len(results.Deleting)),
+			Description: "These clusters are being deleted and are never migrated.",
+			Header:      []string{"CLUSTER ID", "CLUSTER NAME", "CURRENT SIZE", "DELETION TIMESTAMP"},
+		}
+		for _, c := range sortedByClusterID(results.Deleting) {
+			section.Rows = append(section.Rows, []string{c.ClusterID, c.ClusterName, c.CurrentSize, c.DeletionTimestamp})
+		}
+		sections = append(sections, section)
+	}
+
+	addClusters("Unmanaged",
+		"These clusters are outside the OCM namespaces, have no ManifestWork and are never migrated.", results.Unmanaged)
+
+	if excluded := excludedClusters(results); len(excluded) > 0 {
+		section := reportSection{
+			Title:       fmt.Sprintf("Excluded (%d clusters)", len(excluded)),
+			Description: "These clusters are on the exclusion list and will never be migrated.",
+			Header:      []string{"CLUSTER ID", "CLUSTER NAME", "CATEGORY", "REASON"},
+		}
+		for _, c := range sortedByClusterID(excluded) {
+			section.Rows = append(section.Rows, []string{c.ClusterID, c.ClusterName, c.Category, c.ExclusionReason})
+		}
+		sections = append(sections, section)
+	}
+
+	if len(results.Errors) > 0 {
+		section := reportSection{
+			Title:  fmt.Sprintf("Errors (%d)", len(results.Errors)),
+			Header: []string{"NAMESPACE", "ERROR"},
+		}
+		for _, e := range results.Errors {
+			section.Rows = append(section.Rows, []string{e.Namespace, e.Error})
+		}
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// markdownCell escapes a value for use in a markdown table cell.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+// markdownRow formats cells as a markdown table row.
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = markdownCell(c)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+// printMarkdownOutput writes audit results as markdown with a summary table and one table per category,
+// for pasting into Jira, Slack or GitHub. Appended reports are separated by a blank line.
+func (a *auditOpts) printMarkdownOutput(w io.Writer, results *auditResults, appending bool) error {
+	if appending {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "# Autoscaling Audit: %s\n\n", markdownCell(results.MgmtClusterID))
+	if results.GeneratedAt != "" {
+		fmt.Fprintf(w, "- Generated: %s\n", results.GeneratedAt)
+	}
+	if results.Environment != "" {
+		fmt.Fprintf(w, "- Environment: %s\n", results.Environment)
+	}
+	fmt.Fprintf(w, "- Total hosted clusters scanned: %d\n\n", results.TotalScanned)
+
+	if results.Partial {
+		fmt.Fprint(w, "> **Warning:** audit was interrupted; results are partial\n\n")
+	}
+
+	fmt.Fprint(w, "## Summary\n\n")
+	fmt.Fprintln(w, markdownRow([]string{"Category", "Clusters"}))
+	fmt.Fprintln(w, "| --- | ---: |")
+	for _, c := range a.reportCategoryCounts(results) {
+		fmt.Fprintln(w, markdownRow([]string{c.Category, fmt.Sprint(c.Count)}))
+	}
+
+	for _, section := range a.reportSections(results) {
+		fmt.Fprintf(w, "\n## %s\n\n", section.Title)
+		if section.Description != "" {
+			fmt.Fprintf(w, "%s\n\n", section.Description)
+		}
+		fmt.Fprintln(w, markdownRow(section.Header))
+		fmt.Fprintln(w, "|"+strings.Repeat(" --- |", len(section.Header)))
+		for _, row := range section.Rows {
+			fmt.Fprintln(w, markdownRow(row))
+		}
+	}
+
+	return nil
+}
+
+// htmlReport is the data rendered by the HTML report template.
+type htmlReport struct {
+	Results  *auditResults
+	Counts   []categoryCount
+	MaxCount int
+	Sections []reportSection
+}
+
+// BarWidth returns the width of a summary chart bar as a percentage of the largest count.
+func (r htmlReport) BarWidth(count int) int {
+	if r.MaxCount == 0 {
+		return 0
+	}
+	return count * 100 / r.MaxCount
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Autoscaling Audit: {{.Results.MgmtClusterID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.25em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+.meta { color: #59636e; }
+.warning { background: #fff8c5; border: 1px solid #d4a72c; padding: .5em 1em; }
+.chart { max-width: 48em; }
+.chart .row { display: flex; align-items: center; margin: .25em 0; }
+.chart .label { width: 18em; }
+.chart .bar { background: #0969da; height: 1.1em; min-width: 2px; margin-right: .5em; }
+table { border-collapse: collapse; margin-top: .5em; }
+th, td { border: 1px solid #d0d7de; padding: .3em .7em; text-align: left; font-size: .9em; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+</style>
+</head>
+<body>
+<h1>Autoscaling Audit: {{.Results.MgmtClusterID}}</h1>
+<p class="meta">
+{{- if .Results.GeneratedAt}}Generated {{.Results.GeneratedAt}} &middot; {{end -}}
+{{- if .Results.Environment}}Environment {{.Results.Environment}} &middot; {{end -}}
+{{.Results.TotalScanned}} hosted clusters scanned</p>
+{{- if .Results.Partial}}
+<p class="warning">Audit was interrupted; results are partial.</p>
+{{- end}}
+<h2>Summary</h2>
+<div class="chart">
+{{- range .Counts}}
+<div class="row"><span class="label">{{.Category}}</span><span class="bar" style="width: {{$.BarWidth .Count}}%"></span>{{.Count}}</div>
+{{- end}}
+</div>
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<table class="sortable">
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var body = table.tBodies[0];
+      Array.from(body.rows).sort(function (a, b) {
+        var x = a.cells[column].textContent, y = b.cells[column].textContent;
+        var cmp = x.localeCompare(y, undefined, { numeric: true });
+        return asc ? cmp : -cmp;
+      }).forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// printHTMLOutput writes audit results as a self-contained HTML report with a summary chart and sortable
+// tables per category.
+func (a *auditOpts) printHTMLOutput(w io.Writer, results *auditResults) error {
+	report := htmlReport{
+		Results:  results,
+		Counts:   a.reportCategoryCounts(results),
+		Sections: a.reportSections(results),
+	}
+	for _, c := range report.Counts {
+		report.MaxCount = max(report.MaxCount, c.Count)
+	}
+	return htmlReportTemplate.Execute(w, report)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// testReportResults returns audit results covering every report section.
+func testReportResults() *auditResults {
+	return &auditResults{
+		MgmtClusterID: "mc-id",
+		GeneratedAt:   "2026-02-03T10:00:00Z",
+		Environment:   "production",
+		TotalScanned:  4,
+		NeedsLabelRemoval: []hostedClusterAuditInfo{
+			{ClusterID: "b2", ClusterName: "beta", Namespace: "ocm-production-b2", CurrentSize: "large"},
+			{ClusterID: "a1", ClusterName: "alpha|prod", Namespace: "ocm-production-a1", CurrentSize: "small",
+				Category: "needs-removal", Excluded: true, ExclusionReason: "incident <123>"},
+		},
+		ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "c3", ClusterName: "gamma", Namespace: "ocm-production-c3"}},
+		Drifted: []hostedClusterAuditInfo{{ClusterID: "d4", ClusterName: "delta",
+			Drift: []annotationDrift{{Annotation: "hypershift.openshift.io/resource-based-cp-auto-scaling", ManifestWork: "true"}}}},
+		Errors: []auditError{{Namespace: "ocm-production-e5", Error: "no HostedCluster found"}},
+	}
+}
+
+// TestPrintMarkdownOutput verifies the markdown report has a summary table and a sorted, escaped table per section.
+func TestPrintMarkdownOutput(t *testing.T) {
+	a := &auditOpts{checkDrift: true, exclusions: map[string]string{"a1": "incident <123>"}}
+	var buf bytes.Buffer
+	if err := a.printMarkdownOutput(&buf, testReportResults(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"# Autoscaling Audit: mc-id\n",
+		"- Environment: production\n",
+		"| Group A (Needs annotation removal) | 2 |\n",
+		"| Drifted | 1 |\n",
+		"| Excluded | 1 |\n",
+		"## Group A: Needs Annotation Removal (2 clusters)\n",
+		"| CLUSTER ID | CLUSTER NAME | NAMESPACE | CURRENT SIZE |\n| --- | --- | --- | --- |\n| a1 | alpha\\|prod |",
+		"## Drifted (1 clusters)\n",
+		"| d4 | delta | hypershift.openshift.io/resource-based-cp-auto-scaling | true | <unset> |\n",
+		"| a1 | alpha\\|prod | needs-removal | incident <123> |\n",
+		"| ocm-production-e5 | no HostedCluster found |\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", e, out)
+		}
+	}
+	if strings.Contains(out, "Already Configured") {
+		t.Error("Expected empty sections to be omitted")
+	}
+	if strings.Index(out, "| a1 |") > strings.Index(out, "| b2 |") {
+		t.Error("Expected clusters to be sorted by cluster ID")
+	}
+}
+
+// TestPrintHTMLOutput verifies the HTML report contains the summary chart and sortable tables with escaped values.
+func TestPrintHTMLOutput(t *testing.T) {
+	a := &auditOpts{checkDrift: true, exclusions: map[string]string{"a1": "incident <123>"}}
+	var buf bytes.Buffer
+	if err := a.printHTMLOutput(&buf, testReportResults()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"<title>Autoscaling Audit: mc-id</title>",
+		`<span class="bar" style="width: 100%"></span>2`,
+		`<span class="bar" style="width: 50%"></span>1`,
+		"<h2>Group B: Ready for Migration (1 clusters)</h2>",
+		`<table class="sortable">`,
+		"<td>incident &lt;123&gt;</td>",
+		"<script>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected HTML to contain %q", e)
+		}
+	}
+	if strings.Contains(out, "incident <123>") {
+		t.Error("Expected cell values to be HTML escaped")
+	}
+}
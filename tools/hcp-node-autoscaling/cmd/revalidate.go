@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// stateChanged is the status of a migration result for a cluster that was skipped because it was no
+// longer ready for migration when it was about to be patched.
+const stateChanged = "state-changed"
+
+// revalidateBeforePatch re-fetches the HostedCluster of a candidate right before it is patched and
+// categorizes it again, so a cluster whose annotations or state changed since it was audited is not
+// patched from stale data. It returns why the cluster is no longer ready for migration, or "" when it
+// still is. Targets that were not categorized, such as the cluster of an annotate run, are not checked.
+func (m *migrateOpts) revalidateBeforePatch(ctx context.Context, info hostedClusterAuditInfo) (string, error) {
+	if info.Category != "ready-for-migration" {
+		return "", nil
+	}
+
+	hc, err := m.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("HostedCluster %s/%s no longer exists", info.Namespace, info.ClusterName), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get HostedCluster %s/%s: %w", info.Namespace, info.ClusterName, err)
+	}
+
+	auditOpts := &auditOpts{profile: m.clusterProfile(info.ClusterID), includePaused: m.includePaused}
+	if category := auditOpts.categorizeCluster(hc); category != "ready-for-migration" {
+		slog.Warn("Skipping cluster whose state changed since it was audited",
+			"clusterID", info.ClusterID, "category", category)
+		return fmt.Sprintf("category is now %s, not ready-for-migration", category), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestRevalidateBeforePatch verifies clusters whose category changed since the audit are reported as changed.
+func TestRevalidateBeforePatch(t *testing.T) {
+	ready := newTestHostedCluster("a1", nil)
+	configured := newTestHostedCluster("b2", map[string]string{
+		"hypershift.openshift.io/topology":                       "dedicated-request-serving-components",
+		"hypershift.openshift.io/resource-based-cp-auto-scaling": "true",
+	})
+	overridden := newTestHostedCluster("c3", map[string]string{"hypershift.openshift.io/cluster-size-override": "large"})
+	paused := newTestHostedCluster("d4", nil)
+	pausedUntil := "true"
+	paused.Spec.PausedUntil = &pausedUntil
+
+	m := &migrateOpts{
+		profile:    defaultProfile,
+		mgmtClient: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(ready, configured, overridden, paused).Build(),
+	}
+	candidate := func(id string) hostedClusterAuditInfo {
+		return hostedClusterAuditInfo{ClusterID: id, ClusterName: "cluster-" + id, Namespace: "ocm-production-" + id, Category: "ready-for-migration"}
+	}
+
+	tests := []struct {
+		name     string
+		info     hostedClusterAuditInfo
+		expected string
+	}{
+		{name: "still ready", info: candidate("a1"), expected: ""},
+		{name: "configured since audit", info: candidate("b2"), expected: "category is now already-configured"},
+		{name: "override added since audit", info: candidate("c3"), expected: "category is now needs-removal"},
+		{name: "paused since audit", info: candidate("d4"), expected: "category is now paused"},
+		{name: "deleted since audit", info: candidate("e5"), expected: "no longer exists"},
+		{name: "not categorized", info: hostedClusterAuditInfo{ClusterID: "b2", ClusterName: configured.Name, Namespace: configured.Namespace}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := m.revalidateBeforePatch(context.Background(), tt.info)
+			if err != nil {
+				t.Fatalf("revalidateBeforePatch() error = %v", err)
+			}
+			if tt.expected == "" && reason != "" || !strings.Contains(reason, tt.expected) {
+				t.Errorf("revalidateBeforePatch() = %q, want it to contain %q", reason, tt.expected)
+			}
+		})
+	}
+
+	// A changed cluster is skipped before the ManifestWork is touched, so no service client is needed.
+	result := m.migrateCluster(context.Background(), candidate("b2"))
+	if result.Status != stateChanged {
+		t.Errorf("migrateCluster() status = %s, want %s", result.Status, stateChanged)
+	}
+	if err := migrationExitError([]migrationResult{result}); err != nil {
+		t.Errorf("Expected a state-changed cluster not to fail the run, got %v", err)
+	}
+}
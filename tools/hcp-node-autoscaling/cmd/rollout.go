@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultSoakPeriod is how long a rollout stage is left to soak before its clusters are verified and the
+// next stage starts.
+const defaultSoakPeriod = 30 * time.Minute
+
+// rolloutPlan orders the management clusters of a --mgmt-cluster-ids run into stages by their OSD Fleet
+// Manager sector, so that a canary sector is migrated and verified before the rest of the fleet.
+type rolloutPlan struct {
+	order      []string
+	soakPeriod time.Duration
+}
+
+// addFlags registers the rollout ordering flags.
+func (p *rolloutPlan) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&p.order, "rollout-order", nil,
+		"Comma-separated fleet manager sectors to migrate one after the other, e.g. canary,main (requires --mgmt-cluster-ids)")
+	cmd.Flags().DurationVar(&p.soakPeriod, "soak-period", defaultSoakPeriod,
+		"Time to wait after each --rollout-order stage before verifying its clusters and starting the next stage")
+}
+
+// validate checks the sectors of --rollout-order and the soak period.
+func (p *rolloutPlan) validate(mgmtClusterIDs []string) error {
+	if len(p.order) == 0 {
+		return nil
+	}
+	if len(mgmtClusterIDs) == 0 {
+		return fmt.Errorf("--rollout-order requires --mgmt-cluster-ids")
+	}
+	seen := map[string]bool{}
+	for i, sector := range p.order {
+		sector = strings.TrimSpace(sector)
+		if sector == "" || seen[sector] {
+			return fmt.Errorf("invalid rollout order '%s'. Expected distinct, non-empty sectors", strings.Join(p.order, ","))
+		}
+		seen[sector] = true
+		p.order[i] = sector
+	}
+	if p.soakPeriod < 0 {
+		return fmt.Errorf("invalid soak period %v: must not be negative", p.soakPeriod)
+	}
+	return nil
+}
+
+// rolloutStage is the management clusters of one sector, migrated together.
+type rolloutStage struct {
+	sector string
+	runs   []*mgmtClusterRun
+}
+
+// rolloutStages groups the management cluster runs into stages in --rollout-order, each ordered by region
+// and management cluster name. Without --rollout-order all runs form a single stage. A management cluster in
+// a sector that is not listed is an error, so that no part of the fleet is migrated by accident.
+func rolloutStages(runs []*mgmtClusterRun, order []string) ([]rolloutStage, error) {
+	if len(order) == 0 {
+		return []rolloutStage{{runs: runs}}, nil
+	}
+
+	index := map[string]int{}
+	stages := make([]rolloutStage, len(order))
+	for i, sector := range order {
+		index[sector] = i
+		stages[i].sector = sector
+	}
+	for _, r := range runs {
+		i, ok := index[r.sector]
+		if !ok {
+			return nil, fmt.Errorf("management cluster %s is in sector '%s', which is not in --rollout-order %s",
+				r.opts.mgmtClusterName, r.sector, strings.Join(order, ","))
+		}
+		stages[i].runs = append(stages[i].runs, r)
+	}
+
+	nonEmpty := stages[:0]
+	for _, stage := range stages {
+		if len(stage.runs) == 0 {
+			slog.Info("No management clusters in rollout sector", "sector", stage.sector)
+			continue
+		}
+		sort.SliceStable(stage.runs, func(i, j int) bool {
+			if stage.runs[i].region != stage.runs[j].region {
+				return stage.runs[i].region < stage.runs[j].region
+			}
+			return stage.runs[i].opts.mgmtClusterName < stage.runs[j].opts.mgmtClusterName
+		})
+		nonEmpty = append(nonEmpty, stage)
+	}
+	return nonEmpty, nil
+}
+
+// migrateStages migrates the rollout stages one after the other with migrate. After every stage but the last
+// it waits for the soak period and verifies the migrated clusters. The rollout stops when a stage has failed
+// migrations or unhealthy clusters, and migrateStages returns why.
+func (m *migrateOpts) migrateStages(ctx context.Context, stages []rolloutStage, migrate func(r *mgmtClusterRun)) string {
+	for i, stage := range stages {
+		if len(stages) > 1 {
+			slog.Info("Starting rollout stage", "stage", i+1, "sector", stage.sector, "mgmtClusters", len(stage.runs))
+		}
+		forEachRun(stage.runs, migrate)
+		if i == len(stages)-1 || ctx.Err() != nil {
+			return ""
+		}
+
+		failed := 0
+		for _, r := range stage.runs {
+			for _, result := range r.results {
+				if result.Status == "failed" {
+					failed++
+				}
+			}
+		}
+		if failed > 0 {
+			return fmt.Sprintf("%d migrations failed in sector %s", failed, stage.sector)
+		}
+
+		slog.Info("Soaking rollout stage", "sector", stage.sector, "soakPeriod", m.rollout.soakPeriod)
+		if !sleepContext(ctx, m.rollout.soakPeriod) {
+			return ""
+		}
+		if problems := verifyStageHealth(ctx, stage.runs); len(problems) > 0 {
+			return fmt.Sprintf("%d migrated clusters in sector %s are unhealthy after the soak period: %s",
+				len(problems), stage.sector, strings.Join(problems, "; "))
+		}
+		slog.Info("Rollout stage verified", "sector", stage.sector)
+	}
+	return ""
+}
+
+// verifyStageHealth re-reads the HostedClusters migrated in a rollout stage and returns a problem for each one
+// that is no longer Available or no longer has the profile's annotations.
+func verifyStageHealth(ctx context.Context, runs []*mgmtClusterRun) []string {
+	var problems []string
+	for _, r := range runs {
+		candidates := map[string]hostedClusterAuditInfo{}
+		for _, c := range r.candidates {
+			candidates[c.ClusterID] = c
+		}
+		for _, result := range r.results {
+			if result.Status != "success" {
+				continue
+			}
+			info := candidates[result.ClusterID]
+			hc, err := r.opts.getHostedClusterFromMgmt(ctx, info.Namespace, info.ClusterName)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("%s: failed to get HostedCluster: %v", result.ClusterID, err))
+			case hostedClusterAvailable(hc) != "True":
+				problems = append(problems, fmt.Sprintf("%s: HostedCluster is not Available", result.ClusterID))
+			case !r.opts.hasRequiredAnnotations(hc):
+				problems = append(problems, fmt.Sprintf("%s: annotations are no longer set", result.ClusterID))
+			}
+		}
+	}
+	return problems
+}
+
+// displayRolloutPlan prints the rollout stages and the soak period between them.
+func displayRolloutPlan(w io.Writer, stages []rolloutStage, soakPeriod time.Duration) {
+	fmt.Fprintf(w, "\n=== Rollout Order: %d stages, %s soak between stages ===\n\n", len(stages), soakPeriod)
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"STAGE", "SECTOR", "REGION", "MGMT CLUSTER", "CANDIDATES"})
+	for i, stage := range stages {
+		for _, r := range stage.runs {
+			p.AddRow([]string{strconv.Itoa(i + 1), stage.sector, r.region, r.opts.mgmtClusterName, strconv.Itoa(len(r.candidates))})
+		}
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestRolloutPlanValidate verifies --rollout-order requires --mgmt-cluster-ids and distinct sectors.
+func TestRolloutPlanValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		order          []string
+		mgmtClusterIDs []string
+		soakPeriod     time.Duration
+		expectedErr    string
+	}{
+		{name: "no rollout order"},
+		{name: "valid", order: []string{"canary", " main"}, mgmtClusterIDs: []string{"a", "b"}},
+		{name: "single management cluster", order: []string{"canary"}, expectedErr: "requires --mgmt-cluster-ids"},
+		{name: "duplicate sector", order: []string{"canary", "canary"}, mgmtClusterIDs: []string{"a"}, expectedErr: "invalid rollout order"},
+		{name: "empty sector", order: []string{"canary", ""}, mgmtClusterIDs: []string{"a"}, expectedErr: "invalid rollout order"},
+		{name: "negative soak", order: []string{"canary"}, mgmtClusterIDs: []string{"a"}, soakPeriod: -time.Minute, expectedErr: "invalid soak period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &rolloutPlan{order: tt.order, soakPeriod: tt.soakPeriod}
+			err := p.validate(tt.mgmtClusterIDs)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for _, sector := range p.order {
+					if sector != strings.TrimSpace(sector) {
+						t.Errorf("Expected sector %q to be trimmed", sector)
+					}
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestRolloutStages verifies management clusters are grouped by sector in rollout order and ordered by region
+// and name within a stage.
+func TestRolloutStages(t *testing.T) {
+	run := func(name, sector, region string) *mgmtClusterRun {
+		return &mgmtClusterRun{opts: &migrateOpts{mgmtClusterName: name}, sector: sector, region: region}
+	}
+	runs := []*mgmtClusterRun{
+		run("hs-mc-4", "main", "us-east-1"),
+		run("hs-mc-3", "main", "eu-west-1"),
+		run("hs-mc-1", "canary", "us-east-1"),
+		run("hs-mc-2", "main", "eu-west-1"),
+	}
+
+	stages, err := rolloutStages(runs, []string{"canary", "empty", "main"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got []string
+	for _, stage := range stages {
+		var names []string
+		for _, r := range stage.runs {
+			names = append(names, r.opts.mgmtClusterName)
+		}
+		got = append(got, stage.sector+"="+strings.Join(names, ","))
+	}
+	expected := "canary=hs-mc-1 main=hs-mc-2,hs-mc-3,hs-mc-4"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("Expected stages %q, got %q", expected, strings.Join(got, " "))
+	}
+
+	if stages, err := rolloutStages(runs, nil); err != nil || len(stages) != 1 || len(stages[0].runs) != len(runs) {
+		t.Errorf("Expected a single stage without a rollout order, got %d stages (%v)", len(stages), err)
+	}
+
+	if _, err := rolloutStages(runs, []string{"canary"}); err == nil || !strings.Contains(err.Error(), "hs-mc-4 is in sector 'main'") {
+		t.Errorf("Expected an error for a sector missing from the rollout order, got %v", err)
+	}
+}
+
+// TestMigrateStages verifies the next stage only starts when the previous one migrated without failures and
+// its clusters are still healthy after the soak period.
+func TestMigrateStages(t *testing.T) {
+	autoscaling := defaultProfile.Ensure
+
+	tests := []struct {
+		name            string
+		canaryStatus    string
+		canaryAvailable metav1.ConditionStatus
+		canaryAnn       map[string]string
+		expectedHalt    string
+	}{
+		{name: "healthy canary", canaryStatus: "success", canaryAvailable: metav1.ConditionTrue, canaryAnn: autoscaling},
+		{name: "failed canary", canaryStatus: "failed", canaryAvailable: metav1.ConditionTrue, canaryAnn: autoscaling,
+			expectedHalt: "1 migrations failed in sector canary"},
+		{name: "unavailable canary", canaryStatus: "success", canaryAvailable: metav1.ConditionFalse, canaryAnn: autoscaling,
+			expectedHalt: "a1: HostedCluster is not Available"},
+		{name: "annotations reverted", canaryStatus: "success", canaryAvailable: metav1.ConditionTrue,
+			expectedHalt: "a1: annotations are no longer set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := newTestHostedCluster("a1", tt.canaryAnn)
+			hc.Status.Conditions = []metav1.Condition{{Type: string(hypershiftv1beta1.HostedClusterAvailable), Status: tt.canaryAvailable}}
+			mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(hc).Build()
+
+			canary := &mgmtClusterRun{
+				opts:       &migrateOpts{mgmtClusterName: "hs-mc-1", mgmtClient: mgmtClient},
+				candidates: []hostedClusterAuditInfo{{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace}},
+				sector:     "canary",
+			}
+			main := &mgmtClusterRun{
+				opts:       &migrateOpts{mgmtClusterName: "hs-mc-2"},
+				candidates: []hostedClusterAuditInfo{{ClusterID: "b2"}},
+				sector:     "main",
+			}
+			stages, err := rolloutStages([]*mgmtClusterRun{main, canary}, []string{"canary", "main"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var migrated []string
+			m := &migrateOpts{rollout: rolloutPlan{order: []string{"canary", "main"}, soakPeriod: 10 * time.Millisecond}}
+			halted := m.migrateStages(context.Background(), stages, func(r *mgmtClusterRun) {
+				migrated = append(migrated, r.opts.mgmtClusterName)
+				status := "success"
+				if r == canary {
+					status = tt.canaryStatus
+				}
+				r.results = []migrationResult{{ClusterID: r.candidates[0].ClusterID, Status: status}}
+			})
+
+			if tt.expectedHalt == "" {
+				if halted != "" || len(migrated) != 2 {
+					t.Errorf("Expected both stages to be migrated, got %v (halted: %s)", migrated, halted)
+				}
+				return
+			}
+			if !strings.Contains(halted, tt.expectedHalt) {
+				t.Errorf("Expected rollout to halt with %q, got %q", tt.expectedHalt, halted)
+			}
+			if len(migrated) != 1 || len(main.notStarted()) != 1 {
+				t.Errorf("Expected only the canary stage to be migrated, got %v", migrated)
+			}
+		})
+	}
+}
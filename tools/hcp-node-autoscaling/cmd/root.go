@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// Execute runs the hcp-node-autoscaling command with the process arguments and returns its exit code.
+func Execute() int {
+	rootCmd := NewRootCmd()
+
+	// The first SIGINT or SIGTERM cancels the context so that subcommands can stop starting new work
+	// and report partial results. Signal handling is then restored so a second Ctrl-C exits immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitCode(err)
+	}
+	return 0
+}
+
+// NewRootCmd creates the hcp-node-autoscaling command with all its subcommands, e.g. to register the whole
+// tool as a command group of osdctl. Each subcommand sets up its own run, so they can also be registered
+// on their own.
+func NewRootCmd() *cobra.Command {
+	helpExitCodes := false
+	rootCmd := &cobra.Command{
+		Use:   "hcp-node-autoscaling",
+		Short: "HCP node autoscaling audit and migration tool",
+		Long: `A tool for auditing and migrating hosted clusters on ROSA HCP management clusters
+for node autoscaling readiness.
+
+Use the audit subcommand to analyze clusters and the migrate subcommand to perform
+the actual migration.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if helpExitCodes {
+				printExitCodes(os.Stdout)
+				return nil
+			}
+			return cmd.Help()
+		},
+	}
+
+	rootCmd.Flags().BoolVar(&helpExitCodes, "help-exit-codes", false, "Print the exit codes returned by each subcommand")
+
+	rootCmd.AddCommand(NewAuditCmd())
+	rootCmd.AddCommand(NewMigrateCmd())
+	rootCmd.AddCommand(NewAuditNodePoolsCmd())
+	rootCmd.AddCommand(NewPreflightCmd())
+	rootCmd.AddCommand(NewPlanCmd())
+	rootCmd.AddCommand(NewApplyCmd())
+	rootCmd.AddCommand(NewAnnotateCmd())
+	rootCmd.AddCommand(NewDrainOverrideCmd())
+	rootCmd.AddCommand(NewStatsCmd())
+	rootCmd.AddCommand(NewVerifyCmd())
+	rootCmd.AddCommand(NewDoctorCmd())
+	for _, cmd := range rootCmd.Commands() {
+		registerClusterCompletions(cmd)
+	}
+	return rootCmd
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewRootCmd verifies the root command registers every exported subcommand with the flags every
+// subcommand has, and that subcommands built on their own for another CLI keep their flags.
+func TestNewRootCmd(t *testing.T) {
+	root := NewRootCmd()
+	var names []string
+	for _, c := range root.Commands() {
+		names = append(names, c.Name())
+	}
+	for _, want := range []string{"audit", "migrate", "audit-nodepools", "preflight", "plan", "apply", "annotate", "drain-override", "stats"} {
+		if !strings.Contains(" "+strings.Join(names, " ")+" ", " "+want+" ") {
+			t.Errorf("Expected subcommand %s, got %v", want, names)
+		}
+	}
+	for _, c := range root.Commands() {
+		if c.Flags().Lookup("run-id") == nil || c.Flags().Lookup("qps") == nil {
+			t.Errorf("Expected subcommand %s to have the --run-id and --qps flags", c.Name())
+		}
+	}
+
+	migrate := NewMigrateCmd()
+	if migrate.Flags().Lookup("mgmt-cluster-id") == nil || migrate.Flags().Lookup("config") == nil || migrate.Parent() != nil {
+		t.Errorf("Expected a standalone migrate command with its flags")
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// runIDPattern limits --run-id to characters that are safe in file names and metric label values.
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// resolveRunID returns the --run-id value, or a new random UUID when it is empty.
+func resolveRunID(value string) (string, error) {
+	if value == "" {
+		return uuid.NewString(), nil
+	}
+	if !runIDPattern.MatchString(value) {
+		return "", fmt.Errorf("invalid run ID '%s'. Expected up to 64 letters, digits, '.', '_' or '-'", value)
+	}
+	return value, nil
+}
+
+// printRunID prints the run ID line of a text report header.
+func printRunID(w io.Writer, id string) {
+	if id != "" {
+		fmt.Fprintf(w, "Run ID: %s\n", id)
+	}
+}
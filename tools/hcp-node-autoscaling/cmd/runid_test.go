@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestResolveRunID verifies a UUID is generated when --run-id is not set and that set values are validated.
+func TestResolveRunID(t *testing.T) {
+	generated, err := resolveRunID("")
+	if err != nil {
+		t.Fatalf("resolveRunID() error = %v", err)
+	}
+	if _, err := uuid.Parse(generated); err != nil {
+		t.Errorf("Expected a UUID, got %q", generated)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+	}{
+		{name: "pipeline build", value: "pipeline-1234.5"},
+		{name: "uuid", value: "3f2c1e4a-9b8d-4c7e-a6f5-0e1d2c3b4a59"},
+		{name: "leading dash", value: "-run", expectError: true},
+		{name: "slash", value: "runs/1", expectError: true},
+		{name: "too long", value: strings.Repeat("r", 65), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveRunID(tt.value)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("resolveRunID(%q) error = %v, expectError %v", tt.value, err, tt.expectError)
+			}
+			if err == nil && result != tt.value {
+				t.Errorf("resolveRunID(%q) = %q, want the value unchanged", tt.value, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"time"
+)
+
+// auditSchemaVersion is the version of the JSON and YAML audit report written by audit. Version 1 reports,
+// written before the schema was versioned, have no schema_version field and no summary, and are still read
+// by --diff, --from-audit and stats.
+const auditSchemaVersion = 2
+
+// auditSummary is the summary block of a version 2 audit report.
+type auditSummary struct {
+	// Categories is the number of clusters in each audit category, including empty ones.
+	Categories map[string]int `json:"categories" yaml:"categories"`
+
+	// Sizes is the number of clusters of each current size class, per category.
+	Sizes []sizeSummary `json:"sizes" yaml:"sizes"`
+
+	Excluded        int     `json:"excluded" yaml:"excluded"`
+	Errors          int     `json:"errors" yaml:"errors"`
+	DurationSeconds float64 `json:"duration_seconds" yaml:"duration_seconds"`
+	ToolVersion     string  `json:"tool_version" yaml:"tool_version"`
+}
+
+// sizeSummary is the number of clusters of a current size class, per category. Clusters without a size
+// label are counted under "<unset>".
+type sizeSummary struct {
+	Size       string         `json:"size" yaml:"size"`
+	Total      int            `json:"total" yaml:"total"`
+	Categories map[string]int `json:"categories" yaml:"categories"`
+}
+
+// categoryGroup is the clusters of an audit category.
+type categoryGroup struct {
+	category string
+	clusters []hostedClusterAuditInfo
+}
+
+// categoryGroups returns the clusters of every audit category, in the order of the text output.
+func categoryGroups(results *auditResults) []categoryGroup {
+	return []categoryGroup{
+		{"needs-removal", results.NeedsLabelRemoval},
+		{"ready-for-migration", results.ReadyForMigration},
+		{"already-configured", results.AlreadyConfigured},
+		{"drifted", results.Drifted},
+		{"paused", results.Paused},
+		{"deleting", results.Deleting},
+		{unmanagedCategory, results.Unmanaged},
+	}
+}
+
+// newAuditSummary summarizes the results of an audit that took duration.
+func newAuditSummary(results *auditResults, duration time.Duration) *auditSummary {
+	summary := &auditSummary{
+		Categories:      map[string]int{},
+		Sizes:           []sizeSummary{},
+		Errors:          len(results.Errors),
+		DurationSeconds: duration.Round(time.Millisecond).Seconds(),
+		ToolVersion:     toolVersion(),
+	}
+
+	bySize := map[string]*sizeSummary{}
+	for _, group := range categoryGroups(results) {
+		summary.Categories[group.category] = len(group.clusters)
+		for _, c := range group.clusters {
+			if c.Excluded {
+				summary.Excluded++
+			}
+			size := driftValue(c.CurrentSize)
+			s, ok := bySize[size]
+			if !ok {
+				s = &sizeSummary{Size: size, Categories: map[string]int{}}
+				bySize[size] = s
+			}
+			s.Categories[group.category]++
+			s.Total++
+		}
+	}
+
+	for _, s := range bySize {
+		summary.Sizes = append(summary.Sizes, *s)
+	}
+	sort.Slice(summary.Sizes, func(i, j int) bool { return summary.Sizes[i].Size < summary.Sizes[j].Size })
+	return summary
+}
+
+// toolVersion returns the module version the binary was built from, or its VCS revision for local builds.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return setting.Value[:12]
+		}
+	}
+	return "(devel)"
+}
+
+// checkSchemaVersion rejects audit reports written with a newer schema than this build reads. Reports
+// without a schema_version are version 1.
+func checkSchemaVersion(report *auditResults) error {
+	if report.SchemaVersion > auditSchemaVersion {
+		return fmt.Errorf("unsupported audit report schema version %d, expected at most %d; upgrade hcp-node-autoscaling",
+			report.SchemaVersion, auditSchemaVersion)
+	}
+	return nil
+}
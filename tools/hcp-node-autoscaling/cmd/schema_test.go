@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestNewAuditSummary verifies the per-category counts, the per-size histogram, the excluded and error counts
+// and the run duration of the summary block.
+func TestNewAuditSummary(t *testing.T) {
+	results := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{{ClusterID: "a1", CurrentSize: "large"}},
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "b1", CurrentSize: "small"},
+			{ClusterID: "b2", CurrentSize: "large", Excluded: true},
+		},
+		Paused: []hostedClusterAuditInfo{{ClusterID: "c1"}},
+		Errors: []auditError{{Namespace: "ocm-production-d1", Error: "no HostedCluster found"}},
+	}
+
+	summary := newAuditSummary(results, 1500*time.Millisecond)
+
+	expectedCategories := map[string]int{"needs-removal": 1, "ready-for-migration": 2, "already-configured": 0, "drifted": 0,
+		"paused": 1, "deleting": 0, "unmanaged": 0}
+	if !reflect.DeepEqual(summary.Categories, expectedCategories) {
+		t.Errorf("Categories = %v, want %v", summary.Categories, expectedCategories)
+	}
+	expectedSizes := []sizeSummary{
+		{Size: "<unset>", Total: 1, Categories: map[string]int{"paused": 1}},
+		{Size: "large", Total: 2, Categories: map[string]int{"needs-removal": 1, "ready-for-migration": 1}},
+		{Size: "small", Total: 1, Categories: map[string]int{"ready-for-migration": 1}},
+	}
+	if !reflect.DeepEqual(summary.Sizes, expectedSizes) {
+		t.Errorf("Sizes = %+v, want %+v", summary.Sizes, expectedSizes)
+	}
+	if summary.Excluded != 1 || summary.Errors != 1 || summary.DurationSeconds != 1.5 || summary.ToolVersion == "" {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+}
+
+// TestLoadAuditReportSchemaVersion verifies version 1 reports without a schema_version are still read, and
+// reports of a newer schema are rejected by --diff, --from-audit and stats.
+func TestLoadAuditReportSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"version 1", `{"mgmt_cluster_id":"mgmt-123","generated_at":"2026-10-15T10:00:00Z"}`, false},
+		{"version 2", `{"schema_version":2,"mgmt_cluster_id":"mgmt-123","generated_at":"2026-10-15T10:00:00Z","summary":{"errors":0}}`, false},
+		{"newer version", `{"schema_version":3,"mgmt_cluster_id":"mgmt-123","generated_at":"2026-10-15T10:00:00Z"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadAuditReport(path); (err != nil) != tt.wantErr {
+				t.Errorf("loadAuditReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := decodeAuditReports(path); (err != nil) != tt.wantErr {
+				t.Errorf("decodeAuditReports() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
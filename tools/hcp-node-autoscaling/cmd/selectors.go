@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// hostedClusterSelector restricts an audit to the HostedClusters whose labels and annotations match.
+// A nil *hostedClusterSelector matches every HostedCluster.
+type hostedClusterSelector struct {
+	labels      labels.Selector
+	annotations labels.Selector
+}
+
+// parseHostedClusterSelector parses --label-selector and --annotation-selector. Both use label selector
+// syntax (e.g. "key=value,other!=value,key in (a,b)"). It returns nil when neither is set.
+func parseHostedClusterSelector(labelSelector, annotationSelector string) (*hostedClusterSelector, error) {
+	if labelSelector == "" && annotationSelector == "" {
+		return nil, nil
+	}
+
+	selector := &hostedClusterSelector{labels: labels.Everything(), annotations: labels.Everything()}
+
+	if labelSelector != "" {
+		parsed, err := labels.Parse(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %v", labelSelector, err)
+		}
+		selector.labels = parsed
+	}
+
+	if annotationSelector != "" {
+		parsed, err := labels.Parse(annotationSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation selector '%s': %v", annotationSelector, err)
+		}
+		selector.annotations = parsed
+	}
+
+	return selector, nil
+}
+
+// matches reports whether the HostedCluster's labels and annotations satisfy the selector.
+func (s *hostedClusterSelector) matches(hc *hypershiftv1beta1.HostedCluster) bool {
+	if s == nil {
+		return true
+	}
+	return s.labels.Matches(labels.Set(hc.Labels)) && s.annotations.Matches(labels.Set(hc.Annotations))
+}
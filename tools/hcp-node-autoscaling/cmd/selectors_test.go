@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestHostedClusterSelector verifies label and annotation selectors are matched against the HostedCluster.
+func TestHostedClusterSelector(t *testing.T) {
+	hc := &hypershiftv1beta1.HostedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"api.openshift.com/environment":               "production",
+				"hypershift.openshift.io/hosted-cluster-size": "large",
+			},
+			Annotations: map[string]string{
+				"hypershift.openshift.io/cluster-size-override": "m5xl",
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		labelSelector      string
+		annotationSelector string
+		expected           bool
+		expectError        bool
+	}{
+		{
+			name:     "no selectors",
+			expected: true,
+		},
+		{
+			name:          "matching label",
+			labelSelector: "hypershift.openshift.io/hosted-cluster-size=large",
+			expected:      true,
+		},
+		{
+			name:          "non-matching label",
+			labelSelector: "hypershift.openshift.io/hosted-cluster-size=small",
+			expected:      false,
+		},
+		{
+			name:          "set-based label",
+			labelSelector: "api.openshift.com/environment in (production,staging)",
+			expected:      true,
+		},
+		{
+			name:               "matching annotation",
+			annotationSelector: "hypershift.openshift.io/cluster-size-override",
+			expected:           true,
+		},
+		{
+			name:               "label matches but annotation does not",
+			labelSelector:      "hypershift.openshift.io/hosted-cluster-size=large",
+			annotationSelector: "!hypershift.openshift.io/cluster-size-override",
+			expected:           false,
+		},
+		{
+			name:          "invalid label selector",
+			labelSelector: "hypershift.openshift.io/hosted-cluster-size in (large",
+			expectError:   true,
+		},
+		{
+			name:               "invalid annotation selector",
+			annotationSelector: "=m5xl",
+			expectError:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := parseHostedClusterSelector(tt.labelSelector, tt.annotationSelector)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result := selector.matches(hc); result != tt.expected {
+				t.Errorf("matches() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
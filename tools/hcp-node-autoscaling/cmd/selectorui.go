@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// selectorKeysHelp is the key help line at the top of the terminal UI.
+	selectorKeysHelp = "↑/↓ move  space toggle  a all  n none  enter confirm  q quit"
+
+	// Escape sequences switching to the alternate screen and hiding the cursor while the terminal UI runs,
+	// clearing the screen before each frame, and restoring the terminal afterwards.
+	enterSelectorScreen = "\033[?1049h\033[?25l"
+	exitSelectorScreen  = "\033[?25h\033[?1049l"
+	clearSelectorScreen = "\033[H\033[2J"
+)
+
+// selectorAction is what a key pressed in the terminal UI does to the selection as a whole.
+type selectorAction int
+
+const (
+	selectorContinue selectorAction = iota
+	selectorConfirm
+	selectorQuit
+)
+
+// selectInTerminal runs the terminal UI on the alternate screen of f, with the terminal in raw mode so
+// every key is read as it is pressed.
+func (s *candidateSelector) selectInTerminal(f *os.File) ([]hostedClusterAuditInfo, error) {
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the terminal: %v", err)
+	}
+	defer term.Restore(int(f.Fd()), state)
+
+	fmt.Fprint(s.out, enterSelectorScreen)
+	defer fmt.Fprint(s.out, exitSelectorScreen)
+
+	return s.selectWithKeys(func() (int, int) {
+		if out, ok := s.out.(*os.File); ok {
+			if width, height, err := term.GetSize(int(out.Fd())); err == nil {
+				return width, height
+			}
+		}
+		return 80, 24
+	})
+}
+
+// selectWithKeys draws the terminal UI at the size returned by size and applies the keys read from s.in
+// until the selection is confirmed or the operator quits.
+func (s *candidateSelector) selectWithKeys(size func() (int, int)) ([]hostedClusterAuditInfo, error) {
+	for {
+		fmt.Fprint(s.out, clearSelectorScreen+strings.Join(s.view(size()), "\r\n"))
+
+		key, err := readSelectorKey(s.in)
+		if err != nil {
+			return nil, fmt.Errorf("selection cancelled: %v", err)
+		}
+		switch s.handleKey(key) {
+		case selectorConfirm:
+			return s.selection(), nil
+		case selectorQuit:
+			return nil, fmt.Errorf("migration cancelled by user")
+		}
+	}
+}
+
+// readSelectorKey reads a key press, returning the names up, down, pgup, pgdown, home, end, enter, esc and
+// ctrl-c for those keys, and the character typed otherwise.
+func readSelectorKey(in *bufio.Reader) (string, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch r {
+	case '\r', '\n':
+		return "enter", nil
+	case 3:
+		return "ctrl-c", nil
+	case 27:
+		if in.Buffered() == 0 {
+			return "esc", nil
+		}
+	default:
+		return string(r), nil
+	}
+
+	// An escape sequence, e.g. ESC [ A for the up arrow or ESC [ 5 ~ for page up.
+	seq := ""
+	for in.Buffered() > 0 {
+		b, err := in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		seq += string(b)
+		if len(seq) > 1 && (b >= 'A' && b <= 'Z' || b == '~') {
+			break
+		}
+	}
+	switch seq {
+	case "[A", "OA":
+		return "up", nil
+	case "[B", "OB":
+		return "down", nil
+	case "[5~":
+		return "pgup", nil
+	case "[6~":
+		return "pgdown", nil
+	case "[H", "OH", "[1~":
+		return "home", nil
+	case "[F", "OF", "[4~":
+		return "end", nil
+	}
+	return "esc" + seq, nil
+}
+
+// handleKey moves the cursor or changes the selection for a key read by readSelectorKey.
+func (s *candidateSelector) handleKey(key string) selectorAction {
+	last := len(s.candidates) - 1
+	switch key {
+	case "up", "k":
+		s.cursor = max(s.cursor-1, 0)
+	case "down", "j":
+		s.cursor = min(s.cursor+1, last)
+	case "pgup":
+		s.cursor = max(s.cursor-10, 0)
+	case "pgdown":
+		s.cursor = min(s.cursor+10, last)
+	case "home", "g":
+		s.cursor = 0
+	case "end", "G":
+		s.cursor = last
+	case " ", "x":
+		s.selected[s.cursor] = !s.selected[s.cursor]
+	case "a":
+		s.setAll(true)
+	case "n":
+		s.setAll(false)
+	case "enter", "c":
+		return selectorConfirm
+	case "q", "esc", "ctrl-c":
+		return selectorQuit
+	}
+	return selectorContinue
+}
+
+// view returns the lines of the terminal UI for a screen of width by height characters: the key help and
+// selection count, the part of the candidate list around the cursor, and below it the detail pane with the
+// labels and annotations of the highlighted candidate.
+func (s *candidateSelector) view(width, height int) []string {
+	listHeight := min(len(s.candidates), max(height/2-3, 3))
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+listHeight {
+		s.offset = s.cursor - listHeight + 1
+	}
+
+	idWidth, nameWidth, namespaceWidth := len("CLUSTER ID"), len("CLUSTER NAME"), len("NAMESPACE")
+	for _, c := range s.candidates {
+		idWidth, nameWidth = max(idWidth, len(c.ClusterID)), max(nameWidth, len(c.ClusterName))
+		namespaceWidth = max(namespaceWidth, len(c.Namespace))
+	}
+	row := func(cursor, marker, id, name, namespace, size string) string {
+		return fmt.Sprintf("%s %s %-*s  %-*s  %-*s  %s", cursor, marker, idWidth, id, nameWidth, name, namespaceWidth, namespace, size)
+	}
+
+	lines := []string{
+		selectorKeysHelp,
+		fmt.Sprintf("%d of %d clusters selected", len(s.selection()), len(s.candidates)),
+		"",
+		row(" ", "   ", "CLUSTER ID", "CLUSTER NAME", "NAMESPACE", "CURRENT SIZE"),
+	}
+	for i := s.offset; i < s.offset+listHeight; i++ {
+		c := s.candidates[i]
+		cursor, marker := " ", "[ ]"
+		if i == s.cursor {
+			cursor = ">"
+		}
+		if s.selected[i] {
+			marker = "[x]"
+		}
+		lines = append(lines, row(cursor, marker, c.ClusterID, c.ClusterName, c.Namespace, c.CurrentSize))
+	}
+
+	var details strings.Builder
+	s.showDetails(&details, s.candidates[s.cursor])
+	lines = append(lines, strings.Repeat("─", width))
+	lines = append(lines, strings.Split(strings.TrimSpace(details.String()), "\n")...)
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > width {
+			lines[i] = string(runes[:width])
+		}
+	}
+	return lines
+}
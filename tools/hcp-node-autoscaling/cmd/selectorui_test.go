@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadSelectorKey verifies key presses and the escape sequences of arrow and paging keys are named.
+func TestReadSelectorKey(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("\x1b[A\x1b[B\x1bOB\x1b[5~\x1b[6~\x1b[H\x1b[F \r\x03a"))
+	var keys []string
+	for {
+		key, err := readSelectorKey(in)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+	}
+
+	expected := []string{"up", "down", "down", "pgup", "pgdown", "home", "end", " ", "enter", "ctrl-c", "a"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("readSelectorKey() = %q, want %q", keys, expected)
+	}
+}
+
+// TestSelectWithKeys verifies the terminal UI toggles the highlighted cluster, selects and deselects all
+// clusters, and confirms or quits on a key.
+func TestSelectWithKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        string
+		expectedIDs []string
+		expectError bool
+	}{
+		{name: "enter keeps all clusters selected", keys: "\r", expectedIDs: []string{"cluster1", "cluster2", "cluster3"}},
+		{name: "toggle the second cluster", keys: "\x1b[B \r", expectedIDs: []string{"cluster1", "cluster3"}},
+		{name: "cursor stops at the last cluster", keys: "n\x1b[B\x1b[B\x1b[B\x1b[B \r", expectedIDs: []string{"cluster3"}},
+		{name: "deselect all then select all", keys: "na\r", expectedIDs: []string{"cluster1", "cluster2", "cluster3"}},
+		{name: "quit cancels migration", keys: "q", expectError: true},
+		{name: "ctrl-c cancels migration", keys: "\x03", expectError: true},
+		{name: "end of input cancels migration", keys: " ", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &candidateSelector{
+				candidates: []hostedClusterAuditInfo{{ClusterID: "cluster1"}, {ClusterID: "cluster2"}, {ClusterID: "cluster3"}},
+				selected:   make([]bool, 3),
+				in:         bufio.NewReader(strings.NewReader(tt.keys)),
+				out:        &bytes.Buffer{},
+			}
+			s.setAll(true)
+
+			selected, err := s.selectWithKeys(func() (int, int) { return 80, 24 })
+			if (err != nil) != tt.expectError {
+				t.Fatalf("selectWithKeys() error = %v, expectError %v", err, tt.expectError)
+			}
+			var ids []string
+			for _, c := range selected {
+				ids = append(ids, c.ClusterID)
+			}
+			if !tt.expectError && !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("selectWithKeys() = %v, want %v", ids, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+// TestSelectorView verifies the list scrolls to keep the cursor on screen and the detail pane shows the
+// labels and annotations of the highlighted cluster, within the size of the screen.
+func TestSelectorView(t *testing.T) {
+	s := &candidateSelector{selected: make([]bool, 20)}
+	for i := range 20 {
+		s.candidates = append(s.candidates, hostedClusterAuditInfo{
+			ClusterID:   "cluster" + string(rune('a'+i)),
+			ClusterName: "name-" + string(rune('a'+i)),
+			Labels:      map[string]string{"index": string(rune('a' + i))},
+			Annotations: map[string]string{autoscalingAnnotation: "false"},
+		})
+	}
+	s.selected[3] = true
+	s.cursor = 15
+
+	lines := s.view(100, 20)
+	view := strings.Join(lines, "\n")
+	if len(lines) > 20 {
+		t.Errorf("Expected at most 20 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if len([]rune(line)) > 100 {
+			t.Errorf("Expected lines of at most 100 characters, got %q", line)
+		}
+	}
+	if !strings.Contains(view, "1 of 20 clusters selected") {
+		t.Errorf("Expected the selection count, got:\n%s", view)
+	}
+	if !strings.Contains(view, "> [ ] clusterp") || strings.Contains(view, "clustera") {
+		t.Errorf("Expected the list scrolled to the highlighted cluster, got:\n%s", view)
+	}
+	if !strings.Contains(view, "--- name-p (clusterp) ---") || !strings.Contains(view, "  index: p") ||
+		!strings.Contains(view, "  hypershift.openshift.io/resource-based-cp-auto-scaling: false") {
+		t.Errorf("Expected the details of the highlighted cluster, got:\n%s", view)
+	}
+}
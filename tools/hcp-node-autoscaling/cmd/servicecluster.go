@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	osdfmv1 "github.com/openshift-online/ocm-sdk-go/osdfleetmgmt/v1"
+)
+
+// discoverServiceCluster returns the service cluster a management cluster is parented to in OSD Fleet Manager.
+func discoverServiceCluster(conn *sdk.Connection, cache *ocmCache, mgmtClusterName string) (*cmv1.Cluster, error) {
+	parentName, err := cache.parentServiceCluster(mgmtClusterName, func() (string, error) {
+		return lookupParentServiceCluster(conn, mgmtClusterName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	serviceCluster, err := cache.getCluster(conn, parentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service cluster %s: %v", parentName, err)
+	}
+	return serviceCluster, nil
+}
+
+// lookupParentServiceCluster returns the name of a management cluster's parent service cluster in OSD Fleet Manager.
+func lookupParentServiceCluster(conn *sdk.Connection, mgmtClusterName string) (string, error) {
+	mgmtCluster, err := getFleetManagementCluster(conn, mgmtClusterName)
+	if err != nil {
+		return "", err
+	}
+
+	parent := mgmtCluster.Parent()
+	if parent.Kind() != "ServiceCluster" || parent.Name() == "" {
+		return "", fmt.Errorf("management cluster %s has no parent service cluster in fleet manager", mgmtClusterName)
+	}
+	return parent.Name(), nil
+}
+
+// getFleetManagementCluster returns the OSD Fleet Manager record of a management cluster.
+func getFleetManagementCluster(conn *sdk.Connection, mgmtClusterName string) (*osdfmv1.ManagementCluster, error) {
+	resp, err := conn.OSDFleetMgmt().V1().ManagementClusters().List().
+		Parameter("search", fmt.Sprintf("name='%s'", mgmtClusterName)).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fleet manager information for management cluster %s: %v", mgmtClusterName, err)
+	}
+	if resp.Items().Len() == 0 {
+		return nil, fmt.Errorf("management cluster %s not found in fleet manager", mgmtClusterName)
+	}
+	return resp.Items().Get(0), nil
+}
+
+// resolveServiceCluster returns the service cluster whose ManifestWorks belong to the management cluster.
+// Without a service cluster ID the parent service cluster is discovered. An explicit ID overrides discovery,
+// but a warning is logged when it is not the management cluster's parent, since its ManifestWorks would not
+// be found.
+func resolveServiceCluster(conn *sdk.Connection, cache *ocmCache, serviceClusterID, mgmtClusterName string) (*cmv1.Cluster, error) {
+	if serviceClusterID == "" {
+		serviceCluster, err := discoverServiceCluster(conn, cache, mgmtClusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover service cluster, pass --service-cluster-id: %v", err)
+		}
+		slog.Info("Discovered service cluster", "mgmtCluster", mgmtClusterName,
+			"serviceCluster", serviceCluster.Name(), "serviceClusterID", serviceCluster.ID())
+		return serviceCluster, nil
+	}
+
+	serviceCluster, err := cache.getCluster(conn, serviceClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service cluster: %v", err)
+	}
+
+	parent, err := discoverServiceCluster(conn, cache, mgmtClusterName)
+	if err != nil {
+		slog.Warn("Could not verify that the service cluster is the management cluster's parent", "error", err)
+		return serviceCluster, nil
+	}
+	if parent.ID() != serviceCluster.ID() {
+		slog.Warn("Service cluster is not the management cluster's parent; ManifestWorks are likely to be missing",
+			"mgmtCluster", mgmtClusterName, "serviceCluster", serviceCluster.Name(),
+			"parentServiceCluster", parent.Name(), "parentServiceClusterID", parent.ID())
+	}
+	return serviceCluster, nil
+}
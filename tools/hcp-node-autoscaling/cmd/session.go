@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/spf13/cobra"
+)
+
+// runSession is the state of a run of a subcommand: the flags every subcommand has and what they set up,
+// such as the run ID, the logger, the deadline, the trace and the debug profiles of the run. Each exported
+// subcommand constructor adds its own with addRunSession, so a subcommand registered without the root
+// command, e.g. in osdctl, is set up the same way, and commands built in the same process share nothing.
+type runSession struct {
+	// id identifies the run in logs, reports, run histories, service logs, notifications and metrics, so
+	// that runs by several operators at the same time can be told apart. It is set from --run-id when the
+	// run starts.
+	id string
+
+	// quiet is set by --quiet. It suppresses log messages below error, progress bars and the output of
+	// migrate meant for an operator watching the run, so a command run from cron prints only its selected
+	// output format and errors, and reports its outcome through the exit code.
+	quiet bool
+
+	// eventsOnStdout is set when migrate writes its --output jsonl event stream to stdout.
+	eventsOnStdout bool
+
+	// stderr receives the log messages, below the status line holding the progress bar.
+	stderr *statusLine
+
+	// rateLimit is the client-side rate limit of every Kubernetes client of the run, set by --qps and
+	// --burst.
+	rateLimit clientfactory.RateLimit
+
+	logging    logOpts
+	configPath string
+	runIDFlag  string
+	noProgress bool
+	timeout    runTimeout
+	tracing    runTracing
+	profile    debugProfile
+}
+
+// newRunSession returns a session with the default settings and a random run ID.
+func newRunSession() *runSession {
+	return &runSession{
+		id:        uuid.NewString(),
+		stderr:    &statusLine{w: os.Stderr},
+		rateLimit: clientfactory.RateLimit{QPS: defaultQPS, Burst: defaultBurst},
+	}
+}
+
+// addRunSession registers the flags every subcommand has on cmd and wraps its RunE so the run is set up
+// from them before it starts, and its deadline, trace and debug profiles are finished after it returns,
+// whether it failed or not.
+func addRunSession(cmd *cobra.Command) *runSession {
+	s := newRunSession()
+	cmd.Flags().StringVar(&s.logging.level, "log-level", "info", "Log level: debug, info, warn, error")
+	cmd.Flags().StringVar(&s.logging.format, "log-format", "text", "Log format: text, json")
+	cmd.Flags().StringVar(&s.runIDFlag, "run-id", "",
+		"ID of the run recorded in logs, reports, run histories, service logs, notifications and metrics (default: a random UUID)")
+	cmd.Flags().StringVar(&s.configPath, "config", "",
+		"Config file with flag defaults (default ~/.config/hcp-node-autoscaling/config.yaml)")
+	cmd.Flags().DurationVar(&s.timeout.timeout, "timeout", 0,
+		"Deadline for the whole command, e.g. 30m (0 means no deadline)")
+	cmd.Flags().BoolVar(&s.noProgress, "no-progress", false,
+		"Do not show progress bars on stderr (they are only shown when stderr is a terminal)")
+	addRateLimitFlags(cmd, &s.rateLimit)
+	addDebugProfileFlag(cmd, &s.profile)
+	cmd.Flags().BoolVar(&s.quiet, "quiet", false,
+		"Print only the selected output format and errors, e.g. for cron jobs: log errors only, show no progress bars and leave out the candidate and summary tables of migrate")
+
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := s.start(cmd)
+		if err == nil {
+			err = run(cmd, args)
+		}
+		return s.finish(err)
+	}
+	return s
+}
+
+// start applies the config file to the flags of cmd, resolves the run ID, sets up logging and progress
+// bars, and starts the trace, the deadline and the debug profiles of the run on the context of cmd.
+func (s *runSession) start(cmd *cobra.Command) error {
+	if err := applyConfig(cmd, s.configPath); err != nil {
+		return err
+	}
+	if err := validateRateLimit(s.rateLimit); err != nil {
+		return err
+	}
+	id, err := resolveRunID(s.runIDFlag)
+	if err != nil {
+		return err
+	}
+	s.id = id
+	if s.quiet {
+		s.logging.level = "error"
+	}
+	if err := s.logging.setup(s.stderr); err != nil {
+		return err
+	}
+	slog.SetDefault(slog.Default().With("runID", s.id))
+	s.stderr.progress = !s.noProgress && !s.quiet && isTerminal(os.Stderr)
+	if err := s.tracing.apply(cmd, s.id); err != nil {
+		return err
+	}
+	s.timeout.apply(cmd)
+	return s.profile.start(s.id)
+}
+
+// finish releases the deadline, ends the trace and writes the debug profiles of the run, returning err,
+// or the error writing the profiles when the run succeeded.
+func (s *runSession) finish(err error) error {
+	err = s.tracing.finish(s.timeout.finish(err))
+	if profileErr := s.profile.stop(os.Stderr); profileErr != nil && err == nil {
+		err = profileErr
+	}
+	return err
+}
+
+// runID returns the ID of the run, or an empty ID without a session.
+func (s *runSession) runID() string {
+	if s == nil {
+		return ""
+	}
+	return s.id
+}
+
+// progressOut returns the status line progress bars are drawn on, or nil without a session.
+func (s *runSession) progressOut() *statusLine {
+	if s == nil {
+		return nil
+	}
+	return s.stderr
+}
+
+// timings returns the phase timings of the run, which are nil without --debug-profile.
+func (s *runSession) timings() *phaseTimings {
+	if s == nil {
+		return nil
+	}
+	return s.profile.timings
+}
+
+// apiRateLimit returns the rate limit of the Kubernetes clients of the run, the default one without a
+// session.
+func (s *runSession) apiRateLimit() clientfactory.RateLimit {
+	if s == nil {
+		return clientfactory.RateLimit{QPS: defaultQPS, Burst: defaultBurst}
+	}
+	return s.rateLimit
+}
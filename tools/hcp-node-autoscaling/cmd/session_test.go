@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/spf13/cobra"
+)
+
+// newTestSessionCmd returns a command set up like an exported subcommand, running run.
+func newTestSessionCmd(run func(cmd *cobra.Command) error) (*cobra.Command, *runSession) {
+	cmd := &cobra.Command{Use: "audit", RunE: func(cmd *cobra.Command, _ []string) error { return run(cmd) }}
+	return cmd, addRunSession(cmd)
+}
+
+// TestRunSession verifies a subcommand built without the root command reads the config file and its run ID
+// and rate limit flags before it runs, and that the sessions of two commands share nothing.
+func TestRunSession(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	logger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(logger) })
+	configDir := filepath.Join(home, ".config", "hcp-node-autoscaling")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("qps: 20\nburst: 40\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	audit, s := newTestSessionCmd(func(*cobra.Command) error {
+		slog.Info("Auditing")
+		return nil
+	})
+	var logs bytes.Buffer
+	s.stderr.w = &logs
+	audit.SetArgs([]string{"--run-id", "run-123"})
+	if err := audit.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.runID() != "run-123" || !strings.Contains(logs.String(), "runID=run-123") {
+		t.Errorf("Expected run ID run-123 in the session and logs, got %q and %q", s.runID(), logs.String())
+	}
+	if limit := s.apiRateLimit(); limit != (clientfactory.RateLimit{QPS: 20, Burst: 40}) {
+		t.Errorf("Expected the rate limit of the config file, got %+v", limit)
+	}
+
+	other, otherSession := newTestSessionCmd(func(*cobra.Command) error { return nil })
+	other.SetArgs([]string{"--quiet"})
+	if err := other.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if otherSession.runID() == "" || otherSession.runID() == s.runID() || !otherSession.quiet || s.quiet {
+		t.Errorf("Expected the sessions not to share state, got run IDs %q and %q", s.runID(), otherSession.runID())
+	}
+
+	invalid, _ := newTestSessionCmd(func(*cobra.Command) error {
+		t.Error("Expected the command not to run with an invalid run ID")
+		return nil
+	})
+	invalid.SetArgs([]string{"--run-id", "../run"})
+	if err := invalid.Execute(); err == nil || !strings.Contains(err.Error(), "invalid run ID") {
+		t.Errorf("Expected an invalid run ID error, got %v", err)
+	}
+}
+
+// TestRunSessionTimeout verifies the deadline of the run is finished by the command itself, saying so in its
+// error.
+func TestRunSessionTimeout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	logger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(logger) })
+
+	cmd, _ := newTestSessionCmd(func(cmd *cobra.Command) error {
+		<-cmd.Context().Done()
+		return cmd.Context().Err()
+	})
+	cmd.SetArgs([]string{"--timeout", "10ms"})
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "run timed out after 10ms") || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the run to time out, got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/resources"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// loadSizeClasses reads the size classes from the management cluster's ClusterSizingConfiguration.
+func (a *auditOpts) loadSizeClasses(ctx context.Context) ([]schedulingv1alpha1.SizeConfiguration, error) {
+	csc := &schedulingv1alpha1.ClusterSizingConfiguration{}
+	if err := a.mgmtClient.Get(ctx, types.NamespacedName{Name: "cluster"}, csc); err != nil {
+		return nil, fmt.Errorf("failed to get ClusterSizingConfiguration: %v", err)
+	}
+	return csc.Spec.Sizes, nil
+}
+
+// analyzeSizing populates the NodePool, worker and control plane request fields of info and
+// computes the size class the cluster is expected to have once autoscaling is enabled.
+func (a *auditOpts) analyzeSizing(ctx context.Context, hc *hypershiftv1beta1.HostedCluster, info *hostedClusterAuditInfo) error {
+	nodePools := &hypershiftv1beta1.NodePoolList{}
+	if err := a.mgmtClient.List(ctx, nodePools, client.InNamespace(hc.Namespace)); err != nil {
+		return fmt.Errorf("failed to list NodePools: %v", err)
+	}
+	info.NodePoolCount, info.WorkerReplicas = summarizeNodePools(nodePools.Items, hc.Name)
+
+	pods := &corev1.PodList{}
+	controlPlaneNamespace := fmt.Sprintf("%s-%s", hc.Namespace, hc.Name)
+	if err := a.mgmtClient.List(ctx, pods, client.InNamespace(controlPlaneNamespace)); err != nil {
+		return fmt.Errorf("failed to list control plane pods in %s: %v", controlPlaneNamespace, err)
+	}
+	cpu, memory := resources.PodRequests(pods.Items)
+	info.ControlPlaneCPURequests = cpu.String()
+	info.ControlPlaneMemoryRequests = memory.String()
+
+	info.ExpectedSizeClass = expectedSizeClass(a.sizeClasses, info.WorkerReplicas)
+
+	if a.simulateSizing {
+		kasMemory := containerMemoryRequests(pods.Items, kubeAPIServerName)
+		info.KubeAPIServerMemoryRequests = kasMemory.String()
+		info.SimulatedSizeClass = simulateSizeClass(a.sizeClasses, info.WorkerReplicas, kasMemory)
+		info.SizeChange = info.SimulatedSizeClass != "" && info.SimulatedSizeClass != info.CurrentSize
+	}
+
+	return nil
+}
+
+// summarizeNodePools returns the number of NodePools belonging to the named hosted cluster and
+// their total current worker replicas.
+func summarizeNodePools(nodePools []hypershiftv1beta1.NodePool, clusterName string) (int, int32) {
+	count := 0
+	var replicas int32
+	for _, np := range nodePools {
+		if np.Spec.ClusterName != clusterName {
+			continue
+		}
+		count++
+		replicas += np.Status.Replicas
+	}
+	return count, replicas
+}
+
+// expectedSizeClass returns the name of the size class whose node count criteria match the
+// given number of workers, or an empty string if no size class matches.
+func expectedSizeClass(sizes []schedulingv1alpha1.SizeConfiguration, workers int32) string {
+	if workers < 0 {
+		return ""
+	}
+	nodes := uint32(workers)
+
+	for _, size := range sizes {
+		if nodes < size.Criteria.From {
+			continue
+		}
+		if size.Criteria.To != nil && nodes > *size.Criteria.To {
+			continue
+		}
+		return size.Name
+	}
+
+	return ""
+}
+
+// kubeAPIServerName is the name of the kube-apiserver deployment and container in a hosted control plane.
+const kubeAPIServerName = "kube-apiserver"
+
+// containerMemoryRequests returns the total memory requests of the named container in running or pending pods.
+func containerMemoryRequests(pods []corev1.Pod, containerName string) resource.Quantity {
+	memory := resource.Quantity{Format: resource.BinarySI}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok && c.Name == containerName {
+				memory.Add(q)
+			}
+		}
+	}
+	return memory
+}
+
+// kubeAPIServerMemory returns the kube-apiserver memory a size class provides: its kube-apiserver
+// container memory request or, when not set, its kube-apiserver GOMEMLIMIT.
+func kubeAPIServerMemory(size schedulingv1alpha1.SizeConfiguration) (resource.Quantity, bool) {
+	if size.Effects == nil {
+		return resource.Quantity{}, false
+	}
+	for _, r := range size.Effects.ResourceRequests {
+		if r.DeploymentName == kubeAPIServerName && r.ContainerName == kubeAPIServerName && r.Memory != nil {
+			return *r.Memory, true
+		}
+	}
+	if size.Effects.KASGoMemLimit != nil {
+		return *size.Effects.KASGoMemLimit, true
+	}
+	return resource.Quantity{}, false
+}
+
+// simulateSizeClass predicts the size class the resource-based autoscaler chooses for a cluster: the
+// smallest size class providing at least the kube-apiserver memory the cluster requests today, and no
+// smaller than the size class matching its worker count. Without size classes declaring kube-apiserver
+// memory the prediction is the size class matching the worker count.
+func simulateSizeClass(sizes []schedulingv1alpha1.SizeConfiguration, workers int32, kasMemory resource.Quantity) string {
+	ordered := append([]schedulingv1alpha1.SizeConfiguration{}, sizes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Criteria.From < ordered[j].Criteria.From
+	})
+
+	floor := -1
+	byWorkers := expectedSizeClass(ordered, workers)
+	for i, size := range ordered {
+		if size.Name == byWorkers {
+			floor = i
+		}
+	}
+
+	byMemory := -1
+	for i, size := range ordered {
+		capacity, ok := kubeAPIServerMemory(size)
+		if !ok {
+			continue
+		}
+		byMemory = i
+		if capacity.Cmp(kasMemory) >= 0 {
+			break
+		}
+	}
+
+	if chosen := max(floor, byMemory); chosen >= 0 {
+		return ordered[chosen].Name
+	}
+	return ""
+}
+
+// sizeChangeValue returns the size_change CSV value, which is empty when sizing was not simulated.
+func sizeChangeValue(c hostedClusterAuditInfo) string {
+	if c.SimulatedSizeClass == "" {
+		return ""
+	}
+	return strconv.FormatBool(c.SizeChange)
+}
+
+// printSizeChanges prints the clusters expected to change size class once autoscaling is enabled.
+func printSizeChanges(w io.Writer, clusters []hostedClusterAuditInfo, noHeaders bool) {
+	var changes []hostedClusterAuditInfo
+	for _, c := range clusters {
+		if c.SizeChange {
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ClusterID < changes[j].ClusterID
+	})
+
+	fmt.Fprintf(w, "=== Expected Size Changes (%d clusters) ===\n", len(changes))
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No cluster is expected to change size class after migration.")
+		fmt.Fprintln(w)
+		return
+	}
+	fmt.Fprintln(w, "These clusters are expected to move to another size class, and new request serving nodes, after migration:")
+
+	p := output.NewTable(w, output.TableMinWidth)
+	if !noHeaders {
+		p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "CATEGORY", "CURRENT SIZE", "SIMULATED SIZE", "WORKERS", "KAS MEMORY"})
+	}
+	for _, c := range changes {
+		p.AddRow([]string{c.ClusterID, c.ClusterName, c.Category, c.CurrentSize, c.SimulatedSizeClass,
+			strconv.Itoa(int(c.WorkerReplicas)), c.KubeAPIServerMemoryRequests})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
+
+// sizeTransition counts clusters moving from one size class to another.
+type sizeTransition struct {
+	From  string
+	To    string
+	Count int
+}
+
+// summarizeSizeTransitions groups clusters by their current and expected size classes.
+func summarizeSizeTransitions(clusters []hostedClusterAuditInfo) []sizeTransition {
+	counts := map[[2]string]int{}
+	for _, c := range clusters {
+		if c.ExpectedSizeClass == "" {
+			continue
+		}
+		counts[[2]string{c.CurrentSize, c.ExpectedSizeClass}]++
+	}
+
+	transitions := make([]sizeTransition, 0, len(counts))
+	for k, count := range counts {
+		transitions = append(transitions, sizeTransition{From: k[0], To: k[1], Count: count})
+	}
+
+	sort.Slice(transitions, func(i, j int) bool {
+		if transitions[i].From != transitions[j].From {
+			return transitions[i].From < transitions[j].From
+		}
+		return transitions[i].To < transitions[j].To
+	})
+
+	return transitions
+}
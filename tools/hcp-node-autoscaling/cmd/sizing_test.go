@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+// TestExpectedSizeClass verifies size class selection by worker node count.
+func TestExpectedSizeClass(t *testing.T) {
+	sizes := []schedulingv1alpha1.SizeConfiguration{
+		{Name: "small", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 0, To: uint32Ptr(10)}},
+		{Name: "medium", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 11, To: uint32Ptr(100)}},
+		{Name: "large", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 101}},
+	}
+
+	tests := []struct {
+		name     string
+		sizes    []schedulingv1alpha1.SizeConfiguration
+		workers  int32
+		expected string
+	}{
+		{name: "no workers", sizes: sizes, workers: 0, expected: "small"},
+		{name: "upper bound is inclusive", sizes: sizes, workers: 10, expected: "small"},
+		{name: "lower bound is inclusive", sizes: sizes, workers: 11, expected: "medium"},
+		{name: "unbounded size class", sizes: sizes, workers: 500, expected: "large"},
+		{name: "no size classes", sizes: nil, workers: 5, expected: ""},
+		{name: "negative workers", sizes: sizes, workers: -1, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := expectedSizeClass(tt.sizes, tt.workers); result != tt.expected {
+				t.Errorf("expectedSizeClass() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSummarizeNodePools verifies NodePool counting is scoped to the hosted cluster.
+func TestSummarizeNodePools(t *testing.T) {
+	nodePools := []hypershiftv1beta1.NodePool{
+		{Spec: hypershiftv1beta1.NodePoolSpec{ClusterName: "hc1"}, Status: hypershiftv1beta1.NodePoolStatus{Replicas: 3}},
+		{Spec: hypershiftv1beta1.NodePoolSpec{ClusterName: "hc1"}, Status: hypershiftv1beta1.NodePoolStatus{Replicas: 5}},
+		{Spec: hypershiftv1beta1.NodePoolSpec{ClusterName: "hc2"}, Status: hypershiftv1beta1.NodePoolStatus{Replicas: 7}},
+	}
+
+	count, replicas := summarizeNodePools(nodePools, "hc1")
+	if count != 2 || replicas != 8 {
+		t.Errorf("summarizeNodePools() = (%d, %d), want (2, 8)", count, replicas)
+	}
+}
+
+// TestSummarizeSizeTransitions verifies grouping of current to expected size classes.
+func TestSummarizeSizeTransitions(t *testing.T) {
+	clusters := []hostedClusterAuditInfo{
+		{ClusterID: "c1", CurrentSize: "large", ExpectedSizeClass: "small"},
+		{ClusterID: "c2", CurrentSize: "large", ExpectedSizeClass: "small"},
+		{ClusterID: "c3", CurrentSize: "small", ExpectedSizeClass: "small"},
+		{ClusterID: "c4", CurrentSize: "medium"},
+	}
+
+	expected := []sizeTransition{
+		{From: "large", To: "small", Count: 2},
+		{From: "small", To: "small", Count: 1},
+	}
+
+	if result := summarizeSizeTransitions(clusters); !reflect.DeepEqual(result, expected) {
+		t.Errorf("summarizeSizeTransitions() = %+v, want %+v", result, expected)
+	}
+}
+
+// TestSimulateSizeClass verifies the simulated size class fits the kube-apiserver memory requests and is
+// never smaller than the size class matching the worker count.
+func TestSimulateSizeClass(t *testing.T) {
+	kasMemory := func(memory string) *schedulingv1alpha1.Effects {
+		q := resource.MustParse(memory)
+		return &schedulingv1alpha1.Effects{ResourceRequests: []schedulingv1alpha1.ResourceRequest{
+			{DeploymentName: kubeAPIServerName, ContainerName: kubeAPIServerName, Memory: &q},
+		}}
+	}
+	goMemLimit := resource.MustParse("24Gi")
+	// Listed out of order to verify size classes are ordered by their node count criteria.
+	sizes := []schedulingv1alpha1.SizeConfiguration{
+		{Name: "large", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 101}, Effects: &schedulingv1alpha1.Effects{KASGoMemLimit: &goMemLimit}},
+		{Name: "small", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 0, To: uint32Ptr(10)}, Effects: kasMemory("4Gi")},
+		{Name: "medium", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 11, To: uint32Ptr(100)}, Effects: kasMemory("12Gi")},
+	}
+	workerOnly := []schedulingv1alpha1.SizeConfiguration{
+		{Name: "small", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 0, To: uint32Ptr(10)}},
+		{Name: "large", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 11}},
+	}
+
+	tests := []struct {
+		name      string
+		sizes     []schedulingv1alpha1.SizeConfiguration
+		workers   int32
+		kasMemory string
+		expected  string
+	}{
+		{name: "fits the worker size class", sizes: sizes, workers: 3, kasMemory: "2Gi", expected: "small"},
+		{name: "memory needs a larger size class", sizes: sizes, workers: 3, kasMemory: "10Gi", expected: "medium"},
+		{name: "capacity is inclusive", sizes: sizes, workers: 3, kasMemory: "12Gi", expected: "medium"},
+		{name: "GOMEMLIMIT is used without a request", sizes: sizes, workers: 3, kasMemory: "20Gi", expected: "large"},
+		{name: "memory beyond every size class", sizes: sizes, workers: 3, kasMemory: "64Gi", expected: "large"},
+		{name: "worker count is the floor", sizes: sizes, workers: 150, kasMemory: "2Gi", expected: "large"},
+		{name: "no memory capacities", sizes: workerOnly, workers: 20, kasMemory: "64Gi", expected: "large"},
+		{name: "no size classes", sizes: nil, workers: 5, kasMemory: "2Gi", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := simulateSizeClass(tt.sizes, tt.workers, resource.MustParse(tt.kasMemory)); got != tt.expected {
+				t.Errorf("simulateSizeClass() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestContainerMemoryRequests verifies only the named container of active pods is counted.
+func TestContainerMemoryRequests(t *testing.T) {
+	container := func(name, memory string) corev1.Container {
+		return corev1.Container{Name: name, Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		}}
+	}
+	pods := []corev1.Pod{
+		{Spec: corev1.PodSpec{Containers: []corev1.Container{container("kube-apiserver", "3Gi"), container("konnectivity-server", "1Gi")}}},
+		{Spec: corev1.PodSpec{Containers: []corev1.Container{container("kube-apiserver", "3Gi")}}},
+		{Spec: corev1.PodSpec{Containers: []corev1.Container{container("kube-apiserver", "3Gi")}}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+	}
+
+	memory := containerMemoryRequests(pods, kubeAPIServerName)
+	if expected := resource.MustParse("6Gi"); memory.Cmp(expected) != 0 {
+		t.Errorf("containerMemoryRequests() = %s, want %s", memory.String(), expected.String())
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// skippedStatus is the status of a migration result for an audited candidate that was left out of the run
+// before anything was patched, e.g. because it is paused, excluded or in a change freeze.
+const skippedStatus = "skipped"
+
+// skippedCluster is an audited candidate left out of a migrate run, with the reason it was left out.
+type skippedCluster struct {
+	info   hostedClusterAuditInfo
+	reason string
+}
+
+// result returns the migration result reporting the skipped cluster.
+func (s skippedCluster) result() migrationResult {
+	return migrationResult{
+		ClusterID:   s.info.ClusterID,
+		ClusterName: s.info.ClusterName,
+		Status:      skippedStatus,
+		Error:       s.reason,
+	}
+}
+
+// skip records that an audited candidate is left out of the run, so the summary accounts for it.
+func (m *migrateOpts) skip(info hostedClusterAuditInfo, reason string) {
+	m.skipped = append(m.skipped, skippedCluster{info: info, reason: reason})
+}
+
+// skipFiltered removes the candidates on the exclusion list and those below --min-version and records them
+// as skipped. The clusters below the minimum version are also returned, to be listed before confirmation.
+func (m *migrateOpts) skipFiltered(candidates []hostedClusterAuditInfo) ([]hostedClusterAuditInfo, []belowMinVersionCluster) {
+	for _, c := range candidates {
+		if reason, ok := m.exclusions[c.ClusterID]; ok {
+			m.skip(c, "excluded: "+reason)
+		}
+	}
+	candidates, belowMinVersion := filterMinVersion(filterExcluded(candidates, m.exclusions), m.minVersion)
+	for _, b := range belowMinVersion {
+		m.skip(b.info, "below minimum version: "+b.reason)
+	}
+	return candidates, belowMinVersion
+}
+
+// skipFrozen records the candidates left out by the maintenance and change freeze checks as skipped.
+func (m *migrateOpts) skipFrozen(frozen []frozenCluster) {
+	for _, f := range frozen {
+		m.skip(f.info, "maintenance or change freeze: "+strings.Join(f.reasons, "; "))
+	}
+}
+
+// skipRejected records the --candidates-file rows that failed validation as skipped.
+func (m *migrateOpts) skipRejected(rejected []rejectedCandidate) {
+	for _, r := range rejected {
+		m.skip(hostedClusterAuditInfo{ClusterID: r.row.ClusterID, ClusterName: r.row.ClusterName, Namespace: r.row.Namespace},
+			"rejected from candidates file: "+r.reason)
+	}
+}
+
+// recordSkipped publishes the skipped clusters to --events-broker and adds them to the run history.
+func (m *migrateOpts) recordSkipped() {
+	for _, s := range m.skipped {
+		m.broker.migrated(m.mgmtClusterID, s.info, s.result())
+	}
+	for _, s := range m.skipped {
+		if err := m.history.record(s.info, s.result(), false); err != nil {
+			slog.Warn("Failed to write run history", "error", err)
+			return
+		}
+	}
+}
+
+// displaySkipped prints the audited candidates that were left out of the run, with the reason.
+func displaySkipped(w io.Writer, skipped []skippedCluster) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "- Skipped (%d):\n", len(skipped))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "REASON"})
+	for _, s := range skipped {
+		p.AddRow([]string{s.info.ClusterID, s.info.ClusterName, s.reason})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSkipFiltered verifies excluded candidates and those below the minimum version are removed and recorded
+// as skipped with their reason.
+func TestSkipFiltered(t *testing.T) {
+	minVersion, err := parseMinVersion("4.15")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := &migrateOpts{exclusions: map[string]string{"b2": "customer freeze"}, minVersion: minVersion}
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "a1", OpenShiftVersion: "4.16.3"},
+		{ClusterID: "b2", OpenShiftVersion: "4.16.3"},
+		{ClusterID: "c3", OpenShiftVersion: "4.14.9"},
+	}
+
+	candidates, belowMinVersion := m.skipFiltered(candidates)
+	if len(candidates) != 1 || candidates[0].ClusterID != "a1" {
+		t.Errorf("Expected only a1 to remain, got %+v", candidates)
+	}
+	if len(belowMinVersion) != 1 || belowMinVersion[0].info.ClusterID != "c3" {
+		t.Errorf("Expected c3 below the minimum version, got %+v", belowMinVersion)
+	}
+
+	m.skipFrozen([]frozenCluster{{info: hostedClusterAuditInfo{ClusterID: "d4"}, reasons: []string{"upgrading", "limited support"}}})
+	m.skipRejected([]rejectedCandidate{{row: candidateRow{ClusterID: "e5"}, reason: "category is paused, not ready-for-migration"}})
+
+	var got []string
+	for _, s := range m.skipped {
+		got = append(got, s.info.ClusterID+": "+s.reason)
+	}
+	expected := []string{
+		"b2: excluded: customer freeze",
+		"c3: below minimum version: " + belowMinVersion[0].reason,
+		"d4: maintenance or change freeze: upgrading; limited support",
+		"e5: rejected from candidates file: category is paused, not ready-for-migration",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("skipped = %q, want %q", got, expected)
+	}
+}
+
+// TestRecordSkipped verifies skipped clusters are written to the run history with their reason.
+func TestRecordSkipped(t *testing.T) {
+	m := &migrateOpts{mgmtClusterID: "mgmt-456", patchStrategy: "update"}
+	m.skip(hostedClusterAuditInfo{ClusterID: "a1", ClusterName: "one"}, "paused: pausedUntil is set")
+	m.history = m.newRunHistory(t.TempDir(), time.Now())
+	m.recordSkipped()
+
+	data, err := os.ReadFile(m.history.path)
+	if err != nil {
+		t.Fatalf("History not written: %v", err)
+	}
+	history := &runHistory{}
+	if err := json.Unmarshal(data, history); err != nil {
+		t.Fatalf("Failed to parse history: %v", err)
+	}
+	if len(history.Changes) == 0 {
+		t.Fatal("Expected the skipped cluster in the run history")
+	}
+	for _, c := range history.Changes {
+		if c.ClusterID != "a1" || c.Status != skippedStatus || c.Error != "paused: pausedUntil is set" || c.Before != c.After {
+			t.Errorf("Unexpected change for skipped cluster: %+v", c)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// sparklineLevels are the bars of a sparkline, from lowest to highest.
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// statsOpts reads a directory of saved audit JSON reports and reports migration progress over time.
+type statsOpts struct {
+	reportsDir string
+	output     string
+	outputFile string
+	noHeaders  bool
+	sparkline  bool
+	session    *runSession
+}
+
+// statsPoint is the number of clusters per category of a management cluster, or the fleet, on one date.
+type statsPoint struct {
+	Date              string
+	NeedsRemoval      int
+	ReadyForMigration int
+	AlreadyConfigured int
+	Drifted           int
+	Paused            int
+}
+
+// total returns the number of clusters in every category.
+func (p statsPoint) total() int {
+	return p.NeedsRemoval + p.ReadyForMigration + p.AlreadyConfigured + p.Drifted + p.Paused
+}
+
+// configuredPercent returns the share of clusters that are already configured.
+func (p statsPoint) configuredPercent() float64 {
+	if p.total() == 0 {
+		return 0
+	}
+	return float64(p.AlreadyConfigured) * 100 / float64(p.total())
+}
+
+// mgmtClusterTrend is the migration progress of one management cluster, oldest date first.
+type mgmtClusterTrend struct {
+	MgmtClusterID string
+	Points        []statsPoint
+}
+
+// NewStatsCmd creates the stats subcommand that summarizes migration progress from saved audit reports.
+func NewStatsCmd() *cobra.Command {
+	opts := &statsOpts{}
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize fleet migration progress over time from saved audit reports",
+		Long: `Read the audit JSON reports saved with --output json --output-file in a directory and report how
+many clusters of each management cluster were in each category per date, with the fleet total.
+
+When a management cluster was audited several times on one date, its latest report of that date is used.
+Partial reports are skipped. The fleet total of a date adds up the latest report of every management
+cluster on or before that date.`,
+		Example: `
+  # Trend table with sparklines of the configured share per management cluster
+  hcp-node-autoscaling stats --reports-dir ./audits --sparkline
+
+  # Trend as CSV for a spreadsheet or dashboard
+  hcp-node-autoscaling stats --reports-dir ./audits --output csv --output-file progress.csv`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.reportsDir, "reports-dir", "", "Directory of saved audit JSON reports, searched recursively")
+	cmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text, csv")
+	cmd.Flags().StringVar(&opts.outputFile, "output-file", "", "Atomically write csv results to this file instead of stdout")
+	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Skip headers in output")
+	cmd.Flags().BoolVar(&opts.sparkline, "sparkline", false,
+		"Chart the share of already configured clusters over time for each management cluster (text output)")
+	_ = cmd.MarkFlagRequired("reports-dir")
+	opts.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// run reads the reports and prints the trends.
+func (s *statsOpts) run() error {
+	if err := output.ValidateFormat(s.output, "text", "csv"); err != nil {
+		return err
+	}
+	if err := validateOutputFile(s.outputFile, false, s.output); err != nil {
+		return err
+	}
+
+	reports, err := loadStatsReports(s.reportsDir)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("no audit JSON reports found in %s", s.reportsDir)
+	}
+	trends := computeTrends(reports)
+
+	if s.outputFile != "" {
+		err := output.WriteFile(s.outputFile, false, func(w io.Writer, _ bool) error {
+			return writeStatsCSV(w, trends, !s.noHeaders)
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("Wrote migration progress", "file", s.outputFile, "reports", len(reports))
+		return nil
+	}
+
+	if s.output == "csv" {
+		return writeStatsCSV(os.Stdout, trends, !s.noHeaders)
+	}
+	writeStatsText(os.Stdout, trends, fleetTrend(trends), s.sparkline, !s.noHeaders)
+	return nil
+}
+
+// loadStatsReports reads every audit report in the .json files under dir. Files holding several
+// appended reports are supported. Files that are not audit reports, and partial reports, are skipped.
+func loadStatsReports(dir string) ([]*auditResults, error) {
+	var reports []*auditResults
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		fileReports, err := decodeAuditReports(path)
+		if err != nil {
+			slog.Warn("Skipping file that is not an audit JSON report", "file", path, "error", err)
+			return nil
+		}
+		for _, r := range fileReports {
+			if r.Partial {
+				slog.Warn("Skipping partial audit report", "file", path, "mgmtClusterID", r.MgmtClusterID)
+				continue
+			}
+			reports = append(reports, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit reports: %v", err)
+	}
+	return reports, nil
+}
+
+// decodeAuditReports decodes the audit reports of a JSON file. Reports need a management cluster ID and
+// a valid generated_at timestamp.
+func decodeAuditReports(path string) ([]*auditResults, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reports []*auditResults
+	decoder := json.NewDecoder(f)
+	for {
+		report := &auditResults{}
+		err := decoder.Decode(report)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if report.MgmtClusterID == "" {
+			return nil, fmt.Errorf("no mgmt_cluster_id")
+		}
+		if err := checkSchemaVersion(report); err != nil {
+			return nil, err
+		}
+		if _, err := time.Parse(time.RFC3339, report.GeneratedAt); err != nil {
+			return nil, fmt.Errorf("invalid generated_at timestamp '%s'", report.GeneratedAt)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// computeTrends counts the clusters per category of each management cluster per UTC date, using the
+// latest report of a date. Trends are sorted by management cluster ID.
+func computeTrends(reports []*auditResults) []mgmtClusterTrend {
+	type latest struct {
+		generatedAt time.Time
+		report      *auditResults
+	}
+	byCluster := map[string]map[string]latest{}
+	for _, r := range reports {
+		generatedAt, _ := time.Parse(time.RFC3339, r.GeneratedAt)
+		date := generatedAt.UTC().Format(time.DateOnly)
+		if byCluster[r.MgmtClusterID] == nil {
+			byCluster[r.MgmtClusterID] = map[string]latest{}
+		}
+		if current, ok := byCluster[r.MgmtClusterID][date]; !ok || generatedAt.After(current.generatedAt) {
+			byCluster[r.MgmtClusterID][date] = latest{generatedAt: generatedAt, report: r}
+		}
+	}
+
+	trends := make([]mgmtClusterTrend, 0, len(byCluster))
+	for id, byDate := range byCluster {
+		trend := mgmtClusterTrend{MgmtClusterID: id}
+		for date, l := range byDate {
+			trend.Points = append(trend.Points, statsPoint{
+				Date:              date,
+				NeedsRemoval:      len(l.report.NeedsLabelRemoval),
+				ReadyForMigration: len(l.report.ReadyForMigration),
+				AlreadyConfigured: len(l.report.AlreadyConfigured),
+				Drifted:           len(l.report.Drifted),
+				Paused:            len(l.report.Paused),
+			})
+		}
+		sort.Slice(trend.Points, func(i, j int) bool { return trend.Points[i].Date < trend.Points[j].Date })
+		trends = append(trends, trend)
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].MgmtClusterID < trends[j].MgmtClusterID })
+	return trends
+}
+
+// fleetTrend adds up the management clusters for every date any of them was audited, carrying each
+// management cluster's latest earlier counts forward to the dates it was not audited.
+func fleetTrend(trends []mgmtClusterTrend) []statsPoint {
+	dateSet := map[string]bool{}
+	for _, t := range trends {
+		for _, p := range t.Points {
+			dateSet[p.Date] = true
+		}
+	}
+	dates := make([]string, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	fleet := make([]statsPoint, 0, len(dates))
+	for _, date := range dates {
+		total := statsPoint{Date: date}
+		for _, t := range trends {
+			var current *statsPoint
+			for i := range t.Points {
+				if t.Points[i].Date > date {
+					break
+				}
+				current = &t.Points[i]
+			}
+			if current == nil {
+				continue
+			}
+			total.NeedsRemoval += current.NeedsRemoval
+			total.ReadyForMigration += current.ReadyForMigration
+			total.AlreadyConfigured += current.AlreadyConfigured
+			total.Drifted += current.Drifted
+			total.Paused += current.Paused
+		}
+		fleet = append(fleet, total)
+	}
+	return fleet
+}
+
+// statsRow returns the table and CSV columns of a point.
+func statsRow(p statsPoint) []string {
+	return []string{
+		p.Date,
+		strconv.Itoa(p.NeedsRemoval),
+		strconv.Itoa(p.ReadyForMigration),
+		strconv.Itoa(p.AlreadyConfigured),
+		strconv.Itoa(p.Drifted),
+		strconv.Itoa(p.Paused),
+		strconv.Itoa(p.total()),
+		fmt.Sprintf("%.1f", p.configuredPercent()),
+	}
+}
+
+// writeStatsText prints a trend table per management cluster and for the fleet and, with sparkline, a
+// chart of the configured share of each.
+func writeStatsText(w io.Writer, trends []mgmtClusterTrend, fleet []statsPoint, sparkline, headers bool) {
+	printTrend := func(title string, points []statsPoint) {
+		fmt.Fprintf(w, "=== %s ===\n", title)
+		p := output.NewTable(w, output.TableMinWidth)
+		if headers {
+			p.AddRow([]string{"DATE", "NEEDS REMOVAL", "READY", "CONFIGURED", "DRIFTED", "PAUSED", "TOTAL", "CONFIGURED %"})
+		}
+		for _, point := range points {
+			p.AddRow(statsRow(point))
+		}
+		p.Flush()
+		fmt.Fprintln(w)
+	}
+
+	for _, t := range trends {
+		printTrend("Management Cluster "+t.MgmtClusterID, t.Points)
+	}
+	printTrend("Fleet", fleet)
+
+	if !sparkline {
+		return
+	}
+	fmt.Fprintln(w, "=== Configured Share ===")
+	p := output.NewTable(w, output.TableMinWidth)
+	if headers {
+		p.AddRow([]string{"MANAGEMENT CLUSTER", "TREND", "FIRST", "LAST"})
+	}
+	rows := append(append([]mgmtClusterTrend{}, trends...), mgmtClusterTrend{MgmtClusterID: "fleet", Points: fleet})
+	for _, t := range rows {
+		percents := make([]float64, 0, len(t.Points))
+		for _, point := range t.Points {
+			percents = append(percents, point.configuredPercent())
+		}
+		p.AddRow([]string{t.MgmtClusterID, sparklineOf(percents),
+			fmt.Sprintf("%.0f%%", percents[0]), fmt.Sprintf("%.0f%%", percents[len(percents)-1])})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
+
+// sparklineOf charts percentages between 0 and 100 as a row of bars.
+func sparklineOf(percents []float64) string {
+	var b strings.Builder
+	top := len(sparklineLevels) - 1
+	for _, percent := range percents {
+		level := int(percent/100*float64(top) + 0.5)
+		b.WriteRune(sparklineLevels[min(max(level, 0), top)])
+	}
+	return b.String()
+}
+
+// writeStatsCSV writes one row per management cluster and date.
+func writeStatsCSV(out io.Writer, trends []mgmtClusterTrend, headers bool) error {
+	w := csv.NewWriter(out)
+	if headers {
+		w.Write([]string{"mgmt_cluster_id", "date", "needs_removal", "ready_for_migration", "already_configured",
+			"drifted", "paused", "total", "configured_percent"})
+	}
+	for _, t := range trends {
+		for _, p := range t.Points {
+			w.Write(append([]string{t.MgmtClusterID}, statsRow(p)...))
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
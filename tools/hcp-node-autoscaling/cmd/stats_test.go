@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// writeTestReports writes audit reports to a file, appended one after the other.
+func writeTestReports(t *testing.T, path string, reports ...*auditResults) {
+	t.Helper()
+	var b bytes.Buffer
+	for _, r := range reports {
+		if err := output.JSON(&b, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testStatsReport returns a report of mgmtClusterID with the given number of ready and configured clusters.
+func testStatsReport(mgmtClusterID, generatedAt string, ready, configured int) *auditResults {
+	r := &auditResults{MgmtClusterID: mgmtClusterID, GeneratedAt: generatedAt}
+	for i := 0; i < ready; i++ {
+		r.ReadyForMigration = append(r.ReadyForMigration, hostedClusterAuditInfo{})
+	}
+	for i := 0; i < configured; i++ {
+		r.AlreadyConfigured = append(r.AlreadyConfigured, hostedClusterAuditInfo{})
+	}
+	return r
+}
+
+// TestStatsTrends verifies reports are grouped per management cluster and date, using the latest report of
+// a date, and that the fleet total carries management clusters forward to dates they were not audited.
+func TestStatsTrends(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "mc-2"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeTestReports(t, filepath.Join(dir, "mc-1.json"),
+		testStatsReport("mc-1", "2026-03-01T08:00:00Z", 4, 0),
+		testStatsReport("mc-1", "2026-03-01T18:00:00Z", 3, 1),
+		testStatsReport("mc-1", "2026-03-03T08:00:00Z", 0, 4))
+	writeTestReports(t, filepath.Join(dir, "mc-2", "audit.json"), testStatsReport("mc-2", "2026-03-02T08:00:00Z", 2, 2))
+	partial := testStatsReport("mc-2", "2026-03-03T08:00:00Z", 0, 0)
+	partial.Partial = true
+	writeTestReports(t, filepath.Join(dir, "mc-2", "partial.json"), partial)
+	if err := os.WriteFile(filepath.Join(dir, "notes.json"), []byte(`{"note": "not a report"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := loadStatsReports(dir)
+	if err != nil {
+		t.Fatalf("loadStatsReports() error = %v", err)
+	}
+	if len(reports) != 4 {
+		t.Fatalf("Expected 4 reports, got %d", len(reports))
+	}
+
+	trends := computeTrends(reports)
+	expected := []mgmtClusterTrend{
+		{MgmtClusterID: "mc-1", Points: []statsPoint{
+			{Date: "2026-03-01", ReadyForMigration: 3, AlreadyConfigured: 1},
+			{Date: "2026-03-03", AlreadyConfigured: 4},
+		}},
+		{MgmtClusterID: "mc-2", Points: []statsPoint{{Date: "2026-03-02", ReadyForMigration: 2, AlreadyConfigured: 2}}},
+	}
+	if !reflect.DeepEqual(trends, expected) {
+		t.Errorf("computeTrends() = %+v, want %+v", trends, expected)
+	}
+
+	fleet := fleetTrend(trends)
+	expectedFleet := []statsPoint{
+		{Date: "2026-03-01", ReadyForMigration: 3, AlreadyConfigured: 1},
+		{Date: "2026-03-02", ReadyForMigration: 5, AlreadyConfigured: 3},
+		{Date: "2026-03-03", ReadyForMigration: 2, AlreadyConfigured: 6},
+	}
+	if !reflect.DeepEqual(fleet, expectedFleet) {
+		t.Errorf("fleetTrend() = %+v, want %+v", fleet, expectedFleet)
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeStatsCSV(&csvOut, trends, true); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n"); len(lines) != 4 || lines[2] != "mc-1,2026-03-03,0,0,4,0,0,4,100.0" {
+		t.Errorf("Unexpected CSV output:\n%s", csvOut.String())
+	}
+
+	var text bytes.Buffer
+	writeStatsText(&text, trends, fleet, true, true)
+	for _, want := range []string{"=== Management Cluster mc-1 ===", "=== Fleet ===", "=== Configured Share ===", "▃█"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected text output to contain %q, got:\n%s", want, text.String())
+		}
+	}
+}
+
+// TestSparklineOf verifies percentages map to the lowest and highest bars at the ends of the range.
+func TestSparklineOf(t *testing.T) {
+	if result := sparklineOf([]float64{0, 50, 100}); result != "▁▅█" {
+		t.Errorf("sparklineOf() = %q, want %q", result, "▁▅█")
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// clusterAuditEvent is the result of auditing one OCM namespace: the hosted clusters found in it, or the
+// error auditing it.
+type clusterAuditEvent struct {
+	Namespace string
+	Clusters  []hostedClusterAuditInfo
+	Err       error
+}
+
+// streamAudit audits namespaces in order in the background and sends an event per namespace as soon as it
+// has been audited, so long audits can be consumed incrementally instead of waiting for the aggregate
+// results. The channel is closed when every namespace has been audited or ctx is cancelled; a namespace
+// whose audit was cut short by the cancellation is not sent. The audit clients must be initialized.
+func (a *auditOpts) streamAudit(ctx context.Context, namespaces []corev1.Namespace) <-chan clusterAuditEvent {
+	events := make(chan clusterAuditEvent)
+	go func() {
+		defer close(events)
+		for _, ns := range namespaces {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Debug("Auditing namespace", "namespace", ns.Name)
+			audited := a.session.timings().track(phaseNamespaceAudit)
+			infos, err := a.auditNamespace(ctx, ns.Name)
+			audited()
+			if err != nil && ctx.Err() != nil {
+				return
+			}
+			select {
+			case events <- clusterAuditEvent{Namespace: ns.Name, Clusters: infos, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestStreamAudit verifies an event is sent per namespace in order, carrying the audited clusters or the
+// error auditing the namespace, and that the stream is closed once every namespace has been audited.
+func TestStreamAudit(t *testing.T) {
+	ready := newTestHostedCluster("a1", nil)
+	configured := newTestHostedCluster("b2", defaultProfile.Ensure)
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(ready, configured).Build()
+	a := &auditOpts{mgmtClient: mgmtClient}
+
+	namespaces := []corev1.Namespace{*newTestNamespace(ready.Namespace), *newTestNamespace("ocm-production-c3"), *newTestNamespace(configured.Namespace)}
+	var events []clusterAuditEvent
+	for event := range a.streamAudit(context.Background(), namespaces) {
+		events = append(events, event)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	if e := events[0]; e.Namespace != ready.Namespace || e.Err != nil || len(e.Clusters) != 1 || e.Clusters[0].Category != "ready-for-migration" {
+		t.Errorf("Unexpected first event %+v", e)
+	}
+	if e := events[1]; e.Namespace != "ocm-production-c3" || e.Err == nil {
+		t.Errorf("Expected an error for the namespace without a HostedCluster, got %+v", e)
+	}
+	if e := events[2]; e.Namespace != configured.Namespace || len(e.Clusters) != 1 || e.Clusters[0].Category != "already-configured" {
+		t.Errorf("Unexpected last event %+v", e)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for event := range a.streamAudit(ctx, namespaces) {
+		t.Errorf("Expected no events after cancellation, got %+v", event)
+	}
+}
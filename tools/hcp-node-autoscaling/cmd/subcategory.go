@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	topologyAnnotation     = "hypershift.openshift.io/topology"
+	autoscalingAnnotation  = "hypershift.openshift.io/resource-based-cp-auto-scaling"
+	sizeOverrideAnnotation = "hypershift.openshift.io/cluster-size-override"
+
+	// dedicatedTopology is the topology resource-based control plane autoscaling requires.
+	dedicatedTopology = "dedicated-request-serving-components"
+)
+
+// Subcategories refine the migration category of a cluster by the state of its topology, autoscaling
+// and size override annotations, e.g. to tell a cluster that never had autoscaling configured from one
+// where it was turned off by hand.
+const (
+	subcategorySizeOverride        = "size-override"
+	subcategoryUnconfigured        = "unconfigured"
+	subcategoryTopologyOnly        = "topology-only"
+	subcategoryAutoscalingDisabled = "autoscaling-disabled"
+	subcategoryWrongTopology       = "autoscaling-wrong-topology"
+	subcategoryConfigured          = "configured"
+)
+
+// subcategories lists every subcategory in the order they are reported.
+var subcategories = []string{
+	subcategorySizeOverride,
+	subcategoryUnconfigured,
+	subcategoryTopologyOnly,
+	subcategoryAutoscalingDisabled,
+	subcategoryWrongTopology,
+	subcategoryConfigured,
+}
+
+// subcategoryOf returns the subcategory of a HostedCluster with the given annotations, where the topology is
+// the one the profile migrates the cluster to.
+func subcategoryOf(profile *migrationProfile, annotations map[string]string) string {
+	if _, ok := annotations[sizeOverrideAnnotation]; ok {
+		return subcategorySizeOverride
+	}
+
+	migrated := profile.migratedTopology()
+	topology := annotations[topologyAnnotation]
+	autoscaling, autoscalingSet := annotations[autoscalingAnnotation]
+	switch {
+	case autoscaling == "true" && topology == migrated:
+		return subcategoryConfigured
+	case autoscaling == "true":
+		return subcategoryWrongTopology
+	case autoscalingSet:
+		return subcategoryAutoscalingDisabled
+	case topology == migrated:
+		return subcategoryTopologyOnly
+	default:
+		return subcategoryUnconfigured
+	}
+}
+
+// migratedTopology returns the topology the profile ensures, e.g. a --candidates-file row's own value, or
+// the dedicated request serving topology resource-based autoscaling requires when it ensures none.
+func (p *migrationProfile) migratedTopology() string {
+	if topology, ok := p.orDefault().Ensure[topologyAnnotation]; ok {
+		return topology
+	}
+	return dedicatedTopology
+}
+
+// isSubcategory reports whether name is a subcategory rather than a category.
+func isSubcategory(name string) bool {
+	return subcategoryIndex(name) < len(subcategories)
+}
+
+// categoryReasons explains the category of a HostedCluster: the removed annotations that are set, the
+// ensured annotations that are missing or differ from the profile, and, when the profile does not ensure the
+// topology, a topology that does not support resource-based autoscaling.
+func categoryReasons(profile *migrationProfile, annotations map[string]string) []string {
+	profile = profile.orDefault()
+
+	var reasons []string
+	for _, key := range profile.Remove {
+		if value, ok := annotations[key]; ok {
+			reasons = append(reasons, fmt.Sprintf("%s is set to %q", key, value))
+		}
+	}
+	for _, key := range profile.ensureKeys() {
+		value, ok := annotations[key]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("%s is unset", key))
+		case value != profile.Ensure[key]:
+			reasons = append(reasons, fmt.Sprintf("%s is %q, not %q", key, value, profile.Ensure[key]))
+		}
+	}
+	if _, ensured := profile.Ensure[topologyAnnotation]; ensured {
+		return reasons
+	}
+	if topology := annotations[topologyAnnotation]; topology != profile.migratedTopology() {
+		reasons = append(reasons, fmt.Sprintf("%s is %s, not %s", topologyAnnotation, driftValue(topology), profile.migratedTopology()))
+	}
+	return reasons
+}
+
+// subcategoryCount is the number of clusters of a category in one subcategory.
+type subcategoryCount struct {
+	Category    string
+	Subcategory string
+	Count       int
+}
+
+// summarizeBySubcategory counts the clusters of every category per subcategory, in the order categories
+// and subcategories are reported.
+func summarizeBySubcategory(results *auditResults) []subcategoryCount {
+	var counts []subcategoryCount
+	for _, group := range categoryGroups(results) {
+		bySubcategory := map[string]int{}
+		for _, c := range group.clusters {
+			bySubcategory[c.Subcategory]++
+		}
+		keys := make([]string, 0, len(bySubcategory))
+		for key := range bySubcategory {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return subcategoryIndex(keys[i]) < subcategoryIndex(keys[j]) })
+		for _, key := range keys {
+			counts = append(counts, subcategoryCount{Category: group.category, Subcategory: key, Count: bySubcategory[key]})
+		}
+	}
+	return counts
+}
+
+// subcategoryIndex returns the report position of a subcategory. Unknown subcategories sort last.
+func subcategoryIndex(name string) int {
+	for i, s := range subcategories {
+		if s == name {
+			return i
+		}
+	}
+	return len(subcategories)
+}
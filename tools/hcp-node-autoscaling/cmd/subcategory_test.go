@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSubcategoryOf verifies clusters are told apart by their topology, autoscaling and size override annotations.
+func TestSubcategoryOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{name: "no annotations", annotations: nil, expected: subcategoryUnconfigured},
+		{
+			name:        "size override",
+			annotations: map[string]string{sizeOverrideAnnotation: "large", autoscalingAnnotation: "true"},
+			expected:    subcategorySizeOverride,
+		},
+		{
+			name:        "topology without autoscaling",
+			annotations: map[string]string{topologyAnnotation: dedicatedTopology},
+			expected:    subcategoryTopologyOnly,
+		},
+		{
+			name:        "autoscaling turned off",
+			annotations: map[string]string{topologyAnnotation: dedicatedTopology, autoscalingAnnotation: "false"},
+			expected:    subcategoryAutoscalingDisabled,
+		},
+		{
+			name:        "autoscaling without dedicated topology",
+			annotations: map[string]string{autoscalingAnnotation: "true"},
+			expected:    subcategoryWrongTopology,
+		},
+		{
+			name:        "fully configured",
+			annotations: map[string]string{topologyAnnotation: dedicatedTopology, autoscalingAnnotation: "true"},
+			expected:    subcategoryConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := subcategoryOf(nil, tt.annotations); result != tt.expected {
+				t.Errorf("subcategoryOf() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCategoryReasons verifies the reasons list the removed, missing and mismatched annotations, and the
+// topology when the profile does not ensure it.
+func TestCategoryReasons(t *testing.T) {
+	reasons := categoryReasons(nil, map[string]string{sizeOverrideAnnotation: "large", autoscalingAnnotation: "false"})
+	expected := []string{
+		`hypershift.openshift.io/cluster-size-override is set to "large"`,
+		`hypershift.openshift.io/resource-based-cp-auto-scaling is "false", not "true"`,
+		"hypershift.openshift.io/topology is unset",
+	}
+	if !reflect.DeepEqual(reasons, expected) {
+		t.Errorf("categoryReasons() = %q, want %q", reasons, expected)
+	}
+
+	autoscalingOnly := &migrationProfile{Name: "autoscaling", Ensure: map[string]string{autoscalingAnnotation: "true"}}
+	reasons = categoryReasons(autoscalingOnly, map[string]string{autoscalingAnnotation: "true", topologyAnnotation: "shared"})
+	expected = []string{"hypershift.openshift.io/topology is shared, not dedicated-request-serving-components"}
+	if !reflect.DeepEqual(reasons, expected) {
+		t.Errorf("categoryReasons() = %q, want %q", reasons, expected)
+	}
+
+	configured := map[string]string{topologyAnnotation: dedicatedTopology, autoscalingAnnotation: "true"}
+	if reasons := categoryReasons(nil, configured); len(reasons) != 0 {
+		t.Errorf("Expected no reasons for a configured cluster, got %q", reasons)
+	}
+}
+
+// TestApplyFilterSubcategory verifies --show-only with a subcategory keeps matching clusters of every category.
+func TestApplyFilterSubcategory(t *testing.T) {
+	results := &auditResults{
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "a1", Subcategory: subcategoryTopologyOnly},
+			{ClusterID: "b2", Subcategory: subcategoryUnconfigured},
+		},
+		AlreadyConfigured: []hostedClusterAuditInfo{
+			{ClusterID: "c3", Subcategory: subcategoryWrongTopology},
+			{ClusterID: "d4", Subcategory: subcategoryConfigured},
+		},
+		Paused: []hostedClusterAuditInfo{{ClusterID: "e5", Subcategory: subcategoryWrongTopology}},
+	}
+
+	filtered := (&auditOpts{showOnly: subcategoryWrongTopology}).applyFilter(results)
+	if filtered.TotalScanned != 2 || len(filtered.ReadyForMigration) != 0 ||
+		len(filtered.AlreadyConfigured) != 1 || filtered.AlreadyConfigured[0].ClusterID != "c3" || len(filtered.Paused) != 1 {
+		t.Errorf("Unexpected filtered results: %+v", filtered)
+	}
+
+	counts := summarizeBySubcategory(results)
+	expected := []subcategoryCount{
+		{Category: "ready-for-migration", Subcategory: subcategoryUnconfigured, Count: 1},
+		{Category: "ready-for-migration", Subcategory: subcategoryTopologyOnly, Count: 1},
+		{Category: "already-configured", Subcategory: subcategoryWrongTopology, Count: 1},
+		{Category: "already-configured", Subcategory: subcategoryConfigured, Count: 1},
+		{Category: "paused", Subcategory: subcategoryWrongTopology, Count: 1},
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("summarizeBySubcategory() = %+v, want %+v", counts, expected)
+	}
+}
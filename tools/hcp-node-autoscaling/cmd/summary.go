@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+)
+
+// sizeBreakdown is the number of clusters of a single current size class in each audit category.
+type sizeBreakdown struct {
+	Size              string
+	NeedsRemoval      int
+	ReadyForMigration int
+	AlreadyConfigured int
+	Drifted           int
+	Paused            int
+	Deleting          int
+	Unmanaged         int
+	Total             int
+}
+
+// summarizeByCurrentSize counts clusters per audit category for each current hosted-cluster-size,
+// sorted by size. Clusters without a size label are counted under "<unset>".
+func summarizeByCurrentSize(results *auditResults) []sizeBreakdown {
+	bySize := map[string]*sizeBreakdown{}
+	count := func(clusters []hostedClusterAuditInfo, field func(*sizeBreakdown) *int) {
+		for _, c := range clusters {
+			size := driftValue(c.CurrentSize)
+			b, ok := bySize[size]
+			if !ok {
+				b = &sizeBreakdown{Size: size}
+				bySize[size] = b
+			}
+			*field(b)++
+			b.Total++
+		}
+	}
+
+	count(results.NeedsLabelRemoval, func(b *sizeBreakdown) *int { return &b.NeedsRemoval })
+	count(results.ReadyForMigration, func(b *sizeBreakdown) *int { return &b.ReadyForMigration })
+	count(results.AlreadyConfigured, func(b *sizeBreakdown) *int { return &b.AlreadyConfigured })
+	count(results.Drifted, func(b *sizeBreakdown) *int { return &b.Drifted })
+	count(results.Paused, func(b *sizeBreakdown) *int { return &b.Paused })
+	count(results.Deleting, func(b *sizeBreakdown) *int { return &b.Deleting })
+	count(results.Unmanaged, func(b *sizeBreakdown) *int { return &b.Unmanaged })
+
+	breakdown := make([]sizeBreakdown, 0, len(bySize))
+	for _, b := range bySize {
+		breakdown = append(breakdown, *b)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].Size < breakdown[j].Size
+	})
+
+	return breakdown
+}
+
+// sizeOverrideCount is the number of Group A clusters with a single cluster-size-override value.
+type sizeOverrideCount struct {
+	Override string
+	Count    int
+}
+
+// summarizeSizeOverrides counts the Group A clusters per cluster-size-override value, sorted by value, to
+// predict the capacity impact of removing the overrides.
+func summarizeSizeOverrides(results *auditResults) []sizeOverrideCount {
+	byOverride := map[string]int{}
+	for _, c := range results.NeedsLabelRemoval {
+		byOverride[driftValue(c.SizeOverride)]++
+	}
+
+	histogram := make([]sizeOverrideCount, 0, len(byOverride))
+	for override, count := range byOverride {
+		histogram = append(histogram, sizeOverrideCount{Override: override, Count: count})
+	}
+	sort.Slice(histogram, func(i, j int) bool {
+		return histogram[i].Override < histogram[j].Override
+	})
+
+	return histogram
+}
+
+// printSummaryOutput prints only the aggregate category counts, a breakdown by current size class and a
+// histogram of the Group A size overrides.
+func (a *auditOpts) printSummaryOutput(results *auditResults) error {
+	fmt.Printf("\nManagement Cluster: %s\n", results.MgmtClusterID)
+	printRunID(os.Stdout, results.RunID)
+	fmt.Printf("Total Hosted Clusters Scanned: %d\n\n", results.TotalScanned)
+
+	if results.Partial {
+		fmt.Println("WARNING: audit was interrupted; results are partial")
+		fmt.Println()
+	}
+
+	if breakdown := summarizeByCurrentSize(results); len(breakdown) > 0 {
+		fmt.Println("=== By Current Size ===")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			header := []string{"CURRENT SIZE", "NEEDS REMOVAL", "READY", "CONFIGURED"}
+			if a.checkDrift {
+				header = append(header, "DRIFTED")
+			}
+			header = append(header, "PAUSED")
+			if len(results.Deleting) > 0 {
+				header = append(header, "DELETING")
+			}
+			if len(results.Unmanaged) > 0 {
+				header = append(header, "UNMANAGED")
+			}
+			p.AddRow(append(header, "TOTAL"))
+		}
+		for _, b := range breakdown {
+			row := []string{b.Size, strconv.Itoa(b.NeedsRemoval), strconv.Itoa(b.ReadyForMigration), strconv.Itoa(b.AlreadyConfigured)}
+			if a.checkDrift {
+				row = append(row, strconv.Itoa(b.Drifted))
+			}
+			row = append(row, strconv.Itoa(b.Paused))
+			if len(results.Deleting) > 0 {
+				row = append(row, strconv.Itoa(b.Deleting))
+			}
+			if len(results.Unmanaged) > 0 {
+				row = append(row, strconv.Itoa(b.Unmanaged))
+			}
+			p.AddRow(append(row, strconv.Itoa(b.Total)))
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if counts := summarizeBySubcategory(results); len(counts) > 0 {
+		fmt.Println("=== By Subcategory ===")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"CATEGORY", "SUBCATEGORY", "CLUSTERS"})
+		}
+		for _, c := range counts {
+			p.AddRow([]string{c.Category, driftValue(c.Subcategory), strconv.Itoa(c.Count)})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if histogram := summarizeSizeOverrides(results); len(histogram) > 0 {
+		fmt.Println("=== Group A Size Overrides ===")
+
+		p := output.NewTable(os.Stdout, output.TableMinWidth)
+		if !a.noHeaders {
+			p.AddRow([]string{"SIZE OVERRIDE", "CLUSTERS"})
+		}
+		for _, h := range histogram {
+			p.AddRow([]string{h.Override, strconv.Itoa(h.Count)})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	excluded := 0
+	for _, clusters := range [][]hostedClusterAuditInfo{results.NeedsLabelRemoval, results.ReadyForMigration, results.AlreadyConfigured, results.Drifted, results.Paused, results.Deleting, results.Unmanaged} {
+		for _, c := range clusters {
+			if c.Excluded {
+				excluded++
+			}
+		}
+	}
+
+	a.printCategoryCounts(results, excluded)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import "testing"
+
+// TestSummarizeByCurrentSize verifies clusters are counted per category for each current size class.
+func TestSummarizeByCurrentSize(t *testing.T) {
+	results := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{
+			{ClusterID: "c1", CurrentSize: "large"},
+		},
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "c2", CurrentSize: "small"},
+			{ClusterID: "c3", CurrentSize: "large"},
+			{ClusterID: "c4", CurrentSize: ""},
+		},
+		AlreadyConfigured: []hostedClusterAuditInfo{
+			{ClusterID: "c5", CurrentSize: "small"},
+		},
+		Drifted: []hostedClusterAuditInfo{
+			{ClusterID: "c6", CurrentSize: "large"},
+		},
+		Paused: []hostedClusterAuditInfo{
+			{ClusterID: "c7", CurrentSize: "small"},
+		},
+	}
+
+	expected := []sizeBreakdown{
+		{Size: "<unset>", ReadyForMigration: 1, Total: 1},
+		{Size: "large", NeedsRemoval: 1, ReadyForMigration: 1, Drifted: 1, Total: 3},
+		{Size: "small", ReadyForMigration: 1, AlreadyConfigured: 1, Paused: 1, Total: 3},
+	}
+
+	result := summarizeByCurrentSize(results)
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d sizes, got %d: %+v", len(expected), len(result), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Size %d = %+v, want %+v", i, result[i], expected[i])
+		}
+	}
+
+	if empty := summarizeByCurrentSize(&auditResults{}); len(empty) != 0 {
+		t.Errorf("Expected no sizes for empty results, got %+v", empty)
+	}
+}
+
+// TestSummarizeSizeOverrides verifies Group A clusters are counted per size override value.
+func TestSummarizeSizeOverrides(t *testing.T) {
+	results := &auditResults{
+		NeedsLabelRemoval: []hostedClusterAuditInfo{
+			{ClusterID: "c1", SizeOverride: "m5xl"},
+			{ClusterID: "c2", SizeOverride: "m52xl"},
+			{ClusterID: "c3", SizeOverride: "m5xl"},
+			{ClusterID: "c4"},
+		},
+		ReadyForMigration: []hostedClusterAuditInfo{
+			{ClusterID: "c5", SizeOverride: "m54xl"},
+		},
+	}
+
+	expected := []sizeOverrideCount{{"<unset>", 1}, {"m52xl", 1}, {"m5xl", 2}}
+	result := summarizeSizeOverrides(results)
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d overrides, got %d: %+v", len(expected), len(result), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Override %d = %+v, want %+v", i, result[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Default per-phase deadlines. They are generous so that only an unresponsive API server trips them.
+const (
+	defaultListNamespacesTimeout = 2 * time.Minute
+	defaultNamespaceTimeout      = 2 * time.Minute
+	defaultManifestWorkTimeout   = time.Minute
+)
+
+// phaseTimeouts bounds the phases of an audit or migration so that a stalled API call fails the phase
+// with an error naming it instead of hanging the run. A zero timeout disables the deadline of its phase.
+type phaseTimeouts struct {
+	listNamespaces time.Duration
+	namespace      time.Duration
+	manifestWork   time.Duration
+}
+
+// defaultPhaseTimeouts returns the per-phase deadlines used when no flags set them.
+func defaultPhaseTimeouts() phaseTimeouts {
+	return phaseTimeouts{
+		listNamespaces: defaultListNamespacesTimeout,
+		namespace:      defaultNamespaceTimeout,
+		manifestWork:   defaultManifestWorkTimeout,
+	}
+}
+
+// addFlags registers the per-phase deadline flags of an audit. With manifestWork the ManifestWork
+// update deadline of a migration is registered too.
+func (t *phaseTimeouts) addFlags(cmd *cobra.Command, manifestWork bool) {
+	cmd.Flags().DurationVar(&t.listNamespaces, "list-namespaces-timeout", defaultListNamespacesTimeout,
+		"Deadline for listing the hosted cluster namespaces of the management cluster (0 disables it)")
+	cmd.Flags().DurationVar(&t.namespace, "namespace-timeout", defaultNamespaceTimeout,
+		"Deadline for auditing each hosted cluster namespace (0 disables it)")
+	if manifestWork {
+		cmd.Flags().DurationVar(&t.manifestWork, "manifestwork-timeout", defaultManifestWorkTimeout,
+			"Deadline for each get and update of a cluster's ManifestWork, or HostedCluster with --direct (0 disables it)")
+	}
+}
+
+// withPhaseTimeout runs fn with ctx bounded by timeout. When the phase deadline, rather than the run's
+// context, expired, the error names the phase and the cluster or namespace that stalled.
+func withPhaseTimeout(ctx context.Context, timeout time.Duration, phase, target string, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s for %s timed out after %v: %w", phase, target, timeout, err)
+	}
+	return err
+}
+
+// runTimeout bounds a whole command with the global --timeout deadline.
+type runTimeout struct {
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// apply sets the run deadline on the context of the command being executed.
+func (r *runTimeout) apply(cmd *cobra.Command) {
+	if r.timeout <= 0 {
+		return
+	}
+	r.ctx, r.cancel = context.WithTimeout(cmd.Context(), r.timeout)
+	cmd.SetContext(r.ctx)
+}
+
+// finish releases the run deadline and, when it expired, says so in the command's error.
+func (r *runTimeout) finish(err error) error {
+	if r == nil || r.cancel == nil {
+		return err
+	}
+	expired := errors.Is(r.ctx.Err(), context.DeadlineExceeded)
+	r.cancel()
+
+	if err != nil && expired {
+		return fmt.Errorf("run timed out after %v: %w", r.timeout, err)
+	}
+	return err
+}
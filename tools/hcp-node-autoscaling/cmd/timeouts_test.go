@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestWithPhaseTimeout verifies only an expired phase deadline is reported as a timeout of the phase.
+func TestWithPhaseTimeout(t *testing.T) {
+	stall := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		timeout       time.Duration
+		fn            func(context.Context) error
+		expectError   bool
+		expectTimeout bool
+	}{
+		{name: "finishes in time", ctx: context.Background(), timeout: time.Second, fn: func(context.Context) error { return nil }},
+		{name: "other errors are unchanged", ctx: context.Background(), timeout: time.Second,
+			fn: func(context.Context) error { return errors.New("forbidden") }, expectError: true},
+		{name: "phase deadline expires", ctx: context.Background(), timeout: 10 * time.Millisecond, fn: stall,
+			expectError: true, expectTimeout: true},
+		{name: "run context canceled", ctx: canceled, timeout: time.Second, fn: stall, expectError: true},
+		{name: "zero disables the deadline", ctx: context.Background(), fn: func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); ok {
+				return errors.New("unexpected deadline")
+			}
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := withPhaseTimeout(tt.ctx, tt.timeout, "auditing namespace", "ocm-production-a1", tt.fn)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("withPhaseTimeout() error = %v, expectError %v", err, tt.expectError)
+			}
+			isTimeout := err != nil && strings.Contains(err.Error(), "auditing namespace for ocm-production-a1 timed out")
+			if isTimeout != tt.expectTimeout {
+				t.Errorf("Expected timeout error %v, got %v", tt.expectTimeout, err)
+			}
+		})
+	}
+}
+
+// TestRunTimeoutFinish verifies an expired run deadline is named in the error and the exit code is kept.
+func TestRunTimeoutFinish(t *testing.T) {
+	r := &runTimeout{timeout: time.Millisecond}
+	r.ctx, r.cancel = context.WithTimeout(context.Background(), r.timeout)
+	<-r.ctx.Done()
+
+	err := r.finish(withExitCode(exitInterrupted, errors.New("audit interrupted")))
+	if err == nil || !strings.HasPrefix(err.Error(), "run timed out after 1ms") {
+		t.Errorf("Expected run timeout error, got %v", err)
+	}
+	if code := ExitCode(err); code != exitInterrupted {
+		t.Errorf("Expected exit code %d, got %d", exitInterrupted, code)
+	}
+
+	if err := (&runTimeout{}).finish(nil); err != nil {
+		t.Errorf("Expected no error without a deadline, got %v", err)
+	}
+}
+
+// TestAuditNamespacesPhaseTimeout verifies a namespace whose API calls stall is reported as an audit error
+// naming it, and the remaining namespaces are still audited.
+func TestAuditNamespacesPhaseTimeout(t *testing.T) {
+	stalled := newTestHostedCluster("a1", nil)
+	healthy := newTestHostedCluster("b2", nil)
+
+	mgmtClient := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(stalled, healthy).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				if listOpts.Namespace == stalled.Namespace {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+
+	a := &auditOpts{mgmtClusterID: "mgmt-1", mgmtClient: mgmtClient, timeouts: phaseTimeouts{namespace: 20 * time.Millisecond}}
+	namespaces := []corev1.Namespace{*newTestNamespace(stalled.Namespace), *newTestNamespace(healthy.Namespace)}
+
+	results, audited := a.auditNamespaces(context.Background(), namespaces)
+	if audited != len(namespaces) {
+		t.Errorf("Expected %d namespaces audited, got %d", len(namespaces), audited)
+	}
+	if len(results.Errors) != 1 || !strings.Contains(results.Errors[0].Error, "auditing namespace for "+stalled.Namespace+" timed out") {
+		t.Errorf("Expected a timeout error for %s, got %+v", stalled.Namespace, results.Errors)
+	}
+	if total := len(results.NeedsLabelRemoval) + len(results.ReadyForMigration) + len(results.AlreadyConfigured); total != 1 {
+		t.Errorf("Expected the healthy namespace to be audited, got %d clusters", total)
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the spans of a run.
+const tracerName = "github.com/openshift-online/rosa-hcp-platform-tools/tools/hcp-node-autoscaling"
+
+// traceShutdownTimeout bounds how long the spans still buffered at the end of a run are exported for.
+const traceShutdownTimeout = 5 * time.Second
+
+// Names of the spans of a run, below the root span named after the command, e.g. "hcp-node-autoscaling migrate".
+const (
+	spanOCMManagementCluster  = "ocm.get_management_cluster"
+	spanOCMServiceCluster     = "ocm.get_service_cluster"
+	spanOCMClusterNames       = "ocm.resolve_cluster_names"
+	spanOCMCluster            = "ocm.get_cluster"
+	spanOCMClusterState       = "ocm.get_cluster_state"
+	spanNamespaceAudit        = "audit.namespace"
+	spanManifestWorkPreflight = "migrate.check_manifestworks"
+	spanMigrateCluster        = "migrate.cluster"
+	spanPatch                 = "migrate.patch"
+	spanSyncWait              = "migrate.wait_for_sync"
+)
+
+// Attributes of the spans of a run.
+const (
+	attrRunID            = "run.id"
+	attrMgmtClusterID    = "mgmt_cluster.id"
+	attrServiceClusterID = "service_cluster.id"
+	attrClusterID        = "cluster.id"
+	attrClusterIDs       = "cluster.ids"
+	attrClusterName      = "cluster.name"
+	attrNamespace        = "k8s.namespace.name"
+	attrPatchTarget      = "patch.target"
+	attrRetry            = "migration.retry"
+	attrMigrationStatus  = "migration.status"
+	attrFailureClass     = "migration.failure_class"
+)
+
+// tracingEnabled reports whether an OTLP endpoint to export traces to is configured in the environment.
+func tracingEnabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// runTracing traces a whole command as a root span, exported over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT
+// or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set. The exporter reads the other OTEL_EXPORTER_OTLP_* variables,
+// e.g. for headers or TLS, itself.
+type runTracing struct {
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+	span     trace.Span
+	finished bool
+}
+
+// apply starts the root span of the command being executed by the run with the given ID and sets it on the
+// command's context. It does nothing when no OTLP endpoint is configured.
+func (r *runTracing) apply(cmd *cobra.Command, runID string) error {
+	if !tracingEnabled() {
+		return nil
+	}
+	exporter, err := otlptracehttp.New(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP trace exporter: %v", err)
+	}
+	r.start(cmd, sdktrace.NewBatchSpanProcessor(exporter), runID)
+	return nil
+}
+
+// start installs a tracer provider sending the spans to processor and starts the root span of cmd.
+func (r *runTracing) start(cmd *cobra.Command, processor sdktrace.SpanProcessor, runID string) {
+	r.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "hcp-node-autoscaling"))),
+	)
+	otel.SetTracerProvider(r.provider)
+
+	ctx, span := startSpan(cmd.Context(), cmd.CommandPath(), attribute.String(attrRunID, runID))
+	r.span = span
+	cmd.SetContext(ctx)
+}
+
+// finish ends the root span, marking it failed when err is set, and exports the spans still buffered. It only
+// does so once, so it can be called both after a successful run and when the run failed. Traces are best
+// effort: an export failure is logged and err is returned unchanged.
+func (r *runTracing) finish(err error) error {
+	if r == nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.provider == nil || r.finished {
+		return err
+	}
+	r.finished = true
+
+	endSpan(r.span, err)
+	ctx, cancel := context.WithTimeout(context.Background(), traceShutdownTimeout)
+	defer cancel()
+	if shutdownErr := r.provider.Shutdown(ctx); shutdownErr != nil {
+		slog.Warn("Failed to export traces", "error", shutdownErr)
+	}
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	return err
+}
+
+// startSpan starts a span below the span of ctx. Without tracing it returns a span that records nothing.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends a span, recording err on it and marking it failed when err is set.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traced runs fn in a span named name.
+func traced(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) error {
+	ctx, span := startSpan(ctx, name, attrs...)
+	err := fn(ctx)
+	endSpan(span, err)
+	return err
+}
+
+// setRunAttributes adds attributes, e.g. the management cluster once resolved, to the span of ctx.
+func setRunAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// clusterAttributes returns the span attributes identifying a hosted cluster.
+func clusterAttributes(info hostedClusterAuditInfo) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String(attrClusterID, info.ClusterID),
+		attribute.String(attrClusterName, info.ClusterName),
+		attribute.String(attrNamespace, info.Namespace),
+	}
+}
+
+// clusterIDsAttribute returns the span attribute listing the IDs of the audited hosted clusters.
+func clusterIDsAttribute(infos []hostedClusterAuditInfo) attribute.KeyValue {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.ClusterID)
+	}
+	return attribute.StringSlice(attrClusterIDs, ids)
+}
+
+// migrateClusterTraced migrates a cluster in a span recording the outcome of the migration.
+func (m *migrateOpts) migrateClusterTraced(ctx context.Context, info hostedClusterAuditInfo, retry int) migrationResult {
+	ctx, span := startSpan(ctx, spanMigrateCluster, append(clusterAttributes(info), attribute.Int(attrRetry, retry))...)
+	result := m.migrateCluster(ctx, info)
+	span.SetAttributes(attribute.String(attrMigrationStatus, result.Status))
+	if result.Status == "failed" {
+		span.SetAttributes(attribute.String(attrFailureClass, result.FailureClass))
+		span.SetStatus(codes.Error, result.Error)
+	}
+	span.End()
+	return result
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// startTestTracing traces a command run into a span recorder, and returns the recorder with the command
+// whose context holds the root span.
+func startTestTracing(t *testing.T) (*runTracing, *cobra.Command, *tracetest.SpanRecorder) {
+	t.Helper()
+	cmd := &cobra.Command{Use: "migrate"}
+	cmd.SetContext(context.Background())
+	recorder := tracetest.NewSpanRecorder()
+	r := &runTracing{}
+	r.start(cmd, recorder, "run-123")
+	t.Cleanup(func() { r.finish(nil) })
+	return r, cmd, recorder
+}
+
+// spanAttribute returns the value of an attribute of a span, and whether the span has it.
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// TestRunTracing verifies the spans of a run are children of the root span named after the command, failed
+// spans record their error and finishing twice ends the root span once.
+func TestRunTracing(t *testing.T) {
+	r, cmd, recorder := startTestTracing(t)
+
+	info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: "cluster-a1", Namespace: "ocm-production-a1"}
+	err := traced(cmd.Context(), spanSyncWait, func(context.Context) error {
+		return errors.New("sync timed out")
+	}, clusterAttributes(info)...)
+	if err == nil || err.Error() != "sync timed out" {
+		t.Fatalf("traced() error = %v, want the error of fn", err)
+	}
+	setRunAttributes(cmd.Context(), attribute.String(attrMgmtClusterID, "mc-id"))
+
+	runErr := errors.New("migration failed")
+	if err := r.finish(runErr); err != runErr {
+		t.Errorf("finish() = %v, want the run error", err)
+	}
+	if err := r.finish(nil); err != nil {
+		t.Errorf("Second finish() = %v, want nil", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 ended spans, got %d", len(spans))
+	}
+	sync, root := spans[0], spans[1]
+	if sync.Name() != spanSyncWait || root.Name() != "migrate" {
+		t.Errorf("Unexpected span names %q and %q", sync.Name(), root.Name())
+	}
+	if sync.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("Expected the sync wait span to be a child of the root span")
+	}
+	if sync.Status().Code != codes.Error || root.Status().Code != codes.Error || root.Status().Description != "migration failed" {
+		t.Errorf("Unexpected span status %+v and %+v", sync.Status(), root.Status())
+	}
+	for span, expected := range map[sdktrace.ReadOnlySpan]map[string]string{
+		sync: {attrClusterID: "a1", attrClusterName: "cluster-a1", attrNamespace: "ocm-production-a1"},
+		root: {attrRunID: "run-123", attrMgmtClusterID: "mc-id"},
+	} {
+		for key, value := range expected {
+			if got, ok := spanAttribute(span, key); !ok || got.AsString() != value {
+				t.Errorf("Span %s attribute %s = %q, want %q", span.Name(), key, got.AsString(), value)
+			}
+		}
+	}
+}
+
+// TestRunTracingDisabled verifies nothing is traced without an OTLP endpoint.
+func TestRunTracingDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	cmd := &cobra.Command{Use: "audit"}
+	cmd.SetContext(context.Background())
+	r := &runTracing{}
+	if err := r.apply(cmd, "run-123"); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	if r.provider != nil {
+		t.Error("Expected no tracer provider without an OTLP endpoint")
+	}
+	if _, span := startSpan(cmd.Context(), spanNamespaceAudit); span.IsRecording() {
+		t.Error("Expected spans not to be recorded")
+	}
+	runErr := errors.New("boom")
+	if err := r.finish(runErr); err != runErr {
+		t.Errorf("finish() = %v, want the run error", err)
+	}
+}
+
+// TestMigrateClusterTraced verifies each migration attempt is a span with the cluster, retry and outcome.
+func TestMigrateClusterTraced(t *testing.T) {
+	_, cmd, recorder := startTestTracing(t)
+
+	m := &migrateOpts{}
+	info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: "cluster-a1", Category: unmanagedCategory}
+	if result := m.migrateClusterTraced(cmd.Context(), info, 2); result.Status != "failed" {
+		t.Fatalf("Expected the unmanaged cluster to fail, got %+v", result)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != spanMigrateCluster {
+		t.Fatalf("Expected one %s span, got %d", spanMigrateCluster, len(spans))
+	}
+	span := spans[0]
+	if status, _ := spanAttribute(span, attrMigrationStatus); status.AsString() != "failed" {
+		t.Errorf("Expected status failed, got %q", status.AsString())
+	}
+	if retry, _ := spanAttribute(span, attrRetry); retry.AsInt64() != 2 {
+		t.Errorf("Expected retry 2, got %d", retry.AsInt64())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected the span to be failed, got %+v", span.Status())
+	}
+}
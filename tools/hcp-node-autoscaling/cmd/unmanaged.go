@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// unmanagedCategory is the audit category of HostedClusters outside the OCM namespaces. They are not
+// delivered by a ManifestWork, so they are reported by audit --include-unmanaged but never migrated.
+const unmanagedCategory = "unmanaged"
+
+// unmanagedNamespace reports whether a hosted cluster namespace is outside the ocm-* namespaces that OCM
+// creates for the clusters it manages.
+func unmanagedNamespace(name string) bool {
+	return !strings.HasPrefix(name, "ocm-")
+}
+
+// listUnmanagedNamespaces returns the namespaces outside the OCM namespaces that hold HostedClusters, sorted
+// by name, and records them so that their clusters are categorized as unmanaged. Namespaces matching
+// --namespace-exclude-pattern are left out. HostedClusters are listed across all namespaces in pages, with
+// the list namespaces deadline applying to each page.
+func (a *auditOpts) listUnmanagedNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	found := map[string]bool{}
+	continueToken := ""
+	for {
+		hcList := &hypershiftv1beta1.HostedClusterList{}
+		err := withPhaseTimeout(ctx, a.timeouts.listNamespaces, "listing hosted clusters", "management cluster "+a.mgmtClusterID,
+			func(ctx context.Context) error {
+				pageSize := a.listing.pageSize
+				if pageSize <= 0 {
+					pageSize = defaultNamespacePageSize
+				}
+				return a.mgmtClient.List(ctx, hcList, client.Limit(pageSize), client.Continue(continueToken))
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hc := range hcList.Items {
+			if unmanagedNamespace(hc.Namespace) && (a.listing.exclude == nil || !a.listing.exclude.MatchString(hc.Namespace)) {
+				found[hc.Namespace] = true
+			}
+		}
+
+		if hcList.Continue == "" {
+			break
+		}
+		continueToken = hcList.Continue
+	}
+
+	a.unmanagedNamespaces = found
+	namespaces := make([]corev1.Namespace, 0, len(found))
+	for name := range found {
+		namespaces = append(namespaces, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	slog.Debug("Listed unmanaged hosted cluster namespaces", "count", len(namespaces))
+	return namespaces, nil
+}
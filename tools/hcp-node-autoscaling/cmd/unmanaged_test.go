@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestAuditUnmanagedClusters verifies that HostedClusters outside the OCM namespaces are audited as unmanaged,
+// that OCM namespaces of other environments and excluded namespaces are left out, and that unmanaged clusters
+// are counted with the rest.
+func TestAuditUnmanagedClusters(t *testing.T) {
+	managed := newTestHostedCluster("a1", nil)
+	staging := newTestHostedCluster("b2", nil)
+	staging.Namespace = "ocm-staging-b2"
+	unmanaged := newTestHostedCluster("c3", nil)
+	unmanaged.Namespace = "internal-hcp"
+	excluded := newTestHostedCluster("d4", nil)
+	excluded.Namespace = "scratch-hcp"
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(testScheme(t)).
+		WithObjects(newTestNamespace(managed.Namespace), managed, newTestNamespace(staging.Namespace), staging,
+			newTestNamespace(unmanaged.Namespace), unmanaged, newTestNamespace(excluded.Namespace), excluded).
+		Build()
+	a := &auditOpts{mgmtClusterID: "mgmt-123", environment: "production", mgmtClient: mgmtClient, includeUnmanaged: true,
+		listing: namespaceListing{exclude: regexp.MustCompile("^scratch-")}}
+
+	namespaces, err := a.listOcmNamespaces(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmanagedNamespaces, err := a.listUnmanagedNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("listUnmanagedNamespaces() error = %v", err)
+	}
+	if len(unmanagedNamespaces) != 1 || unmanagedNamespaces[0].Name != "internal-hcp" {
+		t.Fatalf("listUnmanagedNamespaces() = %v, want internal-hcp", unmanagedNamespaces)
+	}
+
+	results, _ := a.auditNamespaces(context.Background(), append(namespaces, unmanagedNamespaces...))
+	if len(results.ReadyForMigration) != 1 || results.ReadyForMigration[0].ClusterID != "a1" {
+		t.Errorf("Expected a1 ready for migration, got %+v", results.ReadyForMigration)
+	}
+	if len(results.Unmanaged) != 1 || results.Unmanaged[0].ClusterID != "c3" || results.Unmanaged[0].Category != unmanagedCategory {
+		t.Errorf("Expected c3 unmanaged, got %+v", results.Unmanaged)
+	}
+	if results.TotalScanned != 2 {
+		t.Errorf("TotalScanned = %d, want 2", results.TotalScanned)
+	}
+}
+
+// TestMigrateClusterRefusesUnmanaged verifies an unmanaged cluster is refused before anything is read or patched.
+func TestMigrateClusterRefusesUnmanaged(t *testing.T) {
+	m := &migrateOpts{
+		profile:       topologyProfile,
+		mgmtClient:    fake.NewClientBuilder().WithScheme(testScheme(t)).Build(),
+		serviceClient: fake.NewClientBuilder().WithScheme(testScheme(t)).Build(),
+	}
+	info := hostedClusterAuditInfo{ClusterID: "c3", ClusterName: "cluster-c3", Namespace: "internal-hcp", Category: unmanagedCategory}
+
+	result := m.migrateCluster(context.Background(), info)
+	if result.Status != "failed" || !strings.Contains(result.Error, "no ManifestWork exists") {
+		t.Errorf("migrateCluster() = %+v, want a refusal", result)
+	}
+}
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// pendingVerificationStatus is the status of a migration result for a cluster that was patched with
+	// --no-verify and whose sync is verified later by the verify command.
+	pendingVerificationStatus = "pending-verification"
+
+	// defaultPendingFile is the file migrate --no-verify records patched clusters to and verify reads.
+	defaultPendingFile = "pending-verification.jsonl"
+)
+
+// pendingVerification is a cluster patched by migrate --no-verify, recorded with what verify needs to
+// check that the annotations synced to the management cluster.
+type pendingVerification struct {
+	ClusterID        string            `json:"cluster_id"`
+	ClusterName      string            `json:"cluster_name"`
+	Namespace        string            `json:"namespace"`
+	MgmtClusterID    string            `json:"mgmt_cluster_id"`
+	MgmtClusterName  string            `json:"mgmt_cluster_name,omitempty"`
+	ServiceClusterID string            `json:"service_cluster_id,omitempty"`
+	Target           string            `json:"target"`
+	Profile          string            `json:"profile"`
+	Ensure           map[string]string `json:"ensure"`
+	Remove           []string          `json:"remove,omitempty"`
+	PatchedAt        string            `json:"patched_at"`
+	RunID            string            `json:"run_id,omitempty"`
+}
+
+// auditInfo returns the cluster to verify.
+func (p pendingVerification) auditInfo() hostedClusterAuditInfo {
+	return hostedClusterAuditInfo{ClusterID: p.ClusterID, ClusterName: p.ClusterName, Namespace: p.Namespace}
+}
+
+// profile returns the annotations the cluster was patched with.
+func (p pendingVerification) profile() *migrationProfile {
+	return &migrationProfile{Name: p.Profile, Ensure: p.Ensure, Remove: p.Remove}
+}
+
+// pendingRecorder appends the clusters patched with --no-verify to the pending file as JSON lines, so
+// the clusters patched before an interrupted run are not lost. A nil *pendingRecorder records nothing.
+type pendingRecorder struct {
+	mu   sync.Mutex
+	path string
+}
+
+// record appends a cluster to the pending file.
+func (r *pendingRecorder) record(p pendingVerification) error {
+	if r == nil {
+		return nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode pending verification: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open pending file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write pending file: %v", err)
+	}
+	return nil
+}
+
+// pendingVerification returns the pending file entry of a cluster patched through target.
+func (m *migrateOpts) pendingVerification(info hostedClusterAuditInfo, target string) pendingVerification {
+	profile := m.clusterProfile(info.ClusterID).orDefault()
+	return pendingVerification{
+		ClusterID:        info.ClusterID,
+		ClusterName:      info.ClusterName,
+		Namespace:        info.Namespace,
+		MgmtClusterID:    m.mgmtClusterID,
+		MgmtClusterName:  m.mgmtClusterName,
+		ServiceClusterID: m.serviceClusterID,
+		Target:           target,
+		Profile:          profile.Name,
+		Ensure:           profile.Ensure,
+		Remove:           profile.Remove,
+		PatchedAt:        time.Now().UTC().Format(time.RFC3339),
+		RunID:            m.session.runID(),
+	}
+}
+
+// loadPendingVerifications reads the clusters of a pending file. A missing file has no clusters.
+func loadPendingVerifications(path string) ([]pendingVerification, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending file: %v", err)
+	}
+	defer f.Close()
+
+	var pending []pendingVerification
+	decoder := json.NewDecoder(f)
+	for {
+		var p pendingVerification
+		err := decoder.Decode(&p)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pending file %s: %v", path, err)
+		}
+		if p.ClusterID == "" || p.MgmtClusterID == "" || p.Namespace == "" || p.ClusterName == "" {
+			return nil, fmt.Errorf("failed to parse pending file %s: entry %d needs cluster_id, cluster_name, namespace and mgmt_cluster_id", path, len(pending)+1)
+		}
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
+
+// savePendingVerifications atomically replaces the pending file with the clusters still to verify, and
+// removes it once none are left.
+func savePendingVerifications(path string, pending []pendingVerification) error {
+	if len(pending) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove pending file: %v", err)
+		}
+		return nil
+	}
+	err := output.WriteFile(path, false, func(w io.Writer, _ bool) error {
+		encoder := json.NewEncoder(w)
+		for _, p := range pending {
+			if err := encoder.Encode(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write pending file: %v", err)
+	}
+	return nil
+}
+
+// verifyOpts holds the options of the verify command. Sync is verified with migrate's options, one
+// management cluster of the pending file at a time.
+type verifyOpts struct {
+	pendingFile   string
+	mgmtClusterID string
+	migrate       migrateOpts
+}
+
+// verifyResult is the outcome of verifying a pending cluster.
+type verifyResult struct {
+	pending pendingVerification
+	result  migrationResult
+}
+
+// NewVerifyCmd creates the verify subcommand that completes the verification of clusters patched with
+// migrate --no-verify.
+func NewVerifyCmd() *cobra.Command {
+	opts := &verifyOpts{}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that clusters patched with migrate --no-verify synced to their management clusters",
+		Long: `Verify the clusters recorded in the pending file by migrate --no-verify.
+
+Each cluster is checked, as migrate does, until the annotations it was patched with are on its
+HostedCluster on the management cluster or --sync-timeout passes. Verified clusters are removed from
+the pending file, and clusters that could not be verified are kept so verify can be run again. The
+file is removed once every cluster is verified.
+
+verify only reads the management and service clusters and needs no --ticket. Run it after the
+migrations that write the pending file have finished.`,
+		Example: `
+  # Patch quickly and verify later
+  hcp-node-autoscaling migrate --ticket OHSS-12345 --mgmt-cluster-id mgmt-456 --no-verify
+  hcp-node-autoscaling verify
+
+  # Verify only the clusters of one management cluster, waiting up to 10 minutes for each
+  hcp-node-autoscaling verify --pending-file pending.jsonl --mgmt-cluster-id mgmt-456 --sync-timeout 10m`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.pendingFile, "pending-file", defaultPendingFile,
+		"File of clusters pending verification, written by migrate --no-verify")
+	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "",
+		"Only verify the clusters of this management cluster ID or name (default: every cluster in the pending file)")
+	cmd.Flags().DurationVar(&opts.migrate.syncTimeout, "sync-timeout", defaultSyncTimeout,
+		"Maximum time to wait for the annotations of each cluster to sync to the management cluster")
+	cmd.Flags().DurationVar(&opts.migrate.pollInterval, "poll-interval", defaultPollInterval,
+		"Interval between sync verification attempts")
+	opts.migrate.kubeconfigs.addFlags(cmd, true)
+	opts.migrate.session = addRunSession(cmd)
+
+	return cmd
+}
+
+// validate checks the sync settings and that kubeconfig overrides apply to a single management cluster.
+func (v *verifyOpts) validate() error {
+	if err := validateSyncSettings(v.migrate.syncTimeout, v.migrate.pollInterval); err != nil {
+		return err
+	}
+	if err := v.migrate.kubeconfigs.validate(); err != nil {
+		return err
+	}
+	if (v.migrate.kubeconfigs.mgmt != "" || v.migrate.kubeconfigs.service != "") && v.mgmtClusterID == "" {
+		return fmt.Errorf("--mgmt-kubeconfig and --service-kubeconfig require --mgmt-cluster-id")
+	}
+	return nil
+}
+
+// run verifies the clusters of the pending file and rewrites it with the clusters that are left.
+func (v *verifyOpts) run(ctx context.Context) error {
+	if err := v.validate(); err != nil {
+		return err
+	}
+
+	pending, err := loadPendingVerifications(v.pendingFile)
+	if err != nil {
+		return err
+	}
+	var selected, others []pendingVerification
+	for _, p := range pending {
+		if v.mgmtClusterID == "" || v.mgmtClusterID == p.MgmtClusterID || v.mgmtClusterID == p.MgmtClusterName {
+			selected = append(selected, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintf(v.migrate.session.infoOut(), "No clusters pending verification in %s\n", v.pendingFile)
+		return withExitCode(exitNothingToDo, fmt.Errorf("nothing to do: no clusters pending verification in %s", v.pendingFile))
+	}
+
+	results := v.verify(ctx, selected)
+
+	remaining := others
+	var migrationResults []migrationResult
+	for _, r := range results {
+		migrationResults = append(migrationResults, r.result)
+		if r.result.Status != "success" {
+			remaining = append(remaining, r.pending)
+		}
+	}
+	notStarted := selected[len(results):]
+	remaining = append(remaining, notStarted...)
+	if err := savePendingVerifications(v.pendingFile, remaining); err != nil {
+		return err
+	}
+
+	displayVerifyResults(v.migrate.session.infoOut(), v.migrate.session.runID(), results, len(notStarted), len(remaining), v.pendingFile)
+
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted,
+			fmt.Errorf("verification interrupted: %d of %d clusters not started", len(notStarted), len(selected)))
+	}
+	return migrationExitError(migrationResults)
+}
+
+// verify verifies the pending clusters, grouped by management and service cluster so each pair of clients
+// is created once. It stops starting clusters when ctx is cancelled and returns the results in the order
+// of pending.
+func (v *verifyOpts) verify(ctx context.Context, pending []pendingVerification) []verifyResult {
+	var results []verifyResult
+	clients := map[string]*migrateOpts{}
+	clientErrs := map[string]error{}
+
+	for _, p := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		key := p.MgmtClusterID + "/" + p.ServiceClusterID
+		m, ok := clients[key]
+		if !ok && clientErrs[key] == nil {
+			m, clientErrs[key] = v.clientsFor(p)
+			clients[key] = m
+		}
+		if err := clientErrs[key]; err != nil {
+			results = append(results, verifyResult{pending: p, result: migrationResult{
+				ClusterID:    p.ClusterID,
+				ClusterName:  p.ClusterName,
+				Status:       "failed",
+				FailureClass: classifyFailure(err),
+				Error:        err.Error(),
+			}})
+			continue
+		}
+		results = append(results, verifyResult{pending: p, result: m.verifyPending(ctx, p)})
+	}
+	return results
+}
+
+// clientsFor returns migrate options with non-elevated clients for the management and service cluster of
+// a pending cluster. The service cluster is only read to report the ManifestWork status while waiting, so
+// verification continues without it when it cannot be reached.
+func (v *verifyOpts) clientsFor(p pendingVerification) (*migrateOpts, error) {
+	m := v.migrate
+	m.mgmtClusterID = p.MgmtClusterID
+	m.mgmtClusterName = p.MgmtClusterName
+	m.serviceClusterID = p.ServiceClusterID
+
+	clientScheme, err := scheme.Cached(scheme.HyperShift, scheme.Work)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := m.kubeconfigs.clients(m.clients, m.session.apiRateLimit(), m.mgmtClusterID, m.serviceClusterID)
+	mgmtClient, err := clients.NewClient(m.mgmtClusterID, clientScheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	m.mgmtClient = mgmtClient
+
+	if m.serviceClusterID != "" {
+		serviceClient, err := clients.NewClient(m.serviceClusterID, clientScheme)
+		if err != nil {
+			slog.Warn("Failed to create service cluster client; ManifestWork status will not be checked",
+				"serviceClusterID", m.serviceClusterID, "error", err)
+		} else {
+			m.serviceClient = serviceClient
+		}
+	}
+	return &m, nil
+}
+
+// verifyPending waits for the annotations a pending cluster was patched with to sync to the management cluster.
+func (m *migrateOpts) verifyPending(ctx context.Context, p pendingVerification) migrationResult {
+	result := migrationResult{ClusterID: p.ClusterID, ClusterName: p.ClusterName}
+	m.profile = p.profile()
+	m.direct = p.Target == "HostedCluster"
+
+	syncStart := time.Now()
+	err := m.waitForSync(ctx, p.auditInfo())
+	result.SyncSeconds = time.Since(syncStart).Seconds()
+	switch {
+	case err != nil && ctx.Err() != nil:
+		result.Status = "interrupted"
+		result.Error = "interrupted before sync to the management cluster was verified"
+	case err != nil:
+		result.Status = "failed"
+		result.FailureClass = classifyFailure(err)
+		result.Error = fmt.Sprintf("sync verification failed: %v", err)
+	default:
+		result.Status = "success"
+		result.VerifiedAt = time.Now().Format(time.RFC3339)
+	}
+	return result
+}
+
+// displayVerifyResults prints the outcome of each verified cluster of the run with the given ID and how many
+// are left in the pending file.
+func displayVerifyResults(w io.Writer, runID string, results []verifyResult, notStarted, remaining int, pendingFile string) {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.result.Status]++
+	}
+
+	fmt.Fprintf(w, "\n=== Verification Summary ===\n\n")
+	printRunID(w, runID)
+	fmt.Fprintf(w, "Verified: %d\n", counts["success"])
+	fmt.Fprintf(w, "Failed: %d\n", counts["failed"])
+	if counts["interrupted"] > 0 || notStarted > 0 {
+		fmt.Fprintf(w, "Interrupted: %d\n", counts["interrupted"])
+		fmt.Fprintf(w, "Not started: %d\n", notStarted)
+	}
+	if remaining > 0 {
+		fmt.Fprintf(w, "Still pending verification: %d (pending file %s)\n", remaining, pendingFile)
+	} else {
+		fmt.Fprintf(w, "Every cluster is verified; removed pending file %s\n", pendingFile)
+	}
+	fmt.Fprintln(w)
+
+	if len(results) == 0 {
+		return
+	}
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "MGMT CLUSTER", "PATCHED AT", "STATUS", "DETAIL"})
+	for _, r := range results {
+		detail := r.result.Error
+		if r.result.Status == "success" {
+			detail = fmt.Sprintf("synced in %s", r.result.syncDuration().Round(time.Second))
+		}
+		mgmtCluster := r.pending.MgmtClusterName
+		if mgmtCluster == "" {
+			mgmtCluster = r.pending.MgmtClusterID
+		}
+		p.AddRow([]string{r.pending.ClusterID, r.pending.ClusterName, mgmtCluster, r.pending.PatchedAt,
+			r.result.Status, detail})
+	}
+	p.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestPendingVerification returns a cluster of mgmt-123 pending verification of the topology profile.
+func newTestPendingVerification(id string) pendingVerification {
+	return pendingVerification{
+		ClusterID:        id,
+		ClusterName:      "cluster-" + id,
+		Namespace:        "ocm-production-" + id,
+		MgmtClusterID:    "mgmt-123",
+		MgmtClusterName:  "mgmt-cluster",
+		ServiceClusterID: "svc-123",
+		Target:           "ManifestWork",
+		Profile:          topologyProfile.Name,
+		Ensure:           topologyProfile.Ensure,
+		PatchedAt:        "2026-10-15T10:00:00Z",
+	}
+}
+
+// TestPendingVerificationFile verifies that recorded clusters are read back in order, that the file is
+// rewritten with the clusters left and removed once none are.
+func TestPendingVerificationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.jsonl")
+
+	if pending, err := loadPendingVerifications(path); err != nil || pending != nil {
+		t.Fatalf("loadPendingVerifications() on a missing file = %v, %v, want no clusters", pending, err)
+	}
+
+	recorder := &pendingRecorder{path: path}
+	expected := []pendingVerification{newTestPendingVerification("a1"), newTestPendingVerification("a2")}
+	for _, p := range expected {
+		if err := recorder.record(p); err != nil {
+			t.Fatalf("record() error = %v", err)
+		}
+	}
+	var nilRecorder *pendingRecorder
+	if err := nilRecorder.record(newTestPendingVerification("a3")); err != nil {
+		t.Errorf("record() on a nil recorder error = %v", err)
+	}
+
+	pending, err := loadPendingVerifications(path)
+	if err != nil {
+		t.Fatalf("loadPendingVerifications() error = %v", err)
+	}
+	if !reflect.DeepEqual(pending, expected) {
+		t.Errorf("loadPendingVerifications() = %+v, want %+v", pending, expected)
+	}
+
+	if err := savePendingVerifications(path, expected[1:]); err != nil {
+		t.Fatalf("savePendingVerifications() error = %v", err)
+	}
+	if pending, _ := loadPendingVerifications(path); !reflect.DeepEqual(pending, expected[1:]) {
+		t.Errorf("loadPendingVerifications() after save = %+v, want %+v", pending, expected[1:])
+	}
+
+	if err := savePendingVerifications(path, nil); err != nil {
+		t.Fatalf("savePendingVerifications() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the pending file to be removed once no clusters are left, got %v", err)
+	}
+}
+
+// TestLoadPendingVerificationsInvalid verifies entries missing what verify needs are rejected.
+func TestLoadPendingVerificationsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not JSON", "not json\n"},
+		{"no management cluster", `{"cluster_id":"a1","cluster_name":"cluster-a1","namespace":"ocm-production-a1"}` + "\n"},
+		{"no namespace", `{"cluster_id":"a1","cluster_name":"cluster-a1","mgmt_cluster_id":"mgmt-123"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pending.jsonl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadPendingVerifications(path); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// TestMigrateClusterNoVerify verifies that with --no-verify a patched cluster is recorded to the pending
+// file without waiting for sync, and does not fail the run.
+func TestMigrateClusterNoVerify(t *testing.T) {
+	scheme := testScheme(t)
+	hc := newTestHostedCluster("a1", nil)
+	path := filepath.Join(t.TempDir(), "pending.jsonl")
+
+	m := &migrateOpts{
+		profile:          topologyProfile,
+		mgmtClusterID:    "mgmt-123",
+		mgmtClusterName:  "mgmt-cluster",
+		serviceClusterID: "svc-123",
+		syncTimeout:      time.Hour,
+		pollInterval:     time.Hour,
+		noVerify:         true,
+		pendingFile:      path,
+		pending:          &pendingRecorder{path: path},
+		mgmtClient:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(hc).Build(),
+		serviceClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestManifestWork(t, "mgmt-cluster", hc)).Build(),
+	}
+	info := hostedClusterAuditInfo{ClusterID: "a1", ClusterName: hc.Name, Namespace: hc.Namespace, Category: "ready-for-migration"}
+
+	result := m.migrateCluster(context.Background(), info)
+	if result.Status != pendingVerificationStatus || result.Error != "" || result.VerifiedAt != "" {
+		t.Fatalf("migrateCluster() = %+v, want a %s result", result, pendingVerificationStatus)
+	}
+	if err := migrationExitError([]migrationResult{result}); err != nil {
+		t.Errorf("Expected a cluster pending verification not to fail the run, got %v", err)
+	}
+
+	pending, err := loadPendingVerifications(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 cluster pending verification, got %+v", pending)
+	}
+	p := pending[0]
+	if p.ClusterID != "a1" || p.MgmtClusterID != "mgmt-123" || p.ServiceClusterID != "svc-123" || p.Target != "ManifestWork" ||
+		!reflect.DeepEqual(p.Ensure, topologyProfile.Ensure) {
+		t.Errorf("Unexpected pending verification %+v", p)
+	}
+}
+
+// TestVerifyPending verifies that synced clusters are verified, that clusters whose annotations did not sync
+// fail, and that the results follow the order of the pending file.
+func TestVerifyPending(t *testing.T) {
+	scheme := testScheme(t)
+	synced := newTestHostedCluster("a1", topologyProfile.Ensure)
+	notSynced := newTestHostedCluster("a2", nil)
+
+	v := &verifyOpts{migrate: migrateOpts{
+		syncTimeout:  50 * time.Millisecond,
+		pollInterval: 10 * time.Millisecond,
+		clients: &fakeClientFactory{clients: map[string]client.Client{
+			"mgmt-123": fake.NewClientBuilder().WithScheme(scheme).WithObjects(synced, notSynced).Build(),
+			"svc-123":  fake.NewClientBuilder().WithScheme(scheme).Build(),
+		}},
+	}}
+	other := newTestPendingVerification("b1")
+	other.MgmtClusterID = "mgmt-unknown"
+
+	results := v.verify(context.Background(), []pendingVerification{
+		newTestPendingVerification("a1"), newTestPendingVerification("a2"), other,
+	})
+
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.result.ClusterID+"="+r.result.Status)
+	}
+	expected := []string{"a1=success", "a2=failed", "b1=failed"}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("verify() statuses = %v, want %v", statuses, expected)
+	}
+	if results[0].result.VerifiedAt == "" {
+		t.Error("Expected the verified cluster to have a verification time")
+	}
+	if results[1].result.FailureClass != failureSyncTimeout {
+		t.Errorf("Expected a %s failure, got %+v", failureSyncTimeout, results[1].result)
+	}
+}
+
+// TestVerifyNothingPending verifies verify exits with the nothing to do code when no cluster of the selected
+// management cluster is pending, and leaves the pending file alone.
+func TestVerifyNothingPending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.jsonl")
+	if err := (&pendingRecorder{path: path}).record(newTestPendingVerification("a1")); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &verifyOpts{pendingFile: path, mgmtClusterID: "mgmt-456", migrate: migrateOpts{
+		syncTimeout:  defaultSyncTimeout,
+		pollInterval: defaultPollInterval,
+	}}
+	if code := ExitCode(v.run(context.Background())); code != exitNothingToDo {
+		t.Errorf("run() exit code = %d, want %d", code, exitNothingToDo)
+	}
+	if pending, _ := loadPendingVerifications(path); len(pending) != 1 {
+		t.Errorf("Expected the pending file to be unchanged, got %+v", pending)
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// The HyperShift operator publishes its own version in the server-version key of the supported-versions
+// ConfigMap in its namespace.
+const (
+	hypershiftNamespace         = "hypershift"
+	supportedVersionsConfigMap  = "supported-versions"
+	hypershiftServerVersionKey  = "server-version"
+	unknownOpenShiftVersionNote = "OpenShift version unknown"
+)
+
+// belowMinVersionCluster is a migration candidate skipped because its OpenShift version is older than
+// --min-version, or unknown.
+type belowMinVersionCluster struct {
+	info   hostedClusterAuditInfo
+	reason string
+}
+
+// hostedClusterVersion returns the OpenShift version the HostedCluster last completed rolling out, or the
+// version it is rolling out to when no rollout has completed yet.
+func hostedClusterVersion(hc *hypershiftv1beta1.HostedCluster) string {
+	if hc.Status.Version == nil {
+		return ""
+	}
+	for _, update := range hc.Status.Version.History {
+		if update.State == configv1.CompletedUpdate {
+			return update.Version
+		}
+	}
+	return hc.Status.Version.Desired.Version
+}
+
+// channelGroup returns the channel group of an update channel such as stable-4.16.
+func channelGroup(channel string) string {
+	group, _, _ := strings.Cut(channel, "-")
+	return group
+}
+
+// hypershiftOperatorVersion reads the version of the HyperShift operator running on the management cluster.
+func (a *auditOpts) hypershiftOperatorVersion(ctx context.Context) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: hypershiftNamespace, Name: supportedVersionsConfigMap}
+	if err := a.mgmtClient.Get(ctx, key, configMap); err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s: %v", key, err)
+	}
+	version, ok := configMap.Data[hypershiftServerVersionKey]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s has no %s", key, hypershiftServerVersionKey)
+	}
+	return strings.TrimSpace(version), nil
+}
+
+// parseMinVersion parses the --min-version flag. An empty value disables the check.
+func parseMinVersion(value string) (*utilversion.Version, error) {
+	if value == "" {
+		return nil, nil
+	}
+	version, err := utilversion.ParseGeneric(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --min-version '%s': %v", value, err)
+	}
+	return version, nil
+}
+
+// filterMinVersion removes the candidates whose OpenShift version is older than minVersion or unknown.
+// Pre-release versions such as 4.16.0-rc.1 count as their release.
+func filterMinVersion(candidates []hostedClusterAuditInfo, minVersion *utilversion.Version) ([]hostedClusterAuditInfo, []belowMinVersionCluster) {
+	if minVersion == nil {
+		return candidates, nil
+	}
+
+	var allowed []hostedClusterAuditInfo
+	var skipped []belowMinVersionCluster
+	for _, c := range candidates {
+		reason := ""
+		version, err := utilversion.ParseGeneric(c.OpenShiftVersion)
+		switch {
+		case c.OpenShiftVersion == "" || err != nil:
+			reason = unknownOpenShiftVersionNote
+		case version.LessThan(minVersion):
+			reason = fmt.Sprintf("OpenShift %s is older than %s", c.OpenShiftVersion, minVersion)
+		}
+		if reason != "" {
+			slog.Info("Skipping cluster below the minimum version", "clusterID", c.ClusterID, "reason", reason)
+			skipped = append(skipped, belowMinVersionCluster{info: c, reason: reason})
+			continue
+		}
+		allowed = append(allowed, c)
+	}
+	return allowed, skipped
+}
+
+// displayBelowMinVersion prints the candidates skipped by --min-version.
+func displayBelowMinVersion(w io.Writer, skipped []belowMinVersionCluster) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Skipped: Below Minimum Version (%d) ===\n\n", len(skipped))
+	p := output.NewTable(w, output.TableMinWidth)
+	p.AddRow([]string{"CLUSTER ID", "CLUSTER NAME", "VERSION", "REASON"})
+	for _, s := range skipped {
+		p.AddRow([]string{s.info.ClusterID, s.info.ClusterName, driftValue(s.info.OpenShiftVersion), s.reason})
+	}
+	p.Flush()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestHostedClusterVersion verifies the last completed version is reported, falling back to the desired one.
+func TestHostedClusterVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  *hypershiftv1beta1.ClusterVersionStatus
+		expected string
+	}{
+		{
+			name:     "no version status",
+			expected: "",
+		},
+		{
+			name: "upgrade in progress",
+			version: &hypershiftv1beta1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.16.10"},
+				History: []configv1.UpdateHistory{
+					{State: configv1.PartialUpdate, Version: "4.16.10"},
+					{State: configv1.CompletedUpdate, Version: "4.15.30"},
+				},
+			},
+			expected: "4.15.30",
+		},
+		{
+			name: "first rollout",
+			version: &hypershiftv1beta1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.17.1"},
+				History: []configv1.UpdateHistory{{State: configv1.PartialUpdate, Version: "4.17.1"}},
+			},
+			expected: "4.17.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &hypershiftv1beta1.HostedCluster{Status: hypershiftv1beta1.HostedClusterStatus{Version: tt.version}}
+			if result := hostedClusterVersion(hc); result != tt.expected {
+				t.Errorf("hostedClusterVersion() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	for channel, expected := range map[string]string{"stable-4.16": "stable", "fast-4.17": "fast", "": ""} {
+		if result := channelGroup(channel); result != expected {
+			t.Errorf("channelGroup(%q) = %q, want %q", channel, result, expected)
+		}
+	}
+}
+
+// TestFilterMinVersion verifies clusters older than --min-version or with an unknown version are skipped.
+func TestFilterMinVersion(t *testing.T) {
+	if _, err := parseMinVersion("latest"); err == nil {
+		t.Error("Expected an error for an invalid --min-version")
+	}
+	minVersion, err := parseMinVersion("4.16.10")
+	if err != nil {
+		t.Fatalf("parseMinVersion() error = %v", err)
+	}
+
+	candidates := []hostedClusterAuditInfo{
+		{ClusterID: "old", OpenShiftVersion: "4.15.30"},
+		{ClusterID: "equal", OpenShiftVersion: "4.16.10"},
+		{ClusterID: "newer", OpenShiftVersion: "4.17.0-rc.1"},
+		{ClusterID: "unknown"},
+	}
+	allowed, skipped := filterMinVersion(candidates, minVersion)
+	if len(allowed) != 2 || allowed[0].ClusterID != "equal" || allowed[1].ClusterID != "newer" {
+		t.Errorf("Expected equal and newer to be kept, got %v", allowed)
+	}
+	if len(skipped) != 2 || skipped[0].info.ClusterID != "old" || skipped[1].reason != unknownOpenShiftVersionNote {
+		t.Errorf("Expected old and unknown to be skipped, got %v", skipped)
+	}
+
+	var out bytes.Buffer
+	displayBelowMinVersion(&out, skipped)
+	if !strings.Contains(out.String(), "Below Minimum Version (2)") || !strings.Contains(out.String(), "older than 4.16.10") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	if all, none := filterMinVersion(candidates, nil); len(all) != len(candidates) || none != nil {
+		t.Error("Expected no filtering without --min-version")
+	}
+}
+
+// TestHyperShiftOperatorVersion verifies the operator version is read from the supported-versions ConfigMap.
+func TestHyperShiftOperatorVersion(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: supportedVersionsConfigMap, Namespace: hypershiftNamespace},
+		Data:       map[string]string{hypershiftServerVersionKey: "abc1234\n"},
+	}
+	a := &auditOpts{mgmtClient: fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(configMap).Build()}
+	version, err := a.hypershiftOperatorVersion(context.Background())
+	if err != nil || version != "abc1234" {
+		t.Errorf("hypershiftOperatorVersion() = %q, %v; want abc1234", version, err)
+	}
+
+	a.mgmtClient = fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+	if _, err := a.hypershiftOperatorVersion(context.Background()); err == nil {
+		t.Error("Expected an error without the supported-versions ConfigMap")
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// ocmAutoscalingSupport returns why the Clusters Mgmt API cannot change the control plane autoscaling of
+// hosted clusters, or nil once it can. The clusters_mgmt v1 Cluster type of the OCM SDK has no control plane
+// autoscaling or sizing setting yet, so the annotations can only be delivered through the ManifestWork.
+func ocmAutoscalingSupport() error {
+	return fmt.Errorf("the Clusters Mgmt API does not expose control plane autoscaling settings for hosted clusters")
+}
+
+// checkViaOCM decides how a --via-ocm run makes its change. When the Clusters Mgmt API cannot make it, the
+// run falls back to patching the ManifestWorks, with a warning to the operator before confirmation.
+func (m *migrateOpts) checkViaOCM(w io.Writer) {
+	if !m.viaOCM {
+		return
+	}
+	err := ocmAutoscalingSupport()
+	if err == nil {
+		return
+	}
+	slog.Warn("Falling back to patching ManifestWorks", "reason", err)
+	fmt.Fprintf(w, "WARNING: --via-ocm is not possible: %v.\n", err)
+	fmt.Fprintln(w, "The clusters will be migrated by patching their ManifestWorks instead.")
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestCheckViaOCM verifies that --via-ocm warns that the run falls back to patching ManifestWorks while the
+// Clusters Mgmt API has no control plane autoscaling settings, and that nothing is printed without it.
+func TestCheckViaOCM(t *testing.T) {
+	var out bytes.Buffer
+	(&migrateOpts{}).checkViaOCM(&out)
+	if out.Len() != 0 {
+		t.Errorf("Expected no output without --via-ocm, got %q", out.String())
+	}
+
+	(&migrateOpts{viaOCM: true}).checkViaOCM(&out)
+	if !strings.Contains(out.String(), "WARNING: --via-ocm is not possible") || !strings.Contains(out.String(), "patching their ManifestWorks") {
+		t.Errorf("Expected a fallback warning, got %q", out.String())
+	}
+}
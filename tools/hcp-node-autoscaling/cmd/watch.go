@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// watchRetryInterval is how long audit --watch waits before re-establishing a failed watch.
+const watchRetryInterval = 10 * time.Second
+
+// watchEvent is a hosted cluster reported by audit --watch, appended as a JSON line to --watch-file.
+type watchEvent struct {
+	ObservedAt    string `json:"observed_at"`
+	MgmtClusterID string `json:"mgmt_cluster_id"`
+	hostedClusterAuditInfo
+}
+
+// isWatchedCategory reports whether audit --watch reports clusters in category.
+func isWatchedCategory(category string) bool {
+	return category == "needs-removal" || category == "ready-for-migration"
+}
+
+// watchReporter prints hosted clusters that newly need annotation removal or migration. Each cluster
+// is reported once per category until it leaves that category or is deleted.
+type watchReporter struct {
+	out           io.Writer
+	file          io.Writer
+	mgmtClusterID string
+	reported      map[string]string
+	now           func() time.Time
+}
+
+// newWatchReporter returns a reporter that skips the clusters already shown by the initial audit.
+func newWatchReporter(out, file io.Writer, results *auditResults) *watchReporter {
+	r := &watchReporter{
+		out:           out,
+		file:          file,
+		mgmtClusterID: results.MgmtClusterID,
+		reported:      map[string]string{},
+		now:           time.Now,
+	}
+	for _, clusters := range [][]hostedClusterAuditInfo{results.NeedsLabelRemoval, results.ReadyForMigration} {
+		for _, c := range clusters {
+			r.reported[c.ClusterID] = c.Category
+		}
+	}
+	return r
+}
+
+// isReported reports whether the cluster has already been reported in category.
+func (r *watchReporter) isReported(clusterID, category string) bool {
+	return r.reported[clusterID] == category
+}
+
+// observe reports a cluster that has entered a watched category, and forgets clusters that left one so
+// they are reported again if they return to it. Excluded clusters are never reported.
+func (r *watchReporter) observe(info *hostedClusterAuditInfo) {
+	if !isWatchedCategory(info.Category) || info.Excluded {
+		r.forget(info.ClusterID)
+		return
+	}
+	if r.isReported(info.ClusterID, info.Category) {
+		return
+	}
+	r.reported[info.ClusterID] = info.Category
+
+	event := watchEvent{
+		ObservedAt:             r.now().UTC().Format(time.RFC3339),
+		MgmtClusterID:          r.mgmtClusterID,
+		hostedClusterAuditInfo: *info,
+	}
+	fmt.Fprintf(r.out, "%s  %-20s  %s  %s  %s\n",
+		event.ObservedAt, info.Category, info.ClusterID, info.ClusterName, info.Namespace)
+
+	if r.file != nil {
+		if err := json.NewEncoder(r.file).Encode(event); err != nil {
+			slog.Error("Failed to write watch event", "clusterID", info.ClusterID, "error", err)
+		}
+	}
+}
+
+// forget drops a cluster so it is reported again when it next enters a watched category.
+func (r *watchReporter) forget(clusterID string) {
+	delete(r.reported, clusterID)
+}
+
+// watchHostedClusters watches the HostedClusters on the management cluster after the initial audit and
+// reports clusters that newly need annotation removal or migration until ctx is cancelled.
+func (a *auditOpts) watchHostedClusters(ctx context.Context, out io.Writer, results *auditResults) error {
+	pattern, err := a.listing.namespaceMatcher(a.environment)
+	if err != nil {
+		return err
+	}
+
+	var file io.Writer
+	if a.watchFile != "" {
+		f, err := os.OpenFile(a.watchFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open watch file: %v", err)
+		}
+		defer f.Close()
+		file = f
+	}
+
+	reporter := newWatchReporter(out, file, results)
+	slog.Info("Watching for hosted clusters that need annotation removal or migration",
+		"mgmtCluster", a.mgmtClusterName, "environment", a.environment)
+
+	for {
+		err := a.watchOnce(ctx, pattern, reporter)
+		if ctx.Err() != nil {
+			slog.Info("Stopped watching hosted clusters")
+			return nil
+		}
+		if err == nil {
+			slog.Debug("Watch on HostedClusters closed, restarting")
+			continue
+		}
+
+		slog.Warn("Watch on HostedClusters failed, retrying", "error", err, "retryIn", watchRetryInterval)
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped watching hosted clusters")
+			return nil
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+// watchOnce lists the HostedClusters to catch up on changes made while not watching, then watches them
+// until the watch closes or fails.
+func (a *auditOpts) watchOnce(ctx context.Context, pattern namespaceMatcher, reporter *watchReporter) error {
+	hcList := &hypershiftv1beta1.HostedClusterList{}
+	if err := a.watchClient.List(ctx, hcList); err != nil {
+		return fmt.Errorf("failed to list HostedClusters: %v", err)
+	}
+	for i := range hcList.Items {
+		a.observeHostedCluster(ctx, pattern, reporter, &hcList.Items[i])
+	}
+
+	w, err := a.watchClient.Watch(ctx, &hypershiftv1beta1.HostedClusterList{},
+		&client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: hcList.ResourceVersion}})
+	if err != nil {
+		return fmt.Errorf("failed to watch HostedClusters: %v", err)
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if hc, ok := event.Object.(*hypershiftv1beta1.HostedCluster); ok {
+					a.observeHostedCluster(ctx, pattern, reporter, hc)
+				}
+			case watch.Deleted:
+				if hc, ok := event.Object.(*hypershiftv1beta1.HostedCluster); ok {
+					reporter.forget(hc.Labels["api.openshift.com/id"])
+				}
+			case watch.Error:
+				return fmt.Errorf("watch error: %v", apierrors.FromObject(event.Object))
+			}
+		}
+	}
+}
+
+// observeHostedCluster audits a HostedCluster in the selected environment and passes it to the reporter.
+// The full audit only runs for clusters that may need reporting, since HostedClusters are modified often.
+func (a *auditOpts) observeHostedCluster(ctx context.Context, pattern namespaceMatcher, reporter *watchReporter, hc *hypershiftv1beta1.HostedCluster) {
+	if !pattern.MatchString(hc.Namespace) || !hc.DeletionTimestamp.IsZero() {
+		return
+	}
+
+	clusterID := hc.Labels["api.openshift.com/id"]
+	category := a.categorizeCluster(hc)
+	if !isWatchedCategory(category) {
+		reporter.forget(clusterID)
+		return
+	}
+	if reporter.isReported(clusterID, category) {
+		return
+	}
+
+	if info := a.auditHostedCluster(ctx, hc); info != nil {
+		reporter.observe(info)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// TestWatchReporter verifies clusters are reported once per watched category, clusters from the initial
+// audit and excluded clusters are skipped, and clusters leaving a watched category are reported on return.
+func TestWatchReporter(t *testing.T) {
+	results := &auditResults{
+		MgmtClusterID:     "mc-id",
+		ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "known", Category: "ready-for-migration"}},
+	}
+	var out, file bytes.Buffer
+	r := newWatchReporter(&out, &file, results)
+	r.now = func() time.Time { return time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC) }
+
+	observations := []hostedClusterAuditInfo{
+		{ClusterID: "known", Category: "ready-for-migration"},
+		{ClusterID: "new", ClusterName: "new-cluster", Namespace: "ocm-production-new", Category: "ready-for-migration"},
+		{ClusterID: "new", Category: "ready-for-migration"},
+		{ClusterID: "frozen", Category: "needs-removal", Excluded: true},
+		{ClusterID: "known", Category: "already-configured"},
+		{ClusterID: "known", Category: "needs-removal"},
+	}
+	for i := range observations {
+		r.observe(&observations[i])
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 reported clusters, got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], "ready-for-migration") || !strings.Contains(lines[0], "new-cluster") {
+		t.Errorf("Unexpected first report: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "needs-removal") || !strings.Contains(lines[1], "known") {
+		t.Errorf("Unexpected second report: %s", lines[1])
+	}
+
+	var event watchEvent
+	if err := json.NewDecoder(&file).Decode(&event); err != nil {
+		t.Fatalf("Failed to decode watch event: %v", err)
+	}
+	if event.ClusterID != "new" || event.MgmtClusterID != "mc-id" || event.ObservedAt != "2026-02-03T10:00:00Z" {
+		t.Errorf("Unexpected watch event: %+v", event)
+	}
+}
+
+// lineWriter sends each write to a channel.
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+// TestWatchHostedClusters verifies clusters created while watching are reported, and that clusters outside
+// the selected environment or already configured are not.
+func TestWatchHostedClusters(t *testing.T) {
+	scheme := testScheme(t)
+	watching := make(chan struct{}, 1)
+	mgmtClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newTestHostedCluster("a1", nil)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				w, err := c.Watch(ctx, list, opts...)
+				watching <- struct{}{}
+				return w, err
+			},
+		}).
+		Build()
+
+	a := &auditOpts{environment: "production", mgmtClient: mgmtClient, watchClient: mgmtClient}
+	results := &auditResults{ReadyForMigration: []hostedClusterAuditInfo{{ClusterID: "a1", Category: "ready-for-migration"}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(lineWriter, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- a.watchHostedClusters(ctx, out, results)
+	}()
+
+	select {
+	case <-watching:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the watch to start")
+	}
+
+	staging := newTestHostedCluster("s1", nil)
+	staging.Namespace = "ocm-staging-s1"
+	configured := newTestHostedCluster("c3", defaultProfile.Ensure)
+	needsRemoval := newTestHostedCluster("b2", map[string]string{"hypershift.openshift.io/cluster-size-override": "m5xl"})
+	for _, hc := range []client.Object{staging, configured, needsRemoval} {
+		if err := mgmtClient.Create(ctx, hc); err != nil {
+			t.Fatalf("Failed to create HostedCluster: %v", err)
+		}
+	}
+
+	select {
+	case line := <-out:
+		if !strings.Contains(line, "needs-removal") || !strings.Contains(line, "cluster-b2") {
+			t.Errorf("Unexpected report: %s", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the new cluster to be reported")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected watch to stop cleanly, got %v", err)
+	}
+	if len(out) != 0 {
+		t.Errorf("Expected a single report, got %d more", len(out))
+	}
+}
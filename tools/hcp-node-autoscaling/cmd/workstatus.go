@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// workAgentRejection returns why the work agent failed to apply the current generation of a ManifestWork,
+// or "" when it has not reported a failure. Conditions observed for an older generation are ignored, as
+// the agent has not yet picked up the patch.
+func workAgentRejection(mw *workv1.ManifestWork) string {
+	for _, manifest := range mw.Status.ResourceStatus.Manifests {
+		if manifest.ResourceMeta.Kind != "HostedCluster" {
+			continue
+		}
+		if c := meta.FindStatusCondition(manifest.Conditions, workv1.ManifestApplied); failedForGeneration(c, mw.Generation) {
+			return fmt.Sprintf("HostedCluster manifest %s=False (%s): %s", workv1.ManifestApplied, c.Reason, c.Message)
+		}
+	}
+	if c := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkApplied); failedForGeneration(c, mw.Generation) {
+		return fmt.Sprintf("%s=False (%s): %s", workv1.WorkApplied, c.Reason, c.Message)
+	}
+	return ""
+}
+
+// failedForGeneration reports whether a condition is False for the given ManifestWork generation.
+func failedForGeneration(c *metav1.Condition, generation int64) bool {
+	return c != nil && c.Status == metav1.ConditionFalse && c.ObservedGeneration >= generation
+}
+
+// workAgentStatus summarizes the Applied and Available conditions of a ManifestWork, with the message of
+// any condition that is not True.
+func workAgentStatus(mw *workv1.ManifestWork) string {
+	var parts []string
+	for _, conditionType := range []string{workv1.WorkApplied, workv1.WorkAvailable} {
+		c := meta.FindStatusCondition(mw.Status.Conditions, conditionType)
+		if c == nil {
+			parts = append(parts, conditionType+"=Unknown")
+			continue
+		}
+		part := fmt.Sprintf("%s=%s", conditionType, c.Status)
+		if c.Status != metav1.ConditionTrue && c.Message != "" {
+			part += fmt.Sprintf(" (%s: %s)", c.Reason, c.Message)
+		}
+		parts = append(parts, part)
+	}
+	return "ManifestWork " + strings.Join(parts, ", ")
+}
+
+// checkWorkAgent reads the cluster's ManifestWork while waiting for sync. It returns the work agent's
+// status for the timeout error, and an error when the agent rejected the patched ManifestWork. A failed
+// read is logged and not treated as a rejection.
+func (m *migrateOpts) checkWorkAgent(ctx context.Context, info hostedClusterAuditInfo) (string, error) {
+	if m.direct || m.serviceClient == nil {
+		return "", nil
+	}
+
+	mw, err := m.getManifestWork(ctx, info.ClusterID)
+	if err != nil {
+		slog.Warn("Failed to get ManifestWork status", "clusterID", info.ClusterID, "error", err)
+		return "", nil
+	}
+	if reason := workAgentRejection(mw); reason != "" {
+		return "", fmt.Errorf("work agent failed to apply ManifestWork %s/%s: %s", mw.Namespace, mw.Name, reason)
+	}
+
+	status := workAgentStatus(mw)
+	slog.Debug("ManifestWork status", "clusterID", info.ClusterID, "status", status)
+	return status, nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// TestWorkAgentRejection verifies only failures reported for the current ManifestWork generation are rejections.
+func TestWorkAgentRejection(t *testing.T) {
+	applied := func(status metav1.ConditionStatus, generation int64) metav1.Condition {
+		return metav1.Condition{Type: workv1.WorkApplied, Status: status, Reason: "AppliedManifestWorkFailed",
+			Message: "webhook denied", ObservedGeneration: generation}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		manifests  []workv1.ManifestCondition
+		expected   string
+	}{
+		{name: "no status yet"},
+		{name: "applied", conditions: []metav1.Condition{applied(metav1.ConditionTrue, 2)}},
+		{name: "failure from previous generation", conditions: []metav1.Condition{applied(metav1.ConditionFalse, 1)}},
+		{name: "failure for current generation", conditions: []metav1.Condition{applied(metav1.ConditionFalse, 2)},
+			expected: "Applied=False (AppliedManifestWorkFailed): webhook denied"},
+		{name: "HostedCluster manifest failed", conditions: []metav1.Condition{applied(metav1.ConditionFalse, 2)},
+			manifests: []workv1.ManifestCondition{
+				{ResourceMeta: workv1.ManifestResourceMeta{Kind: "Namespace"}},
+				{ResourceMeta: workv1.ManifestResourceMeta{Kind: "HostedCluster"}, Conditions: []metav1.Condition{{
+					Type: workv1.ManifestApplied, Status: metav1.ConditionFalse, Reason: "AppliedManifestFailed",
+					Message: "spec.release is immutable", ObservedGeneration: 2,
+				}}},
+			},
+			expected: "HostedCluster manifest Applied=False (AppliedManifestFailed): spec.release is immutable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := &workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: workv1.ManifestWorkStatus{
+					Conditions:     tt.conditions,
+					ResourceStatus: workv1.ManifestResourceStatus{Manifests: tt.manifests},
+				},
+			}
+			if got := workAgentRejection(mw); got != tt.expected {
+				t.Errorf("workAgentRejection() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWorkAgentStatus verifies the condition summary includes the message of conditions that are not True.
+func TestWorkAgentStatus(t *testing.T) {
+	mw := &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{Conditions: []metav1.Condition{
+		{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, Reason: "AppliedManifestWorkComplete", Message: "Apply manifest work complete"},
+		{Type: workv1.WorkAvailable, Status: metav1.ConditionFalse, Reason: "ResourcesNotAvailable", Message: "1 of 1 resources are not available"},
+	}}}
+
+	expected := "ManifestWork Applied=True, Available=False (ResourcesNotAvailable: 1 of 1 resources are not available)"
+	if got := workAgentStatus(mw); got != expected {
+		t.Errorf("workAgentStatus() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultPlaceholderNamespace = "hypershift-request-serving-node-placeholders"
+	sizeLabel                   = "hypershift.openshift.io/cluster-size"
+	unknownSize                 = "<unknown>"
+)
+
+type auditOpts struct {
+	mgmtClusterID string
+	namespace     string
+	output        string
+	size          string
+	noHeaders     bool
+
+	mgmtClient client.Client
+}
+
+type placeholderInfo struct {
+	Name          string `json:"name" yaml:"name"`
+	Size          string `json:"size" yaml:"size"`
+	Replicas      int32  `json:"replicas" yaml:"replicas"`
+	ReadyReplicas int32  `json:"ready_replicas" yaml:"ready_replicas"`
+	Ready         bool   `json:"ready" yaml:"ready"`
+}
+
+type sizePlaceholders struct {
+	Size          string `json:"size" yaml:"size"`
+	Configured    int    `json:"configured" yaml:"configured"`
+	Deployments   int    `json:"deployments" yaml:"deployments"`
+	Ready         int    `json:"ready" yaml:"ready"`
+	Replicas      int32  `json:"replicas" yaml:"replicas"`
+	ReadyReplicas int32  `json:"ready_replicas" yaml:"ready_replicas"`
+	Delta         int    `json:"delta" yaml:"delta"`
+	Status        string `json:"status" yaml:"status"`
+}
+
+type auditResults struct {
+	MgmtClusterID string             `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	Namespace     string             `json:"namespace" yaml:"namespace"`
+	Sizes         []sizePlaceholders `json:"sizes" yaml:"sizes"`
+	Placeholders  []placeholderInfo  `json:"placeholders" yaml:"placeholders"`
+}
+
+func main() {
+	opts := &auditOpts{}
+	rootCmd := &cobra.Command{
+		Use:   "hcp-placeholder-audit",
+		Short: "Audit placeholder capacity per size class on a ROSA HCP management cluster",
+		Long: `Audit the request-serving placeholder deployments of a ROSA HCP management cluster.
+
+Placeholder deployments keep request-serving nodes of each size class ready for new hosted
+control planes. The number of placeholders per size class is configured in the
+ClusterSizingConfiguration (spec.sizes[].management.placeholders). This tool counts the
+placeholder deployments and their ready replicas per size class, compares them against the
+configuration, and reports each size class as:
+- ok: the configured number of placeholders exist and are ready
+- over-provisioned: more placeholder deployments exist than are configured
+- under-provisioned: fewer ready placeholder deployments exist than are configured
+
+Use this alongside hcp-node-autoscaling to validate placeholder capacity after hosted clusters
+are migrated to resource-based autoscaling.`,
+		Example: `
+  # Audit placeholder capacity on a management cluster
+  hcp-placeholder-audit --mgmt-cluster-id mgmt-cluster-123
+
+  # Only audit one size class
+  hcp-placeholder-audit --mgmt-cluster-id mgmt-cluster-123 --size large
+
+  # Export to JSON for scripting
+  hcp-placeholder-audit --mgmt-cluster-id mgmt-cluster-123 --output json
+`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(context.Background())
+		},
+	}
+
+	rootCmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID to audit")
+	rootCmd.Flags().StringVar(&opts.namespace, "namespace", defaultPlaceholderNamespace, "Namespace of the placeholder deployments")
+	rootCmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text, json, yaml, csv")
+	rootCmd.Flags().StringVar(&opts.size, "size", "", "Only audit this size class")
+	rootCmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Skip headers in output (for text and csv formats)")
+	_ = rootCmd.MarkFlagRequired("mgmt-cluster-id")
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run builds and prints the placeholder capacity audit.
+func (a *auditOpts) run(ctx context.Context) error {
+	if err := utils.IsValidClusterKey(a.mgmtClusterID); err != nil {
+		return err
+	}
+
+	validOutputs := map[string]bool{"text": true, "json": true, "yaml": true, "csv": true}
+	if !validOutputs[a.output] {
+		return fmt.Errorf("invalid output format '%s'. Valid options: text, json, yaml, csv", a.output)
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, a.mgmtClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %v", err)
+	}
+
+	isMC, err := utils.IsManagementCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("failed to verify if cluster is a management cluster: %v", err)
+	}
+	if !isMC {
+		return fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
+	}
+
+	a.mgmtClusterID = cluster.ID()
+
+	fmt.Fprintf(os.Stderr, "Auditing management cluster: %s (%s)\n", cluster.Name(), cluster.ID())
+
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add apps v1 scheme: %v", err)
+	}
+	if err := schedulingv1alpha1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add scheduling scheme: %v", err)
+	}
+
+	mgmtClient, err := k8s.New(a.mgmtClusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	a.mgmtClient = mgmtClient
+
+	results, err := a.audit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.outputResults(results)
+}
+
+// audit compares the placeholder deployments against the placeholders configured for each size class.
+func (a *auditOpts) audit(ctx context.Context) (*auditResults, error) {
+	csc := &schedulingv1alpha1.ClusterSizingConfiguration{}
+	if err := a.mgmtClient.Get(ctx, types.NamespacedName{Name: "cluster"}, csc); err != nil {
+		return nil, fmt.Errorf("failed to get ClusterSizingConfiguration: %v", err)
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := a.mgmtClient.List(ctx, deployments, client.InNamespace(a.namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list placeholder deployments in %s: %v", a.namespace, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Found %d placeholder deployments\n", len(deployments.Items))
+
+	results := &auditResults{
+		MgmtClusterID: a.mgmtClusterID,
+		Namespace:     a.namespace,
+		Placeholders:  []placeholderInfo{},
+	}
+	for _, d := range deployments.Items {
+		info := buildPlaceholderInfo(d)
+		if a.size != "" && info.Size != a.size {
+			continue
+		}
+		results.Placeholders = append(results.Placeholders, info)
+	}
+
+	sort.Slice(results.Placeholders, func(i, j int) bool {
+		if results.Placeholders[i].Size != results.Placeholders[j].Size {
+			return results.Placeholders[i].Size < results.Placeholders[j].Size
+		}
+		return results.Placeholders[i].Name < results.Placeholders[j].Name
+	})
+
+	results.Sizes = summarizeBySize(configuredPlaceholders(csc.Spec.Sizes, a.size), results.Placeholders)
+
+	return results, nil
+}
+
+// buildPlaceholderInfo summarizes a placeholder deployment. The size class is read from the deployment's
+// cluster-size label, falling back to the node selector of its pods.
+func buildPlaceholderInfo(d appsv1.Deployment) placeholderInfo {
+	size := d.Labels[sizeLabel]
+	if size == "" {
+		size = d.Spec.Template.Spec.NodeSelector[sizeLabel]
+	}
+	if size == "" {
+		size = unknownSize
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	return placeholderInfo{
+		Name:          d.Name,
+		Size:          size,
+		Replicas:      replicas,
+		ReadyReplicas: d.Status.ReadyReplicas,
+		Ready:         d.Status.ReadyReplicas >= replicas,
+	}
+}
+
+// configuredPlaceholders returns the number of placeholders configured for each size class, optionally
+// restricted to one size class.
+func configuredPlaceholders(sizes []schedulingv1alpha1.SizeConfiguration, only string) map[string]int {
+	configured := map[string]int{}
+	for _, s := range sizes {
+		if only != "" && s.Name != only {
+			continue
+		}
+		configured[s.Name] = 0
+		if s.Management != nil {
+			configured[s.Name] = s.Management.Placeholders
+		}
+	}
+	return configured
+}
+
+// summarizeBySize counts the placeholder deployments of each size class and compares them against the
+// configured number of placeholders. Size classes that are configured but have no deployments are included.
+func summarizeBySize(configured map[string]int, placeholders []placeholderInfo) []sizePlaceholders {
+	bySize := map[string]*sizePlaceholders{}
+	for size, count := range configured {
+		bySize[size] = &sizePlaceholders{Size: size, Configured: count}
+	}
+
+	for _, p := range placeholders {
+		s, ok := bySize[p.Size]
+		if !ok {
+			s = &sizePlaceholders{Size: p.Size}
+			bySize[p.Size] = s
+		}
+		s.Deployments++
+		s.Replicas += p.Replicas
+		s.ReadyReplicas += p.ReadyReplicas
+		if p.Ready {
+			s.Ready++
+		}
+	}
+
+	summaries := make([]sizePlaceholders, 0, len(bySize))
+	for _, s := range bySize {
+		s.Delta = s.Deployments - s.Configured
+		s.Status = placeholderStatus(*s)
+		summaries = append(summaries, *s)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Size < summaries[j].Size
+	})
+
+	return summaries
+}
+
+// placeholderStatus classifies the placeholder capacity of a size class.
+func placeholderStatus(s sizePlaceholders) string {
+	switch {
+	case s.Deployments > s.Configured:
+		return "over-provisioned"
+	case s.Ready < s.Configured:
+		return "under-provisioned"
+	default:
+		return "ok"
+	}
+}
+
+// outputResults formats and prints the audit in the requested output format.
+func (a *auditOpts) outputResults(results *auditResults) error {
+	switch a.output {
+	case "json":
+		return a.printJSONOutput(results)
+	case "yaml":
+		return a.printYAMLOutput(results)
+	case "csv":
+		return a.printCSVOutput(results)
+	default:
+		return a.printTextOutput(results)
+	}
+}
+
+// printTextOutput prints the audit in human-readable text format.
+func (a *auditOpts) printTextOutput(results *auditResults) error {
+	fmt.Printf("\nManagement Cluster: %s\n", results.MgmtClusterID)
+	fmt.Printf("Placeholder Namespace: %s\n\n", results.Namespace)
+
+	if len(results.Placeholders) > 0 {
+		fmt.Println("=== Placeholder Deployments ===")
+
+		p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+		if !a.noHeaders {
+			p.AddRow([]string{"DEPLOYMENT", "SIZE", "READY"})
+		}
+		for _, d := range results.Placeholders {
+			p.AddRow([]string{d.Name, d.Size, fmt.Sprintf("%d/%d", d.ReadyReplicas, d.Replicas)})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	fmt.Println("=== Placeholder Capacity by Size ===")
+	p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	if !a.noHeaders {
+		p.AddRow([]string{"SIZE", "CONFIGURED", "DEPLOYMENTS", "READY", "REPLICAS READY", "DELTA", "STATUS"})
+	}
+	for _, s := range results.Sizes {
+		p.AddRow([]string{
+			s.Size, strconv.Itoa(s.Configured), strconv.Itoa(s.Deployments), strconv.Itoa(s.Ready),
+			fmt.Sprintf("%d/%d", s.ReadyReplicas, s.Replicas), formatDelta(s.Delta), s.Status,
+		})
+	}
+	p.Flush()
+
+	return nil
+}
+
+// formatDelta formats the difference between deployed and configured placeholders with its sign.
+func formatDelta(delta int) string {
+	if delta > 0 {
+		return "+" + strconv.Itoa(delta)
+	}
+	return strconv.Itoa(delta)
+}
+
+// printJSONOutput prints the audit in JSON format.
+func (a *auditOpts) printJSONOutput(results *auditResults) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// printYAMLOutput prints the audit in YAML format.
+func (a *auditOpts) printYAMLOutput(results *auditResults) error {
+	data, err := yaml.Marshal(results)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printCSVOutput prints one row per size class in CSV format.
+func (a *auditOpts) printCSVOutput(results *auditResults) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if !a.noHeaders {
+		w.Write([]string{"size", "configured", "deployments", "ready", "replicas", "ready_replicas", "delta", "status"})
+	}
+
+	for _, s := range results.Sizes {
+		w.Write([]string{s.Size, strconv.Itoa(s.Configured), strconv.Itoa(s.Deployments), strconv.Itoa(s.Ready),
+			strconv.Itoa(int(s.Replicas)), strconv.Itoa(int(s.ReadyReplicas)), strconv.Itoa(s.Delta), s.Status})
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestDeployment returns a placeholder deployment with the given size label, replicas and ready replicas.
+func newTestDeployment(name, size string, replicas, ready int32) *appsv1.Deployment {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: defaultPlaceholderNamespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+	}
+	if size != "" {
+		d.Labels = map[string]string{sizeLabel: size}
+	}
+	return d
+}
+
+// TestBuildPlaceholderInfo verifies the size class is read from the label or node selector and readiness is computed.
+func TestBuildPlaceholderInfo(t *testing.T) {
+	fromSelector := newTestDeployment("placeholder-small-0", "", 2, 1)
+	fromSelector.Spec.Template.Spec = corev1.PodSpec{NodeSelector: map[string]string{sizeLabel: "small"}}
+
+	tests := []struct {
+		name          string
+		deployment    *appsv1.Deployment
+		expectedSize  string
+		expectedReady bool
+	}{
+		{name: "size label", deployment: newTestDeployment("placeholder-large-0", "large", 2, 2), expectedSize: "large", expectedReady: true},
+		{name: "node selector", deployment: fromSelector, expectedSize: "small", expectedReady: false},
+		{name: "no size", deployment: newTestDeployment("placeholder", "", 1, 1), expectedSize: unknownSize, expectedReady: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := buildPlaceholderInfo(*tt.deployment)
+			if info.Size != tt.expectedSize {
+				t.Errorf("expected size %s, got %s", tt.expectedSize, info.Size)
+			}
+			if info.Ready != tt.expectedReady {
+				t.Errorf("expected ready %v, got %v", tt.expectedReady, info.Ready)
+			}
+		})
+	}
+}
+
+// TestSummarizeBySize verifies placeholder deployments are compared against the configured placeholders.
+func TestSummarizeBySize(t *testing.T) {
+	configured := map[string]int{"small": 2, "medium": 1, "large": 1}
+	placeholders := []placeholderInfo{
+		{Name: "a", Size: "small", Replicas: 2, ReadyReplicas: 2, Ready: true},
+		{Name: "b", Size: "small", Replicas: 2, ReadyReplicas: 1},
+		{Name: "c", Size: "large", Replicas: 2, ReadyReplicas: 2, Ready: true},
+		{Name: "d", Size: "large", Replicas: 2, ReadyReplicas: 2, Ready: true},
+		{Name: "e", Size: "xlarge", Replicas: 2, ReadyReplicas: 2, Ready: true},
+	}
+
+	expected := []sizePlaceholders{
+		{Size: "large", Configured: 1, Deployments: 2, Ready: 2, Replicas: 4, ReadyReplicas: 4, Delta: 1, Status: "over-provisioned"},
+		{Size: "medium", Configured: 1, Delta: -1, Status: "under-provisioned"},
+		{Size: "small", Configured: 2, Deployments: 2, Ready: 1, Replicas: 4, ReadyReplicas: 3, Status: "under-provisioned"},
+		{Size: "xlarge", Deployments: 1, Ready: 1, Replicas: 2, ReadyReplicas: 2, Delta: 1, Status: "over-provisioned"},
+	}
+
+	summaries := summarizeBySize(configured, placeholders)
+	if len(summaries) != len(expected) {
+		t.Fatalf("expected %d size summaries, got %d: %+v", len(expected), len(summaries), summaries)
+	}
+	for i := range expected {
+		if summaries[i] != expected[i] {
+			t.Errorf("size %d = %+v, want %+v", i, summaries[i], expected[i])
+		}
+	}
+}
+
+// TestAudit verifies the audit reads the ClusterSizingConfiguration and placeholder deployments, honoring --size.
+func TestAudit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add apps v1 scheme: %v", err)
+	}
+	if err := schedulingv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheduling scheme: %v", err)
+	}
+
+	csc := &schedulingv1alpha1.ClusterSizingConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: schedulingv1alpha1.ClusterSizingConfigurationSpec{
+			Sizes: []schedulingv1alpha1.SizeConfiguration{
+				{Name: "small", Management: &schedulingv1alpha1.Management{Placeholders: 1}},
+				{Name: "large"},
+			},
+		},
+	}
+	other := newTestDeployment("unrelated", "small", 1, 1)
+	other.Namespace = "default"
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		csc,
+		newTestDeployment("placeholder-small-0", "small", 2, 2),
+		newTestDeployment("placeholder-large-0", "large", 2, 2),
+		other,
+	).Build()
+
+	a := &auditOpts{mgmtClusterID: "mgmt-123", namespace: defaultPlaceholderNamespace, size: "small", mgmtClient: mgmtClient}
+	results, err := a.audit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results.Placeholders) != 1 || results.Placeholders[0].Name != "placeholder-small-0" {
+		t.Errorf("expected only the small placeholder deployment, got %+v", results.Placeholders)
+	}
+	if len(results.Sizes) != 1 || results.Sizes[0].Size != "small" || results.Sizes[0].Status != "ok" {
+		t.Errorf("expected the small size class to be ok, got %+v", results.Sizes)
+	}
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/resources"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type reportOpts struct {
+	mgmtClusterID string
+	output        string
+	size          string
+	noHeaders     bool
+
+	mgmtClient client.Client
+}
+
+type servingNodeInfo struct {
+	Name              string  `json:"name" yaml:"name"`
+	Size              string  `json:"size" yaml:"size"`
+	Zone              string  `json:"zone" yaml:"zone"`
+	HostedCluster     string  `json:"hosted_cluster,omitempty" yaml:"hosted_cluster,omitempty"`
+	Placeholder       bool    `json:"placeholder" yaml:"placeholder"`
+	PodCount          int     `json:"pod_count" yaml:"pod_count"`
+	CPURequests       string  `json:"cpu_requests" yaml:"cpu_requests"`
+	CPUAllocatable    string  `json:"cpu_allocatable" yaml:"cpu_allocatable"`
+	CPUPercent        float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryRequests    string  `json:"memory_requests" yaml:"memory_requests"`
+	MemoryAllocatable string  `json:"memory_allocatable" yaml:"memory_allocatable"`
+	MemoryPercent     float64 `json:"memory_percent" yaml:"memory_percent"`
+}
+
+type sizeSummary struct {
+	Size             string  `json:"size" yaml:"size"`
+	Nodes            int     `json:"nodes" yaml:"nodes"`
+	Occupied         int     `json:"occupied" yaml:"occupied"`
+	Placeholder      int     `json:"placeholder" yaml:"placeholder"`
+	HostedClusters   int     `json:"hosted_clusters" yaml:"hosted_clusters"`
+	AvgCPUPercent    float64 `json:"avg_cpu_percent" yaml:"avg_cpu_percent"`
+	AvgMemoryPercent float64 `json:"avg_memory_percent" yaml:"avg_memory_percent"`
+}
+
+type reportResults struct {
+	MgmtClusterID string            `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	TotalNodes    int               `json:"total_nodes" yaml:"total_nodes"`
+	Sizes         []sizeSummary     `json:"sizes" yaml:"sizes"`
+	Nodes         []servingNodeInfo `json:"nodes" yaml:"nodes"`
+	Errors        []nodeError       `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+type nodeError struct {
+	Node  string `json:"node" yaml:"node"`
+	Error string `json:"error" yaml:"error"`
+}
+
+func main() {
+	opts := &reportOpts{}
+	rootCmd := &cobra.Command{
+		Use:   "hcp-serving-node-report",
+		Short: "Report request-serving node utilization on a ROSA HCP management cluster",
+		Long: `Inspect the request-serving nodes of a ROSA HCP management cluster.
+
+For every node labeled hypershift.openshift.io/request-serving-component the report shows
+its size class, the hosted control plane pinned to it (if any), and how much of its
+allocatable CPU and memory is requested by the pods running on it. Nodes without a hosted
+control plane are reported as placeholder capacity.
+
+Use this alongside hcp-node-autoscaling to check whether autoscaling is balancing hosted
+control planes across size classes.`,
+		Example: `
+  # Report request-serving nodes on a management cluster
+  hcp-serving-node-report --mgmt-cluster-id mgmt-cluster-123
+
+  # Only show nodes of one size class
+  hcp-serving-node-report --mgmt-cluster-id mgmt-cluster-123 --size large
+
+  # Export to JSON for scripting
+  hcp-serving-node-report --mgmt-cluster-id mgmt-cluster-123 --output json
+`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(context.Background())
+		},
+	}
+
+	rootCmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "", "The management cluster ID to inspect")
+	rootCmd.Flags().StringVar(&opts.output, "output", "text", "Output format: text, json, yaml, csv")
+	rootCmd.Flags().StringVar(&opts.size, "size", "", "Only report nodes with this size class")
+	rootCmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Skip headers in output (for text and csv formats)")
+	_ = rootCmd.MarkFlagRequired("mgmt-cluster-id")
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run builds and prints the request-serving node report.
+func (r *reportOpts) run(ctx context.Context) error {
+	if err := utils.IsValidClusterKey(r.mgmtClusterID); err != nil {
+		return err
+	}
+
+	validOutputs := map[string]bool{"text": true, "json": true, "yaml": true, "csv": true}
+	if !validOutputs[r.output] {
+		return fmt.Errorf("invalid output format '%s'. Valid options: text, json, yaml, csv", r.output)
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, r.mgmtClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %v", err)
+	}
+
+	isMC, err := utils.IsManagementCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("failed to verify if cluster is a management cluster: %v", err)
+	}
+	if !isMC {
+		return fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
+	}
+
+	r.mgmtClusterID = cluster.ID()
+
+	fmt.Fprintf(os.Stderr, "Inspecting management cluster: %s (%s)\n", cluster.Name(), cluster.ID())
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add core v1 scheme: %v", err)
+	}
+
+	mgmtClient, err := k8s.New(r.mgmtClusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	r.mgmtClient = mgmtClient
+
+	nodes, err := r.listServingNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list request-serving nodes: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Found %d request-serving nodes\n", len(nodes))
+
+	results := &reportResults{
+		MgmtClusterID: r.mgmtClusterID,
+		Nodes:         []servingNodeInfo{},
+		Errors:        []nodeError{},
+	}
+
+	for _, node := range nodes {
+		pods := &corev1.PodList{}
+		if err := r.mgmtClient.List(ctx, pods, client.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+			results.Errors = append(results.Errors, nodeError{
+				Node:  node.Name,
+				Error: fmt.Sprintf("failed to list pods: %v", err),
+			})
+			continue
+		}
+		results.Nodes = append(results.Nodes, buildNodeInfo(node, pods.Items))
+	}
+
+	sort.Slice(results.Nodes, func(i, j int) bool {
+		if results.Nodes[i].Size != results.Nodes[j].Size {
+			return results.Nodes[i].Size < results.Nodes[j].Size
+		}
+		return results.Nodes[i].Name < results.Nodes[j].Name
+	})
+
+	results.TotalNodes = len(results.Nodes)
+	results.Sizes = summarizeBySize(results.Nodes)
+
+	return r.outputResults(results)
+}
+
+// listServingNodes returns the request-serving nodes, optionally restricted to one size class.
+func (r *reportOpts) listServingNodes(ctx context.Context) ([]corev1.Node, error) {
+	labels := client.MatchingLabels{"hypershift.openshift.io/request-serving-component": "true"}
+	if r.size != "" {
+		labels["hypershift.openshift.io/cluster-size"] = r.size
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.mgmtClient.List(ctx, nodeList, labels); err != nil {
+		return nil, err
+	}
+
+	return nodeList.Items, nil
+}
+
+// buildNodeInfo summarizes a request-serving node and the requests of the pods scheduled on it.
+func buildNodeInfo(node corev1.Node, pods []corev1.Pod) servingNodeInfo {
+	cpu, memory := resources.PodRequests(pods)
+	allocatableCPU := node.Status.Allocatable[corev1.ResourceCPU]
+	allocatableMemory := node.Status.Allocatable[corev1.ResourceMemory]
+
+	podCount := 0
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			podCount++
+		}
+	}
+
+	hostedCluster := node.Labels["hypershift.openshift.io/cluster"]
+
+	return servingNodeInfo{
+		Name:              node.Name,
+		Size:              node.Labels["hypershift.openshift.io/cluster-size"],
+		Zone:              node.Labels["topology.kubernetes.io/zone"],
+		HostedCluster:     hostedCluster,
+		Placeholder:       hostedCluster == "",
+		PodCount:          podCount,
+		CPURequests:       cpu.String(),
+		CPUAllocatable:    allocatableCPU.String(),
+		CPUPercent:        utilizationPercent(cpu, allocatableCPU),
+		MemoryRequests:    memory.String(),
+		MemoryAllocatable: allocatableMemory.String(),
+		MemoryPercent:     utilizationPercent(memory, allocatableMemory),
+	}
+}
+
+// utilizationPercent returns requested as a percentage of allocatable, rounded to one decimal place.
+func utilizationPercent(requested, allocatable resource.Quantity) float64 {
+	if allocatable.IsZero() {
+		return 0
+	}
+	return roundPercent(float64(requested.MilliValue()) / float64(allocatable.MilliValue()) * 100)
+}
+
+// roundPercent rounds a percentage to one decimal place.
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*10) / 10
+}
+
+// summarizeBySize groups nodes by size class and averages their utilization.
+func summarizeBySize(nodes []servingNodeInfo) []sizeSummary {
+	bySize := map[string]*sizeSummary{}
+	hostedClusters := map[string]map[string]bool{}
+
+	for _, n := range nodes {
+		s, ok := bySize[n.Size]
+		if !ok {
+			s = &sizeSummary{Size: n.Size}
+			bySize[n.Size] = s
+			hostedClusters[n.Size] = map[string]bool{}
+		}
+
+		s.Nodes++
+		if n.Placeholder {
+			s.Placeholder++
+		} else {
+			s.Occupied++
+			hostedClusters[n.Size][n.HostedCluster] = true
+		}
+		s.AvgCPUPercent += n.CPUPercent
+		s.AvgMemoryPercent += n.MemoryPercent
+	}
+
+	summaries := make([]sizeSummary, 0, len(bySize))
+	for size, s := range bySize {
+		s.HostedClusters = len(hostedClusters[size])
+		s.AvgCPUPercent = roundPercent(s.AvgCPUPercent / float64(s.Nodes))
+		s.AvgMemoryPercent = roundPercent(s.AvgMemoryPercent / float64(s.Nodes))
+		summaries = append(summaries, *s)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Size < summaries[j].Size
+	})
+
+	return summaries
+}
+
+// outputResults formats and prints the report in the requested output format.
+func (r *reportOpts) outputResults(results *reportResults) error {
+	switch r.output {
+	case "json":
+		return r.printJSONOutput(results)
+	case "yaml":
+		return r.printYAMLOutput(results)
+	case "csv":
+		return r.printCSVOutput(results)
+	default:
+		return r.printTextOutput(results)
+	}
+}
+
+// printTextOutput prints the report in human-readable text format.
+func (r *reportOpts) printTextOutput(results *reportResults) error {
+	fmt.Printf("\nManagement Cluster: %s\n", results.MgmtClusterID)
+	fmt.Printf("Total Request-Serving Nodes: %d\n\n", results.TotalNodes)
+
+	if len(results.Nodes) > 0 {
+		fmt.Println("=== Request-Serving Nodes ===")
+
+		p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+		if !r.noHeaders {
+			p.AddRow([]string{"NODE", "SIZE", "ZONE", "HOSTED CLUSTER", "PODS", "CPU REQ/ALLOC", "CPU %", "MEM REQ/ALLOC", "MEM %"})
+		}
+		for _, n := range results.Nodes {
+			hostedCluster := n.HostedCluster
+			if n.Placeholder {
+				hostedCluster = "<placeholder>"
+			}
+			p.AddRow([]string{
+				n.Name, n.Size, n.Zone, hostedCluster, strconv.Itoa(n.PodCount),
+				n.CPURequests + "/" + n.CPUAllocatable, formatPercent(n.CPUPercent),
+				n.MemoryRequests + "/" + n.MemoryAllocatable, formatPercent(n.MemoryPercent),
+			})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	if len(results.Errors) > 0 {
+		fmt.Printf("=== Errors (%d) ===\n", len(results.Errors))
+		p := printer.NewTablePrinter(os.Stdout, 30, 1, 3, ' ')
+		p.AddRow([]string{"NODE", "ERROR"})
+		for _, e := range results.Errors {
+			p.AddRow([]string{e.Node, e.Error})
+		}
+		p.Flush()
+		fmt.Println()
+	}
+
+	fmt.Println("=== Summary by Size ===")
+	p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	if !r.noHeaders {
+		p.AddRow([]string{"SIZE", "NODES", "OCCUPIED", "PLACEHOLDER", "HOSTED CLUSTERS", "AVG CPU %", "AVG MEM %"})
+	}
+	for _, s := range results.Sizes {
+		p.AddRow([]string{
+			s.Size, strconv.Itoa(s.Nodes), strconv.Itoa(s.Occupied), strconv.Itoa(s.Placeholder),
+			strconv.Itoa(s.HostedClusters), formatPercent(s.AvgCPUPercent), formatPercent(s.AvgMemoryPercent),
+		})
+	}
+	p.Flush()
+
+	return nil
+}
+
+// formatPercent formats a utilization percentage for text output.
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', 1, 64) + "%"
+}
+
+// printJSONOutput prints the report in JSON format.
+func (r *reportOpts) printJSONOutput(results *reportResults) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// printYAMLOutput prints the report in YAML format.
+func (r *reportOpts) printYAMLOutput(results *reportResults) error {
+	data, err := yaml.Marshal(results)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printCSVOutput prints one row per request-serving node in CSV format.
+func (r *reportOpts) printCSVOutput(results *reportResults) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if !r.noHeaders {
+		w.Write([]string{"node", "size", "zone", "hosted_cluster", "placeholder", "pod_count",
+			"cpu_requests", "cpu_allocatable", "cpu_percent", "memory_requests", "memory_allocatable", "memory_percent"})
+	}
+
+	for _, n := range results.Nodes {
+		w.Write([]string{n.Name, n.Size, n.Zone, n.HostedCluster, strconv.FormatBool(n.Placeholder), strconv.Itoa(n.PodCount),
+			n.CPURequests, n.CPUAllocatable, strconv.FormatFloat(n.CPUPercent, 'f', 1, 64),
+			n.MemoryRequests, n.MemoryAllocatable, strconv.FormatFloat(n.MemoryPercent, 'f', 1, 64)})
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestUtilizationPercent verifies requests are converted to a rounded percentage of allocatable.
+func TestUtilizationPercent(t *testing.T) {
+	tests := []struct {
+		name        string
+		requested   string
+		allocatable string
+		expected    float64
+	}{
+		{
+			name:        "half of allocatable CPU",
+			requested:   "2",
+			allocatable: "4",
+			expected:    50,
+		},
+		{
+			name:        "millicores are rounded to one decimal place",
+			requested:   "1",
+			allocatable: "3",
+			expected:    33.3,
+		},
+		{
+			name:        "memory quantities",
+			requested:   "12Gi",
+			allocatable: "16Gi",
+			expected:    75,
+		},
+		{
+			name:        "zero allocatable",
+			requested:   "1",
+			allocatable: "0",
+			expected:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := utilizationPercent(resource.MustParse(tt.requested), resource.MustParse(tt.allocatable))
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestBuildNodeInfo verifies node labels and pod requests are summarized correctly.
+func TestBuildNodeInfo(t *testing.T) {
+	node := func(labels map[string]string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-1-1", Labels: labels},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+			},
+		}
+	}
+	pod := func(phase corev1.PodPhase, cpu, memory string) corev1.Pod {
+		return corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		node                corev1.Node
+		pods                []corev1.Pod
+		expectedCluster     string
+		expectedPlaceholder bool
+		expectedPods        int
+		expectedCPU         float64
+		expectedMemory      float64
+	}{
+		{
+			name: "node pinned to a hosted control plane",
+			node: node(map[string]string{
+				"hypershift.openshift.io/request-serving-component": "true",
+				"hypershift.openshift.io/cluster-size":              "large",
+				"hypershift.openshift.io/cluster":                   "ocm-production-abc-my-cluster",
+				"topology.kubernetes.io/zone":                       "us-east-1a",
+			}),
+			pods: []corev1.Pod{
+				pod(corev1.PodRunning, "2", "8Gi"),
+				pod(corev1.PodRunning, "2", "8Gi"),
+				pod(corev1.PodSucceeded, "4", "16Gi"),
+			},
+			expectedCluster:     "ocm-production-abc-my-cluster",
+			expectedPlaceholder: false,
+			expectedPods:        2,
+			expectedCPU:         50,
+			expectedMemory:      50,
+		},
+		{
+			name: "placeholder node without a hosted control plane",
+			node: node(map[string]string{
+				"hypershift.openshift.io/request-serving-component": "true",
+				"hypershift.openshift.io/cluster-size":              "large",
+			}),
+			pods:                []corev1.Pod{pod(corev1.PodRunning, "1", "2Gi")},
+			expectedPlaceholder: true,
+			expectedPods:        1,
+			expectedCPU:         12.5,
+			expectedMemory:      6.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := buildNodeInfo(tt.node, tt.pods)
+			if info.Size != "large" {
+				t.Errorf("expected size large, got %s", info.Size)
+			}
+			if info.HostedCluster != tt.expectedCluster {
+				t.Errorf("expected hosted cluster %q, got %q", tt.expectedCluster, info.HostedCluster)
+			}
+			if info.Placeholder != tt.expectedPlaceholder {
+				t.Errorf("expected placeholder %v, got %v", tt.expectedPlaceholder, info.Placeholder)
+			}
+			if info.PodCount != tt.expectedPods {
+				t.Errorf("expected %d pods, got %d", tt.expectedPods, info.PodCount)
+			}
+			if info.CPUPercent != tt.expectedCPU {
+				t.Errorf("expected CPU %v%%, got %v%%", tt.expectedCPU, info.CPUPercent)
+			}
+			if info.MemoryPercent != tt.expectedMemory {
+				t.Errorf("expected memory %v%%, got %v%%", tt.expectedMemory, info.MemoryPercent)
+			}
+		})
+	}
+}
+
+// TestSummarizeBySize verifies nodes are grouped by size with placeholder and hosted cluster counts.
+func TestSummarizeBySize(t *testing.T) {
+	nodes := []servingNodeInfo{
+		{Name: "a", Size: "small", HostedCluster: "ns-one", CPUPercent: 40, MemoryPercent: 30},
+		{Name: "b", Size: "small", HostedCluster: "ns-one", CPUPercent: 60, MemoryPercent: 50},
+		{Name: "c", Size: "small", Placeholder: true, CPUPercent: 5, MemoryPercent: 4},
+		{Name: "d", Size: "large", HostedCluster: "ns-two", CPUPercent: 80, MemoryPercent: 70},
+	}
+
+	summaries := summarizeBySize(nodes)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 size summaries, got %d", len(summaries))
+	}
+
+	large, small := summaries[0], summaries[1]
+	if large.Size != "large" || small.Size != "small" {
+		t.Fatalf("expected summaries sorted by size, got %s, %s", large.Size, small.Size)
+	}
+
+	if small.Nodes != 3 || small.Occupied != 2 || small.Placeholder != 1 {
+		t.Errorf("unexpected small node counts: %+v", small)
+	}
+	if small.HostedClusters != 1 {
+		t.Errorf("expected 1 hosted cluster on small nodes, got %d", small.HostedClusters)
+	}
+	if small.AvgCPUPercent != 35 {
+		t.Errorf("expected small average CPU 35%%, got %v%%", small.AvgCPUPercent)
+	}
+	if small.AvgMemoryPercent != 28 {
+		t.Errorf("expected small average memory 28%%, got %v%%", small.AvgMemoryPercent)
+	}
+
+	if large.Nodes != 1 || large.Occupied != 1 || large.Placeholder != 0 || large.HostedClusters != 1 {
+		t.Errorf("unexpected large node counts: %+v", large)
+	}
+}
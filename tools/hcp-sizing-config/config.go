@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/yaml"
+)
+
+// sizingConfig summarizes a ClusterSizingConfiguration for reports.
+type sizingConfig struct {
+	Sizes                               []sizeClass `json:"sizes" yaml:"sizes"`
+	ConcurrencyLimit                    int32       `json:"concurrency_limit" yaml:"concurrency_limit"`
+	ConcurrencySlidingWindow            string      `json:"concurrency_sliding_window" yaml:"concurrency_sliding_window"`
+	TransitionDelayIncrease             string      `json:"transition_delay_increase" yaml:"transition_delay_increase"`
+	TransitionDelayDecrease             string      `json:"transition_delay_decrease" yaml:"transition_delay_decrease"`
+	NonRequestServingNodesBufferPerZone string      `json:"non_request_serving_nodes_buffer_per_zone,omitempty" yaml:"non_request_serving_nodes_buffer_per_zone,omitempty"`
+
+	// Valid is the status of the ClusterSizingConfigurationValid condition reported by HyperShift, and
+	// Message its message.
+	Valid   string `json:"valid,omitempty" yaml:"valid,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// sizeClass summarizes a size class of a ClusterSizingConfiguration. To is empty for the unbounded size class.
+type sizeClass struct {
+	Name                          string `json:"name" yaml:"name"`
+	From                          uint32 `json:"from" yaml:"from"`
+	To                            string `json:"to,omitempty" yaml:"to,omitempty"`
+	Placeholders                  int    `json:"placeholders" yaml:"placeholders"`
+	NonRequestServingNodesPerZone string `json:"non_request_serving_nodes_per_zone,omitempty" yaml:"non_request_serving_nodes_per_zone,omitempty"`
+}
+
+// configReport is the ClusterSizingConfiguration of a management cluster, or the error reading it.
+type configReport struct {
+	MgmtClusterID   string        `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	MgmtClusterName string        `json:"mgmt_cluster_name" yaml:"mgmt_cluster_name"`
+	Config          *sizingConfig `json:"config,omitempty" yaml:"config,omitempty"`
+	Error           string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// summarizeConfig summarizes a ClusterSizingConfiguration.
+func summarizeConfig(csc *schedulingv1alpha1.ClusterSizingConfiguration) *sizingConfig {
+	spec := csc.Spec
+	config := &sizingConfig{
+		Sizes:                    []sizeClass{},
+		ConcurrencyLimit:         spec.Concurrency.Limit,
+		ConcurrencySlidingWindow: spec.Concurrency.SlidingWindow.Duration.String(),
+		TransitionDelayIncrease:  spec.TransitionDelay.Increase.Duration.String(),
+		TransitionDelayDecrease:  spec.TransitionDelay.Decrease.Duration.String(),
+	}
+	if spec.NonRequestServingNodesBufferPerZone != nil {
+		config.NonRequestServingNodesBufferPerZone = spec.NonRequestServingNodesBufferPerZone.String()
+	}
+
+	for _, s := range spec.Sizes {
+		size := sizeClass{Name: s.Name, From: s.Criteria.From}
+		if s.Criteria.To != nil {
+			size.To = strconv.FormatUint(uint64(*s.Criteria.To), 10)
+		}
+		if s.Management != nil {
+			size.Placeholders = s.Management.Placeholders
+			if s.Management.NonRequestServingNodesPerZone != nil {
+				size.NonRequestServingNodesPerZone = s.Management.NonRequestServingNodesPerZone.String()
+			}
+		}
+		config.Sizes = append(config.Sizes, size)
+	}
+
+	if condition := meta.FindStatusCondition(csc.Status.Conditions, schedulingv1alpha1.ClusterSizingConfigurationValidType); condition != nil {
+		config.Valid = string(condition.Status)
+		config.Message = condition.Message
+	}
+
+	return config
+}
+
+// loadDesiredConfig reads a ClusterSizingConfiguration manifest, such as the output of
+// `oc get clustersizingconfiguration cluster -o yaml`. Only its spec is used.
+func loadDesiredConfig(path string) (*schedulingv1alpha1.ClusterSizingConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sizing configuration: %v", err)
+	}
+
+	csc := &schedulingv1alpha1.ClusterSizingConfiguration{}
+	if err := yaml.UnmarshalStrict(data, csc); err != nil {
+		return nil, fmt.Errorf("failed to parse sizing configuration %s: %v", path, err)
+	}
+	if csc.Kind != "" && csc.Kind != "ClusterSizingConfiguration" {
+		return nil, fmt.Errorf("sizing configuration %s is a %s, not a ClusterSizingConfiguration", path, csc.Kind)
+	}
+	if len(csc.Spec.Sizes) == 0 {
+		return nil, fmt.Errorf("sizing configuration %s has no spec.sizes", path)
+	}
+	return csc, nil
+}
+
+type getOpts struct {
+	fleet fleetOpts
+}
+
+func newGetCmd() *cobra.Command {
+	opts := &getOpts{}
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the ClusterSizingConfiguration of management clusters",
+		Example: `
+  # Show the size classes, concurrency and transition delays of two management clusters
+  hcp-sizing-config get --mgmt-cluster-ids mgmt-123,mgmt-456
+
+  # Export the configuration for scripting
+  hcp-sizing-config get --mgmt-cluster-ids mgmt-123 --output json
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+	opts.fleet.addFlags(cmd)
+	opts.fleet.addOutputFlag(cmd)
+	return cmd
+}
+
+// run reads and reports the ClusterSizingConfiguration of each management cluster.
+func (g *getOpts) run(ctx context.Context) error {
+	if err := g.fleet.validate(); err != nil {
+		return err
+	}
+
+	clusters, err := g.fleet.connect()
+	defer g.fleet.close()
+	if err != nil {
+		return err
+	}
+
+	var reports []configReport
+	for _, mc := range clusters {
+		fmt.Fprintf(os.Stderr, "Reading sizing configuration of management cluster %s\n", clusterLabel(mc.id, mc.name))
+		reports = append(reports, g.report(ctx, mc))
+	}
+
+	if err := g.printReports(os.Stdout, reports); err != nil {
+		return err
+	}
+	return failedClusters(len(reports), countErrors(reports, func(r configReport) string { return r.Error }))
+}
+
+// report reads the ClusterSizingConfiguration of a management cluster.
+func (g *getOpts) report(ctx context.Context, mc mgmtCluster) configReport {
+	report := configReport{MgmtClusterID: mc.id, MgmtClusterName: mc.name}
+	c, err := g.fleet.client(mc)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	csc, err := getSizingConfig(ctx, c)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Config = summarizeConfig(csc)
+	return report
+}
+
+// printReports prints the configuration of each management cluster and, for several, a fleet summary.
+func (g *getOpts) printReports(w io.Writer, reports []configReport) error {
+	switch g.fleet.output {
+	case "json":
+		return output.JSON(w, reports)
+	case "yaml":
+		return output.YAML(w, reports, false)
+	}
+
+	for _, r := range reports {
+		fmt.Fprintf(w, "\n=== Management Cluster: %s ===\n", clusterLabel(r.MgmtClusterID, r.MgmtClusterName))
+		if r.Error != "" {
+			fmt.Fprintf(w, "Error: %s\n", r.Error)
+			continue
+		}
+		printConfig(w, r.Config, g.fleet.noHeaders)
+	}
+
+	if len(reports) > 1 {
+		fmt.Fprintln(w, "\n=== Fleet Summary ===")
+		p := output.NewTable(w, output.TableMinWidth)
+		if !g.fleet.noHeaders {
+			p.AddRow([]string{"MGMT CLUSTER", "SIZES", "CONCURRENCY", "INCREASE DELAY", "DECREASE DELAY", "VALID"})
+		}
+		for _, r := range reports {
+			if r.Error != "" {
+				p.AddRow([]string{clusterLabel(r.MgmtClusterID, r.MgmtClusterName), "-", "-", "-", "-", "error"})
+				continue
+			}
+			p.AddRow([]string{clusterLabel(r.MgmtClusterID, r.MgmtClusterName), strconv.Itoa(len(r.Config.Sizes)),
+				concurrencyString(r.Config), r.Config.TransitionDelayIncrease, r.Config.TransitionDelayDecrease, orUnknown(r.Config.Valid)})
+		}
+		p.Flush()
+	}
+	return nil
+}
+
+// printConfig prints the size classes, concurrency and transition delays of a configuration.
+func printConfig(w io.Writer, config *sizingConfig, noHeaders bool) {
+	p := output.NewTable(w, output.TableMinWidth)
+	if !noHeaders {
+		p.AddRow([]string{"SIZE", "FROM", "TO", "PLACEHOLDERS", "NON-REQUEST-SERVING NODES/ZONE"})
+	}
+	for _, s := range config.Sizes {
+		to := s.To
+		if to == "" {
+			to = "+inf"
+		}
+		p.AddRow([]string{s.Name, strconv.FormatUint(uint64(s.From), 10), to, strconv.Itoa(s.Placeholders), orUnset(s.NonRequestServingNodesPerZone)})
+	}
+	p.Flush()
+
+	fmt.Fprintf(w, "\nConcurrency: %s\n", concurrencyString(config))
+	fmt.Fprintf(w, "Transition Delay: increase %s, decrease %s\n", config.TransitionDelayIncrease, config.TransitionDelayDecrease)
+	if config.NonRequestServingNodesBufferPerZone != "" {
+		fmt.Fprintf(w, "Non-Request-Serving Nodes Buffer/Zone: %s\n", config.NonRequestServingNodesBufferPerZone)
+	}
+	fmt.Fprintf(w, "Valid: %s", orUnknown(config.Valid))
+	if config.Message != "" {
+		fmt.Fprintf(w, " (%s)", config.Message)
+	}
+	fmt.Fprintln(w)
+}
+
+// concurrencyString formats the concurrency limit, e.g. "5 per 10m0s".
+func concurrencyString(config *sizingConfig) string {
+	return fmt.Sprintf("%d per %s", config.ConcurrencyLimit, config.ConcurrencySlidingWindow)
+}
+
+// orUnset marks unset values in text output.
+func orUnset(value string) string {
+	if value == "" {
+		return "<unset>"
+	}
+	return value
+}
+
+// orUnknown marks a condition that has not been reported in text output.
+func orUnknown(status string) string {
+	if status == "" {
+		return "Unknown"
+	}
+	return status
+}
+
+// countErrors returns the number of reports with an error.
+func countErrors[T any](reports []T, errOf func(T) string) int {
+	failed := 0
+	for _, r := range reports {
+		if errOf(r) != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// failedClusters returns an error when some of the management clusters could not be processed.
+func failedClusters(total, failed int) error {
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d management clusters failed", failed, total)
+}
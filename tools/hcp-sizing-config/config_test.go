@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestSummarizeConfig verifies size classes, limits, delays and the Valid condition are summarized.
+func TestSummarizeConfig(t *testing.T) {
+	csc := newTestSizingConfig()
+	csc.Status.Conditions = []metav1.Condition{{Type: schedulingv1alpha1.ClusterSizingConfigurationValidType, Status: metav1.ConditionTrue}}
+
+	config := summarizeConfig(csc)
+
+	if len(config.Sizes) != 3 {
+		t.Fatalf("Expected 3 size classes, got %+v", config.Sizes)
+	}
+	if config.Sizes[0] != (sizeClass{Name: "small", From: 0, To: "10", Placeholders: 2}) {
+		t.Errorf("Sizes[0] = %+v", config.Sizes[0])
+	}
+	if config.Sizes[2] != (sizeClass{Name: "large", From: 101}) {
+		t.Errorf("Sizes[2] = %+v", config.Sizes[2])
+	}
+	if concurrencyString(config) != "5 per 10m0s" {
+		t.Errorf("concurrencyString() = %q", concurrencyString(config))
+	}
+	if config.TransitionDelayIncrease != "30s" || config.TransitionDelayDecrease != "0s" {
+		t.Errorf("Unexpected transition delays %q and %q", config.TransitionDelayIncrease, config.TransitionDelayDecrease)
+	}
+	if config.Valid != "True" {
+		t.Errorf("Valid = %q, want True", config.Valid)
+	}
+}
+
+// TestLoadDesiredConfig verifies manifests are parsed strictly and must be ClusterSizingConfigurations with sizes.
+func TestLoadDesiredConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "manifest",
+			content: `apiVersion: scheduling.hypershift.openshift.io/v1alpha1
+kind: ClusterSizingConfiguration
+metadata:
+  name: cluster
+spec:
+  sizes:
+  - name: small
+    criteria:
+      from: 0
+  concurrency:
+    limit: 5
+    slidingWindow: 10m
+`,
+		},
+		{name: "unknown field", content: "spec:\n  sizes:\n  - name: small\n    criteria: {from: 0}\n  concurency: {}\n", expectedErr: "failed to parse"},
+		{name: "other kind", content: "kind: ConfigMap\nspec:\n  sizes:\n  - name: small\n", expectedErr: "not a ClusterSizingConfiguration"},
+		{name: "no sizes", content: "spec: {}\n", expectedErr: "has no spec.sizes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sizing.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			csc, err := loadDesiredConfig(path)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("loadDesiredConfig() error = %v, want %q", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadDesiredConfig() error = %v", err)
+			}
+			if csc.Spec.Concurrency.Limit != 5 || csc.Spec.Concurrency.SlidingWindow.Duration.String() != "10m0s" {
+				t.Errorf("Unexpected concurrency %+v", csc.Spec.Concurrency)
+			}
+		})
+	}
+}
+
+// TestGetReports verifies each management cluster is reported, with a fleet summary for several clusters,
+// and that a missing configuration is reported as an error.
+func TestGetReports(t *testing.T) {
+	g := &getOpts{fleet: fleetOpts{output: "text", clients: &fakeClients{client: newTestClient(t, newTestSizingConfig())}}}
+	reports := []configReport{g.report(context.Background(), mgmtCluster{id: "mgmt-123", name: "hs-mc-a"})}
+
+	g.fleet.clients = &fakeClients{client: newTestClient(t)}
+	reports = append(reports, g.report(context.Background(), mgmtCluster{id: "mgmt-456", name: "hs-mc-b"}))
+
+	if reports[0].Error != "" || reports[0].Config == nil {
+		t.Fatalf("Expected the first configuration to be read, got %+v", reports[0])
+	}
+	if !strings.Contains(reports[1].Error, "failed to get ClusterSizingConfiguration") {
+		t.Errorf("Expected the missing configuration to be reported, got %q", reports[1].Error)
+	}
+	if err := failedClusters(len(reports), countErrors(reports, func(r configReport) string { return r.Error })); err == nil {
+		t.Error("Expected the failed management cluster to fail the run")
+	}
+
+	var buf bytes.Buffer
+	if err := g.printReports(&buf, reports); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"=== Management Cluster: hs-mc-a (mgmt-123) ===", "+inf", "Concurrency: 5 per 10m0s", "=== Fleet Summary ===", "error"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+)
+
+// fieldDiff is a field of the ClusterSizingConfiguration spec whose live value differs from the desired
+// value. An empty value means the field is not set on that side.
+type fieldDiff struct {
+	Field   string `json:"field" yaml:"field"`
+	Live    string `json:"live" yaml:"live"`
+	Desired string `json:"desired" yaml:"desired"`
+}
+
+// diffReport holds the differences between the live configuration of a management cluster and the desired
+// configuration, or the error reading it.
+type diffReport struct {
+	MgmtClusterID   string      `json:"mgmt_cluster_id" yaml:"mgmt_cluster_id"`
+	MgmtClusterName string      `json:"mgmt_cluster_name" yaml:"mgmt_cluster_name"`
+	Differences     []fieldDiff `json:"differences" yaml:"differences"`
+	Error           string      `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// resourceVersion is the resourceVersion of the live configuration that was compared.
+	resourceVersion string
+}
+
+// diffSpecs returns the fields that differ between two ClusterSizingConfiguration specs. Fields are named
+// by their JSON path, with size classes identified by name, e.g. sizes[large].criteria.to.
+func diffSpecs(live, desired schedulingv1alpha1.ClusterSizingConfigurationSpec) ([]fieldDiff, error) {
+	liveFields, err := flattenSpec(live)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := flattenSpec(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []fieldDiff{}
+	for field, value := range desiredFields {
+		if liveFields[field] != value {
+			diffs = append(diffs, fieldDiff{Field: field, Live: liveFields[field], Desired: value})
+		}
+	}
+	for field, value := range liveFields {
+		if _, ok := desiredFields[field]; !ok {
+			diffs = append(diffs, fieldDiff{Field: field, Live: value})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs, nil
+}
+
+// flattenSpec returns the values of the fields set in a spec by JSON path.
+func flattenSpec(spec schedulingv1alpha1.ClusterSizingConfigurationSpec) (map[string]string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sizing configuration: %v", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode sizing configuration: %v", err)
+	}
+
+	fields := map[string]string{}
+	flatten("", decoded, fields)
+	return fields, nil
+}
+
+// flatten adds the scalar values of a decoded JSON value to fields by path. Elements of lists of named
+// objects, such as size classes, are identified by name rather than position, so reordering them is not a
+// difference.
+func flatten(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if path == "" {
+				flatten(key, child, fields)
+			} else {
+				flatten(path+"."+key, child, fields)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			key := strconv.Itoa(i)
+			if obj, ok := child.(map[string]interface{}); ok {
+				if name, ok := obj["name"].(string); ok {
+					key = name
+				}
+			}
+			flatten(path+"["+key+"]", child, fields)
+		}
+	case string:
+		fields[path] = v
+	case nil:
+	default:
+		fields[path] = fmt.Sprint(v)
+	}
+}
+
+type diffOpts struct {
+	fleet fleetOpts
+	file  string
+}
+
+func newDiffCmd() *cobra.Command {
+	opts := &diffOpts{}
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the ClusterSizingConfiguration of management clusters with a desired manifest",
+		Example: `
+  # Show how the live configuration of two management clusters differs from sizing.yaml
+  hcp-sizing-config diff --file sizing.yaml --mgmt-cluster-ids mgmt-123,mgmt-456
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+	opts.fleet.addFlags(cmd)
+	opts.fleet.addOutputFlag(cmd)
+	cmd.Flags().StringVar(&opts.file, "file", "", "The desired ClusterSizingConfiguration manifest")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// run compares the live configuration of each management cluster with the --file manifest.
+func (d *diffOpts) run(ctx context.Context) error {
+	if err := d.fleet.validate(); err != nil {
+		return err
+	}
+	desired, err := loadDesiredConfig(d.file)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := d.fleet.connect()
+	defer d.fleet.close()
+	if err != nil {
+		return err
+	}
+
+	var reports []diffReport
+	for _, mc := range clusters {
+		fmt.Fprintf(os.Stderr, "Comparing sizing configuration of management cluster %s\n", clusterLabel(mc.id, mc.name))
+		reports = append(reports, diffCluster(ctx, &d.fleet, mc, desired))
+	}
+
+	if err := d.printReports(os.Stdout, reports); err != nil {
+		return err
+	}
+	return failedClusters(len(reports), countErrors(reports, func(r diffReport) string { return r.Error }))
+}
+
+// diffCluster compares the live configuration of a management cluster with the desired configuration.
+func diffCluster(ctx context.Context, fleet *fleetOpts, mc mgmtCluster, desired *schedulingv1alpha1.ClusterSizingConfiguration) diffReport {
+	report := diffReport{MgmtClusterID: mc.id, MgmtClusterName: mc.name, Differences: []fieldDiff{}}
+	c, err := fleet.client(mc)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	live, err := getSizingConfig(ctx, c)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	diffs, err := diffSpecs(live.Spec, desired.Spec)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Differences = diffs
+	report.resourceVersion = live.ResourceVersion
+	return report
+}
+
+// printReports prints the differences of each management cluster and, for several, a fleet summary.
+func (d *diffOpts) printReports(w io.Writer, reports []diffReport) error {
+	switch d.fleet.output {
+	case "json":
+		return output.JSON(w, reports)
+	case "yaml":
+		return output.YAML(w, reports, false)
+	}
+
+	for _, r := range reports {
+		printDiffReport(w, r, d.fleet.noHeaders)
+	}
+
+	if len(reports) > 1 {
+		fmt.Fprintln(w, "\n=== Fleet Summary ===")
+		p := output.NewTable(w, output.TableMinWidth)
+		if !d.fleet.noHeaders {
+			p.AddRow([]string{"MGMT CLUSTER", "DIFFERENCES"})
+		}
+		for _, r := range reports {
+			differences := strconv.Itoa(len(r.Differences))
+			if r.Error != "" {
+				differences = "error"
+			}
+			p.AddRow([]string{clusterLabel(r.MgmtClusterID, r.MgmtClusterName), differences})
+		}
+		p.Flush()
+	}
+	return nil
+}
+
+// printDiffReport prints the fields of a management cluster that differ from the desired configuration.
+func printDiffReport(w io.Writer, r diffReport, noHeaders bool) {
+	fmt.Fprintf(w, "\n=== Management Cluster: %s ===\n", clusterLabel(r.MgmtClusterID, r.MgmtClusterName))
+	switch {
+	case r.Error != "":
+		fmt.Fprintf(w, "Error: %s\n", r.Error)
+	case len(r.Differences) == 0:
+		fmt.Fprintln(w, "No differences")
+	default:
+		p := output.NewTable(w, output.WideTableMinWidth)
+		if !noHeaders {
+			p.AddRow([]string{"FIELD", "LIVE", "DESIRED"})
+		}
+		for _, diff := range r.Differences {
+			p.AddRow([]string{diff.Field, orUnset(diff.Live), orUnset(diff.Desired)})
+		}
+		p.Flush()
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TestDiffSpecs verifies changed, added and removed fields are reported by path and reordered size classes are not.
+func TestDiffSpecs(t *testing.T) {
+	live := newTestSizingConfig().Spec
+	desired := newTestSizingConfig().Spec
+	desired.Sizes[0], desired.Sizes[1] = desired.Sizes[1], desired.Sizes[0]
+	desired.Sizes[1].Management.Placeholders = 3
+	desired.Concurrency.Limit = 10
+	desired.TransitionDelay.Increase.Duration = 0
+	buffer := resource.MustParse("2")
+	desired.NonRequestServingNodesBufferPerZone = &buffer
+
+	diffs, err := diffSpecs(live, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []fieldDiff{
+		{Field: "concurrency.limit", Live: "5", Desired: "10"},
+		{Field: "nonRequestServingNodesBufferPerZone", Desired: "2"},
+		{Field: "sizes[small].management.placeholders", Live: "2", Desired: "3"},
+		{Field: "transitionDelay.increase", Live: "30s", Desired: "0s"},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("diffSpecs() = %+v, want %+v", diffs, expected)
+	}
+	for i := range expected {
+		if diffs[i] != expected[i] {
+			t.Errorf("diffs[%d] = %+v, want %+v", i, diffs[i], expected[i])
+		}
+	}
+
+	desired = newTestSizingConfig().Spec
+	desired.Sizes = desired.Sizes[:2]
+	diffs, err = diffSpecs(live, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 || diffs[0].Field != "sizes[large].criteria.from" || diffs[0].Desired != "" || diffs[1].Field != "sizes[large].name" {
+		t.Errorf("Expected the removed size class to be reported, got %+v", diffs)
+	}
+}
+
+// TestDiffCluster verifies the live configuration is compared with the desired configuration and its
+// resourceVersion recorded.
+func TestDiffCluster(t *testing.T) {
+	fleet := &fleetOpts{clients: &fakeClients{client: newTestClient(t, newTestSizingConfig())}}
+	desired := newTestSizingConfig()
+	desired.Spec.Sizes = append(desired.Spec.Sizes[:2], schedulingv1alpha1.SizeConfiguration{Name: "xlarge",
+		Criteria: schedulingv1alpha1.NodeCountCriteria{From: 101}})
+
+	report := diffCluster(context.Background(), fleet, mgmtCluster{id: "mgmt-123"}, desired)
+
+	if report.Error != "" {
+		t.Fatalf("Unexpected error %s", report.Error)
+	}
+	if len(report.Differences) != 4 {
+		t.Errorf("Expected the large size class to be removed and xlarge added, got %+v", report.Differences)
+	}
+	if report.resourceVersion == "" {
+		t.Error("Expected the resourceVersion of the live configuration to be recorded")
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/clientfactory"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/scheme"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sizingConfigName is the name of the cluster-scoped ClusterSizingConfiguration read by HyperShift.
+const sizingConfigName = "cluster"
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   "hcp-sizing-config",
+		Short: "Read, validate, diff and patch the ClusterSizingConfiguration of ROSA HCP management clusters",
+		Long: `Manage the HyperShift ClusterSizingConfiguration of ROSA HCP management clusters.
+
+The ClusterSizingConfiguration defines the size classes of hosted clusters (node count thresholds,
+placeholders and effects), how many clusters may transition between size classes at once and how
+long transitions are delayed. Once hosted clusters use resource-based autoscaling, tuning this
+configuration is how their size classes are managed.
+
+Every subcommand accepts several management clusters and reports each of them, followed by a
+fleet summary.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+	}
+
+	rootCmd.AddCommand(newGetCmd())
+	rootCmd.AddCommand(newValidateCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newPatchCmd())
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// fleetOpts holds the flags shared by the subcommands, which all act on one or more management clusters.
+type fleetOpts struct {
+	mgmtClusterIDs []string
+	output         string
+	noHeaders      bool
+
+	clients clientfactory.Factory
+	ocmConn *sdk.Connection
+}
+
+// mgmtCluster is a management cluster resolved through OCM.
+type mgmtCluster struct {
+	id   string
+	name string
+}
+
+// addFlags registers the management cluster and header flags.
+func (f *fleetOpts) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&f.mgmtClusterIDs, "mgmt-cluster-ids", nil, "Comma-separated IDs or names of the management clusters")
+	cmd.Flags().BoolVar(&f.noHeaders, "no-headers", false, "Skip headers in text output")
+}
+
+// addOutputFlag registers --output for the subcommands that produce a report.
+func (f *fleetOpts) addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.output, "output", "text", "Output format: text, json, yaml")
+}
+
+// validate checks the management cluster IDs and the output format.
+func (f *fleetOpts) validate() error {
+	if len(f.mgmtClusterIDs) == 0 {
+		return fmt.Errorf("--mgmt-cluster-ids is required")
+	}
+	for _, id := range f.mgmtClusterIDs {
+		if err := utils.IsValidClusterKey(id); err != nil {
+			return err
+		}
+	}
+	if f.output == "" {
+		return nil
+	}
+	return output.ValidateFormat(f.output, "text", "json", "yaml")
+}
+
+// connect opens the OCM connection and resolves the management clusters, failing on the first cluster that
+// does not exist or is not a management cluster. The connection is closed with close.
+func (f *fleetOpts) connect() ([]mgmtCluster, error) {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	f.ocmConn = connection
+
+	var clusters []mgmtCluster
+	for _, id := range f.mgmtClusterIDs {
+		cluster, err := utils.GetCluster(connection, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cluster %s: %v", id, err)
+		}
+		isMC, err := utils.IsManagementCluster(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify if cluster %s is a management cluster: %v", cluster.ID(), err)
+		}
+		if !isMC {
+			return nil, fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
+		}
+		clusters = append(clusters, mgmtCluster{id: cluster.ID(), name: cluster.Name()})
+	}
+	return clusters, nil
+}
+
+// close closes the OCM connection opened by connect.
+func (f *fleetOpts) close() {
+	if f.ocmConn != nil {
+		f.ocmConn.Close()
+	}
+}
+
+// newScheme returns the scheme holding the ClusterSizingConfiguration type.
+func newScheme() (*runtime.Scheme, error) {
+	return scheme.Cached(scheme.Scheduling)
+}
+
+// client returns a client for a management cluster with the operator's own permissions.
+func (f *fleetOpts) client(mc mgmtCluster) (client.Client, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+	c, err := clientfactory.OrDefault(f.clients).NewClient(mc.id, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management cluster client: %v", err)
+	}
+	return c, nil
+}
+
+// elevatedClient returns a client for a management cluster with backplane cluster-admin permissions,
+// recording reason for the elevation.
+func (f *fleetOpts) elevatedClient(mc mgmtCluster, reason string) (client.Client, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+	c, err := clientfactory.OrDefault(f.clients).NewElevatedClient(mc.id, scheme, f.ocmConn, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create elevated management cluster client: %v", err)
+	}
+	return c, nil
+}
+
+// getSizingConfig reads the ClusterSizingConfiguration of a management cluster.
+func getSizingConfig(ctx context.Context, c client.Client) (*schedulingv1alpha1.ClusterSizingConfiguration, error) {
+	csc := &schedulingv1alpha1.ClusterSizingConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: sizingConfigName}, csc); err != nil {
+		return nil, fmt.Errorf("failed to get ClusterSizingConfiguration: %v", err)
+	}
+	return csc, nil
+}
+
+// clusterLabel formats a management cluster for report headings, e.g. "hs-mc-abc (2abc...)".
+func clusterLabel(id, name string) string {
+	if name == "" || name == id {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeClients returns the same controller-runtime client for every management cluster, elevated or not.
+type fakeClients struct {
+	client   client.Client
+	elevated []string
+}
+
+func (f *fakeClients) NewClient(clusterID string, scheme *runtime.Scheme) (client.Client, error) {
+	return f.client, nil
+}
+
+func (f *fakeClients) NewElevatedClient(clusterID string, scheme *runtime.Scheme, conn *sdk.Connection, reason string) (client.Client, error) {
+	f.elevated = append(f.elevated, reason)
+	return f.client, nil
+}
+
+func (f *fakeClients) NewWatchClient(clusterID string, scheme *runtime.Scheme) (client.WithWatch, error) {
+	return nil, nil
+}
+
+// uint32Ptr returns a pointer to v, for the upper node count limit of size classes.
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+// newTestSizingConfig returns a ClusterSizingConfiguration with small (0-10), medium (11-100) and large (101+)
+// size classes, a concurrency of 5 per 10 minutes and a 30 second scale-up delay.
+func newTestSizingConfig() *schedulingv1alpha1.ClusterSizingConfiguration {
+	return &schedulingv1alpha1.ClusterSizingConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: sizingConfigName},
+		Spec: schedulingv1alpha1.ClusterSizingConfigurationSpec{
+			Sizes: []schedulingv1alpha1.SizeConfiguration{
+				{Name: "small", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 0, To: uint32Ptr(10)},
+					Management: &schedulingv1alpha1.Management{Placeholders: 2}},
+				{Name: "medium", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 11, To: uint32Ptr(100)}},
+				{Name: "large", Criteria: schedulingv1alpha1.NodeCountCriteria{From: 101}},
+			},
+			Concurrency: schedulingv1alpha1.ConcurrencyConfiguration{
+				Limit:         5,
+				SlidingWindow: metav1.Duration{Duration: 10 * time.Minute},
+			},
+			TransitionDelay: schedulingv1alpha1.TransitionDelayConfiguration{
+				Increase: metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+	}
+}
+
+// newTestClient returns a fake management cluster client holding objs.
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+// TestFleetOptsValidate verifies management cluster IDs are required and the output format is checked.
+func TestFleetOptsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        fleetOpts
+		expectError bool
+	}{
+		{name: "valid", opts: fleetOpts{mgmtClusterIDs: []string{"mgmt-123", "mgmt-456"}, output: "json"}},
+		{name: "no output flag", opts: fleetOpts{mgmtClusterIDs: []string{"mgmt-123"}}},
+		{name: "no clusters", opts: fleetOpts{output: "text"}, expectError: true},
+		{name: "invalid output", opts: fleetOpts{mgmtClusterIDs: []string{"mgmt-123"}, output: "csv"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(); (err != nil) != tt.expectError {
+				t.Errorf("validate() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
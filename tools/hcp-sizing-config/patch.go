@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/prompt"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Statuses of a management cluster in the patch results.
+const (
+	patchStatusPatched   = "patched"
+	patchStatusUnchanged = "unchanged"
+	patchStatusFailed    = "failed"
+)
+
+type patchOpts struct {
+	fleet            fleetOpts
+	file             string
+	reason           string
+	dryRun           bool
+	skipConfirmation bool
+}
+
+// patchResult is the outcome of patching the configuration of a management cluster.
+type patchResult struct {
+	report diffReport
+	status string
+	err    error
+}
+
+func newPatchCmd() *cobra.Command {
+	opts := &patchOpts{}
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Apply a desired ClusterSizingConfiguration manifest to management clusters",
+		Long: `Replace the spec of the ClusterSizingConfiguration of management clusters with the spec of a
+desired manifest.
+
+The manifest is validated first and the differences of every management cluster are shown before
+asking for confirmation. Management clusters already matching the manifest are left unchanged.
+The patch uses backplane cluster-admin elevation and fails if the configuration changed since it
+was compared.`,
+		Example: `
+  # Show what would change without patching
+  hcp-sizing-config patch --file sizing.yaml --mgmt-cluster-ids mgmt-123 --reason OHSS-12345 --dry-run
+
+  # Patch two management clusters
+  hcp-sizing-config patch --file sizing.yaml --mgmt-cluster-ids mgmt-123,mgmt-456 --reason OHSS-12345
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+	opts.fleet.addFlags(cmd)
+	cmd.Flags().StringVar(&opts.file, "file", "", "The desired ClusterSizingConfiguration manifest")
+	cmd.Flags().StringVar(&opts.reason, "reason", "", "Reason recorded for the backplane elevation, e.g. the Jira ticket")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the differences without patching")
+	cmd.Flags().BoolVar(&opts.skipConfirmation, "skip-confirmation", false, "Patch without asking for confirmation")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// run compares each management cluster with the --file manifest and, once confirmed, patches those that differ.
+func (p *patchOpts) run(ctx context.Context) error {
+	if err := p.fleet.validate(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(p.reason) == "" && !p.dryRun {
+		return fmt.Errorf("--reason is required to patch the sizing configuration")
+	}
+
+	desired, err := loadDesiredConfig(p.file)
+	if err != nil {
+		return err
+	}
+	if problems := validateSpec(desired.Spec); len(problems) > 0 {
+		return fmt.Errorf("sizing configuration %s is invalid: %s", p.file, strings.Join(problems, "; "))
+	}
+
+	clusters, err := p.fleet.connect()
+	defer p.fleet.close()
+	if err != nil {
+		return err
+	}
+
+	var reports []diffReport
+	changes := 0
+	for _, mc := range clusters {
+		report := diffCluster(ctx, &p.fleet, mc, desired)
+		printDiffReport(os.Stdout, report, p.fleet.noHeaders)
+		if report.Error == "" && len(report.Differences) > 0 {
+			changes++
+		}
+		reports = append(reports, report)
+	}
+
+	failed := countErrors(reports, func(r diffReport) string { return r.Error })
+	if changes == 0 {
+		fmt.Println("\nNo management cluster needs to be patched")
+		return failedClusters(len(reports), failed)
+	}
+	if p.dryRun {
+		fmt.Printf("\nDry run: %d management clusters would be patched\n", changes)
+		return failedClusters(len(reports), failed)
+	}
+
+	fmt.Printf("\n%d management clusters will be patched.\n", changes)
+	if !p.skipConfirmation && !prompt.Confirm(os.Stdin, os.Stdout) {
+		return fmt.Errorf("patch cancelled")
+	}
+
+	var results []patchResult
+	for i, mc := range clusters {
+		results = append(results, p.patchCluster(ctx, mc, reports[i], desired))
+	}
+
+	printPatchResults(os.Stdout, results, p.fleet.noHeaders)
+
+	failed = 0
+	for _, r := range results {
+		if r.status == patchStatusFailed {
+			failed++
+		}
+	}
+	return failedClusters(len(results), failed)
+}
+
+// patchCluster patches the configuration of a management cluster that differs from the desired configuration.
+func (p *patchOpts) patchCluster(ctx context.Context, mc mgmtCluster, report diffReport, desired *schedulingv1alpha1.ClusterSizingConfiguration) patchResult {
+	result := patchResult{report: report, status: patchStatusUnchanged}
+	if report.Error != "" {
+		result.status = patchStatusFailed
+		result.err = fmt.Errorf("%s", report.Error)
+		return result
+	}
+	if len(report.Differences) == 0 {
+		return result
+	}
+
+	c, err := p.fleet.elevatedClient(mc, p.reason)
+	if err == nil {
+		err = patchSizingConfig(ctx, c, desired, report.resourceVersion)
+	}
+	if err != nil {
+		result.status = patchStatusFailed
+		result.err = err
+		return result
+	}
+	fmt.Fprintf(os.Stderr, "Patched sizing configuration of management cluster %s\n", clusterLabel(mc.id, mc.name))
+	result.status = patchStatusPatched
+	return result
+}
+
+// patchSizingConfig replaces the spec of the live configuration with the desired spec. It fails rather than
+// overwrite a configuration that changed since resourceVersion was compared, and the merge patch carries the
+// resourceVersion so the API server rejects changes made while patching.
+func patchSizingConfig(ctx context.Context, c client.Client, desired *schedulingv1alpha1.ClusterSizingConfiguration, resourceVersion string) error {
+	live, err := getSizingConfig(ctx, c)
+	if err != nil {
+		return err
+	}
+	if live.ResourceVersion != resourceVersion {
+		return fmt.Errorf("ClusterSizingConfiguration changed since it was compared; re-run to review the new differences")
+	}
+	patched := live.DeepCopy()
+	patched.Spec = desired.Spec
+	if err := c.Patch(ctx, patched, client.MergeFromWithOptions(live, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to patch ClusterSizingConfiguration: %v", err)
+	}
+	return nil
+}
+
+// printPatchResults prints the status of each management cluster after patching.
+func printPatchResults(w io.Writer, results []patchResult, noHeaders bool) {
+	fmt.Fprintln(w, "\n=== Patch Results ===")
+	t := output.NewTable(w, output.TableMinWidth)
+	if !noHeaders {
+		t.AddRow([]string{"MGMT CLUSTER", "DIFFERENCES", "STATUS", "ERROR"})
+	}
+	for _, r := range results {
+		errMsg := ""
+		if r.err != nil {
+			errMsg = r.err.Error()
+		}
+		t.AddRow([]string{clusterLabel(r.report.MgmtClusterID, r.report.MgmtClusterName), fmt.Sprint(len(r.report.Differences)), r.status, errMsg})
+	}
+	t.Flush()
+}
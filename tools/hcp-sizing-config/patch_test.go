@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestPatchCluster verifies differing configurations are patched with an elevated client, matching ones are
+// left unchanged and configurations changed since they were compared are not overwritten.
+func TestPatchCluster(t *testing.T) {
+	desired := newTestSizingConfig()
+	desired.Spec.Concurrency.Limit = 10
+	mc := mgmtCluster{id: "mgmt-123", name: "hs-mc-a"}
+
+	t.Run("patched", func(t *testing.T) {
+		clients := &fakeClients{client: newTestClient(t, newTestSizingConfig())}
+		p := &patchOpts{fleet: fleetOpts{clients: clients}, reason: "OHSS-12345"}
+
+		report := diffCluster(context.Background(), &p.fleet, mc, desired)
+		result := p.patchCluster(context.Background(), mc, report, desired)
+
+		if result.status != patchStatusPatched || result.err != nil {
+			t.Fatalf("patchCluster() = %s, %v", result.status, result.err)
+		}
+		if len(clients.elevated) != 1 || clients.elevated[0] != "OHSS-12345" {
+			t.Errorf("Expected one elevation with the reason, got %v", clients.elevated)
+		}
+		live, err := getSizingConfig(context.Background(), clients.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if live.Spec.Concurrency.Limit != 10 {
+			t.Errorf("Expected the concurrency limit to be patched, got %d", live.Spec.Concurrency.Limit)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		clients := &fakeClients{client: newTestClient(t, desired.DeepCopy())}
+		p := &patchOpts{fleet: fleetOpts{clients: clients}, reason: "OHSS-12345"}
+
+		report := diffCluster(context.Background(), &p.fleet, mc, desired)
+		result := p.patchCluster(context.Background(), mc, report, desired)
+
+		if result.status != patchStatusUnchanged || len(clients.elevated) != 0 {
+			t.Errorf("Expected no patch and no elevation, got %s with %v", result.status, clients.elevated)
+		}
+	})
+
+	t.Run("changed since compared", func(t *testing.T) {
+		clients := &fakeClients{client: newTestClient(t, newTestSizingConfig())}
+		p := &patchOpts{fleet: fleetOpts{clients: clients}, reason: "OHSS-12345"}
+
+		report := diffCluster(context.Background(), &p.fleet, mc, desired)
+		live, err := getSizingConfig(context.Background(), clients.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		live.Spec.Concurrency.Limit = 7
+		if err := clients.client.Update(context.Background(), live); err != nil {
+			t.Fatal(err)
+		}
+
+		result := p.patchCluster(context.Background(), mc, report, desired)
+		if result.status != patchStatusFailed || result.err == nil || !strings.Contains(result.err.Error(), "changed since it was compared") {
+			t.Errorf("Expected the patch to fail, got %s, %v", result.status, result.err)
+		}
+	})
+}
+
+// TestPatchValidation verifies a reason is required unless only showing the differences.
+func TestPatchValidation(t *testing.T) {
+	p := &patchOpts{fleet: fleetOpts{mgmtClusterIDs: []string{"mgmt-123"}}, file: "sizing.yaml"}
+	if err := p.run(context.Background()); err == nil || !strings.Contains(err.Error(), "--reason is required") {
+		t.Errorf("run() error = %v, want --reason is required", err)
+	}
+}
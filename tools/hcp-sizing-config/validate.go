@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/openshift-online/rosa-hcp-platform-tools/internal/output"
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validationReport lists the problems found in the ClusterSizingConfiguration of a management cluster or of
+// a --file manifest.
+type validationReport struct {
+	MgmtClusterID   string   `json:"mgmt_cluster_id,omitempty" yaml:"mgmt_cluster_id,omitempty"`
+	MgmtClusterName string   `json:"mgmt_cluster_name,omitempty" yaml:"mgmt_cluster_name,omitempty"`
+	File            string   `json:"file,omitempty" yaml:"file,omitempty"`
+	Problems        []string `json:"problems" yaml:"problems"`
+	Error           string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// status classifies the report as valid, invalid or error.
+func (r validationReport) status() string {
+	switch {
+	case r.Error != "":
+		return "error"
+	case len(r.Problems) > 0:
+		return "invalid"
+	default:
+		return "valid"
+	}
+}
+
+// validateSpec returns the problems of a ClusterSizingConfiguration spec. Size classes must have unique
+// names and their node count criteria must cover [0,+inf) without gaps or overlaps, as HyperShift requires.
+func validateSpec(spec schedulingv1alpha1.ClusterSizingConfigurationSpec) []string {
+	problems := []string{}
+	if len(spec.Sizes) == 0 {
+		return append(problems, "no size classes are defined")
+	}
+
+	names := map[string]bool{}
+	for _, s := range spec.Sizes {
+		switch {
+		case s.Name == "":
+			problems = append(problems, "a size class has no name")
+		case names[s.Name]:
+			problems = append(problems, fmt.Sprintf("size class %s is defined more than once", s.Name))
+		}
+		names[s.Name] = true
+
+		if s.Criteria.To != nil && *s.Criteria.To < s.Criteria.From {
+			problems = append(problems, fmt.Sprintf("size class %s ends at %d nodes, before it starts at %d", s.Name, *s.Criteria.To, s.Criteria.From))
+		}
+		if s.Management != nil && s.Management.Placeholders < 0 {
+			problems = append(problems, fmt.Sprintf("size class %s has %d placeholders", s.Name, s.Management.Placeholders))
+		}
+	}
+
+	sizes := append([]schedulingv1alpha1.SizeConfiguration{}, spec.Sizes...)
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].Criteria.From < sizes[j].Criteria.From })
+	if sizes[0].Criteria.From != 0 {
+		problems = append(problems, fmt.Sprintf("no size class covers clusters with fewer than %d nodes", sizes[0].Criteria.From))
+	}
+	for i, s := range sizes {
+		if s.Criteria.To == nil {
+			if i != len(sizes)-1 {
+				problems = append(problems, fmt.Sprintf("size class %s is unbounded but overlaps size class %s", s.Name, sizes[i+1].Name))
+			}
+			continue
+		}
+		if i == len(sizes)-1 {
+			problems = append(problems, fmt.Sprintf("no size class covers clusters with more than %d nodes", *s.Criteria.To))
+			continue
+		}
+		next := sizes[i+1]
+		switch {
+		case next.Criteria.From <= *s.Criteria.To:
+			problems = append(problems, fmt.Sprintf("size classes %s and %s overlap at %d nodes", s.Name, next.Name, next.Criteria.From))
+		case next.Criteria.From > *s.Criteria.To+1:
+			problems = append(problems, fmt.Sprintf("no size class covers clusters with %d to %d nodes", *s.Criteria.To+1, next.Criteria.From-1))
+		}
+	}
+
+	if spec.Concurrency.Limit < 0 {
+		problems = append(problems, fmt.Sprintf("concurrency limit %d is negative", spec.Concurrency.Limit))
+	}
+	for _, d := range []struct {
+		name     string
+		duration metav1.Duration
+	}{
+		{"concurrency sliding window", spec.Concurrency.SlidingWindow},
+		{"transition delay increase", spec.TransitionDelay.Increase},
+		{"transition delay decrease", spec.TransitionDelay.Decrease},
+	} {
+		if d.duration.Duration < 0 {
+			problems = append(problems, fmt.Sprintf("%s %s is negative", d.name, d.duration.Duration))
+		}
+	}
+
+	return problems
+}
+
+// validateConfig returns the problems of a live ClusterSizingConfiguration, including a
+// ClusterSizingConfigurationValid condition that HyperShift reports as not true.
+func validateConfig(csc *schedulingv1alpha1.ClusterSizingConfiguration) []string {
+	problems := validateSpec(csc.Spec)
+	condition := meta.FindStatusCondition(csc.Status.Conditions, schedulingv1alpha1.ClusterSizingConfigurationValidType)
+	if condition != nil && condition.Status != metav1.ConditionTrue {
+		problems = append(problems, fmt.Sprintf("HyperShift reports the configuration as not valid: %s", condition.Message))
+	}
+	return problems
+}
+
+type validateOpts struct {
+	fleet fleetOpts
+	file  string
+}
+
+func newValidateCmd() *cobra.Command {
+	opts := &validateOpts{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check that ClusterSizingConfigurations cover every node count and have sane limits",
+		Example: `
+  # Validate the live configuration of management clusters
+  hcp-sizing-config validate --mgmt-cluster-ids mgmt-123,mgmt-456
+
+  # Validate a manifest before patching it onto management clusters
+  hcp-sizing-config validate --file sizing.yaml
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context())
+		},
+	}
+	opts.fleet.addFlags(cmd)
+	opts.fleet.addOutputFlag(cmd)
+	cmd.Flags().StringVar(&opts.file, "file", "", "Validate this ClusterSizingConfiguration manifest instead of the live configuration")
+	return cmd
+}
+
+// run validates the --file manifest or the live configuration of each management cluster and fails when
+// any of them is invalid.
+func (v *validateOpts) run(ctx context.Context) error {
+	var reports []validationReport
+	if v.file != "" {
+		if len(v.fleet.mgmtClusterIDs) > 0 {
+			return fmt.Errorf("--file and --mgmt-cluster-ids cannot be combined")
+		}
+		if err := output.ValidateFormat(v.fleet.output, "text", "json", "yaml"); err != nil {
+			return err
+		}
+		report := validationReport{File: v.file, Problems: []string{}}
+		csc, err := loadDesiredConfig(v.file)
+		if err != nil {
+			report.Error = err.Error()
+		} else {
+			report.Problems = validateSpec(csc.Spec)
+		}
+		reports = append(reports, report)
+	} else {
+		if err := v.fleet.validate(); err != nil {
+			return err
+		}
+		clusters, err := v.fleet.connect()
+		defer v.fleet.close()
+		if err != nil {
+			return err
+		}
+		for _, mc := range clusters {
+			fmt.Fprintf(os.Stderr, "Validating sizing configuration of management cluster %s\n", clusterLabel(mc.id, mc.name))
+			reports = append(reports, v.report(ctx, mc))
+		}
+	}
+
+	if err := v.printReports(os.Stdout, reports); err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, r := range reports {
+		if r.status() != "valid" {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d sizing configurations are invalid or could not be read", invalid, len(reports))
+	}
+	return nil
+}
+
+// report validates the live configuration of a management cluster.
+func (v *validateOpts) report(ctx context.Context, mc mgmtCluster) validationReport {
+	report := validationReport{MgmtClusterID: mc.id, MgmtClusterName: mc.name, Problems: []string{}}
+	c, err := v.fleet.client(mc)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	csc, err := getSizingConfig(ctx, c)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Problems = validateConfig(csc)
+	return report
+}
+
+// printReports prints the status of each configuration followed by its problems.
+func (v *validateOpts) printReports(w io.Writer, reports []validationReport) error {
+	switch v.fleet.output {
+	case "json":
+		return output.JSON(w, reports)
+	case "yaml":
+		return output.YAML(w, reports, false)
+	}
+
+	p := output.NewTable(w, output.TableMinWidth)
+	if !v.fleet.noHeaders {
+		p.AddRow([]string{"CONFIGURATION", "STATUS", "PROBLEMS"})
+	}
+	for _, r := range reports {
+		p.AddRow([]string{r.source(), r.status(), fmt.Sprint(len(r.Problems))})
+	}
+	p.Flush()
+
+	for _, r := range reports {
+		if r.status() == "valid" {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", r.source())
+		if r.Error != "" {
+			fmt.Fprintf(w, "  - %s\n", r.Error)
+		}
+		for _, problem := range r.Problems {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+	}
+	return nil
+}
+
+// source names the validated configuration: the --file path or the management cluster.
+func (r validationReport) source() string {
+	if r.File != "" {
+		return r.File
+	}
+	return clusterLabel(r.MgmtClusterID, r.MgmtClusterName)
+}
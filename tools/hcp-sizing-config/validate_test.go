@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	schedulingv1alpha1 "github.com/openshift/hypershift/api/scheduling/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestValidateSpec verifies size classes must cover every node count exactly once and limits must not be negative.
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec)
+		problems []string
+	}{
+		{name: "valid", modify: func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) {}},
+		{
+			name: "unordered sizes",
+			modify: func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) {
+				spec.Sizes[0], spec.Sizes[2] = spec.Sizes[2], spec.Sizes[0]
+			},
+		},
+		{
+			name:     "no sizes",
+			modify:   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) { spec.Sizes = nil },
+			problems: []string{"no size classes are defined"},
+		},
+		{
+			name:     "gap",
+			modify:   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) { spec.Sizes[1].Criteria.From = 20 },
+			problems: []string{"no size class covers clusters with 11 to 19 nodes"},
+		},
+		{
+			name:     "overlap",
+			modify:   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) { spec.Sizes[1].Criteria.From = 5 },
+			problems: []string{"size classes small and medium overlap at 5 nodes"},
+		},
+		{
+			name:     "lower bound",
+			modify:   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) { spec.Sizes = spec.Sizes[1:] },
+			problems: []string{"no size class covers clusters with fewer than 11 nodes"},
+		},
+		{
+			name:     "upper bound",
+			modify:   func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) { spec.Sizes = spec.Sizes[:2] },
+			problems: []string{"no size class covers clusters with more than 100 nodes"},
+		},
+		{
+			name: "duplicate name and negative limits",
+			modify: func(spec *schedulingv1alpha1.ClusterSizingConfigurationSpec) {
+				spec.Sizes[1].Name = "small"
+				spec.Concurrency.Limit = -1
+				spec.TransitionDelay.Decrease = metav1.Duration{Duration: -time.Second}
+			},
+			problems: []string{"size class small is defined more than once", "concurrency limit -1 is negative", "transition delay decrease -1s is negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newTestSizingConfig().Spec
+			tt.modify(&spec)
+			problems := validateSpec(spec)
+			if fmt.Sprint(problems) != fmt.Sprint(append([]string{}, tt.problems...)) {
+				t.Errorf("validateSpec() = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
+
+// TestValidateConfig verifies a ClusterSizingConfigurationValid condition that is not true is a problem.
+func TestValidateConfig(t *testing.T) {
+	csc := newTestSizingConfig()
+	csc.Status.Conditions = []metav1.Condition{{Type: schedulingv1alpha1.ClusterSizingConfigurationValidType,
+		Status: metav1.ConditionFalse, Message: "sizes overlap"}}
+
+	problems := validateConfig(csc)
+	if len(problems) != 1 || problems[0] != "HyperShift reports the configuration as not valid: sizes overlap" {
+		t.Errorf("validateConfig() = %q", problems)
+	}
+
+	report := validationReport{MgmtClusterID: "mgmt-123", Problems: problems}
+	if report.status() != "invalid" || report.source() != "mgmt-123" {
+		t.Errorf("Unexpected status %q and source %q", report.status(), report.source())
+	}
+}